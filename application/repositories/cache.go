@@ -0,0 +1,20 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/redcardinal-io/metering/domain/models"
+)
+
+type QueryCacheRepository interface {
+	Get(ctx context.Context, key models.QueryCacheKey) (*models.QueryMeterResult, bool)
+	Set(ctx context.Context, key models.QueryCacheKey, result *models.QueryMeterResult, ttl time.Duration)
+	// InvalidateMeter drops every cached result of a meter
+	InvalidateMeter(ctx context.Context, tenantSlug, meterSlug string)
+	// HoldMeter drops every cached result of a meter and marks results reaching past since as
+	// unsettled until until, while late events published for the meter are still being ingested
+	HoldMeter(ctx context.Context, tenantSlug, meterSlug string, since, until time.Time)
+	// MeterHold returns the earliest time a hold on the meter that has not expired unsettles
+	MeterHold(ctx context.Context, tenantSlug, meterSlug string) (time.Time, bool)
+}
@@ -0,0 +1,23 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/redcardinal-io/metering/domain/models"
+)
+
+type AlertNotifierRepository interface {
+	// NotifyAlert delivers an alert that started firing
+	NotifyAlert(ctx context.Context, event models.AlertEvent) error
+}
+
+type NotifierRepository interface {
+	// Notify publishes a change that has already been made. It cannot fail the change, so
+	// implementations report their own failures.
+	Notify(ctx context.Context, notification models.Notification)
+}
+
+type WebhookSenderRepository interface {
+	// Send posts a webhook request and returns the response of the endpoint whatever its status
+	Send(ctx context.Context, req models.WebhookRequest) (*models.WebhookResponse, error)
+}
@@ -14,6 +14,16 @@ type OlapRepository interface {
 
 	// meter methods
 	CreateMeter(ctx context.Context, arg models.CreateMeterInput) error
-	QueryMeter(ctx context.Context, arg models.QueryMeterParams, agg *models.AggregationEnum) (*models.QueryMeterResult, error)
+	QueryMeter(ctx context.Context, arg models.QueryMeterParams, meter *models.Meter) (*models.QueryMeterResult, error)
+	// StreamMeter runs a meter query and calls fn for each row as it is read, without buffering the result
+	StreamMeter(ctx context.Context, arg models.QueryMeterParams, meter *models.Meter, fn func(row models.QueryMeterRow) error) error
 	DeleteMeter(ctx context.Context, meterSlug string) error
+	// ListMeterViews returns every meter view and rollup in the OLAP database
+	ListMeterViews(ctx context.Context) ([]models.OlapView, error)
+	// ExpectedMeterViews returns the views a meter should have according to its definition
+	ExpectedMeterViews(meter *models.Meter) ([]models.OlapView, error)
+	// DropMeterView drops a single meter view or rollup by name
+	DropMeterView(ctx context.Context, name string) error
+	// PreviewMeter evaluates a meter definition without creating any object
+	PreviewMeter(ctx context.Context, input models.PreviewMeterInput) (*models.MeterPreview, error)
 }
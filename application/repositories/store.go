@@ -2,6 +2,7 @@ package repositories
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/redcardinal-io/metering/domain/models"
@@ -20,8 +21,14 @@ type MeterStoreRepository interface {
 	GetMeterByIDorSlug(ctx context.Context, idOrSlug string) (*models.Meter, error)
 	ListMeters(ctx context.Context, pagination pagination.Pagination) (*pagination.PaginationView[models.Meter], error)
 	ListMetersByEventTypes(ctx context.Context, eventTypes []string) ([]*models.Meter, error)
+	ListMeterSlugsReferencing(ctx context.Context, meterSlug string) ([]string, error)
 	DeleteMeterByIDorSlug(ctx context.Context, idOrSlug string) error
 	UpdateMeterByIDorSlug(ctx context.Context, idOrSlug string, arg models.UpdateMeterInput) (*models.Meter, error)
+	UpdateMeterStatus(ctx context.Context, id uuid.UUID, arg models.UpdateMeterStatusInput) (*models.Meter, error)
+	// ListMetersByStatus returns meters of every tenant in the given states not updated since updatedBefore
+	ListMetersByStatus(ctx context.Context, statuses []models.MeterStatus, updatedBefore time.Time, limit int) ([]*models.Meter, error)
+	// ListAllMeters returns the meters of every tenant
+	ListAllMeters(ctx context.Context) ([]*models.Meter, error)
 }
 
 type PlanStoreRepository interface {
@@ -31,6 +38,8 @@ type PlanStoreRepository interface {
 	DeletePlanByIDorSlug(ctx context.Context, idOrSlug string) error
 	UpdatePlanByIDorSlug(ctx context.Context, idOrSlug string, arg models.UpdatePlanInput) (*models.Plan, error)
 	ArchivePlanByIDorSlug(ctx context.Context, idOrSlug string, arg models.ArchivePlanInput) error
+	// ClonePlan copies the plan with its draft features and quotas into a new plan in one transaction
+	ClonePlan(ctx context.Context, sourceID uuid.UUID, arg models.ClonePlanInput) (*models.Plan, error)
 }
 
 type PlanAssignmentsStoreRepository interface {
@@ -40,6 +49,48 @@ type PlanAssignmentsStoreRepository interface {
 	ListAssignments(ctx context.Context, arg models.QueryPlanAssignmentInput, pagination pagination.Pagination) (*pagination.PaginationView[models.PlanAssignment], error)
 	ListAssignmentsHistory(ctx context.Context, arg models.QueryPlanAssignmentHistoryInput, pagination pagination.Pagination) (*pagination.PaginationView[models.PlanAssignmentHistory], error)
 	ListAllAssignments(ctx context.Context, pagination pagination.Pagination) (*pagination.PaginationView[models.PlanAssignment], error)
+	GetAssignment(ctx context.Context, id uuid.UUID) (*models.PlanAssignment, error)
+	// ListActiveSubjectAssignments returns the assignments of the organization or user valid at the given time,
+	// base plans first, each with the kind of its plan
+	ListActiveSubjectAssignments(ctx context.Context, arg models.GetActiveAssignmentInput) ([]models.PlanAssignment, error)
+	// CountOverlappingBaseAssignments counts the base plan assignments of the subject overlapping the period of arg
+	CountOverlappingBaseAssignments(ctx context.Context, arg models.CreateAssignmentInput) (int64, error)
+	// ListActiveAssignmentsByPlan returns the assignments of the plan valid at the given time
+	ListActiveAssignmentsByPlan(ctx context.Context, planID uuid.UUID, at time.Time) ([]models.PlanAssignment, error)
+	// MigrateAssignments moves the selected assignments of a plan to another of its versions and returns how many were moved
+	MigrateAssignments(ctx context.Context, arg models.MigratePlanVersionInput) (int64, error)
+}
+
+type PlanVersionStoreRepository interface {
+	// PublishPlanVersion snapshots the draft features and quotas of the plan into a new version
+	PublishPlanVersion(ctx context.Context, planID uuid.UUID, arg models.PublishPlanVersionInput) (*models.PlanVersion, error)
+	ListPlanVersions(ctx context.Context, planID uuid.UUID) ([]models.PlanVersion, error)
+	GetPlanVersion(ctx context.Context, planID uuid.UUID, version int) (*models.PlanVersion, error)
+	GetLatestPlanVersion(ctx context.Context, planID uuid.UUID) (*models.PlanVersion, error)
+}
+
+type PlanChangeStoreRepository interface {
+	// CreatePlanChange ends the assignment being changed at the effective time and creates the
+	// assignment of the new plan, if any, in one transaction with the change recording them
+	CreatePlanChange(ctx context.Context, arg models.CreatePlanChangeInput) (*models.PlanChange, error)
+	GetPlanChange(ctx context.Context, id uuid.UUID, at time.Time) (*models.PlanChange, error)
+	ListPlanChanges(ctx context.Context, arg models.QueryPlanChangeInput, pagination pagination.Pagination) (*pagination.PaginationView[models.PlanChange], error)
+	// CancelPlanChange deletes the assignment of the new plan and restores the end of the changed
+	// assignment in one transaction with marking the change canceled
+	CancelPlanChange(ctx context.Context, id uuid.UUID, canceledBy string, at time.Time) (*models.PlanChange, error)
+}
+
+type TrialStoreRepository interface {
+	// CreateTrial creates the trial assignment together with the trial recording what happens when it ends
+	CreateTrial(ctx context.Context, arg models.CreateTrialInput) (*models.Trial, error)
+	GetTrial(ctx context.Context, id uuid.UUID) (*models.Trial, error)
+	ListTrials(ctx context.Context, arg models.QueryTrialInput, pagination pagination.Pagination) (*pagination.PaginationView[models.Trial], error)
+	// ListDueTrials returns the active trials of every tenant that ended by the given time
+	ListDueTrials(ctx context.Context, at time.Time, limit int) ([]models.Trial, error)
+	ExtendTrial(ctx context.Context, id uuid.UUID, until time.Time, updatedBy string) (*models.Trial, error)
+	// EndTrial creates the assignment the subject is moved onto, if any, and records the outcome of
+	// the trial in its assignment history in one transaction with marking the trial ended
+	EndTrial(ctx context.Context, arg models.EndTrialInput) (*models.Trial, error)
 }
 
 type FeatureStoreRepository interface {
@@ -55,8 +106,11 @@ type PlanFeatureStoreRepository interface {
 	UpdatePlanFeature(ctx context.Context, planID, featureID uuid.UUID, arg models.UpdatePlanFeatureInput) (*models.PlanFeature, error)
 	DeletePlanFeature(ctx context.Context, arg models.DeletePlanFeatureInput) error
 	ListPlanFeaturesByPlan(ctx context.Context, planID uuid.UUID, filter models.PlanFeatureListFilter) ([]models.PlanFeature, error)
+	// ListPlanFeaturesByVersion returns the features published in the plan version
+	ListPlanFeaturesByVersion(ctx context.Context, planVersionID uuid.UUID, filter models.PlanFeatureListFilter) ([]models.PlanFeature, error)
 	CheckPlanAndFeatureForTenant(ctx context.Context, planID, featureID uuid.UUID) (bool, error)
 	GetPlanFeatureIDByPlanAndFeature(ctx context.Context, planID, featureID uuid.UUID) (uuid.UUID, error)
+	GetPlanFeatureByID(ctx context.Context, planFeatureID uuid.UUID) (*models.PlanFeature, error)
 }
 
 type PlanFeatureQuotaStoreRepository interface {
@@ -65,4 +119,53 @@ type PlanFeatureQuotaStoreRepository interface {
 	UpdatePlanFeatureQuota(ctx context.Context, arg models.UpdatePlanFeatureQuotaInput) (*models.PlanFeatureQuota, error)
 	DeletePlanFeatureQuota(ctx context.Context, planFeatureID uuid.UUID) error
 	CheckMeteredFeature(ctx context.Context, planFeatureID uuid.UUID) (bool, error)
+	// GetQuotaGraceState returns when the subject last used up the allowance of the quota of the feature
+	GetQuotaGraceState(ctx context.Context, subjectType models.SubjectType, subjectID string, featureID uuid.UUID) (*models.QuotaGraceState, error)
+	// SaveQuotaGraceState records when the subject used up the allowance, replacing the previous record
+	SaveQuotaGraceState(ctx context.Context, state models.QuotaGraceState) (*models.QuotaGraceState, error)
+}
+
+type EntitlementOverrideStoreRepository interface {
+	CreateEntitlementOverride(ctx context.Context, arg models.CreateEntitlementOverrideInput) (*models.EntitlementOverride, error)
+	GetEntitlementOverride(ctx context.Context, id uuid.UUID) (*models.EntitlementOverride, error)
+	ListEntitlementOverrides(ctx context.Context, arg models.QueryEntitlementOverrideInput, pagination pagination.Pagination) (*pagination.PaginationView[models.EntitlementOverride], error)
+	// ListActiveEntitlementOverrides returns the overrides of the subject in force at the given time, oldest first
+	ListActiveEntitlementOverrides(ctx context.Context, subjectType models.SubjectType, subjectID string, at time.Time) ([]models.EntitlementOverride, error)
+	UpdateEntitlementOverride(ctx context.Context, id uuid.UUID, arg models.UpdateEntitlementOverrideInput) (*models.EntitlementOverride, error)
+	DeleteEntitlementOverride(ctx context.Context, id uuid.UUID) error
+}
+
+type AlertStoreRepository interface {
+	CreateAlertRule(ctx context.Context, arg models.CreateAlertRuleInput) (*models.AlertRule, error)
+	GetAlertRule(ctx context.Context, id uuid.UUID) (*models.AlertRule, error)
+	ListAlertRules(ctx context.Context, pagination pagination.Pagination) (*pagination.PaginationView[models.AlertRule], error)
+	UpdateAlertRule(ctx context.Context, id uuid.UUID, arg models.UpdateAlertRuleInput) (*models.AlertRule, error)
+	DeleteAlertRule(ctx context.Context, id uuid.UUID) error
+	// ListEnabledAlertRules returns the enabled alert rules of every tenant
+	ListEnabledAlertRules(ctx context.Context) ([]*models.AlertRule, error)
+	ListAlertStates(ctx context.Context, ruleID uuid.UUID) ([]models.AlertState, error)
+	SaveAlertState(ctx context.Context, state models.AlertState) (*models.AlertState, error)
+}
+
+type WebhookStoreRepository interface {
+	CreateWebhookEndpoint(ctx context.Context, arg models.CreateWebhookEndpointInput) (*models.WebhookEndpoint, error)
+	GetWebhookEndpoint(ctx context.Context, id uuid.UUID) (*models.WebhookEndpoint, error)
+	ListWebhookEndpoints(ctx context.Context, pagination pagination.Pagination) (*pagination.PaginationView[models.WebhookEndpoint], error)
+	// ListSubscribedWebhookEndpoints returns the enabled endpoints of the tenant subscribed to the notification type
+	ListSubscribedWebhookEndpoints(ctx context.Context, notificationType models.NotificationType) ([]models.WebhookEndpoint, error)
+	UpdateWebhookEndpoint(ctx context.Context, id uuid.UUID, arg models.UpdateWebhookEndpointInput) (*models.WebhookEndpoint, error)
+	DeleteWebhookEndpoint(ctx context.Context, id uuid.UUID) error
+	// CreateWebhookDelivery queues an event for an endpoint. An event already queued for the
+	// endpoint is not queued again; its existing delivery is returned.
+	CreateWebhookDelivery(ctx context.Context, arg models.CreateWebhookDeliveryInput) (*models.WebhookDelivery, error)
+	// GetWebhookDelivery returns a delivery with the log of its attempts
+	GetWebhookDelivery(ctx context.Context, id uuid.UUID) (*models.WebhookDelivery, error)
+	ListWebhookDeliveries(ctx context.Context, endpointID uuid.UUID, pagination pagination.Pagination) (*pagination.PaginationView[models.WebhookDelivery], error)
+	// ClaimDueWebhookDeliveries returns up to limit pending deliveries of every tenant due at now,
+	// hiding them from other claims until leaseUntil
+	ClaimDueWebhookDeliveries(ctx context.Context, now, leaseUntil time.Time, limit int) ([]models.WebhookDelivery, error)
+	// RecordWebhookAttempt logs an attempt and moves its delivery to the resulting state
+	RecordWebhookAttempt(ctx context.Context, arg models.RecordWebhookAttemptInput) (*models.WebhookDelivery, error)
+	// RequeueWebhookDelivery makes a delivery pending and due immediately
+	RequeueWebhookDelivery(ctx context.Context, id uuid.UUID) (*models.WebhookDelivery, error)
 }
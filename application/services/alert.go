@@ -0,0 +1,139 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redcardinal-io/metering/application/repositories"
+	domainerrors "github.com/redcardinal-io/metering/domain/errors"
+	"github.com/redcardinal-io/metering/domain/models"
+	"github.com/redcardinal-io/metering/domain/pkg/pagination"
+)
+
+// AlertService manages tenant-defined usage alert rules and evaluates them, notifying the alert
+// notifiers when a rule starts firing for a subject.
+type AlertService struct {
+	store     repositories.AlertStoreRepository
+	usage     *SubjectUsageService
+	notifiers []repositories.AlertNotifierRepository
+	now       func() time.Time
+}
+
+// NewAlertService creates an AlertService measuring usage through the given subject usage service.
+func NewAlertService(store repositories.AlertStoreRepository, usage *SubjectUsageService, notifiers ...repositories.AlertNotifierRepository) *AlertService {
+	return &AlertService{
+		store:     store,
+		usage:     usage,
+		notifiers: notifiers,
+		now:       time.Now,
+	}
+}
+
+// CreateAlertRule validates the rule against its meter or plan feature and stores it.
+func (s *AlertService) CreateAlertRule(ctx context.Context, arg models.CreateAlertRuleInput) (*models.AlertRule, error) {
+	if arg.SubjectType == "" {
+		arg.SubjectType = models.SubjectTypeOrganization
+	}
+	if err := s.validateAlertRule(ctx, arg); err != nil {
+		return nil, err
+	}
+	return s.store.CreateAlertRule(ctx, arg)
+}
+
+func (s *AlertService) GetAlertRule(ctx context.Context, id uuid.UUID) (*models.AlertRule, error) {
+	return s.store.GetAlertRule(ctx, id)
+}
+
+func (s *AlertService) ListAlertRules(ctx context.Context, pagination pagination.Pagination) (*pagination.PaginationView[models.AlertRule], error) {
+	return s.store.ListAlertRules(ctx, pagination)
+}
+
+// UpdateAlertRule updates the threshold, subjects, window or enabled state of a rule.
+func (s *AlertService) UpdateAlertRule(ctx context.Context, id uuid.UUID, arg models.UpdateAlertRuleInput) (*models.AlertRule, error) {
+	rule, err := s.store.GetAlertRule(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if arg.Threshold != nil && *arg.Threshold <= 0 {
+		return nil, invalidAlertRule(fmt.Errorf("threshold must be greater than zero"), "AlertService.UpdateAlertRule")
+	}
+	if arg.WindowMinutes != nil && (rule.MeterSlug == "" || *arg.WindowMinutes <= 0) {
+		return nil, invalidAlertRule(fmt.Errorf("window_minutes must be positive and only applies to meter rules"), "AlertService.UpdateAlertRule")
+	}
+	return s.store.UpdateAlertRule(ctx, id, arg)
+}
+
+func (s *AlertService) DeleteAlertRule(ctx context.Context, id uuid.UUID) error {
+	return s.store.DeleteAlertRule(ctx, id)
+}
+
+// ListAlertStates returns the state of the rule for every subject it has fired for.
+func (s *AlertService) ListAlertStates(ctx context.Context, id uuid.UUID) ([]models.AlertState, error) {
+	if _, err := s.store.GetAlertRule(ctx, id); err != nil {
+		return nil, err
+	}
+	return s.store.ListAlertStates(ctx, id)
+}
+
+// EvaluateAlertRule evaluates a rule immediately, whether or not it is enabled, and returns the
+// states of the subjects it evaluated.
+func (s *AlertService) EvaluateAlertRule(ctx context.Context, id uuid.UUID) ([]models.AlertState, error) {
+	rule, err := s.store.GetAlertRule(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.evaluateRule(ctx, rule)
+}
+
+// validateAlertRule checks that a rule targets either an active meter over a window or a metered
+// plan feature, and that percent thresholds are only used against quotas.
+func (s *AlertService) validateAlertRule(ctx context.Context, arg models.CreateAlertRuleInput) error {
+	const op = "AlertService.CreateAlertRule"
+	switch {
+	case (arg.MeterSlug == "") == (arg.PlanFeatureID == nil):
+		return invalidAlertRule(fmt.Errorf("exactly one of meter_slug and plan_feature_id is required"), op)
+	case !models.IsValidSubjectType(arg.SubjectType):
+		return invalidAlertRule(fmt.Errorf("invalid subject_type: %s", arg.SubjectType), op)
+	case !models.IsValidAlertThresholdType(arg.ThresholdType):
+		return invalidAlertRule(fmt.Errorf("invalid threshold_type: %s", arg.ThresholdType), op)
+	case arg.Threshold <= 0:
+		return invalidAlertRule(fmt.Errorf("threshold must be greater than zero"), op)
+	}
+
+	if arg.MeterSlug != "" {
+		if arg.ThresholdType == models.AlertThresholdPercent {
+			return invalidAlertRule(fmt.Errorf("percent thresholds require a plan feature quota"), op)
+		}
+		if arg.WindowMinutes == nil || *arg.WindowMinutes <= 0 {
+			return invalidAlertRule(fmt.Errorf("meter rules require a positive window_minutes"), op)
+		}
+		m, err := s.usage.meters.GetMeterIDorSlug(ctx, arg.MeterSlug)
+		if err != nil {
+			return err
+		}
+		return validateMeterActive(m, op)
+	}
+
+	if arg.WindowMinutes != nil {
+		return invalidAlertRule(fmt.Errorf("plan feature rules use the reset period of the quota instead of window_minutes"), op)
+	}
+	feature, err := s.usage.planFeatures.GetPlanFeatureByID(ctx, *arg.PlanFeatureID)
+	if err != nil {
+		return err
+	}
+	if feature.Type != models.FeatureTypeMetered {
+		return invalidAlertRule(fmt.Errorf("plan feature %s is not metered", feature.ID), op)
+	}
+	return nil
+}
+
+func invalidAlertRule(err error, op string) error {
+	return domainerrors.New(
+		err,
+		domainerrors.EINVALID,
+		"invalid alert rule",
+		domainerrors.WithOperation(op),
+	)
+}
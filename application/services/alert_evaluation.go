@@ -0,0 +1,255 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/redcardinal-io/metering/domain/models"
+)
+
+// alertReading is the usage of a subject compared by an alert rule
+type alertReading struct {
+	subjectID   string
+	value       float64
+	threshold   float64
+	periodStart *time.Time
+}
+
+// evaluateRule measures the usage of every subject of the rule, records the resulting states and
+// notifies subjects that started firing. A subject fires once per episode: it fires again only
+// after its usage dropped below the threshold or, for quota rules, a new quota period started.
+// Firings whose notification fails are not recorded, so the next evaluation retries them.
+func (s *AlertService) evaluateRule(ctx context.Context, rule *models.AlertRule) ([]models.AlertState, error) {
+	now := s.now().UTC()
+
+	var readings []alertReading
+	var err error
+	if rule.MeterSlug != "" {
+		readings, err = s.meterReadings(ctx, rule, now)
+	} else {
+		readings, err = s.quotaReadings(ctx, rule, now)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	previous, err := s.store.ListAlertStates(ctx, rule.ID)
+	if err != nil {
+		return nil, err
+	}
+	previousBySubject := make(map[string]*models.AlertState, len(previous))
+	for i := range previous {
+		previousBySubject[previous[i].SubjectID] = &previous[i]
+	}
+
+	// Firing subjects without a reading have no usage left in the window
+	for _, state := range previous {
+		if state.Status != models.AlertStatusFiring && state.Status != models.AlertStatusNotifying {
+			continue
+		}
+		if !slices.ContainsFunc(readings, func(r alertReading) bool { return r.subjectID == state.SubjectID }) {
+			readings = append(readings, alertReading{subjectID: state.SubjectID, threshold: state.ThresholdValue, periodStart: state.PeriodStart})
+		}
+	}
+
+	states := make([]models.AlertState, 0, len(readings))
+	var errs []error
+	for _, reading := range readings {
+		prev := previousBySubject[reading.subjectID]
+		state, fired := nextAlertState(rule, prev, reading, now)
+		if prev == nil && !fired {
+			// Subjects are only tracked once they fire
+			continue
+		}
+		if fired {
+			// The firing is recorded before notifying, so that a retry keeps its FiredAt and
+			// notifiers see the same firing again
+			notifying := state
+			notifying.Status = models.AlertStatusNotifying
+			if _, err := s.store.SaveAlertState(ctx, notifying); err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			if err := s.notify(ctx, rule, state); err != nil {
+				errs = append(errs, err)
+				continue
+			}
+		}
+		saved, err := s.store.SaveAlertState(ctx, state)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		states = append(states, *saved)
+	}
+	return states, errors.Join(errs...)
+}
+
+// nextAlertState applies a reading to the previous state of a subject and reports whether the
+// rule started firing. A firing still being notified fires again at its original FiredAt.
+func nextAlertState(rule *models.AlertRule, prev *models.AlertState, reading alertReading, now time.Time) (models.AlertState, bool) {
+	state := models.AlertState{AlertRuleID: rule.ID, SubjectID: reading.subjectID, Status: models.AlertStatusOK}
+	if prev != nil {
+		state = *prev
+	}
+	newPeriod := prev != nil && !equalTimes(prev.PeriodStart, reading.periodStart)
+	state.Value = reading.value
+	state.ThresholdValue = reading.threshold
+	state.PeriodStart = reading.periodStart
+	state.EvaluatedAt = now
+
+	if reading.value >= reading.threshold {
+		if prev != nil && prev.Status == models.AlertStatusFiring && !newPeriod {
+			return state, false
+		}
+		if prev == nil || prev.Status != models.AlertStatusNotifying || newPeriod {
+			state.FiredAt = &now
+		}
+		state.Status = models.AlertStatusFiring
+		state.ResolvedAt = nil
+		return state, true
+	}
+	if state.Status == models.AlertStatusFiring || state.Status == models.AlertStatusNotifying {
+		state.ResolvedAt = &now
+	}
+	state.Status = models.AlertStatusOK
+	return state, false
+}
+
+// meterReadings reads the usage of each subject on the meter of the rule over its trailing window,
+// widened to the buckets of the meter so that the first one and the one still open are counted.
+func (s *AlertService) meterReadings(ctx context.Context, rule *models.AlertRule, now time.Time) ([]alertReading, error) {
+	column := string(rule.SubjectType)
+	from, to, err := s.usage.bucketRange(ctx, rule.MeterSlug, now.Add(-time.Duration(*rule.WindowMinutes)*time.Minute), now)
+	if err != nil {
+		return nil, err
+	}
+	params := models.QueryMeterParams{
+		MeterSlug: rule.MeterSlug,
+		From:      &from,
+		To:        &to,
+		GroupBy:   []string{column},
+	}
+	if len(rule.SubjectIDs) > 0 {
+		params.FilterGroupBy = map[string][]string{column: rule.SubjectIDs}
+	}
+	result, err := s.usage.meters.QueryMeter(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	readings := make([]alertReading, 0, len(result.Data))
+	for _, row := range result.Data {
+		if row.GroupBy[column] == "" {
+			continue
+		}
+		readings = append(readings, alertReading{subjectID: row.GroupBy[column], value: row.Value, threshold: rule.Threshold})
+	}
+	return readings, nil
+}
+
+// quotaReadings reads the usage of each subject assigned the plan of the rule's plan feature over
+// the current period of the feature's quota, merged across the base plan and add-ons the subject
+// holds. Subjects whose plan versions do not include the feature are skipped.
+func (s *AlertService) quotaReadings(ctx context.Context, rule *models.AlertRule, now time.Time) ([]alertReading, error) {
+	feature, err := s.usage.planFeatures.GetPlanFeatureByID(ctx, *rule.PlanFeatureID)
+	if err != nil {
+		return nil, err
+	}
+	assignments, err := s.ruleAssignments(ctx, rule, feature, now)
+	if err != nil {
+		return nil, err
+	}
+
+	readings := make([]alertReading, 0, len(assignments))
+	for _, assignment := range assignments {
+		subjectID := assignment.OrganizationID
+		if rule.SubjectType == models.SubjectTypeUser {
+			subjectID = assignment.UserID
+		}
+		resolved, err := s.usage.subjectEntitlements(ctx, rule.SubjectType, subjectID, now, models.PlanFeatureListFilter{})
+		if err != nil {
+			return nil, err
+		}
+		entitlements := resolved.entitlements
+		i := slices.IndexFunc(entitlements, func(e entitlement) bool { return e.FeatureID == feature.FeatureID })
+		if i < 0 {
+			continue
+		}
+		e := entitlements[i]
+
+		threshold := rule.Threshold
+		if rule.ThresholdType == models.AlertThresholdPercent {
+			if e.Quota == nil {
+				// Without a quota the subject has no limit to reach a share of
+				continue
+			}
+			threshold = float64(e.Quota.LimitValue) * rule.Threshold / 100
+		}
+
+		start, _ := quotaPeriod(e.Quota, e.assignedAt, now)
+		used, err := s.usage.subjectMeterUsage(ctx, rule.SubjectType, subjectID, feature.MeterSlugs, start, now)
+		if err != nil {
+			return nil, err
+		}
+		readings = append(readings, alertReading{subjectID: subjectID, value: used, threshold: threshold, periodStart: &start})
+	}
+	return readings, nil
+}
+
+// ruleAssignments returns the active assignments of the plan of the feature to the subjects of the rule.
+func (s *AlertService) ruleAssignments(ctx context.Context, rule *models.AlertRule, feature *models.PlanFeature, now time.Time) ([]models.PlanAssignment, error) {
+	if len(rule.SubjectIDs) == 0 {
+		assignments, err := s.usage.assignments.ListActiveAssignmentsByPlan(ctx, feature.PlanID, now)
+		if err != nil {
+			return nil, err
+		}
+		return slices.DeleteFunc(assignments, func(a models.PlanAssignment) bool {
+			if rule.SubjectType == models.SubjectTypeUser {
+				return a.UserID == ""
+			}
+			return a.OrganizationID == ""
+		}), nil
+	}
+
+	assignments := make([]models.PlanAssignment, 0, len(rule.SubjectIDs))
+	for _, subjectID := range rule.SubjectIDs {
+		arg := models.GetActiveAssignmentInput{At: now}
+		if rule.SubjectType == models.SubjectTypeUser {
+			arg.UserID = subjectID
+		} else {
+			arg.OrganizationID = subjectID
+		}
+		active, err := s.usage.assignments.ListActiveSubjectAssignments(ctx, arg)
+		if err != nil {
+			return nil, err
+		}
+		// Subjects that moved to another plan are no longer subject to the quota
+		i := slices.IndexFunc(active, func(a models.PlanAssignment) bool { return a.PlanID == feature.PlanID.String() })
+		if i >= 0 {
+			assignments = append(assignments, active[i])
+		}
+	}
+	return assignments, nil
+}
+
+// notify publishes the firing of a rule for a subject to every alert notifier.
+func (s *AlertService) notify(ctx context.Context, rule *models.AlertRule, state models.AlertState) error {
+	event := models.AlertEvent{TenantSlug: rule.TenantSlug, Rule: *rule, State: state}
+	var errs []error
+	for _, notifier := range s.notifiers {
+		if err := notifier.NotifyAlert(ctx, event); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func equalTimes(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
@@ -0,0 +1,74 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/redcardinal-io/metering/application/repositories"
+	"github.com/redcardinal-io/metering/domain/models"
+	"github.com/redcardinal-io/metering/domain/pkg/config"
+	"github.com/redcardinal-io/metering/domain/pkg/constants"
+	"github.com/redcardinal-io/metering/domain/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// AlertEvaluator periodically evaluates the enabled alert rules of every tenant.
+type AlertEvaluator struct {
+	alerts *AlertService
+	store  repositories.AlertStoreRepository
+	config config.AlertEvaluatorConfig
+	logger *logger.Logger
+}
+
+// NewAlertEvaluator creates an AlertEvaluator evaluating rules through the given alert service.
+func NewAlertEvaluator(alerts *AlertService, store repositories.AlertStoreRepository, cfg config.AlertEvaluatorConfig, logger *logger.Logger) *AlertEvaluator {
+	return &AlertEvaluator{
+		alerts: alerts,
+		store:  store,
+		config: cfg,
+		logger: logger,
+	}
+}
+
+// Run evaluates alert rules every configured interval until the context is cancelled.
+func (e *AlertEvaluator) Run(ctx context.Context) {
+	if e.config.Interval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(e.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := e.Evaluate(ctx); err != nil {
+				e.logger.Error("failed to evaluate alert rules", zap.Error(err))
+			}
+		}
+	}
+}
+
+// Evaluate makes a single pass over the enabled alert rules and returns the states they recorded.
+// Rules that fail are logged and do not stop the pass.
+func (e *AlertEvaluator) Evaluate(ctx context.Context) ([]models.AlertState, error) {
+	rules, err := e.store.ListEnabledAlertRules(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var states []models.AlertState
+	for _, rule := range rules {
+		tenantCtx := context.WithValue(ctx, constants.TenantSlugKey, rule.TenantSlug)
+		ruleStates, err := e.alerts.evaluateRule(tenantCtx, rule)
+		if err != nil {
+			e.logger.Error("failed to evaluate alert rule",
+				zap.String("tenant", rule.TenantSlug),
+				zap.String("rule", rule.ID.String()),
+				zap.Error(err))
+		}
+		states = append(states, ruleStates...)
+	}
+	return states, nil
+}
@@ -0,0 +1,284 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/redcardinal-io/metering/application/repositories"
+	domainerrors "github.com/redcardinal-io/metering/domain/errors"
+	"github.com/redcardinal-io/metering/domain/models"
+	"github.com/redcardinal-io/metering/domain/pkg/config"
+)
+
+// fakeAlertStore keeps alert rules and states in memory
+type fakeAlertStore struct {
+	repositories.AlertStoreRepository
+	rules  map[uuid.UUID]*models.AlertRule
+	states map[uuid.UUID]map[string]models.AlertState
+}
+
+func (f *fakeAlertStore) GetAlertRule(ctx context.Context, id uuid.UUID) (*models.AlertRule, error) {
+	rule, ok := f.rules[id]
+	if !ok {
+		return nil, domainerrors.New(nil, domainerrors.ENOTFOUND, "Resource not found")
+	}
+	return rule, nil
+}
+
+func (f *fakeAlertStore) ListAlertStates(ctx context.Context, ruleID uuid.UUID) ([]models.AlertState, error) {
+	states := make([]models.AlertState, 0, len(f.states[ruleID]))
+	for _, state := range f.states[ruleID] {
+		states = append(states, state)
+	}
+	return states, nil
+}
+
+func (f *fakeAlertStore) SaveAlertState(ctx context.Context, state models.AlertState) (*models.AlertState, error) {
+	if f.states[state.AlertRuleID] == nil {
+		f.states[state.AlertRuleID] = map[string]models.AlertState{}
+	}
+	f.states[state.AlertRuleID][state.SubjectID] = state
+	return &state, nil
+}
+
+// fakeAlertNotifier records the alert events it is notified of
+type fakeAlertNotifier struct {
+	events []models.AlertEvent
+	err    error
+}
+
+func (f *fakeAlertNotifier) NotifyAlert(ctx context.Context, event models.AlertEvent) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.events = append(f.events, event)
+	return nil
+}
+
+func (f *fakePlanFeatureStore) GetPlanFeatureByID(ctx context.Context, planFeatureID uuid.UUID) (*models.PlanFeature, error) {
+	for i := range f.features {
+		if f.features[i].ID == planFeatureID {
+			return &f.features[i], nil
+		}
+	}
+	return nil, domainerrors.New(nil, domainerrors.ENOTFOUND, "Resource not found")
+}
+
+func TestAlertService_EvaluateMeterRule(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 6, 18, 12, 0, 0, 0, time.UTC)
+	ruleID := uuid.New()
+
+	olap := &fakeOlap{results: map[string]*models.QueryMeterResult{
+		"api_calls": {Data: []models.QueryMeterRow{
+			{Value: 120, GroupBy: map[string]string{"organization": "org-a"}},
+			{Value: 10, GroupBy: map[string]string{"organization": "org-b"}},
+		}},
+	}}
+	meters := &fakeMeterStore{meters: map[string]*models.Meter{
+		"api_calls": {Slug: "api_calls", Type: models.MeterTypeStandard, Status: models.MeterStatusActive, Aggregation: models.AggregationCount},
+	}}
+	store := &fakeAlertStore{
+		rules: map[uuid.UUID]*models.AlertRule{ruleID: {
+			Base:          models.Base{ID: ruleID},
+			MeterSlug:     "api_calls",
+			SubjectType:   models.SubjectTypeOrganization,
+			ThresholdType: models.AlertThresholdAbsolute,
+			Threshold:     100,
+			WindowMinutes: ptr(int64(60)),
+			Enabled:       true,
+		}},
+		states: map[uuid.UUID]map[string]models.AlertState{},
+	}
+	notifier := &fakeAlertNotifier{}
+	usage := NewSubjectUsageService(nil, nil, nil, nil, NewMeterService(olap, meters, nil, config.QueryCacheConfig{}))
+	svc := NewAlertService(store, usage, notifier)
+	svc.now = func() time.Time { return now }
+
+	t.Run("Fires once for subjects over the threshold", func(t *testing.T) {
+		states, err := svc.EvaluateAlertRule(ctx, ruleID)
+		require.NoError(t, err)
+		require.Len(t, states, 1)
+		assert.Equal(t, "org-a", states[0].SubjectID)
+		assert.Equal(t, models.AlertStatusFiring, states[0].Status)
+		assert.Equal(t, now, *states[0].FiredAt)
+		require.Len(t, notifier.events, 1)
+
+		_, err = svc.EvaluateAlertRule(ctx, ruleID)
+		require.NoError(t, err)
+		assert.Len(t, notifier.events, 1)
+	})
+
+	t.Run("Resolves subjects back under the threshold", func(t *testing.T) {
+		olap.results["api_calls"] = &models.QueryMeterResult{}
+
+		states, err := svc.EvaluateAlertRule(ctx, ruleID)
+		require.NoError(t, err)
+		require.Len(t, states, 1)
+		assert.Equal(t, models.AlertStatusOK, states[0].Status)
+		assert.Equal(t, now, *states[0].ResolvedAt)
+		assert.Len(t, notifier.events, 1)
+	})
+
+	t.Run("Retries firings whose notification failed", func(t *testing.T) {
+		olap.results["api_calls"] = &models.QueryMeterResult{Data: []models.QueryMeterRow{
+			{Value: 150, GroupBy: map[string]string{"organization": "org-a"}},
+		}}
+		notifier.err = errors.New("unreachable")
+
+		_, err := svc.EvaluateAlertRule(ctx, ruleID)
+		assert.Error(t, err)
+		assert.Equal(t, models.AlertStatusNotifying, store.states[ruleID]["org-a"].Status)
+
+		notifier.err = nil
+		svc.now = func() time.Time { return now.Add(time.Minute) }
+		defer func() { svc.now = func() time.Time { return now } }()
+		states, err := svc.EvaluateAlertRule(ctx, ruleID)
+		require.NoError(t, err)
+		require.Len(t, states, 1)
+		assert.Equal(t, models.AlertStatusFiring, states[0].Status)
+		require.Len(t, notifier.events, 2)
+		// The retry notifies the same firing, so notifiers can deduplicate it
+		assert.Equal(t, now, *states[0].FiredAt)
+		assert.Equal(t, now, *notifier.events[1].State.FiredAt)
+	})
+
+	t.Run("Counts the bucket of the meter that is still open", func(t *testing.T) {
+		today := time.Date(2025, 6, 18, 0, 0, 0, 0, time.UTC)
+		olap := &rangeOlap{rows: map[string][]models.QueryMeterRow{"daily_calls": {
+			{WindowStart: today, WindowEnd: today.AddDate(0, 0, 1), Value: 500, GroupBy: map[string]string{"organization": "org-a"}},
+		}}}
+		meters := &fakeMeterStore{meters: map[string]*models.Meter{
+			"daily_calls": {Slug: "daily_calls", Type: models.MeterTypeStandard, Status: models.MeterStatusActive, Aggregation: models.AggregationCount, Granularity: models.GranularityDay},
+		}}
+		dailyRuleID := uuid.New()
+		store := &fakeAlertStore{
+			rules: map[uuid.UUID]*models.AlertRule{dailyRuleID: {
+				Base:          models.Base{ID: dailyRuleID},
+				MeterSlug:     "daily_calls",
+				SubjectType:   models.SubjectTypeOrganization,
+				ThresholdType: models.AlertThresholdAbsolute,
+				Threshold:     100,
+				WindowMinutes: ptr(int64(24 * 60)),
+				Enabled:       true,
+			}},
+			states: map[uuid.UUID]map[string]models.AlertState{},
+		}
+		usage := NewSubjectUsageService(nil, nil, nil, nil, NewMeterService(olap, meters, nil, config.QueryCacheConfig{}))
+		svc := NewAlertService(store, usage, &fakeAlertNotifier{})
+		svc.now = func() time.Time { return now }
+
+		states, err := svc.EvaluateAlertRule(ctx, dailyRuleID)
+		require.NoError(t, err)
+		require.Len(t, states, 1)
+		assert.Equal(t, float64(500), states[0].Value)
+	})
+}
+
+func TestAlertService_EvaluateQuotaRule(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 6, 18, 12, 0, 0, 0, time.UTC)
+	planID, planFeatureID, ruleID := uuid.New(), uuid.New(), uuid.New()
+	featureID, versionA, versionB, versionFeatureID := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+
+	olap := &fakeOlap{results: map[string]*models.QueryMeterResult{
+		"api_calls": {Data: []models.QueryMeterRow{{Value: 850}}},
+	}}
+	meters := &fakeMeterStore{meters: map[string]*models.Meter{
+		"api_calls": {Slug: "api_calls", Type: models.MeterTypeStandard, Status: models.MeterStatusActive, Aggregation: models.AggregationCount},
+	}}
+	planFeatures := &fakePlanFeatureStore{
+		features: []models.PlanFeature{
+			{Base: models.Base{ID: planFeatureID}, PlanID: planID, FeatureID: featureID, FeatureSlug: "api", MeterSlugs: []string{"api_calls"}},
+		},
+		versions: map[uuid.UUID][]models.PlanFeature{
+			versionA: {{Base: models.Base{ID: versionFeatureID}, PlanID: planID, FeatureID: featureID, FeatureSlug: "api", MeterSlugs: []string{"api_calls"}}},
+			versionB: {},
+		},
+	}
+	// The quota comes from the version each subject is pinned to, not from the draft
+	quotas := &fakeQuotaStore{quotas: map[uuid.UUID]*models.PlanFeatureQuota{
+		planFeatureID:    {LimitValue: 100, ResetPeriod: models.MeteredResetPeriodMonth},
+		versionFeatureID: {LimitValue: 1000, ResetPeriod: models.MeteredResetPeriodMonth},
+	}}
+	store := &fakeAlertStore{
+		rules: map[uuid.UUID]*models.AlertRule{ruleID: {
+			Base:          models.Base{ID: ruleID},
+			PlanFeatureID: &planFeatureID,
+			SubjectType:   models.SubjectTypeOrganization,
+			ThresholdType: models.AlertThresholdPercent,
+			Threshold:     80,
+			Enabled:       true,
+		}},
+		states: map[uuid.UUID]map[string]models.AlertState{},
+	}
+	assignments := new(MockPlanAssignmentsStoreRepository)
+	assignments.On("ListActiveAssignmentsByPlan", mock.Anything, planID, mock.Anything).Return([]models.PlanAssignment{
+		{PlanID: planID.String(), PlanVersionID: versionA.String(), OrganizationID: "org-a", ValidFrom: now.AddDate(0, -3, 0)},
+		{PlanID: planID.String(), PlanVersionID: versionB.String(), OrganizationID: "org-b", ValidFrom: now.AddDate(0, -3, 0)},
+		{PlanID: planID.String(), PlanVersionID: versionA.String(), UserID: "user-a", ValidFrom: now.AddDate(0, -3, 0)},
+	}, nil)
+	subject := func(organizationID string) any {
+		return mock.MatchedBy(func(arg models.GetActiveAssignmentInput) bool { return arg.OrganizationID == organizationID })
+	}
+	assignments.On("ListActiveSubjectAssignments", mock.Anything, subject("org-a")).Return([]models.PlanAssignment{
+		{PlanID: planID.String(), PlanKind: models.PlanKindBase, PlanVersionID: versionA.String(), OrganizationID: "org-a", ValidFrom: now.AddDate(0, -3, 0)},
+	}, nil)
+	assignments.On("ListActiveSubjectAssignments", mock.Anything, subject("org-b")).Return([]models.PlanAssignment{
+		{PlanID: planID.String(), PlanKind: models.PlanKindBase, PlanVersionID: versionB.String(), OrganizationID: "org-b", ValidFrom: now.AddDate(0, -3, 0)},
+	}, nil)
+
+	notifier := &fakeAlertNotifier{}
+	usage := NewSubjectUsageService(assignments, planFeatures, quotas, &fakeEntitlementOverrideStore{}, NewMeterService(olap, meters, nil, config.QueryCacheConfig{}))
+	svc := NewAlertService(store, usage, notifier)
+	svc.now = func() time.Time { return now }
+
+	states, err := svc.EvaluateAlertRule(ctx, ruleID)
+	require.NoError(t, err)
+	require.Len(t, states, 1)
+	assert.Equal(t, "org-a", states[0].SubjectID)
+	assert.Equal(t, float64(800), states[0].ThresholdValue)
+	assert.Equal(t, time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC), *states[0].PeriodStart)
+	require.Len(t, notifier.events, 1)
+
+	// The next quota period fires again even though usage never dropped
+	svc.now = func() time.Time { return now.AddDate(0, 1, 0) }
+	_, err = svc.EvaluateAlertRule(ctx, ruleID)
+	require.NoError(t, err)
+	assert.Len(t, notifier.events, 2)
+}
+
+func TestAlertService_CreateAlertRule(t *testing.T) {
+	ctx := context.Background()
+	meters := &fakeMeterStore{meters: map[string]*models.Meter{
+		"api_calls": {Slug: "api_calls", Type: models.MeterTypeStandard, Status: models.MeterStatusActive, Aggregation: models.AggregationCount},
+	}}
+	usage := NewSubjectUsageService(nil, &fakePlanFeatureStore{}, nil, &fakeEntitlementOverrideStore{}, NewMeterService(&fakeOlap{}, meters, nil, config.QueryCacheConfig{}))
+	svc := NewAlertService(&fakeAlertStore{}, usage)
+	planFeatureID := uuid.New()
+
+	tests := []struct {
+		name string
+		arg  models.CreateAlertRuleInput
+	}{
+		{"no target", models.CreateAlertRuleInput{ThresholdType: models.AlertThresholdAbsolute, Threshold: 10}},
+		{"both targets", models.CreateAlertRuleInput{MeterSlug: "api_calls", PlanFeatureID: &planFeatureID, ThresholdType: models.AlertThresholdAbsolute, Threshold: 10}},
+		{"percent on meter", models.CreateAlertRuleInput{MeterSlug: "api_calls", ThresholdType: models.AlertThresholdPercent, Threshold: 80, WindowMinutes: ptr(int64(5))}},
+		{"meter without window", models.CreateAlertRuleInput{MeterSlug: "api_calls", ThresholdType: models.AlertThresholdAbsolute, Threshold: 10}},
+		{"window on quota", models.CreateAlertRuleInput{PlanFeatureID: &planFeatureID, ThresholdType: models.AlertThresholdPercent, Threshold: 80, WindowMinutes: ptr(int64(5))}},
+		{"zero threshold", models.CreateAlertRuleInput{MeterSlug: "api_calls", ThresholdType: models.AlertThresholdAbsolute, WindowMinutes: ptr(int64(5))}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := svc.CreateAlertRule(ctx, tt.arg)
+			assert.Equal(t, string(domainerrors.EINVALID), domainerrors.GetErrorCode(err))
+		})
+	}
+}
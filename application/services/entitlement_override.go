@@ -0,0 +1,172 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redcardinal-io/metering/application/repositories"
+	domainerrors "github.com/redcardinal-io/metering/domain/errors"
+	"github.com/redcardinal-io/metering/domain/models"
+	"github.com/redcardinal-io/metering/domain/pkg/pagination"
+)
+
+// EntitlementOverrideService manages the per-subscriber overrides granting, revoking or adjusting
+// features on top of the plans a subject holds.
+type EntitlementOverrideService struct {
+	store       repositories.EntitlementOverrideStoreRepository
+	features    repositories.FeatureStoreRepository
+	assignments repositories.PlanAssignmentsStoreRepository
+	now         func() time.Time
+}
+
+// NewEntitlementOverrideService creates an EntitlementOverrideService resolving features and assignments through the given stores.
+func NewEntitlementOverrideService(
+	store repositories.EntitlementOverrideStoreRepository,
+	features repositories.FeatureStoreRepository,
+	assignments repositories.PlanAssignmentsStoreRepository,
+) *EntitlementOverrideService {
+	return &EntitlementOverrideService{
+		store:       store,
+		features:    features,
+		assignments: assignments,
+		now:         time.Now,
+	}
+}
+
+// CreateEntitlementOverride validates the override against its feature and, when attached to one,
+// the assignment of the subject, and stores it.
+func (s *EntitlementOverrideService) CreateEntitlementOverride(ctx context.Context, arg models.CreateEntitlementOverrideInput) (*models.EntitlementOverride, error) {
+	const op = "EntitlementOverrideService.CreateEntitlementOverride"
+	switch {
+	case !models.IsValidSubjectType(arg.SubjectType) || arg.SubjectID == "":
+		return nil, invalidEntitlementOverride(fmt.Errorf("invalid subject %s %q", arg.SubjectType, arg.SubjectID), op)
+	case !models.IsValidEntitlementOverrideEffect(arg.Effect):
+		return nil, invalidEntitlementOverride(fmt.Errorf("invalid effect: %s", arg.Effect), op)
+	}
+
+	feature, err := s.features.GetFeatureByIDorSlug(ctx, arg.Feature)
+	if err != nil {
+		return nil, err
+	}
+	arg.FeatureID = feature.ID
+
+	override := models.EntitlementOverride{
+		FeatureType:         feature.Type,
+		Effect:              arg.Effect,
+		LimitValue:          arg.LimitValue,
+		ResetPeriod:         arg.ResetPeriod,
+		CustomPeriodMinutes: arg.CustomPeriodMinutes,
+		ActionAtLimit:       arg.ActionAtLimit,
+		ExpiresAt:           arg.ExpiresAt,
+	}
+	if err := s.validateEntitlementOverride(override, op); err != nil {
+		return nil, err
+	}
+
+	if arg.PlanAssignmentID != nil {
+		assignment, err := s.assignments.GetAssignment(ctx, *arg.PlanAssignmentID)
+		if err != nil {
+			return nil, err
+		}
+		subjectID := assignment.OrganizationID
+		if arg.SubjectType == models.SubjectTypeUser {
+			subjectID = assignment.UserID
+		}
+		if subjectID != arg.SubjectID {
+			return nil, invalidEntitlementOverride(fmt.Errorf("assignment %s does not belong to %s %s", assignment.ID, arg.SubjectType, arg.SubjectID), op)
+		}
+	}
+
+	return s.store.CreateEntitlementOverride(ctx, arg)
+}
+
+// GetEntitlementOverride returns an override of the subject.
+func (s *EntitlementOverrideService) GetEntitlementOverride(ctx context.Context, subjectType models.SubjectType, subjectID string, id uuid.UUID) (*models.EntitlementOverride, error) {
+	override, err := s.store.GetEntitlementOverride(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if override.SubjectType != subjectType || override.SubjectID != subjectID {
+		return nil, domainerrors.New(
+			fmt.Errorf("entitlement override %s does not belong to %s %s", id, subjectType, subjectID),
+			domainerrors.ENOTFOUND,
+			"entitlement override not found",
+			domainerrors.WithOperation("EntitlementOverrideService.GetEntitlementOverride"),
+		)
+	}
+	return override, nil
+}
+
+// ListEntitlementOverrides lists the overrides of a subject, newest first.
+func (s *EntitlementOverrideService) ListEntitlementOverrides(ctx context.Context, arg models.QueryEntitlementOverrideInput, pagination pagination.Pagination) (*pagination.PaginationView[models.EntitlementOverride], error) {
+	arg.At = s.now().UTC()
+	return s.store.ListEntitlementOverrides(ctx, arg, pagination)
+}
+
+// UpdateEntitlementOverride replaces the quota fields, reason or expiry of an override of the subject.
+func (s *EntitlementOverrideService) UpdateEntitlementOverride(ctx context.Context, subjectType models.SubjectType, subjectID string, id uuid.UUID, arg models.UpdateEntitlementOverrideInput) (*models.EntitlementOverride, error) {
+	override, err := s.GetEntitlementOverride(ctx, subjectType, subjectID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	updated := *override
+	if arg.LimitValue != nil {
+		updated.LimitValue = arg.LimitValue
+	}
+	if arg.ResetPeriod != "" {
+		updated.ResetPeriod = arg.ResetPeriod
+	}
+	if arg.CustomPeriodMinutes != nil {
+		updated.CustomPeriodMinutes = arg.CustomPeriodMinutes
+	}
+	if arg.ActionAtLimit != "" {
+		updated.ActionAtLimit = arg.ActionAtLimit
+	}
+	updated.ExpiresAt = arg.ExpiresAt
+	if err := s.validateEntitlementOverride(updated, "EntitlementOverrideService.UpdateEntitlementOverride"); err != nil {
+		return nil, err
+	}
+
+	return s.store.UpdateEntitlementOverride(ctx, id, arg)
+}
+
+// DeleteEntitlementOverride deletes an override of the subject.
+func (s *EntitlementOverrideService) DeleteEntitlementOverride(ctx context.Context, subjectType models.SubjectType, subjectID string, id uuid.UUID) error {
+	if _, err := s.GetEntitlementOverride(ctx, subjectType, subjectID, id); err != nil {
+		return err
+	}
+	return s.store.DeleteEntitlementOverride(ctx, id)
+}
+
+// validateEntitlementOverride checks that only grants of metered features carry quota fields, that
+// custom reset periods have a length and that expiries lie ahead.
+func (s *EntitlementOverrideService) validateEntitlementOverride(override models.EntitlementOverride, op string) error {
+	setsQuota := override.LimitValue != nil || override.ResetPeriod != "" || override.CustomPeriodMinutes != nil || override.ActionAtLimit != ""
+	switch {
+	case setsQuota && override.Effect == models.EntitlementOverrideRevoke:
+		return invalidEntitlementOverride(fmt.Errorf("revoke overrides cannot set quota fields"), op)
+	case setsQuota && override.FeatureType != models.FeatureTypeMetered:
+		return invalidEntitlementOverride(fmt.Errorf("quota fields only apply to metered features"), op)
+	case override.LimitValue != nil && *override.LimitValue < 0:
+		return invalidEntitlementOverride(fmt.Errorf("limit_value cannot be negative"), op)
+	case override.CustomPeriodMinutes != nil && *override.CustomPeriodMinutes <= 0:
+		return invalidEntitlementOverride(fmt.Errorf("custom_period_minutes must be greater than zero"), op)
+	case override.ResetPeriod == models.MeteredResetPeriodCustom && override.CustomPeriodMinutes == nil:
+		return invalidEntitlementOverride(fmt.Errorf("custom reset periods require custom_period_minutes"), op)
+	case override.ExpiresAt != nil && !override.ExpiresAt.After(s.now()):
+		return invalidEntitlementOverride(fmt.Errorf("expires_at must be in the future"), op)
+	}
+	return nil
+}
+
+func invalidEntitlementOverride(err error, op string) error {
+	return domainerrors.New(
+		err,
+		domainerrors.EINVALID,
+		"invalid entitlement override",
+		domainerrors.WithOperation(op),
+	)
+}
@@ -0,0 +1,159 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/redcardinal-io/metering/application/repositories"
+	domainerrors "github.com/redcardinal-io/metering/domain/errors"
+	"github.com/redcardinal-io/metering/domain/models"
+	"github.com/redcardinal-io/metering/domain/pkg/config"
+)
+
+type fakeEntitlementOverrideStore struct {
+	repositories.EntitlementOverrideStoreRepository
+	active  []models.EntitlementOverride
+	created []models.CreateEntitlementOverrideInput
+}
+
+func (f *fakeEntitlementOverrideStore) ListActiveEntitlementOverrides(ctx context.Context, subjectType models.SubjectType, subjectID string, at time.Time) ([]models.EntitlementOverride, error) {
+	return f.active, nil
+}
+
+func (f *fakeEntitlementOverrideStore) CreateEntitlementOverride(ctx context.Context, arg models.CreateEntitlementOverrideInput) (*models.EntitlementOverride, error) {
+	f.created = append(f.created, arg)
+	return &models.EntitlementOverride{Base: models.Base{ID: uuid.New()}, SubjectType: arg.SubjectType, SubjectID: arg.SubjectID, FeatureID: arg.FeatureID, Effect: arg.Effect}, nil
+}
+
+type fakeFeatureStore struct {
+	repositories.FeatureStoreRepository
+	features map[string]*models.Feature
+}
+
+func (f *fakeFeatureStore) GetFeatureByIDorSlug(ctx context.Context, idOrSlug string) (*models.Feature, error) {
+	feature, ok := f.features[idOrSlug]
+	if !ok {
+		return nil, domainerrors.New(nil, domainerrors.ENOTFOUND, "feature not found")
+	}
+	return feature, nil
+}
+
+func TestSubjectUsageService_EntitlementOverrides(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 6, 18, 12, 0, 0, 0, time.UTC)
+	assignmentID, version := uuid.New(), uuid.New()
+	apiID, supportID, ssoID, exportID := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	planAPI := uuid.New()
+
+	planFeatures := &fakePlanFeatureStore{versions: map[uuid.UUID][]models.PlanFeature{
+		version: {
+			{Base: models.Base{ID: planAPI}, FeatureID: apiID, FeatureSlug: "api", MergeRule: models.QuotaMergeSum},
+			{Base: models.Base{ID: uuid.New()}, FeatureID: supportID, FeatureSlug: "support"},
+		},
+	}}
+	quotas := &fakeQuotaStore{quotas: map[uuid.UUID]*models.PlanFeatureQuota{
+		planAPI: {LimitValue: 1000, ResetPeriod: models.MeteredResetPeriodMonth, ActionAtLimit: models.MeteredActionAtLimitBlock},
+	}}
+	assignments := new(MockPlanAssignmentsStoreRepository)
+	assignments.On("ListActiveSubjectAssignments", mock.Anything, mock.Anything).Return([]models.PlanAssignment{
+		{Base: models.Base{ID: assignmentID}, PlanID: uuid.NewString(), PlanKind: models.PlanKindBase, PlanVersionID: version.String(), ValidFrom: now.AddDate(0, -1, 0)},
+	}, nil)
+	inactive := uuid.New()
+	overrides := &fakeEntitlementOverrideStore{active: []models.EntitlementOverride{
+		{Base: models.Base{ID: uuid.New(), CreatedBy: "sales", UpdatedBy: "support-lead"}, PlanAssignmentID: &assignmentID, FeatureID: apiID, FeatureSlug: "api", Effect: models.EntitlementOverrideGrant, LimitValue: ptr(int64(5000)), ActionAtLimit: models.MeteredActionAtLimitNone},
+		{Base: models.Base{ID: uuid.New(), CreatedBy: "sales"}, FeatureID: supportID, FeatureSlug: "support", Effect: models.EntitlementOverrideRevoke},
+		{Base: models.Base{ID: uuid.New(), CreatedBy: "sales"}, FeatureID: ssoID, FeatureSlug: "sso", FeatureType: models.FeatureTypeStatic, Effect: models.EntitlementOverrideGrant},
+		// Overrides attached to an assignment that is no longer active do not apply
+		{Base: models.Base{ID: uuid.New(), CreatedBy: "sales"}, PlanAssignmentID: &inactive, FeatureID: exportID, FeatureSlug: "export", Effect: models.EntitlementOverrideGrant},
+	}}
+	svc := NewSubjectUsageService(assignments, planFeatures, quotas, overrides, NewMeterService(&fakeOlap{}, &fakeMeterStore{}, nil, config.QueryCacheConfig{}))
+	svc.now = func() time.Time { return now }
+
+	entitlements, err := svc.GetSubjectEntitlements(ctx, models.SubjectTypeOrganization, "org-a")
+	require.NoError(t, err)
+	require.Len(t, entitlements.Overrides, 3)
+	require.Len(t, entitlements.Features, 2)
+
+	api := entitlements.Features[0]
+	assert.Equal(t, "api", api.FeatureSlug)
+	assert.Equal(t, int64(5000), api.Quota.LimitValue)
+	assert.Equal(t, models.MeteredResetPeriodMonth, api.Quota.ResetPeriod, "fields the override leaves unset keep the plan values")
+	assert.Equal(t, models.MeteredActionAtLimitNone, api.Quota.ActionAtLimit)
+	require.Len(t, api.Sources, 2)
+	assert.Equal(t, "support-lead", api.Sources[1].GrantedBy)
+	assert.Equal(t, int64(1000), quotas.quotas[planAPI].LimitValue)
+
+	sso := entitlements.Features[1]
+	assert.Equal(t, "sso", sso.FeatureSlug)
+	assert.Nil(t, sso.Quota)
+	assert.Equal(t, "sales", sso.Sources[0].GrantedBy)
+}
+
+func TestEntitlementOverrideService_CreateEntitlementOverride(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 6, 18, 12, 0, 0, 0, time.UTC)
+	apiID, ssoID, assignmentID := uuid.New(), uuid.New(), uuid.New()
+	features := &fakeFeatureStore{features: map[string]*models.Feature{
+		"api": {Base: models.Base{ID: apiID}, Slug: "api", Type: models.FeatureTypeMetered},
+		"sso": {Base: models.Base{ID: ssoID}, Slug: "sso", Type: models.FeatureTypeStatic},
+	}}
+	assignments := new(MockPlanAssignmentsStoreRepository)
+	assignments.On("GetAssignment", mock.Anything, assignmentID).Return(&models.PlanAssignment{Base: models.Base{ID: assignmentID}, OrganizationID: "org-a"}, nil)
+
+	grant := models.CreateEntitlementOverrideInput{
+		SubjectType: models.SubjectTypeOrganization,
+		SubjectID:   "org-a",
+		Feature:     "api",
+		Effect:      models.EntitlementOverrideGrant,
+		CreatedBy:   "sales",
+	}
+	past := now.Add(-time.Hour)
+	tests := map[string]func(arg *models.CreateEntitlementOverrideInput){
+		"invalid effect": func(arg *models.CreateEntitlementOverrideInput) { arg.Effect = "extend" },
+		"revoke with a limit": func(arg *models.CreateEntitlementOverrideInput) {
+			arg.Effect, arg.LimitValue = models.EntitlementOverrideRevoke, ptr(int64(10))
+		},
+		"limit on a static feature":     func(arg *models.CreateEntitlementOverrideInput) { arg.Feature, arg.LimitValue = "sso", ptr(int64(10)) },
+		"negative limit":                func(arg *models.CreateEntitlementOverrideInput) { arg.LimitValue = ptr(int64(-1)) },
+		"custom period without minutes": func(arg *models.CreateEntitlementOverrideInput) { arg.ResetPeriod = models.MeteredResetPeriodCustom },
+		"expiry in the past":            func(arg *models.CreateEntitlementOverrideInput) { arg.ExpiresAt = &past },
+		"assignment of another subject": func(arg *models.CreateEntitlementOverrideInput) {
+			arg.SubjectID, arg.PlanAssignmentID = "org-b", &assignmentID
+		},
+	}
+	for name, modify := range tests {
+		t.Run(name, func(t *testing.T) {
+			store := &fakeEntitlementOverrideStore{}
+			svc := NewEntitlementOverrideService(store, features, assignments)
+			svc.now = func() time.Time { return now }
+
+			arg := grant
+			modify(&arg)
+			_, err := svc.CreateEntitlementOverride(ctx, arg)
+			require.Error(t, err)
+			assert.Equal(t, string(domainerrors.EINVALID), domainerrors.GetErrorCode(err))
+			assert.Empty(t, store.created)
+		})
+	}
+
+	t.Run("resolves the feature and checks the assignment", func(t *testing.T) {
+		store := &fakeEntitlementOverrideStore{}
+		svc := NewEntitlementOverrideService(store, features, assignments)
+		svc.now = func() time.Time { return now }
+
+		arg := grant
+		arg.PlanAssignmentID = &assignmentID
+		arg.LimitValue = ptr(int64(5000))
+		override, err := svc.CreateEntitlementOverride(ctx, arg)
+		require.NoError(t, err)
+		assert.Equal(t, apiID, override.FeatureID)
+		require.Len(t, store.created, 1)
+		assert.Equal(t, "sales", store.created[0].CreatedBy)
+	})
+}
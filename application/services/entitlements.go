@@ -0,0 +1,303 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	domainerrors "github.com/redcardinal-io/metering/domain/errors"
+	"github.com/redcardinal-io/metering/domain/models"
+)
+
+// entitlement is a merged entitlement together with the start of the assignment its quota
+// periods are anchored to.
+type entitlement struct {
+	models.Entitlement
+	assignedAt time.Time
+}
+
+// resolvedEntitlements are the entitlements of a subject together with the assignments and
+// overrides they were resolved from.
+type resolvedEntitlements struct {
+	assignments  []models.PlanAssignment
+	overrides    []models.EntitlementOverride
+	entitlements []entitlement
+}
+
+// GetSubjectEntitlements returns the features the subject is entitled to right now, merged across
+// its base plan and the add-ons stacked on top of it, with the overrides of the subject applied.
+// Metered features with a quota report their current usage and band.
+func (s *SubjectUsageService) GetSubjectEntitlements(ctx context.Context, subjectType models.SubjectType, subjectID string) (*models.SubjectEntitlements, error) {
+	now := s.now().UTC()
+	resolved, err := s.subjectEntitlements(ctx, subjectType, subjectID, now, models.PlanFeatureListFilter{})
+	if err != nil {
+		return nil, err
+	}
+
+	result := &models.SubjectEntitlements{
+		SubjectType: subjectType,
+		SubjectID:   subjectID,
+		Assignments: resolved.assignments,
+		Overrides:   resolved.overrides,
+		Features:    make([]models.Entitlement, 0, len(resolved.entitlements)),
+	}
+	for _, e := range resolved.entitlements {
+		if e.Type == models.FeatureTypeMetered && e.Quota != nil {
+			usage, err := s.entitlementUsage(ctx, subjectType, subjectID, e, now)
+			if err != nil {
+				return nil, err
+			}
+			e.Used, e.Band, e.GraceEndsAt = &usage.used, usage.band, usage.graceEndsAt
+		}
+		result.Features = append(result.Features, e.Entitlement)
+	}
+	return result, nil
+}
+
+// subjectEntitlements merges the features of the plan versions assigned to the subject at now.
+// Only the latest base plan counts when several overlap, as assignments made before plans had a
+// kind may do. Entitlements are ordered as the plans granting them first, followed by those only
+// granted by overrides.
+func (s *SubjectUsageService) subjectEntitlements(ctx context.Context, subjectType models.SubjectType, subjectID string, now time.Time, filter models.PlanFeatureListFilter) (*resolvedEntitlements, error) {
+	if !models.IsValidSubjectType(subjectType) || subjectID == "" {
+		return nil, domainerrors.New(
+			fmt.Errorf("invalid subject %s %q", subjectType, subjectID),
+			domainerrors.EINVALID,
+			"invalid subject",
+			domainerrors.WithOperation("SubjectUsageService.subjectEntitlements"),
+		)
+	}
+
+	arg := models.GetActiveAssignmentInput{At: now}
+	if subjectType == models.SubjectTypeOrganization {
+		arg.OrganizationID = subjectID
+	} else {
+		arg.UserID = subjectID
+	}
+	active, err := s.assignments.ListActiveSubjectAssignments(ctx, arg)
+	if err != nil {
+		return nil, err
+	}
+
+	assignments := make([]models.PlanAssignment, 0, len(active))
+	for _, assignment := range active {
+		if assignment.PlanKind != models.PlanKindAddon && slices.ContainsFunc(assignments, func(a models.PlanAssignment) bool { return a.PlanKind != models.PlanKindAddon }) {
+			continue
+		}
+		assignments = append(assignments, assignment)
+	}
+	if len(assignments) == 0 {
+		return nil, domainerrors.New(
+			fmt.Errorf("%s %s has no active plan", subjectType, subjectID),
+			domainerrors.ENOTFOUND,
+			"subject has no active plan",
+			domainerrors.WithOperation("SubjectUsageService.subjectEntitlements"),
+		)
+	}
+
+	var entitlements []entitlement
+	for _, assignment := range assignments {
+		features, err := s.versionFeatures(ctx, assignment.PlanVersionID, filter)
+		if err != nil {
+			return nil, err
+		}
+		for _, feature := range features {
+			quota, err := s.quotas.GetPlanFeatureQuota(ctx, feature.ID)
+			if err != nil {
+				if domainerrors.GetErrorCode(err) != string(domainerrors.ENOTFOUND) {
+					return nil, err
+				}
+				quota = nil
+			}
+
+			i := slices.IndexFunc(entitlements, func(e entitlement) bool { return e.FeatureID == feature.FeatureID })
+			if i < 0 {
+				entitlements = append(entitlements, newEntitlement(assignment, feature, quota))
+				continue
+			}
+			entitlements[i].grant(assignment, feature, quota)
+		}
+	}
+
+	overrides, err := s.overrides.ListActiveEntitlementOverrides(ctx, subjectType, subjectID, now)
+	if err != nil {
+		return nil, err
+	}
+	resolved := &resolvedEntitlements{assignments: assignments, overrides: make([]models.EntitlementOverride, 0, len(overrides))}
+	for _, override := range overrides {
+		assignedAt := assignments[0].ValidFrom
+		if override.PlanAssignmentID != nil {
+			i := slices.IndexFunc(assignments, func(a models.PlanAssignment) bool { return a.ID == *override.PlanAssignmentID })
+			if i < 0 {
+				// The assignment the override is attached to is no longer active
+				continue
+			}
+			assignedAt = assignments[i].ValidFrom
+		}
+		if filter.FeatureType != "" && override.FeatureType != filter.FeatureType {
+			continue
+		}
+		resolved.overrides = append(resolved.overrides, override)
+
+		i := slices.IndexFunc(entitlements, func(e entitlement) bool { return e.FeatureID == override.FeatureID })
+		switch {
+		case override.Effect == models.EntitlementOverrideRevoke:
+			if i >= 0 {
+				entitlements = slices.Delete(entitlements, i, i+1)
+			}
+		case i < 0:
+			e := entitlement{
+				Entitlement: models.Entitlement{
+					FeatureID:   override.FeatureID,
+					FeatureSlug: override.FeatureSlug,
+					FeatureName: override.FeatureName,
+					Type:        override.FeatureType,
+					MeterSlugs:  override.MeterSlugs,
+					MergeRule:   override.MergeRule,
+				},
+				assignedAt: assignedAt,
+			}
+			e.override(override)
+			entitlements = append(entitlements, e)
+		default:
+			entitlements[i].override(override)
+		}
+	}
+	resolved.entitlements = entitlements
+	return resolved, nil
+}
+
+// newEntitlement starts an entitlement from the first plan granting the feature.
+func newEntitlement(assignment models.PlanAssignment, feature models.PlanFeature, quota *models.PlanFeatureQuota) entitlement {
+	e := entitlement{
+		Entitlement: models.Entitlement{
+			FeatureID:   feature.FeatureID,
+			FeatureSlug: feature.FeatureSlug,
+			FeatureName: feature.FeatureName,
+			Type:        feature.Type,
+			MeterSlugs:  feature.MeterSlugs,
+			MergeRule:   feature.MergeRule,
+		},
+		assignedAt: assignment.ValidFrom,
+	}
+	if e.MergeRule == "" {
+		e.MergeRule = models.QuotaMergeSum
+	}
+	if quota != nil {
+		merged := *quota
+		e.Quota = &merged
+	}
+	e.mergeConfig(feature.Config)
+	e.Sources = []models.EntitlementSource{entitlementSource(assignment, quota)}
+	return e
+}
+
+// grant merges another plan granting the feature into the entitlement. A plan granting the
+// feature without a quota lifts the limit altogether; otherwise limits are summed or maxed by the
+// merge rule of the feature. The soft limit, the overage allowance and the grace period follow
+// the larger limit when maxed. When summed, the soft limit keeps its distance below the limit,
+// the overage allowances add up as amounts and the longer grace period applies. A quota resetting
+// over another period than the entitlement cannot be combined with it: its source is marked with
+// the conflict and its limit left out. Assignments stacking such quotas are refused, so this only
+// happens to plans held before.
+func (e *entitlement) grant(assignment models.PlanAssignment, feature models.PlanFeature, quota *models.PlanFeatureQuota) {
+	unlimited := e.Quota == nil
+	e.Sources = append(e.Sources, entitlementSource(assignment, quota))
+	e.mergeConfig(feature.Config)
+
+	switch {
+	case unlimited:
+	case quota == nil:
+		e.Quota = nil
+	case !e.Quota.SamePeriod(quota):
+		e.Sources[len(e.Sources)-1].PeriodConflict = true
+	case e.MergeRule == models.QuotaMergeMax:
+		if quota.LimitValue > e.Quota.LimitValue {
+			e.Quota.LimitValue = quota.LimitValue
+			e.Quota.SoftLimitValue = quota.SoftLimitValue
+			e.Quota.OverageAllowance = quota.OverageAllowance
+			e.Quota.OverageAllowanceType = quota.OverageAllowanceType
+			e.Quota.GracePeriodMinutes = quota.GracePeriodMinutes
+		}
+	default:
+		allowance := e.Quota.AllowedUsage() - e.Quota.LimitValue + quota.AllowedUsage() - quota.LimitValue
+		e.Quota.LimitValue += quota.LimitValue
+		if e.Quota.SoftLimitValue != nil {
+			// The pointer may be shared with the quota of a plan
+			softLimit := *e.Quota.SoftLimitValue + quota.LimitValue
+			e.Quota.SoftLimitValue = &softLimit
+		}
+		if allowance > 0 {
+			e.Quota.OverageAllowance = &allowance
+			e.Quota.OverageAllowanceType = models.QuotaOverageAbsolute
+		}
+		if quota.GracePeriodMinutes != nil && (e.Quota.GracePeriodMinutes == nil || *quota.GracePeriodMinutes > *e.Quota.GracePeriodMinutes) {
+			e.Quota.GracePeriodMinutes = quota.GracePeriodMinutes
+		}
+	}
+}
+
+// override applies a grant override to the entitlement, replacing the quota fields it sets. A
+// grant setting a limit on an unlimited entitlement starts a quota that never resets, and a soft
+// limit of the plans that is not below the limit of the override is dropped.
+func (e *entitlement) override(override models.EntitlementOverride) {
+	if override.LimitValue != nil && e.Quota == nil {
+		e.Quota = &models.PlanFeatureQuota{
+			ResetPeriod:   models.MeteredResetPeriodNever,
+			ActionAtLimit: models.MeteredActionAtLimitNone,
+		}
+	}
+	if e.Quota != nil {
+		if override.LimitValue != nil {
+			e.Quota.LimitValue = *override.LimitValue
+			if e.Quota.SoftLimitValue != nil && *e.Quota.SoftLimitValue >= e.Quota.LimitValue {
+				e.Quota.SoftLimitValue = nil
+			}
+		}
+		if override.ResetPeriod != "" {
+			e.Quota.ResetPeriod = override.ResetPeriod
+			e.Quota.CustomPeriodMinutes = override.CustomPeriodMinutes
+		}
+		if override.ActionAtLimit != "" {
+			e.Quota.ActionAtLimit = override.ActionAtLimit
+		}
+	}
+
+	grantedBy := override.UpdatedBy
+	if grantedBy == "" {
+		grantedBy = override.CreatedBy
+	}
+	e.Sources = append(e.Sources, models.EntitlementSource{
+		OverrideID: override.ID.String(),
+		GrantedBy:  grantedBy,
+		Limit:      override.LimitValue,
+	})
+}
+
+// mergeConfig shallow-merges a plan feature config into the entitlement, later plans overriding
+// earlier ones key by key.
+func (e *entitlement) mergeConfig(config any) {
+	m, ok := config.(map[string]any)
+	if !ok || len(m) == 0 {
+		return
+	}
+	if e.Config == nil {
+		e.Config = make(map[string]any, len(m))
+	}
+	maps.Copy(e.Config, m)
+}
+
+func entitlementSource(assignment models.PlanAssignment, quota *models.PlanFeatureQuota) models.EntitlementSource {
+	source := models.EntitlementSource{
+		PlanID:        assignment.PlanID,
+		PlanVersionID: assignment.PlanVersionID,
+		PlanKind:      assignment.PlanKind,
+	}
+	if quota != nil {
+		limit := quota.LimitValue
+		source.Limit = &limit
+	}
+	return source
+}
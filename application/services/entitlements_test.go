@@ -0,0 +1,154 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/redcardinal-io/metering/domain/models"
+	"github.com/redcardinal-io/metering/domain/pkg/config"
+)
+
+func TestSubjectUsageService_GetSubjectEntitlements(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 6, 18, 12, 0, 0, 0, time.UTC)
+	basePlan, seatsPlan, supportPlan := uuid.New(), uuid.New(), uuid.New()
+	baseVersion, seatsVersion, supportVersion, legacyVersion := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	apiID, seatsID, supportID := uuid.New(), uuid.New(), uuid.New()
+	baseAPI, baseSeats, baseSupport, addonAPI, addonSeats, addonSupport := uuid.New(), uuid.New(), uuid.New(), uuid.New(), uuid.New(), uuid.New()
+
+	planFeatures := &fakePlanFeatureStore{versions: map[uuid.UUID][]models.PlanFeature{
+		baseVersion: {
+			{Base: models.Base{ID: baseAPI}, FeatureID: apiID, FeatureSlug: "api", MergeRule: models.QuotaMergeSum, Config: map[string]any{"region": "eu", "burst": 10}},
+			{Base: models.Base{ID: baseSeats}, FeatureID: seatsID, FeatureSlug: "seats", MergeRule: models.QuotaMergeMax},
+			{Base: models.Base{ID: baseSupport}, FeatureID: supportID, FeatureSlug: "support"},
+		},
+		seatsVersion: {
+			{Base: models.Base{ID: addonAPI}, FeatureID: apiID, FeatureSlug: "api", MergeRule: models.QuotaMergeSum, Config: map[string]any{"burst": 50}},
+			{Base: models.Base{ID: addonSeats}, FeatureID: seatsID, FeatureSlug: "seats", MergeRule: models.QuotaMergeMax},
+		},
+		supportVersion: {
+			{Base: models.Base{ID: addonSupport}, FeatureID: supportID, FeatureSlug: "support"},
+		},
+		legacyVersion: {
+			{Base: models.Base{ID: uuid.New()}, FeatureID: uuid.New(), FeatureSlug: "legacy"},
+		},
+	}}
+	quotas := &fakeQuotaStore{quotas: map[uuid.UUID]*models.PlanFeatureQuota{
+		baseAPI: {
+			LimitValue: 1000, ResetPeriod: models.MeteredResetPeriodMonth, ActionAtLimit: models.MeteredActionAtLimitBlock, SoftLimitValue: ptr(int64(800)),
+			OverageAllowance: ptr(int64(10)), OverageAllowanceType: models.QuotaOveragePercent, GracePeriodMinutes: ptr(int64(60)),
+		},
+		addonAPI: {
+			LimitValue: 500, ResetPeriod: models.MeteredResetPeriodMonth, ActionAtLimit: models.MeteredActionAtLimitNone,
+			OverageAllowance: ptr(int64(50)), OverageAllowanceType: models.QuotaOverageAbsolute, GracePeriodMinutes: ptr(int64(120)),
+		},
+		baseSeats:    {LimitValue: 5, ResetPeriod: models.MeteredResetPeriodNever, GracePeriodMinutes: ptr(int64(30))},
+		addonSeats:   {LimitValue: 20, ResetPeriod: models.MeteredResetPeriodNever, SoftLimitValue: ptr(int64(15)), OverageAllowance: ptr(int64(2)), OverageAllowanceType: models.QuotaOverageAbsolute},
+		addonSupport: {LimitValue: 3, ResetPeriod: models.MeteredResetPeriodMonth},
+	}}
+
+	assignments := new(MockPlanAssignmentsStoreRepository)
+	assignments.On("ListActiveSubjectAssignments", mock.Anything, models.GetActiveAssignmentInput{OrganizationID: "org-a", At: now}).Return([]models.PlanAssignment{
+		{PlanID: basePlan.String(), PlanKind: models.PlanKindBase, PlanVersionID: baseVersion.String(), ValidFrom: now.AddDate(0, -2, 0)},
+		// Only the latest of overlapping base plans counts
+		{PlanID: uuid.NewString(), PlanKind: models.PlanKindBase, PlanVersionID: legacyVersion.String(), ValidFrom: now.AddDate(-1, 0, 0)},
+		{PlanID: seatsPlan.String(), PlanKind: models.PlanKindAddon, PlanVersionID: seatsVersion.String(), ValidFrom: now.AddDate(0, -1, 0)},
+		{PlanID: supportPlan.String(), PlanKind: models.PlanKindAddon, PlanVersionID: supportVersion.String(), ValidFrom: now.AddDate(0, 0, -1)},
+	}, nil)
+	svc := NewSubjectUsageService(assignments, planFeatures, quotas, &fakeEntitlementOverrideStore{}, NewMeterService(&fakeOlap{}, &fakeMeterStore{}, nil, config.QueryCacheConfig{}))
+	svc.now = func() time.Time { return now }
+
+	entitlements, err := svc.GetSubjectEntitlements(ctx, models.SubjectTypeOrganization, "org-a")
+	require.NoError(t, err)
+	require.Len(t, entitlements.Assignments, 3)
+	require.Len(t, entitlements.Features, 3)
+
+	api := entitlements.Features[0]
+	assert.Equal(t, "api", api.FeatureSlug)
+	assert.Equal(t, int64(1500), api.Quota.LimitValue)
+	assert.Equal(t, models.MeteredResetPeriodMonth, api.Quota.ResetPeriod, "the base plan sets the reset period")
+	assert.Equal(t, models.MeteredActionAtLimitBlock, api.Quota.ActionAtLimit)
+	assert.Equal(t, map[string]any{"region": "eu", "burst": 50}, api.Config)
+	require.Len(t, api.Sources, 2)
+	assert.Equal(t, models.PlanKindAddon, api.Sources[1].PlanKind)
+	assert.Equal(t, int64(500), *api.Sources[1].Limit)
+	assert.Equal(t, int64(1300), *api.Quota.SoftLimitValue, "summed limits keep the soft limit as far below the limit")
+	assert.Equal(t, int64(150), *api.Quota.OverageAllowance, "summed allowances add up as amounts")
+	assert.Equal(t, models.QuotaOverageAbsolute, api.Quota.OverageAllowanceType)
+	assert.Equal(t, int64(1650), api.Quota.AllowedUsage())
+	assert.Equal(t, int64(120), *api.Quota.GracePeriodMinutes, "the longer grace period applies")
+	// Merging leaves the quotas of the plans untouched
+	assert.Equal(t, int64(1000), quotas.quotas[baseAPI].LimitValue)
+	assert.Equal(t, int64(800), *quotas.quotas[baseAPI].SoftLimitValue)
+
+	seats := entitlements.Features[1]
+	assert.Equal(t, models.QuotaMergeMax, seats.MergeRule)
+	assert.Equal(t, int64(20), seats.Quota.LimitValue)
+	assert.Equal(t, int64(15), *seats.Quota.SoftLimitValue, "the soft limit follows the larger limit")
+	assert.Equal(t, int64(22), seats.Quota.AllowedUsage(), "the allowance follows the larger limit")
+	assert.Nil(t, seats.Quota.GracePeriodMinutes, "the grace period follows the larger limit")
+
+	support := entitlements.Features[2]
+	assert.Nil(t, support.Quota, "a plan granting the feature without a quota lifts the limit")
+	assert.Equal(t, models.QuotaMergeSum, support.MergeRule)
+	assert.Nil(t, support.Sources[0].Limit)
+	assert.Equal(t, int64(3), *support.Sources[1].Limit)
+}
+
+func TestEntitlement_GrantOtherPeriod(t *testing.T) {
+	base := models.PlanAssignment{PlanKind: models.PlanKindBase}
+	addon := models.PlanAssignment{PlanKind: models.PlanKindAddon}
+	monthly := &models.PlanFeatureQuota{LimitValue: 1000, ResetPeriod: models.MeteredResetPeriodMonth}
+	hourly := &models.PlanFeatureQuota{LimitValue: 1000, ResetPeriod: models.MeteredResetPeriodCustom, CustomPeriodMinutes: ptr(int64(60))}
+
+	tests := []struct {
+		name  string
+		base  *models.PlanFeatureQuota
+		addon *models.PlanFeatureQuota
+	}{
+		{name: "Another reset period", base: monthly, addon: &models.PlanFeatureQuota{LimitValue: 100, ResetPeriod: models.MeteredResetPeriodDay}},
+		{name: "A larger limit over another period", base: monthly, addon: &models.PlanFeatureQuota{LimitValue: 5000, ResetPeriod: models.MeteredResetPeriodDay}},
+		{name: "Another custom period", base: hourly, addon: &models.PlanFeatureQuota{LimitValue: 100, ResetPeriod: models.MeteredResetPeriodCustom, CustomPeriodMinutes: ptr(int64(30))}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, rule := range []models.QuotaMergeRule{models.QuotaMergeSum, models.QuotaMergeMax} {
+				feature := models.PlanFeature{FeatureSlug: "api", MergeRule: rule}
+				e := newEntitlement(base, feature, tt.base)
+				e.grant(addon, feature, tt.addon)
+
+				assert.Equal(t, int64(1000), e.Quota.LimitValue, "limits over other periods are not combined")
+				assert.Equal(t, tt.base.ResetPeriod, e.Quota.ResetPeriod)
+				require.Len(t, e.Sources, 2)
+				assert.False(t, e.Sources[0].PeriodConflict)
+				assert.True(t, e.Sources[1].PeriodConflict, "the conflicting plan is flagged")
+			}
+		})
+	}
+}
+
+func TestEntitlement_OverrideBelowSoftLimit(t *testing.T) {
+	base := models.PlanAssignment{PlanKind: models.PlanKindBase}
+	feature := models.PlanFeature{FeatureSlug: "api", MergeRule: models.QuotaMergeSum}
+	quota := &models.PlanFeatureQuota{
+		LimitValue: 1000, ResetPeriod: models.MeteredResetPeriodMonth, SoftLimitValue: ptr(int64(800)),
+		OverageAllowance: ptr(int64(10)), OverageAllowanceType: models.QuotaOveragePercent,
+	}
+
+	e := newEntitlement(base, feature, quota)
+	e.override(models.EntitlementOverride{Base: models.Base{ID: uuid.New()}, Effect: models.EntitlementOverrideGrant, LimitValue: ptr(int64(500))})
+	assert.Equal(t, int64(500), e.Quota.LimitValue)
+	assert.Nil(t, e.Quota.SoftLimitValue, "a soft limit past the limit of the override is dropped")
+	assert.Equal(t, models.QuotaBandOverage, e.Quota.Band(520, nil, time.Now()))
+	assert.Equal(t, int64(800), *quota.SoftLimitValue, "the quota of the plan is untouched")
+
+	e = newEntitlement(base, feature, quota)
+	e.override(models.EntitlementOverride{Base: models.Base{ID: uuid.New()}, Effect: models.EntitlementOverrideGrant, LimitValue: ptr(int64(900))})
+	assert.Equal(t, int64(800), *e.Quota.SoftLimitValue, "a soft limit below the limit of the override is kept")
+}
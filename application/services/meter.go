@@ -2,26 +2,76 @@ package services
 
 import (
 	"context"
+	"fmt"
+	"strings"
+	"time"
 
 	"github.com/redcardinal-io/metering/application/repositories"
+	domainerrors "github.com/redcardinal-io/metering/domain/errors"
 	"github.com/redcardinal-io/metering/domain/models"
+	"github.com/redcardinal-io/metering/domain/pkg/config"
 	"github.com/redcardinal-io/metering/domain/pkg/pagination"
 )
 
 type MeterService struct {
-	olap  repositories.OlapRepository
-	store repositories.MeterStoreRepository
+	olap        repositories.OlapRepository
+	store       repositories.MeterStoreRepository
+	cache       repositories.QueryCacheRepository
+	cacheConfig config.QueryCacheConfig
+	notifiers   []repositories.NotifierRepository
+	now         func() time.Time
 }
 
 // NewMeterService creates a new MeterService with the provided OLAP and meter store repositories.
-func NewMeterService(olap repositories.OlapRepository, store repositories.MeterStoreRepository) *MeterService {
+// A nil cache disables query result caching. Notifiers are told about meters that become active.
+func NewMeterService(olap repositories.OlapRepository, store repositories.MeterStoreRepository, cache repositories.QueryCacheRepository, cacheConfig config.QueryCacheConfig, notifiers ...repositories.NotifierRepository) *MeterService {
 	return &MeterService{
-		olap:  olap,
-		store: store,
+		olap:        olap,
+		store:       store,
+		cache:       cache,
+		cacheConfig: cacheConfig,
+		notifiers:   notifiers,
+		now:         time.Now,
 	}
 }
 
+// CreateMeter stores a meter as pending, creates its OLAP view and activates it. A meter whose view
+// cannot be created is kept as failed so the error is visible through the API; it can then be
+// retried or deleted. Meters left pending by an interrupted request are picked up by the MeterReconciler.
 func (s *MeterService) CreateMeter(ctx context.Context, arg models.CreateMeterInput) (*models.Meter, error) {
+	var m *models.Meter
+	var err error
+	if arg.Type == models.MeterTypeDerived {
+		m, err = s.createDerivedMeter(ctx, arg)
+	} else {
+		m, err = s.createStandardMeter(ctx, arg)
+	}
+	if err != nil {
+		return nil, err
+	}
+	notify(ctx, s.notifiers, models.NotificationMeterCreated, m)
+	return m, nil
+}
+
+// createStandardMeter stores a meter reading events and creates its OLAP view.
+func (s *MeterService) createStandardMeter(ctx context.Context, arg models.CreateMeterInput) (*models.Meter, error) {
+	if err := normalizeEventTypes(&arg); err != nil {
+		return nil, domainerrors.New(
+			err,
+			domainerrors.EINVALID,
+			"invalid meter event types",
+			domainerrors.WithOperation("MeterService.CreateMeter"),
+		)
+	}
+	if err := validateMeterFilter(arg.Filter, "MeterService.CreateMeter"); err != nil {
+		return nil, err
+	}
+	if arg.Granularity == "" {
+		arg.Granularity = models.DefaultGranularity
+	}
+	arg.Rollups = models.RollupGranularities(arg.Granularity)
+	arg.Status = models.MeterStatusPending
+
 	// Store the meter in the database
 	m, err := s.store.CreateMeter(ctx, arg)
 	if err != nil {
@@ -31,14 +81,16 @@ func (s *MeterService) CreateMeter(ctx context.Context, arg models.CreateMeterIn
 	// Call the repository to create the meter
 	err = s.olap.CreateMeter(ctx, arg)
 	if err != nil {
-		// delete the meter from the database if OLAP creation fails
-		if deleteErr := s.store.DeleteMeterByIDorSlug(ctx, m.Slug); deleteErr != nil {
-			return nil, deleteErr
+		if _, statusErr := s.store.UpdateMeterStatus(ctx, m.ID, models.UpdateMeterStatusInput{
+			Status: models.MeterStatusFailed,
+			Error:  statusError(err),
+		}); statusErr != nil {
+			return nil, statusErr
 		}
 		return nil, err
 	}
 
-	return m, nil
+	return s.store.UpdateMeterStatus(ctx, m.ID, models.UpdateMeterStatusInput{Status: models.MeterStatusActive})
 }
 
 func (s *MeterService) GetMeterIDorSlug(ctx context.Context, IDorSlug string) (*models.Meter, error) {
@@ -55,28 +107,255 @@ func (s *MeterService) QueryMeter(ctx context.Context, arg models.QueryMeterPara
 	if err != nil {
 		return nil, err
 	}
-	result, err := s.olap.QueryMeter(ctx, arg, &m.Aggregation)
-	return result, err
+	if err := validateMeterActive(m, "MeterService.QueryMeter"); err != nil {
+		return nil, err
+	}
+	if err := validateQueryColumns(m, arg); err != nil {
+		return nil, err
+	}
+	if err := validateFill(arg); err != nil {
+		return nil, err
+	}
+	if err := validateQueryWindow(m, arg); err != nil {
+		return nil, err
+	}
+	if err := validateQueryMode(m, arg); err != nil {
+		return nil, err
+	}
+
+	result, err := s.runQuery(ctx, m, arg)
+	if err != nil {
+		return nil, err
+	}
+	if arg.Compare != "" {
+		if err := s.compareWithPreviousPeriod(ctx, m, arg, result); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// runQuery queries a validated meter and applies fill and cumulative mode to the result.
+func (s *MeterService) runQuery(ctx context.Context, m *models.Meter, arg models.QueryMeterParams) (*models.QueryMeterResult, error) {
+	var result *models.QueryMeterResult
+	var err error
+	if m.IsDerived() {
+		result, err = s.queryDerivedMeter(ctx, m, arg)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		if err := resolveQueryPage(&arg); err != nil {
+			return nil, err
+		}
+		result, err = s.queryStandardMeter(ctx, m, arg)
+		if err != nil {
+			return nil, err
+		}
+		setNextCursor(result, arg)
+	}
+
+	if arg.Fill != "" {
+		fillWindows(result, arg)
+	}
+	if arg.Cumulative {
+		accumulateWindows(result, arg)
+	}
+	return result, nil
+}
+
+// resolveQueryPage validates ordering and pagination and turns a cursor into an offset.
+func resolveQueryPage(arg *models.QueryMeterParams) error {
+	if arg.OrderBy != nil {
+		validField := arg.OrderBy.Field == models.OrderByValue || arg.OrderBy.Field == "windowstart"
+		for _, column := range arg.GroupBy {
+			if arg.OrderBy.Field == column {
+				validField = true
+			}
+		}
+		if !validField {
+			return domainerrors.New(
+				fmt.Errorf("order_by field must be value, windowstart or a group_by property: %s", arg.OrderBy.Field),
+				domainerrors.EINVALID,
+				"invalid meter query",
+				domainerrors.WithOperation("MeterService.QueryMeter"),
+			)
+		}
+		if arg.OrderBy.Direction != "" && arg.OrderBy.Direction != models.SortAsc && arg.OrderBy.Direction != models.SortDesc {
+			return domainerrors.New(
+				fmt.Errorf("invalid order_by direction: %s", arg.OrderBy.Direction),
+				domainerrors.EINVALID,
+				"invalid meter query",
+				domainerrors.WithOperation("MeterService.QueryMeter"),
+			)
+		}
+	}
+
+	if arg.Cursor != "" {
+		if arg.Offset != nil {
+			return domainerrors.New(
+				fmt.Errorf("cursor and offset cannot be used together"),
+				domainerrors.EINVALID,
+				"invalid meter query",
+				domainerrors.WithOperation("MeterService.QueryMeter"),
+			)
+		}
+		offset, err := models.DecodeQueryCursor(arg.Cursor)
+		if err != nil {
+			return domainerrors.New(
+				err,
+				domainerrors.EINVALID,
+				"invalid meter query cursor",
+				domainerrors.WithOperation("MeterService.QueryMeter"),
+			)
+		}
+		arg.Offset = &offset
+	}
+
+	if (arg.Offset != nil || arg.IncludeOthers) && arg.Limit == nil {
+		return domainerrors.New(
+			fmt.Errorf("limit is required when using offset, cursor or include_others"),
+			domainerrors.EINVALID,
+			"invalid meter query",
+			domainerrors.WithOperation("MeterService.QueryMeter"),
+		)
+	}
+	if arg.IncludeOthers && len(arg.GroupBy) == 0 {
+		return domainerrors.New(
+			fmt.Errorf("include_others requires group_by"),
+			domainerrors.EINVALID,
+			"invalid meter query",
+			domainerrors.WithOperation("MeterService.QueryMeter"),
+		)
+	}
+	return nil
+}
+
+// setNextCursor points the result at the following page when the current page is full.
+func setNextCursor(result *models.QueryMeterResult, arg models.QueryMeterParams) {
+	if arg.Limit == nil {
+		return
+	}
+
+	pageSize := 0
+	if len(arg.GroupBy) == 0 {
+		pageSize = len(result.Data)
+	} else {
+		groups := make(map[string]struct{})
+		for _, row := range result.Data {
+			if row.Others {
+				continue
+			}
+			groups[groupKey(row, arg.GroupBy)] = struct{}{}
+		}
+		pageSize = len(groups)
+	}
+
+	if pageSize >= *arg.Limit {
+		offset := 0
+		if arg.Offset != nil {
+			offset = *arg.Offset
+		}
+		result.NextCursor = models.EncodeQueryCursor(offset + *arg.Limit)
+	}
 }
 
-// TODO: implement recovery if store deletion fails
+// validateQueryColumns rejects group-by and filter columns that are not declared on the meter.
+func validateQueryColumns(m *models.Meter, arg models.QueryMeterParams) error {
+	columns := meterQueryColumns(m)
+	known := make(map[string]struct{}, len(columns))
+	for _, c := range columns {
+		known[c] = struct{}{}
+	}
+
+	for _, column := range arg.GroupBy {
+		if _, ok := known[column]; !ok {
+			return domainerrors.New(
+				fmt.Errorf("unknown group_by property: %s", column),
+				domainerrors.EINVALID,
+				"invalid meter query",
+				domainerrors.WithOperation("MeterService.QueryMeter"),
+			)
+		}
+	}
+	for column := range arg.FilterGroupBy {
+		if _, ok := known[column]; !ok {
+			return domainerrors.New(
+				fmt.Errorf("unknown filter_group_by property: %s", column),
+				domainerrors.EINVALID,
+				"invalid meter query",
+				domainerrors.WithOperation("MeterService.QueryMeter"),
+			)
+		}
+	}
+	if arg.Filter != nil {
+		if err := arg.Filter.Validate(columns); err != nil {
+			return domainerrors.New(
+				err,
+				domainerrors.EINVALID,
+				"invalid meter query filter",
+				domainerrors.WithOperation("MeterService.QueryMeter"),
+			)
+		}
+	}
+	return nil
+}
+
+// validateQueryWindow rejects windows finer than the granularity the meter view is stored in.
+func validateQueryWindow(m *models.Meter, arg models.QueryMeterParams) error {
+	if arg.WindowSize == nil || m.Granularity == "" || m.Granularity.SupportsWindow(*arg.WindowSize) {
+		return nil
+	}
+	return domainerrors.New(
+		fmt.Errorf("window size %s is finer than the meter granularity %s", *arg.WindowSize, m.Granularity),
+		domainerrors.EINVALID,
+		"invalid meter query",
+		domainerrors.WithOperation("MeterService.QueryMeter"),
+		domainerrors.WithData("granularity", m.Granularity),
+	)
+}
+
+// meterQueryColumns returns the columns of a meter view that can be grouped or filtered on.
+func meterQueryColumns(m *models.Meter) []string {
+	columns := make([]string, 0, len(m.Properties)+2)
+	columns = append(columns, "organization", "user")
+	return append(columns, m.Properties...)
+}
+
+// DeleteMeter marks a meter as deleting, then drops its OLAP view and its row. If either step fails
+// the meter stays deleting with the error recorded and the MeterReconciler finishes the removal.
 func (s *MeterService) DeleteMeter(ctx context.Context, iDorSlug string) error {
 	meter, err := s.store.GetMeterByIDorSlug(ctx, iDorSlug)
 	if err != nil {
 		return err
 	}
 
-	// Call the OLAP repository to delete the meter
-	err = s.olap.DeleteMeter(ctx, meter.Slug)
+	dependents, err := s.store.ListMeterSlugsReferencing(ctx, meter.Slug)
 	if err != nil {
 		return err
 	}
+	if len(dependents) > 0 {
+		return domainerrors.New(
+			fmt.Errorf("meter %s is referenced by derived meters %v", meter.Slug, dependents),
+			domainerrors.ECONFLICT,
+			"meter is referenced by derived meters",
+			domainerrors.WithOperation("MeterService.DeleteMeter"),
+			domainerrors.WithData("derived_meters", dependents),
+		)
+	}
 
-	err = s.store.DeleteMeterByIDorSlug(ctx, iDorSlug)
-	if err != nil {
-		return err
+	if meter.Status != models.MeterStatusDeleting {
+		meter, err = s.store.UpdateMeterStatus(ctx, meter.ID, models.UpdateMeterStatusInput{Status: models.MeterStatusDeleting})
+		if err != nil {
+			return err
+		}
 	}
+	s.InvalidateQueryCache(ctx, meter.Slug)
 
+	if err := s.removeMeter(ctx, meter); err != nil {
+		s.recordMeterFailure(ctx, meter, models.MeterStatusDeleting, err, 0)
+		return err
+	}
 	return nil
 }
 
@@ -86,6 +365,7 @@ func (s *MeterService) UpdateMeter(ctx context.Context, IDorSlug string, arg mod
 	if err != nil {
 		return nil, err
 	}
+	s.InvalidateQueryCache(ctx, m.Slug)
 
 	return m, nil
 }
@@ -99,3 +379,12 @@ func (s *MeterService) ListMeters(ctx context.Context, pagination pagination.Pag
 
 	return m, nil
 }
+
+// groupKey identifies the group of a result row by its group by values.
+func groupKey(row models.QueryMeterRow, groupBy []string) string {
+	key := make([]string, 0, len(groupBy))
+	for _, column := range groupBy {
+		key = append(key, row.GroupBy[column])
+	}
+	return strings.Join(key, "\x00")
+}
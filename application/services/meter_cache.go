@@ -0,0 +1,162 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"slices"
+	"time"
+
+	"github.com/redcardinal-io/metering/domain/models"
+	"github.com/redcardinal-io/metering/domain/pkg/constants"
+)
+
+// queryStandardMeter runs a query against the OLAP store, serving windows that are closed
+// and settled from the query cache. Only the still open part of a range is re-queried.
+func (s *MeterService) queryStandardMeter(ctx context.Context, m *models.Meter, arg models.QueryMeterParams) (*models.QueryMeterResult, error) {
+	if s.cache == nil || s.cacheConfig.MaxEntries <= 0 || (arg.WindowTimeZone != nil && *arg.WindowTimeZone != "UTC") {
+		return s.olap.QueryMeter(ctx, arg, m)
+	}
+
+	settled := s.now().Add(-s.cacheConfig.SettleDelay)
+	if arg.To != nil && !queryEnd(arg).After(settled) {
+		return s.cachedQuery(ctx, m, arg)
+	}
+
+	// Ranked pages and the others bucket depend on the whole range, so they cannot be split
+	if arg.WindowSize == nil || arg.From == nil || arg.Limit != nil || arg.IncludeOthers {
+		return s.olap.QueryMeter(ctx, arg, m)
+	}
+	split := settled.UTC().Truncate(arg.WindowSize.Duration())
+	if !arg.From.Before(split) {
+		return s.olap.QueryMeter(ctx, arg, m)
+	}
+
+	closedArg, openArg := arg, arg
+	closedArg.To, openArg.From = &split, &split
+	closed, err := s.cachedQuery(ctx, m, closedArg)
+	if err != nil {
+		return nil, err
+	}
+	open, err := s.olap.QueryMeter(ctx, openArg, m)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &models.QueryMeterResult{
+		WindowStart: closed.WindowStart,
+		WindowEnd:   open.WindowEnd,
+		WindowSize:  arg.WindowSize,
+		Data:        append(closed.Data, open.Data...),
+	}
+	if len(closed.Data) == 0 {
+		result.WindowStart = arg.From
+	}
+	if arg.OrderBy != nil {
+		sortQueryRows(result.Data, arg.OrderBy)
+	}
+	return result, nil
+}
+
+// cachedQuery serves a query over a closed range from the cache, populating it on a miss unless
+// the range reaches late events that may still be on their way into the OLAP store.
+func (s *MeterService) cachedQuery(ctx context.Context, m *models.Meter, arg models.QueryMeterParams) (*models.QueryMeterResult, error) {
+	key, err := queryCacheKey(ctx, m, arg)
+	if err != nil {
+		return s.olap.QueryMeter(ctx, arg, m)
+	}
+	if result, ok := s.cache.Get(ctx, key); ok {
+		return result, nil
+	}
+
+	result, err := s.olap.QueryMeter(ctx, arg, m)
+	if err != nil {
+		return nil, err
+	}
+	// Late events may not be ingested yet, so the result could miss them
+	if since, held := s.cache.MeterHold(ctx, key.TenantSlug, key.MeterSlug); held && queryEnd(arg).After(since) {
+		return result, nil
+	}
+	s.cache.Set(ctx, key, result, s.cacheConfig.TTL)
+	return result, nil
+}
+
+// InvalidateQueryCache drops cached results of a meter, for instance after its usage was adjusted.
+func (s *MeterService) InvalidateQueryCache(ctx context.Context, meterSlug string) {
+	if s.cache == nil {
+		return
+	}
+	tenantSlug, _ := ctx.Value(constants.TenantSlugKey).(string)
+	s.cache.InvalidateMeter(ctx, tenantSlug, meterSlug)
+}
+
+// queryEnd returns the end of the last window a query covers.
+func queryEnd(arg models.QueryMeterParams) time.Time {
+	if arg.WindowSize == nil {
+		return *arg.To
+	}
+	from := *arg.To
+	if arg.From != nil {
+		from = *arg.From
+	}
+	_, to := alignWindowRange(*arg.WindowSize, from, *arg.To)
+	return to
+}
+
+// queryCacheKey identifies a query by tenant, meter version and the normalized query parameters.
+func queryCacheKey(ctx context.Context, m *models.Meter, arg models.QueryMeterParams) (models.QueryCacheKey, error) {
+	tenantSlug, _ := ctx.Value(constants.TenantSlugKey).(string)
+
+	filterGroupBy := make(map[string][]string, len(arg.FilterGroupBy))
+	for column, values := range arg.FilterGroupBy {
+		sorted := slices.Clone(values)
+		slices.Sort(sorted)
+		filterGroupBy[column] = sorted
+	}
+	utc := func(t *time.Time) *time.Time {
+		if t == nil {
+			return nil
+		}
+		u := t.UTC()
+		return &u
+	}
+
+	normalized, err := json.Marshal(struct {
+		Version       time.Time                 `json:"version"`
+		Aggregation   models.AggregationEnum    `json:"aggregation"`
+		FilterGroupBy map[string][]string       `json:"filter_group_by"`
+		Filter        *models.FilterExpression  `json:"filter"`
+		From          *time.Time                `json:"from"`
+		To            *time.Time                `json:"to"`
+		GroupBy       []string                  `json:"group_by"`
+		WindowSize    *models.WindowSize        `json:"window_size"`
+		OrderBy       *models.QueryMeterOrderBy `json:"order_by"`
+		Limit         *int                      `json:"limit"`
+		Offset        *int                      `json:"offset"`
+		IncludeOthers bool                      `json:"include_others"`
+	}{
+		Version:       m.UpdatedAt.UTC(),
+		Aggregation:   m.Aggregation,
+		FilterGroupBy: filterGroupBy,
+		Filter:        arg.Filter,
+		From:          utc(arg.From),
+		To:            utc(arg.To),
+		GroupBy:       arg.GroupBy,
+		WindowSize:    arg.WindowSize,
+		OrderBy:       arg.OrderBy,
+		Limit:         arg.Limit,
+		Offset:        arg.Offset,
+		IncludeOthers: arg.IncludeOthers,
+	})
+	if err != nil {
+		return models.QueryCacheKey{}, err
+	}
+
+	digest := sha256.Sum256(normalized)
+	return models.QueryCacheKey{
+		TenantSlug: tenantSlug,
+		MeterSlug:  m.Slug,
+		Digest:     hex.EncodeToString(digest[:]),
+	}, nil
+}
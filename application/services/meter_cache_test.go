@@ -0,0 +1,141 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/redcardinal-io/metering/domain/models"
+	"github.com/redcardinal-io/metering/domain/pkg/config"
+	"github.com/redcardinal-io/metering/domain/pkg/constants"
+	"github.com/redcardinal-io/metering/infrastructure/cache"
+)
+
+// recordingOlap records the time range of every query it serves
+type recordingOlap struct {
+	fakeOlap
+	ranges [][2]time.Time
+}
+
+func (r *recordingOlap) QueryMeter(ctx context.Context, arg models.QueryMeterParams, meter *models.Meter) (*models.QueryMeterResult, error) {
+	r.ranges = append(r.ranges, [2]time.Time{*arg.From, *arg.To})
+	return &models.QueryMeterResult{Data: []models.QueryMeterRow{
+		{WindowStart: *arg.From, WindowEnd: arg.From.Add(time.Hour), Value: 1},
+	}}, nil
+}
+
+func TestMeterService_QueryMeterCache(t *testing.T) {
+	ctx := context.WithValue(context.Background(), constants.TenantSlugKey, "acme")
+	now := time.Date(2025, 6, 2, 12, 30, 0, 0, time.UTC)
+	windowSize := models.WindowSizeHour
+
+	store := &fakeMeterStore{meters: map[string]*models.Meter{
+		"requests": {Slug: "requests", Type: models.MeterTypeStandard, Status: models.MeterStatusActive, Aggregation: models.AggregationCount},
+	}}
+	newService := func() (*MeterService, *recordingOlap) {
+		olap := &recordingOlap{}
+		svc := NewMeterService(olap, store, cache.NewInMemoryQueryCache(100), config.QueryCacheConfig{
+			MaxEntries:  100,
+			TTL:         24 * time.Hour,
+			SettleDelay: 5 * time.Minute,
+		})
+		svc.now = func() time.Time { return now }
+		return svc, olap
+	}
+	query := func(svc *MeterService, from, to time.Time) (*models.QueryMeterResult, error) {
+		return svc.QueryMeter(ctx, models.QueryMeterParams{
+			MeterSlug:  "requests",
+			WindowSize: &windowSize,
+			From:       &from,
+			To:         &to,
+		})
+	}
+
+	t.Run("Caches closed ranges", func(t *testing.T) {
+		svc, olap := newService()
+		from, to := now.Add(-48*time.Hour), now.Add(-24*time.Hour)
+
+		for i := 0; i < 2; i++ {
+			_, err := query(svc, from, to)
+			require.NoError(t, err)
+		}
+		assert.Len(t, olap.ranges, 1)
+	})
+
+	t.Run("Re-queries only the open window", func(t *testing.T) {
+		svc, olap := newService()
+		from, to := now.Add(-6*time.Hour), now.Add(time.Hour)
+		split := time.Date(2025, 6, 2, 12, 0, 0, 0, time.UTC)
+
+		result, err := query(svc, from, to)
+		require.NoError(t, err)
+		assert.Len(t, result.Data, 2)
+		require.Len(t, olap.ranges, 2)
+		assert.Equal(t, split, olap.ranges[0][1])
+		assert.Equal(t, split, olap.ranges[1][0])
+
+		_, err = query(svc, from, to)
+		require.NoError(t, err)
+		require.Len(t, olap.ranges, 3)
+		assert.Equal(t, split, olap.ranges[2][0])
+	})
+
+	t.Run("Invalidation drops cached results", func(t *testing.T) {
+		svc, olap := newService()
+		from, to := now.Add(-48*time.Hour), now.Add(-24*time.Hour)
+
+		_, err := query(svc, from, to)
+		require.NoError(t, err)
+		svc.InvalidateQueryCache(ctx, "requests")
+		_, err = query(svc, from, to)
+		require.NoError(t, err)
+		assert.Len(t, olap.ranges, 2)
+	})
+	t.Run("Late events are not cached before they are ingested", func(t *testing.T) {
+		queryCache := cache.NewInMemoryQueryCache(100)
+		cacheConfig := config.QueryCacheConfig{MaxEntries: 100, TTL: 24 * time.Hour, SettleDelay: 5 * time.Minute, IngestDelay: 2 * time.Minute}
+		olap := &fakeOlap{results: map[string]*models.QueryMeterResult{
+			"requests": {Data: []models.QueryMeterRow{{Value: 10}}},
+		}}
+		svc := NewMeterService(olap, store, queryCache, cacheConfig)
+		svc.now = func() time.Time { return now }
+
+		producer := new(MockProducerRepository)
+		producer.On("PublishEvents", "events", mock.Anything).Return(nil)
+		meters := new(MockMeterStoreRepository)
+		meters.On("ListMetersByEventTypes", mock.Anything, mock.Anything).
+			Return([]*models.Meter{{Slug: "requests", EventType: "request"}}, nil)
+		producerSvc := NewProducerService(producer, meters, queryCache, cacheConfig)
+
+		from, to := now.Add(-48*time.Hour), now.Add(-24*time.Hour)
+		_, err := query(svc, from, to)
+		require.NoError(t, err)
+
+		_, err = producerSvc.PublishEvents(ctx, "events", &models.EventBatch{Events: []*models.Event{
+			{ID: "late", Type: "request", Source: "test", Organization: "org", User: "user", Timestamp: now.Add(-36 * time.Hour).Format(time.RFC3339), Properties: "{}"},
+		}}, false)
+		require.NoError(t, err)
+
+		// Queried before the event is ingested, the stale total must not be cached again
+		result, err := query(svc, from, to)
+		require.NoError(t, err)
+		assert.Equal(t, float64(10), result.Data[0].Value)
+
+		olap.results["requests"] = &models.QueryMeterResult{Data: []models.QueryMeterRow{{Value: 11}}}
+		result, err = query(svc, from, to)
+		require.NoError(t, err)
+		assert.Equal(t, float64(11), result.Data[0].Value)
+
+		// Ranges ending before the late event are unaffected by the hold
+		earlier, err := query(svc, from, now.Add(-40*time.Hour))
+		require.NoError(t, err)
+		olap.results["requests"] = &models.QueryMeterResult{Data: []models.QueryMeterRow{{Value: 12}}}
+		again, err := query(svc, from, now.Add(-40*time.Hour))
+		require.NoError(t, err)
+		assert.Equal(t, earlier.Data[0].Value, again.Data[0].Value)
+	})
+}
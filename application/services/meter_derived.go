@@ -0,0 +1,223 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"time"
+
+	domainerrors "github.com/redcardinal-io/metering/domain/errors"
+	"github.com/redcardinal-io/metering/domain/models"
+	"github.com/redcardinal-io/metering/domain/pkg/formula"
+)
+
+// createDerivedMeter validates the formula of a derived meter against the meters it references
+// and stores it. Derived meters expose the properties shared by all of their source meters
+// and take the coarsest granularity among them.
+func (s *MeterService) createDerivedMeter(ctx context.Context, arg models.CreateMeterInput) (*models.Meter, error) {
+	if arg.Filter != nil {
+		return nil, domainerrors.New(
+			fmt.Errorf("derived meters cannot filter events"),
+			domainerrors.EINVALID,
+			"invalid meter filter",
+			domainerrors.WithOperation("MeterService.CreateMeter"),
+		)
+	}
+	expr, err := formula.Parse(arg.Formula)
+	if err != nil {
+		return nil, domainerrors.New(
+			err,
+			domainerrors.EINVALID,
+			"invalid derived meter formula",
+			domainerrors.WithOperation("MeterService.CreateMeter"),
+		)
+	}
+
+	sources := expr.Variables()
+	if len(sources) == 0 {
+		return nil, domainerrors.New(
+			fmt.Errorf("formula %q does not reference any meter", arg.Formula),
+			domainerrors.EINVALID,
+			"invalid derived meter formula",
+			domainerrors.WithOperation("MeterService.CreateMeter"),
+		)
+	}
+
+	var properties []string
+	granularity := models.GranularitySecond
+	for i, slug := range sources {
+		if slug == arg.MeterSlug {
+			return nil, domainerrors.New(
+				fmt.Errorf("derived meter %s cannot reference itself", slug),
+				domainerrors.EINVALID,
+				"invalid derived meter formula",
+				domainerrors.WithOperation("MeterService.CreateMeter"),
+			)
+		}
+		source, err := s.store.GetMeterByIDorSlug(ctx, slug)
+		if err != nil {
+			if domainerrors.GetErrorCode(err) == string(domainerrors.ENOTFOUND) {
+				return nil, domainerrors.New(
+					fmt.Errorf("formula references unknown meter %s", slug),
+					domainerrors.EINVALID,
+					"invalid derived meter formula",
+					domainerrors.WithOperation("MeterService.CreateMeter"),
+				)
+			}
+			return nil, err
+		}
+		if err := validateMeterActive(source, "MeterService.CreateMeter"); err != nil {
+			return nil, err
+		}
+
+		if source.Granularity.Duration() > granularity.Duration() {
+			granularity = source.Granularity
+		}
+		if i == 0 {
+			properties = append([]string{}, source.Properties...)
+			continue
+		}
+		properties = slices.DeleteFunc(properties, func(p string) bool {
+			return !slices.Contains(source.Properties, p)
+		})
+	}
+
+	arg.EventType = ""
+	arg.EventTypes = nil
+	arg.ValueProperties = nil
+	arg.ValueProperty = ""
+	arg.Aggregation = ""
+	arg.Properties = properties
+	arg.SourceMeters = sources
+	arg.Granularity = granularity
+	arg.Status = models.MeterStatusActive
+	return s.store.CreateMeter(ctx, arg)
+}
+
+// queryDerivedMeter queries every source meter with the same parameters, joins the rows
+// on window and group by values and evaluates the formula for each joined row.
+// Sources without a row for a given window and group contribute zero.
+func (s *MeterService) queryDerivedMeter(ctx context.Context, m *models.Meter, arg models.QueryMeterParams) (*models.QueryMeterResult, error) {
+	if arg.Limit != nil || arg.Offset != nil || arg.Cursor != "" || arg.IncludeOthers {
+		return nil, domainerrors.New(
+			fmt.Errorf("limit, offset, cursor and include_others are not supported for derived meter %s", m.Slug),
+			domainerrors.EINVALID,
+			"invalid meter query",
+			domainerrors.WithOperation("MeterService.QueryMeter"),
+		)
+	}
+
+	expr, err := formula.Parse(m.Formula)
+	if err != nil {
+		return nil, domainerrors.New(
+			err,
+			domainerrors.EINTERNAL,
+			"stored derived meter formula is invalid",
+			domainerrors.WithOperation("MeterService.QueryMeter"),
+		)
+	}
+
+	type joinedRow struct {
+		row  models.QueryMeterRow
+		vars map[string]float64
+	}
+	joined := make(map[string]*joinedRow)
+	keys := make([]string, 0)
+	result := &models.QueryMeterResult{WindowSize: arg.WindowSize}
+
+	for _, slug := range m.SourceMeters {
+		sub := arg
+		sub.MeterSlug = slug
+		sub.OrderBy = nil
+		sub.Fill = ""
+		// Running totals and comparisons apply to the evaluated formula, not to its sources
+		sub.Cumulative = false
+		sub.Compare = ""
+		sourceResult, err := s.QueryMeter(ctx, sub)
+		if err != nil {
+			return nil, err
+		}
+		result.WindowStart = earliest(result.WindowStart, sourceResult.WindowStart)
+		result.WindowEnd = latest(result.WindowEnd, sourceResult.WindowEnd)
+
+		for _, row := range sourceResult.Data {
+			key := groupKey(row, arg.GroupBy)
+			if arg.WindowSize != nil {
+				key = strconv.FormatInt(row.WindowStart.Unix(), 10) + "\x00" + key
+			}
+
+			j, ok := joined[key]
+			if !ok {
+				j = &joinedRow{row: row, vars: make(map[string]float64)}
+				joined[key] = j
+				keys = append(keys, key)
+			} else if arg.WindowSize == nil {
+				// Without windows each source reports its own first and last window
+				if row.WindowStart.Before(j.row.WindowStart) {
+					j.row.WindowStart = row.WindowStart
+				}
+				if row.WindowEnd.After(j.row.WindowEnd) {
+					j.row.WindowEnd = row.WindowEnd
+				}
+			}
+			j.vars[slug] += row.Value
+		}
+	}
+
+	result.Data = make([]models.QueryMeterRow, 0, len(keys))
+	for _, key := range keys {
+		j := joined[key]
+		j.row.Value = expr.Eval(j.vars)
+		result.Data = append(result.Data, j.row)
+	}
+
+	if arg.OrderBy != nil {
+		if err := resolveQueryPage(&arg); err != nil {
+			return nil, err
+		}
+		sortQueryRows(result.Data, arg.OrderBy)
+	} else if arg.WindowSize != nil {
+		sort.SliceStable(result.Data, func(i, k int) bool {
+			return result.Data[i].WindowStart.Before(result.Data[k].WindowStart)
+		})
+	}
+
+	return result, nil
+}
+
+// sortQueryRows orders result rows in memory the same way the OLAP query would.
+func sortQueryRows(rows []models.QueryMeterRow, orderBy *models.QueryMeterOrderBy) {
+	desc := orderBy.Direction == models.SortDesc
+	sort.SliceStable(rows, func(i, k int) bool {
+		a, b := rows[i], rows[k]
+		var less, equal bool
+		switch orderBy.Field {
+		case models.OrderByValue:
+			less, equal = a.Value < b.Value, a.Value == b.Value
+		case "windowstart":
+			less, equal = a.WindowStart.Before(b.WindowStart), a.WindowStart.Equal(b.WindowStart)
+		default:
+			less, equal = a.GroupBy[orderBy.Field] < b.GroupBy[orderBy.Field], a.GroupBy[orderBy.Field] == b.GroupBy[orderBy.Field]
+		}
+		if desc {
+			return !less && !equal
+		}
+		return less
+	})
+}
+
+func earliest(a, b *time.Time) *time.Time {
+	if a == nil || (b != nil && b.Before(*a)) {
+		return b
+	}
+	return a
+}
+
+func latest(a, b *time.Time) *time.Time {
+	if a == nil || (b != nil && b.After(*a)) {
+		return b
+	}
+	return a
+}
@@ -0,0 +1,155 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/redcardinal-io/metering/domain/models"
+	"github.com/redcardinal-io/metering/domain/pkg/config"
+)
+
+// fakeMeterStore serves meters from a map and otherwise behaves like MockMeterStoreRepository
+type fakeMeterStore struct {
+	MockMeterStoreRepository
+	meters map[string]*models.Meter
+}
+
+func (f *fakeMeterStore) GetMeterByIDorSlug(ctx context.Context, idOrSlug string) (*models.Meter, error) {
+	return f.meters[idOrSlug], nil
+}
+
+// fakeOlap returns copies of canned query results per meter slug
+type fakeOlap struct {
+	results  map[string]*models.QueryMeterResult
+	preview  *models.MeterPreview
+	views    []models.OlapView
+	expected map[string][]models.OlapView
+	dropped  []string
+}
+
+func (f *fakeOlap) Connect(cfg *config.OlapConfig) error { return nil }
+func (f *fakeOlap) Close() error                         { return nil }
+func (f *fakeOlap) GetDB() any                           { return nil }
+func (f *fakeOlap) CreateMeter(ctx context.Context, arg models.CreateMeterInput) error {
+	return nil
+}
+func (f *fakeOlap) StreamMeter(ctx context.Context, arg models.QueryMeterParams, meter *models.Meter, fn func(row models.QueryMeterRow) error) error {
+	result, ok := f.results[arg.MeterSlug]
+	if !ok {
+		return nil
+	}
+	for _, row := range result.Data {
+		if err := fn(row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+func (f *fakeOlap) DeleteMeter(ctx context.Context, meterSlug string) error { return nil }
+func (f *fakeOlap) ListMeterViews(ctx context.Context) ([]models.OlapView, error) {
+	return f.views, nil
+}
+func (f *fakeOlap) ExpectedMeterViews(meter *models.Meter) ([]models.OlapView, error) {
+	return f.expected[meter.Slug], nil
+}
+func (f *fakeOlap) DropMeterView(ctx context.Context, name string) error {
+	f.dropped = append(f.dropped, name)
+	return nil
+}
+func (f *fakeOlap) PreviewMeter(ctx context.Context, input models.PreviewMeterInput) (*models.MeterPreview, error) {
+	return f.preview, nil
+}
+func (f *fakeOlap) QueryMeter(ctx context.Context, arg models.QueryMeterParams, meter *models.Meter) (*models.QueryMeterResult, error) {
+	result, ok := f.results[arg.MeterSlug]
+	if !ok {
+		return nil, nil
+	}
+	copied := *result
+	copied.Data = append([]models.QueryMeterRow(nil), result.Data...)
+	return &copied, nil
+}
+
+func TestMeterService_QueryDerivedMeter(t *testing.T) {
+	ctx := context.Background()
+	day1 := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	day2 := day1.Add(24 * time.Hour)
+	windowSize := models.WindowSizeDay
+
+	row := func(start time.Time, org string, value float64) models.QueryMeterRow {
+		return models.QueryMeterRow{
+			WindowStart: start,
+			WindowEnd:   start.Add(24 * time.Hour),
+			Value:       value,
+			GroupBy:     map[string]string{"organization": org},
+		}
+	}
+
+	store := &fakeMeterStore{meters: map[string]*models.Meter{
+		"errors":     {Slug: "errors", Type: models.MeterTypeStandard, Status: models.MeterStatusActive, Aggregation: models.AggregationCount, Properties: []string{"status"}},
+		"requests":   {Slug: "requests", Type: models.MeterTypeStandard, Status: models.MeterStatusActive, Aggregation: models.AggregationCount, Properties: []string{"status"}},
+		"error_rate": {Slug: "error_rate", Type: models.MeterTypeDerived, Status: models.MeterStatusActive, Formula: "errors / requests", SourceMeters: []string{"errors", "requests"}},
+	}}
+	olap := &fakeOlap{results: map[string]*models.QueryMeterResult{
+		"errors": {Data: []models.QueryMeterRow{
+			row(day1, "org-a", 5),
+		}},
+		"requests": {Data: []models.QueryMeterRow{
+			row(day1, "org-a", 50),
+			row(day2, "org-a", 10),
+			row(day1, "org-b", 20),
+		}},
+	}}
+	svc := NewMeterService(olap, store, nil, config.QueryCacheConfig{})
+
+	t.Run("Joins sources on window and group", func(t *testing.T) {
+		result, err := svc.QueryMeter(ctx, models.QueryMeterParams{
+			MeterSlug:  "error_rate",
+			GroupBy:    []string{"organization"},
+			WindowSize: &windowSize,
+			From:       &day1,
+			To:         &day2,
+		})
+		require.NoError(t, err)
+		require.Len(t, result.Data, 3)
+
+		values := make(map[string]float64)
+		for _, r := range result.Data {
+			values[r.WindowStart.Format(time.DateOnly)+"/"+r.GroupBy["organization"]] = r.Value
+		}
+		assert.InDelta(t, 0.1, values["2025-06-01/org-a"], 1e-9)
+		assert.Equal(t, 0.0, values["2025-06-02/org-a"])
+		assert.Equal(t, 0.0, values["2025-06-01/org-b"])
+	})
+
+	t.Run("Orders rows in memory", func(t *testing.T) {
+		result, err := svc.QueryMeter(ctx, models.QueryMeterParams{
+			MeterSlug:  "error_rate",
+			GroupBy:    []string{"organization"},
+			WindowSize: &windowSize,
+			OrderBy:    &models.QueryMeterOrderBy{Field: models.OrderByValue, Direction: models.SortDesc},
+		})
+		require.NoError(t, err)
+		assert.InDelta(t, 0.1, result.Data[0].Value, 1e-9)
+	})
+
+	t.Run("Rejects pagination", func(t *testing.T) {
+		limit := 10
+		_, err := svc.QueryMeter(ctx, models.QueryMeterParams{
+			MeterSlug: "error_rate",
+			Limit:     &limit,
+		})
+		assert.Error(t, err)
+	})
+
+	t.Run("Rejects undeclared group by", func(t *testing.T) {
+		_, err := svc.QueryMeter(ctx, models.QueryMeterParams{
+			MeterSlug: "error_rate",
+			GroupBy:   []string{"region"},
+		})
+		assert.Error(t, err)
+	})
+}
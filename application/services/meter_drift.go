@@ -0,0 +1,196 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/redcardinal-io/metering/domain/models"
+	"github.com/redcardinal-io/metering/domain/pkg/constants"
+)
+
+// CheckDrift compares the meters in the store with the views in the OLAP database and reports
+// active meters with missing views or views whose columns do not match their definition, and
+// views without a meter. With Repair set, the views of drifted meters are recreated from rc_events
+// and orphan views are dropped, unless DryRun is also set.
+// Meters that are pending, deleting or failed are left to the MeterReconciler and RetryMeter.
+func (s *MeterService) CheckDrift(ctx context.Context, opts models.MeterDriftOptions) (*models.MeterDriftReport, error) {
+	meters, err := s.store.ListAllMeters(ctx)
+	if err != nil {
+		return nil, err
+	}
+	views, err := s.olap.ListMeterViews(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	actual := make(map[string]models.OlapView, len(views))
+	for _, view := range views {
+		actual[view.Name] = view
+	}
+
+	report := &models.MeterDriftReport{
+		Repair: opts.Repair,
+		DryRun: opts.DryRun,
+		Drifts: make([]models.MeterDrift, 0),
+	}
+	owned := make(map[string]struct{})
+	tenants := make(map[string]struct{})
+	drifted := make(map[string]*models.Meter)
+
+	for _, m := range meters {
+		tenants[m.TenantSlug] = struct{}{}
+		if m.IsDerived() {
+			continue
+		}
+		expected, err := s.olap.ExpectedMeterViews(m)
+		if err != nil {
+			return nil, err
+		}
+		for _, view := range expected {
+			owned[view.Name] = struct{}{}
+		}
+		if m.Status != models.MeterStatusActive || (opts.TenantSlug != "" && m.TenantSlug != opts.TenantSlug) {
+			continue
+		}
+
+		report.Meters++
+		for _, view := range expected {
+			drift := models.MeterDrift{
+				TenantSlug: m.TenantSlug,
+				MeterSlug:  m.Slug,
+				View:       view.Name,
+				Action:     models.DriftActionRecreate,
+			}
+			existing, ok := actual[view.Name]
+			if !ok {
+				drift.Kind = models.DriftMissingView
+			} else if detail := diffColumns(view.Columns, existing.Columns); detail != "" {
+				drift.Kind = models.DriftColumnMismatch
+				drift.Detail = detail
+			} else {
+				continue
+			}
+			report.Drifts = append(report.Drifts, drift)
+			drifted[m.TenantSlug+"\x00"+m.Slug] = m
+		}
+	}
+
+	for _, view := range views {
+		tenant := viewTenant(view.Name, tenants)
+		if opts.TenantSlug != "" && tenant != opts.TenantSlug {
+			continue
+		}
+		report.Views++
+		if _, ok := owned[view.Name]; ok {
+			continue
+		}
+		report.Drifts = append(report.Drifts, models.MeterDrift{
+			Kind:       models.DriftOrphanView,
+			TenantSlug: tenant,
+			View:       view.Name,
+			Action:     models.DriftActionDrop,
+		})
+	}
+
+	if opts.Repair && !opts.DryRun {
+		s.repairDrift(ctx, report, drifted)
+	}
+	return report, nil
+}
+
+// repairDrift applies the action of every drift in the report, recreating the views of each
+// drifted meter once, and records the outcome on the drifts.
+func (s *MeterService) repairDrift(ctx context.Context, report *models.MeterDriftReport, drifted map[string]*models.Meter) {
+	recreated := make(map[string]error)
+	for i := range report.Drifts {
+		drift := &report.Drifts[i]
+		tenantCtx := context.WithValue(ctx, constants.TenantSlugKey, drift.TenantSlug)
+
+		var err error
+		switch drift.Action {
+		case models.DriftActionDrop:
+			err = s.olap.DropMeterView(tenantCtx, drift.View)
+		case models.DriftActionRecreate:
+			key := drift.TenantSlug + "\x00" + drift.MeterSlug
+			var done bool
+			if err, done = recreated[key]; !done {
+				err = s.recreateMeterViews(tenantCtx, drifted[key])
+				recreated[key] = err
+			}
+		}
+
+		drift.Repaired = err == nil
+		if err != nil {
+			drift.Error = statusError(err)
+		}
+	}
+}
+
+// recreateMeterViews drops the views of a meter and creates them again, populated from rc_events.
+func (s *MeterService) recreateMeterViews(ctx context.Context, m *models.Meter) error {
+	if err := s.olap.DeleteMeter(ctx, m.Slug); err != nil {
+		return err
+	}
+	if err := s.olap.CreateMeter(ctx, meterViewInput(m, true)); err != nil {
+		return err
+	}
+	s.InvalidateQueryCache(ctx, m.Slug)
+	return nil
+}
+
+// diffColumns describes how the actual columns of a view differ from the expected ones, or
+// returns an empty string when they match.
+func diffColumns(expected, actual []models.OlapColumn) string {
+	actualTypes := make(map[string]string, len(actual))
+	for _, column := range actual {
+		actualTypes[column.Name] = column.Type
+	}
+
+	var missing, changed []string
+	expectedNames := make(map[string]struct{}, len(expected))
+	for _, column := range expected {
+		expectedNames[column.Name] = struct{}{}
+		actualType, ok := actualTypes[column.Name]
+		if !ok {
+			missing = append(missing, column.Name)
+		} else if actualType != column.Type {
+			changed = append(changed, fmt.Sprintf("%s is %s, expected %s", column.Name, actualType, column.Type))
+		}
+	}
+	var unexpected []string
+	for _, column := range actual {
+		if _, ok := expectedNames[column.Name]; !ok {
+			unexpected = append(unexpected, column.Name)
+		}
+	}
+
+	details := make([]string, 0, 3)
+	if len(missing) > 0 {
+		details = append(details, "missing columns "+strings.Join(missing, ", "))
+	}
+	if len(unexpected) > 0 {
+		details = append(details, "unexpected columns "+strings.Join(unexpected, ", "))
+	}
+	details = append(details, changed...)
+	return strings.Join(details, "; ")
+}
+
+// viewTenant attributes a view to the known tenant with the longest matching name prefix, or
+// returns an empty string. View names do not delimit the tenant, so the match is best effort.
+func viewTenant(view string, tenants map[string]struct{}) string {
+	candidates := make([]string, 0, len(tenants))
+	for tenant := range tenants {
+		if strings.HasPrefix(view, "rc_"+tenant+"_") {
+			candidates = append(candidates, tenant)
+		}
+	}
+	sort.Slice(candidates, func(i, k int) bool {
+		return len(candidates[i]) > len(candidates[k])
+	})
+	if len(candidates) == 0 {
+		return ""
+	}
+	return candidates[0]
+}
@@ -0,0 +1,125 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/redcardinal-io/metering/domain/models"
+	"github.com/redcardinal-io/metering/domain/pkg/config"
+)
+
+// recreatingOlap records the meters whose views are dropped and created
+type recreatingOlap struct {
+	fakeOlap
+	deleted []string
+	created []models.CreateMeterInput
+}
+
+func (f *recreatingOlap) DeleteMeter(ctx context.Context, meterSlug string) error {
+	f.deleted = append(f.deleted, meterSlug)
+	return nil
+}
+
+func (f *recreatingOlap) CreateMeter(ctx context.Context, arg models.CreateMeterInput) error {
+	f.created = append(f.created, arg)
+	return nil
+}
+
+func TestMeterService_CheckDrift(t *testing.T) {
+	ctx := context.Background()
+	columns := []models.OlapColumn{
+		{Name: "organization", Type: "String"},
+		{Name: "value", Type: "AggregateFunction(sum, Float64)"},
+		{Name: "region", Type: "String"},
+	}
+	meter := func(tenant, slug string, status models.MeterStatus) *models.Meter {
+		return &models.Meter{Base: models.Base{ID: uuid.New()}, Slug: slug, Type: models.MeterTypeStandard, Status: status, TenantSlug: tenant}
+	}
+	view := func(name string, columns []models.OlapColumn) models.OlapView {
+		return models.OlapView{Name: name, Columns: columns}
+	}
+
+	healthy := meter("acme", "requests", models.MeterStatusActive)
+	missing := meter("acme", "tokens", models.MeterStatusActive)
+	changed := meter("globex", "storage", models.MeterStatusActive)
+	pending := meter("globex", "egress", models.MeterStatusPending)
+	store := &lifecycleStore{meters: map[uuid.UUID]*models.Meter{
+		healthy.ID: healthy, missing.ID: missing, changed.ID: changed, pending.ID: pending,
+	}}
+
+	newOlap := func() *recreatingOlap {
+		return &recreatingOlap{fakeOlap: fakeOlap{
+			expected: map[string][]models.OlapView{
+				"requests": {view("rc_acme_requests_mv", columns), view("rc_acme_requests_mv_hour", columns)},
+				"tokens":   {view("rc_acme_tokens_mv", columns), view("rc_acme_tokens_mv_hour", columns)},
+				"storage":  {view("rc_globex_storage_mv", columns)},
+				"egress":   {view("rc_globex_egress_mv", columns)},
+			},
+			views: []models.OlapView{
+				view("rc_acme_requests_mv", columns),
+				view("rc_acme_requests_mv_hour", columns),
+				view("rc_acme_tokens_mv", columns),
+				view("rc_globex_storage_mv", []models.OlapColumn{
+					{Name: "organization", Type: "String"},
+					{Name: "value", Type: "AggregateFunction(avg, Float64)"},
+					{Name: "zone", Type: "String"},
+				}),
+				view("rc_acme_legacy_mv", columns),
+			},
+		}}
+	}
+
+	t.Run("Reports drift without repairing", func(t *testing.T) {
+		olap := newOlap()
+		svc := NewMeterService(olap, store, nil, config.QueryCacheConfig{})
+
+		report, err := svc.CheckDrift(ctx, models.MeterDriftOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, 3, report.Meters)
+		assert.Equal(t, 5, report.Views)
+
+		byView := make(map[string]models.MeterDrift)
+		for _, drift := range report.Drifts {
+			byView[drift.View] = drift
+		}
+		require.Len(t, byView, 3)
+		assert.Equal(t, models.DriftMissingView, byView["rc_acme_tokens_mv_hour"].Kind)
+		assert.Equal(t, models.DriftColumnMismatch, byView["rc_globex_storage_mv"].Kind)
+		assert.Equal(t, "missing columns region; unexpected columns zone; value is AggregateFunction(avg, Float64), expected AggregateFunction(sum, Float64)", byView["rc_globex_storage_mv"].Detail)
+		assert.Equal(t, models.DriftOrphanView, byView["rc_acme_legacy_mv"].Kind)
+		assert.Equal(t, "acme", byView["rc_acme_legacy_mv"].TenantSlug)
+		assert.Empty(t, olap.deleted)
+		assert.Empty(t, olap.dropped)
+	})
+
+	t.Run("Dry run does not repair", func(t *testing.T) {
+		olap := newOlap()
+		svc := NewMeterService(olap, store, nil, config.QueryCacheConfig{})
+
+		report, err := svc.CheckDrift(ctx, models.MeterDriftOptions{Repair: true, DryRun: true})
+		require.NoError(t, err)
+		assert.Len(t, report.Drifts, 3)
+		assert.Empty(t, olap.created)
+		assert.Empty(t, olap.dropped)
+	})
+
+	t.Run("Repairs drift for a single tenant", func(t *testing.T) {
+		olap := newOlap()
+		svc := NewMeterService(olap, store, nil, config.QueryCacheConfig{})
+
+		report, err := svc.CheckDrift(ctx, models.MeterDriftOptions{TenantSlug: "acme", Repair: true})
+		require.NoError(t, err)
+		require.Len(t, report.Drifts, 2)
+		for _, drift := range report.Drifts {
+			assert.True(t, drift.Repaired, drift.View)
+		}
+		assert.Equal(t, []string{"tokens"}, olap.deleted)
+		require.Len(t, olap.created, 1)
+		assert.True(t, olap.created[0].Populate)
+		assert.Equal(t, []string{"rc_acme_legacy_mv"}, olap.dropped)
+	})
+}
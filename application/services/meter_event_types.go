@@ -0,0 +1,46 @@
+package services
+
+import (
+	"fmt"
+
+	"github.com/redcardinal-io/metering/domain/models"
+)
+
+// normalizeEventTypes merges EventType and EventTypes into the deduplicated list of event types a
+// standard meter aggregates, with EventType set to the first one, and checks that events of every
+// type have a value property unless the meter counts events.
+func normalizeEventTypes(arg *models.CreateMeterInput) error {
+	eventTypes := make([]string, 0, len(arg.EventTypes)+1)
+	seen := make(map[string]struct{}, len(arg.EventTypes)+1)
+	for _, eventType := range append([]string{arg.EventType}, arg.EventTypes...) {
+		if _, ok := seen[eventType]; ok || eventType == "" {
+			continue
+		}
+		seen[eventType] = struct{}{}
+		eventTypes = append(eventTypes, eventType)
+	}
+	if len(eventTypes) == 0 {
+		return fmt.Errorf("at least one event type is required")
+	}
+	arg.EventType = eventTypes[0]
+	arg.EventTypes = eventTypes
+
+	if arg.Aggregation == models.AggregationCount {
+		arg.ValueProperties = nil
+		return nil
+	}
+	for eventType, property := range arg.ValueProperties {
+		if _, ok := seen[eventType]; !ok {
+			return fmt.Errorf("value_properties references event type %s the meter does not aggregate", eventType)
+		}
+		if property == "" {
+			return fmt.Errorf("value property for event type %s is empty", eventType)
+		}
+	}
+	for _, eventType := range eventTypes {
+		if _, ok := arg.ValueProperties[eventType]; !ok && arg.ValueProperty == "" {
+			return fmt.Errorf("value_property is required for events of type %s", eventType)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/redcardinal-io/metering/domain/models"
+)
+
+func TestNormalizeEventTypes(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   models.CreateMeterInput
+		want    []string
+		wantErr bool
+	}{
+		{
+			name:  "Single event type",
+			input: models.CreateMeterInput{EventType: "completion", Aggregation: models.AggregationCount},
+			want:  []string{"completion"},
+		},
+		{
+			name: "Merges and deduplicates event types",
+			input: models.CreateMeterInput{
+				EventType:     "vm.stopped",
+				EventTypes:    []string{"job.finished", "vm.stopped"},
+				ValueProperty: "seconds",
+				Aggregation:   models.AggregationSum,
+			},
+			want: []string{"vm.stopped", "job.finished"},
+		},
+		{
+			name: "Value properties per event type",
+			input: models.CreateMeterInput{
+				EventTypes:      []string{"vm.stopped", "job.finished"},
+				ValueProperties: map[string]string{"vm.stopped": "seconds", "job.finished": "duration"},
+				Aggregation:     models.AggregationSum,
+			},
+			want: []string{"vm.stopped", "job.finished"},
+		},
+		{
+			name:    "No event type",
+			input:   models.CreateMeterInput{Aggregation: models.AggregationCount},
+			wantErr: true,
+		},
+		{
+			name: "Event type without a value property",
+			input: models.CreateMeterInput{
+				EventTypes:      []string{"vm.stopped", "job.finished"},
+				ValueProperties: map[string]string{"vm.stopped": "seconds"},
+				Aggregation:     models.AggregationSum,
+			},
+			wantErr: true,
+		},
+		{
+			name: "Value property for an unknown event type",
+			input: models.CreateMeterInput{
+				EventType:       "vm.stopped",
+				ValueProperty:   "seconds",
+				ValueProperties: map[string]string{"job.finished": "duration"},
+				Aggregation:     models.AggregationSum,
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := normalizeEventTypes(&tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, tt.input.EventTypes)
+			assert.Equal(t, tt.want[0], tt.input.EventType)
+		})
+	}
+}
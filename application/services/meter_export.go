@@ -0,0 +1,63 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	domainerrors "github.com/redcardinal-io/metering/domain/errors"
+	"github.com/redcardinal-io/metering/domain/models"
+	"github.com/redcardinal-io/metering/domain/pkg/export"
+)
+
+// ExportMeter validates an export of a meter query and returns a function streaming the rows
+// to a writer in the requested format. Validation errors are returned before anything is written,
+// so callers can still report them with a proper status.
+func (s *MeterService) ExportMeter(ctx context.Context, arg models.QueryMeterParams, format models.ExportFormat) (func(w io.Writer) error, error) {
+	if !models.IsValidExportFormat(format) {
+		return nil, domainerrors.New(
+			fmt.Errorf("unsupported export format: %s", format),
+			domainerrors.EINVALID,
+			"invalid meter export",
+			domainerrors.WithOperation("MeterService.ExportMeter"),
+		)
+	}
+
+	m, err := s.store.GetMeterByIDorSlug(ctx, arg.MeterSlug)
+	if err != nil {
+		return nil, err
+	}
+	if m.IsDerived() || arg.Fill != "" || arg.Cumulative || arg.Compare != "" {
+		// All of these need the complete result in memory, which defeats streaming
+		return nil, domainerrors.New(
+			fmt.Errorf("derived meters, filled, cumulative and compared series cannot be exported"),
+			domainerrors.EINVALID,
+			"invalid meter export",
+			domainerrors.WithOperation("MeterService.ExportMeter"),
+		)
+	}
+	if err := validateMeterActive(m, "MeterService.ExportMeter"); err != nil {
+		return nil, err
+	}
+	if err := validateQueryColumns(m, arg); err != nil {
+		return nil, err
+	}
+	if err := validateQueryWindow(m, arg); err != nil {
+		return nil, err
+	}
+	if err := resolveQueryPage(&arg); err != nil {
+		return nil, err
+	}
+
+	layout := export.Layout{GroupBy: arg.GroupBy, Others: arg.IncludeOthers}
+	return func(w io.Writer) error {
+		enc, err := export.NewEncoder(format, w, layout)
+		if err != nil {
+			return err
+		}
+		if err := s.olap.StreamMeter(ctx, arg, m, enc.Encode); err != nil {
+			return err
+		}
+		return enc.Close()
+	}, nil
+}
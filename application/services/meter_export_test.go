@@ -0,0 +1,52 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/redcardinal-io/metering/domain/models"
+	"github.com/redcardinal-io/metering/domain/pkg/config"
+)
+
+func TestMeterService_ExportMeter(t *testing.T) {
+	ctx := context.Background()
+	start := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	store := &fakeMeterStore{meters: map[string]*models.Meter{
+		"requests":   {Slug: "requests", Type: models.MeterTypeStandard, Status: models.MeterStatusActive, Aggregation: models.AggregationCount},
+		"error_rate": {Slug: "error_rate", Type: models.MeterTypeDerived, Status: models.MeterStatusActive, Formula: "requests / requests", SourceMeters: []string{"requests"}},
+	}}
+	olap := &fakeOlap{results: map[string]*models.QueryMeterResult{
+		"requests": {Data: []models.QueryMeterRow{
+			{WindowStart: start, WindowEnd: start.Add(time.Hour), Value: 3, GroupBy: map[string]string{"organization": "org-a"}},
+		}},
+	}}
+	svc := NewMeterService(olap, store, nil, config.QueryCacheConfig{})
+
+	t.Run("Streams rows in the requested format", func(t *testing.T) {
+		stream, err := svc.ExportMeter(ctx, models.QueryMeterParams{
+			MeterSlug: "requests",
+			GroupBy:   []string{"organization"},
+		}, models.ExportFormatCSV)
+		require.NoError(t, err)
+
+		var buf bytes.Buffer
+		require.NoError(t, stream(&buf))
+		assert.Equal(t, "window_start,window_end,organization,value\n2025-06-01T00:00:00Z,2025-06-01T01:00:00Z,org-a,3\n", buf.String())
+	})
+
+	t.Run("Rejects derived meters", func(t *testing.T) {
+		_, err := svc.ExportMeter(ctx, models.QueryMeterParams{MeterSlug: "error_rate"}, models.ExportFormatNDJSON)
+		assert.Error(t, err)
+	})
+
+	t.Run("Rejects unknown formats", func(t *testing.T) {
+		_, err := svc.ExportMeter(ctx, models.QueryMeterParams{MeterSlug: "requests"}, "xlsx")
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,129 @@
+package services
+
+import (
+	"fmt"
+	"time"
+
+	domainerrors "github.com/redcardinal-io/metering/domain/errors"
+	"github.com/redcardinal-io/metering/domain/models"
+)
+
+// MaxFillWindows bounds the number of windows per group a filled query may produce
+const MaxFillWindows = 10000
+
+// validateFill checks that a fill mode is only requested for bounded windowed queries.
+func validateFill(arg models.QueryMeterParams) error {
+	if arg.Fill == "" {
+		return nil
+	}
+	if !models.IsValidFillMode(arg.Fill) {
+		return domainerrors.New(
+			fmt.Errorf("invalid fill mode: %s", arg.Fill),
+			domainerrors.EINVALID,
+			"invalid meter query",
+			domainerrors.WithOperation("MeterService.QueryMeter"),
+		)
+	}
+	if arg.WindowSize == nil || arg.From == nil || arg.To == nil {
+		return domainerrors.New(
+			fmt.Errorf("fill requires window_size, from and to"),
+			domainerrors.EINVALID,
+			"invalid meter query",
+			domainerrors.WithOperation("MeterService.QueryMeter"),
+		)
+	}
+
+	from, to := alignWindowRange(*arg.WindowSize, *arg.From, *arg.To)
+	if windows := int(to.Sub(from) / arg.WindowSize.Duration()); windows > MaxFillWindows {
+		return domainerrors.New(
+			fmt.Errorf("fill would produce %d windows per group, maximum is %d", windows, MaxFillWindows),
+			domainerrors.EINVALID,
+			"invalid meter query",
+			domainerrors.WithOperation("MeterService.QueryMeter"),
+		)
+	}
+	return nil
+}
+
+// alignWindowRange expands a time range to whole windows, matching the range used by the OLAP query.
+func alignWindowRange(windowSize models.WindowSize, from, to time.Time) (time.Time, time.Time) {
+	size := windowSize.Duration()
+	from = from.UTC().Truncate(size)
+	alignedTo := to.UTC().Truncate(size)
+	if windowSize != models.WindowSizeMinute && !alignedTo.Equal(to.UTC()) {
+		alignedTo = alignedTo.Add(size)
+	}
+	return from, alignedTo
+}
+
+// fillWindows adds a row for every window between From and To that has no data, for every
+// group present in the result. Groups are emitted in order of first appearance within each window.
+func fillWindows(result *models.QueryMeterResult, arg models.QueryMeterParams) {
+	size := arg.WindowSize.Duration()
+	from, to := alignWindowRange(*arg.WindowSize, *arg.From, *arg.To)
+
+	type group struct {
+		sample models.QueryMeterRow
+		rows   map[int64]models.QueryMeterRow
+	}
+	groups := make(map[string]*group)
+	order := make([]string, 0)
+	for _, row := range result.Data {
+		key := groupKey(row, arg.GroupBy)
+		if row.Others {
+			key = "\x00others"
+		}
+		g, ok := groups[key]
+		if !ok {
+			g = &group{sample: row, rows: make(map[int64]models.QueryMeterRow)}
+			groups[key] = g
+			order = append(order, key)
+		}
+		g.rows[row.WindowStart.Unix()] = row
+	}
+
+	// Without any rows there is nothing to fill per group, except the single series of an ungrouped query
+	if len(order) == 0 && len(arg.GroupBy) == 0 {
+		groups[""] = &group{rows: make(map[int64]models.QueryMeterRow)}
+		order = append(order, "")
+	}
+
+	filled := make([]models.QueryMeterRow, 0, len(result.Data))
+	previous := make(map[string]*models.QueryMeterRow, len(order))
+	for start := from; start.Before(to); start = start.Add(size) {
+		for _, key := range order {
+			g := groups[key]
+			if row, ok := g.rows[start.Unix()]; ok {
+				filled = append(filled, row)
+				previous[key] = &row
+				continue
+			}
+
+			row := models.QueryMeterRow{
+				WindowStart: start,
+				WindowEnd:   start.Add(size),
+				GroupBy:     g.sample.GroupBy,
+				Others:      g.sample.Others,
+				Filled:      true,
+			}
+			switch arg.Fill {
+			case models.FillModeNull:
+				row.Null = true
+			case models.FillModePrevious:
+				if prev, ok := previous[key]; ok {
+					row.Value, row.Null = prev.Value, prev.Null
+				} else {
+					row.Null = true
+				}
+			}
+			filled = append(filled, row)
+		}
+	}
+
+	if arg.OrderBy != nil {
+		sortQueryRows(filled, arg.OrderBy)
+	}
+	result.Data = filled
+	windowStart, windowEnd := from, to
+	result.WindowStart, result.WindowEnd = &windowStart, &windowEnd
+}
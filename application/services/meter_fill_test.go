@@ -0,0 +1,123 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/redcardinal-io/metering/domain/models"
+	"github.com/redcardinal-io/metering/domain/pkg/config"
+)
+
+func TestMeterService_QueryMeterFill(t *testing.T) {
+	ctx := context.Background()
+	hour0 := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	hour := func(n int) time.Time { return hour0.Add(time.Duration(n) * time.Hour) }
+	windowSize := models.WindowSizeHour
+
+	row := func(start time.Time, org string, value float64) models.QueryMeterRow {
+		return models.QueryMeterRow{
+			WindowStart: start,
+			WindowEnd:   start.Add(time.Hour),
+			Value:       value,
+			GroupBy:     map[string]string{"organization": org},
+		}
+	}
+
+	store := &fakeMeterStore{meters: map[string]*models.Meter{
+		"cpu": {Slug: "cpu", Type: models.MeterTypeStandard, Status: models.MeterStatusActive, Aggregation: models.AggregationMax},
+	}}
+	olap := &fakeOlap{results: map[string]*models.QueryMeterResult{
+		"cpu": {Data: []models.QueryMeterRow{
+			row(hour(1), "org-a", 40),
+			row(hour(1), "org-b", 10),
+			row(hour(3), "org-a", 60),
+		}},
+	}}
+	svc := NewMeterService(olap, store, nil, config.QueryCacheConfig{})
+
+	query := func(fill models.FillMode) (*models.QueryMeterResult, error) {
+		from, to := hour(0), hour(4).Add(-time.Minute)
+		return svc.QueryMeter(ctx, models.QueryMeterParams{
+			MeterSlug:  "cpu",
+			GroupBy:    []string{"organization"},
+			WindowSize: &windowSize,
+			From:       &from,
+			To:         &to,
+			Fill:       fill,
+		})
+	}
+
+	series := func(result *models.QueryMeterResult, org string) []models.QueryMeterRow {
+		rows := make([]models.QueryMeterRow, 0)
+		for _, r := range result.Data {
+			if r.GroupBy["organization"] == org {
+				rows = append(rows, r)
+			}
+		}
+		return rows
+	}
+
+	t.Run("Zero fills every window per group", func(t *testing.T) {
+		result, err := query(models.FillModeZero)
+		require.NoError(t, err)
+		require.Len(t, result.Data, 8)
+
+		a := series(result, "org-a")
+		require.Len(t, a, 4)
+		assert.Equal(t, hour(0), a[0].WindowStart)
+		assert.True(t, a[0].Filled)
+		assert.Equal(t, 0.0, a[0].Value)
+		assert.False(t, a[1].Filled)
+		assert.Equal(t, 40.0, a[1].Value)
+		assert.Equal(t, hour(3), a[3].WindowStart)
+	})
+
+	t.Run("Null fills with null values", func(t *testing.T) {
+		result, err := query(models.FillModeNull)
+		require.NoError(t, err)
+
+		b := series(result, "org-b")
+		require.Len(t, b, 4)
+		assert.True(t, b[0].Null)
+		assert.False(t, b[1].Null)
+		assert.True(t, b[2].Null)
+	})
+
+	t.Run("Previous carries the last value forward", func(t *testing.T) {
+		result, err := query(models.FillModePrevious)
+		require.NoError(t, err)
+
+		a := series(result, "org-a")
+		require.Len(t, a, 4)
+		assert.True(t, a[0].Null)
+		assert.Equal(t, 40.0, a[2].Value)
+		assert.True(t, a[2].Filled)
+		assert.Equal(t, 60.0, a[3].Value)
+	})
+
+	t.Run("Requires a bounded window", func(t *testing.T) {
+		_, err := svc.QueryMeter(ctx, models.QueryMeterParams{
+			MeterSlug:  "cpu",
+			WindowSize: &windowSize,
+			Fill:       models.FillModeZero,
+		})
+		assert.Error(t, err)
+	})
+
+	t.Run("Rejects too many windows", func(t *testing.T) {
+		minute := models.WindowSizeMinute
+		from, to := hour(0), hour(0).Add(365*24*time.Hour)
+		_, err := svc.QueryMeter(ctx, models.QueryMeterParams{
+			MeterSlug:  "cpu",
+			WindowSize: &minute,
+			From:       &from,
+			To:         &to,
+			Fill:       models.FillModeZero,
+		})
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,158 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	domainerrors "github.com/redcardinal-io/metering/domain/errors"
+	"github.com/redcardinal-io/metering/domain/models"
+)
+
+// RetryMeter resumes the operation that failed on a meter. A meter that failed to be created is
+// brought back to active by creating its OLAP view again; events ingested before the view exists
+// are not backfilled. A meter that failed to be removed is removed, and returned as deleting.
+func (s *MeterService) RetryMeter(ctx context.Context, idOrSlug string) (*models.Meter, error) {
+	m, err := s.store.GetMeterByIDorSlug(ctx, idOrSlug)
+	if err != nil {
+		return nil, err
+	}
+
+	switch m.Status {
+	case models.MeterStatusFailed:
+		active, err := s.activateMeter(ctx, m)
+		if err != nil {
+			s.recordMeterFailure(ctx, m, models.MeterStatusFailed, err, 0)
+			return nil, err
+		}
+		s.InvalidateQueryCache(ctx, m.Slug)
+		return active, nil
+	case models.MeterStatusDeleteFailed:
+		if err := s.removeMeter(ctx, m); err != nil {
+			s.recordMeterFailure(ctx, m, models.MeterStatusDeleteFailed, err, 0)
+			return nil, err
+		}
+		s.InvalidateQueryCache(ctx, m.Slug)
+		deleted := *m
+		deleted.Status = models.MeterStatusDeleting
+		return &deleted, nil
+	default:
+		return nil, domainerrors.New(
+			fmt.Errorf("meter %s is %s, only failed and delete_failed meters can be retried", m.Slug, m.Status),
+			domainerrors.ECONFLICT,
+			"meter is not failed",
+			domainerrors.WithOperation("MeterService.RetryMeter"),
+			domainerrors.WithData("status", m.Status),
+		)
+	}
+}
+
+// activateMeter creates the OLAP views of a meter if they do not exist and marks it active.
+func (s *MeterService) activateMeter(ctx context.Context, m *models.Meter) (*models.Meter, error) {
+	if !m.IsDerived() {
+		if err := s.olap.CreateMeter(ctx, meterViewInput(m, false)); err != nil {
+			return nil, err
+		}
+	}
+	return s.store.UpdateMeterStatus(ctx, m.ID, models.UpdateMeterStatusInput{Status: models.MeterStatusActive})
+}
+
+// meterViewInput returns the input that creates the OLAP views of a stored meter.
+func meterViewInput(m *models.Meter, populate bool) models.CreateMeterInput {
+	return models.CreateMeterInput{
+		Name:            m.Name,
+		MeterSlug:       m.Slug,
+		Type:            m.Type,
+		EventType:       m.EventType,
+		EventTypes:      m.EventTypes,
+		ValueProperty:   m.ValueProperty,
+		ValueProperties: m.ValueProperties,
+		Properties:      m.Properties,
+		Aggregation:     m.Aggregation,
+		Granularity:     m.Granularity,
+		Rollups:         m.Rollups,
+		Filter:          m.Filter,
+		Populate:        populate,
+	}
+}
+
+// validateMeterFilter checks the event filter of a meter definition.
+func validateMeterFilter(filter *models.FilterExpression, op string) error {
+	if filter == nil {
+		return nil
+	}
+	if err := filter.ValidateEventFilter(); err != nil {
+		return domainerrors.New(
+			err,
+			domainerrors.EINVALID,
+			"invalid meter filter",
+			domainerrors.WithOperation(op),
+		)
+	}
+	return nil
+}
+
+// removeMeter drops the OLAP views of a meter and deletes its row. Both steps are idempotent,
+// so an interrupted removal can be resumed.
+func (s *MeterService) removeMeter(ctx context.Context, m *models.Meter) error {
+	// Derived meters have no OLAP view
+	if !m.IsDerived() {
+		if err := s.olap.DeleteMeter(ctx, m.Slug); err != nil {
+			return err
+		}
+	}
+	return s.store.DeleteMeterByIDorSlug(ctx, m.ID.String())
+}
+
+// recordMeterFailure stores the error met while moving a meter to a consistent state and returns
+// the meter as recorded. The meter keeps the given status unless maxAttempts is set and reached,
+// in which case it is marked failed, or delete_failed when it was being removed, so that retrying
+// it resumes the removal rather than recreating its view. Errors recording the failure are
+// dropped: the original error is what the caller reports.
+func (s *MeterService) recordMeterFailure(ctx context.Context, m *models.Meter, status models.MeterStatus, err error, maxAttempts int) *models.Meter {
+	failed := *m
+	failed.Status = status
+	failed.StatusError = statusError(err)
+	failed.ReconcileAttempts++
+	if maxAttempts > 0 && failed.ReconcileAttempts >= maxAttempts {
+		failed.Status = models.MeterStatusFailed
+		if status == models.MeterStatusDeleting {
+			failed.Status = models.MeterStatusDeleteFailed
+		}
+	}
+
+	updated, updateErr := s.store.UpdateMeterStatus(ctx, m.ID, models.UpdateMeterStatusInput{
+		Status:            failed.Status,
+		Error:             failed.StatusError,
+		ReconcileAttempts: failed.ReconcileAttempts,
+	})
+	if updateErr != nil {
+		return &failed
+	}
+	return updated
+}
+
+// validateMeterActive rejects meters that cannot be queried because they are not active.
+func validateMeterActive(m *models.Meter, op string) error {
+	if m.Status == models.MeterStatusActive {
+		return nil
+	}
+	return domainerrors.New(
+		fmt.Errorf("meter %s is %s", m.Slug, m.Status),
+		domainerrors.ECONFLICT,
+		"meter is not active",
+		domainerrors.WithOperation(op),
+		domainerrors.WithData("status", m.Status),
+		domainerrors.WithData("status_error", m.StatusError),
+	)
+}
+
+// statusError describes an error for the status_error of a meter, including the underlying cause
+// that application errors only carry internally.
+func statusError(err error) string {
+	var appErr *domainerrors.AppError
+	if errors.As(err, &appErr) && appErr.Err != nil {
+		return appErr.Message + ": " + appErr.Err.Error()
+	}
+	return err.Error()
+}
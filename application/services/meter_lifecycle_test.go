@@ -0,0 +1,268 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/redcardinal-io/metering/domain/models"
+	"github.com/redcardinal-io/metering/domain/pkg/config"
+	"github.com/redcardinal-io/metering/domain/pkg/constants"
+	"github.com/redcardinal-io/metering/domain/pkg/logger"
+)
+
+// lifecycleStore keeps meters in memory and records status transitions
+type lifecycleStore struct {
+	MockMeterStoreRepository
+	meters    map[uuid.UUID]*models.Meter
+	deleteErr error
+}
+
+func (f *lifecycleStore) CreateMeter(ctx context.Context, arg models.CreateMeterInput) (*models.Meter, error) {
+	m := &models.Meter{
+		Base:       models.Base{ID: uuid.New()},
+		Slug:       arg.MeterSlug,
+		Type:       models.MeterTypeStandard,
+		Status:     arg.Status,
+		Filter:     arg.Filter,
+		TenantSlug: ctx.Value(constants.TenantSlugKey).(string),
+	}
+	f.meters[m.ID] = m
+	copied := *m
+	return &copied, nil
+}
+
+func (f *lifecycleStore) GetMeterByIDorSlug(ctx context.Context, idOrSlug string) (*models.Meter, error) {
+	for _, m := range f.meters {
+		if m.Slug == idOrSlug || m.ID.String() == idOrSlug {
+			copied := *m
+			return &copied, nil
+		}
+	}
+	return nil, errors.New("not found")
+}
+
+func (f *lifecycleStore) UpdateMeterStatus(ctx context.Context, id uuid.UUID, arg models.UpdateMeterStatusInput) (*models.Meter, error) {
+	m := f.meters[id]
+	m.Status = arg.Status
+	m.StatusError = arg.Error
+	m.ReconcileAttempts = arg.ReconcileAttempts
+	copied := *m
+	return &copied, nil
+}
+
+func (f *lifecycleStore) DeleteMeterByIDorSlug(ctx context.Context, idOrSlug string) error {
+	if f.deleteErr != nil {
+		return f.deleteErr
+	}
+	delete(f.meters, uuid.MustParse(idOrSlug))
+	return nil
+}
+
+func (f *lifecycleStore) ListMetersByStatus(ctx context.Context, statuses []models.MeterStatus, updatedBefore time.Time, limit int) ([]*models.Meter, error) {
+	meters := make([]*models.Meter, 0)
+	for _, m := range f.meters {
+		for _, status := range statuses {
+			if m.Status == status {
+				copied := *m
+				meters = append(meters, &copied)
+			}
+		}
+	}
+	return meters, nil
+}
+
+func (f *lifecycleStore) ListAllMeters(ctx context.Context) ([]*models.Meter, error) {
+	meters := make([]*models.Meter, 0, len(f.meters))
+	for _, m := range f.meters {
+		copied := *m
+		meters = append(meters, &copied)
+	}
+	return meters, nil
+}
+
+// failingOlap fails meter creation and deletion with the configured errors
+type failingOlap struct {
+	fakeOlap
+	createErr error
+	deleteErr error
+	created   []models.CreateMeterInput
+}
+
+func (f *failingOlap) CreateMeter(ctx context.Context, arg models.CreateMeterInput) error {
+	f.created = append(f.created, arg)
+	return f.createErr
+}
+
+func (f *failingOlap) DeleteMeter(ctx context.Context, meterSlug string) error {
+	return f.deleteErr
+}
+
+func TestMeterService_Lifecycle(t *testing.T) {
+	ctx := context.WithValue(context.Background(), constants.TenantSlugKey, "tenant")
+	input := models.CreateMeterInput{MeterSlug: "requests", EventType: "request", Aggregation: models.AggregationCount}
+
+	t.Run("Creates active meters", func(t *testing.T) {
+		store := &lifecycleStore{meters: map[uuid.UUID]*models.Meter{}}
+		svc := NewMeterService(&failingOlap{}, store, nil, config.QueryCacheConfig{})
+
+		m, err := svc.CreateMeter(ctx, input)
+		require.NoError(t, err)
+		assert.Equal(t, models.MeterStatusActive, m.Status)
+	})
+
+	t.Run("Keeps meters whose view cannot be created as failed", func(t *testing.T) {
+		store := &lifecycleStore{meters: map[uuid.UUID]*models.Meter{}}
+		olap := &failingOlap{createErr: errors.New("clickhouse is down")}
+		svc := NewMeterService(olap, store, nil, config.QueryCacheConfig{})
+
+		_, err := svc.CreateMeter(ctx, input)
+		require.Error(t, err)
+
+		m, err := store.GetMeterByIDorSlug(ctx, "requests")
+		require.NoError(t, err)
+		assert.Equal(t, models.MeterStatusFailed, m.Status)
+		assert.Equal(t, "clickhouse is down", m.StatusError)
+
+		_, err = svc.QueryMeter(ctx, models.QueryMeterParams{MeterSlug: "requests"})
+		assert.Error(t, err)
+
+		olap.createErr = nil
+		m, err = svc.RetryMeter(ctx, "requests")
+		require.NoError(t, err)
+		assert.Equal(t, models.MeterStatusActive, m.Status)
+		assert.Empty(t, m.StatusError)
+
+		_, err = svc.RetryMeter(ctx, "requests")
+		assert.Error(t, err, "active meters cannot be retried")
+	})
+
+	t.Run("Rejects invalid event filters", func(t *testing.T) {
+		store := &lifecycleStore{meters: map[uuid.UUID]*models.Meter{}}
+		svc := NewMeterService(&failingOlap{}, store, nil, config.QueryCacheConfig{})
+
+		invalid := input
+		invalid.Filter = &models.FilterExpression{Property: "status", Operator: models.FilterOpGt, Value: "abc"}
+		_, err := svc.CreateMeter(ctx, invalid)
+		require.Error(t, err)
+		assert.Empty(t, store.meters, "invalid meters are not stored")
+	})
+
+	t.Run("Recreates views with the stored event filter", func(t *testing.T) {
+		store := &lifecycleStore{meters: map[uuid.UUID]*models.Meter{}}
+		olap := &failingOlap{createErr: errors.New("clickhouse is down")}
+		svc := NewMeterService(olap, store, nil, config.QueryCacheConfig{})
+
+		filtered := input
+		filtered.Filter = &models.FilterExpression{Property: "region", Operator: models.FilterOpEq, Value: "eu"}
+		_, err := svc.CreateMeter(ctx, filtered)
+		require.Error(t, err)
+
+		olap.createErr = nil
+		_, err = svc.RetryMeter(ctx, "requests")
+		require.NoError(t, err)
+		require.Len(t, olap.created, 2)
+		assert.Equal(t, filtered.Filter, olap.created[1].Filter)
+	})
+
+	t.Run("Leaves meters that fail to delete as deleting", func(t *testing.T) {
+		store := &lifecycleStore{meters: map[uuid.UUID]*models.Meter{}}
+		svc := NewMeterService(&failingOlap{}, store, nil, config.QueryCacheConfig{})
+		_, err := svc.CreateMeter(ctx, input)
+		require.NoError(t, err)
+
+		store.deleteErr = errors.New("connection reset")
+		require.Error(t, svc.DeleteMeter(ctx, "requests"))
+
+		m, err := store.GetMeterByIDorSlug(ctx, "requests")
+		require.NoError(t, err)
+		assert.Equal(t, models.MeterStatusDeleting, m.Status)
+		assert.Equal(t, 1, m.ReconcileAttempts)
+	})
+}
+
+func TestMeterReconciler_Reconcile(t *testing.T) {
+	ctx := context.Background()
+	pending := &models.Meter{Base: models.Base{ID: uuid.New()}, Slug: "pending", Type: models.MeterTypeStandard, Status: models.MeterStatusPending, TenantSlug: "tenant-a"}
+	deleting := &models.Meter{Base: models.Base{ID: uuid.New()}, Slug: "deleting", Type: models.MeterTypeStandard, Status: models.MeterStatusDeleting, TenantSlug: "tenant-b"}
+	active := &models.Meter{Base: models.Base{ID: uuid.New()}, Slug: "active", Type: models.MeterTypeStandard, Status: models.MeterStatusActive, TenantSlug: "tenant-a"}
+
+	log, err := logger.NewLogger(&config.LoggerConfig{Level: config.INFO, Mode: "dev"})
+	require.NoError(t, err)
+
+	t.Run("Activates pending meters and removes deleting meters", func(t *testing.T) {
+		store := &lifecycleStore{meters: map[uuid.UUID]*models.Meter{
+			pending.ID: ptr(*pending), deleting.ID: ptr(*deleting), active.ID: ptr(*active),
+		}}
+		svc := NewMeterService(&failingOlap{}, store, nil, config.QueryCacheConfig{})
+		reconciler := NewMeterReconciler(svc, store, config.MeterReconcilerConfig{MaxAttempts: 3, BatchSize: 10}, log)
+
+		reconciled, err := reconciler.Reconcile(ctx)
+		require.NoError(t, err)
+		assert.Len(t, reconciled, 2)
+		assert.Equal(t, models.MeterStatusActive, store.meters[pending.ID].Status)
+		assert.NotContains(t, store.meters, deleting.ID)
+		assert.Contains(t, store.meters, active.ID)
+	})
+
+	t.Run("Marks meters failed after the maximum attempts", func(t *testing.T) {
+		store := &lifecycleStore{meters: map[uuid.UUID]*models.Meter{pending.ID: ptr(*pending)}}
+		olap := &failingOlap{createErr: errors.New("clickhouse is down")}
+		svc := NewMeterService(olap, store, nil, config.QueryCacheConfig{})
+		reconciler := NewMeterReconciler(svc, store, config.MeterReconcilerConfig{MaxAttempts: 2, BatchSize: 10}, log)
+
+		_, err := reconciler.Reconcile(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, models.MeterStatusPending, store.meters[pending.ID].Status)
+		assert.Equal(t, 1, store.meters[pending.ID].ReconcileAttempts)
+
+		reconciled, err := reconciler.Reconcile(ctx)
+		require.NoError(t, err)
+		require.Len(t, reconciled, 1)
+		assert.Equal(t, models.MeterStatusFailed, reconciled[0].Status)
+		assert.Equal(t, "clickhouse is down", reconciled[0].StatusError)
+
+		reconciled, err = reconciler.Reconcile(ctx)
+		require.NoError(t, err)
+		assert.Empty(t, reconciled, "failed meters are left for the user to retry or delete")
+	})
+
+	t.Run("Keeps the delete intent of meters that fail to be removed", func(t *testing.T) {
+		store := &lifecycleStore{meters: map[uuid.UUID]*models.Meter{deleting.ID: ptr(*deleting)}}
+		olap := &failingOlap{deleteErr: errors.New("clickhouse is down")}
+		svc := NewMeterService(olap, store, nil, config.QueryCacheConfig{})
+		reconciler := NewMeterReconciler(svc, store, config.MeterReconcilerConfig{MaxAttempts: 2, BatchSize: 10}, log)
+
+		for range 2 {
+			_, err := reconciler.Reconcile(ctx)
+			require.NoError(t, err)
+		}
+		assert.Equal(t, models.MeterStatusDeleteFailed, store.meters[deleting.ID].Status)
+		assert.Equal(t, "clickhouse is down", store.meters[deleting.ID].StatusError)
+
+		reconciled, err := reconciler.Reconcile(ctx)
+		require.NoError(t, err)
+		assert.Empty(t, reconciled, "delete_failed meters are left for the user to retry")
+
+		tenantCtx := context.WithValue(ctx, constants.TenantSlugKey, deleting.TenantSlug)
+		_, err = svc.RetryMeter(tenantCtx, deleting.Slug)
+		require.Error(t, err)
+		assert.Equal(t, models.MeterStatusDeleteFailed, store.meters[deleting.ID].Status)
+
+		olap.deleteErr = nil
+		retried, err := svc.RetryMeter(tenantCtx, deleting.Slug)
+		require.NoError(t, err)
+		assert.Equal(t, models.MeterStatusDeleting, retried.Status)
+		assert.NotContains(t, store.meters, deleting.ID)
+		assert.Empty(t, olap.created, "retrying a failed delete must not recreate the view")
+	})
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
@@ -0,0 +1,173 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	domainerrors "github.com/redcardinal-io/metering/domain/errors"
+	"github.com/redcardinal-io/metering/domain/models"
+)
+
+// validateQueryMode checks that cumulative and comparison queries can be answered for the meter.
+func validateQueryMode(m *models.Meter, arg models.QueryMeterParams) error {
+	invalid := func(err error) error {
+		return domainerrors.New(
+			err,
+			domainerrors.EINVALID,
+			"invalid meter query",
+			domainerrors.WithOperation("MeterService.QueryMeter"),
+		)
+	}
+
+	if arg.Cumulative {
+		if arg.WindowSize == nil {
+			return invalid(fmt.Errorf("cumulative queries require window_size"))
+		}
+		if !m.IsDerived() && m.Aggregation != models.AggregationSum && m.Aggregation != models.AggregationCount {
+			return invalid(fmt.Errorf("running totals of %s meters are not meaningful", m.Aggregation))
+		}
+		if len(arg.GroupBy) == 0 && (arg.Limit != nil || arg.Offset != nil || arg.Cursor != "") {
+			// Without groups, pages split the series the totals run over
+			return invalid(fmt.Errorf("cumulative queries without group_by cannot be paginated"))
+		}
+	}
+
+	if arg.Compare != "" {
+		if !models.IsValidCompareMode(arg.Compare) {
+			return invalid(fmt.Errorf("invalid compare mode: %s", arg.Compare))
+		}
+		if arg.From == nil || arg.To == nil {
+			return invalid(fmt.Errorf("compare requires from and to"))
+		}
+		if arg.IncludeOthers {
+			return invalid(fmt.Errorf("compare cannot be combined with include_others"))
+		}
+	}
+	return nil
+}
+
+// accumulateWindows replaces the value of each row with the running total of its group over the
+// windows of the result, leaving the order of the rows unchanged. Rows with unknown values carry
+// the total forward.
+func accumulateWindows(result *models.QueryMeterResult, arg models.QueryMeterParams) {
+	groups := make(map[string][]int)
+	for i, row := range result.Data {
+		key := rowKey(row, arg.GroupBy)
+		groups[key] = append(groups[key], i)
+	}
+
+	for _, indexes := range groups {
+		sort.SliceStable(indexes, func(a, b int) bool {
+			return result.Data[indexes[a]].WindowStart.Before(result.Data[indexes[b]].WindowStart)
+		})
+		var total float64
+		for _, i := range indexes {
+			row := &result.Data[i]
+			if !row.Null {
+				total += row.Value
+			}
+			row.Value, row.Null = total, false
+		}
+	}
+}
+
+// compareWithPreviousPeriod runs the query again over the period of the same length ending at
+// From and adds the previous value, the change and the percent change to each row. Rows are
+// matched by group and, for windowed queries, by window shifted by one period. Groups or windows
+// without a counterpart compare with zero; when the result is not paginated, rows only present in
+// the previous period are added with a zero value.
+func (s *MeterService) compareWithPreviousPeriod(ctx context.Context, m *models.Meter, arg models.QueryMeterParams, result *models.QueryMeterResult) error {
+	period := arg.To.Sub(*arg.From)
+	previousFrom, previousTo := arg.From.Add(-period), *arg.From
+
+	previousArg := arg
+	previousArg.From, previousArg.To = &previousFrom, &previousTo
+	previousArg.Compare = ""
+	// Every group of the current page needs its previous value, wherever it ranked before
+	previousArg.Limit, previousArg.Offset, previousArg.Cursor = nil, nil, ""
+	previous, err := s.runQuery(ctx, m, previousArg)
+	if err != nil {
+		return err
+	}
+
+	matchKey := func(row models.QueryMeterRow, shift bool) string {
+		key := rowKey(row, arg.GroupBy)
+		if arg.WindowSize != nil {
+			start := row.WindowStart
+			if shift {
+				start = start.Add(period)
+			}
+			key += fmt.Sprintf("\x00%d", start.Unix())
+		}
+		return key
+	}
+
+	previousRows := make(map[string]models.QueryMeterRow, len(previous.Data))
+	order := make([]string, 0, len(previous.Data))
+	for _, row := range previous.Data {
+		key := matchKey(row, true)
+		previousRows[key] = row
+		order = append(order, key)
+	}
+
+	matched := make(map[string]struct{}, len(result.Data))
+	for i := range result.Data {
+		row := &result.Data[i]
+		key := matchKey(*row, false)
+		matched[key] = struct{}{}
+		previousRow, ok := previousRows[key]
+		if row.Null || (ok && previousRow.Null) {
+			continue
+		}
+		compareRow(row, previousRow.Value)
+	}
+
+	paginated := arg.Limit != nil || arg.Offset != nil || arg.Cursor != ""
+	if !paginated {
+		for _, key := range order {
+			if _, ok := matched[key]; ok {
+				continue
+			}
+			previousRow := previousRows[key]
+			if previousRow.Null {
+				continue
+			}
+			row := models.QueryMeterRow{
+				WindowStart: previousRow.WindowStart.Add(period),
+				WindowEnd:   previousRow.WindowEnd.Add(period),
+				GroupBy:     previousRow.GroupBy,
+			}
+			if arg.WindowSize == nil {
+				row.WindowStart, row.WindowEnd = *arg.From, *arg.To
+			}
+			compareRow(&row, previousRow.Value)
+			result.Data = append(result.Data, row)
+		}
+		if arg.OrderBy != nil {
+			sortQueryRows(result.Data, arg.OrderBy)
+		}
+	}
+
+	result.PreviousFrom, result.PreviousTo = &previousFrom, &previousTo
+	return nil
+}
+
+// compareRow sets the comparison fields of a row against the previous value.
+func compareRow(row *models.QueryMeterRow, previous float64) {
+	change := row.Value - previous
+	row.PreviousValue = &previous
+	row.Change = &change
+	if previous != 0 {
+		percent := change / previous * 100
+		row.ChangePercent = &percent
+	}
+}
+
+// rowKey identifies the series a row belongs to, keeping the others bucket apart from groups.
+func rowKey(row models.QueryMeterRow, groupBy []string) string {
+	if row.Others {
+		return "\x00others"
+	}
+	return groupKey(row, groupBy)
+}
@@ -0,0 +1,141 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/redcardinal-io/metering/domain/models"
+	"github.com/redcardinal-io/metering/domain/pkg/config"
+)
+
+// periodOlap returns canned query results per queried From
+type periodOlap struct {
+	fakeOlap
+	byFrom map[time.Time][]models.QueryMeterRow
+}
+
+func (p *periodOlap) QueryMeter(ctx context.Context, arg models.QueryMeterParams, meter *models.Meter) (*models.QueryMeterResult, error) {
+	rows := p.byFrom[*arg.From]
+	return &models.QueryMeterResult{Data: append([]models.QueryMeterRow(nil), rows...)}, nil
+}
+
+func TestMeterService_QueryModes(t *testing.T) {
+	ctx := context.Background()
+	day1 := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	day2 := day1.Add(24 * time.Hour)
+	day3 := day2.Add(24 * time.Hour)
+	windowSize := models.WindowSizeDay
+	store := &fakeMeterStore{meters: map[string]*models.Meter{
+		"api_calls": {Slug: "api_calls", Type: models.MeterTypeStandard, Status: models.MeterStatusActive, Aggregation: models.AggregationCount, Properties: []string{"region"}},
+		"latency":   {Slug: "latency", Type: models.MeterTypeStandard, Status: models.MeterStatusActive, Aggregation: models.AggregationAvg},
+	}}
+	row := func(start time.Time, region string, value float64) models.QueryMeterRow {
+		return models.QueryMeterRow{
+			WindowStart: start,
+			WindowEnd:   start.Add(24 * time.Hour),
+			GroupBy:     map[string]string{"region": region},
+			Value:       value,
+		}
+	}
+
+	t.Run("Cumulative returns running totals per group", func(t *testing.T) {
+		olap := &periodOlap{byFrom: map[time.Time][]models.QueryMeterRow{
+			day1: {row(day1, "eu", 1), row(day1, "us", 10), row(day2, "eu", 2), row(day2, "us", 20)},
+		}}
+		svc := NewMeterService(olap, store, nil, config.QueryCacheConfig{})
+
+		result, err := svc.QueryMeter(ctx, models.QueryMeterParams{
+			MeterSlug:  "api_calls",
+			From:       &day1,
+			To:         &day3,
+			GroupBy:    []string{"region"},
+			WindowSize: &windowSize,
+			Cumulative: true,
+		})
+		require.NoError(t, err)
+		require.Len(t, result.Data, 4)
+		values := []float64{result.Data[0].Value, result.Data[1].Value, result.Data[2].Value, result.Data[3].Value}
+		assert.Equal(t, []float64{1, 10, 3, 30}, values)
+	})
+
+	t.Run("Compare adds the previous period to each row", func(t *testing.T) {
+		previous := day1.Add(-48 * time.Hour)
+		olap := &periodOlap{byFrom: map[time.Time][]models.QueryMeterRow{
+			day1:     {row(day1, "eu", 15), row(day1, "us", 5)},
+			previous: {row(previous, "eu", 10), row(previous, "ap", 4)},
+		}}
+		svc := NewMeterService(olap, store, nil, config.QueryCacheConfig{})
+
+		result, err := svc.QueryMeter(ctx, models.QueryMeterParams{
+			MeterSlug: "api_calls",
+			From:      &day1,
+			To:        &day3,
+			GroupBy:   []string{"region"},
+			Compare:   models.CompareModePreviousPeriod,
+		})
+		require.NoError(t, err)
+		require.Len(t, result.Data, 3)
+		assert.Equal(t, previous, *result.PreviousFrom)
+		assert.Equal(t, day1, *result.PreviousTo)
+
+		eu := result.Data[0]
+		assert.Equal(t, float64(10), *eu.PreviousValue)
+		assert.Equal(t, float64(5), *eu.Change)
+		assert.Equal(t, float64(50), *eu.ChangePercent)
+
+		us := result.Data[1]
+		assert.Equal(t, float64(0), *us.PreviousValue)
+		assert.Nil(t, us.ChangePercent, "no percent change from zero")
+
+		ap := result.Data[2]
+		assert.Equal(t, "ap", ap.GroupBy["region"])
+		assert.Equal(t, float64(0), ap.Value)
+		assert.Equal(t, float64(-4), *ap.Change)
+		assert.Equal(t, float64(-100), *ap.ChangePercent)
+		assert.Equal(t, day1, ap.WindowStart)
+	})
+
+	t.Run("Compare matches windows shifted by the period", func(t *testing.T) {
+		previous := day1.Add(-48 * time.Hour)
+		olap := &periodOlap{byFrom: map[time.Time][]models.QueryMeterRow{
+			day1:     {row(day1, "eu", 3), row(day2, "eu", 6)},
+			previous: {row(previous, "eu", 2), row(previous.Add(24*time.Hour), "eu", 3)},
+		}}
+		svc := NewMeterService(olap, store, nil, config.QueryCacheConfig{})
+
+		result, err := svc.QueryMeter(ctx, models.QueryMeterParams{
+			MeterSlug:  "api_calls",
+			From:       &day1,
+			To:         &day3,
+			GroupBy:    []string{"region"},
+			WindowSize: &windowSize,
+			Compare:    models.CompareModePreviousPeriod,
+		})
+		require.NoError(t, err)
+		require.Len(t, result.Data, 2)
+		assert.Equal(t, float64(2), *result.Data[0].PreviousValue)
+		assert.Equal(t, float64(3), *result.Data[1].PreviousValue)
+		assert.Equal(t, float64(100), *result.Data[1].ChangePercent)
+	})
+
+	t.Run("Rejects unsupported combinations", func(t *testing.T) {
+		svc := NewMeterService(&periodOlap{}, store, nil, config.QueryCacheConfig{})
+		limit := 10
+
+		for name, params := range map[string]models.QueryMeterParams{
+			"cumulative without windows": {MeterSlug: "api_calls", From: &day1, To: &day3, Cumulative: true},
+			"cumulative average":         {MeterSlug: "latency", From: &day1, To: &day3, WindowSize: &windowSize, Cumulative: true},
+			"cumulative ungrouped page":  {MeterSlug: "api_calls", From: &day1, To: &day3, WindowSize: &windowSize, Limit: &limit, Cumulative: true},
+			"compare without range":      {MeterSlug: "api_calls", From: &day1, Compare: models.CompareModePreviousPeriod},
+			"compare with others":        {MeterSlug: "api_calls", From: &day1, To: &day3, GroupBy: []string{"region"}, Limit: &limit, IncludeOthers: true, Compare: models.CompareModePreviousPeriod},
+			"unknown compare mode":       {MeterSlug: "api_calls", From: &day1, To: &day3, Compare: "previous_year"},
+		} {
+			_, err := svc.QueryMeter(ctx, params)
+			assert.Error(t, err, name)
+		}
+	})
+}
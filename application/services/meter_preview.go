@@ -0,0 +1,162 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	domainerrors "github.com/redcardinal-io/metering/domain/errors"
+	"github.com/redcardinal-io/metering/domain/models"
+)
+
+const (
+	// MaxPreviewEvents bounds the number of sample events a preview accepts
+	MaxPreviewEvents = 1000
+	// DefaultPreviewLimit is the number of rows returned when a preview has no limit
+	DefaultPreviewLimit = 100
+	// MaxPreviewLimit bounds the number of rows a preview returns
+	MaxPreviewLimit = 1000
+	// MaxPreviewRange bounds the range of stored events a preview scans
+	MaxPreviewRange = 31 * 24 * time.Hour
+	// previewHighCardinality is the number of distinct values above which a property is flagged
+	previewHighCardinality = 1000
+)
+
+// PreviewMeter dry-runs a meter definition against sample events or a range of stored events and
+// reports the rows the meter would produce along with warnings about the definition.
+// Nothing is written to the store or the OLAP database.
+func (s *MeterService) PreviewMeter(ctx context.Context, input models.PreviewMeterInput) (*models.MeterPreview, error) {
+	if err := validatePreview(&input); err != nil {
+		return nil, err
+	}
+
+	preview, err := s.olap.PreviewMeter(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	preview.Warnings = previewWarnings(input.Meter, preview.Stats)
+	return preview, nil
+}
+
+// validatePreview checks the meter definition and the preview source and applies the default limit.
+func validatePreview(input *models.PreviewMeterInput) error {
+	invalid := func(err error) error {
+		return domainerrors.New(
+			err,
+			domainerrors.EINVALID,
+			"invalid meter preview",
+			domainerrors.WithOperation("MeterService.PreviewMeter"),
+		)
+	}
+
+	m := &input.Meter
+	if m.Type == models.MeterTypeDerived {
+		return invalid(fmt.Errorf("derived meters cannot be previewed"))
+	}
+	if !models.ValidateAggregation(string(m.Aggregation)) {
+		return invalid(fmt.Errorf("invalid aggregation type: %s", m.Aggregation))
+	}
+	if m.Aggregation == models.AggregationCount {
+		m.ValueProperty = ""
+	}
+	if err := normalizeEventTypes(m); err != nil {
+		return invalid(err)
+	}
+	if m.Filter != nil {
+		if err := m.Filter.ValidateEventFilter(); err != nil {
+			return invalid(err)
+		}
+	}
+	if m.MeterSlug == "" {
+		// The slug only names the view in the returned SQL
+		m.MeterSlug = "preview"
+	}
+	if m.Granularity == "" {
+		m.Granularity = models.DefaultGranularity
+	} else if !models.IsValidGranularity(m.Granularity) {
+		return invalid(fmt.Errorf("invalid granularity: %s", m.Granularity))
+	}
+
+	hasRange := input.From != nil || input.To != nil
+	switch {
+	case len(input.Events) > 0 && hasRange:
+		return invalid(fmt.Errorf("events and from/to cannot be used together"))
+	case len(input.Events) > MaxPreviewEvents:
+		return invalid(fmt.Errorf("at most %d sample events can be previewed", MaxPreviewEvents))
+	case len(input.Events) == 0 && (input.From == nil || input.To == nil):
+		return invalid(fmt.Errorf("either events or both from and to are required"))
+	case hasRange && !input.From.Before(*input.To):
+		return invalid(fmt.Errorf("from must be before to"))
+	case hasRange && input.To.Sub(*input.From) > MaxPreviewRange:
+		return invalid(fmt.Errorf("preview range cannot exceed %s", MaxPreviewRange))
+	}
+
+	for _, event := range input.Events {
+		if _, err := time.Parse(time.RFC3339, event.Timestamp); err != nil {
+			return invalid(fmt.Errorf("invalid timestamp %q for sample event", event.Timestamp))
+		}
+	}
+
+	if input.Limit == 0 {
+		input.Limit = DefaultPreviewLimit
+	} else if input.Limit < 0 || input.Limit > MaxPreviewLimit {
+		return invalid(fmt.Errorf("limit must be between 1 and %d", MaxPreviewLimit))
+	}
+	return nil
+}
+
+// previewWarnings derives warnings about a meter definition from the preview statistics.
+func previewWarnings(m models.CreateMeterInput, stats models.MeterPreviewStats) []models.MeterPreviewWarning {
+	warnings := make([]models.MeterPreviewWarning, 0)
+	if stats.Events == 0 {
+		return append(warnings, models.MeterPreviewWarning{
+			Code:    models.PreviewWarningNoEvents,
+			Message: fmt.Sprintf("no events of type %s were found", strings.Join(m.EventTypes, ", ")),
+		})
+	}
+
+	if stats.MissingValueProperty > 0 {
+		warnings = append(warnings, models.MeterPreviewWarning{
+			Code:     models.PreviewWarningMissingValueProperty,
+			Message:  fmt.Sprintf("%d of %d events do not have the value property", stats.MissingValueProperty, stats.Events),
+			Property: m.ValueProperty,
+			Count:    stats.MissingValueProperty,
+		})
+	}
+	if stats.NonNumericValues > 0 {
+		warnings = append(warnings, models.MeterPreviewWarning{
+			Code:     models.PreviewWarningNonNumericValue,
+			Message:  fmt.Sprintf("%d of %d events have a non-numeric value property", stats.NonNumericValues, stats.Events),
+			Property: m.ValueProperty,
+			Count:    stats.NonNumericValues,
+		})
+	}
+
+	properties := make([]string, 0, len(stats.Properties))
+	for property := range stats.Properties {
+		properties = append(properties, property)
+	}
+	sort.Strings(properties)
+	for _, property := range properties {
+		propertyStats := stats.Properties[property]
+		if propertyStats.Missing > 0 {
+			warnings = append(warnings, models.MeterPreviewWarning{
+				Code:     models.PreviewWarningMissingProperty,
+				Message:  fmt.Sprintf("%d of %d events do not have property %s", propertyStats.Missing, stats.Events, property),
+				Property: property,
+				Count:    propertyStats.Missing,
+			})
+		}
+		if propertyStats.Distinct > previewHighCardinality {
+			warnings = append(warnings, models.MeterPreviewWarning{
+				Code:     models.PreviewWarningHighCardinality,
+				Message:  fmt.Sprintf("property %s has %d distinct values, which multiplies the rows stored per window", property, propertyStats.Distinct),
+				Property: property,
+				Count:    propertyStats.Distinct,
+			})
+		}
+	}
+	return warnings
+}
@@ -0,0 +1,89 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/redcardinal-io/metering/domain/models"
+	"github.com/redcardinal-io/metering/domain/pkg/config"
+)
+
+func TestMeterService_PreviewMeter(t *testing.T) {
+	ctx := context.Background()
+	definition := models.CreateMeterInput{
+		MeterSlug:     "tokens",
+		EventType:     "completion",
+		ValueProperty: "tokens",
+		Properties:    []string{"model", "request_id"},
+		Aggregation:   models.AggregationSum,
+	}
+	events := []*models.Event{
+		{Type: "completion", Organization: "org-a", User: "user-1", Timestamp: "2025-06-01T10:00:00Z", Properties: `{"tokens":12}`},
+	}
+
+	olap := &fakeOlap{}
+	svc := NewMeterService(olap, &fakeMeterStore{}, nil, config.QueryCacheConfig{})
+
+	t.Run("Reports warnings from the preview stats", func(t *testing.T) {
+		olap.preview = &models.MeterPreview{Stats: models.MeterPreviewStats{
+			Events:               50,
+			MissingValueProperty: 2,
+			NonNumericValues:     1,
+			Properties: map[string]models.MeterPreviewPropertyStats{
+				"request_id": {Distinct: 5000},
+				"model":      {Missing: 3, Distinct: 2},
+			},
+		}}
+
+		preview, err := svc.PreviewMeter(ctx, models.PreviewMeterInput{Meter: definition, Events: events})
+		require.NoError(t, err)
+
+		codes := make([]models.MeterPreviewWarningCode, 0, len(preview.Warnings))
+		for _, w := range preview.Warnings {
+			codes = append(codes, w.Code)
+		}
+		assert.Equal(t, []models.MeterPreviewWarningCode{
+			models.PreviewWarningMissingValueProperty,
+			models.PreviewWarningNonNumericValue,
+			models.PreviewWarningMissingProperty,
+			models.PreviewWarningHighCardinality,
+		}, codes)
+		assert.Equal(t, "model", preview.Warnings[2].Property)
+		assert.Equal(t, 5000, preview.Warnings[3].Count)
+	})
+
+	t.Run("Warns when no events match", func(t *testing.T) {
+		olap.preview = &models.MeterPreview{}
+		preview, err := svc.PreviewMeter(ctx, models.PreviewMeterInput{Meter: definition, Events: events})
+		require.NoError(t, err)
+		require.Len(t, preview.Warnings, 1)
+		assert.Equal(t, models.PreviewWarningNoEvents, preview.Warnings[0].Code)
+	})
+
+	from := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(24 * time.Hour)
+	tooLate := from.Add(MaxPreviewRange + time.Hour)
+	derived := models.CreateMeterInput{Type: models.MeterTypeDerived, Formula: "a / b"}
+	noValue := definition
+	noValue.ValueProperty = ""
+
+	invalid := map[string]models.PreviewMeterInput{
+		"derived meter":           {Meter: derived, Events: events},
+		"missing value property":  {Meter: noValue, Events: events},
+		"no events nor range":     {Meter: definition},
+		"events and range":        {Meter: definition, Events: events, From: &from, To: &to},
+		"range too long":          {Meter: definition, From: &from, To: &tooLate},
+		"inverted range":          {Meter: definition, From: &to, To: &from},
+		"limit above the maximum": {Meter: definition, Events: events, Limit: MaxPreviewLimit + 1},
+	}
+	for name, input := range invalid {
+		t.Run("Rejects "+name, func(t *testing.T) {
+			_, err := svc.PreviewMeter(ctx, input)
+			assert.Error(t, err)
+		})
+	}
+}
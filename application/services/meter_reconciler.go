@@ -0,0 +1,106 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/redcardinal-io/metering/application/repositories"
+	"github.com/redcardinal-io/metering/domain/models"
+	"github.com/redcardinal-io/metering/domain/pkg/config"
+	"github.com/redcardinal-io/metering/domain/pkg/constants"
+	"github.com/redcardinal-io/metering/domain/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// MeterReconciler drives meters left pending or deleting by interrupted or failed requests to a
+// consistent state: pending meters get their OLAP view and become active, deleting meters lose
+// their view and their row. Meters that keep failing are marked failed, or delete_failed when they
+// were being removed, with the last error.
+type MeterReconciler struct {
+	meters *MeterService
+	store  repositories.MeterStoreRepository
+	config config.MeterReconcilerConfig
+	logger *logger.Logger
+	now    func() time.Time
+}
+
+// NewMeterReconciler creates a MeterReconciler acting through the given meter service.
+func NewMeterReconciler(meters *MeterService, store repositories.MeterStoreRepository, cfg config.MeterReconcilerConfig, logger *logger.Logger) *MeterReconciler {
+	return &MeterReconciler{
+		meters: meters,
+		store:  store,
+		config: cfg,
+		logger: logger,
+		now:    time.Now,
+	}
+}
+
+// Run reconciles meters every configured interval until the context is cancelled.
+func (r *MeterReconciler) Run(ctx context.Context) {
+	if r.config.Interval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(r.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := r.Reconcile(ctx); err != nil {
+				r.logger.Error("failed to reconcile meters", zap.Error(err))
+			}
+		}
+	}
+}
+
+// Reconcile makes a single pass over the meters that are pending or deleting and returns the
+// meters it processed, with their resulting state. Deleted meters are returned as deleting.
+func (r *MeterReconciler) Reconcile(ctx context.Context) ([]*models.Meter, error) {
+	meters, err := r.store.ListMetersByStatus(
+		ctx,
+		[]models.MeterStatus{models.MeterStatusPending, models.MeterStatusDeleting},
+		r.now().Add(-r.config.GracePeriod),
+		r.config.BatchSize,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	reconciled := make([]*models.Meter, 0, len(meters))
+	for _, m := range meters {
+		tenantCtx := context.WithValue(ctx, constants.TenantSlugKey, m.TenantSlug)
+		reconciled = append(reconciled, r.reconcileMeter(tenantCtx, m))
+	}
+	return reconciled, nil
+}
+
+// reconcileMeter moves a single meter towards its target state and returns its new state.
+func (r *MeterReconciler) reconcileMeter(ctx context.Context, m *models.Meter) *models.Meter {
+	var err error
+	switch m.Status {
+	case models.MeterStatusPending:
+		var active *models.Meter
+		if active, err = r.meters.activateMeter(ctx, m); err == nil {
+			r.logger.Info("activated pending meter", zap.String("tenant", m.TenantSlug), zap.String("meter", m.Slug))
+			notify(ctx, r.meters.notifiers, models.NotificationMeterCreated, active)
+			return active
+		}
+	case models.MeterStatusDeleting:
+		if err = r.meters.removeMeter(ctx, m); err == nil {
+			r.meters.InvalidateQueryCache(ctx, m.Slug)
+			r.logger.Info("removed deleting meter", zap.String("tenant", m.TenantSlug), zap.String("meter", m.Slug))
+			return m
+		}
+	default:
+		return m
+	}
+
+	r.logger.Error("failed to reconcile meter",
+		zap.String("tenant", m.TenantSlug),
+		zap.String("meter", m.Slug),
+		zap.String("status", string(m.Status)),
+		zap.Error(err))
+	return r.meters.recordMeterFailure(ctx, m, m.Status, err, r.config.MaxAttempts)
+}
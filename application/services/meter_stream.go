@@ -0,0 +1,263 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+
+	domainerrors "github.com/redcardinal-io/metering/domain/errors"
+	"github.com/redcardinal-io/metering/domain/models"
+	"github.com/redcardinal-io/metering/domain/pkg/config"
+	"github.com/redcardinal-io/metering/domain/pkg/constants"
+	"github.com/redcardinal-io/metering/domain/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// MeterStreamHub pushes live meter usage to subscribers. Subscribers of the same tenant, meter
+// and query parameters share a single feed that queries the meter every configured interval,
+// so the OLAP load grows with the number of distinct subscriptions rather than connections.
+type MeterStreamHub struct {
+	meters      *MeterService
+	config      config.MeterStreamConfig
+	logger      *logger.Logger
+	now         func() time.Time
+	mu          sync.Mutex
+	feeds       map[string]*usageFeed
+	subscribers int
+}
+
+// usageFeed queries one subscription and fans the results out to its subscribers
+type usageFeed struct {
+	key         string
+	ctx         context.Context
+	cancel      context.CancelFunc
+	params      models.QueryMeterParams
+	latest      *models.QueryMeterResult
+	subscribers map[*MeterSubscription]struct{}
+}
+
+// MeterSubscription receives the usage of a live meter stream. Updates holds at most the latest
+// result; results not read before the next one arrives are dropped.
+type MeterSubscription struct {
+	updates chan *models.QueryMeterResult
+	hub     *MeterStreamHub
+	feed    *usageFeed
+	once    sync.Once
+}
+
+// NewMeterStreamHub creates a MeterStreamHub querying meters through the given meter service.
+func NewMeterStreamHub(meters *MeterService, cfg config.MeterStreamConfig, logger *logger.Logger) *MeterStreamHub {
+	return &MeterStreamHub{
+		meters: meters,
+		config: cfg,
+		logger: logger,
+		now:    time.Now,
+		feeds:  make(map[string]*usageFeed),
+	}
+}
+
+// Heartbeat returns how often idle stream connections should be kept alive.
+func (h *MeterStreamHub) Heartbeat() time.Duration {
+	if h.config.Heartbeat <= 0 {
+		return 15 * time.Second
+	}
+	return h.config.Heartbeat
+}
+
+// Updates returns the channel the subscription's usage is delivered on. It is closed by Close.
+func (s *MeterSubscription) Updates() <-chan *models.QueryMeterResult {
+	return s.updates
+}
+
+// Close unsubscribes from the stream, stopping its feed when no subscriber is left.
+func (s *MeterSubscription) Close() {
+	s.once.Do(func() {
+		s.hub.unsubscribe(s)
+	})
+}
+
+// Subscribe validates the live query and subscribes to its feed, starting one if needed. The meter
+// may be named by ID or slug; both share the same feed. Without
+// From, each result covers the current window of WindowSize; with From, it covers From until now.
+// The subscription immediately receives the latest result.
+func (h *MeterStreamHub) Subscribe(ctx context.Context, params models.QueryMeterParams) (*MeterSubscription, error) {
+	if err := validateStreamParams(params); err != nil {
+		return nil, err
+	}
+	// Subscriptions naming the meter by ID or by slug share the feed of its slug
+	meter, err := h.meters.GetMeterIDorSlug(ctx, params.MeterSlug)
+	if err != nil {
+		return nil, err
+	}
+	params.MeterSlug = meter.Slug
+
+	tenantSlug := ctx.Value(constants.TenantSlugKey).(string)
+	key, err := streamKey(tenantSlug, params)
+	if err != nil {
+		return nil, err
+	}
+
+	h.mu.Lock()
+	if h.config.MaxSubscribers > 0 && h.subscribers >= h.config.MaxSubscribers {
+		h.mu.Unlock()
+		return nil, domainerrors.New(
+			fmt.Errorf("%d stream subscribers are already connected", h.subscribers),
+			domainerrors.EUNAVAILABLE,
+			"too many meter stream subscribers",
+			domainerrors.WithOperation("MeterStreamHub.Subscribe"),
+		)
+	}
+	feed, ok := h.feeds[key]
+	h.mu.Unlock()
+
+	var initial *models.QueryMeterResult
+	if !ok {
+		// Query outside the lock so an invalid or slow subscription does not hold up the others
+		initial, err = h.meters.QueryMeter(ctx, h.liveParams(params))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if feed, ok = h.feeds[key]; !ok {
+		feedCtx, cancel := context.WithCancel(context.WithValue(context.Background(), constants.TenantSlugKey, tenantSlug))
+		feed = &usageFeed{
+			key:         key,
+			ctx:         feedCtx,
+			cancel:      cancel,
+			params:      params,
+			latest:      initial,
+			subscribers: make(map[*MeterSubscription]struct{}),
+		}
+		h.feeds[key] = feed
+		go h.run(feed)
+	}
+
+	sub := &MeterSubscription{
+		updates: make(chan *models.QueryMeterResult, 1),
+		hub:     h,
+		feed:    feed,
+	}
+	feed.subscribers[sub] = struct{}{}
+	h.subscribers++
+	if feed.latest != nil {
+		sub.updates <- feed.latest
+	}
+	return sub, nil
+}
+
+func (h *MeterStreamHub) unsubscribe(sub *MeterSubscription) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	feed := sub.feed
+	delete(feed.subscribers, sub)
+	close(sub.updates)
+	h.subscribers--
+	if len(feed.subscribers) == 0 {
+		feed.cancel()
+		delete(h.feeds, feed.key)
+	}
+}
+
+// run queries the feed every interval until its last subscriber leaves.
+func (h *MeterStreamHub) run(feed *usageFeed) {
+	if h.config.Interval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(h.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-feed.ctx.Done():
+			return
+		case <-ticker.C:
+			h.refresh(feed)
+		}
+	}
+}
+
+// refresh queries the feed and publishes the result to its subscribers if it changed.
+func (h *MeterStreamHub) refresh(feed *usageFeed) {
+	result, err := h.meters.QueryMeter(feed.ctx, h.liveParams(feed.params))
+	if err != nil {
+		if feed.ctx.Err() == nil {
+			h.logger.Error("failed to refresh meter stream", zap.String("meter", feed.params.MeterSlug), zap.Error(err))
+		}
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if feed.ctx.Err() != nil || reflect.DeepEqual(feed.latest, result) {
+		return
+	}
+	feed.latest = result
+	for sub := range feed.subscribers {
+		// Replace an unread result so slow subscribers only see the latest usage
+		select {
+		case <-sub.updates:
+		default:
+		}
+		sub.updates <- result
+	}
+}
+
+// liveParams resolves the range of a live query at the current time. Windowed queries need both
+// ends, so they run up to the end of the current window, which is still open.
+func (h *MeterStreamHub) liveParams(params models.QueryMeterParams) models.QueryMeterParams {
+	if params.WindowSize == nil {
+		return params
+	}
+	windowStart := h.now().UTC().Truncate(params.WindowSize.Duration())
+	if params.From == nil {
+		params.From = &windowStart
+	}
+	to := windowStart.Add(params.WindowSize.Duration())
+	params.To = &to
+	return params
+}
+
+// validateStreamParams checks that the query describes live usage: it is open-ended and
+// either starts at From or follows the current window of WindowSize.
+func validateStreamParams(params models.QueryMeterParams) error {
+	invalid := func(err error) error {
+		return domainerrors.New(
+			err,
+			domainerrors.EINVALID,
+			"invalid meter stream",
+			domainerrors.WithOperation("MeterStreamHub.Subscribe"),
+		)
+	}
+	switch {
+	case params.To != nil:
+		return invalid(fmt.Errorf("live streams cannot end at to"))
+	case params.From == nil && params.WindowSize == nil:
+		return invalid(fmt.Errorf("either from or window_size is required"))
+	case params.WindowSize != nil && !models.IsValidWindowSize(params.WindowSize):
+		return invalid(fmt.Errorf("invalid window_size: %s", *params.WindowSize))
+	case params.Cursor != "" || params.Offset != nil:
+		return invalid(fmt.Errorf("live streams cannot be paginated"))
+	}
+	return nil
+}
+
+// streamKey identifies the feed shared by subscriptions with the same tenant and parameters.
+func streamKey(tenantSlug string, params models.QueryMeterParams) (string, error) {
+	encoded, err := json.Marshal(params)
+	if err != nil {
+		return "", domainerrors.New(
+			err,
+			domainerrors.EINVALID,
+			"invalid meter stream",
+			domainerrors.WithOperation("MeterStreamHub.Subscribe"),
+		)
+	}
+	return tenantSlug + "\x00" + string(encoded), nil
+}
@@ -0,0 +1,168 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/redcardinal-io/metering/domain/models"
+	"github.com/redcardinal-io/metering/domain/pkg/config"
+	"github.com/redcardinal-io/metering/domain/pkg/constants"
+	"github.com/redcardinal-io/metering/domain/pkg/logger"
+	"github.com/redcardinal-io/metering/infrastructure/clickhouse/meters"
+)
+
+// countingOlap counts meter queries and returns the configured usage for the queried range
+type countingOlap struct {
+	fakeOlap
+	queries int
+	value   float64
+}
+
+func (c *countingOlap) QueryMeter(ctx context.Context, arg models.QueryMeterParams, meter *models.Meter) (*models.QueryMeterResult, error) {
+	c.queries++
+	return &models.QueryMeterResult{Data: []models.QueryMeterRow{
+		{WindowStart: *arg.From, WindowEnd: arg.From.Add(arg.WindowSize.Duration()), Value: c.value},
+	}}, nil
+}
+
+func TestMeterStreamHub(t *testing.T) {
+	ctx := context.WithValue(context.Background(), constants.TenantSlugKey, "acme")
+	now := time.Date(2025, 6, 2, 12, 30, 0, 0, time.UTC)
+	hour := models.WindowSizeHour
+	params := models.QueryMeterParams{
+		MeterSlug:     "requests",
+		WindowSize:    &hour,
+		FilterGroupBy: map[string][]string{"organization": {"org-a"}},
+	}
+
+	meterID := uuid.New()
+
+	newHub := func(maxSubscribers int) (*MeterStreamHub, *countingOlap) {
+		olap := &countingOlap{value: 1}
+		meter := &models.Meter{Base: models.Base{ID: meterID}, Slug: "requests", Type: models.MeterTypeStandard, Status: models.MeterStatusActive, Aggregation: models.AggregationCount}
+		store := &fakeMeterStore{meters: map[string]*models.Meter{"requests": meter, meterID.String(): meter}}
+		meters := NewMeterService(olap, store, nil, config.QueryCacheConfig{})
+		// A zero interval leaves refreshes to the test
+		hub := NewMeterStreamHub(meters, config.MeterStreamConfig{MaxSubscribers: maxSubscribers}, &logger.Logger{Logger: zap.NewNop()})
+		hub.now = func() time.Time { return now }
+		return hub, olap
+	}
+
+	t.Run("Subscribers with the same parameters share a feed", func(t *testing.T) {
+		hub, olap := newHub(0)
+		first, err := hub.Subscribe(ctx, params)
+		require.NoError(t, err)
+		second, err := hub.Subscribe(ctx, params)
+		require.NoError(t, err)
+
+		assert.Equal(t, 1, olap.queries)
+		assert.Len(t, hub.feeds, 1)
+		for _, sub := range []*MeterSubscription{first, second} {
+			result := <-sub.Updates()
+			assert.Equal(t, time.Date(2025, 6, 2, 12, 0, 0, 0, time.UTC), result.Data[0].WindowStart)
+		}
+
+		feed := first.feed
+		hub.refresh(feed)
+		assert.Equal(t, 2, olap.queries)
+		assert.Empty(t, first.Updates(), "unchanged usage is not pushed")
+
+		olap.value = 5
+		hub.refresh(feed)
+		for _, sub := range []*MeterSubscription{first, second} {
+			result := <-sub.Updates()
+			assert.Equal(t, float64(5), result.Data[0].Value)
+		}
+
+		first.Close()
+		assert.Len(t, hub.feeds, 1)
+		second.Close()
+		assert.Empty(t, hub.feeds)
+		assert.Error(t, feed.ctx.Err(), "the feed stops with its last subscriber")
+	})
+
+	t.Run("Subscribers naming the meter by ID or slug share a feed", func(t *testing.T) {
+		hub, olap := newHub(0)
+		byID := params
+		byID.MeterSlug = meterID.String()
+
+		first, err := hub.Subscribe(ctx, params)
+		require.NoError(t, err)
+		defer first.Close()
+		second, err := hub.Subscribe(ctx, byID)
+		require.NoError(t, err)
+		defer second.Close()
+
+		assert.Equal(t, 1, olap.queries)
+		assert.Len(t, hub.feeds, 1)
+		assert.Equal(t, "requests", second.feed.params.MeterSlug)
+	})
+
+	t.Run("Different subjects get separate feeds", func(t *testing.T) {
+		hub, olap := newHub(0)
+		other := params
+		other.FilterGroupBy = map[string][]string{"organization": {"org-b"}}
+
+		first, err := hub.Subscribe(ctx, params)
+		require.NoError(t, err)
+		defer first.Close()
+		second, err := hub.Subscribe(ctx, other)
+		require.NoError(t, err)
+		defer second.Close()
+
+		assert.Equal(t, 2, olap.queries)
+		assert.Len(t, hub.feeds, 2)
+	})
+
+	t.Run("Live queries can be built for every window size", func(t *testing.T) {
+		hub, _ := newHub(0)
+		from := now.Add(-3 * time.Hour)
+		for _, windowSize := range []models.WindowSize{models.WindowSizeMinute, models.WindowSizeHour, models.WindowSizeDay} {
+			for _, start := range []*time.Time{nil, &from} {
+				live := params
+				live.WindowSize = &windowSize
+				live.From = start
+				resolved := hub.liveParams(live)
+				require.NotNil(t, resolved.To)
+				assert.True(t, resolved.To.After(now), "the current window is included")
+
+				query := meters.QueryMeter{
+					TenantSlug:    "acme",
+					MeterSlug:     resolved.MeterSlug,
+					FilterGroupBy: resolved.FilterGroupBy,
+					From:          resolved.From,
+					To:            resolved.To,
+					WindowSize:    resolved.WindowSize,
+					Aggregation:   models.AggregationCount,
+				}
+				_, _, err := query.ToSQL()
+				assert.NoError(t, err, "window size %s", windowSize)
+			}
+		}
+	})
+
+	t.Run("Rejects invalid streams", func(t *testing.T) {
+		hub, _ := newHub(1)
+		to := now
+
+		_, err := hub.Subscribe(ctx, models.QueryMeterParams{MeterSlug: "requests"})
+		assert.Error(t, err, "either from or window_size is required")
+
+		bounded := params
+		bounded.To = &to
+		_, err = hub.Subscribe(ctx, bounded)
+		assert.Error(t, err)
+
+		sub, err := hub.Subscribe(ctx, params)
+		require.NoError(t, err)
+		defer sub.Close()
+		_, err = hub.Subscribe(ctx, params)
+		assert.Error(t, err, "subscribers are limited")
+	})
+}
@@ -0,0 +1,29 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redcardinal-io/metering/application/repositories"
+	"github.com/redcardinal-io/metering/domain/models"
+	"github.com/redcardinal-io/metering/domain/pkg/constants"
+)
+
+// notify publishes a change of the tenant of the context to every notifier.
+func notify(ctx context.Context, notifiers []repositories.NotifierRepository, notificationType models.NotificationType, data any) {
+	if len(notifiers) == 0 {
+		return
+	}
+	tenantSlug, _ := ctx.Value(constants.TenantSlugKey).(string)
+	notification := models.Notification{
+		ID:         uuid.New(),
+		Type:       notificationType,
+		TenantSlug: tenantSlug,
+		CreatedAt:  time.Now().UTC(),
+		Data:       data,
+	}
+	for _, notifier := range notifiers {
+		notifier.Notify(ctx, notification)
+	}
+}
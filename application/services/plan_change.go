@@ -0,0 +1,195 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	domainerrors "github.com/redcardinal-io/metering/domain/errors"
+	"github.com/redcardinal-io/metering/domain/models"
+	"github.com/redcardinal-io/metering/domain/pkg/pagination"
+)
+
+// SchedulePlanChange switches a subject from one of its active plans to another, or cancels it, at
+// the requested time, at the end of the current calendar period or right away. The current
+// assignment ends when the change takes effect and the assignment of the new plan starts then,
+// keeping the end the current one had; both are written together with the change so it can be
+// canceled until it takes effect; an assignment has one pending change at a time. The assignment
+// of the new plan is notified once stored.
+func (s *PlanManagementService) SchedulePlanChange(ctx context.Context, arg models.SchedulePlanChangeInput) (*models.PlanChange, error) {
+	const op = "PlanManagementService.SchedulePlanChange"
+	switch {
+	case !models.IsValidPlanChangeType(arg.Type):
+		return nil, invalidPlanChange(fmt.Errorf("invalid plan change type: %s", arg.Type), op)
+	case (arg.OrganizationID == "") == (arg.UserID == ""):
+		return nil, invalidPlanChange(fmt.Errorf("exactly one of organization_id and user_id is required"), op)
+	case arg.Type == models.PlanChangeCancellation && arg.ToPlanID != nil:
+		return nil, invalidPlanChange(fmt.Errorf("cancellations do not switch to another plan"), op)
+	case arg.Type != models.PlanChangeCancellation && arg.ToPlanID == nil:
+		return nil, invalidPlanChange(fmt.Errorf("%s requires the plan to switch to", arg.Type), op)
+	case arg.ToPlanID != nil && *arg.ToPlanID == arg.FromPlanID:
+		return nil, invalidPlanChange(fmt.Errorf("subject already holds plan %s", arg.FromPlanID), op)
+	case arg.AtPeriodEnd && !arg.EffectiveAt.IsZero():
+		return nil, invalidPlanChange(fmt.Errorf("effective_at cannot be set together with at_period_end"), op)
+	}
+
+	now := s.now().UTC()
+	active, err := s.planAssignmentsStore.ListActiveSubjectAssignments(ctx, models.GetActiveAssignmentInput{
+		OrganizationID: arg.OrganizationID,
+		UserID:         arg.UserID,
+		At:             now,
+	})
+	if err != nil {
+		return nil, err
+	}
+	i := slices.IndexFunc(active, func(a models.PlanAssignment) bool { return a.PlanID == arg.FromPlanID.String() })
+	if i < 0 {
+		return nil, domainerrors.New(
+			fmt.Errorf("subject holds no active assignment of plan %s", arg.FromPlanID),
+			domainerrors.ENOTFOUND,
+			"subject holds no active assignment of the plan",
+			domainerrors.WithOperation(op),
+		)
+	}
+	from := active[i]
+
+	effectiveAt, err := planChangeEffectiveAt(arg, from, now)
+	if err != nil {
+		return nil, err
+	}
+	if !from.ValidUntil.IsZero() && !effectiveAt.Before(from.ValidUntil) {
+		return nil, invalidPlanChange(fmt.Errorf("assignment already ends on %s", from.ValidUntil.Format(time.RFC3339)), op)
+	}
+
+	change := models.CreatePlanChangeInput{
+		Type:        arg.Type,
+		From:        from,
+		EffectiveAt: effectiveAt,
+		Reason:      arg.Reason,
+		CreatedBy:   arg.CreatedBy,
+	}
+	if arg.ToPlanID != nil {
+		plan, err := s.planStore.GetPlanByIDorSlug(ctx, arg.ToPlanID.String())
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case !plan.ArchivedAt.IsZero():
+			return nil, invalidPlanChange(fmt.Errorf("plan %s is archived and cannot be assigned", plan.ID), op)
+		case plan.Kind != from.PlanKind:
+			return nil, invalidPlanChange(fmt.Errorf("cannot switch from a %s plan to a %s plan", from.PlanKind, plan.Kind), op)
+		}
+
+		change.To = &models.CreateAssignmentInput{
+			OrganizationID: arg.OrganizationID,
+			UserID:         arg.UserID,
+			PlanID:         &plan.ID,
+			PlanVersion:    arg.ToPlanVersion,
+			ValidFrom:      effectiveAt,
+			ValidUntil:     from.ValidUntil,
+			CreatedBy:      arg.CreatedBy,
+		}
+		if plan.Kind != models.PlanKindAddon {
+			// The current assignment runs past effectiveAt until the change ends it, so it is
+			// counted once; any other base plan of the subject over the period conflicts
+			overlapping, err := s.planAssignmentsStore.CountOverlappingBaseAssignments(ctx, *change.To)
+			if err != nil {
+				return nil, err
+			}
+			if overlapping > 1 {
+				return nil, domainerrors.New(
+					fmt.Errorf("subject holds another base plan after %s", effectiveAt.Format(time.RFC3339)),
+					domainerrors.ECONFLICT,
+					"subject holds another base plan once the change takes effect, end it first",
+					domainerrors.WithOperation(op),
+				)
+			}
+		}
+		change.To.PlanVersionID, err = s.assignmentVersion(ctx, plan.ID, *change.To)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.validateQuotaPeriods(ctx, *change.To, plan.Kind, from.ID, op); err != nil {
+			return nil, err
+		}
+	}
+
+	created, err := s.planChangeStore.CreatePlanChange(ctx, change)
+	if err != nil {
+		return nil, err
+	}
+	if created.ToAssignmentID != nil {
+		s.notifyAssignmentCreated(ctx, *created.ToAssignmentID)
+	}
+	return created, nil
+}
+
+// planChangeEffectiveAt resolves when a plan change takes effect: at the requested time, which
+// cannot be in the past, at the end of the current calendar period, a month unless set, or now.
+func planChangeEffectiveAt(arg models.SchedulePlanChangeInput, from models.PlanAssignment, now time.Time) (time.Time, error) {
+	const op = "PlanManagementService.SchedulePlanChange"
+	switch {
+	case arg.AtPeriodEnd:
+		period := arg.Period
+		if period == "" {
+			period = models.MeteredResetPeriodMonth
+		}
+		switch period {
+		case models.MeteredResetPeriodDay, models.MeteredResetPeriodWeek, models.MeteredResetPeriodMonth, models.MeteredResetPeriodYear:
+		default:
+			return time.Time{}, invalidPlanChange(fmt.Errorf("invalid period: %s", period), op)
+		}
+		_, end := quotaPeriod(&models.PlanFeatureQuota{ResetPeriod: period}, from.ValidFrom, now)
+		return *end, nil
+	case arg.EffectiveAt.IsZero():
+		return now, nil
+	case arg.EffectiveAt.Before(now):
+		return time.Time{}, invalidPlanChange(fmt.Errorf("effective_at cannot be in the past"), op)
+	default:
+		return arg.EffectiveAt.UTC(), nil
+	}
+}
+
+// GetPlanChange returns a plan change with its status as of now.
+func (s *PlanManagementService) GetPlanChange(ctx context.Context, id uuid.UUID) (*models.PlanChange, error) {
+	return s.planChangeStore.GetPlanChange(ctx, id, s.now().UTC())
+}
+
+// ListPlanChanges lists plan changes, latest effective first, with their status as of now.
+func (s *PlanManagementService) ListPlanChanges(ctx context.Context, arg models.QueryPlanChangeInput, pagination pagination.Pagination) (*pagination.PaginationView[models.PlanChange], error) {
+	if arg.Status != "" && !models.IsValidPlanChangeStatus(arg.Status) {
+		return nil, invalidPlanChange(fmt.Errorf("invalid plan change status: %s", arg.Status), "PlanManagementService.ListPlanChanges")
+	}
+	arg.At = s.now().UTC()
+	return s.planChangeStore.ListPlanChanges(ctx, arg, pagination)
+}
+
+// CancelPlanChange cancels a pending plan change, removing the assignment of the new plan and
+// restoring the end the changed assignment had.
+func (s *PlanManagementService) CancelPlanChange(ctx context.Context, id uuid.UUID, canceledBy string) (*models.PlanChange, error) {
+	now := s.now().UTC()
+	change, err := s.planChangeStore.GetPlanChange(ctx, id, now)
+	if err != nil {
+		return nil, err
+	}
+	if change.Status != models.PlanChangePending {
+		return nil, domainerrors.New(
+			fmt.Errorf("plan change %s is %s", id, change.Status),
+			domainerrors.ECONFLICT,
+			fmt.Sprintf("plan change is %s, only pending changes can be canceled", change.Status),
+			domainerrors.WithOperation("PlanManagementService.CancelPlanChange"),
+		)
+	}
+	return s.planChangeStore.CancelPlanChange(ctx, id, canceledBy, now)
+}
+
+func invalidPlanChange(err error, op string) error {
+	return domainerrors.New(
+		err,
+		domainerrors.EINVALID,
+		"invalid plan change",
+		domainerrors.WithOperation(op),
+	)
+}
@@ -0,0 +1,239 @@
+package services
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/redcardinal-io/metering/application/repositories"
+	domainerrors "github.com/redcardinal-io/metering/domain/errors"
+	"github.com/redcardinal-io/metering/domain/models"
+	"github.com/redcardinal-io/metering/domain/pkg/pagination"
+)
+
+// fakePlanChangeStore keeps plan changes with the status they are stored with, refusing a change
+// of an assignment with a pending one like the unique index does
+type fakePlanChangeStore struct {
+	repositories.PlanChangeStoreRepository
+	changes  []models.PlanChange
+	created  []models.CreatePlanChangeInput
+	canceled []uuid.UUID
+}
+
+func (f *fakePlanChangeStore) CreatePlanChange(ctx context.Context, arg models.CreatePlanChangeInput) (*models.PlanChange, error) {
+	if slices.ContainsFunc(f.changes, func(c models.PlanChange) bool {
+		return c.FromAssignmentID == arg.From.ID && c.Status == models.PlanChangePending
+	}) {
+		return nil, domainerrors.New(nil, domainerrors.ECONFLICT, "the plan already has a pending change, cancel it first")
+	}
+	f.created = append(f.created, arg)
+	change := &models.PlanChange{Base: models.Base{ID: uuid.New()}, Type: arg.Type, FromAssignmentID: arg.From.ID, EffectiveAt: arg.EffectiveAt}
+	if arg.To != nil {
+		id := uuid.New()
+		change.ToAssignmentID = &id
+	}
+	return change, nil
+}
+
+func (f *fakePlanChangeStore) GetPlanChange(ctx context.Context, id uuid.UUID, at time.Time) (*models.PlanChange, error) {
+	for i := range f.changes {
+		if f.changes[i].ID == id {
+			return &f.changes[i], nil
+		}
+	}
+	return nil, domainerrors.New(nil, domainerrors.ENOTFOUND, "Resource not found")
+}
+
+func (f *fakePlanChangeStore) ListPlanChanges(ctx context.Context, arg models.QueryPlanChangeInput, page pagination.Pagination) (*pagination.PaginationView[models.PlanChange], error) {
+	var changes []models.PlanChange
+	for _, change := range f.changes {
+		if arg.Status == "" || change.Status == arg.Status {
+			changes = append(changes, change)
+		}
+	}
+	return &pagination.PaginationView[models.PlanChange]{Results: changes, Total: len(changes)}, nil
+}
+
+func (f *fakePlanChangeStore) CancelPlanChange(ctx context.Context, id uuid.UUID, canceledBy string, at time.Time) (*models.PlanChange, error) {
+	f.canceled = append(f.canceled, id)
+	return &models.PlanChange{Base: models.Base{ID: id}, Status: models.PlanChangeCanceled, CanceledBy: canceledBy}, nil
+}
+
+func TestPlanManagementService_SchedulePlanChange(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 6, 18, 12, 0, 0, 0, time.UTC)
+	basic := uuid.New()
+	pro := &models.Plan{Base: models.Base{ID: uuid.New()}, Slug: "pro", Kind: models.PlanKindBase}
+	addon := &models.Plan{Base: models.Base{ID: uuid.New()}, Slug: "extra-seats", Kind: models.PlanKindAddon}
+	v1 := models.PlanVersion{ID: uuid.New(), Version: 1}
+	versions := &fakePlanVersionStore{versions: []models.PlanVersion{v1}}
+	current := models.PlanAssignment{
+		Base:           models.Base{ID: uuid.New()},
+		PlanID:         basic.String(),
+		PlanKind:       models.PlanKindBase,
+		OrganizationID: "org-a",
+		ValidFrom:      now.AddDate(0, -3, 0),
+		ValidUntil:     now.AddDate(1, 0, 0),
+	}
+	newService := func(plan *models.Plan, changes *fakePlanChangeStore) *PlanManagementService {
+		assignments := new(MockPlanAssignmentsStoreRepository)
+		// The current assignment overlaps the new one until the change ends it
+		assignments.On("CountOverlappingBaseAssignments", mock.Anything, mock.Anything).Return(int64(1), nil)
+		// The change looks at the subject now and, for the plans held with the new one, when it takes effect
+		assignments.On("ListActiveSubjectAssignments", mock.Anything, mock.Anything).Return([]models.PlanAssignment{current}, nil)
+		assignments.On("ListAssignments", mock.Anything, mock.Anything, mock.Anything).Return(&pagination.PaginationView[models.PlanAssignment]{}, nil)
+		assignments.On("GetAssignment", mock.Anything, mock.Anything).Return(&models.PlanAssignment{PlanID: plan.ID.String(), OrganizationID: "org-a"}, nil)
+		svc := NewPlanService(&fakePlanStore{plan: plan}, nil, nil, assignments, nil, nil, versions, changes, nil)
+		svc.now = func() time.Time { return now }
+		return svc
+	}
+	upgrade := models.SchedulePlanChangeInput{
+		Type:           models.PlanChangeUpgrade,
+		OrganizationID: "org-a",
+		FromPlanID:     basic,
+		ToPlanID:       &pro.ID,
+		CreatedBy:      "billing",
+	}
+
+	t.Run("Switches plans at the end of the period", func(t *testing.T) {
+		changes := &fakePlanChangeStore{}
+		arg := upgrade
+		arg.AtPeriodEnd = true
+		_, err := newService(pro, changes).SchedulePlanChange(ctx, arg)
+		require.NoError(t, err)
+
+		require.Len(t, changes.created, 1)
+		created := changes.created[0]
+		assert.Equal(t, current.ID, created.From.ID)
+		assert.Equal(t, time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC), created.EffectiveAt)
+		require.NotNil(t, created.To)
+		assert.Equal(t, created.EffectiveAt, created.To.ValidFrom)
+		assert.Equal(t, current.ValidUntil, created.To.ValidUntil, "the new plan keeps the end of the current one")
+		assert.Equal(t, v1.ID, created.To.PlanVersionID)
+	})
+
+	t.Run("Notifies the assignment of the new plan", func(t *testing.T) {
+		changes, notifier := &fakePlanChangeStore{}, &fakeNotifier{}
+		svc := newService(pro, changes)
+		svc.notifiers = []repositories.NotifierRepository{notifier}
+
+		change, err := svc.SchedulePlanChange(ctx, upgrade)
+		require.NoError(t, err)
+		require.Len(t, notifier.notifications, 1)
+		assert.Equal(t, models.NotificationAssignmentCreated, notifier.notifications[0].Type)
+		assert.Equal(t, pro.ID.String(), notifier.notifications[0].Data.(*models.PlanAssignment).PlanID)
+		svc.planAssignmentsStore.(*MockPlanAssignmentsStoreRepository).AssertCalled(t, "GetAssignment", mock.Anything, *change.ToAssignmentID)
+
+		notifier.notifications = nil
+		_, err = svc.SchedulePlanChange(ctx, models.SchedulePlanChangeInput{Type: models.PlanChangeCancellation, OrganizationID: "org-a", FromPlanID: basic})
+		require.NoError(t, err)
+		assert.Empty(t, notifier.notifications, "cancellations assign no plan")
+	})
+
+	t.Run("Cancels the plan right away", func(t *testing.T) {
+		changes := &fakePlanChangeStore{}
+		_, err := newService(pro, changes).SchedulePlanChange(ctx, models.SchedulePlanChangeInput{
+			Type:           models.PlanChangeCancellation,
+			OrganizationID: "org-a",
+			FromPlanID:     basic,
+		})
+		require.NoError(t, err)
+		require.Len(t, changes.created, 1)
+		assert.Equal(t, now, changes.created[0].EffectiveAt)
+		assert.Nil(t, changes.created[0].To)
+	})
+
+	t.Run("Rejects a plan with quotas over other periods than the add-ons held with it", func(t *testing.T) {
+		apiID, proAPI, addonAPI, addonVersion := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+		held := models.PlanAssignment{Base: models.Base{ID: uuid.New()}, PlanKind: models.PlanKindAddon, PlanVersionID: addonVersion.String(), OrganizationID: "org-a"}
+		planFeatures := &fakePlanFeatureStore{versions: map[uuid.UUID][]models.PlanFeature{
+			v1.ID:        {{Base: models.Base{ID: proAPI}, FeatureID: apiID, FeatureSlug: "api"}},
+			addonVersion: {{Base: models.Base{ID: addonAPI}, FeatureID: apiID, FeatureSlug: "api"}},
+		}}
+		quotas := &fakeQuotaStore{quotas: map[uuid.UUID]*models.PlanFeatureQuota{
+			proAPI:   {LimitValue: 1000, ResetPeriod: models.MeteredResetPeriodMonth},
+			addonAPI: {LimitValue: 100, ResetPeriod: models.MeteredResetPeriodDay},
+		}}
+		assignments := new(MockPlanAssignmentsStoreRepository)
+		assignments.On("ListActiveSubjectAssignments", mock.Anything, mock.Anything).Return([]models.PlanAssignment{current, held}, nil)
+		assignments.On("CountOverlappingBaseAssignments", mock.Anything, mock.Anything).Return(int64(1), nil)
+		assignments.On("ListAssignments", mock.Anything, mock.Anything, mock.Anything).Return(&pagination.PaginationView[models.PlanAssignment]{}, nil)
+		changes := &fakePlanChangeStore{}
+		svc := NewPlanService(&fakePlanStore{plan: pro}, nil, planFeatures, assignments, quotas, nil, versions, changes, nil)
+		svc.now = func() time.Time { return now }
+
+		_, err := svc.SchedulePlanChange(ctx, upgrade)
+		assert.Equal(t, string(domainerrors.EINVALID), domainerrors.GetErrorCode(err))
+		assert.Empty(t, changes.created)
+	})
+
+	past := now.Add(-time.Hour)
+	rejected := []struct {
+		name   string
+		plan   *models.Plan
+		modify func(arg *models.SchedulePlanChangeInput)
+		code   domainerrors.ErrorCode
+	}{
+		{"a change in the past", pro, func(arg *models.SchedulePlanChangeInput) { arg.EffectiveAt = past }, domainerrors.EINVALID},
+		{"a change after the assignment ends", pro, func(arg *models.SchedulePlanChangeInput) { arg.EffectiveAt = current.ValidUntil }, domainerrors.EINVALID},
+		{"a cancellation to another plan", pro, func(arg *models.SchedulePlanChangeInput) { arg.Type = models.PlanChangeCancellation }, domainerrors.EINVALID},
+		{"a switch to a plan of another kind", addon, func(arg *models.SchedulePlanChangeInput) { arg.ToPlanID = &addon.ID }, domainerrors.EINVALID},
+		{"a plan the subject does not hold", pro, func(arg *models.SchedulePlanChangeInput) { arg.FromPlanID = uuid.New() }, domainerrors.ENOTFOUND},
+	}
+	for _, tt := range rejected {
+		t.Run("Rejects "+tt.name, func(t *testing.T) {
+			changes := &fakePlanChangeStore{}
+			arg := upgrade
+			tt.modify(&arg)
+			_, err := newService(tt.plan, changes).SchedulePlanChange(ctx, arg)
+			assert.Equal(t, string(tt.code), domainerrors.GetErrorCode(err))
+			assert.Empty(t, changes.created)
+		})
+	}
+
+	t.Run("Rejects a base plan overlapping a future base plan of the subject", func(t *testing.T) {
+		assignments := new(MockPlanAssignmentsStoreRepository)
+		assignments.On("ListActiveSubjectAssignments", mock.Anything, mock.Anything).Return([]models.PlanAssignment{current}, nil)
+		assignments.On("CountOverlappingBaseAssignments", mock.Anything, mock.MatchedBy(func(arg models.CreateAssignmentInput) bool {
+			return arg.ValidFrom.Equal(now) && arg.ValidUntil.Equal(current.ValidUntil)
+		})).Return(int64(2), nil)
+		changes := &fakePlanChangeStore{}
+		svc := NewPlanService(&fakePlanStore{plan: pro}, nil, nil, assignments, nil, nil, versions, changes, nil)
+		svc.now = func() time.Time { return now }
+
+		_, err := svc.SchedulePlanChange(ctx, upgrade)
+		assert.Equal(t, string(domainerrors.ECONFLICT), domainerrors.GetErrorCode(err))
+		assert.Empty(t, changes.created)
+	})
+
+	t.Run("Rejects a second pending change", func(t *testing.T) {
+		changes := &fakePlanChangeStore{changes: []models.PlanChange{
+			{Base: models.Base{ID: uuid.New()}, Status: models.PlanChangePending, FromAssignmentID: current.ID},
+		}}
+		_, err := newService(pro, changes).SchedulePlanChange(ctx, upgrade)
+		assert.Equal(t, string(domainerrors.ECONFLICT), domainerrors.GetErrorCode(err))
+		assert.Empty(t, changes.created)
+	})
+}
+
+func TestPlanManagementService_CancelPlanChange(t *testing.T) {
+	ctx := context.Background()
+	pending := models.PlanChange{Base: models.Base{ID: uuid.New()}, Status: models.PlanChangePending}
+	applied := models.PlanChange{Base: models.Base{ID: uuid.New()}, Status: models.PlanChangeApplied}
+	changes := &fakePlanChangeStore{changes: []models.PlanChange{pending, applied}}
+	svc := NewPlanService(nil, nil, nil, nil, nil, nil, nil, changes, nil)
+
+	change, err := svc.CancelPlanChange(ctx, pending.ID, "billing")
+	require.NoError(t, err)
+	assert.Equal(t, models.PlanChangeCanceled, change.Status)
+
+	_, err = svc.CancelPlanChange(ctx, applied.ID, "billing")
+	assert.Equal(t, string(domainerrors.ECONFLICT), domainerrors.GetErrorCode(err))
+	assert.Equal(t, []uuid.UUID{pending.ID}, changes.canceled)
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/redcardinal-io/metering/application/repositories"
@@ -18,15 +20,26 @@ type PlanManagementService struct {
 	featureStore         repositories.FeatureStoreRepository
 	planFeatureStore     repositories.PlanFeatureStoreRepository
 	planFeatureQuotaRepo repositories.PlanFeatureQuotaStoreRepository
+	meterStore           repositories.MeterStoreRepository
+	planVersionStore     repositories.PlanVersionStoreRepository
+	planChangeStore      repositories.PlanChangeStoreRepository
+	trialStore           repositories.TrialStoreRepository
+	notifiers            []repositories.NotifierRepository
+	now                  func() time.Time
 }
 
-// NewPlanService creates a new PlanManagementService with the provided repository implementations for plans, features, plan features, plan assignments, and plan feature quotas.
+// NewPlanService creates a new PlanManagementService with the provided repository implementations for plans, features, plan features, plan assignments, plan feature quotas, the meters features are linked to, published plan versions, scheduled plan changes, and trials. Notifiers are told about created assignments, archived plans and ended trials.
 func NewPlanService(
 	planStore repositories.PlanStoreRepository,
 	featureStore repositories.FeatureStoreRepository,
 	planFeatureStore repositories.PlanFeatureStoreRepository,
 	planAssignmentsStore repositories.PlanAssignmentsStoreRepository,
 	planFeatureQuotaRepo repositories.PlanFeatureQuotaStoreRepository,
+	meterStore repositories.MeterStoreRepository,
+	planVersionStore repositories.PlanVersionStoreRepository,
+	planChangeStore repositories.PlanChangeStoreRepository,
+	trialStore repositories.TrialStoreRepository,
+	notifiers ...repositories.NotifierRepository,
 ) *PlanManagementService {
 	return &PlanManagementService{
 		planStore:            planStore,
@@ -34,6 +47,12 @@ func NewPlanService(
 		planFeatureStore:     planFeatureStore,
 		planAssignmentsStore: planAssignmentsStore,
 		planFeatureQuotaRepo: planFeatureQuotaRepo,
+		meterStore:           meterStore,
+		planVersionStore:     planVersionStore,
+		planChangeStore:      planChangeStore,
+		trialStore:           trialStore,
+		notifiers:            notifiers,
+		now:                  time.Now,
 	}
 }
 
@@ -50,7 +69,137 @@ func (s *PlanManagementService) CreateAssignment(ctx context.Context, arg models
 			fmt.Sprintf("plan %s is archived and cannot be assigned. This plan was archived on %s. Please choose an active plan instead.", arg.PlanID, plan.ArchivedAt.Format("2006-01-02 15:04:05")),
 		)
 	}
-	return s.planAssignmentsStore.CreateAssignment(ctx, arg)
+	if plan.Kind != models.PlanKindAddon {
+		if err := s.validateSingleBasePlan(ctx, arg); err != nil {
+			return nil, err
+		}
+	}
+	arg.PlanVersionID, err = s.assignmentVersion(ctx, plan.ID, arg)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.validateQuotaPeriods(ctx, arg, plan.Kind, uuid.Nil, "PlanManagementService.CreateAssignment"); err != nil {
+		return nil, err
+	}
+	assignment, err := s.planAssignmentsStore.CreateAssignment(ctx, arg)
+	if err != nil {
+		return nil, err
+	}
+	notify(ctx, s.notifiers, models.NotificationAssignmentCreated, assignment)
+	return assignment, nil
+}
+
+// notifyAssignmentCreated publishes an assignment stored together with a plan change or a trial.
+// The assignment is stored already, so failing to read it back only skips the notification.
+func (s *PlanManagementService) notifyAssignmentCreated(ctx context.Context, id uuid.UUID) {
+	if len(s.notifiers) == 0 {
+		return
+	}
+	if assignment, err := s.planAssignmentsStore.GetAssignment(ctx, id); err == nil {
+		notify(ctx, s.notifiers, models.NotificationAssignmentCreated, assignment)
+	}
+}
+
+// validateSingleBasePlan rejects base plan assignments overlapping another base plan of the
+// subject; add-ons stack on top of the one base plan instead.
+func (s *PlanManagementService) validateSingleBasePlan(ctx context.Context, arg models.CreateAssignmentInput) error {
+	overlapping, err := s.planAssignmentsStore.CountOverlappingBaseAssignments(ctx, arg)
+	if err != nil {
+		return err
+	}
+	if overlapping > 0 {
+		return domainerrors.New(
+			fmt.Errorf("subject already holds a base plan between %s and %s", arg.ValidFrom.Format(time.RFC3339), arg.ValidUntil.Format(time.RFC3339)),
+			domainerrors.ECONFLICT,
+			"subject already holds a base plan over this period, end it first or assign an add-on",
+			domainerrors.WithOperation("PlanManagementService.CreateAssignment"),
+		)
+	}
+	return nil
+}
+
+// validateQuotaPeriods rejects assigning the plan version of arg, of the given kind, when one of
+// its quotas resets over another period than the quota of the same feature in the plans the
+// subject holds it with when it starts: the base plan for an add-on, the add-ons for a base plan.
+// The limits of such quotas could not be combined into one entitlement. The assignment being
+// replaced, if any, is left out.
+func (s *PlanManagementService) validateQuotaPeriods(ctx context.Context, arg models.CreateAssignmentInput, kind models.PlanKindEnum, replaced uuid.UUID, op string) error {
+	at := arg.ValidFrom
+	if at.IsZero() {
+		at = s.now()
+	}
+	active, err := s.planAssignmentsStore.ListActiveSubjectAssignments(ctx, models.GetActiveAssignmentInput{
+		OrganizationID: arg.OrganizationID,
+		UserID:         arg.UserID,
+		At:             at,
+	})
+	if err != nil {
+		return err
+	}
+	var stacked []models.PlanAssignment
+	if kind == models.PlanKindAddon {
+		// Base plans come first, the latest one leading
+		i := slices.IndexFunc(active, func(a models.PlanAssignment) bool { return a.PlanKind != models.PlanKindAddon && a.ID != replaced })
+		if i >= 0 {
+			stacked = active[i : i+1]
+		}
+	} else {
+		for _, a := range active {
+			if a.PlanKind == models.PlanKindAddon && a.ID != replaced {
+				stacked = append(stacked, a)
+			}
+		}
+	}
+	if len(stacked) == 0 {
+		return nil
+	}
+
+	features, err := s.planFeatureStore.ListPlanFeaturesByVersion(ctx, arg.PlanVersionID, models.PlanFeatureListFilter{})
+	if err != nil {
+		return err
+	}
+	for _, a := range stacked {
+		versionID, err := uuid.Parse(a.PlanVersionID)
+		if err != nil {
+			continue
+		}
+		stackedFeatures, err := s.planFeatureStore.ListPlanFeaturesByVersion(ctx, versionID, models.PlanFeatureListFilter{})
+		if err != nil {
+			return err
+		}
+		for _, feature := range features {
+			j := slices.IndexFunc(stackedFeatures, func(f models.PlanFeature) bool { return f.FeatureID == feature.FeatureID })
+			if j < 0 {
+				continue
+			}
+			quota, err := s.optionalPlanFeatureQuota(ctx, feature.ID)
+			if err != nil {
+				return err
+			}
+			stackedQuota, err := s.optionalPlanFeatureQuota(ctx, stackedFeatures[j].ID)
+			if err != nil {
+				return err
+			}
+			if quota != nil && stackedQuota != nil && !quota.SamePeriod(stackedQuota) {
+				return domainerrors.New(
+					fmt.Errorf("quota of feature %s resets per %s while the quota of plan %s resets per %s", feature.FeatureSlug, quota.ResetPeriod, a.PlanID, stackedQuota.ResetPeriod),
+					domainerrors.EINVALID,
+					fmt.Sprintf("the quota of feature %s must reset over the same period as in the plans the subject holds with it", feature.FeatureSlug),
+					domainerrors.WithOperation(op),
+				)
+			}
+		}
+	}
+	return nil
+}
+
+// optionalPlanFeatureQuota returns the quota of the plan feature, nil when it has none
+func (s *PlanManagementService) optionalPlanFeatureQuota(ctx context.Context, planFeatureID uuid.UUID) (*models.PlanFeatureQuota, error) {
+	quota, err := s.planFeatureQuotaRepo.GetPlanFeatureQuota(ctx, planFeatureID)
+	if domainerrors.GetErrorCode(err) == string(domainerrors.ENOTFOUND) {
+		return nil, nil
+	}
+	return quota, err
 }
 
 func (s *PlanManagementService) TerminateAssignment(ctx context.Context, arg models.TerminateAssignmentInput) error {
@@ -178,9 +327,60 @@ func (s *PlanManagementService) ArchivePlanByIDorSlug(ctx context.Context, IDorS
 		return err
 	}
 
+	if arg.Archive && len(s.notifiers) > 0 {
+		// The plan is archived already, so failing to read it back only skips the notification
+		if plan, err := s.planStore.GetPlanByIDorSlug(ctx, IDorSlug); err == nil {
+			notify(ctx, s.notifiers, models.NotificationPlanArchived, plan)
+		}
+	}
 	return nil
 }
 
+// ClonePlan copies a plan with its draft features, their configs and quotas into a new plan.
+// Name, type and description default to those of the source plan, and quota limits can be
+// overridden by feature slug. The clone starts unpublished.
+func (s *PlanManagementService) ClonePlan(ctx context.Context, IDorSlug string, arg models.ClonePlanInput) (*models.Plan, error) {
+	source, err := s.planStore.GetPlanByIDorSlug(ctx, IDorSlug)
+	if err != nil {
+		return nil, err
+	}
+	if arg.Name == "" {
+		arg.Name = source.Name
+	}
+	if arg.Type == "" {
+		arg.Type = source.Type
+	}
+	if arg.Description == "" {
+		arg.Description = source.Description
+	}
+	arg.Kind = source.Kind
+
+	if len(arg.QuotaLimits) > 0 {
+		features, err := s.planFeatureStore.ListPlanFeaturesByPlan(ctx, source.ID, models.PlanFeatureListFilter{})
+		if err != nil {
+			return nil, err
+		}
+		for slug := range arg.QuotaLimits {
+			i := slices.IndexFunc(features, func(f models.PlanFeature) bool { return f.FeatureSlug == slug })
+			if i < 0 {
+				return nil, invalidClone(fmt.Errorf("plan %s has no feature %q", source.Slug, slug), fmt.Sprintf("plan has no feature %q to override the quota of", slug))
+			}
+			if _, err := s.planFeatureQuotaRepo.GetPlanFeatureQuota(ctx, features[i].ID); err != nil {
+				if domainerrors.GetErrorCode(err) == string(domainerrors.ENOTFOUND) {
+					return nil, invalidClone(fmt.Errorf("feature %q of plan %s has no quota", slug, source.Slug), fmt.Sprintf("feature %q has no quota to override", slug))
+				}
+				return nil, err
+			}
+		}
+	}
+
+	return s.planStore.ClonePlan(ctx, source.ID, arg)
+}
+
+func invalidClone(err error, msg string) error {
+	return domainerrors.New(err, domainerrors.EINVALID, msg, domainerrors.WithOperation("PlanManagementService.ClonePlan"))
+}
+
 func (s *PlanManagementService) UpdatePlanByIDorSlug(ctx context.Context, IDorSlug string, arg models.UpdatePlanInput) (*models.Plan, error) {
 	// Call the store repository to update the plan
 	m, err := s.planStore.UpdatePlanByIDorSlug(ctx, IDorSlug, arg)
@@ -202,6 +402,9 @@ func (s *PlanManagementService) ListPlans(ctx context.Context, pagination pagina
 }
 
 func (s *PlanManagementService) CreateFeature(ctx context.Context, arg models.CreateFeatureInput) (*models.Feature, error) {
+	if err := s.validateFeatureMeters(ctx, arg.Type, arg.MeterSlugs, "PlanManagementService.CreateFeature"); err != nil {
+		return nil, err
+	}
 	return s.featureStore.CreateFeature(ctx, arg)
 }
 
@@ -214,9 +417,47 @@ func (s *PlanManagementService) DeleteFeatureByIDorSlug(ctx context.Context, idO
 }
 
 func (s *PlanManagementService) UpdateFeatureByIDorSlug(ctx context.Context, idOrSlug string, arg models.UpdateFeatureInput) (*models.Feature, error) {
+	if arg.MeterSlugs != nil {
+		feature, err := s.featureStore.GetFeatureByIDorSlug(ctx, idOrSlug)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.validateFeatureMeters(ctx, feature.Type, arg.MeterSlugs, "PlanManagementService.UpdateFeature"); err != nil {
+			return nil, err
+		}
+	}
 	return s.featureStore.UpdateFeatureByIDorSlug(ctx, idOrSlug, arg)
 }
 
+// validateFeatureMeters checks that only metered features link meters and that every linked meter exists.
+func (s *PlanManagementService) validateFeatureMeters(ctx context.Context, featureType models.FeatureTypeEnum, meterSlugs []string, op string) error {
+	if len(meterSlugs) == 0 {
+		return nil
+	}
+	if featureType != models.FeatureTypeMetered {
+		return domainerrors.New(
+			fmt.Errorf("only metered features can be linked to meters"),
+			domainerrors.EINVALID,
+			"invalid feature meters",
+			domainerrors.WithOperation(op),
+		)
+	}
+	for _, slug := range meterSlugs {
+		if _, err := s.meterStore.GetMeterByIDorSlug(ctx, slug); err != nil {
+			if domainerrors.GetErrorCode(err) == string(domainerrors.ENOTFOUND) {
+				return domainerrors.New(
+					fmt.Errorf("unknown meter %s", slug),
+					domainerrors.EINVALID,
+					"invalid feature meters",
+					domainerrors.WithOperation(op),
+				)
+			}
+			return err
+		}
+	}
+	return nil
+}
+
 func (s *PlanManagementService) ListFeatures(ctx context.Context, pagination pagination.Pagination) (*pagination.PaginationView[models.Feature], error) {
 	return s.featureStore.ListFeatures(ctx, pagination)
 }
@@ -244,6 +485,15 @@ func (s *PlanManagementService) CheckPlanAndFeatureForTenant(ctx context.Context
 
 // CreatePlanFeatureQuota creates a new quota for a plan feature
 func (s *PlanManagementService) CreatePlanFeatureQuota(ctx context.Context, arg models.CreatePlanFeatureQuotaInput, planID, featureID string) (*models.PlanFeatureQuota, error) {
+	if err := validateQuotaLimits(models.PlanFeatureQuota{
+		LimitValue:           arg.LimitValue,
+		SoftLimitValue:       arg.SoftLimitValue,
+		OverageAllowance:     arg.OverageAllowance,
+		OverageAllowanceType: arg.OverageAllowanceType,
+		GracePeriodMinutes:   arg.GracePeriodMinutes,
+	}, "PlanManagementService.CreatePlanFeatureQuota"); err != nil {
+		return nil, err
+	}
 	planFeatureID, err := s.planFeatureStore.GetPlanFeatureIDByPlanAndFeature(ctx, uuid.MustParse(planID), uuid.MustParse(featureID))
 	if err != nil {
 		return nil, err
@@ -271,11 +521,69 @@ func (s *PlanManagementService) UpdatePlanFeatureQuota(ctx context.Context, inpu
 		return nil, err
 	}
 
+	// Check the limits the quota ends up with
+	quota, err := s.planFeatureQuotaRepo.GetPlanFeatureQuota(ctx, planFeatureID)
+	if err != nil {
+		return nil, err
+	}
+	if input.LimitValue != 0 {
+		quota.LimitValue = input.LimitValue
+	}
+	if input.SoftLimitValue != nil {
+		quota.SoftLimitValue = input.SoftLimitValue
+	} else if input.ClearSoftLimitValue {
+		quota.SoftLimitValue = nil
+	}
+	if input.OverageAllowance != nil {
+		quota.OverageAllowance = input.OverageAllowance
+	} else if input.ClearOverageAllowance {
+		quota.OverageAllowance = nil
+	}
+	if input.OverageAllowanceType != "" {
+		quota.OverageAllowanceType = input.OverageAllowanceType
+	} else if input.ClearOverageAllowance {
+		quota.OverageAllowanceType = ""
+	}
+	if input.GracePeriodMinutes != nil {
+		quota.GracePeriodMinutes = input.GracePeriodMinutes
+	} else if input.ClearGracePeriodMinutes {
+		quota.GracePeriodMinutes = nil
+	}
+	if err := validateQuotaLimits(*quota, "PlanManagementService.UpdatePlanFeatureQuota"); err != nil {
+		return nil, err
+	}
+
 	input.PlanFeatureID = planFeatureID.String()
 	// Update the quota
 	return s.planFeatureQuotaRepo.UpdatePlanFeatureQuota(ctx, input)
 }
 
+// validateQuotaLimits checks that the soft limit of a quota is below its limit and that its
+// overage allowance comes with a type.
+func validateQuotaLimits(quota models.PlanFeatureQuota, op string) error {
+	var err error
+	switch {
+	case quota.SoftLimitValue != nil && (*quota.SoftLimitValue <= 0 || *quota.SoftLimitValue >= quota.LimitValue):
+		err = fmt.Errorf("soft_limit_value must be greater than 0 and less than limit_value")
+	case (quota.OverageAllowance == nil) != (quota.OverageAllowanceType == ""):
+		err = fmt.Errorf("overage_allowance and overage_allowance_type must be set together")
+	case quota.OverageAllowance != nil && *quota.OverageAllowance <= 0:
+		err = fmt.Errorf("overage_allowance must be greater than 0")
+	case quota.OverageAllowanceType != "" && !models.IsValidQuotaOverageType(quota.OverageAllowanceType):
+		err = fmt.Errorf("invalid overage allowance type: %s", quota.OverageAllowanceType)
+	case quota.GracePeriodMinutes != nil && *quota.GracePeriodMinutes <= 0:
+		err = fmt.Errorf("grace_period_minutes must be greater than 0")
+	default:
+		return nil
+	}
+	return domainerrors.New(
+		err,
+		domainerrors.EINVALID,
+		"invalid quota",
+		domainerrors.WithOperation(op),
+	)
+}
+
 // DeletePlanFeatureQuota deletes a quota by plan feature ID
 func (s *PlanManagementService) DeletePlanFeatureQuota(ctx context.Context, planID, featureID string) error {
 	planFeatureID, err := s.planFeatureStore.GetPlanFeatureIDByPlanAndFeature(ctx, uuid.MustParse(planID), uuid.MustParse(featureID))
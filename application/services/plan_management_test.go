@@ -9,7 +9,9 @@ import (
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 
+	domainerrors "github.com/redcardinal-io/metering/domain/errors"
 	"github.com/redcardinal-io/metering/domain/models"
 	"github.com/redcardinal-io/metering/domain/pkg/pagination"
 )
@@ -65,6 +67,40 @@ func (m *MockPlanAssignmentsStoreRepository) ListAllAssignments(ctx context.Cont
 	return args.Get(0).(*pagination.PaginationView[models.PlanAssignment]), args.Error(1)
 }
 
+func (m *MockPlanAssignmentsStoreRepository) ListActiveSubjectAssignments(ctx context.Context, arg models.GetActiveAssignmentInput) ([]models.PlanAssignment, error) {
+	args := m.Called(ctx, arg)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.PlanAssignment), args.Error(1)
+}
+
+func (m *MockPlanAssignmentsStoreRepository) GetAssignment(ctx context.Context, id uuid.UUID) (*models.PlanAssignment, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.PlanAssignment), args.Error(1)
+}
+
+func (m *MockPlanAssignmentsStoreRepository) CountOverlappingBaseAssignments(ctx context.Context, arg models.CreateAssignmentInput) (int64, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockPlanAssignmentsStoreRepository) ListActiveAssignmentsByPlan(ctx context.Context, planID uuid.UUID, at time.Time) ([]models.PlanAssignment, error) {
+	args := m.Called(ctx, planID, at)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.PlanAssignment), args.Error(1)
+}
+
+func (m *MockPlanAssignmentsStoreRepository) MigrateAssignments(ctx context.Context, arg models.MigratePlanVersionInput) (int64, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(int64), args.Error(1)
+}
+
 func TestValidateAssignmentTimeRange(t *testing.T) {
 	// Define common variables for tests
 	ctx := context.Background()
@@ -239,3 +275,209 @@ func TestValidateAssignmentTimeRangeSuccessful(t *testing.T) {
 	// Verify the mock was called as expected
 	mockRepo.AssertExpectations(t)
 }
+
+func TestPlanManagementService_ClonePlan(t *testing.T) {
+	ctx := context.Background()
+	apiFeature, seatsFeature := uuid.New(), uuid.New()
+	plans := &fakePlanStore{plan: &models.Plan{Base: models.Base{ID: uuid.New()}, Name: "Pro", Slug: "pro", Type: models.Standard, Description: "For growing teams"}}
+	planFeatures := &fakePlanFeatureStore{features: []models.PlanFeature{
+		{Base: models.Base{ID: apiFeature}, FeatureSlug: "api"},
+		{Base: models.Base{ID: seatsFeature}, FeatureSlug: "seats"},
+	}}
+	quotas := &fakeQuotaStore{quotas: map[uuid.UUID]*models.PlanFeatureQuota{
+		apiFeature: {LimitValue: 1000, ResetPeriod: models.MeteredResetPeriodMonth},
+	}}
+	svc := NewPlanService(plans, nil, planFeatures, nil, quotas, nil, nil, nil, nil)
+
+	t.Run("Defaults to the source plan", func(t *testing.T) {
+		plan, err := svc.ClonePlan(ctx, "pro", models.ClonePlanInput{Slug: "pro-eu", Type: models.Custom, QuotaLimits: map[string]int64{"api": 5000}})
+		require.NoError(t, err)
+		assert.Equal(t, "Pro", plan.Name)
+		assert.Equal(t, models.Custom, plan.Type)
+		assert.Equal(t, "For growing teams", plan.Description)
+		assert.Equal(t, map[string]int64{"api": 5000}, plans.clones[0].QuotaLimits)
+	})
+
+	t.Run("Rejects overrides without a quota", func(t *testing.T) {
+		for _, slug := range []string{"seats", "storage"} {
+			_, err := svc.ClonePlan(ctx, "pro", models.ClonePlanInput{Slug: "pro-eu", QuotaLimits: map[string]int64{slug: 10}})
+			assert.Equal(t, string(domainerrors.EINVALID), domainerrors.GetErrorCode(err), slug)
+		}
+		assert.Len(t, plans.clones, 1)
+	})
+}
+
+func TestPlanManagementService_CreateAssignmentSingleBasePlan(t *testing.T) {
+	ctx := context.Background()
+	base := &models.Plan{Base: models.Base{ID: uuid.New()}, Slug: "pro", Kind: models.PlanKindBase}
+	addon := &models.Plan{Base: models.Base{ID: uuid.New()}, Slug: "extra-seats", Kind: models.PlanKindAddon}
+	versions := &fakePlanVersionStore{versions: []models.PlanVersion{{ID: uuid.New(), Version: 1}}}
+	none := &pagination.PaginationView[models.PlanAssignment]{}
+
+	t.Run("Rejects a second base plan", func(t *testing.T) {
+		assignments := new(MockPlanAssignmentsStoreRepository)
+		assignments.On("CountOverlappingBaseAssignments", mock.Anything, mock.Anything).Return(int64(1), nil)
+		svc := NewPlanService(&fakePlanStore{plan: base}, nil, nil, assignments, nil, nil, versions, nil, nil)
+
+		_, err := svc.CreateAssignment(ctx, models.CreateAssignmentInput{PlanID: &base.ID, OrganizationID: "org-a"})
+		assert.Equal(t, string(domainerrors.ECONFLICT), domainerrors.GetErrorCode(err))
+		assignments.AssertNotCalled(t, "CreateAssignment", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Stacks add-ons on the base plan", func(t *testing.T) {
+		assignments := new(MockPlanAssignmentsStoreRepository)
+		assignments.On("ListAssignments", mock.Anything, mock.Anything, mock.Anything).Return(none, nil)
+		assignments.On("ListActiveSubjectAssignments", mock.Anything, mock.Anything).Return([]models.PlanAssignment{}, nil)
+		assignments.On("CreateAssignment", mock.Anything, mock.Anything).Return(&models.PlanAssignment{PlanID: addon.ID.String()}, nil)
+		svc := NewPlanService(&fakePlanStore{plan: addon}, nil, nil, assignments, nil, nil, versions, nil, nil)
+
+		_, err := svc.CreateAssignment(ctx, models.CreateAssignmentInput{PlanID: &addon.ID, OrganizationID: "org-a"})
+		require.NoError(t, err)
+		assignments.AssertNotCalled(t, "CountOverlappingBaseAssignments", mock.Anything, mock.Anything)
+	})
+}
+
+func TestPlanManagementService_CreateAssignmentAddonQuotaPeriods(t *testing.T) {
+	ctx := context.Background()
+	addon := &models.Plan{Base: models.Base{ID: uuid.New()}, Slug: "extra-calls", Kind: models.PlanKindAddon}
+	baseVersion, addonVersion := uuid.New(), uuid.New()
+	versions := &fakePlanVersionStore{versions: []models.PlanVersion{{ID: addonVersion, Version: 1}}}
+	none := &pagination.PaginationView[models.PlanAssignment]{}
+	apiID, seatsID := uuid.New(), uuid.New()
+	baseAPI, baseSeats, addonAPI, addonSeats := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	planFeatures := &fakePlanFeatureStore{versions: map[uuid.UUID][]models.PlanFeature{
+		baseVersion: {
+			{Base: models.Base{ID: baseAPI}, FeatureID: apiID, FeatureSlug: "api"},
+			{Base: models.Base{ID: baseSeats}, FeatureID: seatsID, FeatureSlug: "seats"},
+		},
+		addonVersion: {
+			{Base: models.Base{ID: addonAPI}, FeatureID: apiID, FeatureSlug: "api"},
+			{Base: models.Base{ID: addonSeats}, FeatureID: seatsID, FeatureSlug: "seats"},
+		},
+	}}
+
+	tests := []struct {
+		name     string
+		addonAPI *models.PlanFeatureQuota
+		wantErr  bool
+	}{
+		{name: "Accepts quotas over the same period", addonAPI: &models.PlanFeatureQuota{LimitValue: 500, ResetPeriod: models.MeteredResetPeriodMonth}},
+		{name: "Rejects quotas over another period", addonAPI: &models.PlanFeatureQuota{LimitValue: 100, ResetPeriod: models.MeteredResetPeriodDay}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			quotas := &fakeQuotaStore{quotas: map[uuid.UUID]*models.PlanFeatureQuota{
+				baseAPI:  {LimitValue: 1000, ResetPeriod: models.MeteredResetPeriodMonth},
+				addonAPI: tt.addonAPI,
+				// The base plan has no quota on seats, which the add-on cannot change
+				addonSeats: {LimitValue: 5, ResetPeriod: models.MeteredResetPeriodNever},
+			}}
+			assignments := new(MockPlanAssignmentsStoreRepository)
+			assignments.On("ListAssignments", mock.Anything, mock.Anything, mock.Anything).Return(none, nil)
+			assignments.On("ListActiveSubjectAssignments", mock.Anything, mock.Anything).Return([]models.PlanAssignment{
+				{Base: models.Base{ID: uuid.New()}, PlanKind: models.PlanKindBase, PlanVersionID: baseVersion.String()},
+			}, nil)
+			assignments.On("CreateAssignment", mock.Anything, mock.Anything).Return(&models.PlanAssignment{PlanID: addon.ID.String()}, nil)
+			svc := NewPlanService(&fakePlanStore{plan: addon}, nil, planFeatures, assignments, quotas, nil, versions, nil, nil)
+
+			_, err := svc.CreateAssignment(ctx, models.CreateAssignmentInput{PlanID: &addon.ID, OrganizationID: "org-a"})
+			if tt.wantErr {
+				assert.Equal(t, string(domainerrors.EINVALID), domainerrors.GetErrorCode(err))
+				assignments.AssertNotCalled(t, "CreateAssignment", mock.Anything, mock.Anything)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func (f *fakePlanFeatureStore) GetPlanFeatureIDByPlanAndFeature(ctx context.Context, planID, featureID uuid.UUID) (uuid.UUID, error) {
+	for _, feature := range f.features {
+		if feature.PlanID == planID && feature.FeatureID == featureID {
+			return feature.ID, nil
+		}
+	}
+	return uuid.Nil, domainerrors.New(nil, domainerrors.ENOTFOUND, "Resource not found")
+}
+
+// UpdatePlanFeatureQuota applies an update like the store does
+func (f *fakeQuotaStore) UpdatePlanFeatureQuota(ctx context.Context, arg models.UpdatePlanFeatureQuotaInput) (*models.PlanFeatureQuota, error) {
+	quota := f.quotas[uuid.MustParse(arg.PlanFeatureID)]
+	if arg.LimitValue != 0 {
+		quota.LimitValue = arg.LimitValue
+	}
+	if arg.ClearSoftLimitValue {
+		quota.SoftLimitValue = nil
+	} else if arg.SoftLimitValue != nil {
+		quota.SoftLimitValue = arg.SoftLimitValue
+	}
+	if arg.ClearOverageAllowance {
+		quota.OverageAllowance, quota.OverageAllowanceType = nil, ""
+	} else {
+		if arg.OverageAllowance != nil {
+			quota.OverageAllowance = arg.OverageAllowance
+		}
+		if arg.OverageAllowanceType != "" {
+			quota.OverageAllowanceType = arg.OverageAllowanceType
+		}
+	}
+	if arg.ClearGracePeriodMinutes {
+		quota.GracePeriodMinutes = nil
+	} else if arg.GracePeriodMinutes != nil {
+		quota.GracePeriodMinutes = arg.GracePeriodMinutes
+	}
+	copied := *quota
+	return &copied, nil
+}
+
+func TestPlanManagementService_UpdatePlanFeatureQuota(t *testing.T) {
+	ctx := context.Background()
+	planID, featureID, planFeatureID := uuid.New(), uuid.New(), uuid.New()
+	newService := func() (*PlanManagementService, *fakeQuotaStore) {
+		quotas := &fakeQuotaStore{quotas: map[uuid.UUID]*models.PlanFeatureQuota{planFeatureID: {
+			LimitValue: 1000, ResetPeriod: models.MeteredResetPeriodMonth, ActionAtLimit: models.MeteredActionAtLimitBlock,
+			SoftLimitValue: ptr(int64(800)), OverageAllowance: ptr(int64(10)), OverageAllowanceType: models.QuotaOveragePercent,
+			GracePeriodMinutes: ptr(int64(60)),
+		}}}
+		planFeatures := &fakePlanFeatureStore{features: []models.PlanFeature{{Base: models.Base{ID: planFeatureID}, PlanID: planID, FeatureID: featureID}}}
+		return &PlanManagementService{planFeatureStore: planFeatures, planFeatureQuotaRepo: quotas}, quotas
+	}
+
+	t.Run("Clears the soft limit, overage allowance and grace period", func(t *testing.T) {
+		svc, _ := newService()
+		quota, err := svc.UpdatePlanFeatureQuota(ctx, models.UpdatePlanFeatureQuotaInput{
+			ClearSoftLimitValue:     true,
+			ClearOverageAllowance:   true,
+			ClearGracePeriodMinutes: true,
+		}, planID.String(), featureID.String())
+		require.NoError(t, err)
+		assert.Nil(t, quota.SoftLimitValue)
+		assert.Nil(t, quota.OverageAllowance)
+		assert.Empty(t, quota.OverageAllowanceType)
+		assert.Nil(t, quota.GracePeriodMinutes)
+		assert.Equal(t, int64(1000), quota.LimitValue)
+	})
+
+	t.Run("Keeps fields that are not cleared", func(t *testing.T) {
+		svc, _ := newService()
+		quota, err := svc.UpdatePlanFeatureQuota(ctx, models.UpdatePlanFeatureQuotaInput{
+			SoftLimitValue:        ptr(int64(900)),
+			ClearOverageAllowance: true,
+		}, planID.String(), featureID.String())
+		require.NoError(t, err)
+		assert.Equal(t, int64(900), *quota.SoftLimitValue)
+		assert.Nil(t, quota.OverageAllowance)
+		assert.Equal(t, int64(60), *quota.GracePeriodMinutes)
+	})
+
+	t.Run("Validates the quota it ends up with", func(t *testing.T) {
+		svc, quotas := newService()
+		_, err := svc.UpdatePlanFeatureQuota(ctx, models.UpdatePlanFeatureQuotaInput{
+			ClearSoftLimitValue: true,
+			LimitValue:          500,
+			OverageAllowance:    ptr(int64(-1)),
+		}, planID.String(), featureID.String())
+		assert.Equal(t, string(domainerrors.EINVALID), domainerrors.GetErrorCode(err))
+		assert.Equal(t, int64(800), *quotas.quotas[planFeatureID].SoftLimitValue, "a rejected update changes nothing")
+	})
+}
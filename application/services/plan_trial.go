@@ -0,0 +1,268 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+	domainerrors "github.com/redcardinal-io/metering/domain/errors"
+	"github.com/redcardinal-io/metering/domain/models"
+	"github.com/redcardinal-io/metering/domain/pkg/pagination"
+)
+
+// trialProcessorActor is recorded as the actor of the trials ended by the trial processor
+const trialProcessorActor = "trial-processor"
+
+// CreateTrial assigns a plan as a trial lasting the given number of days. When the trial ends the
+// subject is converted to the target plan, moved onto the fallback plan or left without a plan, as
+// the end action says. The trial assignment and the one it ends on are notified like any other.
+func (s *PlanManagementService) CreateTrial(ctx context.Context, arg models.CreateTrialInput) (*models.Trial, error) {
+	const op = "PlanManagementService.CreateTrial"
+	if arg.EndAction == "" {
+		arg.EndAction = models.TrialEndActionConvert
+	}
+	switch {
+	case (arg.Assignment.OrganizationID == "") == (arg.Assignment.UserID == ""):
+		return nil, invalidTrial(fmt.Errorf("exactly one of organization_id and user_id is required"), op)
+	case arg.TrialDays <= 0:
+		return nil, invalidTrial(fmt.Errorf("trial_days must be greater than 0"), op)
+	case !models.IsValidTrialEndAction(arg.EndAction):
+		return nil, invalidTrial(fmt.Errorf("invalid trial end action: %s", arg.EndAction), op)
+	case arg.TargetPlanID == nil:
+		return nil, invalidTrial(fmt.Errorf("target_plan_id is required"), op)
+	case arg.EndAction == models.TrialEndActionFallback && arg.FallbackPlanID == nil:
+		return nil, invalidTrial(fmt.Errorf("the fallback end action requires a fallback plan"), op)
+	}
+
+	now := s.now().UTC()
+	if arg.Assignment.ValidFrom.IsZero() {
+		arg.Assignment.ValidFrom = now
+	}
+	arg.Assignment.ValidUntil = arg.Assignment.ValidFrom.AddDate(0, 0, arg.TrialDays)
+
+	plan, err := s.trialPlan(ctx, *arg.Assignment.PlanID, "", op)
+	if err != nil {
+		return nil, err
+	}
+	for _, planID := range []*uuid.UUID{arg.TargetPlanID, arg.FallbackPlanID} {
+		if planID != nil {
+			if _, err := s.trialPlan(ctx, *planID, plan.Kind, op); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if plan.Kind != models.PlanKindAddon {
+		if err := s.validateSingleBasePlan(ctx, arg.Assignment); err != nil {
+			return nil, err
+		}
+	}
+	arg.Assignment.PlanVersionID, err = s.assignmentVersion(ctx, plan.ID, arg.Assignment)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.validateQuotaPeriods(ctx, arg.Assignment, plan.Kind, uuid.Nil, op); err != nil {
+		return nil, err
+	}
+
+	trial, err := s.trialStore.CreateTrial(ctx, arg)
+	if err != nil {
+		return nil, err
+	}
+	s.notifyAssignmentCreated(ctx, trial.AssignmentID)
+	return withDaysRemaining(trial, now), nil
+}
+
+// trialPlan returns a plan a trial runs on or ends on, rejecting archived plans and, when kind is
+// set, plans of another kind.
+func (s *PlanManagementService) trialPlan(ctx context.Context, planID uuid.UUID, kind models.PlanKindEnum, op string) (*models.Plan, error) {
+	plan, err := s.planStore.GetPlanByIDorSlug(ctx, planID.String())
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case !plan.ArchivedAt.IsZero():
+		return nil, invalidTrial(fmt.Errorf("plan %s is archived and cannot be assigned", plan.ID), op)
+	case kind != "" && plan.Kind != kind:
+		return nil, invalidTrial(fmt.Errorf("a trial of a %s plan cannot end on a %s plan", kind, plan.Kind), op)
+	}
+	return plan, nil
+}
+
+// GetTrial returns a trial with the days it has left.
+func (s *PlanManagementService) GetTrial(ctx context.Context, id uuid.UUID) (*models.Trial, error) {
+	trial, err := s.trialStore.GetTrial(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return withDaysRemaining(trial, s.now().UTC()), nil
+}
+
+// ListTrials lists trials, ending soonest first, with the days they have left.
+func (s *PlanManagementService) ListTrials(ctx context.Context, arg models.QueryTrialInput, pagination pagination.Pagination) (*pagination.PaginationView[models.Trial], error) {
+	if arg.Status != "" && !models.IsValidTrialStatus(arg.Status) {
+		return nil, invalidTrial(fmt.Errorf("invalid trial status: %s", arg.Status), "PlanManagementService.ListTrials")
+	}
+	trials, err := s.trialStore.ListTrials(ctx, arg, pagination)
+	if err != nil {
+		return nil, err
+	}
+	now := s.now().UTC()
+	for i := range trials.Results {
+		withDaysRemaining(&trials.Results[i], now)
+	}
+	return trials, nil
+}
+
+// ExtendTrial moves the end of an active trial the given number of days further.
+func (s *PlanManagementService) ExtendTrial(ctx context.Context, id uuid.UUID, days int, updatedBy string) (*models.Trial, error) {
+	const op = "PlanManagementService.ExtendTrial"
+	if days <= 0 {
+		return nil, invalidTrial(fmt.Errorf("days must be greater than 0"), op)
+	}
+	trial, err := s.trialStore.GetTrial(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if trial.Status != models.TrialActive {
+		return nil, domainerrors.New(
+			fmt.Errorf("trial %s is %s", id, trial.Status),
+			domainerrors.ECONFLICT,
+			fmt.Sprintf("trial is %s, only active trials can be extended", trial.Status),
+			domainerrors.WithOperation(op),
+		)
+	}
+
+	until := trial.EndsAt.AddDate(0, 0, days)
+	plan, err := s.planStore.GetPlanByIDorSlug(ctx, trial.PlanID.String())
+	if err != nil {
+		return nil, err
+	}
+	if plan.Kind != models.PlanKindAddon {
+		if err := s.validateSingleBasePlan(ctx, models.CreateAssignmentInput{
+			OrganizationID: trial.OrganizationID,
+			UserID:         trial.UserID,
+			ValidFrom:      trial.EndsAt,
+			ValidUntil:     until,
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	trial, err = s.trialStore.ExtendTrial(ctx, id, until, updatedBy)
+	if err != nil {
+		return nil, err
+	}
+	return withDaysRemaining(trial, s.now().UTC()), nil
+}
+
+// endTrial ends a trial that ran out, moving the subject onto the plan the end action asks for
+// from the end of the trial on. A convert falls back to the fallback plan and a fallback expires
+// the trial when the plan is gone, archived, never published, would overlap another base plan of
+// the subject or has quotas resetting over other periods than the plans it would be held with.
+func (s *PlanManagementService) endTrial(ctx context.Context, trial models.Trial, now time.Time) (*models.Trial, error) {
+	type outcome struct {
+		planID *uuid.UUID
+		status models.TrialStatus
+	}
+	var outcomes []outcome
+	switch trial.EndAction {
+	case models.TrialEndActionConvert:
+		outcomes = []outcome{{&trial.TargetPlanID, models.TrialConverted}, {trial.FallbackPlanID, models.TrialFellBack}}
+	case models.TrialEndActionFallback:
+		outcomes = []outcome{{trial.FallbackPlanID, models.TrialFellBack}}
+	}
+
+	end := models.EndTrialInput{Trial: trial, Status: models.TrialExpired, At: now, EndedBy: trialProcessorActor}
+	for _, o := range outcomes {
+		if o.planID == nil {
+			continue
+		}
+		to, err := s.trialEndAssignment(ctx, trial, *o.planID)
+		if err != nil {
+			return nil, err
+		}
+		if to != nil {
+			end.Status, end.To = o.status, to
+			break
+		}
+	}
+
+	ended, err := s.trialStore.EndTrial(ctx, end)
+	if err != nil {
+		return nil, err
+	}
+	if ended.ResultingAssignmentID != nil {
+		s.notifyAssignmentCreated(ctx, *ended.ResultingAssignmentID)
+	}
+	notify(ctx, s.notifiers, models.NotificationTrialEnded, ended)
+	return ended, nil
+}
+
+// trialEndAssignment returns the open-ended assignment of the plan starting when the trial ends,
+// or nil when the plan cannot be assigned to the subject anymore.
+func (s *PlanManagementService) trialEndAssignment(ctx context.Context, trial models.Trial, planID uuid.UUID) (*models.CreateAssignmentInput, error) {
+	plan, err := s.planStore.GetPlanByIDorSlug(ctx, planID.String())
+	if domainerrors.GetErrorCode(err) == string(domainerrors.ENOTFOUND) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !plan.ArchivedAt.IsZero() {
+		return nil, nil
+	}
+
+	to := &models.CreateAssignmentInput{
+		OrganizationID: trial.OrganizationID,
+		UserID:         trial.UserID,
+		PlanID:         &plan.ID,
+		ValidFrom:      trial.EndsAt,
+		CreatedBy:      trialProcessorActor,
+	}
+	if plan.Kind != models.PlanKindAddon {
+		err := s.validateSingleBasePlan(ctx, *to)
+		if domainerrors.GetErrorCode(err) == string(domainerrors.ECONFLICT) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	to.PlanVersionID, err = s.assignmentVersion(ctx, plan.ID, *to)
+	if domainerrors.GetErrorCode(err) == string(domainerrors.EINVALID) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	err = s.validateQuotaPeriods(ctx, *to, plan.Kind, trial.AssignmentID, "PlanManagementService.endTrial")
+	if domainerrors.GetErrorCode(err) == string(domainerrors.EINVALID) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return to, nil
+}
+
+// withDaysRemaining sets the whole days an active trial has left, counting a started day as a
+// full one; ended trials have none.
+func withDaysRemaining(trial *models.Trial, now time.Time) *models.Trial {
+	trial.DaysRemaining = 0
+	if trial.Status == models.TrialActive && trial.EndsAt.After(now) {
+		trial.DaysRemaining = int(math.Ceil(trial.EndsAt.Sub(now).Hours() / 24))
+	}
+	return trial
+}
+
+func invalidTrial(err error, op string) error {
+	return domainerrors.New(
+		err,
+		domainerrors.EINVALID,
+		"invalid trial",
+		domainerrors.WithOperation(op),
+	)
+}
@@ -0,0 +1,328 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/redcardinal-io/metering/application/repositories"
+	domainerrors "github.com/redcardinal-io/metering/domain/errors"
+	"github.com/redcardinal-io/metering/domain/models"
+	"github.com/redcardinal-io/metering/domain/pkg/config"
+	"github.com/redcardinal-io/metering/domain/pkg/logger"
+	"github.com/redcardinal-io/metering/domain/pkg/pagination"
+)
+
+// fakePlanCatalog serves several plans by ID
+type fakePlanCatalog struct {
+	repositories.PlanStoreRepository
+	plans []*models.Plan
+}
+
+func (f *fakePlanCatalog) GetPlanByIDorSlug(ctx context.Context, idOrSlug string) (*models.Plan, error) {
+	for _, plan := range f.plans {
+		if plan.ID.String() == idOrSlug || plan.Slug == idOrSlug {
+			return plan, nil
+		}
+	}
+	return nil, domainerrors.New(nil, domainerrors.ENOTFOUND, "Resource not found")
+}
+
+// fakeTrialStore keeps trials and records the ones created, extended and ended
+type fakeTrialStore struct {
+	repositories.TrialStoreRepository
+	trials   []models.Trial
+	created  []models.CreateTrialInput
+	extended []time.Time
+	ended    []models.EndTrialInput
+}
+
+func (f *fakeTrialStore) CreateTrial(ctx context.Context, arg models.CreateTrialInput) (*models.Trial, error) {
+	f.created = append(f.created, arg)
+	return &models.Trial{
+		Base:         models.Base{ID: uuid.New()},
+		AssignmentID: uuid.New(),
+		PlanID:       *arg.Assignment.PlanID,
+		TargetPlanID: *arg.TargetPlanID,
+		EndAction:    arg.EndAction,
+		Status:       models.TrialActive,
+		StartsAt:     arg.Assignment.ValidFrom,
+		EndsAt:       arg.Assignment.ValidUntil,
+	}, nil
+}
+
+func (f *fakeTrialStore) GetTrial(ctx context.Context, id uuid.UUID) (*models.Trial, error) {
+	for i := range f.trials {
+		if f.trials[i].ID == id {
+			trial := f.trials[i]
+			return &trial, nil
+		}
+	}
+	return nil, domainerrors.New(nil, domainerrors.ENOTFOUND, "Resource not found")
+}
+
+func (f *fakeTrialStore) ListDueTrials(ctx context.Context, at time.Time, limit int) ([]models.Trial, error) {
+	return f.trials, nil
+}
+
+func (f *fakeTrialStore) ExtendTrial(ctx context.Context, id uuid.UUID, until time.Time, updatedBy string) (*models.Trial, error) {
+	f.extended = append(f.extended, until)
+	trial, err := f.GetTrial(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	trial.EndsAt = until
+	return trial, nil
+}
+
+func (f *fakeTrialStore) EndTrial(ctx context.Context, arg models.EndTrialInput) (*models.Trial, error) {
+	f.ended = append(f.ended, arg)
+	trial := arg.Trial
+	trial.Status = arg.Status
+	trial.EndedAt = &arg.At
+	if arg.To != nil {
+		id := uuid.New()
+		trial.ResultingAssignmentID = &id
+	}
+	return &trial, nil
+}
+
+type fakeNotifier struct {
+	notifications []models.Notification
+}
+
+func (f *fakeNotifier) Notify(ctx context.Context, notification models.Notification) {
+	f.notifications = append(f.notifications, notification)
+}
+
+func TestPlanManagementService_CreateTrial(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 6, 18, 12, 0, 0, 0, time.UTC)
+	trialPlan := &models.Plan{Base: models.Base{ID: uuid.New()}, Slug: "pro-trial", Kind: models.PlanKindBase}
+	pro := &models.Plan{Base: models.Base{ID: uuid.New()}, Slug: "pro", Kind: models.PlanKindBase}
+	addon := &models.Plan{Base: models.Base{ID: uuid.New()}, Slug: "extra-seats", Kind: models.PlanKindAddon}
+	archived := &models.Plan{Base: models.Base{ID: uuid.New()}, Slug: "legacy", Kind: models.PlanKindBase, ArchivedAt: now.AddDate(0, -1, 0)}
+	v1 := models.PlanVersion{ID: uuid.New(), Version: 1}
+
+	newService := func(trials *fakeTrialStore, notifier *fakeNotifier) *PlanManagementService {
+		assignments := new(MockPlanAssignmentsStoreRepository)
+		assignments.On("CountOverlappingBaseAssignments", mock.Anything, mock.Anything).Return(int64(0), nil)
+		assignments.On("ListAssignments", mock.Anything, mock.Anything, mock.Anything).Return(&pagination.PaginationView[models.PlanAssignment]{}, nil)
+		assignments.On("ListActiveSubjectAssignments", mock.Anything, mock.Anything).Return([]models.PlanAssignment{}, nil)
+		assignments.On("GetAssignment", mock.Anything, mock.Anything).Return(&models.PlanAssignment{PlanID: trialPlan.ID.String(), OrganizationID: "org-a"}, nil)
+		plans := &fakePlanCatalog{plans: []*models.Plan{trialPlan, pro, addon, archived}}
+		svc := NewPlanService(plans, nil, nil, assignments, nil, nil, &fakePlanVersionStore{versions: []models.PlanVersion{v1}}, nil, trials, notifier)
+		svc.now = func() time.Time { return now }
+		return svc
+	}
+	trial := models.CreateTrialInput{
+		Assignment:   models.CreateAssignmentInput{PlanID: &trialPlan.ID, OrganizationID: "org-a", CreatedBy: "sales"},
+		TrialDays:    14,
+		TargetPlanID: &pro.ID,
+	}
+
+	t.Run("Runs for the trial days and converts by default", func(t *testing.T) {
+		trials, notifier := &fakeTrialStore{}, &fakeNotifier{}
+		svc := newService(trials, notifier)
+		created, err := svc.CreateTrial(ctx, trial)
+		require.NoError(t, err)
+
+		require.Len(t, trials.created, 1)
+		assert.Equal(t, models.TrialEndActionConvert, trials.created[0].EndAction)
+		assert.Equal(t, now, trials.created[0].Assignment.ValidFrom)
+		assert.Equal(t, now.AddDate(0, 0, 14), trials.created[0].Assignment.ValidUntil)
+		assert.Equal(t, v1.ID, trials.created[0].Assignment.PlanVersionID)
+		assert.Equal(t, 14, created.DaysRemaining)
+
+		require.Len(t, notifier.notifications, 1)
+		assert.Equal(t, models.NotificationAssignmentCreated, notifier.notifications[0].Type)
+		svc.planAssignmentsStore.(*MockPlanAssignmentsStoreRepository).AssertCalled(t, "GetAssignment", mock.Anything, created.AssignmentID)
+	})
+
+	rejected := map[string]func(arg *models.CreateTrialInput){
+		"no trial days":                 func(arg *models.CreateTrialInput) { arg.TrialDays = 0 },
+		"no target plan":                func(arg *models.CreateTrialInput) { arg.TargetPlanID = nil },
+		"fallback without a plan":       func(arg *models.CreateTrialInput) { arg.EndAction = models.TrialEndActionFallback },
+		"invalid end action":            func(arg *models.CreateTrialInput) { arg.EndAction = "renew" },
+		"archived target plan":          func(arg *models.CreateTrialInput) { arg.TargetPlanID = &archived.ID },
+		"target plan of another kind":   func(arg *models.CreateTrialInput) { arg.TargetPlanID = &addon.ID },
+		"fallback plan of another kind": func(arg *models.CreateTrialInput) { arg.FallbackPlanID = &addon.ID },
+	}
+	for name, modify := range rejected {
+		t.Run("Rejects "+name, func(t *testing.T) {
+			trials := &fakeTrialStore{}
+			arg := trial
+			modify(&arg)
+			_, err := newService(trials, &fakeNotifier{}).CreateTrial(ctx, arg)
+			assert.Equal(t, string(domainerrors.EINVALID), domainerrors.GetErrorCode(err))
+			assert.Empty(t, trials.created)
+		})
+	}
+}
+
+func TestTrialProcessor_Process(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 6, 18, 12, 0, 0, 0, time.UTC)
+	pro := &models.Plan{Base: models.Base{ID: uuid.New()}, Slug: "pro", Kind: models.PlanKindBase}
+	free := &models.Plan{Base: models.Base{ID: uuid.New()}, Slug: "free", Kind: models.PlanKindBase}
+	archived := &models.Plan{Base: models.Base{ID: uuid.New()}, Slug: "legacy", Kind: models.PlanKindBase, ArchivedAt: now.AddDate(0, -1, 0)}
+	v1 := models.PlanVersion{ID: uuid.New(), Version: 1}
+	endsAt := now.Add(-time.Hour)
+
+	due := func(target uuid.UUID, fallback *uuid.UUID, action models.TrialEndAction) models.Trial {
+		return models.Trial{
+			Base:           models.Base{ID: uuid.New()},
+			TenantSlug:     "acme",
+			PlanID:         uuid.New(),
+			OrganizationID: "org-a",
+			TargetPlanID:   target,
+			FallbackPlanID: fallback,
+			EndAction:      action,
+			Status:         models.TrialActive,
+			EndsAt:         endsAt,
+		}
+	}
+	tests := []struct {
+		name   string
+		trial  models.Trial
+		status models.TrialStatus
+		plan   *models.Plan
+	}{
+		{"converts to the target plan", due(pro.ID, &free.ID, models.TrialEndActionConvert), models.TrialConverted, pro},
+		{"falls back when the target plan is archived", due(archived.ID, &free.ID, models.TrialEndActionConvert), models.TrialFellBack, free},
+		{"falls back to the fallback plan", due(pro.ID, &free.ID, models.TrialEndActionFallback), models.TrialFellBack, free},
+		{"expires when no plan can be assigned", due(archived.ID, nil, models.TrialEndActionConvert), models.TrialExpired, nil},
+		{"expires without a replacement", due(pro.ID, &free.ID, models.TrialEndActionExpire), models.TrialExpired, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assignments := new(MockPlanAssignmentsStoreRepository)
+			assignments.On("CountOverlappingBaseAssignments", mock.Anything, mock.Anything).Return(int64(0), nil)
+			assignments.On("ListAssignments", mock.Anything, mock.Anything, mock.Anything).Return(&pagination.PaginationView[models.PlanAssignment]{}, nil)
+			assignments.On("ListActiveSubjectAssignments", mock.Anything, mock.Anything).Return([]models.PlanAssignment{}, nil)
+			assignments.On("GetAssignment", mock.Anything, mock.Anything).Return(&models.PlanAssignment{OrganizationID: "org-a"}, nil)
+			trials := &fakeTrialStore{trials: []models.Trial{tt.trial}}
+			notifier := &fakeNotifier{}
+			plans := &fakePlanCatalog{plans: []*models.Plan{pro, free, archived}}
+			svc := NewPlanService(plans, nil, nil, assignments, nil, nil, &fakePlanVersionStore{versions: []models.PlanVersion{v1}}, nil, trials, notifier)
+			svc.now = func() time.Time { return now }
+			processor := NewTrialProcessor(svc, trials, config.TrialProcessorConfig{BatchSize: 10}, &logger.Logger{Logger: zap.NewNop()})
+
+			ended, err := processor.Process(ctx)
+			require.NoError(t, err)
+			require.Len(t, ended, 1)
+			assert.Equal(t, tt.status, ended[0].Status)
+
+			require.Len(t, trials.ended, 1)
+			end := trials.ended[0]
+			assert.Equal(t, now, end.At)
+			if tt.plan == nil {
+				assert.Nil(t, end.To)
+			} else {
+				require.NotNil(t, end.To)
+				assert.Equal(t, tt.plan.ID, *end.To.PlanID)
+				assert.Equal(t, endsAt, end.To.ValidFrom, "the plan starts when the trial ends")
+				assert.True(t, end.To.ValidUntil.IsZero())
+				assert.Equal(t, v1.ID, end.To.PlanVersionID)
+			}
+
+			var types []models.NotificationType
+			for _, n := range notifier.notifications {
+				types = append(types, n.Type)
+				assert.Equal(t, "acme", n.TenantSlug)
+			}
+			if tt.plan == nil {
+				assert.Equal(t, []models.NotificationType{models.NotificationTrialEnded}, types)
+			} else {
+				assert.Equal(t, []models.NotificationType{models.NotificationAssignmentCreated, models.NotificationTrialEnded}, types, "the assignment the trial ends on is notified")
+				assignments.AssertCalled(t, "GetAssignment", mock.Anything, *ended[0].ResultingAssignmentID)
+			}
+		})
+	}
+}
+
+func TestTrialProcessor_ProcessQuotaPeriods(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 6, 18, 12, 0, 0, 0, time.UTC)
+	pro := &models.Plan{Base: models.Base{ID: uuid.New()}, Slug: "pro", Kind: models.PlanKindBase}
+	free := &models.Plan{Base: models.Base{ID: uuid.New()}, Slug: "free", Kind: models.PlanKindBase}
+	proVersion := models.PlanVersion{ID: uuid.New(), PlanID: pro.ID, Version: 1}
+	freeVersion := models.PlanVersion{ID: uuid.New(), PlanID: free.ID, Version: 1}
+	addonVersion, apiID := uuid.New(), uuid.New()
+	proAPI, freeAPI, addonAPI := uuid.New(), uuid.New(), uuid.New()
+	planFeatures := &fakePlanFeatureStore{versions: map[uuid.UUID][]models.PlanFeature{
+		proVersion.ID:  {{Base: models.Base{ID: proAPI}, FeatureID: apiID, FeatureSlug: "api"}},
+		freeVersion.ID: {{Base: models.Base{ID: freeAPI}, FeatureID: apiID, FeatureSlug: "api"}},
+		addonVersion:   {{Base: models.Base{ID: addonAPI}, FeatureID: apiID, FeatureSlug: "api"}},
+	}}
+	quotas := &fakeQuotaStore{quotas: map[uuid.UUID]*models.PlanFeatureQuota{
+		proAPI:   {LimitValue: 1000, ResetPeriod: models.MeteredResetPeriodMonth},
+		freeAPI:  {LimitValue: 10, ResetPeriod: models.MeteredResetPeriodDay},
+		addonAPI: {LimitValue: 100, ResetPeriod: models.MeteredResetPeriodDay},
+	}}
+
+	assignments := new(MockPlanAssignmentsStoreRepository)
+	assignments.On("CountOverlappingBaseAssignments", mock.Anything, mock.Anything).Return(int64(0), nil)
+	assignments.On("ListAssignments", mock.Anything, mock.Anything, mock.Anything).Return(&pagination.PaginationView[models.PlanAssignment]{}, nil)
+	// The subject holds a daily add-on next to its trial
+	assignments.On("ListActiveSubjectAssignments", mock.Anything, mock.Anything).Return([]models.PlanAssignment{
+		{Base: models.Base{ID: uuid.New()}, PlanKind: models.PlanKindAddon, PlanVersionID: addonVersion.String(), OrganizationID: "org-a"},
+	}, nil)
+	trials := &fakeTrialStore{trials: []models.Trial{{
+		Base:           models.Base{ID: uuid.New()},
+		TenantSlug:     "acme",
+		PlanID:         uuid.New(),
+		OrganizationID: "org-a",
+		TargetPlanID:   pro.ID,
+		FallbackPlanID: &free.ID,
+		EndAction:      models.TrialEndActionConvert,
+		Status:         models.TrialActive,
+		EndsAt:         now.Add(-time.Hour),
+	}}}
+	plans := &fakePlanCatalog{plans: []*models.Plan{pro, free}}
+	versions := &fakePlanVersionStore{versions: []models.PlanVersion{proVersion, freeVersion}}
+	svc := NewPlanService(plans, nil, planFeatures, assignments, quotas, nil, versions, nil, trials)
+	svc.now = func() time.Time { return now }
+	processor := NewTrialProcessor(svc, trials, config.TrialProcessorConfig{BatchSize: 10}, &logger.Logger{Logger: zap.NewNop()})
+
+	ended, err := processor.Process(ctx)
+	require.NoError(t, err)
+	require.Len(t, ended, 1)
+	// The monthly quota of the target plan cannot be combined with the daily add-on
+	assert.Equal(t, models.TrialFellBack, ended[0].Status)
+	require.NotNil(t, trials.ended[0].To)
+	assert.Equal(t, free.ID, *trials.ended[0].To.PlanID)
+}
+
+func TestPlanManagementService_ExtendTrial(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 6, 18, 12, 0, 0, 0, time.UTC)
+	plan := &models.Plan{Base: models.Base{ID: uuid.New()}, Slug: "pro-trial", Kind: models.PlanKindBase}
+	active := models.Trial{Base: models.Base{ID: uuid.New()}, PlanID: plan.ID, OrganizationID: "org-a", Status: models.TrialActive, EndsAt: now.Add(36 * time.Hour)}
+	converted := models.Trial{Base: models.Base{ID: uuid.New()}, PlanID: plan.ID, OrganizationID: "org-a", Status: models.TrialConverted, EndsAt: now.Add(-time.Hour)}
+	trials := &fakeTrialStore{trials: []models.Trial{active, converted}}
+
+	assignments := new(MockPlanAssignmentsStoreRepository)
+	assignments.On("CountOverlappingBaseAssignments", mock.Anything, mock.MatchedBy(func(arg models.CreateAssignmentInput) bool {
+		return arg.ValidFrom.Equal(active.EndsAt)
+	})).Return(int64(0), nil)
+	svc := NewPlanService(&fakePlanStore{plan: plan}, nil, nil, assignments, nil, nil, nil, nil, trials)
+	svc.now = func() time.Time { return now }
+
+	extended, err := svc.ExtendTrial(ctx, active.ID, 7, "support")
+	require.NoError(t, err)
+	assert.Equal(t, []time.Time{active.EndsAt.AddDate(0, 0, 7)}, trials.extended)
+	assert.Equal(t, 9, extended.DaysRemaining)
+
+	_, err = svc.ExtendTrial(ctx, converted.ID, 7, "support")
+	assert.Equal(t, string(domainerrors.ECONFLICT), domainerrors.GetErrorCode(err))
+	_, err = svc.ExtendTrial(ctx, active.ID, 0, "support")
+	assert.Equal(t, string(domainerrors.EINVALID), domainerrors.GetErrorCode(err))
+	assert.Len(t, trials.extended, 1)
+}
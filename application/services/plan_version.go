@@ -0,0 +1,188 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	domainerrors "github.com/redcardinal-io/metering/domain/errors"
+	"github.com/redcardinal-io/metering/domain/models"
+	"github.com/redcardinal-io/metering/domain/pkg/pagination"
+)
+
+// PublishPlanVersion publishes the draft features and quotas of the plan as its next version.
+// The draft stays editable; assignments keep the version they were made on until migrated.
+func (s *PlanManagementService) PublishPlanVersion(ctx context.Context, idOrSlug string, arg models.PublishPlanVersionInput) (*models.PlanVersion, error) {
+	plan, err := s.planStore.GetPlanByIDorSlug(ctx, idOrSlug)
+	if err != nil {
+		return nil, err
+	}
+	if !plan.ArchivedAt.IsZero() {
+		return nil, domainerrors.New(
+			fmt.Errorf("plan %s is archived", plan.Slug),
+			domainerrors.EINVALID,
+			"archived plans cannot be published",
+			domainerrors.WithOperation("PlanManagementService.PublishPlanVersion"),
+		)
+	}
+
+	version, err := s.planVersionStore.PublishPlanVersion(ctx, plan.ID, arg)
+	if err != nil {
+		return nil, err
+	}
+	version.Features, err = s.planFeatureStore.ListPlanFeaturesByVersion(ctx, version.ID, models.PlanFeatureListFilter{})
+	if err != nil {
+		return nil, err
+	}
+	return version, nil
+}
+
+// ListPlanVersions returns the published versions of the plan, newest first.
+func (s *PlanManagementService) ListPlanVersions(ctx context.Context, idOrSlug string) ([]models.PlanVersion, error) {
+	plan, err := s.planStore.GetPlanByIDorSlug(ctx, idOrSlug)
+	if err != nil {
+		return nil, err
+	}
+	return s.planVersionStore.ListPlanVersions(ctx, plan.ID)
+}
+
+// GetPlanVersion returns a published version of the plan together with its features.
+func (s *PlanManagementService) GetPlanVersion(ctx context.Context, idOrSlug string, version int) (*models.PlanVersion, error) {
+	plan, err := s.planStore.GetPlanByIDorSlug(ctx, idOrSlug)
+	if err != nil {
+		return nil, err
+	}
+	m, err := s.planVersionStore.GetPlanVersion(ctx, plan.ID, version)
+	if err != nil {
+		return nil, err
+	}
+	m.Features, err = s.planFeatureStore.ListPlanFeaturesByVersion(ctx, m.ID, models.PlanFeatureListFilter{})
+	if err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// MigratePlanVersion moves the current and future assignments of the plan to another of its
+// versions. With OnRenewal the assignments keep their version and their renewals are assigned the
+// target version instead.
+func (s *PlanManagementService) MigratePlanVersion(ctx context.Context, idOrSlug string, arg models.MigratePlanVersionInput) (*models.PlanVersionMigration, error) {
+	plan, err := s.planStore.GetPlanByIDorSlug(ctx, idOrSlug)
+	if err != nil {
+		return nil, err
+	}
+	to, err := s.planVersionStore.GetPlanVersion(ctx, plan.ID, arg.ToVersion)
+	if err != nil {
+		return nil, err
+	}
+	if arg.FromVersion > 0 {
+		if arg.FromVersion == arg.ToVersion {
+			return nil, domainerrors.New(
+				fmt.Errorf("cannot migrate version %d onto itself", arg.FromVersion),
+				domainerrors.EINVALID,
+				"from_version must differ from the target version",
+				domainerrors.WithOperation("PlanManagementService.MigratePlanVersion"),
+			)
+		}
+		from, err := s.planVersionStore.GetPlanVersion(ctx, plan.ID, arg.FromVersion)
+		if err != nil {
+			return nil, err
+		}
+		arg.FromVersionID = &from.ID
+	}
+
+	arg.PlanID = plan.ID
+	arg.ToVersionID = to.ID
+	arg.At = time.Now().UTC()
+	if err := s.validateMigrationQuotaPeriods(ctx, plan, arg); err != nil {
+		return nil, err
+	}
+	migrated, err := s.planAssignmentsStore.MigrateAssignments(ctx, arg)
+	if err != nil {
+		return nil, err
+	}
+	return &models.PlanVersionMigration{
+		Version:     to.Version,
+		OnRenewal:   arg.OnRenewal,
+		Assignments: migrated,
+	}, nil
+}
+
+// validateMigrationQuotaPeriods checks the target version against the plans held with each active
+// assignment the migration moves, as new assignments are, so that no quota ends up stacked with
+// one resetting over another period.
+func (s *PlanManagementService) validateMigrationQuotaPeriods(ctx context.Context, plan *models.Plan, arg models.MigratePlanVersionInput) error {
+	assignments, err := s.planAssignmentsStore.ListActiveAssignmentsByPlan(ctx, plan.ID, arg.At)
+	if err != nil {
+		return err
+	}
+	bySubject := len(arg.OrganizationIDs) > 0 || len(arg.UserIDs) > 0
+	for _, a := range assignments {
+		switch {
+		case a.PlanVersionID == arg.ToVersionID.String():
+			continue
+		case arg.FromVersionID != nil && a.PlanVersionID != arg.FromVersionID.String():
+			continue
+		case bySubject && !slices.Contains(arg.OrganizationIDs, a.OrganizationID) && !slices.Contains(arg.UserIDs, a.UserID):
+			continue
+		}
+		if err := s.validateQuotaPeriods(ctx, models.CreateAssignmentInput{
+			OrganizationID: a.OrganizationID,
+			UserID:         a.UserID,
+			PlanVersionID:  arg.ToVersionID,
+			ValidFrom:      arg.At,
+		}, plan.Kind, a.ID, "PlanManagementService.MigratePlanVersion"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// assignmentVersion resolves the plan version a new assignment is pinned to: the requested
+// version, else the version the subject's previous assignment of the plan was renewing onto or was
+// on, else the latest published version.
+func (s *PlanManagementService) assignmentVersion(ctx context.Context, planID uuid.UUID, arg models.CreateAssignmentInput) (uuid.UUID, error) {
+	if arg.PlanVersion > 0 {
+		version, err := s.planVersionStore.GetPlanVersion(ctx, planID, arg.PlanVersion)
+		if err != nil {
+			return uuid.Nil, err
+		}
+		return version.ID, nil
+	}
+
+	if arg.OrganizationID != "" || arg.UserID != "" {
+		previous, err := s.planAssignmentsStore.ListAssignments(ctx, models.QueryPlanAssignmentInput{
+			PlanID:         &planID,
+			OrganizationID: arg.OrganizationID,
+			UserID:         arg.UserID,
+		}, pagination.Pagination{Limit: 1, Page: 1})
+		if err != nil {
+			return uuid.Nil, err
+		}
+		if len(previous.Results) > 0 {
+			versionID := previous.Results[0].PendingPlanVersionID
+			if versionID == "" {
+				versionID = previous.Results[0].PlanVersionID
+			}
+			if id, err := uuid.Parse(versionID); err == nil {
+				return id, nil
+			}
+		}
+	}
+
+	latest, err := s.planVersionStore.GetLatestPlanVersion(ctx, planID)
+	if err != nil {
+		if domainerrors.GetErrorCode(err) == string(domainerrors.ENOTFOUND) {
+			return uuid.Nil, domainerrors.New(
+				fmt.Errorf("plan %s has no published version", planID),
+				domainerrors.EINVALID,
+				"plan has no published version, publish it before assigning it",
+				domainerrors.WithOperation("PlanManagementService.CreateAssignment"),
+			)
+		}
+		return uuid.Nil, err
+	}
+	return latest.ID, nil
+}
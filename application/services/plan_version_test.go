@@ -0,0 +1,164 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/redcardinal-io/metering/application/repositories"
+	domainerrors "github.com/redcardinal-io/metering/domain/errors"
+	"github.com/redcardinal-io/metering/domain/models"
+	"github.com/redcardinal-io/metering/domain/pkg/pagination"
+)
+
+// fakePlanStore serves a single plan and records the clones made of it
+type fakePlanStore struct {
+	repositories.PlanStoreRepository
+	plan   *models.Plan
+	clones []models.ClonePlanInput
+}
+
+func (f *fakePlanStore) ClonePlan(ctx context.Context, sourceID uuid.UUID, arg models.ClonePlanInput) (*models.Plan, error) {
+	f.clones = append(f.clones, arg)
+	return &models.Plan{Base: models.Base{ID: uuid.New()}, Name: arg.Name, Slug: arg.Slug, Type: arg.Type, Description: arg.Description}, nil
+}
+
+func (f *fakePlanStore) GetPlanByIDorSlug(ctx context.Context, idOrSlug string) (*models.Plan, error) {
+	if idOrSlug != f.plan.ID.String() && idOrSlug != f.plan.Slug {
+		return nil, domainerrors.New(nil, domainerrors.ENOTFOUND, "Resource not found")
+	}
+	return f.plan, nil
+}
+
+// fakePlanVersionStore keeps the published versions of a plan in order
+type fakePlanVersionStore struct {
+	repositories.PlanVersionStoreRepository
+	versions []models.PlanVersion
+}
+
+func (f *fakePlanVersionStore) GetPlanVersion(ctx context.Context, planID uuid.UUID, version int) (*models.PlanVersion, error) {
+	for i := range f.versions {
+		if f.versions[i].Version == version {
+			return &f.versions[i], nil
+		}
+	}
+	return nil, domainerrors.New(nil, domainerrors.ENOTFOUND, "Resource not found")
+}
+
+// GetLatestPlanVersion prefers the versions of the plan, falling back to the last one for versions
+// not tied to a plan
+func (f *fakePlanVersionStore) GetLatestPlanVersion(ctx context.Context, planID uuid.UUID) (*models.PlanVersion, error) {
+	for i := len(f.versions) - 1; i >= 0; i-- {
+		if f.versions[i].PlanID == planID {
+			return &f.versions[i], nil
+		}
+	}
+	if len(f.versions) == 0 {
+		return nil, domainerrors.New(nil, domainerrors.ENOTFOUND, "Resource not found")
+	}
+	return &f.versions[len(f.versions)-1], nil
+}
+
+func TestPlanManagementService_CreateAssignmentPinsVersion(t *testing.T) {
+	ctx := context.Background()
+	plan := &models.Plan{Base: models.Base{ID: uuid.New()}, Slug: "pro"}
+	v1 := models.PlanVersion{ID: uuid.New(), PlanID: plan.ID, Version: 1}
+	v2 := models.PlanVersion{ID: uuid.New(), PlanID: plan.ID, Version: 2}
+	versions := &fakePlanVersionStore{versions: []models.PlanVersion{v1, v2}}
+
+	previous := func(assignments ...models.PlanAssignment) *pagination.PaginationView[models.PlanAssignment] {
+		return &pagination.PaginationView[models.PlanAssignment]{Results: assignments, Total: len(assignments)}
+	}
+	tests := []struct {
+		name     string
+		version  int
+		previous *pagination.PaginationView[models.PlanAssignment]
+		expected uuid.UUID
+	}{
+		{"new subscribers get the latest version", 0, previous(), v2.ID},
+		{"renewals keep the previous version", 0, previous(models.PlanAssignment{PlanVersionID: v1.ID.String()}), v1.ID},
+		{"renewals move to a scheduled version", 0, previous(models.PlanAssignment{PlanVersionID: v1.ID.String(), PendingPlanVersionID: v2.ID.String()}), v2.ID},
+		{"requested version", 1, previous(), v1.ID},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assignments := new(MockPlanAssignmentsStoreRepository)
+			assignments.On("CountOverlappingBaseAssignments", mock.Anything, mock.Anything).Return(int64(0), nil)
+			assignments.On("ListAssignments", mock.Anything, mock.Anything, mock.Anything).Return(tt.previous, nil).Maybe()
+			assignments.On("ListActiveSubjectAssignments", mock.Anything, mock.Anything).Return([]models.PlanAssignment{}, nil)
+			assignments.On("CreateAssignment", mock.Anything, mock.MatchedBy(func(arg models.CreateAssignmentInput) bool {
+				return arg.PlanVersionID == tt.expected
+			})).Return(&models.PlanAssignment{PlanVersionID: tt.expected.String()}, nil)
+			svc := NewPlanService(&fakePlanStore{plan: plan}, nil, nil, assignments, nil, nil, versions, nil, nil)
+
+			assignment, err := svc.CreateAssignment(ctx, models.CreateAssignmentInput{PlanID: &plan.ID, PlanVersion: tt.version, OrganizationID: "org-a"})
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected.String(), assignment.PlanVersionID)
+			assignments.AssertExpectations(t)
+		})
+	}
+
+	t.Run("Fails for plans that were never published", func(t *testing.T) {
+		assignments := new(MockPlanAssignmentsStoreRepository)
+		assignments.On("CountOverlappingBaseAssignments", mock.Anything, mock.Anything).Return(int64(0), nil)
+		assignments.On("ListAssignments", mock.Anything, mock.Anything, mock.Anything).Return(previous(), nil)
+		svc := NewPlanService(&fakePlanStore{plan: plan}, nil, nil, assignments, nil, nil, &fakePlanVersionStore{}, nil, nil)
+
+		_, err := svc.CreateAssignment(ctx, models.CreateAssignmentInput{PlanID: &plan.ID, OrganizationID: "org-a"})
+		assert.Equal(t, string(domainerrors.EINVALID), domainerrors.GetErrorCode(err))
+		assignments.AssertNotCalled(t, "CreateAssignment", mock.Anything, mock.Anything)
+	})
+}
+
+func TestPlanManagementService_MigratePlanVersion(t *testing.T) {
+	ctx := context.Background()
+	plan := &models.Plan{Base: models.Base{ID: uuid.New()}, Slug: "extra-calls", Kind: models.PlanKindAddon}
+	v1 := models.PlanVersion{ID: uuid.New(), PlanID: plan.ID, Version: 1}
+	v2 := models.PlanVersion{ID: uuid.New(), PlanID: plan.ID, Version: 2}
+	v3 := models.PlanVersion{ID: uuid.New(), PlanID: plan.ID, Version: 3}
+	versions := &fakePlanVersionStore{versions: []models.PlanVersion{v1, v2, v3}}
+	baseVersion, apiID := uuid.New(), uuid.New()
+	baseAPI, v2API, v3API := uuid.New(), uuid.New(), uuid.New()
+	planFeatures := &fakePlanFeatureStore{versions: map[uuid.UUID][]models.PlanFeature{
+		baseVersion: {{Base: models.Base{ID: baseAPI}, FeatureID: apiID, FeatureSlug: "api"}},
+		v2.ID:       {{Base: models.Base{ID: v2API}, FeatureID: apiID, FeatureSlug: "api"}},
+		v3.ID:       {{Base: models.Base{ID: v3API}, FeatureID: apiID, FeatureSlug: "api"}},
+	}}
+	quotas := &fakeQuotaStore{quotas: map[uuid.UUID]*models.PlanFeatureQuota{
+		baseAPI: {LimitValue: 1000, ResetPeriod: models.MeteredResetPeriodMonth},
+		v2API:   {LimitValue: 500, ResetPeriod: models.MeteredResetPeriodMonth},
+		v3API:   {LimitValue: 50, ResetPeriod: models.MeteredResetPeriodDay},
+	}}
+	addon := models.PlanAssignment{Base: models.Base{ID: uuid.New()}, PlanID: plan.ID.String(), PlanKind: models.PlanKindAddon, PlanVersionID: v1.ID.String(), OrganizationID: "org-a"}
+
+	assignments := new(MockPlanAssignmentsStoreRepository)
+	assignments.On("ListActiveAssignmentsByPlan", mock.Anything, plan.ID, mock.Anything).Return([]models.PlanAssignment{addon}, nil)
+	assignments.On("ListActiveSubjectAssignments", mock.Anything, mock.Anything).Return([]models.PlanAssignment{
+		{Base: models.Base{ID: uuid.New()}, PlanKind: models.PlanKindBase, PlanVersionID: baseVersion.String(), OrganizationID: "org-a"},
+		addon,
+	}, nil)
+	assignments.On("MigrateAssignments", mock.Anything, mock.MatchedBy(func(arg models.MigratePlanVersionInput) bool {
+		return arg.PlanID == plan.ID && arg.ToVersionID == v2.ID && *arg.FromVersionID == v1.ID && arg.OnRenewal
+	})).Return(int64(3), nil)
+	svc := NewPlanService(&fakePlanStore{plan: plan}, nil, planFeatures, assignments, quotas, nil, versions, nil, nil)
+
+	migration, err := svc.MigratePlanVersion(ctx, "extra-calls", models.MigratePlanVersionInput{FromVersion: 1, ToVersion: 2, OnRenewal: true})
+	require.NoError(t, err)
+	assert.Equal(t, &models.PlanVersionMigration{Version: 2, OnRenewal: true, Assignments: 3}, migration)
+	assignments.AssertExpectations(t)
+
+	// The daily quota of version 3 cannot be combined with the monthly quota of the base plan
+	_, err = svc.MigratePlanVersion(ctx, "extra-calls", models.MigratePlanVersionInput{ToVersion: 3, OnRenewal: true})
+	assert.Equal(t, string(domainerrors.EINVALID), domainerrors.GetErrorCode(err))
+	assignments.AssertNumberOfCalls(t, "MigrateAssignments", 1)
+
+	_, err = svc.MigratePlanVersion(ctx, "extra-calls", models.MigratePlanVersionInput{FromVersion: 2, ToVersion: 2})
+	assert.Equal(t, string(domainerrors.EINVALID), domainerrors.GetErrorCode(err))
+
+	_, err = svc.MigratePlanVersion(ctx, "extra-calls", models.MigratePlanVersionInput{ToVersion: 4})
+	assert.Equal(t, string(domainerrors.ENOTFOUND), domainerrors.GetErrorCode(err))
+}
@@ -4,10 +4,13 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"time"
 
 	"github.com/redcardinal-io/metering/application/repositories"
 	domainerrors "github.com/redcardinal-io/metering/domain/errors" // Assuming AppError is defined here or accessible
 	"github.com/redcardinal-io/metering/domain/models"
+	"github.com/redcardinal-io/metering/domain/pkg/config"
+	"github.com/redcardinal-io/metering/domain/pkg/constants"
 )
 
 const (
@@ -15,20 +18,27 @@ const (
 )
 
 type ProducerService struct {
-	producer repositories.ProducerRepository
-	store    repositories.MeterStoreRepository
+	producer    repositories.ProducerRepository
+	store       repositories.MeterStoreRepository
+	cache       repositories.QueryCacheRepository
+	cacheConfig config.QueryCacheConfig
 }
 
-func NewProducerService(producer repositories.ProducerRepository, store repositories.MeterStoreRepository) *ProducerService {
+// NewProducerService creates a new ProducerService. The query cache, when not nil, is held for
+// meters receiving late events that fall into windows which may already be cached.
+func NewProducerService(producer repositories.ProducerRepository, store repositories.MeterStoreRepository, cache repositories.QueryCacheRepository, cacheConfig config.QueryCacheConfig) *ProducerService {
 	return &ProducerService{
-		producer: producer,
-		store:    store,
+		producer:    producer,
+		store:       store,
+		cache:       cache,
+		cacheConfig: cacheConfig,
 	}
 }
 
 type meterConfig struct {
 	exists             map[string]bool
 	requiredProperties map[string][]string
+	meterSlugs         map[string][]string
 }
 
 type validationResult struct {
@@ -84,10 +94,45 @@ func (p *ProducerService) PublishEvents(ctx context.Context, topic string, event
 		)
 	}
 
+	p.holdLateEvents(ctx, valResult.validEvents, config)
+
 	result.SuccessCount = len(valResult.validEvents)
 	return result, nil
 }
 
+// holdLateEvents drops cached query results of meters that received events older than the
+// settle delay, as those events change windows that are considered closed. The events are only
+// published here, so results reaching them are kept out of the cache until the ingest delay has
+// passed; otherwise a query racing the ingestion would cache the stale totals again.
+func (p *ProducerService) holdLateEvents(ctx context.Context, events []*models.Event, cfg *meterConfig) {
+	if p.cache == nil {
+		return
+	}
+
+	tenantSlug, _ := ctx.Value(constants.TenantSlugKey).(string)
+	now := time.Now()
+	settled := now.Add(-p.cacheConfig.SettleDelay)
+	earliest := make(map[string]time.Time)
+	for _, event := range events {
+		timestamp, err := time.Parse(time.RFC3339, event.Timestamp)
+		if err == nil && timestamp.After(settled) {
+			continue
+		}
+		if err != nil {
+			// Unknown timestamps unsettle every window
+			timestamp = time.Time{}
+		}
+		for _, slug := range cfg.meterSlugs[event.Type] {
+			if since, ok := earliest[slug]; !ok || timestamp.Before(since) {
+				earliest[slug] = timestamp
+			}
+		}
+	}
+	for slug, since := range earliest {
+		p.cache.HoldMeter(ctx, tenantSlug, slug, since, now.Add(p.cacheConfig.IngestDelay))
+	}
+}
+
 func validateBatchSize(events *models.EventBatch) error {
 	if len(events.Events) > MaxBatchSize {
 		return domainerrors.New(
@@ -135,6 +180,7 @@ func (p *ProducerService) fetchAndPrepareMeterConfig(ctx context.Context, events
 	config := &meterConfig{
 		exists:             make(map[string]bool),
 		requiredProperties: make(map[string][]string),
+		meterSlugs:         make(map[string][]string),
 	}
 	tempProperties := make(map[string]map[string]struct{})
 
@@ -142,22 +188,24 @@ func (p *ProducerService) fetchAndPrepareMeterConfig(ctx context.Context, events
 		if meter == nil {
 			continue
 		}
-		eventType := meter.EventType
-		config.exists[eventType] = true
+		for _, eventType := range meter.AllEventTypes() {
+			config.exists[eventType] = true
+			config.meterSlugs[eventType] = append(config.meterSlugs[eventType], meter.Slug)
 
-		if _, ok := tempProperties[eventType]; !ok {
-			tempProperties[eventType] = make(map[string]struct{})
-		}
+			if _, ok := tempProperties[eventType]; !ok {
+				tempProperties[eventType] = make(map[string]struct{})
+			}
 
-		if meter.Properties != nil {
-			for _, prop := range meter.Properties {
-				if prop != "" {
-					tempProperties[eventType][prop] = struct{}{}
+			if meter.Properties != nil {
+				for _, prop := range meter.Properties {
+					if prop != "" {
+						tempProperties[eventType][prop] = struct{}{}
+					}
 				}
 			}
-		}
-		if meter.ValueProperty != "" {
-			tempProperties[eventType][meter.ValueProperty] = struct{}{}
+			if valueProperty := meter.ValuePropertyFor(eventType); valueProperty != "" {
+				tempProperties[eventType][valueProperty] = struct{}{}
+			}
 		}
 	}
 
@@ -6,12 +6,15 @@ import (
 	"errors"
 	"fmt"
 	"testing"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 
 	domainerrors "github.com/redcardinal-io/metering/domain/errors"
 	"github.com/redcardinal-io/metering/domain/models"
+	"github.com/redcardinal-io/metering/domain/pkg/config"
 	"github.com/redcardinal-io/metering/domain/pkg/pagination"
 )
 
@@ -55,6 +58,10 @@ func (m *MockMeterStoreRepository) ListMetersByEventTypes(ctx context.Context, e
 	return args.Get(0).([]*models.Meter), args.Error(1)
 }
 
+func (m *MockMeterStoreRepository) ListMeterSlugsReferencing(ctx context.Context, meterSlug string) ([]string, error) {
+	return nil, nil
+}
+
 func (m *MockMeterStoreRepository) DeleteMeterByIDorSlug(ctx context.Context, idOrSlug string) error {
 	return nil
 }
@@ -63,6 +70,18 @@ func (m *MockMeterStoreRepository) UpdateMeterByIDorSlug(ctx context.Context, id
 	return nil, nil
 }
 
+func (m *MockMeterStoreRepository) UpdateMeterStatus(ctx context.Context, id uuid.UUID, arg models.UpdateMeterStatusInput) (*models.Meter, error) {
+	return nil, nil
+}
+
+func (m *MockMeterStoreRepository) ListAllMeters(ctx context.Context) ([]*models.Meter, error) {
+	return nil, nil
+}
+
+func (m *MockMeterStoreRepository) ListMetersByStatus(ctx context.Context, statuses []models.MeterStatus, updatedBefore time.Time, limit int) ([]*models.Meter, error) {
+	return nil, nil
+}
+
 func TestProducerService_PublishEvents(t *testing.T) {
 	const testTopic = "test-topic"
 	ctx := context.Background()
@@ -105,7 +124,7 @@ func TestProducerService_PublishEvents(t *testing.T) {
 	t.Run("successfully publish all valid events", func(t *testing.T) {
 		mockProducer := new(MockProducerRepository)
 		mockStore := new(MockMeterStoreRepository)
-		service := NewProducerService(mockProducer, mockStore)
+		service := NewProducerService(mockProducer, mockStore, nil, config.QueryCacheConfig{})
 
 		events := &models.EventBatch{
 			Events: []*models.Event{
@@ -137,7 +156,7 @@ func TestProducerService_PublishEvents(t *testing.T) {
 	t.Run("batch size exceeds maximum", func(t *testing.T) {
 		mockProducer := new(MockProducerRepository)
 		mockStore := new(MockMeterStoreRepository)
-		service := NewProducerService(mockProducer, mockStore)
+		service := NewProducerService(mockProducer, mockStore, nil, config.QueryCacheConfig{})
 
 		var oversizedEvents []*models.Event
 		for i := range MaxBatchSize + 1 {
@@ -158,7 +177,7 @@ func TestProducerService_PublishEvents(t *testing.T) {
 	t.Run("fetchAndPrepareMeterConfig returns error - no valid event types in non-empty batch", func(t *testing.T) {
 		mockProducer := new(MockProducerRepository)
 		mockStore := new(MockMeterStoreRepository)
-		service := NewProducerService(mockProducer, mockStore)
+		service := NewProducerService(mockProducer, mockStore, nil, config.QueryCacheConfig{})
 
 		events := &models.EventBatch{Events: []*models.Event{newTestEvent("ev1", "", nil)}} // Empty type
 
@@ -176,7 +195,7 @@ func TestProducerService_PublishEvents(t *testing.T) {
 	t.Run("fetchAndPrepareMeterConfig returns error - completely empty batch", func(t *testing.T) {
 		mockProducer := new(MockProducerRepository)
 		mockStore := new(MockMeterStoreRepository)
-		service := NewProducerService(mockProducer, mockStore)
+		service := NewProducerService(mockProducer, mockStore, nil, config.QueryCacheConfig{})
 
 		events := &models.EventBatch{Events: []*models.Event{}} // Empty batch
 
@@ -194,7 +213,7 @@ func TestProducerService_PublishEvents(t *testing.T) {
 	t.Run("fetchAndPrepareMeterConfig returns error - store fails", func(t *testing.T) {
 		mockProducer := new(MockProducerRepository)
 		mockStore := new(MockMeterStoreRepository)
-		service := NewProducerService(mockProducer, mockStore)
+		service := NewProducerService(mockProducer, mockStore, nil, config.QueryCacheConfig{})
 
 		events := &models.EventBatch{Events: []*models.Event{newTestEvent("ev1", "type1", nil)}}
 		storeErr := errors.New("database connection failed")
@@ -215,7 +234,7 @@ func TestProducerService_PublishEvents(t *testing.T) {
 	t.Run("validation fails - nil event, allowPartialSuccess=true", func(t *testing.T) {
 		mockProducer := new(MockProducerRepository)
 		mockStore := new(MockMeterStoreRepository)
-		service := NewProducerService(mockProducer, mockStore)
+		service := NewProducerService(mockProducer, mockStore, nil, config.QueryCacheConfig{})
 
 		events := &models.EventBatch{
 			Events: []*models.Event{
@@ -247,7 +266,7 @@ func TestProducerService_PublishEvents(t *testing.T) {
 	t.Run("validation fails - nil event, allowPartialSuccess=false", func(t *testing.T) {
 		mockProducer := new(MockProducerRepository)
 		mockStore := new(MockMeterStoreRepository)
-		service := NewProducerService(mockProducer, mockStore)
+		service := NewProducerService(mockProducer, mockStore, nil, config.QueryCacheConfig{})
 
 		events := &models.EventBatch{
 			Events: []*models.Event{
@@ -272,7 +291,7 @@ func TestProducerService_PublishEvents(t *testing.T) {
 	t.Run("validation fails - empty event type, allowPartialSuccess=true", func(t *testing.T) {
 		mockProducer := new(MockProducerRepository)
 		mockStore := new(MockMeterStoreRepository)
-		service := NewProducerService(mockProducer, mockStore)
+		service := NewProducerService(mockProducer, mockStore, nil, config.QueryCacheConfig{})
 
 		events := &models.EventBatch{
 			Events: []*models.Event{
@@ -304,7 +323,7 @@ func TestProducerService_PublishEvents(t *testing.T) {
 	t.Run("validation fails - no meter configured for event type, allowPartialSuccess=true", func(t *testing.T) {
 		mockProducer := new(MockProducerRepository)
 		mockStore := new(MockMeterStoreRepository)
-		service := NewProducerService(mockProducer, mockStore)
+		service := NewProducerService(mockProducer, mockStore, nil, config.QueryCacheConfig{})
 
 		events := &models.EventBatch{
 			Events: []*models.Event{
@@ -341,7 +360,7 @@ func TestProducerService_PublishEvents(t *testing.T) {
 	t.Run("validation fails - missing required property, allowPartialSuccess=false", func(t *testing.T) {
 		mockProducer := new(MockProducerRepository)
 		mockStore := new(MockMeterStoreRepository)
-		service := NewProducerService(mockProducer, mockStore)
+		service := NewProducerService(mockProducer, mockStore, nil, config.QueryCacheConfig{})
 
 		events := &models.EventBatch{
 			Events: []*models.Event{
@@ -366,7 +385,7 @@ func TestProducerService_PublishEvents(t *testing.T) {
 	t.Run("validation fails - empty string for required property, allowPartialSuccess=true", func(t *testing.T) {
 		mockProducer := new(MockProducerRepository)
 		mockStore := new(MockMeterStoreRepository)
-		service := NewProducerService(mockProducer, mockStore)
+		service := NewProducerService(mockProducer, mockStore, nil, config.QueryCacheConfig{})
 
 		events := &models.EventBatch{
 			Events: []*models.Event{
@@ -396,10 +415,44 @@ func TestProducerService_PublishEvents(t *testing.T) {
 		mockProducer.AssertExpectations(t)
 	})
 
+	t.Run("validates the value property of each event type of a multi-type meter", func(t *testing.T) {
+		mockProducer := new(MockProducerRepository)
+		mockStore := new(MockMeterStoreRepository)
+		service := NewProducerService(mockProducer, mockStore, nil, config.QueryCacheConfig{})
+
+		computeMeter := &models.Meter{
+			EventType:       "vm.stopped",
+			EventTypes:      []string{"vm.stopped", "job.finished"},
+			ValueProperty:   "seconds",
+			ValueProperties: map[string]string{"job.finished": "duration"},
+			Slug:            "compute-seconds",
+		}
+		events := &models.EventBatch{
+			Events: []*models.Event{
+				newTestEvent("ev1", "vm.stopped", map[string]any{"seconds": 30}),
+				newTestEvent("ev2", "job.finished", map[string]any{"duration": 12}),
+				newTestEvent("ev3", "job.finished", map[string]any{"seconds": 12}),
+			},
+		}
+		mockStore.On("ListMetersByEventTypes", ctx, mock.AnythingOfType("[]string")).Return([]*models.Meter{computeMeter}, nil).Once()
+		mockProducer.On("PublishEvents", testTopic, mock.MatchedBy(func(batch *models.EventBatch) bool {
+			return len(batch.Events) == 2 && batch.Events[0].ID == "ev1" && batch.Events[1].ID == "ev2"
+		})).Return(nil).Once()
+
+		result, err := service.PublishEvents(ctx, testTopic, events, true)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, result.SuccessCount)
+		assert.Len(t, result.FailedEvents, 1)
+		assert.Equal(t, "ev3", result.FailedEvents[0].Event.ID)
+		mockStore.AssertExpectations(t)
+		mockProducer.AssertExpectations(t)
+	})
+
 	t.Run("validation fails - invalid event properties JSON, allowPartialSuccess=true", func(t *testing.T) {
 		mockProducer := new(MockProducerRepository)
 		mockStore := new(MockMeterStoreRepository)
-		service := NewProducerService(mockProducer, mockStore)
+		service := NewProducerService(mockProducer, mockStore, nil, config.QueryCacheConfig{})
 
 		invalidPropsEvent := newTestEvent("ev1", "type1", nil)
 		invalidPropsEvent.Properties = "{not-a-valid-json"
@@ -435,7 +488,7 @@ func TestProducerService_PublishEvents(t *testing.T) {
 	t.Run("all events fail validation, allowPartialSuccess=true", func(t *testing.T) {
 		mockProducer := new(MockProducerRepository)
 		mockStore := new(MockMeterStoreRepository)
-		service := NewProducerService(mockProducer, mockStore)
+		service := NewProducerService(mockProducer, mockStore, nil, config.QueryCacheConfig{})
 
 		events := &models.EventBatch{
 			Events: []*models.Event{
@@ -461,7 +514,7 @@ func TestProducerService_PublishEvents(t *testing.T) {
 	t.Run("all events fail validation (one nil, one missing prop), allowPartialSuccess=true", func(t *testing.T) {
 		mockProducer := new(MockProducerRepository)
 		mockStore := new(MockMeterStoreRepository)
-		service := NewProducerService(mockProducer, mockStore)
+		service := NewProducerService(mockProducer, mockStore, nil, config.QueryCacheConfig{})
 
 		events := &models.EventBatch{
 			Events: []*models.Event{
@@ -487,7 +540,7 @@ func TestProducerService_PublishEvents(t *testing.T) {
 	t.Run("producer PublishEvents fails", func(t *testing.T) {
 		mockProducer := new(MockProducerRepository)
 		mockStore := new(MockMeterStoreRepository)
-		service := NewProducerService(mockProducer, mockStore)
+		service := NewProducerService(mockProducer, mockStore, nil, config.QueryCacheConfig{})
 
 		events := &models.EventBatch{
 			Events: []*models.Event{
@@ -515,7 +568,7 @@ func TestProducerService_PublishEvents(t *testing.T) {
 	t.Run("meter has no specific properties defined, event has some", func(t *testing.T) {
 		mockProducer := new(MockProducerRepository)
 		mockStore := new(MockMeterStoreRepository)
-		service := NewProducerService(mockProducer, mockStore)
+		service := NewProducerService(mockProducer, mockStore, nil, config.QueryCacheConfig{})
 
 		events := &models.EventBatch{
 			Events: []*models.Event{
@@ -543,7 +596,7 @@ func TestProducerService_PublishEvents(t *testing.T) {
 	t.Run("event properties is empty string, but required props exist for meter", func(t *testing.T) {
 		mockProducer := new(MockProducerRepository)
 		mockStore := new(MockMeterStoreRepository)
-		service := NewProducerService(mockProducer, mockStore)
+		service := NewProducerService(mockProducer, mockStore, nil, config.QueryCacheConfig{})
 
 		eventWithEmptyPropsString := newTestEvent("ev-empty-props", "type1", nil)
 		eventWithEmptyPropsString.Properties = ""
@@ -572,7 +625,7 @@ func TestProducerService_PublishEvents(t *testing.T) {
 	t.Run("event properties is literal empty JSON object string, but required props exist", func(t *testing.T) {
 		mockProducer := new(MockProducerRepository)
 		mockStore := new(MockMeterStoreRepository)
-		service := NewProducerService(mockProducer, mockStore)
+		service := NewProducerService(mockProducer, mockStore, nil, config.QueryCacheConfig{})
 
 		eventWithEmptyJSONObject := newTestEvent("ev-empty-json", "type1", nil)
 		eventWithEmptyJSONObject.Properties = "{}"
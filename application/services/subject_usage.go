@@ -0,0 +1,328 @@
+package services
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redcardinal-io/metering/application/repositories"
+	domainerrors "github.com/redcardinal-io/metering/domain/errors"
+	"github.com/redcardinal-io/metering/domain/models"
+)
+
+// SubjectUsageService reports the usage of organizations and users against the quotas of the
+// plans assigned to them.
+type SubjectUsageService struct {
+	assignments  repositories.PlanAssignmentsStoreRepository
+	planFeatures repositories.PlanFeatureStoreRepository
+	quotas       repositories.PlanFeatureQuotaStoreRepository
+	overrides    repositories.EntitlementOverrideStoreRepository
+	meters       *MeterService
+	now          func() time.Time
+}
+
+// NewSubjectUsageService creates a SubjectUsageService querying usage through the given meter service.
+func NewSubjectUsageService(
+	assignments repositories.PlanAssignmentsStoreRepository,
+	planFeatures repositories.PlanFeatureStoreRepository,
+	quotas repositories.PlanFeatureQuotaStoreRepository,
+	overrides repositories.EntitlementOverrideStoreRepository,
+	meters *MeterService,
+) *SubjectUsageService {
+	return &SubjectUsageService{
+		assignments:  assignments,
+		planFeatures: planFeatures,
+		quotas:       quotas,
+		overrides:    overrides,
+		meters:       meters,
+		now:          time.Now,
+	}
+}
+
+// GetSubjectUsage returns the usage of every metered feature the subject is entitled to through
+// the plan versions currently assigned to it, with quotas merged across its base plan and add-ons
+// and adjusted by the overrides of the subject.
+// Each feature sums the meters it is linked to over the current period of its quota.
+func (s *SubjectUsageService) GetSubjectUsage(ctx context.Context, subjectType models.SubjectType, subjectID string) (*models.SubjectUsage, error) {
+	now := s.now().UTC()
+	resolved, err := s.subjectEntitlements(ctx, subjectType, subjectID, now, models.PlanFeatureListFilter{FeatureType: models.FeatureTypeMetered})
+	if err != nil {
+		return nil, err
+	}
+
+	usage := &models.SubjectUsage{
+		SubjectType: subjectType,
+		SubjectID:   subjectID,
+		Features:    make([]models.FeatureUsage, 0, len(resolved.entitlements)),
+	}
+	for _, assignment := range resolved.assignments {
+		if assignment.PlanKind == models.PlanKindAddon {
+			usage.AddOnPlanIDs = append(usage.AddOnPlanIDs, assignment.PlanID)
+		} else {
+			usage.PlanID = assignment.PlanID
+		}
+	}
+	for _, e := range resolved.entitlements {
+		featureUsage := models.FeatureUsage{
+			FeatureID:   e.FeatureID,
+			FeatureSlug: e.FeatureSlug,
+			FeatureName: e.FeatureName,
+			MeterSlugs:  e.MeterSlugs,
+		}
+		if featureUsage.MeterSlugs == nil {
+			featureUsage.MeterSlugs = []string{}
+		}
+		current, err := s.entitlementUsage(ctx, subjectType, subjectID, e, now)
+		if err != nil {
+			return nil, err
+		}
+		featureUsage.Used, featureUsage.PeriodStart, featureUsage.PeriodEnd = current.used, current.periodStart, current.periodEnd
+		if e.Quota != nil {
+			limit, allowed := e.Quota.LimitValue, e.Quota.AllowedUsage()
+			featureUsage.Limit = &limit
+			featureUsage.SoftLimit = e.Quota.SoftLimitValue
+			featureUsage.AllowedUsage = &allowed
+			featureUsage.ResetPeriod = e.Quota.ResetPeriod
+			featureUsage.ActionAtLimit = e.Quota.ActionAtLimit
+			featureUsage.Band = current.band
+			featureUsage.GraceEndsAt = current.graceEndsAt
+		}
+		if featureUsage.Limit != nil && *featureUsage.Limit > 0 {
+			percentage := featureUsage.Used / float64(*featureUsage.Limit) * 100
+			featureUsage.Percentage = &percentage
+		}
+		usage.Features = append(usage.Features, featureUsage)
+	}
+	return usage, nil
+}
+
+// versionFeatures returns the features published in the plan version an assignment is pinned to.
+func (s *SubjectUsageService) versionFeatures(ctx context.Context, planVersionID string, filter models.PlanFeatureListFilter) ([]models.PlanFeature, error) {
+	id, err := uuid.Parse(planVersionID)
+	if err != nil {
+		return nil, domainerrors.New(
+			err,
+			domainerrors.EINTERNAL,
+			"plan assignment is not pinned to a plan version",
+			domainerrors.WithOperation("SubjectUsageService.versionFeatures"),
+		)
+	}
+	return s.planFeatures.ListPlanFeaturesByVersion(ctx, id, filter)
+}
+
+// quotaUsage is the usage of an entitlement over the current period of its quota, with the
+// band it falls in when the entitlement has a quota.
+type quotaUsage struct {
+	used        float64
+	periodStart time.Time
+	periodEnd   *time.Time
+	band        models.QuotaBand
+	graceEndsAt *time.Time
+}
+
+// entitlementUsage sums the usage of the subject across the meters of an entitlement over the
+// current period of its quota. Once usage used up the overage allowance of a quota with a grace
+// period, the grace period runs from when the allowance was first used up in the period, which is
+// recorded the first time it is seen so later reads do not move it.
+func (s *SubjectUsageService) entitlementUsage(ctx context.Context, subjectType models.SubjectType, subjectID string, e entitlement, now time.Time) (*quotaUsage, error) {
+	usage := &quotaUsage{}
+	usage.periodStart, usage.periodEnd = quotaPeriod(e.Quota, e.assignedAt, now)
+
+	var err error
+	usage.used, err = s.subjectMeterUsage(ctx, subjectType, subjectID, e.MeterSlugs, usage.periodStart, now)
+	if err != nil || e.Quota == nil {
+		return usage, err
+	}
+
+	if e.Quota.GracePeriodMinutes != nil && usage.used >= float64(e.Quota.AllowedUsage()) {
+		crossedAt, err := s.graceStart(ctx, subjectType, subjectID, e, usage.periodStart, now)
+		if err != nil {
+			return nil, err
+		}
+		graceEndsAt := crossedAt.Add(time.Duration(*e.Quota.GracePeriodMinutes) * time.Minute)
+		usage.graceEndsAt = &graceEndsAt
+	}
+	usage.band = e.Quota.Band(usage.used, usage.graceEndsAt, now)
+	return usage, nil
+}
+
+// graceStart returns when the subject used up the overage allowance of the entitlement in the
+// period starting at periodStart. The time recorded for an earlier period is replaced by the one
+// found in the usage of the current period.
+func (s *SubjectUsageService) graceStart(ctx context.Context, subjectType models.SubjectType, subjectID string, e entitlement, periodStart, now time.Time) (time.Time, error) {
+	state, err := s.quotas.GetQuotaGraceState(ctx, subjectType, subjectID, e.FeatureID)
+	if err != nil && domainerrors.GetErrorCode(err) != string(domainerrors.ENOTFOUND) {
+		return time.Time{}, err
+	}
+	if state != nil && !state.AllowanceUsedUpAt.Before(periodStart) && !state.AllowanceUsedUpAt.After(now) {
+		return state.AllowanceUsedUpAt, nil
+	}
+
+	usedUpAt, err := s.allowanceUsedUpAt(ctx, subjectType, subjectID, e.MeterSlugs, e.Quota.AllowedUsage(), periodStart, now)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if _, err := s.quotas.SaveQuotaGraceState(ctx, models.QuotaGraceState{
+		SubjectType:       subjectType,
+		SubjectID:         subjectID,
+		FeatureID:         e.FeatureID,
+		AllowanceUsedUpAt: usedUpAt,
+	}); err != nil {
+		return time.Time{}, err
+	}
+	return usedUpAt, nil
+}
+
+// allowanceUsedUpAt returns the end of the first window in which the usage of the subject since
+// from reached allowed, counting hourly windows or daily ones for meters stored at a coarser
+// granularity. Usage that no window accounts for yet counts as used up at now.
+func (s *SubjectUsageService) allowanceUsedUpAt(ctx context.Context, subjectType models.SubjectType, subjectID string, meterSlugs []string, allowed int64, from, to time.Time) (time.Time, error) {
+	var rows []models.QueryMeterRow
+	for _, slug := range meterSlugs {
+		var result *models.QueryMeterResult
+		var err error
+		for _, windowSize := range []models.WindowSize{models.WindowSizeHour, models.WindowSizeDay} {
+			result, err = s.meters.QueryMeter(ctx, models.QueryMeterParams{
+				MeterSlug:     slug,
+				FilterGroupBy: map[string][]string{string(subjectType): {subjectID}},
+				From:          &from,
+				To:            &to,
+				WindowSize:    &windowSize,
+			})
+			if domainerrors.GetErrorCode(err) != string(domainerrors.EINVALID) {
+				break
+			}
+		}
+		if err != nil {
+			return time.Time{}, err
+		}
+		rows = append(rows, result.Data...)
+	}
+
+	slices.SortStableFunc(rows, func(a, b models.QueryMeterRow) int { return a.WindowEnd.Compare(b.WindowEnd) })
+	var used float64
+	for _, row := range rows {
+		used += row.Value
+		if used >= float64(allowed) {
+			if row.WindowEnd.After(to) {
+				return to, nil
+			}
+			return row.WindowEnd, nil
+		}
+	}
+	return to, nil
+}
+
+// subjectMeterUsage sums the usage of the subject across the meters between from and to. Both
+// ends are widened to the buckets of each meter containing them, so the bucket from falls in is
+// counted in full and the bucket that is still open at to is included.
+func (s *SubjectUsageService) subjectMeterUsage(ctx context.Context, subjectType models.SubjectType, subjectID string, meterSlugs []string, from, to time.Time) (float64, error) {
+	var used float64
+	for _, slug := range meterSlugs {
+		start, end, err := s.bucketRange(ctx, slug, from, to)
+		if err != nil {
+			return 0, err
+		}
+		result, err := s.meters.QueryMeter(ctx, models.QueryMeterParams{
+			MeterSlug:     slug,
+			FilterGroupBy: map[string][]string{string(subjectType): {subjectID}},
+			From:          &start,
+			To:            &end,
+		})
+		if err != nil {
+			return 0, err
+		}
+		for _, row := range result.Data {
+			used += row.Value
+		}
+	}
+	return used, nil
+}
+
+// bucketRange widens from and to to the buckets of the meter containing them. Queries only count
+// buckets that start from their From and end by their To, so usage between from and to is queried
+// from the start of the bucket of from up to the end of the bucket of to.
+func (s *SubjectUsageService) bucketRange(ctx context.Context, meterSlug string, from, to time.Time) (time.Time, time.Time, error) {
+	bucket, err := s.meterBucket(ctx, meterSlug)
+	if err != nil || bucket <= 0 {
+		return from, to, err
+	}
+	end := to.Truncate(bucket)
+	if !end.Equal(to) {
+		end = end.Add(bucket)
+	}
+	return from.Truncate(bucket), end, nil
+}
+
+// meterBucket returns the duration of the buckets a meter stores its usage in. Derived meters
+// follow their coarsest source meter.
+func (s *SubjectUsageService) meterBucket(ctx context.Context, meterSlug string) (time.Duration, error) {
+	m, err := s.meters.GetMeterIDorSlug(ctx, meterSlug)
+	if err != nil {
+		return 0, err
+	}
+	bucket := m.Granularity.Duration()
+	for _, source := range m.SourceMeters {
+		sourceBucket, err := s.meterBucket(ctx, source)
+		if err != nil {
+			return 0, err
+		}
+		bucket = max(bucket, sourceBucket)
+	}
+	return bucket, nil
+}
+
+// quotaPeriod returns the period usage counts against a quota at now, starting no earlier than
+// the assignment. Calendar periods follow UTC with weeks starting on Monday; custom periods repeat
+// every CustomPeriodMinutes from the start of the assignment; rolling periods cover the last
+// CustomPeriodMinutes, or the last month when unset. Usage without a quota or that never resets
+// counts from the start of the assignment and has no end.
+func quotaPeriod(quota *models.PlanFeatureQuota, assignedAt, now time.Time) (time.Time, *time.Time) {
+	assignedAt = assignedAt.UTC()
+	if quota == nil {
+		return assignedAt, nil
+	}
+
+	var start, end time.Time
+	year, month, day := now.Date()
+	switch quota.ResetPeriod {
+	case models.MeteredResetPeriodDay:
+		start = time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+		end = start.AddDate(0, 0, 1)
+	case models.MeteredResetPeriodWeek:
+		// time.Weekday counts from Sunday
+		offset := (int(now.Weekday()) + 6) % 7
+		start = time.Date(year, month, day-offset, 0, 0, 0, 0, time.UTC)
+		end = start.AddDate(0, 0, 7)
+	case models.MeteredResetPeriodMonth:
+		start = time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+		end = start.AddDate(0, 1, 0)
+	case models.MeteredResetPeriodYear:
+		start = time.Date(year, 1, 1, 0, 0, 0, 0, time.UTC)
+		end = start.AddDate(1, 0, 0)
+	case models.MeteredResetPeriodCustom:
+		if quota.CustomPeriodMinutes == nil || *quota.CustomPeriodMinutes <= 0 || now.Before(assignedAt) {
+			return assignedAt, nil
+		}
+		period := time.Duration(*quota.CustomPeriodMinutes) * time.Minute
+		start = assignedAt.Add(now.Sub(assignedAt) / period * period)
+		end = start.Add(period)
+	case models.MeteredResetPeriodRolling:
+		start = now.AddDate(0, -1, 0)
+		if quota.CustomPeriodMinutes != nil && *quota.CustomPeriodMinutes > 0 {
+			start = now.Add(-time.Duration(*quota.CustomPeriodMinutes) * time.Minute)
+		}
+		if start.Before(assignedAt) {
+			start = assignedAt
+		}
+		return start, nil
+	default:
+		return assignedAt, nil
+	}
+
+	if start.Before(assignedAt) {
+		start = assignedAt
+	}
+	return start, &end
+}
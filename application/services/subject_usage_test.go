@@ -0,0 +1,328 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/redcardinal-io/metering/application/repositories"
+	domainerrors "github.com/redcardinal-io/metering/domain/errors"
+	"github.com/redcardinal-io/metering/domain/models"
+	"github.com/redcardinal-io/metering/domain/pkg/config"
+)
+
+// fakePlanFeatureStore lists canned features of every plan
+type fakePlanFeatureStore struct {
+	repositories.PlanFeatureStoreRepository
+	features []models.PlanFeature
+	versions map[uuid.UUID][]models.PlanFeature
+}
+
+func (f *fakePlanFeatureStore) ListPlanFeaturesByPlan(ctx context.Context, planID uuid.UUID, filter models.PlanFeatureListFilter) ([]models.PlanFeature, error) {
+	return f.features, nil
+}
+
+func (f *fakePlanFeatureStore) ListPlanFeaturesByVersion(ctx context.Context, planVersionID uuid.UUID, filter models.PlanFeatureListFilter) ([]models.PlanFeature, error) {
+	return f.versions[planVersionID], nil
+}
+
+// fakeQuotaStore serves quotas by plan feature ID
+type fakeQuotaStore struct {
+	repositories.PlanFeatureQuotaStoreRepository
+	quotas map[uuid.UUID]*models.PlanFeatureQuota
+	grace  map[string]models.QuotaGraceState
+}
+
+func (f *fakeQuotaStore) GetPlanFeatureQuota(ctx context.Context, planFeatureID uuid.UUID) (*models.PlanFeatureQuota, error) {
+	quota, ok := f.quotas[planFeatureID]
+	if !ok {
+		return nil, domainerrors.New(nil, domainerrors.ENOTFOUND, "Resource not found")
+	}
+	copied := *quota
+	return &copied, nil
+}
+
+func (f *fakeQuotaStore) GetQuotaGraceState(ctx context.Context, subjectType models.SubjectType, subjectID string, featureID uuid.UUID) (*models.QuotaGraceState, error) {
+	state, ok := f.grace[string(subjectType)+"/"+subjectID+"/"+featureID.String()]
+	if !ok {
+		return nil, domainerrors.New(nil, domainerrors.ENOTFOUND, "Resource not found")
+	}
+	return &state, nil
+}
+
+func (f *fakeQuotaStore) SaveQuotaGraceState(ctx context.Context, state models.QuotaGraceState) (*models.QuotaGraceState, error) {
+	if f.grace == nil {
+		f.grace = map[string]models.QuotaGraceState{}
+	}
+	f.grace[string(state.SubjectType)+"/"+state.SubjectID+"/"+state.FeatureID.String()] = state
+	return &state, nil
+}
+
+// rangeOlap serves canned rows like the meter views do: only buckets starting from From and
+// ending by To are counted
+type rangeOlap struct {
+	fakeOlap
+	rows map[string][]models.QueryMeterRow
+}
+
+func (f *rangeOlap) QueryMeter(ctx context.Context, arg models.QueryMeterParams, meter *models.Meter) (*models.QueryMeterResult, error) {
+	result := &models.QueryMeterResult{}
+	for _, row := range f.rows[arg.MeterSlug] {
+		if (arg.From == nil || !row.WindowStart.Before(*arg.From)) && (arg.To == nil || !row.WindowEnd.After(*arg.To)) {
+			result.Data = append(result.Data, row)
+		}
+	}
+	return result, nil
+}
+
+func TestSubjectUsageService_GetSubjectUsage(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 6, 18, 12, 0, 0, 0, time.UTC)
+	planID, versionID := uuid.New(), uuid.New()
+	apiFeature, storageFeature := uuid.New(), uuid.New()
+	apiID, storageID := uuid.New(), uuid.New()
+
+	olap := &fakeOlap{results: map[string]*models.QueryMeterResult{
+		"api_calls":   {Data: []models.QueryMeterRow{{Value: 600}}},
+		"batch_calls": {Data: []models.QueryMeterRow{{Value: 150}}},
+		"storage":     {Data: []models.QueryMeterRow{{Value: 3}}},
+	}}
+	store := &fakeMeterStore{meters: map[string]*models.Meter{
+		"api_calls":   {Slug: "api_calls", Type: models.MeterTypeStandard, Status: models.MeterStatusActive, Aggregation: models.AggregationCount},
+		"batch_calls": {Slug: "batch_calls", Type: models.MeterTypeStandard, Status: models.MeterStatusActive, Aggregation: models.AggregationCount},
+		"storage":     {Slug: "storage", Type: models.MeterTypeStandard, Status: models.MeterStatusActive, Aggregation: models.AggregationMax},
+	}}
+	planFeatures := &fakePlanFeatureStore{
+		// The draft has moved on since the version the subject is pinned to was published
+		features: []models.PlanFeature{{Base: models.Base{ID: uuid.New()}, FeatureSlug: "api", MeterSlugs: []string{"api_calls"}}},
+		versions: map[uuid.UUID][]models.PlanFeature{versionID: {
+			{Base: models.Base{ID: apiFeature}, FeatureID: apiID, FeatureSlug: "api", MeterSlugs: []string{"api_calls", "batch_calls"}},
+			{Base: models.Base{ID: storageFeature}, FeatureID: storageID, FeatureSlug: "storage", MeterSlugs: []string{"storage"}},
+		}},
+	}
+	quotas := &fakeQuotaStore{quotas: map[uuid.UUID]*models.PlanFeatureQuota{
+		apiFeature: {LimitValue: 1000, ResetPeriod: models.MeteredResetPeriodMonth, ActionAtLimit: models.MeteredActionAtLimitBlock},
+	}}
+
+	t.Run("Sums linked meters over the quota period", func(t *testing.T) {
+		assignments := new(MockPlanAssignmentsStoreRepository)
+		assignments.On("ListActiveSubjectAssignments", mock.Anything, models.GetActiveAssignmentInput{OrganizationID: "org-a", At: now}).
+			Return([]models.PlanAssignment{{PlanID: planID.String(), PlanKind: models.PlanKindBase, PlanVersionID: versionID.String(), ValidFrom: now.AddDate(0, -3, 0)}}, nil)
+		svc := NewSubjectUsageService(assignments, planFeatures, quotas, &fakeEntitlementOverrideStore{}, NewMeterService(olap, store, nil, config.QueryCacheConfig{}))
+		svc.now = func() time.Time { return now }
+
+		usage, err := svc.GetSubjectUsage(ctx, models.SubjectTypeOrganization, "org-a")
+		require.NoError(t, err)
+		assert.Equal(t, planID.String(), usage.PlanID)
+		require.Len(t, usage.Features, 2)
+
+		api := usage.Features[0]
+		assert.Equal(t, float64(750), api.Used)
+		assert.Equal(t, int64(1000), *api.Limit)
+		assert.Equal(t, float64(75), *api.Percentage)
+		assert.Equal(t, time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC), api.PeriodStart)
+		assert.Equal(t, time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC), *api.PeriodEnd)
+
+		unlimited := usage.Features[1]
+		assert.Equal(t, float64(3), unlimited.Used)
+		assert.Nil(t, unlimited.Limit)
+		assert.Nil(t, unlimited.Percentage)
+		assignments.AssertExpectations(t)
+	})
+
+	t.Run("Reports the band of each quota", func(t *testing.T) {
+		hour := func(h int) time.Time { return time.Date(2025, 6, 18, h, 0, 0, 0, time.UTC) }
+		olap := &fakeOlap{results: map[string]*models.QueryMeterResult{
+			"api_calls": {Data: []models.QueryMeterRow{
+				{WindowStart: hour(8), WindowEnd: hour(9), Value: 700},
+				{WindowStart: hour(10), WindowEnd: hour(11), Value: 50},
+			}},
+			"batch_calls": {Data: []models.QueryMeterRow{{WindowStart: hour(9), WindowEnd: hour(10), Value: 400}}},
+			"storage":     {Data: []models.QueryMeterRow{{WindowStart: hour(7), WindowEnd: hour(8), Value: 6}}},
+		}}
+		quotas := &fakeQuotaStore{quotas: map[uuid.UUID]*models.PlanFeatureQuota{
+			apiFeature: {
+				LimitValue: 1000, ResetPeriod: models.MeteredResetPeriodMonth, ActionAtLimit: models.MeteredActionAtLimitBlock,
+				SoftLimitValue: ptr(int64(800)), OverageAllowance: ptr(int64(10)), OverageAllowanceType: models.QuotaOveragePercent,
+				GracePeriodMinutes: ptr(int64(180)),
+			},
+			storageFeature: {LimitValue: 10, ResetPeriod: models.MeteredResetPeriodNever, SoftLimitValue: ptr(int64(5))},
+		}}
+		assignments := new(MockPlanAssignmentsStoreRepository)
+		assignments.On("ListActiveSubjectAssignments", mock.Anything, mock.Anything).
+			Return([]models.PlanAssignment{{PlanID: planID.String(), PlanKind: models.PlanKindBase, PlanVersionID: versionID.String(), ValidFrom: now.AddDate(0, -3, 0)}}, nil)
+		svc := NewSubjectUsageService(assignments, planFeatures, quotas, &fakeEntitlementOverrideStore{}, NewMeterService(olap, store, nil, config.QueryCacheConfig{}))
+		svc.now = func() time.Time { return now }
+
+		usage, err := svc.GetSubjectUsage(ctx, models.SubjectTypeOrganization, "org-a")
+		require.NoError(t, err)
+		require.Len(t, usage.Features, 2)
+
+		api := usage.Features[0]
+		assert.Equal(t, float64(1150), api.Used)
+		assert.Equal(t, int64(1100), *api.AllowedUsage)
+		assert.Equal(t, int64(800), *api.SoftLimit)
+		// The allowance was used up by the end of the 9:00 window of batch_calls
+		assert.Equal(t, models.QuotaBandGrace, api.Band)
+		assert.Equal(t, hour(13), *api.GraceEndsAt)
+
+		storage := usage.Features[1]
+		assert.Equal(t, models.QuotaBandSoftLimit, storage.Band)
+		assert.Nil(t, storage.GraceEndsAt)
+
+		svc.now = func() time.Time { return hour(14) }
+		usage, err = svc.GetSubjectUsage(ctx, models.SubjectTypeOrganization, "org-a")
+		require.NoError(t, err)
+		assert.Equal(t, models.QuotaBandExceeded, usage.Features[0].Band, "the grace period ran out")
+	})
+
+	t.Run("Keeps the grace period of an allowance used up in the current hour", func(t *testing.T) {
+		hour := func(h, m int) time.Time { return time.Date(2025, 6, 18, h, m, 0, 0, time.UTC) }
+		olap := &rangeOlap{rows: map[string][]models.QueryMeterRow{
+			"api_calls": {
+				{WindowStart: hour(8, 0), WindowEnd: hour(9, 0), Value: 1000},
+				{WindowStart: hour(12, 0), WindowEnd: hour(13, 0), Value: 200},
+			},
+		}}
+		store := &fakeMeterStore{meters: map[string]*models.Meter{
+			"api_calls":   {Slug: "api_calls", Type: models.MeterTypeStandard, Status: models.MeterStatusActive, Aggregation: models.AggregationCount, Granularity: models.GranularityHour},
+			"batch_calls": {Slug: "batch_calls", Type: models.MeterTypeStandard, Status: models.MeterStatusActive, Aggregation: models.AggregationCount, Granularity: models.GranularityHour},
+			"storage":     {Slug: "storage", Type: models.MeterTypeStandard, Status: models.MeterStatusActive, Aggregation: models.AggregationMax, Granularity: models.GranularityHour},
+		}}
+		quotas := &fakeQuotaStore{quotas: map[uuid.UUID]*models.PlanFeatureQuota{
+			apiFeature: {
+				LimitValue: 1000, ResetPeriod: models.MeteredResetPeriodMonth, ActionAtLimit: models.MeteredActionAtLimitBlock,
+				OverageAllowance: ptr(int64(10)), OverageAllowanceType: models.QuotaOveragePercent, GracePeriodMinutes: ptr(int64(60)),
+			},
+		}}
+		assignments := new(MockPlanAssignmentsStoreRepository)
+		assignments.On("ListActiveSubjectAssignments", mock.Anything, mock.Anything).
+			Return([]models.PlanAssignment{{PlanID: planID.String(), PlanKind: models.PlanKindBase, PlanVersionID: versionID.String(), ValidFrom: now.AddDate(0, -3, 0)}}, nil)
+		svc := NewSubjectUsageService(assignments, planFeatures, quotas, &fakeEntitlementOverrideStore{}, NewMeterService(olap, store, nil, config.QueryCacheConfig{}))
+		svc.now = func() time.Time { return hour(12, 10) }
+
+		usage, err := svc.GetSubjectUsage(ctx, models.SubjectTypeOrganization, "org-a")
+		require.NoError(t, err)
+		assert.Equal(t, float64(1200), usage.Features[0].Used)
+		// No closed window accounts for the usage yet, so the allowance counts as used up now
+		assert.Equal(t, models.QuotaBandGrace, usage.Features[0].Band)
+		assert.Equal(t, hour(13, 10), *usage.Features[0].GraceEndsAt)
+
+		svc.now = func() time.Time { return hour(12, 40) }
+		usage, err = svc.GetSubjectUsage(ctx, models.SubjectTypeOrganization, "org-a")
+		require.NoError(t, err)
+		assert.Equal(t, hour(13, 10), *usage.Features[0].GraceEndsAt, "the grace period does not move while the hour is open")
+
+		svc.now = func() time.Time { return hour(13, 20) }
+		usage, err = svc.GetSubjectUsage(ctx, models.SubjectTypeOrganization, "org-a")
+		require.NoError(t, err)
+		assert.Equal(t, models.QuotaBandExceeded, usage.Features[0].Band, "the grace period ran out")
+
+		svc.now = func() time.Time { return time.Date(2025, 7, 1, 0, 30, 0, 0, time.UTC) }
+		olap.rows["api_calls"] = []models.QueryMeterRow{
+			{WindowStart: time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC), WindowEnd: time.Date(2025, 7, 1, 1, 0, 0, 0, time.UTC), Value: 1100},
+		}
+		usage, err = svc.GetSubjectUsage(ctx, models.SubjectTypeOrganization, "org-a")
+		require.NoError(t, err)
+		assert.Equal(t, time.Date(2025, 7, 1, 1, 30, 0, 0, time.UTC), *usage.Features[0].GraceEndsAt, "a new period starts a new grace period")
+	})
+
+	t.Run("Counts the bucket that is still open", func(t *testing.T) {
+		today := time.Date(2025, 6, 18, 0, 0, 0, 0, time.UTC)
+		olap := &rangeOlap{rows: map[string][]models.QueryMeterRow{
+			"api_calls": {
+				{WindowStart: today.AddDate(0, 0, -1), WindowEnd: today, Value: 300},
+				{WindowStart: today, WindowEnd: today.AddDate(0, 0, 1), Value: 200},
+			},
+			"batch_calls": {{WindowStart: now.Add(-time.Minute), WindowEnd: now, Value: 5}, {WindowStart: now, WindowEnd: now.Add(time.Minute), Value: 1}},
+		}}
+		store := &fakeMeterStore{meters: map[string]*models.Meter{
+			"api_calls":   {Slug: "api_calls", Type: models.MeterTypeStandard, Status: models.MeterStatusActive, Aggregation: models.AggregationCount, Granularity: models.GranularityDay},
+			"batch_calls": {Slug: "batch_calls", Type: models.MeterTypeStandard, Status: models.MeterStatusActive, Aggregation: models.AggregationCount, Granularity: models.GranularityMinute},
+			"storage":     {Slug: "storage", Type: models.MeterTypeStandard, Status: models.MeterStatusActive, Aggregation: models.AggregationMax, Granularity: models.GranularityDay},
+		}}
+		assignments := new(MockPlanAssignmentsStoreRepository)
+		assignments.On("ListActiveSubjectAssignments", mock.Anything, mock.Anything).
+			Return([]models.PlanAssignment{{PlanID: planID.String(), PlanKind: models.PlanKindBase, PlanVersionID: versionID.String(), ValidFrom: now.AddDate(0, -3, 0)}}, nil)
+		svc := NewSubjectUsageService(assignments, planFeatures, quotas, &fakeEntitlementOverrideStore{}, NewMeterService(olap, store, nil, config.QueryCacheConfig{}))
+		svc.now = func() time.Time { return now.Add(30 * time.Second) }
+
+		usage, err := svc.GetSubjectUsage(ctx, models.SubjectTypeOrganization, "org-a")
+		require.NoError(t, err)
+		// Today's usage of the day meter and the current minute of the minute meter are included
+		assert.Equal(t, float64(506), usage.Features[0].Used)
+	})
+
+	t.Run("Counts the bucket the assignment starts in", func(t *testing.T) {
+		today := time.Date(2025, 6, 18, 0, 0, 0, 0, time.UTC)
+		olap := &rangeOlap{rows: map[string][]models.QueryMeterRow{
+			"api_calls": {{WindowStart: today, WindowEnd: today.AddDate(0, 0, 1), Value: 40}},
+			"batch_calls": {
+				{WindowStart: today.Add(9 * time.Hour), WindowEnd: today.Add(10 * time.Hour), Value: 7},
+				{WindowStart: today.Add(11 * time.Hour), WindowEnd: today.Add(12 * time.Hour), Value: 3},
+			},
+		}}
+		store := &fakeMeterStore{meters: map[string]*models.Meter{
+			"api_calls":   {Slug: "api_calls", Type: models.MeterTypeStandard, Status: models.MeterStatusActive, Aggregation: models.AggregationCount, Granularity: models.GranularityDay},
+			"batch_calls": {Slug: "batch_calls", Type: models.MeterTypeStandard, Status: models.MeterStatusActive, Aggregation: models.AggregationCount, Granularity: models.GranularityHour},
+			"storage":     {Slug: "storage", Type: models.MeterTypeStandard, Status: models.MeterStatusActive, Aggregation: models.AggregationMax, Granularity: models.GranularityDay},
+		}}
+		assignments := new(MockPlanAssignmentsStoreRepository)
+		assignments.On("ListActiveSubjectAssignments", mock.Anything, mock.Anything).
+			Return([]models.PlanAssignment{{PlanID: planID.String(), PlanKind: models.PlanKindBase, PlanVersionID: versionID.String(), ValidFrom: today.Add(9*time.Hour + 30*time.Minute)}}, nil)
+		// Without a quota usage counts from the start of the assignment
+		svc := NewSubjectUsageService(assignments, planFeatures, &fakeQuotaStore{}, &fakeEntitlementOverrideStore{}, NewMeterService(olap, store, nil, config.QueryCacheConfig{}))
+		svc.now = func() time.Time { return now }
+
+		usage, err := svc.GetSubjectUsage(ctx, models.SubjectTypeOrganization, "org-a")
+		require.NoError(t, err)
+		// The buckets starting before 9:30 are counted in full
+		assert.Equal(t, float64(50), usage.Features[0].Used)
+	})
+
+	t.Run("Fails without an active plan", func(t *testing.T) {
+		assignments := new(MockPlanAssignmentsStoreRepository)
+		assignments.On("ListActiveSubjectAssignments", mock.Anything, mock.Anything).
+			Return([]models.PlanAssignment{}, nil)
+		svc := NewSubjectUsageService(assignments, planFeatures, quotas, &fakeEntitlementOverrideStore{}, NewMeterService(olap, store, nil, config.QueryCacheConfig{}))
+
+		_, err := svc.GetSubjectUsage(ctx, models.SubjectTypeUser, "user-a")
+		assert.Equal(t, string(domainerrors.ENOTFOUND), domainerrors.GetErrorCode(err))
+
+		_, err = svc.GetSubjectUsage(ctx, "team", "team-a")
+		assert.Equal(t, string(domainerrors.EINVALID), domainerrors.GetErrorCode(err))
+	})
+}
+
+func TestQuotaPeriod(t *testing.T) {
+	// Wednesday
+	now := time.Date(2025, 6, 18, 12, 30, 0, 0, time.UTC)
+	assignedAt := time.Date(2025, 1, 10, 8, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name  string
+		quota *models.PlanFeatureQuota
+		start time.Time
+		end   *time.Time
+	}{
+		{"no quota", nil, assignedAt, nil},
+		{"day", &models.PlanFeatureQuota{ResetPeriod: models.MeteredResetPeriodDay}, time.Date(2025, 6, 18, 0, 0, 0, 0, time.UTC), ptr(time.Date(2025, 6, 19, 0, 0, 0, 0, time.UTC))},
+		{"week", &models.PlanFeatureQuota{ResetPeriod: models.MeteredResetPeriodWeek}, time.Date(2025, 6, 16, 0, 0, 0, 0, time.UTC), ptr(time.Date(2025, 6, 23, 0, 0, 0, 0, time.UTC))},
+		{"year", &models.PlanFeatureQuota{ResetPeriod: models.MeteredResetPeriodYear}, assignedAt, ptr(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))},
+		{"custom", &models.PlanFeatureQuota{ResetPeriod: models.MeteredResetPeriodCustom, CustomPeriodMinutes: ptr(int64(60))}, time.Date(2025, 6, 18, 12, 0, 0, 0, time.UTC), ptr(time.Date(2025, 6, 18, 13, 0, 0, 0, time.UTC))},
+		{"rolling", &models.PlanFeatureQuota{ResetPeriod: models.MeteredResetPeriodRolling, CustomPeriodMinutes: ptr(int64(90))}, time.Date(2025, 6, 18, 11, 0, 0, 0, time.UTC), nil},
+		{"never", &models.PlanFeatureQuota{ResetPeriod: models.MeteredResetPeriodNever}, assignedAt, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, end := quotaPeriod(tt.quota, assignedAt, now)
+			assert.Equal(t, tt.start, start)
+			assert.Equal(t, tt.end, end)
+		})
+	}
+}
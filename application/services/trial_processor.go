@@ -0,0 +1,77 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/redcardinal-io/metering/application/repositories"
+	"github.com/redcardinal-io/metering/domain/models"
+	"github.com/redcardinal-io/metering/domain/pkg/config"
+	"github.com/redcardinal-io/metering/domain/pkg/constants"
+	"github.com/redcardinal-io/metering/domain/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// TrialProcessor periodically ends the trials of every tenant that ran out, converting the
+// subjects to their target plans, moving them onto their fallback plans or letting them expire.
+type TrialProcessor struct {
+	plans  *PlanManagementService
+	store  repositories.TrialStoreRepository
+	config config.TrialProcessorConfig
+	logger *logger.Logger
+}
+
+// NewTrialProcessor creates a TrialProcessor ending trials through the given plan service.
+func NewTrialProcessor(plans *PlanManagementService, store repositories.TrialStoreRepository, cfg config.TrialProcessorConfig, logger *logger.Logger) *TrialProcessor {
+	return &TrialProcessor{
+		plans:  plans,
+		store:  store,
+		config: cfg,
+		logger: logger,
+	}
+}
+
+// Run ends the trials that ran out every configured interval until the context is cancelled.
+func (p *TrialProcessor) Run(ctx context.Context) {
+	if p.config.Interval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(p.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := p.Process(ctx); err != nil {
+				p.logger.Error("failed to process trials", zap.Error(err))
+			}
+		}
+	}
+}
+
+// Process makes a single pass over the active trials that ran out and returns the trials it ended.
+// Trials that fail are logged and do not stop the pass.
+func (p *TrialProcessor) Process(ctx context.Context) ([]models.Trial, error) {
+	now := p.plans.now().UTC()
+	due, err := p.store.ListDueTrials(ctx, now, p.config.BatchSize)
+	if err != nil {
+		return nil, err
+	}
+
+	var ended []models.Trial
+	for _, trial := range due {
+		tenantCtx := context.WithValue(ctx, constants.TenantSlugKey, trial.TenantSlug)
+		result, err := p.plans.endTrial(tenantCtx, trial, now)
+		if err != nil {
+			p.logger.Error("failed to end trial",
+				zap.String("tenant", trial.TenantSlug),
+				zap.String("trial", trial.ID.String()),
+				zap.Error(err))
+			continue
+		}
+		ended = append(ended, *result)
+	}
+	return ended, nil
+}
@@ -0,0 +1,221 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redcardinal-io/metering/application/repositories"
+	domainerrors "github.com/redcardinal-io/metering/domain/errors"
+	"github.com/redcardinal-io/metering/domain/models"
+	"github.com/redcardinal-io/metering/domain/pkg/logger"
+	"github.com/redcardinal-io/metering/domain/pkg/pagination"
+	"go.uber.org/zap"
+)
+
+// WebhookService manages the webhook endpoints of tenants and queues notifications for the
+// endpoints subscribed to them. Queued deliveries are sent by the WebhookDispatcher.
+type WebhookService struct {
+	store  repositories.WebhookStoreRepository
+	logger *logger.Logger
+	now    func() time.Time
+}
+
+// NewWebhookService creates a WebhookService. The logger reports notifications that could not be
+// queued, since the changes they describe have already been made.
+func NewWebhookService(store repositories.WebhookStoreRepository, logger *logger.Logger) *WebhookService {
+	return &WebhookService{
+		store:  store,
+		logger: logger,
+		now:    time.Now,
+	}
+}
+
+// CreateWebhookEndpoint validates and stores an endpoint, generating its signing secret when none is given.
+func (s *WebhookService) CreateWebhookEndpoint(ctx context.Context, arg models.CreateWebhookEndpointInput) (*models.WebhookEndpoint, error) {
+	const op = "WebhookService.CreateWebhookEndpoint"
+	if err := validateWebhookURL(arg.URL, op); err != nil {
+		return nil, err
+	}
+	eventTypes, err := normalizeNotificationTypes(arg.EventTypes, op)
+	if err != nil {
+		return nil, err
+	}
+	arg.EventTypes = eventTypes
+	if arg.Secret == "" {
+		if arg.Secret, err = newWebhookSecret(); err != nil {
+			return nil, domainerrors.New(err, domainerrors.EINTERNAL, "failed to generate webhook secret", domainerrors.WithOperation(op))
+		}
+	}
+	return s.store.CreateWebhookEndpoint(ctx, arg)
+}
+
+func (s *WebhookService) GetWebhookEndpoint(ctx context.Context, id uuid.UUID) (*models.WebhookEndpoint, error) {
+	return s.store.GetWebhookEndpoint(ctx, id)
+}
+
+func (s *WebhookService) ListWebhookEndpoints(ctx context.Context, pagination pagination.Pagination) (*pagination.PaginationView[models.WebhookEndpoint], error) {
+	return s.store.ListWebhookEndpoints(ctx, pagination)
+}
+
+// UpdateWebhookEndpoint updates the URL, subscriptions, secret or enabled state of an endpoint.
+func (s *WebhookService) UpdateWebhookEndpoint(ctx context.Context, id uuid.UUID, arg models.UpdateWebhookEndpointInput) (*models.WebhookEndpoint, error) {
+	const op = "WebhookService.UpdateWebhookEndpoint"
+	if arg.URL != nil {
+		if err := validateWebhookURL(*arg.URL, op); err != nil {
+			return nil, err
+		}
+	}
+	if arg.EventTypes != nil {
+		eventTypes, err := normalizeNotificationTypes(arg.EventTypes, op)
+		if err != nil {
+			return nil, err
+		}
+		arg.EventTypes = eventTypes
+	}
+	return s.store.UpdateWebhookEndpoint(ctx, id, arg)
+}
+
+func (s *WebhookService) DeleteWebhookEndpoint(ctx context.Context, id uuid.UUID) error {
+	return s.store.DeleteWebhookEndpoint(ctx, id)
+}
+
+// ListWebhookDeliveries returns the deliveries queued for an endpoint, most recent first.
+func (s *WebhookService) ListWebhookDeliveries(ctx context.Context, endpointID uuid.UUID, pagination pagination.Pagination) (*pagination.PaginationView[models.WebhookDelivery], error) {
+	if _, err := s.store.GetWebhookEndpoint(ctx, endpointID); err != nil {
+		return nil, err
+	}
+	return s.store.ListWebhookDeliveries(ctx, endpointID, pagination)
+}
+
+// GetWebhookDelivery returns a delivery of an endpoint with the log of its attempts.
+func (s *WebhookService) GetWebhookDelivery(ctx context.Context, endpointID, id uuid.UUID) (*models.WebhookDelivery, error) {
+	delivery, err := s.store.GetWebhookDelivery(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if delivery.EndpointID != endpointID {
+		return nil, domainerrors.New(
+			fmt.Errorf("delivery %s does not belong to endpoint %s", id, endpointID),
+			domainerrors.ENOTFOUND,
+			"webhook delivery not found",
+			domainerrors.WithOperation("WebhookService.GetWebhookDelivery"),
+		)
+	}
+	return delivery, nil
+}
+
+// RedeliverWebhookDelivery queues a delivery of an endpoint to be sent again on the next dispatch,
+// whatever its status. A delivery that already used all its attempts gets a single further attempt.
+func (s *WebhookService) RedeliverWebhookDelivery(ctx context.Context, endpointID, id uuid.UUID) (*models.WebhookDelivery, error) {
+	if _, err := s.GetWebhookDelivery(ctx, endpointID, id); err != nil {
+		return nil, err
+	}
+	return s.store.RequeueWebhookDelivery(ctx, id)
+}
+
+// Notify queues the notification for every endpoint of its tenant subscribed to its type.
+func (s *WebhookService) Notify(ctx context.Context, notification models.Notification) {
+	if err := s.enqueue(ctx, notification); err != nil {
+		s.logger.Error("failed to queue webhook deliveries",
+			zap.String("tenant", notification.TenantSlug),
+			zap.String("type", string(notification.Type)),
+			zap.String("notification", notification.ID.String()),
+			zap.Error(err))
+	}
+}
+
+// alertNotificationNamespace namespaces the IDs of alert notifications
+var alertNotificationNamespace = uuid.MustParse("1e52adfe-9ccb-41d1-a926-4f7890f3b51b")
+
+// NotifyAlert queues a threshold notification for an alert that started firing. Unlike Notify it
+// reports failures, so the alert evaluator retries the firing. The notification ID is derived from
+// the firing, so retries do not queue it again for endpoints that already have it and receivers
+// can deduplicate on it.
+func (s *WebhookService) NotifyAlert(ctx context.Context, event models.AlertEvent) error {
+	notificationType := models.NotificationQuotaThresholdReached
+	if event.Rule.MeterSlug != "" {
+		notificationType = models.NotificationMeterThresholdReached
+	}
+	firedAt := event.State.EvaluatedAt
+	if event.State.FiredAt != nil {
+		firedAt = *event.State.FiredAt
+	}
+	firing := event.Rule.ID.String() + "\x00" + event.State.SubjectID + "\x00" + firedAt.UTC().Format(time.RFC3339Nano)
+	return s.enqueue(ctx, models.Notification{
+		ID:         uuid.NewSHA1(alertNotificationNamespace, []byte(firing)),
+		Type:       notificationType,
+		TenantSlug: event.TenantSlug,
+		CreatedAt:  firedAt.UTC(),
+		Data:       event,
+	})
+}
+
+// enqueue stores a delivery of the notification for each subscribed endpoint.
+func (s *WebhookService) enqueue(ctx context.Context, notification models.Notification) error {
+	endpoints, err := s.store.ListSubscribedWebhookEndpoints(ctx, notification.Type)
+	if err != nil || len(endpoints) == 0 {
+		return err
+	}
+	payload, err := json.Marshal(notification)
+	if err != nil {
+		return domainerrors.New(err, domainerrors.EINTERNAL, "failed to encode webhook payload", domainerrors.WithOperation("WebhookService.Notify"))
+	}
+
+	var errs []error
+	for _, endpoint := range endpoints {
+		if _, err := s.store.CreateWebhookDelivery(ctx, models.CreateWebhookDeliveryInput{
+			EndpointID: endpoint.ID,
+			EventID:    notification.ID,
+			EventType:  notification.Type,
+			Payload:    payload,
+		}); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// validateWebhookURL accepts absolute http and https URLs.
+func validateWebhookURL(rawURL string, op string) error {
+	u, err := url.Parse(rawURL)
+	if err == nil && (u.Scheme != "http" && u.Scheme != "https" || u.Host == "") {
+		err = fmt.Errorf("url must be an absolute http or https URL: %s", rawURL)
+	}
+	if err != nil {
+		return domainerrors.New(err, domainerrors.EINVALID, "invalid webhook endpoint", domainerrors.WithOperation(op))
+	}
+	return nil
+}
+
+// normalizeNotificationTypes checks that at least one supported type is subscribed to and removes duplicates.
+func normalizeNotificationTypes(types []models.NotificationType, op string) ([]models.NotificationType, error) {
+	if len(types) == 0 {
+		return nil, domainerrors.New(fmt.Errorf("at least one event type is required"), domainerrors.EINVALID, "invalid webhook endpoint", domainerrors.WithOperation(op))
+	}
+	normalized := make([]models.NotificationType, 0, len(types))
+	for _, t := range types {
+		if !models.IsValidNotificationType(t) {
+			return nil, domainerrors.New(fmt.Errorf("unknown event type: %s", t), domainerrors.EINVALID, "invalid webhook endpoint", domainerrors.WithOperation(op))
+		}
+		if !slices.Contains(normalized, t) {
+			normalized = append(normalized, t)
+		}
+	}
+	return normalized, nil
+}
+
+func newWebhookSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(b), nil
+}
@@ -0,0 +1,182 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redcardinal-io/metering/application/repositories"
+	"github.com/redcardinal-io/metering/domain/models"
+	"github.com/redcardinal-io/metering/domain/pkg/config"
+	"github.com/redcardinal-io/metering/domain/pkg/constants"
+	"github.com/redcardinal-io/metering/domain/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// Headers sent with every webhook delivery
+const (
+	WebhookEventHeader     = "X-Metering-Event"
+	WebhookDeliveryHeader  = "X-Metering-Delivery"
+	WebhookTimestampHeader = "X-Metering-Timestamp"
+	WebhookSignatureHeader = "X-Metering-Signature"
+)
+
+// WebhookDispatcher sends the queued webhook deliveries of every tenant. Failed deliveries are
+// retried with exponential backoff until they run out of attempts, and every attempt is logged.
+type WebhookDispatcher struct {
+	store  repositories.WebhookStoreRepository
+	sender repositories.WebhookSenderRepository
+	config config.WebhookDispatcherConfig
+	logger *logger.Logger
+	now    func() time.Time
+}
+
+// NewWebhookDispatcher creates a WebhookDispatcher sending requests through the given sender.
+func NewWebhookDispatcher(store repositories.WebhookStoreRepository, sender repositories.WebhookSenderRepository, cfg config.WebhookDispatcherConfig, logger *logger.Logger) *WebhookDispatcher {
+	return &WebhookDispatcher{
+		store:  store,
+		sender: sender,
+		config: cfg,
+		logger: logger,
+		now:    time.Now,
+	}
+}
+
+// Run dispatches due deliveries every configured interval until the context is cancelled.
+func (d *WebhookDispatcher) Run(ctx context.Context) {
+	if d.config.Interval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(d.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := d.Dispatch(ctx); err != nil {
+				d.logger.Error("failed to dispatch webhooks", zap.Error(err))
+			}
+		}
+	}
+}
+
+// Dispatch makes a single attempt at a batch of due deliveries and returns them in their resulting
+// state. Claimed deliveries are hidden from other dispatchers for as long as the batch can take, so
+// deliveries claimed by a dispatcher that stopped are picked up again afterwards.
+func (d *WebhookDispatcher) Dispatch(ctx context.Context) ([]models.WebhookDelivery, error) {
+	now := d.now().UTC()
+	lease := time.Duration(d.config.BatchSize+1) * d.config.Timeout
+	deliveries, err := d.store.ClaimDueWebhookDeliveries(ctx, now, now.Add(lease), d.config.BatchSize)
+	if err != nil {
+		return nil, err
+	}
+
+	dispatched := make([]models.WebhookDelivery, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		tenantCtx := context.WithValue(ctx, constants.TenantSlugKey, delivery.TenantSlug)
+		updated, err := d.deliver(tenantCtx, delivery)
+		if err != nil {
+			d.logger.Error("failed to record webhook delivery attempt",
+				zap.String("tenant", delivery.TenantSlug),
+				zap.String("delivery", delivery.ID.String()),
+				zap.Error(err))
+			continue
+		}
+		dispatched = append(dispatched, *updated)
+	}
+	return dispatched, nil
+}
+
+// deliver sends a delivery to its endpoint and records the outcome of the attempt.
+func (d *WebhookDispatcher) deliver(ctx context.Context, delivery models.WebhookDelivery) (*models.WebhookDelivery, error) {
+	attempt := models.WebhookDeliveryAttempt{Attempt: delivery.Attempts + 1, AttemptedAt: d.now().UTC()}
+	succeeded := false
+
+	endpoint, err := d.store.GetWebhookEndpoint(ctx, delivery.EndpointID)
+	switch {
+	case err != nil:
+		return nil, err
+	case !endpoint.Enabled:
+		attempt.Error = "endpoint is disabled"
+	default:
+		resp, err := d.send(ctx, endpoint, delivery, attempt.AttemptedAt)
+		attempt.DurationMs = d.now().UTC().Sub(attempt.AttemptedAt).Milliseconds()
+		if err != nil {
+			attempt.Error = err.Error()
+			break
+		}
+		attempt.ResponseStatus = &resp.StatusCode
+		attempt.ResponseBody = resp.Body
+		succeeded = resp.StatusCode >= 200 && resp.StatusCode < 300
+		if !succeeded {
+			attempt.Error = fmt.Sprintf("endpoint responded with status %d", resp.StatusCode)
+		}
+	}
+
+	arg := models.RecordWebhookAttemptInput{
+		DeliveryID:    delivery.ID,
+		Attempt:       attempt,
+		Status:        models.WebhookDeliveryPending,
+		NextAttemptAt: attempt.AttemptedAt.Add(d.backoff(attempt.Attempt)),
+	}
+	switch {
+	case succeeded:
+		arg.Status = models.WebhookDeliverySucceeded
+		arg.NextAttemptAt = attempt.AttemptedAt
+	case !endpoint.Enabled || attempt.Attempt >= d.config.MaxAttempts:
+		arg.Status = models.WebhookDeliveryFailed
+		arg.NextAttemptAt = attempt.AttemptedAt
+	}
+	return d.store.RecordWebhookAttempt(ctx, arg)
+}
+
+// send posts the signed payload of a delivery to its endpoint.
+func (d *WebhookDispatcher) send(ctx context.Context, endpoint *models.WebhookEndpoint, delivery models.WebhookDelivery, at time.Time) (*models.WebhookResponse, error) {
+	if d.config.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, d.config.Timeout)
+		defer cancel()
+	}
+	timestamp := at.Unix()
+	return d.sender.Send(ctx, models.WebhookRequest{
+		URL: endpoint.URL,
+		Headers: map[string]string{
+			"Content-Type":         "application/json",
+			WebhookEventHeader:     string(delivery.EventType),
+			WebhookDeliveryHeader:  delivery.ID.String(),
+			WebhookTimestampHeader: strconv.FormatInt(timestamp, 10),
+			WebhookSignatureHeader: SignWebhookPayload(endpoint.Secret, timestamp, delivery.Payload),
+		},
+		Body: delivery.Payload,
+	})
+}
+
+// backoff returns the delay before retrying after the given attempt: the initial backoff doubled
+// for every earlier attempt, up to the maximum backoff.
+func (d *WebhookDispatcher) backoff(attempt int) time.Duration {
+	delay := d.config.InitialBackoff
+	for i := 1; i < attempt && delay < d.config.MaxBackoff; i++ {
+		delay *= 2
+	}
+	if d.config.MaxBackoff > 0 && delay > d.config.MaxBackoff {
+		delay = d.config.MaxBackoff
+	}
+	return delay
+}
+
+// SignWebhookPayload returns the signature header of a webhook payload sent at timestamp: the
+// hex encoded HMAC-SHA256 of "<timestamp>.<payload>" keyed with the endpoint secret. Receivers
+// recompute it to authenticate deliveries and reject old timestamps to prevent replays.
+func SignWebhookPayload(secret string, timestamp int64, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	return "v1=" + hex.EncodeToString(mac.Sum(nil))
+}
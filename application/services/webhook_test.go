@@ -0,0 +1,293 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/redcardinal-io/metering/application/repositories"
+	domainerrors "github.com/redcardinal-io/metering/domain/errors"
+	"github.com/redcardinal-io/metering/domain/models"
+	"github.com/redcardinal-io/metering/domain/pkg/config"
+	"github.com/redcardinal-io/metering/domain/pkg/constants"
+	"github.com/redcardinal-io/metering/domain/pkg/logger"
+)
+
+// fakeWebhookStore keeps endpoints and deliveries in memory
+type fakeWebhookStore struct {
+	repositories.WebhookStoreRepository
+	endpoints  map[uuid.UUID]*models.WebhookEndpoint
+	deliveries []*models.WebhookDelivery
+	attempts   []models.RecordWebhookAttemptInput
+}
+
+func (f *fakeWebhookStore) CreateWebhookEndpoint(ctx context.Context, arg models.CreateWebhookEndpointInput) (*models.WebhookEndpoint, error) {
+	endpoint := &models.WebhookEndpoint{Base: models.Base{ID: uuid.New()}, URL: arg.URL, Secret: arg.Secret, EventTypes: arg.EventTypes, Enabled: arg.Enabled}
+	f.endpoints[endpoint.ID] = endpoint
+	return endpoint, nil
+}
+
+func (f *fakeWebhookStore) GetWebhookEndpoint(ctx context.Context, id uuid.UUID) (*models.WebhookEndpoint, error) {
+	endpoint, ok := f.endpoints[id]
+	if !ok {
+		return nil, domainerrors.New(nil, domainerrors.ENOTFOUND, "Resource not found")
+	}
+	return endpoint, nil
+}
+
+func (f *fakeWebhookStore) ListSubscribedWebhookEndpoints(ctx context.Context, notificationType models.NotificationType) ([]models.WebhookEndpoint, error) {
+	var endpoints []models.WebhookEndpoint
+	for _, endpoint := range f.endpoints {
+		for _, t := range endpoint.EventTypes {
+			if t == notificationType && endpoint.Enabled {
+				endpoints = append(endpoints, *endpoint)
+			}
+		}
+	}
+	return endpoints, nil
+}
+
+func (f *fakeWebhookStore) CreateWebhookDelivery(ctx context.Context, arg models.CreateWebhookDeliveryInput) (*models.WebhookDelivery, error) {
+	for _, delivery := range f.deliveries {
+		if delivery.EndpointID == arg.EndpointID && delivery.EventID == arg.EventID {
+			return delivery, nil
+		}
+	}
+	delivery := &models.WebhookDelivery{
+		ID:         uuid.New(),
+		EndpointID: arg.EndpointID,
+		TenantSlug: ctx.Value(constants.TenantSlugKey).(string),
+		EventID:    arg.EventID,
+		EventType:  arg.EventType,
+		Payload:    arg.Payload,
+		Status:     models.WebhookDeliveryPending,
+	}
+	f.deliveries = append(f.deliveries, delivery)
+	return delivery, nil
+}
+
+func (f *fakeWebhookStore) ClaimDueWebhookDeliveries(ctx context.Context, now, leaseUntil time.Time, limit int) ([]models.WebhookDelivery, error) {
+	var due []models.WebhookDelivery
+	for _, delivery := range f.deliveries {
+		if delivery.Status == models.WebhookDeliveryPending && !delivery.NextAttemptAt.After(now) && len(due) < limit {
+			delivery.NextAttemptAt = leaseUntil
+			due = append(due, *delivery)
+		}
+	}
+	return due, nil
+}
+
+func (f *fakeWebhookStore) RecordWebhookAttempt(ctx context.Context, arg models.RecordWebhookAttemptInput) (*models.WebhookDelivery, error) {
+	f.attempts = append(f.attempts, arg)
+	for _, delivery := range f.deliveries {
+		if delivery.ID == arg.DeliveryID {
+			delivery.Status = arg.Status
+			delivery.Attempts = arg.Attempt.Attempt
+			delivery.NextAttemptAt = arg.NextAttemptAt
+			delivery.LastError = arg.Attempt.Error
+			return delivery, nil
+		}
+	}
+	return nil, domainerrors.New(nil, domainerrors.ENOTFOUND, "Resource not found")
+}
+
+// fakeWebhookSender answers every request with the next canned status
+type fakeWebhookSender struct {
+	statuses []int
+	requests []models.WebhookRequest
+}
+
+func (f *fakeWebhookSender) Send(ctx context.Context, req models.WebhookRequest) (*models.WebhookResponse, error) {
+	f.requests = append(f.requests, req)
+	if len(f.statuses) == 0 {
+		return nil, errors.New("connection refused")
+	}
+	status := f.statuses[0]
+	f.statuses = f.statuses[1:]
+	return &models.WebhookResponse{StatusCode: status}, nil
+}
+
+func TestWebhookService_Notify(t *testing.T) {
+	ctx := context.WithValue(context.Background(), constants.TenantSlugKey, "acme")
+	store := &fakeWebhookStore{endpoints: map[uuid.UUID]*models.WebhookEndpoint{}}
+	svc := NewWebhookService(store, &logger.Logger{Logger: zap.NewNop()})
+
+	subscribed, err := svc.CreateWebhookEndpoint(ctx, models.CreateWebhookEndpointInput{
+		URL:        "https://example.com/hooks",
+		EventTypes: []models.NotificationType{models.NotificationMeterCreated, models.NotificationMeterCreated},
+		Enabled:    true,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []models.NotificationType{models.NotificationMeterCreated}, subscribed.EventTypes)
+	assert.Regexp(t, "^whsec_[0-9a-f]{64}$", subscribed.Secret)
+	_, err = svc.CreateWebhookEndpoint(ctx, models.CreateWebhookEndpointInput{
+		URL:        "https://example.com/plans",
+		EventTypes: []models.NotificationType{models.NotificationPlanArchived},
+		Enabled:    true,
+	})
+	require.NoError(t, err)
+
+	meters := NewMeterService(&fakeOlap{}, &fakeMeterStore{meters: map[string]*models.Meter{}}, nil, config.QueryCacheConfig{}, svc)
+	notify(ctx, meters.notifiers, models.NotificationMeterCreated, &models.Meter{Slug: "api_calls"})
+
+	require.Len(t, store.deliveries, 1)
+	delivery := store.deliveries[0]
+	assert.Equal(t, subscribed.ID, delivery.EndpointID)
+	assert.Equal(t, "acme", delivery.TenantSlug)
+
+	var payload struct {
+		ID         uuid.UUID               `json:"id"`
+		Type       models.NotificationType `json:"type"`
+		TenantSlug string                  `json:"tenant_slug"`
+		Data       models.Meter            `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(delivery.Payload, &payload))
+	assert.Equal(t, delivery.EventID, payload.ID)
+	assert.Equal(t, models.NotificationMeterCreated, payload.Type)
+	assert.Equal(t, "acme", payload.TenantSlug)
+	assert.Equal(t, "api_calls", payload.Data.Slug)
+}
+
+func TestWebhookService_NotifyAlert(t *testing.T) {
+	ctx := context.WithValue(context.Background(), constants.TenantSlugKey, "acme")
+	store := &fakeWebhookStore{endpoints: map[uuid.UUID]*models.WebhookEndpoint{}}
+	svc := NewWebhookService(store, &logger.Logger{Logger: zap.NewNop()})
+	for _, url := range []string{"https://example.com/a", "https://example.com/b"} {
+		_, err := svc.CreateWebhookEndpoint(ctx, models.CreateWebhookEndpointInput{
+			URL:        url,
+			EventTypes: []models.NotificationType{models.NotificationMeterThresholdReached},
+			Enabled:    true,
+		})
+		require.NoError(t, err)
+	}
+
+	firedAt := time.Date(2025, 6, 18, 12, 0, 0, 0, time.UTC)
+	event := models.AlertEvent{
+		TenantSlug: "acme",
+		Rule:       models.AlertRule{Base: models.Base{ID: uuid.New()}, MeterSlug: "api_calls"},
+		State:      models.AlertState{SubjectID: "org-a", Status: models.AlertStatusFiring, FiredAt: &firedAt},
+	}
+	require.NoError(t, svc.NotifyAlert(ctx, event))
+	require.Len(t, store.deliveries, 2)
+	eventID := store.deliveries[0].EventID
+
+	// A retried firing is not queued again
+	require.NoError(t, svc.NotifyAlert(ctx, event))
+	require.Len(t, store.deliveries, 2)
+	assert.Equal(t, eventID, store.deliveries[1].EventID)
+
+	// Firings of other subjects or at other times are separate events
+	other := event
+	other.State.SubjectID = "org-b"
+	require.NoError(t, svc.NotifyAlert(ctx, other))
+	later := event
+	laterFiredAt := firedAt.Add(time.Hour)
+	later.State.FiredAt = &laterFiredAt
+	require.NoError(t, svc.NotifyAlert(ctx, later))
+	require.Len(t, store.deliveries, 6)
+	assert.NotEqual(t, eventID, store.deliveries[2].EventID)
+	assert.NotEqual(t, eventID, store.deliveries[4].EventID)
+}
+
+func TestWebhookService_CreateWebhookEndpoint(t *testing.T) {
+	ctx := context.WithValue(context.Background(), constants.TenantSlugKey, "acme")
+	svc := NewWebhookService(&fakeWebhookStore{endpoints: map[uuid.UUID]*models.WebhookEndpoint{}}, &logger.Logger{Logger: zap.NewNop()})
+
+	tests := []struct {
+		name string
+		arg  models.CreateWebhookEndpointInput
+	}{
+		{"relative url", models.CreateWebhookEndpointInput{URL: "/hooks", EventTypes: []models.NotificationType{models.NotificationMeterCreated}}},
+		{"unsupported scheme", models.CreateWebhookEndpointInput{URL: "ftp://example.com", EventTypes: []models.NotificationType{models.NotificationMeterCreated}}},
+		{"no event types", models.CreateWebhookEndpointInput{URL: "https://example.com"}},
+		{"unknown event type", models.CreateWebhookEndpointInput{URL: "https://example.com", EventTypes: []models.NotificationType{"meter.exploded"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := svc.CreateWebhookEndpoint(ctx, tt.arg)
+			assert.Equal(t, string(domainerrors.EINVALID), domainerrors.GetErrorCode(err))
+		})
+	}
+}
+
+func TestWebhookDispatcher_Dispatch(t *testing.T) {
+	now := time.Date(2025, 6, 18, 12, 0, 0, 0, time.UTC)
+	endpoint := &models.WebhookEndpoint{Base: models.Base{ID: uuid.New()}, URL: "https://example.com/hooks", Secret: "whsec_test", Enabled: true}
+	payload := []byte(`{"type":"meter.created"}`)
+	newStore := func() *fakeWebhookStore {
+		return &fakeWebhookStore{
+			endpoints: map[uuid.UUID]*models.WebhookEndpoint{endpoint.ID: endpoint},
+			deliveries: []*models.WebhookDelivery{{
+				ID:            uuid.New(),
+				EndpointID:    endpoint.ID,
+				TenantSlug:    "acme",
+				EventType:     models.NotificationMeterCreated,
+				Payload:       payload,
+				Status:        models.WebhookDeliveryPending,
+				NextAttemptAt: now,
+			}},
+		}
+	}
+	cfg := config.WebhookDispatcherConfig{BatchSize: 10, MaxAttempts: 3, InitialBackoff: time.Minute, MaxBackoff: 90 * time.Second, Timeout: time.Second}
+	log := &logger.Logger{Logger: zap.NewNop()}
+
+	t.Run("Signs and delivers due deliveries", func(t *testing.T) {
+		store, sender := newStore(), &fakeWebhookSender{statuses: []int{204}}
+		dispatcher := NewWebhookDispatcher(store, sender, cfg, log)
+		dispatcher.now = func() time.Time { return now }
+
+		delivered, err := dispatcher.Dispatch(context.Background())
+		require.NoError(t, err)
+		require.Len(t, delivered, 1)
+		assert.Equal(t, models.WebhookDeliverySucceeded, delivered[0].Status)
+
+		require.Len(t, sender.requests, 1)
+		req := sender.requests[0]
+		assert.Equal(t, endpoint.URL, req.URL)
+		assert.Equal(t, payload, req.Body)
+		assert.Equal(t, strconv.FormatInt(now.Unix(), 10), req.Headers[WebhookTimestampHeader])
+		assert.Equal(t, SignWebhookPayload(endpoint.Secret, now.Unix(), payload), req.Headers[WebhookSignatureHeader])
+		assert.NotEqual(t, SignWebhookPayload("other", now.Unix(), payload), req.Headers[WebhookSignatureHeader])
+		assert.Equal(t, 204, *store.attempts[0].Attempt.ResponseStatus)
+	})
+
+	t.Run("Retries with backoff until out of attempts", func(t *testing.T) {
+		store, sender := newStore(), &fakeWebhookSender{statuses: []int{500}}
+		dispatcher := NewWebhookDispatcher(store, sender, cfg, log)
+		clock := now
+		dispatcher.now = func() time.Time { return clock }
+
+		_, err := dispatcher.Dispatch(context.Background())
+		require.NoError(t, err)
+		delivery := store.deliveries[0]
+		assert.Equal(t, models.WebhookDeliveryPending, delivery.Status)
+		assert.Equal(t, now.Add(time.Minute), delivery.NextAttemptAt)
+		assert.Equal(t, "endpoint responded with status 500", delivery.LastError)
+
+		// Not due yet
+		delivered, err := dispatcher.Dispatch(context.Background())
+		require.NoError(t, err)
+		assert.Empty(t, delivered)
+
+		clock = now.Add(time.Minute)
+		_, err = dispatcher.Dispatch(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, clock.Add(90*time.Second), delivery.NextAttemptAt)
+
+		clock = clock.Add(90 * time.Second)
+		_, err = dispatcher.Dispatch(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, models.WebhookDeliveryFailed, delivery.Status)
+		assert.Equal(t, 3, delivery.Attempts)
+		assert.Equal(t, "connection refused", delivery.LastError)
+		assert.Len(t, store.attempts, 3)
+	})
+}
@@ -15,62 +15,9 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/v1/events": {
-            "post": {
-                "description": "Publishes a batch of events to the system",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "events"
-                ],
-                "summary": "Publish events",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Tenant slug identifier",
-                        "name": "X-Tenant",
-                        "in": "header",
-                        "required": true
-                    },
-                    {
-                        "description": "Event batch to publish",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/events.publishEventRequestBody"
-                        }
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "Events published successfully",
-                        "schema": {
-                            "$ref": "#/definitions/models.HttpResponse-models_PublishEventsResult"
-                        }
-                    },
-                    "400": {
-                        "description": "Invalid request",
-                        "schema": {
-                            "$ref": "#/definitions/errors.ErrorResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "Server error",
-                        "schema": {
-                            "$ref": "#/definitions/errors.ErrorResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/v1/features": {
+        "/v1/alerts": {
             "get": {
-                "description": "Get a list of features for the tenant",
+                "description": "Get a list of alert rules for the tenant",
                 "consumes": [
                     "application/json"
                 ],
@@ -78,9 +25,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "features"
+                    "alerts"
                 ],
-                "summary": "List features",
+                "summary": "List alert rules",
                 "parameters": [
                     {
                         "type": "string",
@@ -100,31 +47,13 @@ const docTemplate = `{
                         "description": "Items per page",
                         "name": "limit",
                         "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Sort field",
-                        "name": "sort",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Sort order (asc/desc)",
-                        "name": "order",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Filter by feature type (static/metered)",
-                        "name": "type",
-                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Features retrieved successfully",
+                        "description": "Alert rules retrieved successfully",
                         "schema": {
-                            "$ref": "#/definitions/models.HttpResponse-array_models_Feature"
+                            "$ref": "#/definitions/models.HttpResponse-array_models_AlertRule"
                         }
                     },
                     "400": {
@@ -142,7 +71,7 @@ const docTemplate = `{
                 }
             },
             "post": {
-                "description": "Create a new feature for the tenant",
+                "description": "Create a rule alerting when the usage of subjects reaches a threshold. Meter rules compare the usage of a meter over the trailing window_minutes with an absolute threshold; plan feature rules compare the usage of the subjects assigned the plan over the current quota period with an absolute threshold or a percentage of the quota. Without subject_ids the rule watches every subject of subject_type.",
                 "consumes": [
                     "application/json"
                 ],
@@ -150,9 +79,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "features"
+                    "alerts"
                 ],
-                "summary": "Create a new feature",
+                "summary": "Create an alert rule",
                 "parameters": [
                     {
                         "type": "string",
@@ -162,20 +91,20 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Feature creation data",
-                        "name": "feature",
+                        "description": "Alert rule creation data",
+                        "name": "rule",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/features.createFeatureRequest"
+                            "$ref": "#/definitions/alerts.createAlertRuleRequest"
                         }
                     }
                 ],
                 "responses": {
                     "201": {
-                        "description": "Feature created successfully",
+                        "description": "Alert rule created successfully",
                         "schema": {
-                            "$ref": "#/definitions/models.HttpResponse-models_Feature"
+                            "$ref": "#/definitions/models.HttpResponse-models_AlertRule"
                         }
                     },
                     "400": {
@@ -184,6 +113,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/errors.ErrorResponse"
                         }
                     },
+                    "404": {
+                        "description": "Meter or plan feature not found",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
@@ -193,9 +128,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/features/{idOrSlug}": {
+        "/v1/alerts/{id}": {
             "get": {
-                "description": "Get feature details by ID or slug",
+                "description": "Get an alert rule by ID",
                 "consumes": [
                     "application/json"
                 ],
@@ -203,9 +138,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "features"
+                    "alerts"
                 ],
-                "summary": "Get feature details",
+                "summary": "Get an alert rule",
                 "parameters": [
                     {
                         "type": "string",
@@ -216,17 +151,17 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "description": "Feature ID or Slug",
-                        "name": "idOrSlug",
+                        "description": "Alert rule ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Feature retrieved successfully",
+                        "description": "Alert rule retrieved successfully",
                         "schema": {
-                            "$ref": "#/definitions/models.HttpResponse-models_Feature"
+                            "$ref": "#/definitions/models.HttpResponse-models_AlertRule"
                         }
                     },
                     "400": {
@@ -236,7 +171,7 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "Feature not found",
+                        "description": "Alert rule not found",
                         "schema": {
                             "$ref": "#/definitions/errors.ErrorResponse"
                         }
@@ -250,7 +185,7 @@ const docTemplate = `{
                 }
             },
             "put": {
-                "description": "Update a feature by ID or slug",
+                "description": "Update the name, subjects, threshold, window or enabled state of an alert rule. The target and threshold type of a rule cannot change.",
                 "consumes": [
                     "application/json"
                 ],
@@ -258,9 +193,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "features"
+                    "alerts"
                 ],
-                "summary": "Update a feature",
+                "summary": "Update an alert rule",
                 "parameters": [
                     {
                         "type": "string",
@@ -271,26 +206,26 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "description": "Feature ID or Slug",
-                        "name": "idOrSlug",
+                        "description": "Alert rule ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Feature update data",
-                        "name": "feature",
+                        "description": "Alert rule update data",
+                        "name": "rule",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/features.updateFeatureRequest"
+                            "$ref": "#/definitions/alerts.updateAlertRuleRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Feature updated successfully",
+                        "description": "Alert rule updated successfully",
                         "schema": {
-                            "$ref": "#/definitions/models.HttpResponse-models_Feature"
+                            "$ref": "#/definitions/models.HttpResponse-models_AlertRule"
                         }
                     },
                     "400": {
@@ -300,7 +235,7 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "Feature not found",
+                        "description": "Alert rule not found",
                         "schema": {
                             "$ref": "#/definitions/errors.ErrorResponse"
                         }
@@ -314,7 +249,7 @@ const docTemplate = `{
                 }
             },
             "delete": {
-                "description": "Delete a feature by ID or slug",
+                "description": "Delete an alert rule and its recorded states",
                 "consumes": [
                     "application/json"
                 ],
@@ -322,9 +257,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "features"
+                    "alerts"
                 ],
-                "summary": "Delete a feature",
+                "summary": "Delete an alert rule",
                 "parameters": [
                     {
                         "type": "string",
@@ -335,15 +270,15 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "description": "Feature ID or Slug",
-                        "name": "idOrSlug",
+                        "description": "Alert rule ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "204": {
-                        "description": "Feature deleted successfully"
+                        "description": "Alert rule deleted successfully"
                     },
                     "400": {
                         "description": "Invalid request",
@@ -352,7 +287,7 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "Feature not found",
+                        "description": "Alert rule not found",
                         "schema": {
                             "$ref": "#/definitions/errors.ErrorResponse"
                         }
@@ -366,9 +301,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/meters": {
-            "get": {
-                "description": "Get a paginated list of all meters for the tenant",
+        "/v1/alerts/{id}/evaluate": {
+            "post": {
+                "description": "Evaluate an alert rule immediately, whether or not it is enabled, notifying subjects that start firing. Returns the states recorded by the evaluation.",
                 "consumes": [
                     "application/json"
                 ],
@@ -376,9 +311,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "meters"
+                    "alerts"
                 ],
-                "summary": "List all meters",
+                "summary": "Evaluate an alert rule",
                 "parameters": [
                     {
                         "type": "string",
@@ -388,25 +323,18 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "type": "integer",
-                        "default": 1,
-                        "description": "Page number",
-                        "name": "page",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "default": 10,
-                        "description": "Items per page",
-                        "name": "limit",
-                        "in": "query"
+                        "type": "string",
+                        "description": "Alert rule ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Meters retrieved successfully",
+                        "description": "Alert rule evaluated successfully",
                         "schema": {
-                            "$ref": "#/definitions/models.HttpResponse-pagination_PaginationView-models_Meter"
+                            "$ref": "#/definitions/models.HttpResponse-array_models_AlertState"
                         }
                     },
                     "400": {
@@ -415,6 +343,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/errors.ErrorResponse"
                         }
                     },
+                    "404": {
+                        "description": "Alert rule not found",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
@@ -422,9 +356,11 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "post": {
-                "description": "Create a new meter for the tenant",
+            }
+        },
+        "/v1/alerts/{id}/states": {
+            "get": {
+                "description": "Get the state of an alert rule for every subject it has fired for",
                 "consumes": [
                     "application/json"
                 ],
@@ -432,9 +368,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "meters"
+                    "alerts"
                 ],
-                "summary": "Create a new meter",
+                "summary": "List alert states",
                 "parameters": [
                     {
                         "type": "string",
@@ -444,20 +380,18 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Meter creation data",
-                        "name": "meter",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/meters.createMeterRequest"
-                        }
+                        "type": "string",
+                        "description": "Alert rule ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Meter created successfully",
+                    "200": {
+                        "description": "Alert states retrieved successfully",
                         "schema": {
-                            "$ref": "#/definitions/models.HttpResponse-models_Meter"
+                            "$ref": "#/definitions/models.HttpResponse-array_models_AlertState"
                         }
                     },
                     "400": {
@@ -466,6 +400,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/errors.ErrorResponse"
                         }
                     },
+                    "404": {
+                        "description": "Alert rule not found",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
@@ -475,9 +415,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/meters/query": {
+        "/v1/events": {
             "post": {
-                "description": "Query meter data with filters and grouping options",
+                "description": "Publishes a batch of events to the system",
                 "consumes": [
                     "application/json"
                 ],
@@ -485,32 +425,32 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "meters"
+                    "events"
                 ],
-                "summary": "Query meter data",
+                "summary": "Publish events",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Tenant Slug",
-                        "name": "X-Tenant-Slug",
+                        "description": "Tenant slug identifier",
+                        "name": "X-Tenant",
                         "in": "header",
                         "required": true
                     },
                     {
-                        "description": "Query parameters",
-                        "name": "query",
+                        "description": "Event batch to publish",
+                        "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/meters.queryMeterRequest"
+                            "$ref": "#/definitions/events.publishEventRequestBody"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Meter queried successfully",
+                        "description": "Events published successfully",
                         "schema": {
-                            "$ref": "#/definitions/models.HttpResponse-models_QueryMeterResult"
+                            "$ref": "#/definitions/models.HttpResponse-models_PublishEventsResult"
                         }
                     },
                     "400": {
@@ -519,14 +459,8 @@ const docTemplate = `{
                             "$ref": "#/definitions/errors.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Meter not found",
-                        "schema": {
-                            "$ref": "#/definitions/errors.ErrorResponse"
-                        }
-                    },
                     "500": {
-                        "description": "Internal server error",
+                        "description": "Server error",
                         "schema": {
                             "$ref": "#/definitions/errors.ErrorResponse"
                         }
@@ -534,9 +468,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/meters/{idOrSlug}": {
+        "/v1/features": {
             "get": {
-                "description": "Get details of a specific meter by ID or slug",
+                "description": "Get a list of features for the tenant",
                 "consumes": [
                     "application/json"
                 ],
@@ -544,9 +478,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "meters"
+                    "features"
                 ],
-                "summary": "Get meter details",
+                "summary": "List features",
                 "parameters": [
                     {
                         "type": "string",
@@ -555,19 +489,42 @@ const docTemplate = `{
                         "in": "header",
                         "required": true
                     },
+                    {
+                        "type": "integer",
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Items per page",
+                        "name": "limit",
+                        "in": "query"
+                    },
                     {
                         "type": "string",
-                        "description": "Meter ID or slug",
-                        "name": "idOrSlug",
-                        "in": "path",
-                        "required": true
+                        "description": "Sort field",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort order (asc/desc)",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by feature type (static/metered)",
+                        "name": "type",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Meter retrieved successfully",
+                        "description": "Features retrieved successfully",
                         "schema": {
-                            "$ref": "#/definitions/models.HttpResponse-models_Meter"
+                            "$ref": "#/definitions/models.HttpResponse-array_models_Feature"
                         }
                     },
                     "400": {
@@ -576,12 +533,6 @@ const docTemplate = `{
                             "$ref": "#/definitions/errors.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Meter not found",
-                        "schema": {
-                            "$ref": "#/definitions/errors.ErrorResponse"
-                        }
-                    },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
@@ -590,8 +541,8 @@ const docTemplate = `{
                     }
                 }
             },
-            "put": {
-                "description": "Update a meter's details by ID or slug",
+            "post": {
+                "description": "Create a new feature for the tenant. Metered features can link the meters their usage is measured by, and merge_rule (sum by default, or max) sets how the quotas of a base plan and its add-ons granting the feature combine.",
                 "consumes": [
                     "application/json"
                 ],
@@ -599,9 +550,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "meters"
+                    "features"
                 ],
-                "summary": "Update a meter",
+                "summary": "Create a new feature",
                 "parameters": [
                     {
                         "type": "string",
@@ -611,27 +562,20 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "type": "string",
-                        "description": "Meter ID or slug",
-                        "name": "idOrSlug",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Meter update data",
-                        "name": "meter",
+                        "description": "Feature creation data",
+                        "name": "feature",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/meters.updateMeterRequest"
+                            "$ref": "#/definitions/features.createFeatureRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "Meter updated successfully",
+                    "201": {
+                        "description": "Feature created successfully",
                         "schema": {
-                            "$ref": "#/definitions/models.HttpResponse-models_Meter"
+                            "$ref": "#/definitions/models.HttpResponse-models_Feature"
                         }
                     },
                     "400": {
@@ -640,12 +584,6 @@ const docTemplate = `{
                             "$ref": "#/definitions/errors.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Meter not found",
-                        "schema": {
-                            "$ref": "#/definitions/errors.ErrorResponse"
-                        }
-                    },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
@@ -653,9 +591,11 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
-                "description": "Delete a meter by ID or slug",
+            }
+        },
+        "/v1/features/{idOrSlug}": {
+            "get": {
+                "description": "Get feature details by ID or slug",
                 "consumes": [
                     "application/json"
                 ],
@@ -663,9 +603,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "meters"
+                    "features"
                 ],
-                "summary": "Delete a meter",
+                "summary": "Get feature details",
                 "parameters": [
                     {
                         "type": "string",
@@ -676,15 +616,18 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "description": "Meter ID or slug",
+                        "description": "Feature ID or Slug",
                         "name": "idOrSlug",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "Meter deleted successfully"
+                    "200": {
+                        "description": "Feature retrieved successfully",
+                        "schema": {
+                            "$ref": "#/definitions/models.HttpResponse-models_Feature"
+                        }
                     },
                     "400": {
                         "description": "Invalid request",
@@ -693,7 +636,7 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "Meter not found",
+                        "description": "Feature not found",
                         "schema": {
                             "$ref": "#/definitions/errors.ErrorResponse"
                         }
@@ -705,11 +648,9 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/v1/plans": {
-            "get": {
-                "description": "Get a paginated list of all plans for the tenant",
+            },
+            "put": {
+                "description": "Update a feature by ID or slug",
                 "consumes": [
                     "application/json"
                 ],
@@ -717,9 +658,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "plans"
+                    "features"
                 ],
-                "summary": "List all plans",
+                "summary": "Update a feature",
                 "parameters": [
                     {
                         "type": "string",
@@ -729,25 +670,27 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "type": "integer",
-                        "default": 1,
-                        "description": "Page number",
-                        "name": "page",
-                        "in": "query"
+                        "type": "string",
+                        "description": "Feature ID or Slug",
+                        "name": "idOrSlug",
+                        "in": "path",
+                        "required": true
                     },
                     {
-                        "type": "integer",
-                        "default": 10,
-                        "description": "Items per page",
-                        "name": "limit",
-                        "in": "query"
+                        "description": "Feature update data",
+                        "name": "feature",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/features.updateFeatureRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Plans retrieved successfully",
+                        "description": "Feature updated successfully",
                         "schema": {
-                            "$ref": "#/definitions/models.HttpResponse-pagination_PaginationView-models_Plan"
+                            "$ref": "#/definitions/models.HttpResponse-models_Feature"
                         }
                     },
                     "400": {
@@ -756,6 +699,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/errors.ErrorResponse"
                         }
                     },
+                    "404": {
+                        "description": "Feature not found",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
@@ -764,8 +713,8 @@ const docTemplate = `{
                     }
                 }
             },
-            "post": {
-                "description": "Create a new plan for the tenant",
+            "delete": {
+                "description": "Delete a feature by ID or slug",
                 "consumes": [
                     "application/json"
                 ],
@@ -773,9 +722,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "plans"
+                    "features"
                 ],
-                "summary": "Create a new plan",
+                "summary": "Delete a feature",
                 "parameters": [
                     {
                         "type": "string",
@@ -785,21 +734,16 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Plan information",
-                        "name": "plan",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/plans.createPlanRequest"
-                        }
+                        "type": "string",
+                        "description": "Feature ID or Slug",
+                        "name": "idOrSlug",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Plan created successfully",
-                        "schema": {
-                            "$ref": "#/definitions/models.HttpResponse-models_Plan"
-                        }
+                    "204": {
+                        "description": "Feature deleted successfully"
                     },
                     "400": {
                         "description": "Invalid request",
@@ -807,8 +751,8 @@ const docTemplate = `{
                             "$ref": "#/definitions/errors.ErrorResponse"
                         }
                     },
-                    "409": {
-                        "description": "Plan already exists",
+                    "404": {
+                        "description": "Feature not found",
                         "schema": {
                             "$ref": "#/definitions/errors.ErrorResponse"
                         }
@@ -822,9 +766,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/plans/assignments": {
+        "/v1/meters": {
             "get": {
-                "description": "Get a list of plan assignments with optional filtering",
+                "description": "Get a paginated list of all meters for the tenant",
                 "consumes": [
                     "application/json"
                 ],
@@ -832,9 +776,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "plan-assignments"
+                    "meters"
                 ],
-                "summary": "List plan assignments",
+                "summary": "List all meters",
                 "parameters": [
                     {
                         "type": "string",
@@ -843,44 +787,16 @@ const docTemplate = `{
                         "in": "header",
                         "required": true
                     },
-                    {
-                        "type": "string",
-                        "description": "Valid from date (format: YYYY-MM-DDThh:mm:ssZ)",
-                        "name": "validFrom",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Valid until date (format: YYYY-MM-DDThh:mm:ssZ)",
-                        "name": "validUntil",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Plan ID or slug",
-                        "name": "planIdOrSlug",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Organization ID",
-                        "name": "orgId",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "User ID",
-                        "name": "userId",
-                        "in": "query"
-                    },
                     {
                         "type": "integer",
+                        "default": 1,
                         "description": "Page number",
                         "name": "page",
                         "in": "query"
                     },
                     {
                         "type": "integer",
+                        "default": 10,
                         "description": "Items per page",
                         "name": "limit",
                         "in": "query"
@@ -888,9 +804,9 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "Assignments retrieved successfully",
+                        "description": "Meters retrieved successfully",
                         "schema": {
-                            "$ref": "#/definitions/models.HttpResponse-pagination_PaginationView-models_PlanAssignment"
+                            "$ref": "#/definitions/models.HttpResponse-pagination_PaginationView-models_Meter"
                         }
                     },
                     "400": {
@@ -907,8 +823,8 @@ const docTemplate = `{
                     }
                 }
             },
-            "put": {
-                "description": "Update the validity period for a plan assignment",
+            "post": {
+                "description": "Create a new meter for the tenant. Derived meters set a formula over the slugs of other meters, writing slugs that are not plain identifiers between brackets, e.g. \"[api-calls] / requests\"",
                 "consumes": [
                     "application/json"
                 ],
@@ -916,9 +832,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "plan-assignments"
+                    "meters"
                 ],
-                "summary": "Update a plan assignment",
+                "summary": "Create a new meter",
                 "parameters": [
                     {
                         "type": "string",
@@ -928,20 +844,20 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Assignment update information",
-                        "name": "assignment",
+                        "description": "Meter creation data",
+                        "name": "meter",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/assignments.updateAssignedPlanRequest"
+                            "$ref": "#/definitions/meters.createMeterRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "Assignment updated successfully",
+                    "201": {
+                        "description": "Meter created successfully",
                         "schema": {
-                            "$ref": "#/definitions/models.HttpResponse-models_PlanAssignment"
+                            "$ref": "#/definitions/models.HttpResponse-models_Meter"
                         }
                     },
                     "400": {
@@ -950,12 +866,6 @@ const docTemplate = `{
                             "$ref": "#/definitions/errors.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Plan assignment not found",
-                        "schema": {
-                            "$ref": "#/definitions/errors.ErrorResponse"
-                        }
-                    },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
@@ -963,19 +873,23 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
+            }
+        },
+        "/v1/meters/export": {
             "post": {
-                "description": "Assign a plan to either an organization or a user",
+                "description": "Stream meter query results as a CSV, NDJSON or Parquet file. The format is taken from the body or the Accept header.",
                 "consumes": [
                     "application/json"
                 ],
                 "produces": [
-                    "application/json"
+                    "text/csv",
+                    "application/x-ndjson",
+                    "application/vnd.apache.parquet"
                 ],
                 "tags": [
-                    "plan-assignments"
+                    "meters"
                 ],
-                "summary": "Create a new plan assignment",
+                "summary": "Export meter data",
                 "parameters": [
                     {
                         "type": "string",
@@ -985,20 +899,20 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Assignment information",
-                        "name": "assignment",
+                        "description": "Export parameters",
+                        "name": "query",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/assignments.createAssignmentRequest"
+                            "$ref": "#/definitions/meters.exportMeterRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Plan assignment created successfully",
+                    "200": {
+                        "description": "Exported meter data",
                         "schema": {
-                            "$ref": "#/definitions/models.HttpResponse-models_PlanAssignment"
+                            "type": "file"
                         }
                     },
                     "400": {
@@ -1008,7 +922,7 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "Plan not found",
+                        "description": "Meter not found",
                         "schema": {
                             "$ref": "#/definitions/errors.ErrorResponse"
                         }
@@ -1020,9 +934,11 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
-                "description": "Terminate a plan assignment for an organization or user",
+            }
+        },
+        "/v1/meters/preview": {
+            "post": {
+                "description": "Dry-run a meter definition against sample events or a range of stored events. Returns the rows the meter would produce, the SQL that would create it and warnings about the definition. Nothing is created.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1030,9 +946,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "plan-assignments"
+                    "meters"
                 ],
-                "summary": "Delete a plan assignment",
+                "summary": "Preview a meter definition",
                 "parameters": [
                     {
                         "type": "string",
@@ -1042,27 +958,24 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Assignment termination information",
-                        "name": "assignment",
+                        "description": "Meter definition and preview source",
+                        "name": "preview",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/assignments.terminatePlanRequest"
+                            "$ref": "#/definitions/meters.previewMeterRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content"
-                    },
-                    "400": {
-                        "description": "Invalid request",
+                    "200": {
+                        "description": "Meter previewed successfully",
                         "schema": {
-                            "$ref": "#/definitions/errors.ErrorResponse"
+                            "$ref": "#/definitions/models.HttpResponse-models_MeterPreview"
                         }
                     },
-                    "404": {
-                        "description": "Plan assignment not found",
+                    "400": {
+                        "description": "Invalid request",
                         "schema": {
                             "$ref": "#/definitions/errors.ErrorResponse"
                         }
@@ -1076,9 +989,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/plans/assignments/history": {
-            "get": {
-                "description": "Get historical records of plan assignments with optional filtering",
+        "/v1/meters/query": {
+            "post": {
+                "description": "Query meter data with filters and grouping options. Set cumulative to return running totals per group across windows, or compare to previous_period to add the value of the preceding period of the same length and the change against it to each row.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1086,9 +999,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "plan-assignments"
+                    "meters"
                 ],
-                "summary": "List plan assignment history",
+                "summary": "Query meter data",
                 "parameters": [
                     {
                         "type": "string",
@@ -1098,75 +1011,30 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "type": "string",
-                        "description": "Valid from before date (format: YYYY-MM-DDThh:mm:ssZ)",
-                        "name": "validFromBefore",
-                        "in": "query"
+                        "description": "Query parameters",
+                        "name": "query",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/meters.queryMeterRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Meter queried successfully",
+                        "schema": {
+                            "$ref": "#/definitions/models.HttpResponse-models_QueryMeterResult"
+                        }
                     },
-                    {
-                        "type": "string",
-                        "description": "Valid from after date (format: YYYY-MM-DDThh:mm:ssZ)",
-                        "name": "validFromAfter",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Valid until before date (format: YYYY-MM-DDThh:mm:ssZ)",
-                        "name": "validUntilBefore",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Valid until after date (format: YYYY-MM-DDThh:mm:ssZ)",
-                        "name": "validUntilAfter",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Action type (Create, Update, Delete)",
-                        "name": "action",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Plan ID or slug",
-                        "name": "planIdOrSlug",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Organization ID",
-                        "name": "orgId",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "User ID",
-                        "name": "userId",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Page number",
-                        "name": "page",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Items per page",
-                        "name": "limit",
-                        "in": "query"
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "Assignment history retrieved successfully",
+                    "400": {
+                        "description": "Invalid request",
                         "schema": {
-                            "$ref": "#/definitions/models.HttpResponse-pagination_PaginationView-models_PlanAssignmentHistory"
+                            "$ref": "#/definitions/errors.ErrorResponse"
                         }
                     },
-                    "400": {
-                        "description": "Invalid request",
+                    "404": {
+                        "description": "Meter not found",
                         "schema": {
                             "$ref": "#/definitions/errors.ErrorResponse"
                         }
@@ -1180,9 +1048,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/plans/{idOrSlug}": {
+        "/v1/meters/{idOrSlug}": {
             "get": {
-                "description": "Get detailed information about a plan by ID or slug",
+                "description": "Get details of a specific meter by ID or slug",
                 "consumes": [
                     "application/json"
                 ],
@@ -1190,9 +1058,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "plans"
+                    "meters"
                 ],
-                "summary": "Get plan details",
+                "summary": "Get meter details",
                 "parameters": [
                     {
                         "type": "string",
@@ -1203,7 +1071,7 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "description": "Plan ID or slug",
+                        "description": "Meter ID or slug",
                         "name": "idOrSlug",
                         "in": "path",
                         "required": true
@@ -1211,9 +1079,9 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "Plan retrieved successfully",
+                        "description": "Meter retrieved successfully",
                         "schema": {
-                            "$ref": "#/definitions/models.HttpResponse-models_Plan"
+                            "$ref": "#/definitions/models.HttpResponse-models_Meter"
                         }
                     },
                     "400": {
@@ -1223,7 +1091,7 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "Plan not found",
+                        "description": "Meter not found",
                         "schema": {
                             "$ref": "#/definitions/errors.ErrorResponse"
                         }
@@ -1237,7 +1105,7 @@ const docTemplate = `{
                 }
             },
             "put": {
-                "description": "Update a plan's details by ID or slug",
+                "description": "Update a meter's details by ID or slug",
                 "consumes": [
                     "application/json"
                 ],
@@ -1245,9 +1113,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "plans"
+                    "meters"
                 ],
-                "summary": "Update a plan",
+                "summary": "Update a meter",
                 "parameters": [
                     {
                         "type": "string",
@@ -1258,26 +1126,26 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "description": "Plan ID or slug",
+                        "description": "Meter ID or slug",
                         "name": "idOrSlug",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Updated plan information",
-                        "name": "plan",
+                        "description": "Meter update data",
+                        "name": "meter",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/plans.updatePlanRequest"
+                            "$ref": "#/definitions/meters.updateMeterRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Plan updated successfully",
+                        "description": "Meter updated successfully",
                         "schema": {
-                            "$ref": "#/definitions/models.HttpResponse-models_Plan"
+                            "$ref": "#/definitions/models.HttpResponse-models_Meter"
                         }
                     },
                     "400": {
@@ -1287,7 +1155,7 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "Plan not found",
+                        "description": "Meter not found",
                         "schema": {
                             "$ref": "#/definitions/errors.ErrorResponse"
                         }
@@ -1301,7 +1169,7 @@ const docTemplate = `{
                 }
             },
             "delete": {
-                "description": "Delete a plan by ID or slug",
+                "description": "Delete a meter by ID or slug",
                 "consumes": [
                     "application/json"
                 ],
@@ -1309,9 +1177,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "plans"
+                    "meters"
                 ],
-                "summary": "Delete a plan",
+                "summary": "Delete a meter",
                 "parameters": [
                     {
                         "type": "string",
@@ -1322,7 +1190,7 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "description": "Plan ID or slug",
+                        "description": "Meter ID or slug",
                         "name": "idOrSlug",
                         "in": "path",
                         "required": true
@@ -1330,7 +1198,7 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "204": {
-                        "description": "Plan deleted successfully"
+                        "description": "Meter deleted successfully"
                     },
                     "400": {
                         "description": "Invalid request",
@@ -1338,6 +1206,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/errors.ErrorResponse"
                         }
                     },
+                    "404": {
+                        "description": "Meter not found",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
@@ -1347,9 +1221,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/plans/{idOrSlug}/archive": {
-            "put": {
-                "description": "Archive or unarchive a plan by ID or slug",
+        "/v1/meters/{idOrSlug}/retry": {
+            "post": {
+                "description": "Resume the operation that failed on a meter. A failed meter gets its view created again and is marked active; a delete_failed meter is removed and returned as deleting. The failure is reported in the status_error of the meter.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1357,9 +1231,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "plans"
+                    "meters"
                 ],
-                "summary": "Archive or unarchive a plan",
+                "summary": "Retry a failed meter",
                 "parameters": [
                     {
                         "type": "string",
@@ -1370,24 +1244,18 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "description": "Plan ID or slug",
+                        "description": "Meter ID or slug",
                         "name": "idOrSlug",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Archive request information",
-                        "name": "archiveRequest",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/plans.archivePlanRequest"
-                        }
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "Plan archived/unarchived successfully"
+                    "200": {
+                        "description": "Meter retried successfully",
+                        "schema": {
+                            "$ref": "#/definitions/models.HttpResponse-models_Meter"
+                        }
                     },
                     "400": {
                         "description": "Invalid request",
@@ -1396,7 +1264,13 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "Plan not found",
+                        "description": "Meter not found",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Meter is not failed or delete_failed",
                         "schema": {
                             "$ref": "#/definitions/errors.ErrorResponse"
                         }
@@ -1410,47 +1284,96 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/plans/{planID}/features": {
+        "/v1/meters/{idOrSlug}/stream": {
             "get": {
-                "description": "Retrieves all features associated with a plan",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Stream the usage of a meter as Server-Sent Events. Each \"usage\" event carries the query result for the current window of window_size, or since from, and is sent when the usage changes. Clients with identical parameters share a single query.",
                 "produces": [
-                    "application/json"
+                    "text/event-stream"
                 ],
                 "tags": [
-                    "Plan Features"
+                    "meters"
                 ],
-                "summary": "List plan features",
+                "summary": "Stream live meter usage",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Tenant slug",
-                        "name": "X-Tenant",
+                        "description": "Tenant Slug",
+                        "name": "X-Tenant-Slug",
                         "in": "header",
                         "required": true
                     },
                     {
                         "type": "string",
-                        "format": "uuid",
-                        "description": "Plan ID",
-                        "name": "planID",
+                        "description": "Meter ID or slug",
+                        "name": "idOrSlug",
                         "in": "path",
                         "required": true
                     },
                     {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "csv",
+                        "description": "Organizations to stream usage for",
+                        "name": "organization",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "csv",
+                        "description": "Users to stream usage for",
+                        "name": "user",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "csv",
+                        "description": "Properties to group usage by",
+                        "name": "group_by",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "minute",
+                            "hour",
+                            "day"
+                        ],
                         "type": "string",
-                        "description": "Filter by feature type",
-                        "name": "feature_type",
+                        "description": "Window to stream the current usage of",
+                        "name": "window_size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Stream usage since this RFC3339 time instead of the current window",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "JSON encoded filter expression",
+                        "name": "filter",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of groups",
+                        "name": "limit",
                         "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "List of plan features",
+                        "description": "Stream of usage events",
                         "schema": {
-                            "$ref": "#/definitions/models.HttpResponse-array_models_PlanFeature"
+                            "$ref": "#/definitions/models.QueryMeterResult"
                         }
                     },
                     "400": {
@@ -1459,22 +1382,24 @@ const docTemplate = `{
                             "$ref": "#/definitions/errors.ErrorResponse"
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "404": {
+                        "description": "Meter not found",
                         "schema": {
                             "$ref": "#/definitions/errors.ErrorResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal server error",
+                    "503": {
+                        "description": "Too many subscribers",
                         "schema": {
                             "$ref": "#/definitions/errors.ErrorResponse"
                         }
                     }
                 }
-            },
-            "post": {
-                "description": "Adds a new feature to a plan",
+            }
+        },
+        "/v1/plans": {
+            "get": {
+                "description": "Get a paginated list of all plans for the tenant",
                 "consumes": [
                     "application/json"
                 ],
@@ -1482,40 +1407,43 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Plan Features"
+                    "plans"
                 ],
-                "summary": "Create plan feature",
+                "summary": "List all plans",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Tenant slug",
-                        "name": "X-Tenant",
+                        "description": "Tenant Slug",
+                        "name": "X-Tenant-Slug",
                         "in": "header",
                         "required": true
                     },
                     {
-                        "type": "string",
-                        "format": "uuid",
-                        "description": "Plan ID",
-                        "name": "planID",
-                        "in": "path",
-                        "required": true
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
                     },
                     {
-                        "description": "Plan feature details",
-                        "name": "body",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/planfeatures.createPlanFeatureRequest"
-                        }
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Items per page",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Plan kind (base/addon)",
+                        "name": "kind",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Plan feature created successfully",
+                    "200": {
+                        "description": "Plans retrieved successfully",
                         "schema": {
-                            "$ref": "#/definitions/models.HttpResponse-models_PlanFeature"
+                            "$ref": "#/definitions/models.HttpResponse-pagination_PaginationView-models_Plan"
                         }
                     },
                     "400": {
@@ -1524,18 +1452,6 @@ const docTemplate = `{
                             "$ref": "#/definitions/errors.ErrorResponse"
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
-                        "schema": {
-                            "$ref": "#/definitions/errors.ErrorResponse"
-                        }
-                    },
-                    "409": {
-                        "description": "Plan feature already exists",
-                        "schema": {
-                            "$ref": "#/definitions/errors.ErrorResponse"
-                        }
-                    },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
@@ -1543,11 +1459,9 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/v1/plans/{planID}/features/{featureID}": {
-            "put": {
-                "description": "Updates configuration of a feature associated with a plan",
+            },
+            "post": {
+                "description": "Create a new plan for the tenant. Subjects hold one base plan at a time and any number of add-on plans stacked on top of it; plans are base plans unless kind is addon.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1555,47 +1469,32 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Plan Features"
+                    "plans"
                 ],
+                "summary": "Create a new plan",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Tenant slug",
-                        "name": "X-Tenant",
+                        "description": "Tenant Slug",
+                        "name": "X-Tenant-Slug",
                         "in": "header",
                         "required": true
                     },
                     {
-                        "type": "string",
-                        "format": "uuid",
-                        "description": "Plan ID",
-                        "name": "planID",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "format": "uuid",
-                        "description": "Feature ID",
-                        "name": "featureID",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Updated plan feature details",
-                        "name": "body",
+                        "description": "Plan information",
+                        "name": "plan",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/planfeatures.updatePlanFeatureRequest"
+                            "$ref": "#/definitions/plans.createPlanRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "Plan feature updated successfully",
+                    "201": {
+                        "description": "Plan created successfully",
                         "schema": {
-                            "$ref": "#/definitions/models.HttpResponse-models_PlanFeature"
+                            "$ref": "#/definitions/models.HttpResponse-models_Plan"
                         }
                     },
                     "400": {
@@ -1604,14 +1503,8 @@ const docTemplate = `{
                             "$ref": "#/definitions/errors.ErrorResponse"
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
-                        "schema": {
-                            "$ref": "#/definitions/errors.ErrorResponse"
-                        }
-                    },
-                    "404": {
-                        "description": "Plan feature not found",
+                    "409": {
+                        "description": "Plan already exists",
                         "schema": {
                             "$ref": "#/definitions/errors.ErrorResponse"
                         }
@@ -1623,9 +1516,11 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
-                "description": "Removes a feature from a plan",
+            }
+        },
+        "/v1/plans/assignments": {
+            "get": {
+                "description": "Get a list of plan assignments with optional filtering",
                 "consumes": [
                     "application/json"
                 ],
@@ -1633,108 +1528,65 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Plan Features"
+                    "plan-assignments"
                 ],
-                "summary": "Delete plan feature",
+                "summary": "List plan assignments",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Tenant slug",
-                        "name": "X-Tenant",
+                        "description": "Tenant Slug",
+                        "name": "X-Tenant-Slug",
                         "in": "header",
                         "required": true
                     },
                     {
                         "type": "string",
-                        "format": "uuid",
-                        "description": "Plan ID",
-                        "name": "planID",
-                        "in": "path",
-                        "required": true
+                        "description": "Valid from date (format: YYYY-MM-DDThh:mm:ssZ)",
+                        "name": "valid_from",
+                        "in": "query"
                     },
                     {
                         "type": "string",
-                        "format": "uuid",
-                        "description": "Feature ID",
-                        "name": "featureID",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "204": {
-                        "description": "Plan feature deleted successfully"
-                    },
-                    "400": {
-                        "description": "Invalid request",
-                        "schema": {
-                            "$ref": "#/definitions/errors.ErrorResponse"
-                        }
-                    },
-                    "401": {
-                        "description": "Unauthorized",
-                        "schema": {
-                            "$ref": "#/definitions/errors.ErrorResponse"
-                        }
-                    },
-                    "404": {
-                        "description": "Plan feature not found",
-                        "schema": {
-                            "$ref": "#/definitions/errors.ErrorResponse"
-                        }
+                        "description": "Valid until date (format: YYYY-MM-DDThh:mm:ssZ)",
+                        "name": "valid_until",
+                        "in": "query"
                     },
-                    "500": {
-                        "description": "Internal server error",
-                        "schema": {
-                            "$ref": "#/definitions/errors.ErrorResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/v1/plans/{planID}/features/{featureID}/quotas": {
-            "get": {
-                "description": "Retrieves quota configuration for a specific plan feature",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Plan Feature Quotas"
-                ],
-                "summary": "Get plan feature quota details",
-                "parameters": [
                     {
                         "type": "string",
-                        "description": "Tenant slug",
-                        "name": "X-Tenant",
-                        "in": "header",
-                        "required": true
+                        "description": "Plan ID or slug",
+                        "name": "plan_id_or_slug",
+                        "in": "query"
                     },
                     {
                         "type": "string",
-                        "format": "uuid",
-                        "description": "Plan ID",
-                        "name": "planID",
-                        "in": "path",
-                        "required": true
+                        "description": "Organization ID",
+                        "name": "org_id",
+                        "in": "query"
                     },
                     {
                         "type": "string",
-                        "format": "uuid",
-                        "description": "Feature ID",
-                        "name": "featureID",
-                        "in": "path",
-                        "required": true
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Items per page",
+                        "name": "limit",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Quota details retrieved successfully",
+                        "description": "Assignments retrieved successfully",
                         "schema": {
-                            "$ref": "#/definitions/models.HttpResponse-models_PlanFeatureQuota"
+                            "$ref": "#/definitions/models.HttpResponse-pagination_PaginationView-models_PlanAssignment"
                         }
                     },
                     "400": {
@@ -1743,18 +1595,6 @@ const docTemplate = `{
                             "$ref": "#/definitions/errors.ErrorResponse"
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
-                        "schema": {
-                            "$ref": "#/definitions/errors.ErrorResponse"
-                        }
-                    },
-                    "404": {
-                        "description": "Quota not found",
-                        "schema": {
-                            "$ref": "#/definitions/errors.ErrorResponse"
-                        }
-                    },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
@@ -1764,7 +1604,7 @@ const docTemplate = `{
                 }
             },
             "put": {
-                "description": "Updates the quota configuration for a plan feature",
+                "description": "Update the validity period for a plan assignment",
                 "consumes": [
                     "application/json"
                 ],
@@ -1772,48 +1612,32 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Plan Feature Quotas"
+                    "plan-assignments"
                 ],
-                "summary": "Update plan feature quota",
+                "summary": "Update a plan assignment",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Tenant slug",
-                        "name": "X-Tenant",
+                        "description": "Tenant Slug",
+                        "name": "X-Tenant-Slug",
                         "in": "header",
                         "required": true
                     },
                     {
-                        "type": "string",
-                        "format": "uuid",
-                        "description": "Plan ID",
-                        "name": "planID",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "format": "uuid",
-                        "description": "Feature ID",
-                        "name": "featureID",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Updated quota configuration",
-                        "name": "body",
+                        "description": "Assignment update information",
+                        "name": "assignment",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/quotas.updatePlanFeatureQuotaRequest"
+                            "$ref": "#/definitions/assignments.updateAssignedPlanRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Quota updated successfully",
+                        "description": "Assignment updated successfully",
                         "schema": {
-                            "$ref": "#/definitions/models.HttpResponse-models_PlanFeatureQuota"
+                            "$ref": "#/definitions/models.HttpResponse-models_PlanAssignment"
                         }
                     },
                     "400": {
@@ -1822,14 +1646,8 @@ const docTemplate = `{
                             "$ref": "#/definitions/errors.ErrorResponse"
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
-                        "schema": {
-                            "$ref": "#/definitions/errors.ErrorResponse"
-                        }
-                    },
                     "404": {
-                        "description": "Quota not found",
+                        "description": "Plan assignment not found",
                         "schema": {
                             "$ref": "#/definitions/errors.ErrorResponse"
                         }
@@ -1843,7 +1661,7 @@ const docTemplate = `{
                 }
             },
             "post": {
-                "description": "Adds a quota configuration to a plan feature",
+                "description": "Assign a plan to either an organization or a user. The assignment is pinned to plan_version when given; otherwise a renewal keeps the subject's previous version (or the version it was scheduled to migrate to) and new subscribers get the latest published version. A subject holds at most one base plan at a time, while add-on plans stack on top of it. A plan is refused when one of its quotas resets over another period than the quota of the same feature in the plans held with it: the base plan for an add-on, the add-ons for a base plan.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1851,48 +1669,32 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Plan Feature Quotas"
+                    "plan-assignments"
                 ],
-                "summary": "Create plan feature quota",
+                "summary": "Create a new plan assignment",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Tenant slug",
-                        "name": "X-Tenant",
+                        "description": "Tenant Slug",
+                        "name": "X-Tenant-Slug",
                         "in": "header",
                         "required": true
                     },
                     {
-                        "type": "string",
-                        "format": "uuid",
-                        "description": "Plan ID",
-                        "name": "planID",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "format": "uuid",
-                        "description": "Feature ID",
-                        "name": "featureID",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Quota configuration",
-                        "name": "body",
+                        "description": "Assignment information",
+                        "name": "assignment",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/quotas.createQuotaRequest"
+                            "$ref": "#/definitions/assignments.createAssignmentRequest"
                         }
                     }
                 ],
                 "responses": {
                     "201": {
-                        "description": "Quota created successfully",
+                        "description": "Plan assignment created successfully",
                         "schema": {
-                            "$ref": "#/definitions/models.HttpResponse-models_PlanFeatureQuota"
+                            "$ref": "#/definitions/models.HttpResponse-models_PlanAssignment"
                         }
                     },
                     "400": {
@@ -1901,14 +1703,14 @@ const docTemplate = `{
                             "$ref": "#/definitions/errors.ErrorResponse"
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "404": {
+                        "description": "Plan not found",
                         "schema": {
                             "$ref": "#/definitions/errors.ErrorResponse"
                         }
                     },
                     "409": {
-                        "description": "Quota already exists",
+                        "description": "Subject already holds a base plan over the period",
                         "schema": {
                             "$ref": "#/definitions/errors.ErrorResponse"
                         }
@@ -1922,7 +1724,7 @@ const docTemplate = `{
                 }
             },
             "delete": {
-                "description": "Removes the quota configuration from a plan feature",
+                "description": "Terminate a plan assignment for an organization or user",
                 "consumes": [
                     "application/json"
                 ],
@@ -1930,37 +1732,110 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Plan Feature Quotas"
+                    "plan-assignments"
                 ],
-                "summary": "Delete plan feature quota",
+                "summary": "Delete a plan assignment",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Tenant slug",
-                        "name": "X-Tenant",
+                        "description": "Tenant Slug",
+                        "name": "X-Tenant-Slug",
                         "in": "header",
                         "required": true
                     },
+                    {
+                        "description": "Assignment termination information",
+                        "name": "assignment",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/assignments.terminatePlanRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Plan assignment not found",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/plans/assignments/changes": {
+            "get": {
+                "description": "Get the scheduled plan changes of the tenant, latest effective first, optionally for one organization or user and by status: pending until they take effect, applied afterwards, or canceled.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "plan-assignments"
+                ],
+                "summary": "List plan changes",
+                "parameters": [
                     {
                         "type": "string",
-                        "format": "uuid",
-                        "description": "Plan ID",
-                        "name": "planID",
-                        "in": "path",
+                        "description": "Tenant Slug",
+                        "name": "X-Tenant-Slug",
+                        "in": "header",
                         "required": true
                     },
                     {
                         "type": "string",
-                        "format": "uuid",
-                        "description": "Feature ID",
-                        "name": "featureID",
-                        "in": "path",
-                        "required": true
+                        "description": "Organization ID",
+                        "name": "organization_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Status (pending/applied/canceled)",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Items per page",
+                        "name": "limit",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "Quota deleted successfully"
+                    "200": {
+                        "description": "Plan changes retrieved successfully",
+                        "schema": {
+                            "$ref": "#/definitions/models.HttpResponse-array_models_PlanChange"
+                        }
                     },
                     "400": {
                         "description": "Invalid request",
@@ -1968,14 +1843,65 @@ const docTemplate = `{
                             "$ref": "#/definitions/errors.ErrorResponse"
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Switch an organization or user from one of its active plans to another (upgrade or downgrade), or cancel the plan, at effective_at, at the end of the current calendar period (a month unless period is set) with at_period_end, or right away when neither is given. The current assignment ends when the change takes effect and the assignment of the new plan starts then, keeping the end the current one had; both show in the assignment history. Plans can only be switched for plans of the same kind, a new base plan cannot overlap another base plan of the subject, and an assignment has at most one pending change.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "plan-assignments"
+                ],
+                "summary": "Schedule a plan change",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tenant Slug",
+                        "name": "X-Tenant-Slug",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Plan change information",
+                        "name": "change",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/assignments.schedulePlanChangeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Plan change scheduled successfully",
+                        "schema": {
+                            "$ref": "#/definitions/models.HttpResponse-models_PlanChange"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
                         "schema": {
                             "$ref": "#/definitions/errors.ErrorResponse"
                         }
                     },
                     "404": {
-                        "description": "Quota not found",
+                        "description": "Plan or active assignment not found",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "The assignment already has a pending change or the new base plan overlaps another one",
                         "schema": {
                             "$ref": "#/definitions/errors.ErrorResponse"
                         }
@@ -1988,70 +1914,4860 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "assignments.createAssignmentRequest": {
-            "type": "object",
-            "required": [
-                "created_by",
-                "plan_id_or_slug",
-                "valid_from"
-            ],
-            "properties": {
-                "created_by": {
-                    "type": "string"
-                },
-                "organization_id": {
-                    "type": "string"
-                },
-                "plan_id_or_slug": {
-                    "type": "string"
-                },
-                "user_id": {
-                    "type": "string"
-                },
-                "valid_from": {
-                    "type": "string"
-                },
-                "valid_until": {
-                    "type": "string"
-                }
-            }
         },
-        "assignments.terminatePlanRequest": {
-            "type": "object",
-            "required": [
-                "plan_id_or_slug"
-            ],
-            "properties": {
-                "organization_id": {
-                    "type": "string"
-                },
-                "plan_id_or_slug": {
-                    "type": "string"
-                },
-                "user_id": {
-                    "type": "string"
+        "/v1/plans/assignments/changes/{id}": {
+            "get": {
+                "description": "Get a scheduled plan change by ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "plan-assignments"
+                ],
+                "summary": "Get a plan change",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tenant Slug",
+                        "name": "X-Tenant-Slug",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Plan change ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Plan change retrieved successfully",
+                        "schema": {
+                            "$ref": "#/definitions/models.HttpResponse-models_PlanChange"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Plan change not found",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    }
                 }
             }
         },
-        "assignments.updateAssignedPlanRequest": {
-            "type": "object",
-            "required": [
-                "plan_id_or_slug",
-                "updated_by"
-            ],
-            "properties": {
-                "organization_id": {
-                    "type": "string"
-                },
-                "plan_id_or_slug": {
+        "/v1/plans/assignments/changes/{id}/cancel": {
+            "post": {
+                "description": "Cancel a pending plan change: the assignment of the new plan is removed and the current assignment gets back the end it had before the change was scheduled. Changes that already took effect cannot be canceled.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "plan-assignments"
+                ],
+                "summary": "Cancel a plan change",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tenant Slug",
+                        "name": "X-Tenant-Slug",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Plan change ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Cancellation information",
+                        "name": "cancel",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/assignments.cancelPlanChangeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Plan change canceled successfully",
+                        "schema": {
+                            "$ref": "#/definitions/models.HttpResponse-models_PlanChange"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Plan change not found",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Plan change is no longer pending",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/plans/assignments/history": {
+            "get": {
+                "description": "Get historical records of plan assignments with optional filtering",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "plan-assignments"
+                ],
+                "summary": "List plan assignment history",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tenant Slug",
+                        "name": "X-Tenant-Slug",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Valid from before date (format: YYYY-MM-DDThh:mm:ssZ)",
+                        "name": "valid_from_before",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Valid from after date (format: YYYY-MM-DDThh:mm:ssZ)",
+                        "name": "valid_from_after",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Valid until before date (format: YYYY-MM-DDThh:mm:ssZ)",
+                        "name": "valid_until_before",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Valid until after date (format: YYYY-MM-DDThh:mm:ssZ)",
+                        "name": "valid_until_after",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Action type (CREATE, UPDATE, DELETE, TRIAL_CONVERTED, TRIAL_FELL_BACK, TRIAL_EXPIRED)",
+                        "name": "action",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Plan ID or slug",
+                        "name": "plan_id_or_slug",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Organization ID",
+                        "name": "org_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Items per page",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Assignment history retrieved successfully",
+                        "schema": {
+                            "$ref": "#/definitions/models.HttpResponse-pagination_PaginationView-models_PlanAssignmentHistory"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/plans/assignments/trials": {
+            "get": {
+                "description": "Get the trials of the tenant, ending soonest first, with the days they have left, optionally for one organization or user and by status (active, converted, fell_back or expired).",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "plan-assignments"
+                ],
+                "summary": "List trials",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tenant Slug",
+                        "name": "X-Tenant-Slug",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Organization ID",
+                        "name": "organization_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Status (active/converted/fell_back/expired)",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Items per page",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Trials retrieved successfully",
+                        "schema": {
+                            "$ref": "#/definitions/models.HttpResponse-array_models_Trial"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Assign a plan to an organization or user as a trial lasting trial_days from valid_from, or from now when it is not given. When the trial ends the subject is converted to the target plan (end_action convert, the default), moved onto the fallback plan (fallback), or left without a plan (expire). Conversions move onto the fallback plan when the target plan can no longer be assigned, and trials expire when neither can. The plan the trial ends on starts when the trial ends and is open-ended; the outcome is recorded in the assignment history and sent as a trial.ended notification.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "plan-assignments"
+                ],
+                "summary": "Start a trial",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tenant Slug",
+                        "name": "X-Tenant-Slug",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Trial information",
+                        "name": "trial",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/assignments.createTrialRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Trial started successfully",
+                        "schema": {
+                            "$ref": "#/definitions/models.HttpResponse-models_Trial"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Subject already holds a base plan over the trial",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/plans/assignments/trials/{id}": {
+            "get": {
+                "description": "Get a trial by ID with the days it has left",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "plan-assignments"
+                ],
+                "summary": "Get a trial",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tenant Slug",
+                        "name": "X-Tenant-Slug",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Trial ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Trial retrieved successfully",
+                        "schema": {
+                            "$ref": "#/definitions/models.HttpResponse-models_Trial"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Trial not found",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/plans/assignments/trials/{id}/extend": {
+            "post": {
+                "description": "Move the end of an active trial the given number of days further. Trials that already ended cannot be extended.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "plan-assignments"
+                ],
+                "summary": "Extend a trial",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tenant Slug",
+                        "name": "X-Tenant-Slug",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Trial ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Extension information",
+                        "name": "extension",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/assignments.extendTrialRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Trial extended successfully",
+                        "schema": {
+                            "$ref": "#/definitions/models.HttpResponse-models_Trial"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Trial not found",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Trial is no longer active or the extension overlaps another base plan",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/plans/{idOrSlug}": {
+            "get": {
+                "description": "Get detailed information about a plan by ID or slug",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "plans"
+                ],
+                "summary": "Get plan details",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tenant Slug",
+                        "name": "X-Tenant-Slug",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Plan ID or slug",
+                        "name": "idOrSlug",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Plan retrieved successfully",
+                        "schema": {
+                            "$ref": "#/definitions/models.HttpResponse-models_Plan"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Plan not found",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Update a plan's details by ID or slug",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "plans"
+                ],
+                "summary": "Update a plan",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tenant Slug",
+                        "name": "X-Tenant-Slug",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Plan ID or slug",
+                        "name": "idOrSlug",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated plan information",
+                        "name": "plan",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/plans.updatePlanRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Plan updated successfully",
+                        "schema": {
+                            "$ref": "#/definitions/models.HttpResponse-models_Plan"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Plan not found",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete a plan by ID or slug",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "plans"
+                ],
+                "summary": "Delete a plan",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tenant Slug",
+                        "name": "X-Tenant-Slug",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Plan ID or slug",
+                        "name": "idOrSlug",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Plan deleted successfully"
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/plans/{idOrSlug}/archive": {
+            "put": {
+                "description": "Archive or unarchive a plan by ID or slug",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "plans"
+                ],
+                "summary": "Archive or unarchive a plan",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tenant Slug",
+                        "name": "X-Tenant-Slug",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Plan ID or slug",
+                        "name": "idOrSlug",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Archive request information",
+                        "name": "archiveRequest",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/plans.archivePlanRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Plan archived/unarchived successfully"
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Plan not found",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/plans/{idOrSlug}/clone": {
+            "post": {
+                "description": "Copy a plan with its features, their configs and quotas into a new slug in one transaction. Name, type and description default to those of the source plan, and quota_limits overrides quota limits by feature slug. The clone is not published.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "plans"
+                ],
+                "summary": "Clone a plan",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tenant Slug",
+                        "name": "X-Tenant-Slug",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Plan ID or slug",
+                        "name": "idOrSlug",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Clone information",
+                        "name": "plan",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/plans.clonePlanRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Plan cloned successfully",
+                        "schema": {
+                            "$ref": "#/definitions/models.HttpResponse-models_Plan"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Plan not found",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Plan already exists",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/plans/{idOrSlug}/versions": {
+            "get": {
+                "description": "List the published versions of a plan, newest first",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "plans"
+                ],
+                "summary": "List plan versions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tenant Slug",
+                        "name": "X-Tenant-Slug",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Plan ID or slug",
+                        "name": "idOrSlug",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Plan versions retrieved successfully",
+                        "schema": {
+                            "$ref": "#/definitions/models.HttpResponse-array_models_PlanVersion"
+                        }
+                    },
+                    "404": {
+                        "description": "Plan not found",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Publish the current features and quotas of a plan as its next immutable version",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "plans"
+                ],
+                "summary": "Publish a plan version",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tenant Slug",
+                        "name": "X-Tenant-Slug",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Plan ID or slug",
+                        "name": "idOrSlug",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Version information",
+                        "name": "version",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/plans.publishPlanVersionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Plan version published successfully",
+                        "schema": {
+                            "$ref": "#/definitions/models.HttpResponse-models_PlanVersion"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Plan not found",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/plans/{idOrSlug}/versions/{version}": {
+            "get": {
+                "description": "Get a published version of a plan together with its features",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "plans"
+                ],
+                "summary": "Get plan version details",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tenant Slug",
+                        "name": "X-Tenant-Slug",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Plan ID or slug",
+                        "name": "idOrSlug",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Version number",
+                        "name": "version",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Plan version retrieved successfully",
+                        "schema": {
+                            "$ref": "#/definitions/models.HttpResponse-models_PlanVersion"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Plan version not found",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/plans/{idOrSlug}/versions/{version}/migrate": {
+            "post": {
+                "description": "Move the current and future assignments of a plan to one of its versions, either right away or when each assignment is renewed. Without organization_ids or user_ids every assignment of the plan is migrated. The migration is refused when a quota of the version resets over another period than the quota of the same feature in a plan held with a migrated assignment.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "plans"
+                ],
+                "summary": "Migrate assignments to a plan version",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tenant Slug",
+                        "name": "X-Tenant-Slug",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Plan ID or slug",
+                        "name": "idOrSlug",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Target version number",
+                        "name": "version",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Assignments to migrate",
+                        "name": "migration",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/plans.migratePlanVersionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Assignments migrated successfully",
+                        "schema": {
+                            "$ref": "#/definitions/models.HttpResponse-models_PlanVersionMigration"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Plan version not found",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/plans/{planID}/features": {
+            "get": {
+                "description": "Retrieves all features in the draft of a plan",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Plan Features"
+                ],
+                "summary": "List plan features",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tenant slug",
+                        "name": "X-Tenant",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Plan ID",
+                        "name": "planID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by feature type",
+                        "name": "feature_type",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List of plan features",
+                        "schema": {
+                            "$ref": "#/definitions/models.HttpResponse-array_models_PlanFeature"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Adds a new feature to the draft of a plan. Assignments see it once a version is published and they are migrated to it",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Plan Features"
+                ],
+                "summary": "Create plan feature",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tenant slug",
+                        "name": "X-Tenant",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Plan ID",
+                        "name": "planID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Plan feature details",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/planfeatures.createPlanFeatureRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Plan feature created successfully",
+                        "schema": {
+                            "$ref": "#/definitions/models.HttpResponse-models_PlanFeature"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Plan feature already exists",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/plans/{planID}/features/{featureID}": {
+            "put": {
+                "description": "Updates configuration of a feature in the draft of a plan. Published versions are unaffected",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Plan Features"
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tenant slug",
+                        "name": "X-Tenant",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Plan ID",
+                        "name": "planID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Feature ID",
+                        "name": "featureID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated plan feature details",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/planfeatures.updatePlanFeatureRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Plan feature updated successfully",
+                        "schema": {
+                            "$ref": "#/definitions/models.HttpResponse-models_PlanFeature"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Plan feature not found",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Removes a feature from the draft of a plan. Published versions are unaffected",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Plan Features"
+                ],
+                "summary": "Delete plan feature",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tenant slug",
+                        "name": "X-Tenant",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Plan ID",
+                        "name": "planID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Feature ID",
+                        "name": "featureID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Plan feature deleted successfully"
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Plan feature not found",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/plans/{planID}/features/{featureID}/quotas": {
+            "get": {
+                "description": "Retrieves quota configuration for a specific plan feature in the draft of a plan",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Plan Feature Quotas"
+                ],
+                "summary": "Get plan feature quota details",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tenant slug",
+                        "name": "X-Tenant",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Plan ID",
+                        "name": "planID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Feature ID",
+                        "name": "featureID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Quota details retrieved successfully",
+                        "schema": {
+                            "$ref": "#/definitions/models.HttpResponse-models_PlanFeatureQuota"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Quota not found",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Updates the quota configuration for a plan feature in the draft of a plan. Published versions are unaffected. The soft limit must stay below the limit and an overage allowance needs a type. The soft limit, the overage allowance and the grace period are removed with their clear flags",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Plan Feature Quotas"
+                ],
+                "summary": "Update plan feature quota",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tenant slug",
+                        "name": "X-Tenant",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Plan ID",
+                        "name": "planID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Feature ID",
+                        "name": "featureID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated quota configuration",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/quotas.updatePlanFeatureQuotaRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Quota updated successfully",
+                        "schema": {
+                            "$ref": "#/definitions/models.HttpResponse-models_PlanFeatureQuota"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Quota not found",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Adds a quota configuration to a plan feature in the draft of a plan. A soft limit warns subjects before the limit, an overage allowance, absolute or a percentage of the limit, lets usage go past the limit and a grace period delays the action at limit after the allowance is first used up",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Plan Feature Quotas"
+                ],
+                "summary": "Create plan feature quota",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tenant slug",
+                        "name": "X-Tenant",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Plan ID",
+                        "name": "planID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Feature ID",
+                        "name": "featureID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Quota configuration",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/quotas.createQuotaRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Quota created successfully",
+                        "schema": {
+                            "$ref": "#/definitions/models.HttpResponse-models_PlanFeatureQuota"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Quota already exists",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Removes the quota configuration from a plan feature in the draft of a plan",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Plan Feature Quotas"
+                ],
+                "summary": "Delete plan feature quota",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tenant slug",
+                        "name": "X-Tenant",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Plan ID",
+                        "name": "planID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Feature ID",
+                        "name": "featureID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Quota deleted successfully"
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Quota not found",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/subjects/{subject}/entitlements": {
+            "get": {
+                "description": "List the features an organization or user is entitled to, merged across its active base plan and the add-ons stacked on top of it. Quota limits are summed or maxed by the merge rule of each feature, a plan granting a feature without a quota makes it unlimited, and each feature lists the plans granting it. Active entitlement overrides of the subject are applied on top and listed with the actor who last changed them.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subjects"
+                ],
+                "summary": "Get subject entitlements",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tenant Slug",
+                        "name": "X-Tenant-Slug",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Organization or user ID",
+                        "name": "subject",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "organization",
+                        "description": "Subject type (organization/user)",
+                        "name": "type",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Subject entitlements retrieved successfully",
+                        "schema": {
+                            "$ref": "#/definitions/models.HttpResponse-models_SubjectEntitlements"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Subject has no active plan",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/subjects/{subject}/overrides": {
+            "get": {
+                "description": "Get the entitlement overrides of an organization or user, newest first. Expired overrides are left out unless include_expired is set.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subjects"
+                ],
+                "summary": "List entitlement overrides",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tenant Slug",
+                        "name": "X-Tenant-Slug",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Organization or user ID",
+                        "name": "subject",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "organization",
+                        "description": "Subject type (organization/user)",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Include expired overrides",
+                        "name": "include_expired",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Items per page",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Entitlement overrides retrieved successfully",
+                        "schema": {
+                            "$ref": "#/definitions/models.HttpResponse-array_models_EntitlementOverride"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Grant or revoke a feature for a single organization or user on top of its plans, optionally attached to one of its assignments and expiring at a given time. A grant replaces the limit, reset period and action at limit of the merged quota when set, dropping a soft limit that is not below its limit; a grant of a feature the plans do not offer is unlimited unless it sets a limit.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subjects"
+                ],
+                "summary": "Create an entitlement override",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tenant Slug",
+                        "name": "X-Tenant-Slug",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Organization or user ID",
+                        "name": "subject",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "organization",
+                        "description": "Subject type (organization/user)",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Entitlement override data",
+                        "name": "override",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/subjects.createOverrideRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Entitlement override created successfully",
+                        "schema": {
+                            "$ref": "#/definitions/models.HttpResponse-models_EntitlementOverride"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Feature or assignment not found",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/subjects/{subject}/overrides/{id}": {
+            "get": {
+                "description": "Get an entitlement override of an organization or user by ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subjects"
+                ],
+                "summary": "Get an entitlement override",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tenant Slug",
+                        "name": "X-Tenant-Slug",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Organization or user ID",
+                        "name": "subject",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Entitlement override ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "organization",
+                        "description": "Subject type (organization/user)",
+                        "name": "type",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Entitlement override retrieved successfully",
+                        "schema": {
+                            "$ref": "#/definitions/models.HttpResponse-models_EntitlementOverride"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Entitlement override not found",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Update the limit, reset period, action at limit, reason or expiry of an entitlement override. Revoke overrides only accept a reason and an expiry.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subjects"
+                ],
+                "summary": "Update an entitlement override",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tenant Slug",
+                        "name": "X-Tenant-Slug",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Organization or user ID",
+                        "name": "subject",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Entitlement override ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "organization",
+                        "description": "Subject type (organization/user)",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Entitlement override update data",
+                        "name": "override",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/subjects.updateOverrideRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Entitlement override updated successfully",
+                        "schema": {
+                            "$ref": "#/definitions/models.HttpResponse-models_EntitlementOverride"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Entitlement override not found",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete an entitlement override, returning the subject to the entitlements of its plans",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subjects"
+                ],
+                "summary": "Delete an entitlement override",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tenant Slug",
+                        "name": "X-Tenant-Slug",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Organization or user ID",
+                        "name": "subject",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Entitlement override ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "organization",
+                        "description": "Subject type (organization/user)",
+                        "name": "type",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Entitlement override deleted successfully"
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Entitlement override not found",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/subjects/{subject}/usage": {
+            "get": {
+                "description": "Summarize the usage of an organization or user for every metered feature on its active base plan and add-ons, with quotas merged by the merge rule of each feature. Each feature sums the meters it is linked to over the current reset period of its quota and reports the limit and the percentage used.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subjects"
+                ],
+                "summary": "Get subject usage",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tenant Slug",
+                        "name": "X-Tenant-Slug",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Organization or user ID",
+                        "name": "subject",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "organization",
+                        "description": "Subject type (organization/user)",
+                        "name": "type",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Subject usage retrieved successfully",
+                        "schema": {
+                            "$ref": "#/definitions/models.HttpResponse-models_SubjectUsage"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Subject has no active plan",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/webhooks": {
+            "get": {
+                "description": "Get a list of webhook endpoints for the tenant",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "List webhook endpoints",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tenant Slug",
+                        "name": "X-Tenant-Slug",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Items per page",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Webhook endpoints retrieved successfully",
+                        "schema": {
+                            "$ref": "#/definitions/models.HttpResponse-array_models_WebhookEndpoint"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Register an endpoint receiving the notifications of the subscribed event types: meter.created, plan.archived, assignment.created, quota.threshold_reached, meter.threshold_reached and trial.ended. Deliveries are signed with the secret of the endpoint, which is generated when none is given and is only returned in this response: the X-Metering-Signature header is v1= followed by the hex encoded HMAC-SHA256 of the X-Metering-Timestamp header, a dot and the request body.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Create a webhook endpoint",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tenant Slug",
+                        "name": "X-Tenant-Slug",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Webhook endpoint creation data",
+                        "name": "endpoint",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/webhooks.createWebhookEndpointRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Webhook endpoint created successfully",
+                        "schema": {
+                            "$ref": "#/definitions/models.HttpResponse-models_WebhookEndpointWithSecret"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/webhooks/{id}": {
+            "get": {
+                "description": "Get a webhook endpoint by ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Get a webhook endpoint",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tenant Slug",
+                        "name": "X-Tenant-Slug",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Webhook endpoint ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Webhook endpoint retrieved successfully",
+                        "schema": {
+                            "$ref": "#/definitions/models.HttpResponse-models_WebhookEndpoint"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Webhook endpoint not found",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Update the URL, description, secret, subscribed event types or enabled state of a webhook endpoint. Deliveries to a disabled endpoint fail until they are redelivered. The secret is only returned when it is rotated by this request.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Update a webhook endpoint",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tenant Slug",
+                        "name": "X-Tenant-Slug",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Webhook endpoint ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Webhook endpoint update data",
+                        "name": "endpoint",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/webhooks.updateWebhookEndpointRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Webhook endpoint updated successfully",
+                        "schema": {
+                            "$ref": "#/definitions/models.HttpResponse-models_WebhookEndpoint"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Webhook endpoint not found",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete a webhook endpoint with its deliveries",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Delete a webhook endpoint",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tenant Slug",
+                        "name": "X-Tenant-Slug",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Webhook endpoint ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Webhook endpoint deleted successfully"
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/webhooks/{id}/deliveries": {
+            "get": {
+                "description": "Get the deliveries queued for a webhook endpoint, most recent first",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "List webhook deliveries",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tenant Slug",
+                        "name": "X-Tenant-Slug",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Webhook endpoint ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Items per page",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Webhook deliveries retrieved successfully",
+                        "schema": {
+                            "$ref": "#/definitions/models.HttpResponse-array_models_WebhookDelivery"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Webhook endpoint not found",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/webhooks/{id}/deliveries/{deliveryId}": {
+            "get": {
+                "description": "Get a delivery of a webhook endpoint with the log of its attempts",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Get a webhook delivery",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tenant Slug",
+                        "name": "X-Tenant-Slug",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Webhook endpoint ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Webhook delivery ID",
+                        "name": "deliveryId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Webhook delivery retrieved successfully",
+                        "schema": {
+                            "$ref": "#/definitions/models.HttpResponse-models_WebhookDelivery"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Webhook delivery not found",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/webhooks/{id}/deliveries/{deliveryId}/redeliver": {
+            "post": {
+                "description": "Queue a delivery to be sent again on the next dispatch, whatever its status. A delivery that already used all its attempts gets a single further attempt.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Redeliver a webhook delivery",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tenant Slug",
+                        "name": "X-Tenant-Slug",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Webhook endpoint ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Webhook delivery ID",
+                        "name": "deliveryId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Webhook delivery queued successfully",
+                        "schema": {
+                            "$ref": "#/definitions/models.HttpResponse-models_WebhookDelivery"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Webhook delivery not found",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "alerts.createAlertRuleRequest": {
+            "type": "object",
+            "required": [
+                "created_by",
+                "name",
+                "subject_ids",
+                "threshold",
+                "threshold_type"
+            ],
+            "properties": {
+                "created_by": {
+                    "type": "string"
+                },
+                "enabled": {
+                    "type": "boolean"
+                },
+                "meter_slug": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "minLength": 3
+                },
+                "plan_feature_id": {
+                    "type": "string"
+                },
+                "subject_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "subject_type": {
+                    "type": "string",
+                    "enum": [
+                        "organization",
+                        "user"
+                    ]
+                },
+                "threshold": {
+                    "type": "number"
+                },
+                "threshold_type": {
+                    "type": "string",
+                    "enum": [
+                        "absolute",
+                        "percent"
+                    ]
+                },
+                "window_minutes": {
+                    "type": "integer"
+                }
+            }
+        },
+        "alerts.updateAlertRuleRequest": {
+            "type": "object",
+            "required": [
+                "subject_ids",
+                "updated_by"
+            ],
+            "properties": {
+                "enabled": {
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "minLength": 3
+                },
+                "subject_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "threshold": {
+                    "type": "number"
+                },
+                "updated_by": {
+                    "type": "string"
+                },
+                "window_minutes": {
+                    "type": "integer"
+                }
+            }
+        },
+        "assignments.cancelPlanChangeRequest": {
+            "type": "object",
+            "required": [
+                "canceled_by"
+            ],
+            "properties": {
+                "canceled_by": {
+                    "type": "string"
+                }
+            }
+        },
+        "assignments.createAssignmentRequest": {
+            "type": "object",
+            "required": [
+                "created_by",
+                "plan_id_or_slug",
+                "valid_from"
+            ],
+            "properties": {
+                "created_by": {
+                    "type": "string"
+                },
+                "organization_id": {
+                    "type": "string"
+                },
+                "plan_id_or_slug": {
+                    "type": "string"
+                },
+                "plan_version": {
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "user_id": {
+                    "type": "string"
+                },
+                "valid_from": {
+                    "type": "string"
+                },
+                "valid_until": {
+                    "type": "string"
+                }
+            }
+        },
+        "assignments.createTrialRequest": {
+            "type": "object",
+            "required": [
+                "created_by",
+                "plan_id_or_slug",
+                "target_plan_id_or_slug",
+                "trial_days"
+            ],
+            "properties": {
+                "created_by": {
+                    "type": "string"
+                },
+                "end_action": {
+                    "type": "string",
+                    "enum": [
+                        "convert",
+                        "fallback",
+                        "expire"
+                    ]
+                },
+                "fallback_plan_id_or_slug": {
+                    "type": "string"
+                },
+                "organization_id": {
+                    "type": "string"
+                },
+                "plan_id_or_slug": {
+                    "type": "string"
+                },
+                "plan_version": {
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "target_plan_id_or_slug": {
+                    "type": "string"
+                },
+                "trial_days": {
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "user_id": {
+                    "type": "string"
+                },
+                "valid_from": {
+                    "type": "string"
+                }
+            }
+        },
+        "assignments.extendTrialRequest": {
+            "type": "object",
+            "required": [
+                "days",
+                "updated_by"
+            ],
+            "properties": {
+                "days": {
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "updated_by": {
+                    "type": "string"
+                }
+            }
+        },
+        "assignments.schedulePlanChangeRequest": {
+            "type": "object",
+            "required": [
+                "created_by",
+                "plan_id_or_slug",
+                "type"
+            ],
+            "properties": {
+                "at_period_end": {
+                    "type": "boolean"
+                },
+                "created_by": {
+                    "type": "string"
+                },
+                "effective_at": {
+                    "type": "string"
+                },
+                "organization_id": {
+                    "type": "string"
+                },
+                "period": {
+                    "type": "string",
+                    "enum": [
+                        "day",
+                        "week",
+                        "month",
+                        "year"
+                    ]
+                },
+                "plan_id_or_slug": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "to_plan_id_or_slug": {
+                    "type": "string"
+                },
+                "to_plan_version": {
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "type": {
+                    "type": "string",
+                    "enum": [
+                        "upgrade",
+                        "downgrade",
+                        "cancellation"
+                    ]
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "assignments.terminatePlanRequest": {
+            "type": "object",
+            "required": [
+                "plan_id_or_slug"
+            ],
+            "properties": {
+                "organization_id": {
+                    "type": "string"
+                },
+                "plan_id_or_slug": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "assignments.updateAssignedPlanRequest": {
+            "type": "object",
+            "required": [
+                "plan_id_or_slug",
+                "updated_by"
+            ],
+            "properties": {
+                "organization_id": {
+                    "type": "string"
+                },
+                "plan_id_or_slug": {
+                    "type": "string"
+                },
+                "set_valid_until_to_zero": {
+                    "type": "boolean"
+                },
+                "updated_by": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                },
+                "valid_from": {
+                    "type": "string"
+                },
+                "valid_until": {
+                    "type": "string"
+                }
+            }
+        },
+        "errors.ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "details": {
+                    "type": "object",
+                    "additionalProperties": {}
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "events.event": {
+            "type": "object",
+            "required": [
+                "organization",
+                "type",
+                "user"
+            ],
+            "properties": {
+                "id": {
+                    "type": "string"
+                },
+                "organization": {
+                    "type": "string"
+                },
+                "properties": {
+                    "type": "object",
+                    "additionalProperties": {}
+                },
+                "source": {
+                    "type": "string"
+                },
+                "timestamp": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                },
+                "user": {
+                    "type": "string"
+                }
+            }
+        },
+        "events.publishEventRequestBody": {
+            "type": "object",
+            "required": [
+                "events"
+            ],
+            "properties": {
+                "allow_partial_success": {
+                    "type": "boolean"
+                },
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/events.event"
+                    }
+                }
+            }
+        },
+        "features.createFeatureRequest": {
+            "type": "object",
+            "required": [
+                "created_by",
+                "meter_slugs",
+                "name",
+                "slug",
+                "type"
+            ],
+            "properties": {
+                "config": {
+                    "type": "object",
+                    "additionalProperties": {}
+                },
+                "created_by": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "merge_rule": {
+                    "type": "string",
+                    "enum": [
+                        "sum",
+                        "max"
+                    ]
+                },
+                "meter_slugs": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                },
+                "slug": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string",
+                    "enum": [
+                        "static",
+                        "metered"
+                    ]
+                }
+            }
+        },
+        "features.updateFeatureRequest": {
+            "type": "object",
+            "required": [
+                "meter_slugs",
+                "updated_by"
+            ],
+            "properties": {
+                "config": {
+                    "type": "object",
+                    "additionalProperties": {}
+                },
+                "description": {
+                    "type": "string",
+                    "maxLength": 255,
+                    "minLength": 10
+                },
+                "merge_rule": {
+                    "type": "string",
+                    "enum": [
+                        "sum",
+                        "max"
+                    ]
+                },
+                "meter_slugs": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "minLength": 3
+                },
+                "updated_by": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "minLength": 3
+                }
+            }
+        },
+        "meters.createMeterRequest": {
+            "type": "object",
+            "required": [
+                "created_by",
+                "event_types",
+                "name",
+                "slug"
+            ],
+            "properties": {
+                "aggregation": {
+                    "type": "string",
+                    "enum": [
+                        "count",
+                        "sum",
+                        "avg",
+                        "unique_count",
+                        "min",
+                        "max"
+                    ]
+                },
+                "created_by": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "event_type": {
+                    "type": "string"
+                },
+                "event_types": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "filter": {
+                    "$ref": "#/definitions/models.FilterExpression"
+                },
+                "formula": {
+                    "type": "string"
+                },
+                "granularity": {
+                    "type": "string",
+                    "enum": [
+                        "second",
+                        "minute",
+                        "hour",
+                        "day"
+                    ]
+                },
+                "name": {
+                    "type": "string"
+                },
+                "populate": {
+                    "type": "boolean"
+                },
+                "properties": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "slug": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string",
+                    "enum": [
+                        "standard",
+                        "derived"
+                    ]
+                },
+                "value_properties": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "value_property": {
+                    "type": "string"
+                }
+            }
+        },
+        "meters.exportMeterRequest": {
+            "type": "object",
+            "required": [
+                "meter_slug"
+            ],
+            "properties": {
+                "compare": {
+                    "$ref": "#/definitions/models.CompareMode"
+                },
+                "cumulative": {
+                    "type": "boolean"
+                },
+                "cursor": {
+                    "type": "string"
+                },
+                "fill": {
+                    "$ref": "#/definitions/models.FillMode"
+                },
+                "filter": {
+                    "$ref": "#/definitions/models.FilterExpression"
+                },
+                "filter_group_by": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        }
+                    }
+                },
+                "format": {
+                    "description": "Format takes precedence over the Accept header",
+                    "enum": [
+                        "csv",
+                        "ndjson",
+                        "parquet"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.ExportFormat"
+                        }
+                    ]
+                },
+                "from": {
+                    "type": "string"
+                },
+                "group_by": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "include_others": {
+                    "type": "boolean"
+                },
+                "limit": {
+                    "type": "integer",
+                    "maximum": 10000,
+                    "minimum": 1
+                },
+                "meter_slug": {
+                    "type": "string"
+                },
+                "offset": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "order_by": {
+                    "$ref": "#/definitions/models.QueryMeterOrderBy"
+                },
+                "to": {
+                    "type": "string"
+                },
+                "window_size": {
+                    "$ref": "#/definitions/models.WindowSize"
+                },
+                "window_time_zone": {
+                    "type": "string"
+                }
+            }
+        },
+        "meters.previewEvent": {
+            "type": "object",
+            "required": [
+                "organization",
+                "type",
+                "user"
+            ],
+            "properties": {
+                "organization": {
+                    "type": "string"
+                },
+                "properties": {
+                    "type": "object",
+                    "additionalProperties": {}
+                },
+                "timestamp": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                },
+                "user": {
+                    "type": "string"
+                }
+            }
+        },
+        "meters.previewMeterRequest": {
+            "type": "object",
+            "required": [
+                "aggregation",
+                "event_types",
+                "properties"
+            ],
+            "properties": {
+                "aggregation": {
+                    "type": "string",
+                    "enum": [
+                        "count",
+                        "sum",
+                        "avg",
+                        "unique_count",
+                        "min",
+                        "max"
+                    ]
+                },
+                "event_type": {
+                    "type": "string"
+                },
+                "event_types": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "events": {
+                    "description": "Events are sample events to preview the meter against; mutually exclusive with From and To",
+                    "type": "array",
+                    "maxItems": 1000,
+                    "items": {
+                        "$ref": "#/definitions/meters.previewEvent"
+                    }
+                },
+                "filter": {
+                    "$ref": "#/definitions/models.FilterExpression"
+                },
+                "from": {
+                    "type": "string"
+                },
+                "granularity": {
+                    "type": "string",
+                    "enum": [
+                        "second",
+                        "minute",
+                        "hour",
+                        "day"
+                    ]
+                },
+                "limit": {
+                    "type": "integer",
+                    "maximum": 1000,
+                    "minimum": 1
+                },
+                "properties": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "slug": {
+                    "type": "string"
+                },
+                "to": {
+                    "type": "string"
+                },
+                "value_properties": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "value_property": {
+                    "type": "string"
+                }
+            }
+        },
+        "meters.queryMeterRequest": {
+            "type": "object",
+            "required": [
+                "meter_slug"
+            ],
+            "properties": {
+                "compare": {
+                    "$ref": "#/definitions/models.CompareMode"
+                },
+                "cumulative": {
+                    "type": "boolean"
+                },
+                "cursor": {
+                    "type": "string"
+                },
+                "fill": {
+                    "$ref": "#/definitions/models.FillMode"
+                },
+                "filter": {
+                    "$ref": "#/definitions/models.FilterExpression"
+                },
+                "filter_group_by": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        }
+                    }
+                },
+                "from": {
+                    "type": "string"
+                },
+                "group_by": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "include_others": {
+                    "type": "boolean"
+                },
+                "limit": {
+                    "type": "integer",
+                    "maximum": 10000,
+                    "minimum": 1
+                },
+                "meter_slug": {
+                    "type": "string"
+                },
+                "offset": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "order_by": {
+                    "$ref": "#/definitions/models.QueryMeterOrderBy"
+                },
+                "to": {
+                    "type": "string"
+                },
+                "window_size": {
+                    "$ref": "#/definitions/models.WindowSize"
+                },
+                "window_time_zone": {
+                    "type": "string"
+                }
+            }
+        },
+        "meters.updateMeterRequest": {
+            "type": "object",
+            "required": [
+                "updated_by"
+            ],
+            "properties": {
+                "description": {
+                    "type": "string",
+                    "maxLength": 255,
+                    "minLength": 3
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "minLength": 3
+                },
+                "updated_by": {
+                    "type": "string",
+                    "maxLength": 255,
+                    "minLength": 3
+                }
+            }
+        },
+        "models.AggregationEnum": {
+            "type": "string",
+            "enum": [
+                "count",
+                "sum",
+                "avg",
+                "unique_count",
+                "min",
+                "max"
+            ],
+            "x-enum-varnames": [
+                "AggregationCount",
+                "AggregationSum",
+                "AggregationAvg",
+                "AggregationUniqueCount",
+                "AggregationMin",
+                "AggregationMax"
+            ]
+        },
+        "models.AlertRule": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by": {
+                    "type": "string"
+                },
+                "enabled": {
+                    "type": "boolean"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "meter_slug": {
+                    "description": "MeterSlug and PlanFeatureID are mutually exclusive targets",
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "plan_feature_id": {
+                    "type": "string"
+                },
+                "subject_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "subject_type": {
+                    "$ref": "#/definitions/models.SubjectType"
+                },
+                "tenant_slug": {
+                    "type": "string"
+                },
+                "threshold": {
+                    "type": "number"
+                },
+                "threshold_type": {
+                    "$ref": "#/definitions/models.AlertThresholdType"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "updated_by": {
+                    "type": "string"
+                },
+                "window_minutes": {
+                    "description": "WindowMinutes is the trailing window of meter rules",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.AlertState": {
+            "type": "object",
+            "properties": {
+                "alert_rule_id": {
+                    "type": "string"
+                },
+                "evaluated_at": {
+                    "type": "string"
+                },
+                "fired_at": {
+                    "type": "string"
+                },
+                "period_start": {
+                    "description": "PeriodStart is the quota period the state applies to; firings are deduplicated per period",
+                    "type": "string"
+                },
+                "resolved_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/models.AlertStatus"
+                },
+                "subject_id": {
+                    "type": "string"
+                },
+                "threshold_value": {
+                    "description": "ThresholdValue is the threshold in the unit of Value",
+                    "type": "number"
+                },
+                "value": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.AlertStatus": {
+            "type": "string",
+            "enum": [
+                "ok",
+                "firing",
+                "notifying"
+            ],
+            "x-enum-varnames": [
+                "AlertStatusOK",
+                "AlertStatusFiring",
+                "AlertStatusNotifying"
+            ]
+        },
+        "models.AlertThresholdType": {
+            "type": "string",
+            "enum": [
+                "absolute",
+                "percent"
+            ],
+            "x-enum-varnames": [
+                "AlertThresholdAbsolute",
+                "AlertThresholdPercent"
+            ]
+        },
+        "models.CompareMode": {
+            "type": "string",
+            "enum": [
+                "previous_period"
+            ],
+            "x-enum-varnames": [
+                "CompareModePreviousPeriod"
+            ]
+        },
+        "models.Entitlement": {
+            "type": "object",
+            "properties": {
+                "band": {
+                    "$ref": "#/definitions/models.QuotaBand"
+                },
+                "config": {
+                    "type": "object",
+                    "additionalProperties": {}
+                },
+                "feature_id": {
+                    "type": "string"
+                },
+                "feature_name": {
+                    "type": "string"
+                },
+                "feature_slug": {
+                    "type": "string"
+                },
+                "grace_ends_at": {
+                    "description": "GraceEndsAt is when the grace period started by using up the overage allowance ends",
+                    "type": "string"
+                },
+                "merge_rule": {
+                    "$ref": "#/definitions/models.QuotaMergeRule"
+                },
+                "meter_slugs": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "quota": {
+                    "$ref": "#/definitions/models.PlanFeatureQuota"
+                },
+                "sources": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.EntitlementSource"
+                    }
+                },
+                "type": {
+                    "$ref": "#/definitions/models.FeatureTypeEnum"
+                },
+                "used": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.EntitlementOverride": {
+            "type": "object",
+            "properties": {
+                "action_at_limit": {
+                    "$ref": "#/definitions/models.MeteredActionAtLimit"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by": {
+                    "type": "string"
+                },
+                "custom_period_minutes": {
+                    "type": "integer"
+                },
+                "effect": {
+                    "$ref": "#/definitions/models.EntitlementOverrideEffect"
+                },
+                "expires_at": {
+                    "description": "ExpiresAt is when the override stops applying; nil overrides never expire",
+                    "type": "string"
+                },
+                "feature_id": {
+                    "type": "string"
+                },
+                "feature_name": {
+                    "type": "string"
+                },
+                "feature_slug": {
+                    "type": "string"
+                },
+                "feature_type": {
+                    "$ref": "#/definitions/models.FeatureTypeEnum"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "limit_value": {
+                    "type": "integer"
+                },
+                "merge_rule": {
+                    "$ref": "#/definitions/models.QuotaMergeRule"
+                },
+                "meter_slugs": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "plan_assignment_id": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "reset_period": {
+                    "$ref": "#/definitions/models.MeteredResetPeriod"
+                },
+                "subject_id": {
+                    "type": "string"
+                },
+                "subject_type": {
+                    "$ref": "#/definitions/models.SubjectType"
+                },
+                "tenant_slug": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "updated_by": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.EntitlementOverrideEffect": {
+            "type": "string",
+            "enum": [
+                "grant",
+                "revoke"
+            ],
+            "x-enum-varnames": [
+                "EntitlementOverrideGrant",
+                "EntitlementOverrideRevoke"
+            ]
+        },
+        "models.EntitlementSource": {
+            "type": "object",
+            "properties": {
+                "granted_by": {
+                    "type": "string"
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "override_id": {
+                    "type": "string"
+                },
+                "period_conflict": {
+                    "type": "boolean"
+                },
+                "plan_id": {
+                    "type": "string"
+                },
+                "plan_kind": {
+                    "$ref": "#/definitions/models.PlanKindEnum"
+                },
+                "plan_version_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Event": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "description": "The event ID.",
+                    "type": "string"
+                },
+                "ingested_at": {
+                    "description": "The time the event was ingested.",
+                    "type": "string"
+                },
+                "organization": {
+                    "description": "ID of the organization that user belongs to.",
+                    "type": "string"
+                },
+                "properties": {
+                    "description": "The event data as a JSON string.",
+                    "type": "string"
+                },
+                "source": {
+                    "description": "The event source.",
+                    "type": "string"
+                },
+                "timestamp": {
+                    "description": "The event time.",
+                    "type": "string"
+                },
+                "type": {
+                    "description": "The event type.",
+                    "type": "string"
+                },
+                "user": {
+                    "description": "The ID of the user that owns the event.",
+                    "type": "string"
+                }
+            }
+        },
+        "models.ExportFormat": {
+            "type": "string",
+            "enum": [
+                "csv",
+                "ndjson",
+                "parquet"
+            ],
+            "x-enum-varnames": [
+                "ExportFormatCSV",
+                "ExportFormatNDJSON",
+                "ExportFormatParquet"
+            ]
+        },
+        "models.FailedEvent": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "description": "The error that occurred"
+                },
+                "event": {
+                    "description": "The event that failed",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Event"
+                        }
+                    ]
+                }
+            }
+        },
+        "models.Feature": {
+            "type": "object",
+            "properties": {
+                "config": {
+                    "type": "object",
+                    "additionalProperties": {}
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "merge_rule": {
+                    "description": "MergeRule combines the quotas of the feature when a base plan and add-ons grant it",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.QuotaMergeRule"
+                        }
+                    ]
+                },
+                "meter_slugs": {
+                    "description": "MeterSlugs are the meters the usage of a metered feature is measured by",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                },
+                "slug": {
+                    "type": "string"
+                },
+                "tenant_slug": {
+                    "type": "string"
+                },
+                "type": {
+                    "$ref": "#/definitions/models.FeatureTypeEnum"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "updated_by": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.FeatureTypeEnum": {
+            "type": "string",
+            "enum": [
+                "static",
+                "metered"
+            ],
+            "x-enum-varnames": [
+                "FeatureTypeStatic",
+                "FeatureTypeMetered"
+            ]
+        },
+        "models.FeatureUsage": {
+            "type": "object",
+            "properties": {
+                "action_at_limit": {
+                    "$ref": "#/definitions/models.MeteredActionAtLimit"
+                },
+                "allowed_usage": {
+                    "type": "integer"
+                },
+                "band": {
+                    "$ref": "#/definitions/models.QuotaBand"
+                },
+                "feature_id": {
+                    "type": "string"
+                },
+                "feature_name": {
+                    "type": "string"
+                },
+                "feature_slug": {
+                    "type": "string"
+                },
+                "grace_ends_at": {
+                    "description": "GraceEndsAt is when the grace period started by using up the overage allowance ends",
+                    "type": "string"
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "meter_slugs": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "percentage": {
+                    "type": "number"
+                },
+                "period_end": {
+                    "description": "PeriodEnd is when usage resets; omitted for periods that never reset",
+                    "type": "string"
+                },
+                "period_start": {
+                    "type": "string"
+                },
+                "reset_period": {
+                    "$ref": "#/definitions/models.MeteredResetPeriod"
+                },
+                "soft_limit": {
+                    "type": "integer"
+                },
+                "used": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.FillMode": {
+            "type": "string",
+            "enum": [
+                "zero",
+                "null",
+                "previous"
+            ],
+            "x-enum-varnames": [
+                "FillModeZero",
+                "FillModeNull",
+                "FillModePrevious"
+            ]
+        },
+        "models.FilterExpression": {
+            "type": "object",
+            "properties": {
+                "and": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.FilterExpression"
+                    }
+                },
+                "operator": {
+                    "$ref": "#/definitions/models.FilterOperator"
+                },
+                "or": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.FilterExpression"
+                    }
+                },
+                "property": {
+                    "type": "string"
+                },
+                "value": {},
+                "values": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "models.FilterOperator": {
+            "type": "string",
+            "enum": [
+                "eq",
+                "neq",
+                "in",
+                "not_in",
+                "prefix",
+                "contains",
+                "gt",
+                "gte",
+                "lt",
+                "lte",
+                "exists",
+                "missing"
+            ],
+            "x-enum-varnames": [
+                "FilterOpEq",
+                "FilterOpNeq",
+                "FilterOpIn",
+                "FilterOpNotIn",
+                "FilterOpPrefix",
+                "FilterOpContains",
+                "FilterOpGt",
+                "FilterOpGte",
+                "FilterOpLt",
+                "FilterOpLte",
+                "FilterOpExists",
+                "FilterOpMissing"
+            ]
+        },
+        "models.Granularity": {
+            "type": "string",
+            "enum": [
+                "second",
+                "minute",
+                "hour",
+                "day",
+                "minute"
+            ],
+            "x-enum-varnames": [
+                "GranularitySecond",
+                "GranularityMinute",
+                "GranularityHour",
+                "GranularityDay",
+                "DefaultGranularity"
+            ]
+        },
+        "models.HttpResponse-array_models_AlertRule": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.AlertRule"
+                    }
+                },
+                "message": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.HttpResponse-array_models_AlertState": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.AlertState"
+                    }
+                },
+                "message": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.HttpResponse-array_models_EntitlementOverride": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.EntitlementOverride"
+                    }
+                },
+                "message": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.HttpResponse-array_models_Feature": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Feature"
+                    }
+                },
+                "message": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.HttpResponse-array_models_PlanChange": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PlanChange"
+                    }
+                },
+                "message": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.HttpResponse-array_models_PlanFeature": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PlanFeature"
+                    }
+                },
+                "message": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.HttpResponse-array_models_PlanVersion": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PlanVersion"
+                    }
+                },
+                "message": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.HttpResponse-array_models_Trial": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Trial"
+                    }
+                },
+                "message": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.HttpResponse-array_models_WebhookDelivery": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.WebhookDelivery"
+                    }
+                },
+                "message": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.HttpResponse-array_models_WebhookEndpoint": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.WebhookEndpoint"
+                    }
+                },
+                "message": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.HttpResponse-models_AlertRule": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/models.AlertRule"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.HttpResponse-models_EntitlementOverride": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/models.EntitlementOverride"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.HttpResponse-models_Feature": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/models.Feature"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.HttpResponse-models_Meter": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/models.Meter"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.HttpResponse-models_MeterPreview": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/models.MeterPreview"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.HttpResponse-models_Plan": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/models.Plan"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.HttpResponse-models_PlanAssignment": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/models.PlanAssignment"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.HttpResponse-models_PlanChange": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/models.PlanChange"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.HttpResponse-models_PlanFeature": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/models.PlanFeature"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.HttpResponse-models_PlanFeatureQuota": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/models.PlanFeatureQuota"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.HttpResponse-models_PlanVersion": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/models.PlanVersion"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.HttpResponse-models_PlanVersionMigration": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/models.PlanVersionMigration"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.HttpResponse-models_PublishEventsResult": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/models.PublishEventsResult"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.HttpResponse-models_QueryMeterResult": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/models.QueryMeterResult"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.HttpResponse-models_SubjectEntitlements": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/models.SubjectEntitlements"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.HttpResponse-models_SubjectUsage": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/models.SubjectUsage"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.HttpResponse-models_Trial": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/models.Trial"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.HttpResponse-models_WebhookDelivery": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/models.WebhookDelivery"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.HttpResponse-models_WebhookEndpoint": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/models.WebhookEndpoint"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.HttpResponse-models_WebhookEndpointWithSecret": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/models.WebhookEndpointWithSecret"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.HttpResponse-pagination_PaginationView-models_Meter": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/pagination.PaginationView-models_Meter"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.HttpResponse-pagination_PaginationView-models_Plan": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/pagination.PaginationView-models_Plan"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.HttpResponse-pagination_PaginationView-models_PlanAssignment": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/pagination.PaginationView-models_PlanAssignment"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.HttpResponse-pagination_PaginationView-models_PlanAssignmentHistory": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/pagination.PaginationView-models_PlanAssignmentHistory"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.Meter": {
+            "type": "object",
+            "properties": {
+                "aggregation": {
+                    "$ref": "#/definitions/models.AggregationEnum"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "event_type": {
+                    "type": "string"
+                },
+                "event_types": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "filter": {
+                    "description": "Filter restricts the events the meter aggregates",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.FilterExpression"
+                        }
+                    ]
+                },
+                "formula": {
+                    "type": "string"
+                },
+                "granularity": {
+                    "$ref": "#/definitions/models.Granularity"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "properties": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "reconcile_attempts": {
+                    "type": "integer"
+                },
+                "rollups": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Granularity"
+                    }
+                },
+                "slug": {
+                    "type": "string"
+                },
+                "source_meters": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "status": {
+                    "$ref": "#/definitions/models.MeterStatus"
+                },
+                "status_error": {
+                    "description": "StatusError is the last error met while creating or deleting the meter",
+                    "type": "string"
+                },
+                "tenant_slug": {
+                    "type": "string"
+                },
+                "type": {
+                    "$ref": "#/definitions/models.MeterTypeEnum"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "updated_by": {
+                    "type": "string"
+                },
+                "value_properties": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "value_property": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.MeterPreview": {
+            "type": "object",
+            "properties": {
+                "rows": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.MeterPreviewRow"
+                    }
+                },
+                "sql": {
+                    "description": "SQL is the statement that would create the meter view",
+                    "type": "string"
+                },
+                "stats": {
+                    "$ref": "#/definitions/models.MeterPreviewStats"
+                },
+                "warnings": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.MeterPreviewWarning"
+                    }
+                }
+            }
+        },
+        "models.MeterPreviewPropertyStats": {
+            "type": "object",
+            "properties": {
+                "distinct": {
+                    "type": "integer"
+                },
+                "missing": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.MeterPreviewRow": {
+            "type": "object",
+            "properties": {
+                "organization": {
+                    "type": "string"
+                },
+                "properties": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "user": {
+                    "type": "string"
+                },
+                "value": {
+                    "type": "number"
+                },
+                "window_end": {
+                    "type": "string"
+                },
+                "window_start": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.MeterPreviewStats": {
+            "type": "object",
+            "properties": {
+                "events": {
+                    "type": "integer"
+                },
+                "missing_value_property": {
+                    "type": "integer"
+                },
+                "non_numeric_values": {
+                    "type": "integer"
+                },
+                "properties": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/models.MeterPreviewPropertyStats"
+                    }
+                }
+            }
+        },
+        "models.MeterPreviewWarning": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "$ref": "#/definitions/models.MeterPreviewWarningCode"
+                },
+                "count": {
+                    "type": "integer"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "property": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.MeterPreviewWarningCode": {
+            "type": "string",
+            "enum": [
+                "no_events",
+                "missing_value_property",
+                "non_numeric_value",
+                "missing_property",
+                "high_cardinality_property"
+            ],
+            "x-enum-varnames": [
+                "PreviewWarningNoEvents",
+                "PreviewWarningMissingValueProperty",
+                "PreviewWarningNonNumericValue",
+                "PreviewWarningMissingProperty",
+                "PreviewWarningHighCardinality"
+            ]
+        },
+        "models.MeterStatus": {
+            "type": "string",
+            "enum": [
+                "pending",
+                "active",
+                "deleting",
+                "failed",
+                "delete_failed"
+            ],
+            "x-enum-varnames": [
+                "MeterStatusPending",
+                "MeterStatusActive",
+                "MeterStatusDeleting",
+                "MeterStatusFailed",
+                "MeterStatusDeleteFailed"
+            ]
+        },
+        "models.MeterTypeEnum": {
+            "type": "string",
+            "enum": [
+                "standard",
+                "derived"
+            ],
+            "x-enum-varnames": [
+                "MeterTypeStandard",
+                "MeterTypeDerived"
+            ]
+        },
+        "models.MeteredActionAtLimit": {
+            "type": "string",
+            "enum": [
+                "none",
+                "block",
+                "throttle"
+            ],
+            "x-enum-varnames": [
+                "MeteredActionAtLimitNone",
+                "MeteredActionAtLimitBlock",
+                "MeteredActionAtLimitThrottle"
+            ]
+        },
+        "models.MeteredResetPeriod": {
+            "type": "string",
+            "enum": [
+                "day",
+                "week",
+                "month",
+                "year",
+                "custom",
+                "rolling",
+                "never"
+            ],
+            "x-enum-varnames": [
+                "MeteredResetPeriodDay",
+                "MeteredResetPeriodWeek",
+                "MeteredResetPeriodMonth",
+                "MeteredResetPeriodYear",
+                "MeteredResetPeriodCustom",
+                "MeteredResetPeriodRolling",
+                "MeteredResetPeriodNever"
+            ]
+        },
+        "models.NotificationType": {
+            "type": "string",
+            "enum": [
+                "meter.created",
+                "plan.archived",
+                "assignment.created",
+                "quota.threshold_reached",
+                "meter.threshold_reached",
+                "trial.ended"
+            ],
+            "x-enum-varnames": [
+                "NotificationMeterCreated",
+                "NotificationPlanArchived",
+                "NotificationAssignmentCreated",
+                "NotificationQuotaThresholdReached",
+                "NotificationMeterThresholdReached",
+                "NotificationTrialEnded"
+            ]
+        },
+        "models.Plan": {
+            "type": "object",
+            "properties": {
+                "archived_at": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "kind": {
+                    "$ref": "#/definitions/models.PlanKindEnum"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "slug": {
+                    "type": "string"
+                },
+                "tenant_slug": {
+                    "type": "string"
+                },
+                "type": {
+                    "$ref": "#/definitions/models.PlanTypeEnum"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "updated_by": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.PlanAssignment": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "kind": {
+                    "$ref": "#/definitions/models.PlanAssignmentKind"
+                },
+                "organization_id": {
+                    "type": "string"
+                },
+                "pending_plan_version_id": {
+                    "type": "string"
+                },
+                "plan_id": {
+                    "type": "string"
+                },
+                "plan_kind": {
+                    "$ref": "#/definitions/models.PlanKindEnum"
+                },
+                "plan_version_id": {
+                    "type": "string"
+                },
+                "updated_at": {
                     "type": "string"
                 },
-                "set_valid_until_to_zero": {
-                    "type": "boolean"
-                },
                 "updated_by": {
                     "type": "string"
                 },
@@ -2066,323 +6782,193 @@ const docTemplate = `{
                 }
             }
         },
-        "errors.ErrorResponse": {
+        "models.PlanAssignmentHistory": {
             "type": "object",
             "properties": {
-                "code": {
+                "action": {
                     "type": "string"
                 },
-                "details": {
-                    "type": "object",
-                    "additionalProperties": {}
+                "created_at": {
+                    "type": "string"
                 },
-                "message": {
+                "created_by": {
                     "type": "string"
-                }
-            }
-        },
-        "events.event": {
-            "type": "object",
-            "required": [
-                "organization",
-                "type",
-                "user"
-            ],
-            "properties": {
+                },
                 "id": {
                     "type": "string"
                 },
-                "organization": {
+                "organization_id": {
                     "type": "string"
                 },
-                "properties": {
-                    "type": "object",
-                    "additionalProperties": {}
+                "plan_id": {
+                    "type": "string"
                 },
-                "source": {
+                "updated_at": {
                     "type": "string"
                 },
-                "timestamp": {
+                "updated_by": {
                     "type": "string"
                 },
-                "type": {
+                "user_id": {
                     "type": "string"
                 },
-                "user": {
+                "valid_from": {
+                    "type": "string"
+                },
+                "valid_until": {
                     "type": "string"
                 }
             }
         },
-        "events.publishEventRequestBody": {
-            "type": "object",
-            "required": [
-                "events"
+        "models.PlanAssignmentKind": {
+            "type": "string",
+            "enum": [
+                "standard",
+                "trial"
             ],
-            "properties": {
-                "allow_partial_success": {
-                    "type": "boolean"
-                },
-                "events": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/events.event"
-                    }
-                }
-            }
+            "x-enum-varnames": [
+                "PlanAssignmentKindStandard",
+                "PlanAssignmentKindTrial"
+            ]
         },
-        "features.createFeatureRequest": {
+        "models.PlanChange": {
             "type": "object",
-            "required": [
-                "created_by",
-                "name",
-                "slug",
-                "type"
-            ],
             "properties": {
-                "config": {
-                    "type": "object",
-                    "additionalProperties": {}
-                },
-                "created_by": {
+                "canceled_at": {
                     "type": "string"
                 },
-                "description": {
+                "canceled_by": {
                     "type": "string"
                 },
-                "name": {
+                "created_at": {
                     "type": "string"
                 },
-                "slug": {
+                "created_by": {
                     "type": "string"
                 },
-                "type": {
-                    "type": "string",
-                    "enum": [
-                        "static",
-                        "metered"
-                    ]
-                }
-            }
-        },
-        "features.updateFeatureRequest": {
-            "type": "object",
-            "required": [
-                "updated_by"
-            ],
-            "properties": {
-                "config": {
-                    "type": "object",
-                    "additionalProperties": {}
-                },
-                "description": {
-                    "type": "string",
-                    "maxLength": 255,
-                    "minLength": 10
-                },
-                "name": {
-                    "type": "string",
-                    "maxLength": 100,
-                    "minLength": 3
+                "effective_at": {
+                    "type": "string"
                 },
-                "updated_by": {
-                    "type": "string",
-                    "maxLength": 100,
-                    "minLength": 3
-                }
-            }
-        },
-        "meters.createMeterRequest": {
-            "type": "object",
-            "required": [
-                "aggregation",
-                "created_by",
-                "event_type",
-                "name",
-                "populate",
-                "properties",
-                "slug"
-            ],
-            "properties": {
-                "aggregation": {
-                    "type": "string",
-                    "enum": [
-                        "count",
-                        "sum",
-                        "avg",
-                        "unique_count",
-                        "min",
-                        "max"
-                    ]
+                "from_assignment_id": {
+                    "type": "string"
                 },
-                "created_by": {
+                "from_plan_id": {
                     "type": "string"
                 },
-                "description": {
+                "id": {
                     "type": "string"
                 },
-                "event_type": {
+                "organization_id": {
                     "type": "string"
                 },
-                "name": {
+                "previous_valid_until": {
                     "type": "string"
                 },
-                "populate": {
-                    "type": "boolean"
+                "reason": {
+                    "type": "string"
                 },
-                "properties": {
-                    "type": "array",
-                    "minItems": 1,
-                    "items": {
-                        "type": "string"
-                    }
+                "status": {
+                    "$ref": "#/definitions/models.PlanChangeStatus"
                 },
-                "slug": {
+                "tenant_slug": {
                     "type": "string"
                 },
-                "value_property": {
+                "to_assignment_id": {
                     "type": "string"
-                }
-            }
-        },
-        "meters.queryMeterRequest": {
-            "type": "object",
-            "required": [
-                "meter_slug"
-            ],
-            "properties": {
-                "filter_group_by": {
-                    "type": "object",
-                    "additionalProperties": {
-                        "type": "array",
-                        "items": {
-                            "type": "string"
-                        }
-                    }
                 },
-                "from": {
+                "to_plan_id": {
                     "type": "string"
                 },
-                "group_by": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
+                "type": {
+                    "$ref": "#/definitions/models.PlanChangeType"
                 },
-                "meter_slug": {
+                "updated_at": {
                     "type": "string"
                 },
-                "to": {
+                "updated_by": {
                     "type": "string"
                 },
-                "window_size": {
-                    "$ref": "#/definitions/models.WindowSize"
-                },
-                "window_time_zone": {
+                "user_id": {
                     "type": "string"
                 }
             }
         },
-        "meters.updateMeterRequest": {
-            "type": "object",
-            "required": [
-                "updated_by"
+        "models.PlanChangeStatus": {
+            "type": "string",
+            "enum": [
+                "pending",
+                "applied",
+                "canceled"
             ],
-            "properties": {
-                "description": {
-                    "type": "string",
-                    "maxLength": 255,
-                    "minLength": 3
-                },
-                "name": {
-                    "type": "string",
-                    "maxLength": 100,
-                    "minLength": 3
-                },
-                "updated_by": {
-                    "type": "string",
-                    "maxLength": 255,
-                    "minLength": 3
-                }
-            }
+            "x-enum-varnames": [
+                "PlanChangePending",
+                "PlanChangeApplied",
+                "PlanChangeCanceled"
+            ]
         },
-        "models.AggregationEnum": {
+        "models.PlanChangeType": {
             "type": "string",
             "enum": [
-                "count",
-                "sum",
-                "avg",
-                "unique_count",
-                "min",
-                "max"
+                "upgrade",
+                "downgrade",
+                "cancellation"
             ],
             "x-enum-varnames": [
-                "AggregationCount",
-                "AggregationSum",
-                "AggregationAvg",
-                "AggregationUniqueCount",
-                "AggregationMin",
-                "AggregationMax"
+                "PlanChangeUpgrade",
+                "PlanChangeDowngrade",
+                "PlanChangeCancellation"
             ]
         },
-        "models.Event": {
+        "models.PlanFeature": {
             "type": "object",
             "properties": {
-                "id": {
-                    "description": "The event ID.",
+                "config": {},
+                "created_at": {
                     "type": "string"
                 },
-                "ingested_at": {
-                    "description": "The time the event was ingested.",
+                "created_by": {
                     "type": "string"
                 },
-                "organization": {
-                    "description": "ID of the organization that user belongs to.",
+                "feature_id": {
                     "type": "string"
                 },
-                "properties": {
-                    "description": "The event data as a JSON string.",
+                "feature_merge_rule": {
+                    "$ref": "#/definitions/models.QuotaMergeRule"
+                },
+                "feature_meter_slugs": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "feature_name": {
                     "type": "string"
                 },
-                "source": {
-                    "description": "The event source.",
+                "feature_slug": {
                     "type": "string"
                 },
-                "timestamp": {
-                    "description": "The event time.",
+                "feature_type": {
+                    "$ref": "#/definitions/models.FeatureTypeEnum"
+                },
+                "id": {
                     "type": "string"
                 },
-                "type": {
-                    "description": "The event type.",
+                "plan_id": {
                     "type": "string"
                 },
-                "user": {
-                    "description": "The ID of the user that owns the event.",
+                "updated_at": {
                     "type": "string"
-                }
-            }
-        },
-        "models.FailedEvent": {
-            "type": "object",
-            "properties": {
-                "error": {
-                    "description": "The error that occurred"
                 },
-                "event": {
-                    "description": "The event that failed",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/models.Event"
-                        }
-                    ]
+                "updated_by": {
+                    "type": "string"
                 }
             }
         },
-        "models.Feature": {
+        "models.PlanFeatureQuota": {
             "type": "object",
             "properties": {
-                "config": {
-                    "type": "object",
-                    "additionalProperties": {}
+                "action_at_limit": {
+                    "$ref": "#/definitions/models.MeteredActionAtLimit"
                 },
                 "created_at": {
                     "type": "string"
@@ -2390,23 +6976,32 @@ const docTemplate = `{
                 "created_by": {
                     "type": "string"
                 },
-                "description": {
-                    "type": "string"
+                "custom_period_minutes": {
+                    "type": "integer"
+                },
+                "grace_period_minutes": {
+                    "type": "integer"
                 },
                 "id": {
                     "type": "string"
                 },
-                "name": {
-                    "type": "string"
+                "limit_value": {
+                    "type": "integer"
                 },
-                "slug": {
-                    "type": "string"
+                "overage_allowance": {
+                    "type": "integer"
                 },
-                "tenant_slug": {
+                "overage_allowance_type": {
+                    "$ref": "#/definitions/models.QuotaOverageType"
+                },
+                "plan_feature_id": {
                     "type": "string"
                 },
-                "type": {
-                    "$ref": "#/definitions/models.FeatureTypeEnum"
+                "reset_period": {
+                    "$ref": "#/definitions/models.MeteredResetPeriod"
+                },
+                "soft_limit_value": {
+                    "type": "integer"
                 },
                 "updated_at": {
                     "type": "string"
@@ -2416,224 +7011,285 @@ const docTemplate = `{
                 }
             }
         },
-        "models.FeatureTypeEnum": {
+        "models.PlanKindEnum": {
             "type": "string",
             "enum": [
-                "static",
-                "metered"
+                "base",
+                "addon"
             ],
             "x-enum-varnames": [
-                "FeatureTypeStatic",
-                "FeatureTypeMetered"
+                "PlanKindBase",
+                "PlanKindAddon"
             ]
         },
-        "models.HttpResponse-array_models_Feature": {
+        "models.PlanTypeEnum": {
+            "type": "string",
+            "enum": [
+                "standard",
+                "custom"
+            ],
+            "x-enum-varnames": [
+                "Standard",
+                "Custom"
+            ]
+        },
+        "models.PlanVersion": {
             "type": "object",
             "properties": {
-                "data": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.Feature"
-                    }
+                "created_at": {
+                    "type": "string"
                 },
-                "message": {
+                "created_by": {
                     "type": "string"
                 },
-                "status": {
-                    "type": "integer"
-                }
-            }
-        },
-        "models.HttpResponse-array_models_PlanFeature": {
-            "type": "object",
-            "properties": {
-                "data": {
+                "description": {
+                    "type": "string"
+                },
+                "features": {
                     "type": "array",
                     "items": {
                         "$ref": "#/definitions/models.PlanFeature"
                     }
                 },
-                "message": {
+                "id": {
                     "type": "string"
                 },
-                "status": {
-                    "type": "integer"
-                }
-            }
-        },
-        "models.HttpResponse-models_Feature": {
-            "type": "object",
-            "properties": {
-                "data": {
-                    "$ref": "#/definitions/models.Feature"
-                },
-                "message": {
+                "plan_id": {
                     "type": "string"
                 },
-                "status": {
+                "version": {
                     "type": "integer"
                 }
             }
         },
-        "models.HttpResponse-models_Meter": {
+        "models.PlanVersionMigration": {
             "type": "object",
             "properties": {
-                "data": {
-                    "$ref": "#/definitions/models.Meter"
+                "assignments": {
+                    "type": "integer"
                 },
-                "message": {
-                    "type": "string"
+                "on_renewal": {
+                    "type": "boolean"
                 },
-                "status": {
+                "version": {
                     "type": "integer"
                 }
             }
         },
-        "models.HttpResponse-models_Plan": {
+        "models.PublishEventsResult": {
             "type": "object",
             "properties": {
-                "data": {
-                    "$ref": "#/definitions/models.Plan"
-                },
-                "message": {
-                    "type": "string"
+                "error": {},
+                "failed_events": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.FailedEvent"
+                    }
                 },
-                "status": {
+                "success_count": {
                     "type": "integer"
                 }
             }
         },
-        "models.HttpResponse-models_PlanAssignment": {
+        "models.QueryMeterOrderBy": {
             "type": "object",
             "properties": {
-                "data": {
-                    "$ref": "#/definitions/models.PlanAssignment"
+                "direction": {
+                    "$ref": "#/definitions/models.SortDirection"
                 },
-                "message": {
+                "field": {
                     "type": "string"
-                },
-                "status": {
-                    "type": "integer"
                 }
             }
         },
-        "models.HttpResponse-models_PlanFeature": {
+        "models.QueryMeterResult": {
             "type": "object",
             "properties": {
                 "data": {
-                    "$ref": "#/definitions/models.PlanFeature"
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.QueryMeterRow"
+                    }
                 },
-                "message": {
+                "next_cursor": {
                     "type": "string"
                 },
-                "status": {
-                    "type": "integer"
-                }
-            }
-        },
-        "models.HttpResponse-models_PlanFeatureQuota": {
-            "type": "object",
-            "properties": {
-                "data": {
-                    "$ref": "#/definitions/models.PlanFeatureQuota"
-                },
-                "message": {
+                "previous_from": {
+                    "description": "PreviousFrom and PreviousTo are the range rows were compared with in comparison mode",
                     "type": "string"
                 },
-                "status": {
-                    "type": "integer"
-                }
-            }
-        },
-        "models.HttpResponse-models_PublishEventsResult": {
-            "type": "object",
-            "properties": {
-                "data": {
-                    "$ref": "#/definitions/models.PublishEventsResult"
+                "previous_to": {
+                    "type": "string"
                 },
-                "message": {
+                "window_end": {
                     "type": "string"
                 },
-                "status": {
-                    "type": "integer"
-                }
-            }
-        },
-        "models.HttpResponse-models_QueryMeterResult": {
-            "type": "object",
-            "properties": {
-                "data": {
-                    "$ref": "#/definitions/models.QueryMeterResult"
+                "window_size": {
+                    "$ref": "#/definitions/models.WindowSize"
                 },
-                "message": {
+                "window_start": {
                     "type": "string"
-                },
-                "status": {
-                    "type": "integer"
                 }
             }
         },
-        "models.HttpResponse-pagination_PaginationView-models_Meter": {
+        "models.QueryMeterRow": {
             "type": "object",
             "properties": {
-                "data": {
-                    "$ref": "#/definitions/pagination.PaginationView-models_Meter"
+                "change": {
+                    "type": "number"
                 },
-                "message": {
+                "change_percent": {
+                    "type": "number"
+                },
+                "filled": {
+                    "description": "Filled marks rows added for windows without events",
+                    "type": "boolean"
+                },
+                "group_by": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "others": {
+                    "type": "boolean"
+                },
+                "previous_value": {
+                    "description": "PreviousValue, Change and ChangePercent compare the row with the previous period.\nChangePercent is omitted when the previous value is zero.",
+                    "type": "number"
+                },
+                "value": {
+                    "type": "number"
+                },
+                "window_end": {
                     "type": "string"
                 },
-                "status": {
-                    "type": "integer"
+                "window_start": {
+                    "type": "string"
                 }
             }
         },
-        "models.HttpResponse-pagination_PaginationView-models_Plan": {
+        "models.QuotaBand": {
+            "type": "string",
+            "enum": [
+                "within",
+                "soft_limit",
+                "overage",
+                "grace",
+                "exceeded"
+            ],
+            "x-enum-varnames": [
+                "QuotaBandWithin",
+                "QuotaBandSoftLimit",
+                "QuotaBandOverage",
+                "QuotaBandGrace",
+                "QuotaBandExceeded"
+            ]
+        },
+        "models.QuotaMergeRule": {
+            "type": "string",
+            "enum": [
+                "sum",
+                "max"
+            ],
+            "x-enum-varnames": [
+                "QuotaMergeSum",
+                "QuotaMergeMax"
+            ]
+        },
+        "models.QuotaOverageType": {
+            "type": "string",
+            "enum": [
+                "absolute",
+                "percent"
+            ],
+            "x-enum-varnames": [
+                "QuotaOverageAbsolute",
+                "QuotaOveragePercent"
+            ]
+        },
+        "models.SortDirection": {
+            "type": "string",
+            "enum": [
+                "asc",
+                "desc"
+            ],
+            "x-enum-varnames": [
+                "SortAsc",
+                "SortDesc"
+            ]
+        },
+        "models.SubjectEntitlements": {
             "type": "object",
             "properties": {
-                "data": {
-                    "$ref": "#/definitions/pagination.PaginationView-models_Plan"
+                "assignments": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PlanAssignment"
+                    }
+                },
+                "features": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Entitlement"
+                    }
+                },
+                "overrides": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.EntitlementOverride"
+                    }
                 },
-                "message": {
+                "subject_id": {
                     "type": "string"
                 },
-                "status": {
-                    "type": "integer"
+                "subject_type": {
+                    "$ref": "#/definitions/models.SubjectType"
                 }
             }
         },
-        "models.HttpResponse-pagination_PaginationView-models_PlanAssignment": {
+        "models.SubjectType": {
+            "type": "string",
+            "enum": [
+                "organization",
+                "user"
+            ],
+            "x-enum-varnames": [
+                "SubjectTypeOrganization",
+                "SubjectTypeUser"
+            ]
+        },
+        "models.SubjectUsage": {
             "type": "object",
             "properties": {
-                "data": {
-                    "$ref": "#/definitions/pagination.PaginationView-models_PlanAssignment"
+                "addon_plan_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 },
-                "message": {
-                    "type": "string"
+                "features": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.FeatureUsage"
+                    }
                 },
-                "status": {
-                    "type": "integer"
-                }
-            }
-        },
-        "models.HttpResponse-pagination_PaginationView-models_PlanAssignmentHistory": {
-            "type": "object",
-            "properties": {
-                "data": {
-                    "$ref": "#/definitions/pagination.PaginationView-models_PlanAssignmentHistory"
+                "plan_id": {
+                    "type": "string"
                 },
-                "message": {
+                "subject_id": {
                     "type": "string"
                 },
-                "status": {
-                    "type": "integer"
+                "subject_type": {
+                    "$ref": "#/definitions/models.SubjectType"
                 }
             }
         },
-        "models.Meter": {
+        "models.Trial": {
             "type": "object",
             "properties": {
-                "aggregation": {
-                    "$ref": "#/definitions/models.AggregationEnum"
+                "assignment_id": {
+                    "type": "string"
                 },
                 "created_at": {
                     "type": "string"
@@ -2641,25 +7297,40 @@ const docTemplate = `{
                 "created_by": {
                     "type": "string"
                 },
-                "description": {
+                "days_remaining": {
+                    "type": "integer"
+                },
+                "end_action": {
+                    "$ref": "#/definitions/models.TrialEndAction"
+                },
+                "ended_at": {
                     "type": "string"
                 },
-                "event_type": {
+                "ends_at": {
+                    "type": "string"
+                },
+                "fallback_plan_id": {
                     "type": "string"
                 },
                 "id": {
                     "type": "string"
                 },
-                "name": {
+                "organization_id": {
                     "type": "string"
                 },
-                "properties": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
+                "plan_id": {
+                    "type": "string"
                 },
-                "slug": {
+                "resulting_assignment_id": {
+                    "type": "string"
+                },
+                "starts_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/models.TrialStatus"
+                },
+                "target_plan_id": {
                     "type": "string"
                 },
                 "tenant_slug": {
@@ -2671,182 +7342,151 @@ const docTemplate = `{
                 "updated_by": {
                     "type": "string"
                 },
-                "value_property": {
+                "user_id": {
                     "type": "string"
                 }
             }
         },
-        "models.MeteredActionAtLimit": {
+        "models.TrialEndAction": {
             "type": "string",
             "enum": [
-                "none",
-                "block",
-                "throttle"
+                "convert",
+                "fallback",
+                "expire"
             ],
             "x-enum-varnames": [
-                "MeteredActionAtLimitNone",
-                "MeteredActionAtLimitBlock",
-                "MeteredActionAtLimitThrottle"
+                "TrialEndActionConvert",
+                "TrialEndActionFallback",
+                "TrialEndActionExpire"
             ]
         },
-        "models.MeteredResetPeriod": {
+        "models.TrialStatus": {
             "type": "string",
             "enum": [
-                "day",
-                "week",
-                "month",
-                "year",
-                "custom",
-                "rolling",
-                "never"
+                "active",
+                "converted",
+                "fell_back",
+                "expired"
             ],
             "x-enum-varnames": [
-                "MeteredResetPeriodDay",
-                "MeteredResetPeriodWeek",
-                "MeteredResetPeriodMonth",
-                "MeteredResetPeriodYear",
-                "MeteredResetPeriodCustom",
-                "MeteredResetPeriodRolling",
-                "MeteredResetPeriodNever"
+                "TrialActive",
+                "TrialConverted",
+                "TrialFellBack",
+                "TrialExpired"
             ]
         },
-        "models.Plan": {
+        "models.WebhookDelivery": {
             "type": "object",
             "properties": {
-                "archived_at": {
-                    "type": "string"
+                "attempts": {
+                    "type": "integer"
                 },
                 "created_at": {
                     "type": "string"
                 },
-                "created_by": {
-                    "type": "string"
-                },
-                "description": {
+                "endpoint_id": {
                     "type": "string"
                 },
-                "id": {
+                "event_id": {
                     "type": "string"
                 },
-                "name": {
-                    "type": "string"
+                "event_type": {
+                    "$ref": "#/definitions/models.NotificationType"
                 },
-                "slug": {
+                "id": {
                     "type": "string"
                 },
-                "tenant_slug": {
+                "last_attempt_at": {
                     "type": "string"
                 },
-                "type": {
-                    "$ref": "#/definitions/models.PlanTypeEnum"
-                },
-                "updated_at": {
+                "last_error": {
                     "type": "string"
                 },
-                "updated_by": {
-                    "type": "string"
-                }
-            }
-        },
-        "models.PlanAssignment": {
-            "type": "object",
-            "properties": {
-                "created_at": {
-                    "type": "string"
+                "log": {
+                    "description": "Log holds the attempts of the delivery when it is fetched on its own",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.WebhookDeliveryAttempt"
+                    }
                 },
-                "created_by": {
+                "next_attempt_at": {
                     "type": "string"
                 },
-                "id": {
-                    "type": "string"
+                "payload": {
+                    "type": "object"
                 },
-                "organization_id": {
-                    "type": "string"
+                "status": {
+                    "$ref": "#/definitions/models.WebhookDeliveryStatus"
                 },
-                "plan_id": {
+                "tenant_slug": {
                     "type": "string"
                 },
                 "updated_at": {
                     "type": "string"
-                },
-                "updated_by": {
-                    "type": "string"
-                },
-                "user_id": {
-                    "type": "string"
-                },
-                "valid_from": {
-                    "type": "string"
-                },
-                "valid_until": {
-                    "type": "string"
                 }
             }
         },
-        "models.PlanAssignmentHistory": {
+        "models.WebhookDeliveryAttempt": {
             "type": "object",
             "properties": {
-                "action": {
-                    "type": "string"
-                },
-                "created_at": {
-                    "type": "string"
-                },
-                "created_by": {
-                    "type": "string"
-                },
-                "id": {
-                    "type": "string"
-                },
-                "organization_id": {
-                    "type": "string"
-                },
-                "plan_id": {
-                    "type": "string"
+                "attempt": {
+                    "type": "integer"
                 },
-                "updated_at": {
+                "attempted_at": {
                     "type": "string"
                 },
-                "updated_by": {
-                    "type": "string"
+                "duration_ms": {
+                    "type": "integer"
                 },
-                "user_id": {
+                "error": {
                     "type": "string"
                 },
-                "valid_from": {
+                "response_body": {
                     "type": "string"
                 },
-                "valid_until": {
-                    "type": "string"
+                "response_status": {
+                    "type": "integer"
                 }
             }
         },
-        "models.PlanFeature": {
+        "models.WebhookDeliveryStatus": {
+            "type": "string",
+            "enum": [
+                "pending",
+                "succeeded",
+                "failed"
+            ],
+            "x-enum-varnames": [
+                "WebhookDeliveryPending",
+                "WebhookDeliverySucceeded",
+                "WebhookDeliveryFailed"
+            ]
+        },
+        "models.WebhookEndpoint": {
             "type": "object",
             "properties": {
-                "config": {},
                 "created_at": {
                     "type": "string"
                 },
                 "created_by": {
                     "type": "string"
                 },
-                "feature_id": {
-                    "type": "string"
-                },
-                "feature_name": {
+                "description": {
                     "type": "string"
                 },
-                "feature_slug": {
-                    "type": "string"
+                "enabled": {
+                    "type": "boolean"
                 },
-                "feature_type": {
-                    "$ref": "#/definitions/models.FeatureTypeEnum"
+                "event_types": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.NotificationType"
+                    }
                 },
                 "id": {
                     "type": "string"
                 },
-                "plan_id": {
+                "tenant_slug": {
                     "type": "string"
                 },
                 "updated_at": {
@@ -2854,106 +7494,49 @@ const docTemplate = `{
                 },
                 "updated_by": {
                     "type": "string"
+                },
+                "url": {
+                    "type": "string"
                 }
             }
         },
-        "models.PlanFeatureQuota": {
+        "models.WebhookEndpointWithSecret": {
             "type": "object",
             "properties": {
-                "action_at_limit": {
-                    "$ref": "#/definitions/models.MeteredActionAtLimit"
-                },
                 "created_at": {
                     "type": "string"
                 },
                 "created_by": {
                     "type": "string"
                 },
-                "custom_period_minutes": {
-                    "type": "integer"
-                },
-                "id": {
-                    "type": "string"
-                },
-                "limit_value": {
-                    "type": "integer"
-                },
-                "plan_feature_id": {
-                    "type": "string"
-                },
-                "reset_period": {
-                    "$ref": "#/definitions/models.MeteredResetPeriod"
-                },
-                "updated_at": {
+                "description": {
                     "type": "string"
                 },
-                "updated_by": {
-                    "type": "string"
-                }
-            }
-        },
-        "models.PlanTypeEnum": {
-            "type": "string",
-            "enum": [
-                "standard",
-                "custom"
-            ],
-            "x-enum-varnames": [
-                "Standard",
-                "Custom"
-            ]
-        },
-        "models.PublishEventsResult": {
-            "type": "object",
-            "properties": {
-                "error": {},
-                "failed_events": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.FailedEvent"
-                    }
+                "enabled": {
+                    "type": "boolean"
                 },
-                "success_count": {
-                    "type": "integer"
-                }
-            }
-        },
-        "models.QueryMeterResult": {
-            "type": "object",
-            "properties": {
-                "data": {
+                "event_types": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/models.QueryMeterRow"
+                        "$ref": "#/definitions/models.NotificationType"
                     }
                 },
-                "window_end": {
+                "id": {
                     "type": "string"
                 },
-                "window_size": {
-                    "$ref": "#/definitions/models.WindowSize"
+                "secret": {
+                    "type": "string"
                 },
-                "window_start": {
+                "tenant_slug": {
                     "type": "string"
-                }
-            }
-        },
-        "models.QueryMeterRow": {
-            "type": "object",
-            "properties": {
-                "group_by": {
-                    "type": "object",
-                    "additionalProperties": {
-                        "type": "string"
-                    }
                 },
-                "value": {
-                    "type": "number"
+                "updated_at": {
+                    "type": "string"
                 },
-                "window_end": {
+                "updated_by": {
                     "type": "string"
                 },
-                "window_start": {
+                "url": {
                     "type": "string"
                 }
             }
@@ -3101,6 +7684,43 @@ const docTemplate = `{
                 }
             }
         },
+        "plans.clonePlanRequest": {
+            "type": "object",
+            "required": [
+                "created_by",
+                "quota_limits",
+                "slug"
+            ],
+            "properties": {
+                "created_by": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string",
+                    "maxLength": 255,
+                    "minLength": 10
+                },
+                "name": {
+                    "type": "string"
+                },
+                "quota_limits": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "slug": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string",
+                    "enum": [
+                        "standard",
+                        "custom"
+                    ]
+                }
+            }
+        },
         "plans.createPlanRequest": {
             "type": "object",
             "required": [
@@ -3118,6 +7738,13 @@ const docTemplate = `{
                     "maxLength": 255,
                     "minLength": 10
                 },
+                "kind": {
+                    "type": "string",
+                    "enum": [
+                        "base",
+                        "addon"
+                    ]
+                },
                 "name": {
                     "type": "string"
                 },
@@ -3133,6 +7760,53 @@ const docTemplate = `{
                 }
             }
         },
+        "plans.migratePlanVersionRequest": {
+            "type": "object",
+            "required": [
+                "organization_ids",
+                "updated_by",
+                "user_ids"
+            ],
+            "properties": {
+                "from_version": {
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "on_renewal": {
+                    "type": "boolean"
+                },
+                "organization_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "updated_by": {
+                    "type": "string"
+                },
+                "user_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "plans.publishPlanVersionRequest": {
+            "type": "object",
+            "required": [
+                "created_by"
+            ],
+            "properties": {
+                "created_by": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string",
+                    "maxLength": 255
+                }
+            }
+        },
         "plans.updatePlanRequest": {
             "type": "object",
             "required": [
@@ -3175,9 +7849,22 @@ const docTemplate = `{
                 "custom_period_minutes": {
                     "type": "integer"
                 },
+                "grace_period_minutes": {
+                    "type": "integer"
+                },
                 "limit_value": {
                     "type": "integer"
                 },
+                "overage_allowance": {
+                    "type": "integer"
+                },
+                "overage_allowance_type": {
+                    "type": "string",
+                    "enum": [
+                        "absolute",
+                        "percent"
+                    ]
+                },
                 "reset_period": {
                     "type": "string",
                     "enum": [
@@ -3189,6 +7876,9 @@ const docTemplate = `{
                         "rolling",
                         "never"
                     ]
+                },
+                "soft_limit_value": {
+                    "type": "integer"
                 }
             }
         },
@@ -3203,12 +7893,141 @@ const docTemplate = `{
                         "throttle"
                     ]
                 },
+                "clear_grace_period_minutes": {
+                    "type": "boolean"
+                },
+                "clear_overage_allowance": {
+                    "type": "boolean"
+                },
+                "clear_soft_limit_value": {
+                    "description": "The clear flags remove the soft limit, the overage allowance with its type, or the grace period",
+                    "type": "boolean"
+                },
+                "custom_period_minutes": {
+                    "type": "integer"
+                },
+                "grace_period_minutes": {
+                    "type": "integer"
+                },
+                "limit_value": {
+                    "type": "integer"
+                },
+                "overage_allowance": {
+                    "type": "integer"
+                },
+                "overage_allowance_type": {
+                    "type": "string",
+                    "enum": [
+                        "absolute",
+                        "percent"
+                    ]
+                },
+                "reset_period": {
+                    "type": "string",
+                    "enum": [
+                        "day",
+                        "week",
+                        "month",
+                        "year",
+                        "custom",
+                        "rolling",
+                        "never"
+                    ]
+                },
+                "soft_limit_value": {
+                    "type": "integer"
+                }
+            }
+        },
+        "subjects.createOverrideRequest": {
+            "type": "object",
+            "required": [
+                "created_by",
+                "effect",
+                "feature"
+            ],
+            "properties": {
+                "action_at_limit": {
+                    "type": "string",
+                    "enum": [
+                        "none",
+                        "block",
+                        "throttle"
+                    ]
+                },
+                "created_by": {
+                    "type": "string"
+                },
                 "custom_period_minutes": {
                     "type": "integer"
                 },
+                "effect": {
+                    "type": "string",
+                    "enum": [
+                        "grant",
+                        "revoke"
+                    ]
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "feature": {
+                    "type": "string"
+                },
                 "limit_value": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "plan_assignment_id": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "reset_period": {
+                    "type": "string",
+                    "enum": [
+                        "day",
+                        "week",
+                        "month",
+                        "year",
+                        "custom",
+                        "rolling",
+                        "never"
+                    ]
+                }
+            }
+        },
+        "subjects.updateOverrideRequest": {
+            "type": "object",
+            "required": [
+                "updated_by"
+            ],
+            "properties": {
+                "action_at_limit": {
+                    "type": "string",
+                    "enum": [
+                        "none",
+                        "block",
+                        "throttle"
+                    ]
+                },
+                "clear_expires_at": {
+                    "type": "boolean"
+                },
+                "custom_period_minutes": {
                     "type": "integer"
                 },
+                "expires_at": {
+                    "type": "string"
+                },
+                "limit_value": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "reason": {
+                    "type": "string"
+                },
                 "reset_period": {
                     "type": "string",
                     "enum": [
@@ -3220,6 +8039,76 @@ const docTemplate = `{
                         "rolling",
                         "never"
                     ]
+                },
+                "updated_by": {
+                    "type": "string"
+                }
+            }
+        },
+        "webhooks.createWebhookEndpointRequest": {
+            "type": "object",
+            "required": [
+                "created_by",
+                "event_types",
+                "url"
+            ],
+            "properties": {
+                "created_by": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "enabled": {
+                    "type": "boolean"
+                },
+                "event_types": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "secret": {
+                    "type": "string",
+                    "minLength": 16
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "webhooks.updateWebhookEndpointRequest": {
+            "type": "object",
+            "required": [
+                "event_types",
+                "updated_by"
+            ],
+            "properties": {
+                "description": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "enabled": {
+                    "type": "boolean"
+                },
+                "event_types": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "secret": {
+                    "type": "string",
+                    "minLength": 16
+                },
+                "updated_by": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
                 }
             }
         }
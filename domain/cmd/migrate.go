@@ -158,7 +158,7 @@ func runPostgresMigrations(down bool) error {
 
 	if migrateErr != nil {
 		lg.Error(fmt.Sprintf("failed to run PostgreSQL migrations (%s): %%v", action), zap.Error(migrateErr))
-		return fmt.Errorf("failed to run PostgreSQL migrations (%s): %%w", action, migrateErr)
+		return fmt.Errorf("failed to run PostgreSQL migrations (%s): %w", action, migrateErr)
 	}
 
 	lg.Info(fmt.Sprintf("PostgreSQL migrations (%s) completed successfully", action))
@@ -202,7 +202,7 @@ func runClickHouseMigrations(down bool) error {
 
 	if migrateErr != nil {
 		lg.Error(fmt.Sprintf("failed to run ClickHouse migrations (%s): %%v", action), zap.Error(migrateErr))
-		return fmt.Errorf("failed to run ClickHouse migrations (%s): %%w", action, migrateErr)
+		return fmt.Errorf("failed to run ClickHouse migrations (%s): %w", action, migrateErr)
 	}
 
 	lg.Info(fmt.Sprintf("ClickHouse migrations (%s) completed successfully", action))
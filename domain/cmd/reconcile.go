@@ -0,0 +1,150 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+
+	"github.com/redcardinal-io/metering/application/services"
+	"github.com/redcardinal-io/metering/domain/models"
+	"github.com/redcardinal-io/metering/domain/pkg/config"
+	"github.com/redcardinal-io/metering/domain/pkg/logger"
+	"github.com/redcardinal-io/metering/infrastructure/clickhouse"
+	"github.com/redcardinal-io/metering/infrastructure/postgres/store"
+	"github.com/redcardinal-io/metering/infrastructure/postgres/store/meters"
+)
+
+var (
+	reconcileTenant string
+	reconcileRepair bool
+	reconcileDryRun bool
+	reconcileJSON   bool
+)
+
+func init() {
+	reconcileCmd.Flags().StringVarP(&reconcileTenant, "tenant", "t", "", "Only check the meters and views of this tenant")
+	reconcileCmd.Flags().BoolVar(&reconcileRepair, "repair", false, "Recreate the views of drifted meters from rc_events and drop orphan views")
+	reconcileCmd.Flags().BoolVar(&reconcileDryRun, "dry-run", false, "With --repair, report the repairs without applying them")
+	reconcileCmd.Flags().BoolVar(&reconcileJSON, "json", false, "Print the drift report as JSON")
+
+	rootCmd.AddCommand(reconcileCmd)
+}
+
+var reconcileCmd = &cobra.Command{
+	Use:   "reconcile",
+	Short: "Detect and repair drift between meters and their ClickHouse views",
+	Long: `Compare the meters stored in PostgreSQL with the materialized views in ClickHouse and report
+active meters without their views, views whose columns no longer match the meter definition and
+meter views without a meter.
+
+With --repair, the views of drifted meters are dropped and recreated, populated from rc_events,
+and orphan views are dropped. Events ingested while a view is being populated may be missed.
+Connection settings are read from the same environment as the serve command.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runReconcile(cmd.Context(), cmd.OutOrStdout())
+	},
+}
+
+func runReconcile(ctx context.Context, out io.Writer) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if reconcileDryRun && !reconcileRepair {
+		return fmt.Errorf("--dry-run requires --repair")
+	}
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("error loading config: %w", err)
+	}
+
+	// Logs go to stdout, so they are silenced when the report is printed as JSON
+	log := &logger.Logger{Logger: zap.NewNop()}
+	if !reconcileJSON {
+		if log, err = logger.NewLogger(&config.LoggerConfig{Level: "warn", Mode: "dev"}); err != nil {
+			return fmt.Errorf("error creating logger: %w", err)
+		}
+	}
+
+	olap := clickhouse.ClickHouseOlapRepository(log)
+	if err := olap.Connect(&cfg.ClickHouse); err != nil {
+		return fmt.Errorf("error connecting to ClickHouse: %w", err)
+	}
+	defer olap.Close()
+
+	pg := store.NewPostgresStoreRepository(log)
+	if err := pg.Connect(&cfg.Postgres); err != nil {
+		return fmt.Errorf("error connecting to Postgres: %w", err)
+	}
+	defer pg.Close()
+
+	meterStore := meters.NewPostgresMeterStoreRepository(pg.GetDB(), log)
+	meterService := services.NewMeterService(olap, meterStore, nil, cfg.QueryCache)
+
+	report, err := meterService.CheckDrift(ctx, models.MeterDriftOptions{
+		TenantSlug: reconcileTenant,
+		Repair:     reconcileRepair,
+		DryRun:     reconcileDryRun,
+	})
+	if err != nil {
+		return fmt.Errorf("error checking meter drift: %w", err)
+	}
+
+	if reconcileJSON {
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(report)
+	}
+	return printDriftReport(out, report)
+}
+
+// printDriftReport writes the drift report as a table followed by a summary line.
+func printDriftReport(out io.Writer, report *models.MeterDriftReport) error {
+	if len(report.Drifts) > 0 {
+		w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "TENANT\tMETER\tVIEW\tDRIFT\tACTION\tRESULT\tDETAIL")
+		for _, drift := range report.Drifts {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+				orDash(drift.TenantSlug),
+				orDash(drift.MeterSlug),
+				drift.View,
+				drift.Kind,
+				drift.Action,
+				driftResult(report, drift),
+				drift.Detail,
+			)
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+		fmt.Fprintln(out)
+	}
+
+	_, err := fmt.Fprintf(out, "checked %d meters and %d views, found %d drifts\n", report.Meters, report.Views, len(report.Drifts))
+	return err
+}
+
+func driftResult(report *models.MeterDriftReport, drift models.MeterDrift) string {
+	switch {
+	case !report.Repair:
+		return "-"
+	case report.DryRun:
+		return "dry run"
+	case drift.Repaired:
+		return "repaired"
+	default:
+		return "failed: " + drift.Error
+	}
+}
+
+func orDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
@@ -0,0 +1,104 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AlertThresholdType is how the threshold of an alert rule is expressed
+type AlertThresholdType string
+
+const (
+	// AlertThresholdAbsolute compares usage with the threshold value itself
+	AlertThresholdAbsolute AlertThresholdType = "absolute"
+	// AlertThresholdPercent compares usage with a percentage of the quota limit
+	AlertThresholdPercent AlertThresholdType = "percent"
+)
+
+// IsValidAlertThresholdType returns true if the provided threshold type is supported.
+func IsValidAlertThresholdType(thresholdType AlertThresholdType) bool {
+	switch thresholdType {
+	case AlertThresholdAbsolute, AlertThresholdPercent:
+		return true
+	default:
+		return false
+	}
+}
+
+// AlertStatus is the state of an alert rule for a single subject
+type AlertStatus string
+
+const (
+	AlertStatusOK     AlertStatus = "ok"
+	AlertStatusFiring AlertStatus = "firing"
+	// AlertStatusNotifying marks a firing that not every notifier accepted yet; it is notified
+	// again, with the same FiredAt, on the next evaluation
+	AlertStatusNotifying AlertStatus = "notifying"
+)
+
+// AlertRule watches the usage of subjects on a meter over a trailing window, or against the quota
+// of a plan feature over its reset period, and fires when usage reaches the threshold.
+type AlertRule struct {
+	Base
+	TenantSlug string `json:"tenant_slug"`
+	Name       string `json:"name"`
+	// MeterSlug and PlanFeatureID are mutually exclusive targets
+	MeterSlug     string             `json:"meter_slug,omitempty"`
+	PlanFeatureID *uuid.UUID         `json:"plan_feature_id,omitempty"`
+	SubjectType   SubjectType        `json:"subject_type"`
+	SubjectIDs    []string           `json:"subject_ids"`
+	ThresholdType AlertThresholdType `json:"threshold_type"`
+	Threshold     float64            `json:"threshold"`
+	// WindowMinutes is the trailing window of meter rules
+	WindowMinutes *int64 `json:"window_minutes,omitempty"`
+	Enabled       bool   `json:"enabled"`
+}
+
+// CreateAlertRuleInput represents the input for creating an alert rule. Without SubjectIDs the
+// rule watches every subject of SubjectType.
+type CreateAlertRuleInput struct {
+	Name          string
+	MeterSlug     string
+	PlanFeatureID *uuid.UUID
+	SubjectType   SubjectType
+	SubjectIDs    []string
+	ThresholdType AlertThresholdType
+	Threshold     float64
+	WindowMinutes *int64
+	Enabled       bool
+	CreatedBy     string
+}
+
+// UpdateAlertRuleInput represents the input for updating an alert rule; nil fields are unchanged.
+type UpdateAlertRuleInput struct {
+	Name          *string
+	SubjectIDs    []string
+	Threshold     *float64
+	WindowMinutes *int64
+	Enabled       *bool
+	UpdatedBy     string
+}
+
+// AlertState is where the last evaluation left an alert rule for a subject. FiredAt is when the
+// rule last started firing and is only reset by a new firing.
+type AlertState struct {
+	AlertRuleID uuid.UUID   `json:"alert_rule_id"`
+	SubjectID   string      `json:"subject_id"`
+	Status      AlertStatus `json:"status"`
+	Value       float64     `json:"value"`
+	// ThresholdValue is the threshold in the unit of Value
+	ThresholdValue float64 `json:"threshold_value"`
+	// PeriodStart is the quota period the state applies to; firings are deduplicated per period
+	PeriodStart *time.Time `json:"period_start,omitempty"`
+	FiredAt     *time.Time `json:"fired_at,omitempty"`
+	ResolvedAt  *time.Time `json:"resolved_at,omitempty"`
+	EvaluatedAt time.Time  `json:"evaluated_at"`
+}
+
+// AlertEvent is published to the alert notifiers when an alert rule fires for a subject
+type AlertEvent struct {
+	TenantSlug string     `json:"tenant_slug"`
+	Rule       AlertRule  `json:"rule"`
+	State      AlertState `json:"state"`
+}
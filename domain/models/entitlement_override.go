@@ -0,0 +1,93 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// EntitlementOverrideEffect is what an override does to a feature of a subject
+type EntitlementOverrideEffect string
+
+const (
+	// EntitlementOverrideGrant entitles the subject to the feature, replacing the quota fields it sets
+	EntitlementOverrideGrant EntitlementOverrideEffect = "grant"
+	// EntitlementOverrideRevoke takes the feature away from the subject whatever its plans grant
+	EntitlementOverrideRevoke EntitlementOverrideEffect = "revoke"
+)
+
+// IsValidEntitlementOverrideEffect returns true if the provided effect is supported.
+func IsValidEntitlementOverrideEffect(effect EntitlementOverrideEffect) bool {
+	switch effect {
+	case EntitlementOverrideGrant, EntitlementOverrideRevoke:
+		return true
+	default:
+		return false
+	}
+}
+
+// EntitlementOverride adjusts the entitlements of a single subject on top of the plans it holds.
+// Overrides attached to an assignment only apply while the assignment is active. The quota fields
+// of a grant replace those of the merged quota when set, a soft limit that is not below the limit
+// of the grant being dropped; a grant of a feature the plans do not offer is unlimited unless it
+// sets a limit.
+type EntitlementOverride struct {
+	Base
+	TenantSlug          string                    `json:"tenant_slug"`
+	SubjectType         SubjectType               `json:"subject_type"`
+	SubjectID           string                    `json:"subject_id"`
+	PlanAssignmentID    *uuid.UUID                `json:"plan_assignment_id,omitempty"`
+	FeatureID           uuid.UUID                 `json:"feature_id"`
+	FeatureSlug         string                    `json:"feature_slug"`
+	FeatureName         string                    `json:"feature_name"`
+	FeatureType         FeatureTypeEnum           `json:"feature_type"`
+	MeterSlugs          []string                  `json:"meter_slugs,omitempty"`
+	MergeRule           QuotaMergeRule            `json:"merge_rule"`
+	Effect              EntitlementOverrideEffect `json:"effect"`
+	LimitValue          *int64                    `json:"limit_value,omitempty"`
+	ResetPeriod         MeteredResetPeriod        `json:"reset_period,omitempty"`
+	CustomPeriodMinutes *int64                    `json:"custom_period_minutes,omitempty"`
+	ActionAtLimit       MeteredActionAtLimit      `json:"action_at_limit,omitempty"`
+	Reason              string                    `json:"reason,omitempty"`
+	// ExpiresAt is when the override stops applying; nil overrides never expire
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// CreateEntitlementOverrideInput represents the input for creating an entitlement override.
+// Feature is resolved to FeatureID by ID or slug.
+type CreateEntitlementOverrideInput struct {
+	SubjectType         SubjectType
+	SubjectID           string
+	PlanAssignmentID    *uuid.UUID
+	Feature             string
+	FeatureID           uuid.UUID
+	Effect              EntitlementOverrideEffect
+	LimitValue          *int64
+	ResetPeriod         MeteredResetPeriod
+	CustomPeriodMinutes *int64
+	ActionAtLimit       MeteredActionAtLimit
+	Reason              string
+	ExpiresAt           *time.Time
+	CreatedBy           string
+}
+
+// UpdateEntitlementOverrideInput represents the input for updating an entitlement override; empty
+// fields are unchanged and ClearExpiresAt makes the override permanent.
+type UpdateEntitlementOverrideInput struct {
+	LimitValue          *int64
+	ResetPeriod         MeteredResetPeriod
+	CustomPeriodMinutes *int64
+	ActionAtLimit       MeteredActionAtLimit
+	Reason              *string
+	ExpiresAt           *time.Time
+	ClearExpiresAt      bool
+	UpdatedBy           string
+}
+
+// QueryEntitlementOverrideInput selects the overrides of a subject
+type QueryEntitlementOverrideInput struct {
+	SubjectType    SubjectType
+	SubjectID      string
+	IncludeExpired bool
+	At             time.Time
+}
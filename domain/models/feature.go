@@ -9,6 +9,16 @@ const (
 	FeatureTypeMetered FeatureTypeEnum = "metered"
 )
 
+// QuotaMergeRule is how the quota limits of a feature granted by several assigned plans combine
+type QuotaMergeRule string
+
+const (
+	// QuotaMergeSum adds up the limits of every plan granting the feature
+	QuotaMergeSum QuotaMergeRule = "sum"
+	// QuotaMergeMax keeps the highest limit among the plans granting the feature
+	QuotaMergeMax QuotaMergeRule = "max"
+)
+
 // Feature represents a feature in the system
 type Feature struct {
 	Base
@@ -18,6 +28,10 @@ type Feature struct {
 	TenantSlug  string          `json:"tenant_slug"`
 	Type        FeatureTypeEnum `json:"type"`
 	Config      map[string]any  `json:"config,omitempty"`
+	// MeterSlugs are the meters the usage of a metered feature is measured by
+	MeterSlugs []string `json:"meter_slugs,omitempty"`
+	// MergeRule combines the quotas of the feature when a base plan and add-ons grant it
+	MergeRule QuotaMergeRule `json:"merge_rule"`
 }
 
 // CreateFeatureInput represents the input for creating a new feature
@@ -28,6 +42,8 @@ type CreateFeatureInput struct {
 	TenantSlug  string          `json:"tenant_slug"`
 	Type        FeatureTypeEnum `json:"type"`
 	Config      map[string]any  `json:"config,omitempty"`
+	MeterSlugs  []string        `json:"meter_slugs,omitempty"`
+	MergeRule   QuotaMergeRule  `json:"merge_rule,omitempty"`
 	CreatedBy   string          `json:"created_by"`
 }
 
@@ -36,5 +52,9 @@ type UpdateFeatureInput struct {
 	Name        string         `json:"name"`
 	Description string         `json:"description,omitempty"`
 	Config      map[string]any `json:"config,omitempty"`
-	UpdatedBy   string         `json:"updated_by"`
+	// MeterSlugs replaces the linked meters when not nil
+	MeterSlugs []string `json:"meter_slugs,omitempty"`
+	// MergeRule replaces the merge rule when not empty
+	MergeRule QuotaMergeRule `json:"merge_rule,omitempty"`
+	UpdatedBy string         `json:"updated_by"`
 }
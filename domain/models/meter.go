@@ -1,6 +1,11 @@
 package models
 
 import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -16,30 +21,103 @@ const (
 	AggregationMax         AggregationEnum = "max"
 )
 
+// MeterTypeEnum represents how a meter's values are produced
+type MeterTypeEnum string
+
+const (
+	// MeterTypeStandard aggregates events into a materialized view
+	MeterTypeStandard MeterTypeEnum = "standard"
+	// MeterTypeDerived evaluates a formula over other meters at query time
+	MeterTypeDerived MeterTypeEnum = "derived"
+)
+
+// MeterStatus is the lifecycle state of a meter across the store and the OLAP database
+type MeterStatus string
+
+const (
+	// MeterStatusPending meters are stored but their view may not exist yet
+	MeterStatusPending MeterStatus = "pending"
+	// MeterStatusActive meters have every object they need and can be queried
+	MeterStatusActive MeterStatus = "active"
+	// MeterStatusDeleting meters are being removed; their view may already be gone
+	MeterStatusDeleting MeterStatus = "deleting"
+	// MeterStatusFailed meters could not be created; see StatusError
+	MeterStatusFailed MeterStatus = "failed"
+	// MeterStatusDeleteFailed meters could not be removed; see StatusError. Retrying them resumes
+	// the removal
+	MeterStatusDeleteFailed MeterStatus = "delete_failed"
+)
+
 // Meter represents a meter entity from the database
 type Meter struct {
 	Base
-	Name          string          `json:"name"`
-	Slug          string          `json:"slug"`
-	EventType     string          `json:"event_type"`
-	Description   string          `json:"description,omitempty"`
-	ValueProperty string          `json:"value_property,omitempty"`
-	Properties    []string        `json:"properties"`
-	Aggregation   AggregationEnum `json:"aggregation"`
-	TenantSlug    string          `json:"tenant_slug"`
+	Name            string            `json:"name"`
+	Slug            string            `json:"slug"`
+	Type            MeterTypeEnum     `json:"type"`
+	EventType       string            `json:"event_type,omitempty"`
+	EventTypes      []string          `json:"event_types,omitempty"`
+	Description     string            `json:"description,omitempty"`
+	ValueProperty   string            `json:"value_property,omitempty"`
+	ValueProperties map[string]string `json:"value_properties,omitempty"`
+	Properties      []string          `json:"properties"`
+	Aggregation     AggregationEnum   `json:"aggregation,omitempty"`
+	Formula         string            `json:"formula,omitempty"`
+	SourceMeters    []string          `json:"source_meters,omitempty"`
+	Granularity     Granularity       `json:"granularity"`
+	Rollups         []Granularity     `json:"rollups,omitempty"`
+	// Filter restricts the events the meter aggregates
+	Filter *FilterExpression `json:"filter,omitempty"`
+	Status MeterStatus       `json:"status"`
+	// StatusError is the last error met while creating or deleting the meter
+	StatusError       string `json:"status_error,omitempty"`
+	ReconcileAttempts int    `json:"reconcile_attempts,omitempty"`
+	TenantSlug        string `json:"tenant_slug"`
+}
+
+// IsDerived reports whether the meter is computed from other meters.
+func (m *Meter) IsDerived() bool {
+	return m.Type == MeterTypeDerived
+}
+
+// AllEventTypes returns the event types the meter aggregates.
+func (m *Meter) AllEventTypes() []string {
+	if len(m.EventTypes) > 0 {
+		return m.EventTypes
+	}
+	if m.EventType == "" {
+		return nil
+	}
+	return []string{m.EventType}
+}
+
+// ValuePropertyFor returns the property the value of events of the given type is read from.
+func (m *Meter) ValuePropertyFor(eventType string) string {
+	if property, ok := m.ValueProperties[eventType]; ok {
+		return property
+	}
+	return m.ValueProperty
 }
 
 // CreateMeterInput represents the input for creating a new meter
 type CreateMeterInput struct {
-	Name          string
-	MeterSlug     string
-	EventType     string
-	Description   string
-	ValueProperty string
-	Properties    []string
-	Aggregation   AggregationEnum
-	Populate      bool
-	CreatedBy     string
+	Name            string
+	MeterSlug       string
+	Type            MeterTypeEnum
+	EventType       string
+	EventTypes      []string
+	Description     string
+	ValueProperty   string
+	ValueProperties map[string]string
+	Properties      []string
+	Aggregation     AggregationEnum
+	Formula         string
+	SourceMeters    []string
+	Granularity     Granularity
+	Rollups         []Granularity
+	Filter          *FilterExpression
+	Status          MeterStatus
+	Populate        bool
+	CreatedBy       string
 }
 
 type WindowSize string
@@ -50,6 +128,20 @@ const (
 	WindowSizeDay    WindowSize = "day"
 )
 
+// Duration returns the length of a single window.
+func (ws WindowSize) Duration() time.Duration {
+	switch ws {
+	case WindowSizeMinute:
+		return time.Minute
+	case WindowSizeHour:
+		return time.Hour
+	case WindowSizeDay:
+		return 24 * time.Hour
+	default:
+		return 0
+	}
+}
+
 func IsValidWindowSize(ws *WindowSize) bool {
 	switch *ws {
 	case WindowSizeMinute, WindowSizeHour, WindowSizeDay:
@@ -59,6 +151,56 @@ func IsValidWindowSize(ws *WindowSize) bool {
 	}
 }
 
+// Granularity is the time bucket a meter view stores its aggregates in
+type Granularity string
+
+const (
+	GranularitySecond Granularity = "second"
+	GranularityMinute Granularity = "minute"
+	GranularityHour   Granularity = "hour"
+	GranularityDay    Granularity = "day"
+)
+
+// DefaultGranularity is the granularity of meters created without one
+const DefaultGranularity = GranularityMinute
+
+func (g Granularity) Duration() time.Duration {
+	switch g {
+	case GranularitySecond:
+		return time.Second
+	case GranularityMinute:
+		return time.Minute
+	case GranularityHour:
+		return time.Hour
+	case GranularityDay:
+		return 24 * time.Hour
+	default:
+		return 0
+	}
+}
+
+// SupportsWindow reports whether windows of the given size can be built from buckets of this granularity.
+func (g Granularity) SupportsWindow(ws WindowSize) bool {
+	return g.Duration() > 0 && ws.Duration() >= g.Duration()
+}
+
+// RollupGranularities returns the granularities of the rollup views kept for a meter stored at
+// the given base granularity, from finest to coarsest.
+func RollupGranularities(base Granularity) []Granularity {
+	rollups := make([]Granularity, 0, 2)
+	for _, g := range []Granularity{GranularityHour, GranularityDay} {
+		if g.Duration() > base.Duration() {
+			rollups = append(rollups, g)
+		}
+	}
+	return rollups
+}
+
+// IsValidGranularity returns true if the provided granularity is supported.
+func IsValidGranularity(g Granularity) bool {
+	return g.Duration() > 0
+}
+
 // ValidateAggregation returns true if the provided string is a valid aggregation type.
 func ValidateAggregation(value string) bool {
 	switch AggregationEnum(value) {
@@ -70,14 +212,99 @@ func ValidateAggregation(value string) bool {
 	}
 }
 
+// FillMode controls how windows without events are represented in windowed query results
+type FillMode string
+
+const (
+	FillModeZero FillMode = "zero"
+	FillModeNull FillMode = "null"
+	// FillModePrevious carries the last known value of the group forward, which suits gauges
+	FillModePrevious FillMode = "previous"
+)
+
+// IsValidFillMode returns true if the provided fill mode is supported.
+func IsValidFillMode(mode FillMode) bool {
+	switch mode {
+	case FillModeZero, FillModeNull, FillModePrevious:
+		return true
+	default:
+		return false
+	}
+}
+
+// CompareMode selects the period a meter query is compared with
+type CompareMode string
+
+const (
+	// CompareModePreviousPeriod compares with the range of the same length ending at From
+	CompareModePreviousPeriod CompareMode = "previous_period"
+)
+
+// IsValidCompareMode returns true if the provided comparison mode is supported.
+func IsValidCompareMode(mode CompareMode) bool {
+	return mode == CompareModePreviousPeriod
+}
+
+// ExportFormat is the file format meter query results are exported as
+type ExportFormat string
+
+const (
+	ExportFormatCSV     ExportFormat = "csv"
+	ExportFormatNDJSON  ExportFormat = "ndjson"
+	ExportFormatParquet ExportFormat = "parquet"
+)
+
+// IsValidExportFormat returns true if the provided export format is supported.
+func IsValidExportFormat(format ExportFormat) bool {
+	switch format {
+	case ExportFormatCSV, ExportFormatNDJSON, ExportFormatParquet:
+		return true
+	default:
+		return false
+	}
+}
+
+// SortDirection represents the direction in which meter query results are ordered
+type SortDirection string
+
+const (
+	SortAsc  SortDirection = "asc"
+	SortDesc SortDirection = "desc"
+)
+
+// OrderByValue orders meter query results by the aggregated value
+const OrderByValue = "value"
+
+// QueryMeterOrderBy describes how meter query results are ordered.
+// Field is either OrderByValue, "windowstart" or one of the group by dimensions. When paging through
+// groups, "windowstart" ranks groups by their earliest window, or their latest one descending.
+type QueryMeterOrderBy struct {
+	Field     string        `json:"field"`
+	Direction SortDirection `json:"direction"`
+}
+
 type QueryMeterParams struct {
 	MeterSlug      string
 	FilterGroupBy  map[string][]string
+	Filter         *FilterExpression
 	From           *time.Time
 	To             *time.Time
 	GroupBy        []string
 	WindowSize     *WindowSize
 	WindowTimeZone *string
+	OrderBy        *QueryMeterOrderBy
+	// Limit and Offset page through groups when GroupBy is set, and through rows otherwise
+	Limit  *int
+	Offset *int
+	Cursor string
+	// IncludeOthers adds a single row aggregating every group outside the requested page
+	IncludeOthers bool
+	// Fill completes the series with a row for every window between From and To per group
+	Fill FillMode
+	// Cumulative turns the values of each group into running totals over the windows of the range
+	Cumulative bool
+	// Compare adds the value of the same query over an earlier period to each row
+	Compare CompareMode
 }
 
 type QueryMeterResult struct {
@@ -85,6 +312,10 @@ type QueryMeterResult struct {
 	WindowEnd   *time.Time      `json:"window_end"`
 	WindowSize  *WindowSize     `json:"window_size,omitempty"`
 	Data        []QueryMeterRow `json:"data"`
+	NextCursor  string          `json:"next_cursor,omitempty"`
+	// PreviousFrom and PreviousTo are the range rows were compared with in comparison mode
+	PreviousFrom *time.Time `json:"previous_from,omitempty"`
+	PreviousTo   *time.Time `json:"previous_to,omitempty"`
 }
 
 type QueryMeterRow struct {
@@ -92,6 +323,56 @@ type QueryMeterRow struct {
 	WindowEnd   time.Time         `json:"window_end"`
 	Value       float64           `json:"value"`
 	GroupBy     map[string]string `json:"group_by,omitempty"`
+	Others      bool              `json:"others,omitempty"`
+	// Filled marks rows added for windows without events
+	Filled bool `json:"filled,omitempty"`
+	// Null marks filled rows whose value is unknown; Value is serialized as null
+	Null bool `json:"-"`
+	// PreviousValue, Change and ChangePercent compare the row with the previous period.
+	// ChangePercent is omitted when the previous value is zero.
+	PreviousValue *float64 `json:"previous_value,omitempty"`
+	Change        *float64 `json:"change,omitempty"`
+	ChangePercent *float64 `json:"change_percent,omitempty"`
+}
+
+// MarshalJSON serializes the row, writing a null value for rows marked Null.
+func (r QueryMeterRow) MarshalJSON() ([]byte, error) {
+	type row QueryMeterRow
+	var value *float64
+	if !r.Null {
+		value = &r.Value
+	}
+	return json.Marshal(struct {
+		row
+		Value *float64 `json:"value"`
+	}{row(r), value})
+}
+
+const queryCursorPrefix = "offset:"
+
+// EncodeQueryCursor returns an opaque cursor pointing at the given offset.
+func EncodeQueryCursor(offset int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(queryCursorPrefix + strconv.Itoa(offset)))
+}
+
+// DecodeQueryCursor returns the offset encoded in a cursor produced by EncodeQueryCursor.
+func DecodeQueryCursor(cursor string) (int, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil || !strings.HasPrefix(string(raw), queryCursorPrefix) {
+		return 0, fmt.Errorf("invalid cursor")
+	}
+	offset, err := strconv.Atoi(strings.TrimPrefix(string(raw), queryCursorPrefix))
+	if err != nil || offset < 0 {
+		return 0, fmt.Errorf("invalid cursor")
+	}
+	return offset, nil
+}
+
+// UpdateMeterStatusInput moves a meter to another lifecycle state
+type UpdateMeterStatusInput struct {
+	Status            MeterStatus
+	Error             string
+	ReconcileAttempts int
 }
 
 type UpdateMeterInput struct {
@@ -99,3 +380,11 @@ type UpdateMeterInput struct {
 	Description string
 	UpdatedBy   string
 }
+
+// QueryCacheKey identifies a cached meter query result. Digest covers the normalized query,
+// its time range and the meter version, so results of an updated meter are never served.
+type QueryCacheKey struct {
+	TenantSlug string
+	MeterSlug  string
+	Digest     string
+}
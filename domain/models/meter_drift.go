@@ -0,0 +1,66 @@
+package models
+
+// OlapColumn is a column of an OLAP table or view
+type OlapColumn struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// OlapView is a view in the OLAP database with its columns in declaration order
+type OlapView struct {
+	Name    string       `json:"name"`
+	Columns []OlapColumn `json:"columns"`
+}
+
+// MeterDriftKind describes how the OLAP views of a meter differ from its stored definition
+type MeterDriftKind string
+
+const (
+	// DriftMissingView is reported for an active meter without one of its views
+	DriftMissingView MeterDriftKind = "missing_view"
+	// DriftOrphanView is reported for a meter view without a meter
+	DriftOrphanView MeterDriftKind = "orphan_view"
+	// DriftColumnMismatch is reported for a view whose columns do not match the meter definition
+	DriftColumnMismatch MeterDriftKind = "column_mismatch"
+)
+
+// MeterDriftAction is the repair applied to a drift
+type MeterDriftAction string
+
+const (
+	// DriftActionRecreate drops every view of the meter and creates them again from rc_events
+	DriftActionRecreate MeterDriftAction = "recreate"
+	// DriftActionDrop drops an orphan view
+	DriftActionDrop MeterDriftAction = "drop"
+)
+
+// MeterDriftOptions selects the meters a drift check covers and whether drifts are repaired
+type MeterDriftOptions struct {
+	// TenantSlug restricts the check to a tenant; empty checks every tenant
+	TenantSlug string
+	Repair     bool
+	// DryRun reports the repairs that would be applied without applying them
+	DryRun bool
+}
+
+// MeterDrift is a single difference between the meter store and the OLAP database
+type MeterDrift struct {
+	Kind MeterDriftKind `json:"kind"`
+	// TenantSlug is empty for orphan views that cannot be attributed to a known tenant
+	TenantSlug string           `json:"tenant_slug,omitempty"`
+	MeterSlug  string           `json:"meter_slug,omitempty"`
+	View       string           `json:"view"`
+	Detail     string           `json:"detail,omitempty"`
+	Action     MeterDriftAction `json:"action"`
+	Repaired   bool             `json:"repaired"`
+	Error      string           `json:"error,omitempty"`
+}
+
+// MeterDriftReport lists the drifts found between the meter store and the OLAP database
+type MeterDriftReport struct {
+	Meters int          `json:"meters"`
+	Views  int          `json:"views"`
+	Repair bool         `json:"repair"`
+	DryRun bool         `json:"dry_run"`
+	Drifts []MeterDrift `json:"drifts"`
+}
@@ -0,0 +1,156 @@
+package models
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// MaxFilterDepth bounds how deeply AND/OR groups may be nested in a filter expression
+const MaxFilterDepth = 5
+
+// FilterOperator represents a comparison applied to a single meter property
+type FilterOperator string
+
+const (
+	FilterOpEq       FilterOperator = "eq"
+	FilterOpNeq      FilterOperator = "neq"
+	FilterOpIn       FilterOperator = "in"
+	FilterOpNotIn    FilterOperator = "not_in"
+	FilterOpPrefix   FilterOperator = "prefix"
+	FilterOpContains FilterOperator = "contains"
+	FilterOpGt       FilterOperator = "gt"
+	FilterOpGte      FilterOperator = "gte"
+	FilterOpLt       FilterOperator = "lt"
+	FilterOpLte      FilterOperator = "lte"
+	FilterOpExists   FilterOperator = "exists"
+	FilterOpMissing  FilterOperator = "missing"
+)
+
+// IsNumeric reports whether the operator compares values numerically.
+func (op FilterOperator) IsNumeric() bool {
+	switch op {
+	case FilterOpGt, FilterOpGte, FilterOpLt, FilterOpLte:
+		return true
+	default:
+		return false
+	}
+}
+
+// FilterExpression is a tree of property conditions combined with AND/OR groups.
+// A node is either a leaf (Property + Operator) or a group (And or Or), never both.
+type FilterExpression struct {
+	Property string             `json:"property,omitempty"`
+	Operator FilterOperator     `json:"operator,omitempty"`
+	Value    any                `json:"value,omitempty"`
+	Values   []string           `json:"values,omitempty"`
+	And      []FilterExpression `json:"and,omitempty"`
+	Or       []FilterExpression `json:"or,omitempty"`
+}
+
+// IsGroup reports whether the expression combines child expressions rather than comparing a property.
+func (f *FilterExpression) IsGroup() bool {
+	return len(f.And) > 0 || len(f.Or) > 0
+}
+
+// Validate checks that the expression is well formed and only references the given columns.
+func (f *FilterExpression) Validate(columns []string) error {
+	allowed := make(map[string]struct{}, len(columns))
+	for _, c := range columns {
+		allowed[c] = struct{}{}
+	}
+	return f.validate(allowed, 1)
+}
+
+// ValidateEventFilter checks that an expression filtering the events of a meter is well formed.
+// Event filters may reference any event property.
+func (f *FilterExpression) ValidateEventFilter() error {
+	return f.validate(nil, 1)
+}
+
+// validate checks the expression recursively. A nil allowed set accepts every property.
+func (f *FilterExpression) validate(allowed map[string]struct{}, depth int) error {
+	if depth > MaxFilterDepth {
+		return fmt.Errorf("filter nesting exceeds maximum depth of %d", MaxFilterDepth)
+	}
+
+	if f.IsGroup() {
+		if len(f.And) > 0 && len(f.Or) > 0 {
+			return fmt.Errorf("filter group cannot contain both and and or")
+		}
+		if f.Property != "" || f.Operator != "" {
+			return fmt.Errorf("filter group cannot also set property or operator")
+		}
+		children := f.And
+		if len(f.Or) > 0 {
+			children = f.Or
+		}
+		for i := range children {
+			if err := children[i].validate(allowed, depth+1); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if f.Property == "" {
+		return fmt.Errorf("filter property is required")
+	}
+	if _, ok := allowed[f.Property]; !ok && allowed != nil {
+		return fmt.Errorf("unknown filter property: %s", f.Property)
+	}
+
+	switch f.Operator {
+	case FilterOpEq, FilterOpNeq, FilterOpPrefix, FilterOpContains:
+		if _, err := f.StringValue(); err != nil {
+			return err
+		}
+	case FilterOpIn, FilterOpNotIn:
+		if len(f.Values) == 0 {
+			return fmt.Errorf("filter operator %s on %s requires values", f.Operator, f.Property)
+		}
+	case FilterOpGt, FilterOpGte, FilterOpLt, FilterOpLte:
+		if _, err := f.NumericValue(); err != nil {
+			return err
+		}
+	case FilterOpExists, FilterOpMissing:
+	default:
+		return fmt.Errorf("invalid filter operator: %s", f.Operator)
+	}
+	return nil
+}
+
+// StringValue returns the scalar value of a leaf expression as a string.
+func (f *FilterExpression) StringValue() (string, error) {
+	switch v := f.Value.(type) {
+	case string:
+		return v, nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case nil:
+		return "", fmt.Errorf("filter operator %s on %s requires a value", f.Operator, f.Property)
+	default:
+		return fmt.Sprintf("%v", v), nil
+	}
+}
+
+// NumericValue returns the scalar value of a leaf expression as a number.
+func (f *FilterExpression) NumericValue() (float64, error) {
+	switch v := f.Value.(type) {
+	case float64:
+		return v, nil
+	case int:
+		return float64(v), nil
+	case int64:
+		return float64(v), nil
+	case string:
+		n, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return 0, fmt.Errorf("filter operator %s on %s requires a numeric value", f.Operator, f.Property)
+		}
+		return n, nil
+	default:
+		return 0, fmt.Errorf("filter operator %s on %s requires a numeric value", f.Operator, f.Property)
+	}
+}
@@ -0,0 +1,70 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFilterExpressionValidate(t *testing.T) {
+	columns := []string{"organization", "user", "model", "status"}
+
+	tests := []struct {
+		name    string
+		filter  FilterExpression
+		wantErr bool
+	}{
+		{
+			name:   "Known property",
+			filter: FilterExpression{Property: "model", Operator: FilterOpEq, Value: "large"},
+		},
+		{
+			name:    "Unknown property",
+			filter:  FilterExpression{Property: "region", Operator: FilterOpEq, Value: "eu"},
+			wantErr: true,
+		},
+		{
+			name: "Unknown property in nested group",
+			filter: FilterExpression{And: []FilterExpression{
+				{Property: "model", Operator: FilterOpExists},
+				{Or: []FilterExpression{{Property: "region", Operator: FilterOpMissing}}},
+			}},
+			wantErr: true,
+		},
+		{
+			name: "Group mixed with property",
+			filter: FilterExpression{
+				Property: "model",
+				And:      []FilterExpression{{Property: "status", Operator: FilterOpExists}},
+			},
+			wantErr: true,
+		},
+		{
+			name:    "In without values",
+			filter:  FilterExpression{Property: "model", Operator: FilterOpIn},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.filter.Validate(columns)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestFilterExpressionValidateEventFilter(t *testing.T) {
+	valid := FilterExpression{And: []FilterExpression{
+		{Property: "region", Operator: FilterOpIn, Values: []string{"eu", "us"}},
+		{Property: "status", Operator: FilterOpLt, Value: float64(500)},
+	}}
+	assert.NoError(t, valid.ValidateEventFilter(), "any event property may be referenced")
+
+	invalid := FilterExpression{Property: "status", Operator: FilterOpGt, Value: "abc"}
+	assert.Error(t, invalid.ValidateEventFilter())
+}
@@ -0,0 +1,63 @@
+package models
+
+import "time"
+
+// PreviewMeterInput describes a meter definition to dry-run against either the supplied sample
+// events or the events stored in rc_events between From and To
+type PreviewMeterInput struct {
+	Meter  CreateMeterInput
+	Events []*Event
+	From   *time.Time
+	To     *time.Time
+	// Limit bounds the number of rows returned
+	Limit int
+}
+
+// MeterPreview is the outcome of a meter dry-run. Nothing is persisted to produce it.
+type MeterPreview struct {
+	// SQL is the statement that would create the meter view
+	SQL      string                `json:"sql"`
+	Rows     []MeterPreviewRow     `json:"rows"`
+	Stats    MeterPreviewStats     `json:"stats"`
+	Warnings []MeterPreviewWarning `json:"warnings"`
+}
+
+// MeterPreviewRow is a row the meter view would store
+type MeterPreviewRow struct {
+	Organization string            `json:"organization"`
+	User         string            `json:"user"`
+	WindowStart  time.Time         `json:"window_start"`
+	WindowEnd    time.Time         `json:"window_end"`
+	Value        float64           `json:"value"`
+	Properties   map[string]string `json:"properties,omitempty"`
+}
+
+// MeterPreviewStats summarizes the events matched by the meter definition
+type MeterPreviewStats struct {
+	Events               int                                  `json:"events"`
+	MissingValueProperty int                                  `json:"missing_value_property"`
+	NonNumericValues     int                                  `json:"non_numeric_values"`
+	Properties           map[string]MeterPreviewPropertyStats `json:"properties"`
+}
+
+type MeterPreviewPropertyStats struct {
+	Missing  int `json:"missing"`
+	Distinct int `json:"distinct"`
+}
+
+type MeterPreviewWarningCode string
+
+const (
+	PreviewWarningNoEvents             MeterPreviewWarningCode = "no_events"
+	PreviewWarningMissingValueProperty MeterPreviewWarningCode = "missing_value_property"
+	PreviewWarningNonNumericValue      MeterPreviewWarningCode = "non_numeric_value"
+	PreviewWarningMissingProperty      MeterPreviewWarningCode = "missing_property"
+	PreviewWarningHighCardinality      MeterPreviewWarningCode = "high_cardinality_property"
+)
+
+type MeterPreviewWarning struct {
+	Code     MeterPreviewWarningCode `json:"code"`
+	Message  string                  `json:"message"`
+	Property string                  `json:"property,omitempty"`
+	Count    int                     `json:"count,omitempty"`
+}
@@ -0,0 +1,48 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// NotificationType identifies a change other services can subscribe to
+type NotificationType string
+
+const (
+	NotificationMeterCreated          NotificationType = "meter.created"
+	NotificationPlanArchived          NotificationType = "plan.archived"
+	NotificationAssignmentCreated     NotificationType = "assignment.created"
+	NotificationQuotaThresholdReached NotificationType = "quota.threshold_reached"
+	NotificationMeterThresholdReached NotificationType = "meter.threshold_reached"
+	NotificationTrialEnded            NotificationType = "trial.ended"
+)
+
+// NotificationTypes lists every notification type in the order they are documented
+var NotificationTypes = []NotificationType{
+	NotificationMeterCreated,
+	NotificationPlanArchived,
+	NotificationAssignmentCreated,
+	NotificationQuotaThresholdReached,
+	NotificationMeterThresholdReached,
+	NotificationTrialEnded,
+}
+
+// IsValidNotificationType returns true if the provided notification type is supported.
+func IsValidNotificationType(notificationType NotificationType) bool {
+	for _, t := range NotificationTypes {
+		if t == notificationType {
+			return true
+		}
+	}
+	return false
+}
+
+// Notification is a change of a tenant's resources, carrying the resource it applies to as Data
+type Notification struct {
+	ID         uuid.UUID        `json:"id"`
+	Type       NotificationType `json:"type"`
+	TenantSlug string           `json:"tenant_slug"`
+	CreatedAt  time.Time        `json:"created_at"`
+	Data       any              `json:"data"`
+}
@@ -14,6 +14,26 @@ const (
 	Custom   PlanTypeEnum = "custom"
 )
 
+// PlanKindEnum tells base plans from the add-ons stacked on top of them
+type PlanKindEnum string
+
+const (
+	// PlanKindBase is the plan a subject holds one of at a time
+	PlanKindBase PlanKindEnum = "base"
+	// PlanKindAddon is a pack a subject may hold any number of on top of its base plan
+	PlanKindAddon PlanKindEnum = "addon"
+)
+
+// PlanAssignmentKind tells regular assignments from trials that end on their own
+type PlanAssignmentKind string
+
+const (
+	// PlanAssignmentKindStandard assignments run for the period they were created with
+	PlanAssignmentKindStandard PlanAssignmentKind = "standard"
+	// PlanAssignmentKindTrial assignments are converted, fall back or expire when they end
+	PlanAssignmentKindTrial PlanAssignmentKind = "trial"
+)
+
 // PlanAssignmentHistoryActionEnum represents the possible actions in plan_assignment_history
 type HistoryActionEnum string
 
@@ -21,12 +41,16 @@ const (
 	Insert HistoryActionEnum = "CREATE"
 	Update HistoryActionEnum = "UPDATE"
 	Delete HistoryActionEnum = "DELETE"
+	// Trial actions record how a trial assignment ended
+	TrialConvertedAction HistoryActionEnum = "TRIAL_CONVERTED"
+	TrialFellBackAction  HistoryActionEnum = "TRIAL_FELL_BACK"
+	TrialExpiredAction   HistoryActionEnum = "TRIAL_EXPIRED"
 )
 
 // ValidateHistoryAction checks whether the given string matches a defined HistoryActionEnum value.
 func ValidateHistoryAction(value string) bool {
 	switch HistoryActionEnum(value) {
-	case Insert, Update, Delete:
+	case Insert, Update, Delete, TrialConvertedAction, TrialFellBackAction, TrialExpiredAction:
 		return true
 	default:
 		return false
@@ -43,6 +67,16 @@ func ValidatePlanType(value string) bool {
 	}
 }
 
+// ValidatePlanKind checks whether the given string matches a defined PlanKindEnum value.
+func ValidatePlanKind(value string) bool {
+	switch PlanKindEnum(value) {
+	case PlanKindBase, PlanKindAddon:
+		return true
+	default:
+		return false
+	}
+}
+
 // Plan represents a plan entity from the database
 type Plan struct {
 	Base
@@ -50,18 +84,36 @@ type Plan struct {
 	Description string       `json:"description,omitempty"`
 	Slug        string       `json:"slug"`
 	Type        PlanTypeEnum `json:"type"`
+	Kind        PlanKindEnum `json:"kind"`
 	ArchivedAt  time.Time    `json:"archived_at"`
 	TenantSlug  string       `json:"tenant_slug"`
 }
 
-// PlanAssignment represents a plan_assignment entity from the database
+// PlanVersion represents an immutable, published snapshot of the features and quotas of a plan
+type PlanVersion struct {
+	ID          uuid.UUID     `json:"id"`
+	PlanID      uuid.UUID     `json:"plan_id"`
+	Version     int           `json:"version"`
+	Description string        `json:"description,omitempty"`
+	CreatedAt   time.Time     `json:"created_at"`
+	CreatedBy   string        `json:"created_by"`
+	Features    []PlanFeature `json:"features,omitempty"`
+}
+
+// PlanAssignment represents a plan_assignment entity from the database. PendingPlanVersionID is
+// the version the next assignment of the plan to the same subject is renewed onto, and PlanKind is
+// only set where the assignment is read together with its plan.
 type PlanAssignment struct {
 	Base
-	PlanID         string    `json:"plan_id"`
-	OrganizationID string    `json:"organization_id"`
-	UserID         string    `json:"user_id"`
-	ValidFrom      time.Time `json:"valid_from"`
-	ValidUntil     time.Time `json:"valid_until"`
+	PlanID               string             `json:"plan_id"`
+	PlanKind             PlanKindEnum       `json:"plan_kind,omitempty"`
+	Kind                 PlanAssignmentKind `json:"kind"`
+	PlanVersionID        string             `json:"plan_version_id"`
+	PendingPlanVersionID string             `json:"pending_plan_version_id,omitempty"`
+	OrganizationID       string             `json:"organization_id"`
+	UserID               string             `json:"user_id"`
+	ValidFrom            time.Time          `json:"valid_from"`
+	ValidUntil           time.Time          `json:"valid_until"`
 }
 
 // PlanAssignmentHistory represents a plan_assignment_history entity from the database
@@ -83,6 +135,13 @@ type QueryPlanAssignmentInput struct {
 	ValidUntil     time.Time
 }
 
+// GetActiveAssignmentInput selects the assignments of an organization or user valid at a time
+type GetActiveAssignmentInput struct {
+	OrganizationID string
+	UserID         string
+	At             time.Time
+}
+
 type QueryPlanAssignmentHistoryInput struct {
 	PlanID           *uuid.UUID
 	Action           string
@@ -99,7 +158,21 @@ type CreatePlanInput struct {
 	Name        string
 	Slug        string
 	Type        PlanTypeEnum
+	Kind        PlanKindEnum
+	Description string
+	CreatedBy   string
+}
+
+// ClonePlanInput represents the input for cloning a plan into a new slug. Empty fields keep the
+// value of the source plan, and QuotaLimits overrides quota limits by feature slug. The clone is
+// always of the same kind as the source.
+type ClonePlanInput struct {
+	Name        string
+	Slug        string
+	Type        PlanTypeEnum
+	Kind        PlanKindEnum
 	Description string
+	QuotaLimits map[string]int64
 	CreatedBy   string
 }
 
@@ -118,9 +191,12 @@ type CreateAssignmentInput struct {
 	UserID         string
 	OrganizationID string
 	PlanID         *uuid.UUID
-	ValidFrom      time.Time
-	ValidUntil     time.Time
-	CreatedBy      string
+	// PlanVersion is the published version to assign, zero for the latest one
+	PlanVersion   int
+	PlanVersionID uuid.UUID
+	ValidFrom     time.Time
+	ValidUntil    time.Time
+	CreatedBy     string
 }
 
 type UpdateAssignmentInput struct {
@@ -138,3 +214,32 @@ type TerminateAssignmentInput struct {
 	UserID         string
 	OrganizationID string
 }
+
+// PublishPlanVersionInput represents the input for publishing the draft of a plan as a new version
+type PublishPlanVersionInput struct {
+	Description string
+	CreatedBy   string
+}
+
+// MigratePlanVersionInput selects the assignments of a plan to move to another of its versions.
+// Without subjects every assignment not yet on the target version is migrated, and with OnRenewal
+// the assignments keep their version until they are renewed.
+type MigratePlanVersionInput struct {
+	FromVersion     int
+	ToVersion       int
+	PlanID          uuid.UUID
+	FromVersionID   *uuid.UUID
+	ToVersionID     uuid.UUID
+	OrganizationIDs []string
+	UserIDs         []string
+	OnRenewal       bool
+	At              time.Time
+	UpdatedBy       string
+}
+
+// PlanVersionMigration reports the outcome of migrating assignments to a plan version
+type PlanVersionMigration struct {
+	Version     int   `json:"version"`
+	OnRenewal   bool  `json:"on_renewal"`
+	Assignments int64 `json:"assignments"`
+}
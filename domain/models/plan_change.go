@@ -0,0 +1,110 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PlanChangeType is what a scheduled plan change does to the plan of a subject
+type PlanChangeType string
+
+const (
+	// PlanChangeUpgrade switches the subject to a richer plan
+	PlanChangeUpgrade PlanChangeType = "upgrade"
+	// PlanChangeDowngrade switches the subject to a smaller plan
+	PlanChangeDowngrade PlanChangeType = "downgrade"
+	// PlanChangeCancellation ends the plan of the subject without a replacement
+	PlanChangeCancellation PlanChangeType = "cancellation"
+)
+
+// IsValidPlanChangeType returns true if the provided plan change type is supported.
+func IsValidPlanChangeType(changeType PlanChangeType) bool {
+	switch changeType {
+	case PlanChangeUpgrade, PlanChangeDowngrade, PlanChangeCancellation:
+		return true
+	default:
+		return false
+	}
+}
+
+// PlanChangeStatus is where a plan change stands at a given time
+type PlanChangeStatus string
+
+const (
+	// PlanChangePending changes take effect in the future and can still be canceled
+	PlanChangePending PlanChangeStatus = "pending"
+	// PlanChangeApplied changes have taken effect
+	PlanChangeApplied PlanChangeStatus = "applied"
+	// PlanChangeCanceled changes were canceled before taking effect
+	PlanChangeCanceled PlanChangeStatus = "canceled"
+)
+
+// IsValidPlanChangeStatus returns true if the provided plan change status is supported.
+func IsValidPlanChangeStatus(status PlanChangeStatus) bool {
+	switch status {
+	case PlanChangePending, PlanChangeApplied, PlanChangeCanceled:
+		return true
+	default:
+		return false
+	}
+}
+
+// PlanChange switches a subject from the plan of one assignment to another plan, or cancels it,
+// at EffectiveAt. The assignment it changes ends at EffectiveAt and, unless the change is a
+// cancellation, the assignment of the new plan starts then and keeps the end the changed
+// assignment had before, PreviousValidUntil.
+type PlanChange struct {
+	Base
+	TenantSlug         string           `json:"tenant_slug"`
+	Type               PlanChangeType   `json:"type"`
+	Status             PlanChangeStatus `json:"status"`
+	OrganizationID     string           `json:"organization_id,omitempty"`
+	UserID             string           `json:"user_id,omitempty"`
+	FromAssignmentID   uuid.UUID        `json:"from_assignment_id"`
+	FromPlanID         uuid.UUID        `json:"from_plan_id"`
+	ToAssignmentID     *uuid.UUID       `json:"to_assignment_id,omitempty"`
+	ToPlanID           *uuid.UUID       `json:"to_plan_id,omitempty"`
+	EffectiveAt        time.Time        `json:"effective_at"`
+	PreviousValidUntil time.Time        `json:"previous_valid_until"`
+	Reason             string           `json:"reason,omitempty"`
+	CanceledAt         *time.Time       `json:"canceled_at,omitempty"`
+	CanceledBy         string           `json:"canceled_by,omitempty"`
+}
+
+// CreatePlanChangeInput represents the input for scheduling a plan change. From is the assignment
+// being changed and To, unless the change is a cancellation, the assignment of the new plan.
+type CreatePlanChangeInput struct {
+	Type        PlanChangeType
+	From        PlanAssignment
+	To          *CreateAssignmentInput
+	EffectiveAt time.Time
+	Reason      string
+	CreatedBy   string
+}
+
+// SchedulePlanChangeInput represents a request to switch a subject from one plan to another, or
+// cancel its plan, either at EffectiveAt, at the end of the current Period or right away
+type SchedulePlanChangeInput struct {
+	Type           PlanChangeType
+	OrganizationID string
+	UserID         string
+	FromPlanID     uuid.UUID
+	ToPlanID       *uuid.UUID
+	// ToPlanVersion is the published version of the new plan to assign, zero to pin it as new assignments are
+	ToPlanVersion int
+	EffectiveAt   time.Time
+	// AtPeriodEnd schedules the change for the end of the current calendar Period
+	AtPeriodEnd bool
+	Period      MeteredResetPeriod
+	Reason      string
+	CreatedBy   string
+}
+
+// QueryPlanChangeInput selects plan changes by subject and status
+type QueryPlanChangeInput struct {
+	OrganizationID string
+	UserID         string
+	Status         PlanChangeStatus
+	At             time.Time
+}
@@ -13,6 +13,8 @@ type PlanFeature struct {
 	FeatureName string          `json:"feature_name,omitempty"`
 	FeatureSlug string          `json:"feature_slug,omitempty"`
 	Type        FeatureTypeEnum `json:"feature_type,omitempty"`
+	MeterSlugs  []string        `json:"feature_meter_slugs,omitempty"`
+	MergeRule   QuotaMergeRule  `json:"feature_merge_rule,omitempty"`
 }
 
 // CreatePlanFeatureInput represents the input for creating a new plan feature association
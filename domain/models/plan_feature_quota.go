@@ -1,5 +1,11 @@
 package models
 
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
 type MeteredResetPeriod string
 
 const (
@@ -20,27 +26,133 @@ const (
 	MeteredActionAtLimitThrottle MeteredActionAtLimit = "throttle"
 )
 
+// QuotaOverageType tells whether the overage allowance of a quota is an amount or a percentage of its limit
+type QuotaOverageType string
+
+const (
+	QuotaOverageAbsolute QuotaOverageType = "absolute"
+	QuotaOveragePercent  QuotaOverageType = "percent"
+)
+
+// IsValidQuotaOverageType returns true if the provided overage type is supported.
+func IsValidQuotaOverageType(overageType QuotaOverageType) bool {
+	switch overageType {
+	case QuotaOverageAbsolute, QuotaOveragePercent:
+		return true
+	default:
+		return false
+	}
+}
+
+// QuotaBand is where the usage of a subject stands against the limits of a quota
+type QuotaBand string
+
+const (
+	// QuotaBandWithin usage is below the soft limit, or the limit when there is none
+	QuotaBandWithin QuotaBand = "within"
+	// QuotaBandSoftLimit usage reached the soft limit: the subject is warned but allowed
+	QuotaBandSoftLimit QuotaBand = "soft_limit"
+	// QuotaBandOverage usage reached the limit but is within the overage allowance
+	QuotaBandOverage QuotaBand = "overage"
+	// QuotaBandGrace usage used up the overage allowance while the grace period still runs
+	QuotaBandGrace QuotaBand = "grace"
+	// QuotaBandExceeded usage used up the overage allowance and the action at limit applies
+	QuotaBandExceeded QuotaBand = "exceeded"
+)
+
+// PlanFeatureQuota limits the usage of a metered feature per reset period. Subjects are warned
+// from SoftLimitValue on, may go past LimitValue by the overage allowance, and ActionAtLimit only
+// applies GracePeriodMinutes after usage first used up the allowance.
 type PlanFeatureQuota struct {
 	Base
-	PlanFeatureID       string               `json:"plan_feature_id"`
-	LimitValue          int64                `json:"limit_value"`
-	ResetPeriod         MeteredResetPeriod   `json:"reset_period"`
-	CustomPeriodMinutes *int64               `json:"custom_period_minutes,omitempty"`
-	ActionAtLimit       MeteredActionAtLimit `json:"action_at_limit"`
+	PlanFeatureID        string               `json:"plan_feature_id"`
+	LimitValue           int64                `json:"limit_value"`
+	ResetPeriod          MeteredResetPeriod   `json:"reset_period"`
+	CustomPeriodMinutes  *int64               `json:"custom_period_minutes,omitempty"`
+	ActionAtLimit        MeteredActionAtLimit `json:"action_at_limit"`
+	SoftLimitValue       *int64               `json:"soft_limit_value,omitempty"`
+	OverageAllowance     *int64               `json:"overage_allowance,omitempty"`
+	OverageAllowanceType QuotaOverageType     `json:"overage_allowance_type,omitempty"`
+	GracePeriodMinutes   *int64               `json:"grace_period_minutes,omitempty"`
+}
+
+// AllowedUsage is the usage the action at limit applies from: the limit plus the overage
+// allowance, percentages of the limit rounded down.
+func (q *PlanFeatureQuota) AllowedUsage() int64 {
+	if q.OverageAllowance == nil {
+		return q.LimitValue
+	}
+	if q.OverageAllowanceType == QuotaOveragePercent {
+		return q.LimitValue + q.LimitValue**q.OverageAllowance/100
+	}
+	return q.LimitValue + *q.OverageAllowance
+}
+
+// SamePeriod returns true if both quotas reset over the same period, so that their limits can be
+// combined.
+func (q *PlanFeatureQuota) SamePeriod(other *PlanFeatureQuota) bool {
+	if q.ResetPeriod != other.ResetPeriod {
+		return false
+	}
+	if q.ResetPeriod != MeteredResetPeriodCustom {
+		return true
+	}
+	return q.CustomPeriodMinutes != nil && other.CustomPeriodMinutes != nil && *q.CustomPeriodMinutes == *other.CustomPeriodMinutes
+}
+
+// Band returns where the usage stands against the quota at now. graceEndsAt is the end of the
+// grace period that started when usage first used up the allowance, nil without one.
+func (q *PlanFeatureQuota) Band(used float64, graceEndsAt *time.Time, now time.Time) QuotaBand {
+	switch {
+	case used >= float64(q.AllowedUsage()):
+		if graceEndsAt != nil && now.Before(*graceEndsAt) {
+			return QuotaBandGrace
+		}
+		return QuotaBandExceeded
+	case used >= float64(q.LimitValue):
+		return QuotaBandOverage
+	case q.SoftLimitValue != nil && used >= float64(*q.SoftLimitValue):
+		return QuotaBandSoftLimit
+	default:
+		return QuotaBandWithin
+	}
 }
 
 type CreatePlanFeatureQuotaInput struct {
-	PlanFeatureID       string               `json:"plan_feature_id" validate:"required,uuid"`
-	LimitValue          int64                `json:"limit_value" validate:"required,gt=0"`
-	ResetPeriod         MeteredResetPeriod   `json:"reset_period" validate:"required,oneof=day week month year custom rolling never"`
-	CustomPeriodMinutes *int64               `json:"custom_period_minutes,omitempty" validate:"required_if=ResetPeriod custom,omitempty,gt=0"`
-	ActionAtLimit       MeteredActionAtLimit `json:"action_at_limit" validate:"required,oneof=none block throttle"`
+	PlanFeatureID        string               `json:"plan_feature_id" validate:"required,uuid"`
+	LimitValue           int64                `json:"limit_value" validate:"required,gt=0"`
+	ResetPeriod          MeteredResetPeriod   `json:"reset_period" validate:"required,oneof=day week month year custom rolling never"`
+	CustomPeriodMinutes  *int64               `json:"custom_period_minutes,omitempty" validate:"required_if=ResetPeriod custom,omitempty,gt=0"`
+	ActionAtLimit        MeteredActionAtLimit `json:"action_at_limit" validate:"required,oneof=none block throttle"`
+	SoftLimitValue       *int64               `json:"soft_limit_value,omitempty" validate:"omitempty,gt=0"`
+	OverageAllowance     *int64               `json:"overage_allowance,omitempty" validate:"omitempty,gt=0"`
+	OverageAllowanceType QuotaOverageType     `json:"overage_allowance_type,omitempty" validate:"omitempty,oneof=absolute percent"`
+	GracePeriodMinutes   *int64               `json:"grace_period_minutes,omitempty" validate:"omitempty,gt=0"`
 }
 
+// UpdatePlanFeatureQuotaInput represents the input for updating a quota; empty fields are
+// unchanged and the clear flags remove the soft limit, the overage allowance with its type, or
+// the grace period.
 type UpdatePlanFeatureQuotaInput struct {
-	PlanFeatureID       string               `json:"-" validate:"required,uuid"`
-	LimitValue          int64                `json:"limit_value,omitempty" validate:"omitempty,gt=0"`
-	ResetPeriod         MeteredResetPeriod   `json:"reset_period,omitempty" validate:"omitempty,oneof=day week month year custom rolling never"`
-	CustomPeriodMinutes int64                `json:"custom_period_minutes,omitempty" validate:"omitempty,gt=0"`
-	ActionAtLimit       MeteredActionAtLimit `json:"action_at_limit,omitempty" validate:"omitempty,oneof=none block throttle"`
+	PlanFeatureID           string               `json:"-" validate:"required,uuid"`
+	LimitValue              int64                `json:"limit_value,omitempty" validate:"omitempty,gt=0"`
+	ResetPeriod             MeteredResetPeriod   `json:"reset_period,omitempty" validate:"omitempty,oneof=day week month year custom rolling never"`
+	CustomPeriodMinutes     int64                `json:"custom_period_minutes,omitempty" validate:"omitempty,gt=0"`
+	ActionAtLimit           MeteredActionAtLimit `json:"action_at_limit,omitempty" validate:"omitempty,oneof=none block throttle"`
+	SoftLimitValue          *int64               `json:"soft_limit_value,omitempty" validate:"omitempty,gt=0"`
+	OverageAllowance        *int64               `json:"overage_allowance,omitempty" validate:"omitempty,gt=0"`
+	OverageAllowanceType    QuotaOverageType     `json:"overage_allowance_type,omitempty" validate:"omitempty,oneof=absolute percent"`
+	GracePeriodMinutes      *int64               `json:"grace_period_minutes,omitempty" validate:"omitempty,gt=0"`
+	ClearSoftLimitValue     bool                 `json:"clear_soft_limit_value,omitempty"`
+	ClearOverageAllowance   bool                 `json:"clear_overage_allowance,omitempty"`
+	ClearGracePeriodMinutes bool                 `json:"clear_grace_period_minutes,omitempty"`
+}
+
+// QuotaGraceState records when a subject used up the overage allowance of the quota of a feature.
+// The grace period of the quota runs from it while it falls within the current period.
+type QuotaGraceState struct {
+	SubjectType       SubjectType
+	SubjectID         string
+	FeatureID         uuid.UUID
+	AllowanceUsedUpAt time.Time
 }
@@ -0,0 +1,38 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPlanFeatureQuotaBand(t *testing.T) {
+	now := time.Date(2025, 6, 18, 12, 0, 0, 0, time.UTC)
+	soft, absolute, percent := int64(80), int64(50), int64(10)
+	later, earlier := now.Add(time.Hour), now.Add(-time.Hour)
+
+	tests := []struct {
+		name        string
+		quota       PlanFeatureQuota
+		used        float64
+		graceEndsAt *time.Time
+		allowed     int64
+		band        QuotaBand
+	}{
+		{"within", PlanFeatureQuota{LimitValue: 100, SoftLimitValue: &soft}, 79, nil, 100, QuotaBandWithin},
+		{"soft limit", PlanFeatureQuota{LimitValue: 100, SoftLimitValue: &soft}, 80, nil, 100, QuotaBandSoftLimit},
+		{"limit without allowance", PlanFeatureQuota{LimitValue: 100, SoftLimitValue: &soft}, 100, nil, 100, QuotaBandExceeded},
+		{"absolute overage", PlanFeatureQuota{LimitValue: 100, OverageAllowance: &absolute, OverageAllowanceType: QuotaOverageAbsolute}, 149, nil, 150, QuotaBandOverage},
+		{"percent overage", PlanFeatureQuota{LimitValue: 100, OverageAllowance: &percent, OverageAllowanceType: QuotaOveragePercent}, 105, nil, 110, QuotaBandOverage},
+		{"allowance used up", PlanFeatureQuota{LimitValue: 100, OverageAllowance: &percent, OverageAllowanceType: QuotaOveragePercent}, 110, nil, 110, QuotaBandExceeded},
+		{"grace", PlanFeatureQuota{LimitValue: 100}, 120, &later, 100, QuotaBandGrace},
+		{"grace over", PlanFeatureQuota{LimitValue: 100}, 120, &earlier, 100, QuotaBandExceeded},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.allowed, tt.quota.AllowedUsage())
+			assert.Equal(t, tt.band, tt.quota.Band(tt.used, tt.graceEndsAt, now))
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TrialEndAction is what happens to a subject when its trial ends
+type TrialEndAction string
+
+const (
+	// TrialEndActionConvert moves the subject onto the target plan, or the fallback plan if the
+	// target can no longer be assigned
+	TrialEndActionConvert TrialEndAction = "convert"
+	// TrialEndActionFallback moves the subject onto the fallback plan, usually a free one
+	TrialEndActionFallback TrialEndAction = "fallback"
+	// TrialEndActionExpire lets the trial run out without a replacement
+	TrialEndActionExpire TrialEndAction = "expire"
+)
+
+// IsValidTrialEndAction returns true if the provided trial end action is supported.
+func IsValidTrialEndAction(action TrialEndAction) bool {
+	switch action {
+	case TrialEndActionConvert, TrialEndActionFallback, TrialEndActionExpire:
+		return true
+	default:
+		return false
+	}
+}
+
+// TrialStatus is where a trial stands, the outcome once it has ended
+type TrialStatus string
+
+const (
+	// TrialActive trials have not been ended yet
+	TrialActive TrialStatus = "active"
+	// TrialConverted trials moved the subject onto the target plan
+	TrialConverted TrialStatus = "converted"
+	// TrialFellBack trials moved the subject onto the fallback plan
+	TrialFellBack TrialStatus = "fell_back"
+	// TrialExpired trials ended without a replacement
+	TrialExpired TrialStatus = "expired"
+)
+
+// IsValidTrialStatus returns true if the provided trial status is supported.
+func IsValidTrialStatus(status TrialStatus) bool {
+	switch status {
+	case TrialActive, TrialConverted, TrialFellBack, TrialExpired:
+		return true
+	default:
+		return false
+	}
+}
+
+// Trial is a trial assignment of PlanID that runs from StartsAt to EndsAt. When it ends the subject
+// is moved onto TargetPlanID or FallbackPlanID, as EndAction says, and ResultingAssignmentID is the
+// assignment it was moved onto.
+type Trial struct {
+	Base
+	TenantSlug            string         `json:"tenant_slug"`
+	AssignmentID          uuid.UUID      `json:"assignment_id"`
+	PlanID                uuid.UUID      `json:"plan_id"`
+	OrganizationID        string         `json:"organization_id,omitempty"`
+	UserID                string         `json:"user_id,omitempty"`
+	TargetPlanID          uuid.UUID      `json:"target_plan_id"`
+	FallbackPlanID        *uuid.UUID     `json:"fallback_plan_id,omitempty"`
+	EndAction             TrialEndAction `json:"end_action"`
+	Status                TrialStatus    `json:"status"`
+	StartsAt              time.Time      `json:"starts_at"`
+	EndsAt                time.Time      `json:"ends_at"`
+	DaysRemaining         int            `json:"days_remaining"`
+	ResultingAssignmentID *uuid.UUID     `json:"resulting_assignment_id,omitempty"`
+	EndedAt               *time.Time     `json:"ended_at,omitempty"`
+}
+
+// CreateTrialInput represents the input for starting a trial of a plan that lasts TrialDays
+type CreateTrialInput struct {
+	Assignment     CreateAssignmentInput
+	TrialDays      int
+	TargetPlanID   *uuid.UUID
+	FallbackPlanID *uuid.UUID
+	EndAction      TrialEndAction
+}
+
+// QueryTrialInput selects trials by subject and status
+type QueryTrialInput struct {
+	OrganizationID string
+	UserID         string
+	Status         TrialStatus
+}
+
+// EndTrialInput represents the outcome of a trial that ended at At. To is the assignment the
+// subject is moved onto, nil when the trial expires.
+type EndTrialInput struct {
+	Trial   Trial
+	Status  TrialStatus
+	To      *CreateAssignmentInput
+	At      time.Time
+	EndedBy string
+}
@@ -0,0 +1,102 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SubjectType is the kind of subject a plan is assigned to
+type SubjectType string
+
+const (
+	SubjectTypeOrganization SubjectType = "organization"
+	SubjectTypeUser         SubjectType = "user"
+)
+
+// IsValidSubjectType returns true if the provided subject type is supported.
+func IsValidSubjectType(subjectType SubjectType) bool {
+	switch subjectType {
+	case SubjectTypeOrganization, SubjectTypeUser:
+		return true
+	default:
+		return false
+	}
+}
+
+// SubjectUsage summarizes the usage of every metered feature on the active plans of a subject.
+// PlanID is the base plan, empty for subjects holding add-ons only.
+type SubjectUsage struct {
+	SubjectType  SubjectType    `json:"subject_type"`
+	SubjectID    string         `json:"subject_id"`
+	PlanID       string         `json:"plan_id,omitempty"`
+	AddOnPlanIDs []string       `json:"addon_plan_ids,omitempty"`
+	Features     []FeatureUsage `json:"features"`
+}
+
+// SubjectEntitlements are the features a subject is entitled to, merged across its base plan and
+// the add-ons stacked on top of it, with the overrides of the subject applied last
+type SubjectEntitlements struct {
+	SubjectType SubjectType           `json:"subject_type"`
+	SubjectID   string                `json:"subject_id"`
+	Assignments []PlanAssignment      `json:"assignments"`
+	Overrides   []EntitlementOverride `json:"overrides"`
+	Features    []Entitlement         `json:"features"`
+}
+
+// Entitlement is a feature granted by one or more of the active plans of a subject. Quota holds
+// the merged limit, with the reset period and action of the first plan granting the feature, and
+// is nil when any of the plans grants the feature without a quota. Config merges the plan
+// configurations, add-ons overriding the base plan. Metered features with a quota report the usage
+// over the current period and the band it falls in.
+type Entitlement struct {
+	FeatureID   uuid.UUID         `json:"feature_id"`
+	FeatureSlug string            `json:"feature_slug"`
+	FeatureName string            `json:"feature_name"`
+	Type        FeatureTypeEnum   `json:"type"`
+	MeterSlugs  []string          `json:"meter_slugs,omitempty"`
+	Config      map[string]any    `json:"config,omitempty"`
+	MergeRule   QuotaMergeRule    `json:"merge_rule"`
+	Quota       *PlanFeatureQuota `json:"quota,omitempty"`
+	Used        *float64          `json:"used,omitempty"`
+	Band        QuotaBand         `json:"band,omitempty"`
+	// GraceEndsAt is when the grace period started by using up the overage allowance ends
+	GraceEndsAt *time.Time          `json:"grace_ends_at,omitempty"`
+	Sources     []EntitlementSource `json:"sources"`
+}
+
+// EntitlementSource is an assigned plan or an override granting an entitlement, with its own
+// limit if it sets one. GrantedBy is the last actor to change the override. PeriodConflict marks a
+// plan whose quota resets over another period than the entitlement; its limit is not counted.
+type EntitlementSource struct {
+	PlanID         string       `json:"plan_id,omitempty"`
+	PlanVersionID  string       `json:"plan_version_id,omitempty"`
+	PlanKind       PlanKindEnum `json:"plan_kind,omitempty"`
+	OverrideID     string       `json:"override_id,omitempty"`
+	GrantedBy      string       `json:"granted_by,omitempty"`
+	Limit          *int64       `json:"limit,omitempty"`
+	PeriodConflict bool         `json:"period_conflict,omitempty"`
+}
+
+// FeatureUsage is the usage of a metered feature over its current reset period. Limit, Percentage
+// and Band are omitted for features without a quota. AllowedUsage is the limit plus the overage
+// allowance, the usage the action at limit applies from once any grace period has run out.
+type FeatureUsage struct {
+	FeatureID     uuid.UUID            `json:"feature_id"`
+	FeatureSlug   string               `json:"feature_slug"`
+	FeatureName   string               `json:"feature_name"`
+	MeterSlugs    []string             `json:"meter_slugs"`
+	Used          float64              `json:"used"`
+	Limit         *int64               `json:"limit,omitempty"`
+	SoftLimit     *int64               `json:"soft_limit,omitempty"`
+	AllowedUsage  *int64               `json:"allowed_usage,omitempty"`
+	Percentage    *float64             `json:"percentage,omitempty"`
+	Band          QuotaBand            `json:"band,omitempty"`
+	ResetPeriod   MeteredResetPeriod   `json:"reset_period,omitempty"`
+	ActionAtLimit MeteredActionAtLimit `json:"action_at_limit,omitempty"`
+	PeriodStart   time.Time            `json:"period_start"`
+	// PeriodEnd is when usage resets; omitted for periods that never reset
+	PeriodEnd *time.Time `json:"period_end,omitempty"`
+	// GraceEndsAt is when the grace period started by using up the overage allowance ends
+	GraceEndsAt *time.Time `json:"grace_ends_at,omitempty"`
+}
@@ -0,0 +1,121 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// WebhookEndpoint is a URL notifications of the subscribed types are delivered to. Deliveries
+// are signed with the secret of the endpoint, which is never encoded with it.
+type WebhookEndpoint struct {
+	Base
+	TenantSlug  string             `json:"tenant_slug"`
+	URL         string             `json:"url"`
+	Description string             `json:"description,omitempty"`
+	Secret      string             `json:"-"`
+	EventTypes  []NotificationType `json:"event_types"`
+	Enabled     bool               `json:"enabled"`
+}
+
+// WebhookEndpointWithSecret is a webhook endpoint together with its secret, returned only when
+// the secret is set on creation or rotation.
+type WebhookEndpointWithSecret struct {
+	WebhookEndpoint
+	Secret string `json:"secret"`
+}
+
+// NewWebhookEndpointWithSecret reveals the secret of an endpoint.
+func NewWebhookEndpointWithSecret(endpoint *WebhookEndpoint) *WebhookEndpointWithSecret {
+	return &WebhookEndpointWithSecret{WebhookEndpoint: *endpoint, Secret: endpoint.Secret}
+}
+
+// CreateWebhookEndpointInput represents the input for creating a webhook endpoint. A secret is
+// generated when none is given.
+type CreateWebhookEndpointInput struct {
+	URL         string
+	Description string
+	Secret      string
+	EventTypes  []NotificationType
+	Enabled     bool
+	CreatedBy   string
+}
+
+// UpdateWebhookEndpointInput represents the input for updating a webhook endpoint; nil fields are unchanged.
+type UpdateWebhookEndpointInput struct {
+	URL         *string
+	Description *string
+	Secret      *string
+	EventTypes  []NotificationType
+	Enabled     *bool
+	UpdatedBy   string
+}
+
+// WebhookDeliveryStatus is the state of the delivery of a notification to an endpoint
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryPending   WebhookDeliveryStatus = "pending"
+	WebhookDeliverySucceeded WebhookDeliveryStatus = "succeeded"
+	WebhookDeliveryFailed    WebhookDeliveryStatus = "failed"
+)
+
+// WebhookDelivery is a notification queued for delivery to an endpoint. Pending deliveries are
+// attempted once NextAttemptAt has passed.
+type WebhookDelivery struct {
+	ID            uuid.UUID             `json:"id"`
+	EndpointID    uuid.UUID             `json:"endpoint_id"`
+	TenantSlug    string                `json:"tenant_slug"`
+	EventID       uuid.UUID             `json:"event_id"`
+	EventType     NotificationType      `json:"event_type"`
+	Payload       json.RawMessage       `json:"payload" swaggertype:"object"`
+	Status        WebhookDeliveryStatus `json:"status"`
+	Attempts      int                   `json:"attempts"`
+	NextAttemptAt time.Time             `json:"next_attempt_at"`
+	LastAttemptAt *time.Time            `json:"last_attempt_at,omitempty"`
+	LastError     string                `json:"last_error,omitempty"`
+	CreatedAt     time.Time             `json:"created_at"`
+	UpdatedAt     time.Time             `json:"updated_at"`
+	// Log holds the attempts of the delivery when it is fetched on its own
+	Log []WebhookDeliveryAttempt `json:"log,omitempty"`
+}
+
+// CreateWebhookDeliveryInput represents the input for queueing a notification for an endpoint
+type CreateWebhookDeliveryInput struct {
+	EndpointID uuid.UUID
+	EventID    uuid.UUID
+	EventType  NotificationType
+	Payload    json.RawMessage
+}
+
+// WebhookDeliveryAttempt records a single request made for a delivery
+type WebhookDeliveryAttempt struct {
+	Attempt        int       `json:"attempt"`
+	ResponseStatus *int      `json:"response_status,omitempty"`
+	ResponseBody   string    `json:"response_body,omitempty"`
+	Error          string    `json:"error,omitempty"`
+	DurationMs     int64     `json:"duration_ms"`
+	AttemptedAt    time.Time `json:"attempted_at"`
+}
+
+// RecordWebhookAttemptInput represents the outcome of an attempt and the resulting state of its delivery
+type RecordWebhookAttemptInput struct {
+	DeliveryID    uuid.UUID
+	Attempt       WebhookDeliveryAttempt
+	Status        WebhookDeliveryStatus
+	NextAttemptAt time.Time
+}
+
+// WebhookRequest is a signed request delivering a payload to an endpoint
+type WebhookRequest struct {
+	URL     string
+	Headers map[string]string
+	Body    []byte
+}
+
+// WebhookResponse is the response of an endpoint to a webhook request
+type WebhookResponse struct {
+	StatusCode int
+	Body       string
+}
@@ -0,0 +1,24 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookEndpointSecretEncoding(t *testing.T) {
+	endpoint := &WebhookEndpoint{URL: "https://example.com/hooks", Secret: "whsec_abc"}
+
+	encoded, err := json.Marshal(endpoint)
+	require.NoError(t, err)
+	assert.NotContains(t, string(encoded), "whsec_abc")
+
+	encoded, err = json.Marshal(NewWebhookEndpointWithSecret(endpoint))
+	require.NoError(t, err)
+	var decoded map[string]any
+	require.NoError(t, json.Unmarshal(encoded, &decoded))
+	assert.Equal(t, "whsec_abc", decoded["secret"])
+	assert.Equal(t, "https://example.com/hooks", decoded["url"])
+}
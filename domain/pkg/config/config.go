@@ -3,6 +3,7 @@ package config
 import (
 	"fmt"
 	"log"
+	"time"
 
 	"github.com/spf13/viper"
 )
@@ -55,12 +56,74 @@ type KafkaConfig struct {
 	KafkaRetryBackoffMs   int
 }
 
+type QueryCacheConfig struct {
+	// MaxEntries of zero disables query caching
+	MaxEntries int
+	TTL        time.Duration
+	// SettleDelay is how long after a window closes its events are still expected to arrive
+	SettleDelay time.Duration
+	// IngestDelay is how long published events take to become queryable; results covering late
+	// events are not cached in the meantime
+	IngestDelay time.Duration
+}
+
+type MeterReconcilerConfig struct {
+	// Interval between reconciliation passes; zero disables the reconciler
+	Interval time.Duration
+	// GracePeriod leaves recently updated meters to the request that is still handling them
+	GracePeriod time.Duration
+	// MaxAttempts is the number of failed passes after which a meter is marked failed
+	MaxAttempts int
+	BatchSize   int
+}
+
+type MeterStreamConfig struct {
+	// Interval between queries of each live meter stream
+	Interval time.Duration
+	// Heartbeat is how often idle connections receive a keep-alive comment
+	Heartbeat time.Duration
+	// MaxSubscribers bounds the number of concurrently connected stream clients
+	MaxSubscribers int
+}
+
+type AlertEvaluatorConfig struct {
+	// Interval between evaluations of every enabled alert rule; zero disables the evaluator
+	Interval time.Duration
+}
+
+type TrialProcessorConfig struct {
+	// Interval between passes over the trials that ran out; zero disables the processor
+	Interval  time.Duration
+	BatchSize int
+}
+
+type WebhookDispatcherConfig struct {
+	// Interval between delivery passes; zero disables the dispatcher
+	Interval  time.Duration
+	BatchSize int
+	// MaxAttempts is the number of failed attempts after which a delivery is marked failed
+	MaxAttempts int
+	// InitialBackoff is the delay before the first retry; it doubles with every attempt up to MaxBackoff
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	// Timeout bounds a single delivery request
+	Timeout time.Duration
+	// AllowPrivateNetworks lets endpoints resolve to loopback, private and link-local addresses
+	AllowPrivateNetworks bool
+}
+
 type Config struct {
 	Server     ServerConfig
 	Logger     LoggerConfig
 	Kafka      KafkaConfig
 	Postgres   StoreConfig
 	ClickHouse OlapConfig
+	QueryCache QueryCacheConfig
+	Reconciler MeterReconcilerConfig
+	Stream     MeterStreamConfig
+	Alerts     AlertEvaluatorConfig
+	Trials     TrialProcessorConfig
+	Webhooks   WebhookDispatcherConfig
 }
 
 func initializeViper() error {
@@ -90,6 +153,27 @@ func setDefaults() {
 	viper.SetDefault("RCMETERING_KAFKA_QUEUE_SIZE", 1000)
 	viper.SetDefault("RCMETERING_KAFKA_MAX_RETRIES", 3)
 	viper.SetDefault("RCMETERING_KAFKA_RETRY_BACKOFF_MS", 100)
+	viper.SetDefault("RCMETERING_QUERY_CACHE_MAX_ENTRIES", 10000)
+	viper.SetDefault("RCMETERING_QUERY_CACHE_TTL", "24h")
+	viper.SetDefault("RCMETERING_QUERY_CACHE_SETTLE_DELAY", "5m")
+	viper.SetDefault("RCMETERING_QUERY_CACHE_INGEST_DELAY", "2m")
+	viper.SetDefault("RCMETERING_METER_RECONCILER_INTERVAL", "1m")
+	viper.SetDefault("RCMETERING_METER_RECONCILER_GRACE_PERIOD", "5m")
+	viper.SetDefault("RCMETERING_METER_RECONCILER_MAX_ATTEMPTS", 5)
+	viper.SetDefault("RCMETERING_METER_RECONCILER_BATCH_SIZE", 100)
+	viper.SetDefault("RCMETERING_METER_STREAM_INTERVAL", "5s")
+	viper.SetDefault("RCMETERING_METER_STREAM_HEARTBEAT", "15s")
+	viper.SetDefault("RCMETERING_METER_STREAM_MAX_SUBSCRIBERS", 1000)
+	viper.SetDefault("RCMETERING_ALERT_EVALUATOR_INTERVAL", "1m")
+	viper.SetDefault("RCMETERING_TRIAL_PROCESSOR_INTERVAL", "1m")
+	viper.SetDefault("RCMETERING_TRIAL_PROCESSOR_BATCH_SIZE", 100)
+	viper.SetDefault("RCMETERING_WEBHOOK_DISPATCHER_INTERVAL", "10s")
+	viper.SetDefault("RCMETERING_WEBHOOK_DISPATCHER_BATCH_SIZE", 50)
+	viper.SetDefault("RCMETERING_WEBHOOK_MAX_ATTEMPTS", 8)
+	viper.SetDefault("RCMETERING_WEBHOOK_INITIAL_BACKOFF", "30s")
+	viper.SetDefault("RCMETERING_WEBHOOK_MAX_BACKOFF", "6h")
+	viper.SetDefault("RCMETERING_WEBHOOK_TIMEOUT", "10s")
+	viper.SetDefault("RCMETERING_WEBHOOK_ALLOW_PRIVATE_NETWORKS", false)
 }
 
 func validateConfig(config *Config) error {
@@ -166,6 +250,39 @@ func LoadConfig() (*Config, error) {
 			User:     viper.GetString("RCMETERING_CLICKHOUSE_USER"),
 			Password: viper.GetString("RCMETERING_CLICKHOUSE_PASSWORD"),
 		},
+		QueryCache: QueryCacheConfig{
+			MaxEntries:  viper.GetInt("RCMETERING_QUERY_CACHE_MAX_ENTRIES"),
+			TTL:         viper.GetDuration("RCMETERING_QUERY_CACHE_TTL"),
+			SettleDelay: viper.GetDuration("RCMETERING_QUERY_CACHE_SETTLE_DELAY"),
+			IngestDelay: viper.GetDuration("RCMETERING_QUERY_CACHE_INGEST_DELAY"),
+		},
+		Reconciler: MeterReconcilerConfig{
+			Interval:    viper.GetDuration("RCMETERING_METER_RECONCILER_INTERVAL"),
+			GracePeriod: viper.GetDuration("RCMETERING_METER_RECONCILER_GRACE_PERIOD"),
+			MaxAttempts: viper.GetInt("RCMETERING_METER_RECONCILER_MAX_ATTEMPTS"),
+			BatchSize:   viper.GetInt("RCMETERING_METER_RECONCILER_BATCH_SIZE"),
+		},
+		Stream: MeterStreamConfig{
+			Interval:       viper.GetDuration("RCMETERING_METER_STREAM_INTERVAL"),
+			Heartbeat:      viper.GetDuration("RCMETERING_METER_STREAM_HEARTBEAT"),
+			MaxSubscribers: viper.GetInt("RCMETERING_METER_STREAM_MAX_SUBSCRIBERS"),
+		},
+		Alerts: AlertEvaluatorConfig{
+			Interval: viper.GetDuration("RCMETERING_ALERT_EVALUATOR_INTERVAL"),
+		},
+		Trials: TrialProcessorConfig{
+			Interval:  viper.GetDuration("RCMETERING_TRIAL_PROCESSOR_INTERVAL"),
+			BatchSize: viper.GetInt("RCMETERING_TRIAL_PROCESSOR_BATCH_SIZE"),
+		},
+		Webhooks: WebhookDispatcherConfig{
+			Interval:             viper.GetDuration("RCMETERING_WEBHOOK_DISPATCHER_INTERVAL"),
+			BatchSize:            viper.GetInt("RCMETERING_WEBHOOK_DISPATCHER_BATCH_SIZE"),
+			MaxAttempts:          viper.GetInt("RCMETERING_WEBHOOK_MAX_ATTEMPTS"),
+			InitialBackoff:       viper.GetDuration("RCMETERING_WEBHOOK_INITIAL_BACKOFF"),
+			MaxBackoff:           viper.GetDuration("RCMETERING_WEBHOOK_MAX_BACKOFF"),
+			Timeout:              viper.GetDuration("RCMETERING_WEBHOOK_TIMEOUT"),
+			AllowPrivateNetworks: viper.GetBool("RCMETERING_WEBHOOK_ALLOW_PRIVATE_NETWORKS"),
+		},
 	}
 
 	if err := validateConfig(config); err != nil {
@@ -0,0 +1,160 @@
+// Package export encodes meter query rows as CSV, NDJSON or Parquet files one row at a time,
+// so that exports can be streamed without holding the full result in memory.
+package export
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/redcardinal-io/metering/domain/models"
+	"github.com/xitongsys/parquet-go/parquet"
+	"github.com/xitongsys/parquet-go/writer"
+)
+
+// parquetRowGroupSize bounds how much data the Parquet encoder buffers before flushing a row group
+const parquetRowGroupSize = 16 * 1024 * 1024
+
+// Encoder writes meter query rows to an underlying writer
+type Encoder interface {
+	Encode(row models.QueryMeterRow) error
+	// Close flushes buffered data and writes any trailing metadata. It does not close the underlying writer.
+	Close() error
+}
+
+// Layout describes the columns of an export
+type Layout struct {
+	GroupBy []string
+	Others  bool
+}
+
+// ContentType returns the MIME type of an export format.
+func ContentType(format models.ExportFormat) string {
+	switch format {
+	case models.ExportFormatNDJSON:
+		return "application/x-ndjson"
+	case models.ExportFormatParquet:
+		return "application/vnd.apache.parquet"
+	default:
+		return "text/csv"
+	}
+}
+
+// NewEncoder returns an encoder writing rows in the given format.
+func NewEncoder(format models.ExportFormat, w io.Writer, layout Layout) (Encoder, error) {
+	switch format {
+	case models.ExportFormatCSV:
+		return newCSVEncoder(w, layout)
+	case models.ExportFormatNDJSON:
+		return &ndjsonEncoder{enc: json.NewEncoder(w)}, nil
+	case models.ExportFormatParquet:
+		return newParquetEncoder(w, layout)
+	default:
+		return nil, fmt.Errorf("unsupported export format: %s", format)
+	}
+}
+
+type csvEncoder struct {
+	w      *csv.Writer
+	layout Layout
+	record []string
+}
+
+func newCSVEncoder(w io.Writer, layout Layout) (*csvEncoder, error) {
+	header := append([]string{"window_start", "window_end"}, layout.GroupBy...)
+	header = append(header, "value")
+	if layout.Others {
+		header = append(header, "others")
+	}
+
+	e := &csvEncoder{w: csv.NewWriter(w), layout: layout, record: make([]string, len(header))}
+	if err := e.w.Write(header); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+func (e *csvEncoder) Encode(row models.QueryMeterRow) error {
+	e.record = e.record[:0]
+	e.record = append(e.record, row.WindowStart.UTC().Format(time.RFC3339), row.WindowEnd.UTC().Format(time.RFC3339))
+	for _, column := range e.layout.GroupBy {
+		e.record = append(e.record, row.GroupBy[column])
+	}
+	value := ""
+	if !row.Null {
+		value = strconv.FormatFloat(row.Value, 'f', -1, 64)
+	}
+	e.record = append(e.record, value)
+	if e.layout.Others {
+		e.record = append(e.record, strconv.FormatBool(row.Others))
+	}
+	return e.w.Write(e.record)
+}
+
+func (e *csvEncoder) Close() error {
+	e.w.Flush()
+	return e.w.Error()
+}
+
+type ndjsonEncoder struct {
+	enc *json.Encoder
+}
+
+func (e *ndjsonEncoder) Encode(row models.QueryMeterRow) error {
+	return e.enc.Encode(row)
+}
+
+func (e *ndjsonEncoder) Close() error {
+	return nil
+}
+
+type parquetEncoder struct {
+	w      *writer.CSVWriter
+	layout Layout
+}
+
+func newParquetEncoder(w io.Writer, layout Layout) (*parquetEncoder, error) {
+	schema := []string{
+		"name=window_start, type=INT64, convertedtype=TIMESTAMP_MILLIS",
+		"name=window_end, type=INT64, convertedtype=TIMESTAMP_MILLIS",
+	}
+	for _, column := range layout.GroupBy {
+		schema = append(schema, fmt.Sprintf("name=%s, type=BYTE_ARRAY, convertedtype=UTF8", column))
+	}
+	schema = append(schema, "name=value, type=DOUBLE, repetitiontype=OPTIONAL")
+	if layout.Others {
+		schema = append(schema, "name=others, type=BOOLEAN")
+	}
+
+	pw, err := writer.NewCSVWriterFromWriter(schema, w, 1)
+	if err != nil {
+		return nil, err
+	}
+	pw.RowGroupSize = parquetRowGroupSize
+	pw.CompressionType = parquet.CompressionCodec_SNAPPY
+	return &parquetEncoder{w: pw, layout: layout}, nil
+}
+
+func (e *parquetEncoder) Encode(row models.QueryMeterRow) error {
+	record := make([]any, 0, len(e.layout.GroupBy)+4)
+	record = append(record, row.WindowStart.UnixMilli(), row.WindowEnd.UnixMilli())
+	for _, column := range e.layout.GroupBy {
+		record = append(record, row.GroupBy[column])
+	}
+	if row.Null {
+		record = append(record, nil)
+	} else {
+		record = append(record, row.Value)
+	}
+	if e.layout.Others {
+		record = append(record, row.Others)
+	}
+	return e.w.Write(record)
+}
+
+func (e *parquetEncoder) Close() error {
+	return e.w.WriteStop()
+}
@@ -0,0 +1,75 @@
+package export
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xitongsys/parquet-go-source/buffer"
+	"github.com/xitongsys/parquet-go/reader"
+
+	"github.com/redcardinal-io/metering/domain/models"
+)
+
+func exportRows() []models.QueryMeterRow {
+	start := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	return []models.QueryMeterRow{
+		{WindowStart: start, WindowEnd: start.Add(time.Hour), Value: 1.5, GroupBy: map[string]string{"organization": "org-a"}},
+		{WindowStart: start, WindowEnd: start.Add(time.Hour), Null: true, Filled: true, GroupBy: map[string]string{"organization": "org-b"}},
+		{WindowStart: start, WindowEnd: start.Add(time.Hour), Value: 7, Others: true, GroupBy: map[string]string{}},
+	}
+}
+
+func encodeAll(t *testing.T, format models.ExportFormat, layout Layout) []byte {
+	var buf bytes.Buffer
+	enc, err := NewEncoder(format, &buf, layout)
+	require.NoError(t, err)
+	for _, row := range exportRows() {
+		require.NoError(t, enc.Encode(row))
+	}
+	require.NoError(t, enc.Close())
+	return buf.Bytes()
+}
+
+func TestEncoders(t *testing.T) {
+	layout := Layout{GroupBy: []string{"organization"}, Others: true}
+
+	t.Run("CSV", func(t *testing.T) {
+		out := string(encodeAll(t, models.ExportFormatCSV, layout))
+		assert.Equal(t, strings.Join([]string{
+			"window_start,window_end,organization,value,others",
+			"2025-06-01T00:00:00Z,2025-06-01T01:00:00Z,org-a,1.5,false",
+			"2025-06-01T00:00:00Z,2025-06-01T01:00:00Z,org-b,,false",
+			"2025-06-01T00:00:00Z,2025-06-01T01:00:00Z,,7,true",
+		}, "\n")+"\n", out)
+	})
+
+	t.Run("NDJSON", func(t *testing.T) {
+		lines := strings.Split(strings.TrimSpace(string(encodeAll(t, models.ExportFormatNDJSON, layout))), "\n")
+		require.Len(t, lines, 3)
+		assert.Contains(t, lines[0], `"value":1.5`)
+		assert.Contains(t, lines[1], `"value":null`)
+		assert.Contains(t, lines[2], `"others":true`)
+	})
+
+	t.Run("Parquet", func(t *testing.T) {
+		out := encodeAll(t, models.ExportFormatParquet, layout)
+		assert.Equal(t, "PAR1", string(out[:4]))
+		assert.Equal(t, "PAR1", string(out[len(out)-4:]))
+
+		file, err := buffer.NewBufferFile(out)
+		require.NoError(t, err)
+		pr, err := reader.NewParquetColumnReader(file, 1)
+		require.NoError(t, err)
+		defer pr.ReadStop()
+		assert.Equal(t, int64(3), pr.GetNumRows())
+	})
+
+	t.Run("Unsupported format", func(t *testing.T) {
+		_, err := NewEncoder("xlsx", &bytes.Buffer{}, layout)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,219 @@
+// Package formula parses and evaluates arithmetic expressions over named variables,
+// such as the definitions of derived meters ("errors / requests").
+package formula
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Expr is a parsed arithmetic expression
+type Expr struct {
+	source string
+	root   node
+}
+
+type node interface {
+	eval(vars map[string]float64) float64
+	collect(names map[string]struct{})
+}
+
+type number float64
+
+func (n number) eval(map[string]float64) float64 { return float64(n) }
+func (n number) collect(map[string]struct{})     {}
+
+type variable string
+
+func (v variable) eval(vars map[string]float64) float64 { return vars[string(v)] }
+func (v variable) collect(names map[string]struct{})    { names[string(v)] = struct{}{} }
+
+type negate struct{ operand node }
+
+func (n negate) eval(vars map[string]float64) float64 { return -n.operand.eval(vars) }
+func (n negate) collect(names map[string]struct{})    { n.operand.collect(names) }
+
+type binary struct {
+	op          byte
+	left, right node
+}
+
+// eval treats division by zero as zero so that ratios over empty windows stay finite.
+func (b binary) eval(vars map[string]float64) float64 {
+	l, r := b.left.eval(vars), b.right.eval(vars)
+	switch b.op {
+	case '+':
+		return l + r
+	case '-':
+		return l - r
+	case '*':
+		return l * r
+	default:
+		if r == 0 {
+			return 0
+		}
+		return l / r
+	}
+}
+
+func (b binary) collect(names map[string]struct{}) {
+	b.left.collect(names)
+	b.right.collect(names)
+}
+
+// Parse parses an expression made of numbers, variables, + - * /, unary minus and parentheses.
+// Variable names start with a letter or underscore and contain letters, digits and underscores.
+// Names containing other characters, such as the slug of a meter "api-calls", are written between
+// brackets: "[api-calls] / requests".
+func Parse(source string) (*Expr, error) {
+	p := &parser{input: source}
+	root, err := p.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpaces()
+	if p.pos < len(p.input) {
+		return nil, fmt.Errorf("unexpected %q at position %d", p.input[p.pos], p.pos)
+	}
+	return &Expr{source: source, root: root}, nil
+}
+
+// String returns the source the expression was parsed from.
+func (e *Expr) String() string {
+	return e.source
+}
+
+// Variables returns the sorted, de-duplicated variable names referenced by the expression.
+func (e *Expr) Variables() []string {
+	names := make(map[string]struct{})
+	e.root.collect(names)
+	result := make([]string, 0, len(names))
+	for name := range names {
+		result = append(result, name)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// Eval evaluates the expression. Variables missing from vars evaluate to zero.
+func (e *Expr) Eval(vars map[string]float64) float64 {
+	return e.root.eval(vars)
+}
+
+type parser struct {
+	input string
+	pos   int
+}
+
+func (p *parser) skipSpaces() {
+	for p.pos < len(p.input) && unicode.IsSpace(rune(p.input[p.pos])) {
+		p.pos++
+	}
+}
+
+func (p *parser) peek() byte {
+	p.skipSpaces()
+	if p.pos >= len(p.input) {
+		return 0
+	}
+	return p.input[p.pos]
+}
+
+// parseExpr parses terms separated by + and -.
+func (p *parser) parseExpr() (node, error) {
+	left, err := p.parseTerm()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op := p.peek()
+		if op != '+' && op != '-' {
+			return left, nil
+		}
+		p.pos++
+		right, err := p.parseTerm()
+		if err != nil {
+			return nil, err
+		}
+		left = binary{op: op, left: left, right: right}
+	}
+}
+
+// parseTerm parses factors separated by * and /.
+func (p *parser) parseTerm() (node, error) {
+	left, err := p.parseFactor()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op := p.peek()
+		if op != '*' && op != '/' {
+			return left, nil
+		}
+		p.pos++
+		right, err := p.parseFactor()
+		if err != nil {
+			return nil, err
+		}
+		left = binary{op: op, left: left, right: right}
+	}
+}
+
+func (p *parser) parseFactor() (node, error) {
+	c := p.peek()
+	switch {
+	case c == 0:
+		return nil, fmt.Errorf("unexpected end of expression")
+	case c == '-':
+		p.pos++
+		operand, err := p.parseFactor()
+		if err != nil {
+			return nil, err
+		}
+		return negate{operand: operand}, nil
+	case c == '(':
+		p.pos++
+		inner, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek() != ')' {
+			return nil, fmt.Errorf("missing closing parenthesis at position %d", p.pos)
+		}
+		p.pos++
+		return inner, nil
+	case c == '.' || (c >= '0' && c <= '9'):
+		start := p.pos
+		for p.pos < len(p.input) && (p.input[p.pos] == '.' || (p.input[p.pos] >= '0' && p.input[p.pos] <= '9')) {
+			p.pos++
+		}
+		value, err := strconv.ParseFloat(p.input[start:p.pos], 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q at position %d", p.input[start:p.pos], start)
+		}
+		return number(value), nil
+	case c == '[':
+		start := p.pos + 1
+		end := strings.IndexAny(p.input[start:], "[]")
+		if end < 0 || p.input[start+end] != ']' {
+			return nil, fmt.Errorf("missing closing bracket at position %d", p.pos)
+		}
+		name := strings.TrimSpace(p.input[start : start+end])
+		if name == "" {
+			return nil, fmt.Errorf("empty variable name at position %d", p.pos)
+		}
+		p.pos = start + end + 1
+		return variable(name), nil
+	case c == '_' || unicode.IsLetter(rune(c)):
+		start := p.pos
+		for p.pos < len(p.input) && (p.input[p.pos] == '_' || unicode.IsLetter(rune(p.input[p.pos])) || unicode.IsDigit(rune(p.input[p.pos]))) {
+			p.pos++
+		}
+		return variable(p.input[start:p.pos]), nil
+	default:
+		return nil, fmt.Errorf("unexpected %q at position %d", c, p.pos)
+	}
+}
@@ -0,0 +1,86 @@
+package formula
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseAndEval(t *testing.T) {
+	vars := map[string]float64{
+		"errors":     5,
+		"requests":   50,
+		"tokens_in":  1000,
+		"tokens_out": 200,
+		"api-calls":  40,
+	}
+
+	tests := []struct {
+		name      string
+		source    string
+		want      float64
+		variables []string
+	}{
+		{
+			name:      "Ratio",
+			source:    "errors / requests",
+			want:      0.1,
+			variables: []string{"errors", "requests"},
+		},
+		{
+			name:      "Weighted sum",
+			source:    "tokens_in*0.5 + tokens_out*1.5",
+			want:      800,
+			variables: []string{"tokens_in", "tokens_out"},
+		},
+		{
+			name:      "Precedence and parentheses",
+			source:    "(requests - errors) * 2 / -(errors)",
+			want:      -18,
+			variables: []string{"errors", "requests"},
+		},
+		{
+			name:      "Division by zero yields zero",
+			source:    "errors / missing",
+			want:      0,
+			variables: []string{"errors", "missing"},
+		},
+		{
+			name:      "Repeated variable",
+			source:    "requests + requests",
+			want:      100,
+			variables: []string{"requests"},
+		},
+		{
+			name:      "Bracketed names",
+			source:    "[api-calls] - [ errors ] / requests",
+			want:      39.9,
+			variables: []string{"api-calls", "errors", "requests"},
+		},
+		{
+			name:      "Bracketed and bare names are the same variable",
+			source:    "[requests]-requests",
+			want:      0,
+			variables: []string{"requests"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expr, err := Parse(tt.source)
+			require.NoError(t, err)
+			assert.InDelta(t, tt.want, expr.Eval(vars), 1e-9)
+			assert.Equal(t, tt.variables, expr.Variables())
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, source := range []string{"", "errors /", "(errors + requests", "errors requests", "errors % 2", "1..2", "[api-calls", "[] + 1", "[api[calls]]"} {
+		t.Run(source, func(t *testing.T) {
+			_, err := Parse(source)
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,167 @@
+// Package cache provides query result caches for closed, immutable meter windows.
+package cache
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/redcardinal-io/metering/application/repositories"
+	"github.com/redcardinal-io/metering/domain/models"
+)
+
+type meterKey struct {
+	tenantSlug string
+	meterSlug  string
+}
+
+// hold keeps results reaching past since out of the cache until until
+type hold struct {
+	since time.Time
+	until time.Time
+}
+
+type entry struct {
+	key       models.QueryCacheKey
+	result    *models.QueryMeterResult
+	expiresAt time.Time
+}
+
+// InMemoryQueryCache is a process local LRU cache of meter query results
+type InMemoryQueryCache struct {
+	mu         sync.Mutex
+	maxEntries int
+	entries    map[models.QueryCacheKey]*list.Element
+	byMeter    map[meterKey]map[models.QueryCacheKey]struct{}
+	holds      map[meterKey]hold
+	lru        *list.List
+	now        func() time.Time
+}
+
+// NewInMemoryQueryCache creates an in-memory query cache holding at most maxEntries results.
+func NewInMemoryQueryCache(maxEntries int) repositories.QueryCacheRepository {
+	return newInMemoryQueryCache(maxEntries, time.Now)
+}
+
+func newInMemoryQueryCache(maxEntries int, now func() time.Time) *InMemoryQueryCache {
+	return &InMemoryQueryCache{
+		maxEntries: maxEntries,
+		entries:    make(map[models.QueryCacheKey]*list.Element),
+		byMeter:    make(map[meterKey]map[models.QueryCacheKey]struct{}),
+		holds:      make(map[meterKey]hold),
+		lru:        list.New(),
+		now:        now,
+	}
+}
+
+// Get returns a copy of the cached result, so callers may reorder or replace its rows.
+func (c *InMemoryQueryCache) Get(ctx context.Context, key models.QueryCacheKey) (*models.QueryMeterResult, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	e := el.Value.(*entry)
+	if !c.now().Before(e.expiresAt) {
+		c.remove(el)
+		return nil, false
+	}
+	c.lru.MoveToFront(el)
+	return copyResult(e.result), true
+}
+
+func (c *InMemoryQueryCache) Set(ctx context.Context, key models.QueryCacheKey, result *models.QueryMeterResult, ttl time.Duration) {
+	if c.maxEntries <= 0 || ttl <= 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.entries[key]; ok {
+		c.remove(el)
+	}
+	el := c.lru.PushFront(&entry{key: key, result: copyResult(result), expiresAt: c.now().Add(ttl)})
+	c.entries[key] = el
+
+	mk := meterKey{tenantSlug: key.TenantSlug, meterSlug: key.MeterSlug}
+	if c.byMeter[mk] == nil {
+		c.byMeter[mk] = make(map[models.QueryCacheKey]struct{})
+	}
+	c.byMeter[mk][key] = struct{}{}
+
+	for c.lru.Len() > c.maxEntries {
+		c.remove(c.lru.Back())
+	}
+}
+
+func (c *InMemoryQueryCache) InvalidateMeter(ctx context.Context, tenantSlug, meterSlug string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.invalidate(meterKey{tenantSlug: tenantSlug, meterSlug: meterSlug})
+}
+
+// HoldMeter merges overlapping holds into one reaching from the earliest since to the latest until.
+func (c *InMemoryQueryCache) HoldMeter(ctx context.Context, tenantSlug, meterSlug string, since, until time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	mk := meterKey{tenantSlug: tenantSlug, meterSlug: meterSlug}
+	c.invalidate(mk)
+	if current, ok := c.holds[mk]; ok && c.now().Before(current.until) {
+		if current.since.Before(since) {
+			since = current.since
+		}
+		if current.until.After(until) {
+			until = current.until
+		}
+	}
+	c.holds[mk] = hold{since: since, until: until}
+}
+
+func (c *InMemoryQueryCache) MeterHold(ctx context.Context, tenantSlug, meterSlug string) (time.Time, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	mk := meterKey{tenantSlug: tenantSlug, meterSlug: meterSlug}
+	h, ok := c.holds[mk]
+	if !ok {
+		return time.Time{}, false
+	}
+	if !c.now().Before(h.until) {
+		delete(c.holds, mk)
+		return time.Time{}, false
+	}
+	return h.since, true
+}
+
+// invalidate drops every cached result of a meter. The caller must hold the lock.
+func (c *InMemoryQueryCache) invalidate(mk meterKey) {
+	for key := range c.byMeter[mk] {
+		if el, ok := c.entries[key]; ok {
+			c.remove(el)
+		}
+	}
+}
+
+// remove drops an element from every index. The caller must hold the lock.
+func (c *InMemoryQueryCache) remove(el *list.Element) {
+	e := c.lru.Remove(el).(*entry)
+	delete(c.entries, e.key)
+
+	mk := meterKey{tenantSlug: e.key.TenantSlug, meterSlug: e.key.MeterSlug}
+	delete(c.byMeter[mk], e.key)
+	if len(c.byMeter[mk]) == 0 {
+		delete(c.byMeter, mk)
+	}
+}
+
+func copyResult(result *models.QueryMeterResult) *models.QueryMeterResult {
+	copied := *result
+	copied.Data = append([]models.QueryMeterRow(nil), result.Data...)
+	return &copied
+}
@@ -0,0 +1,82 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/redcardinal-io/metering/domain/models"
+)
+
+func TestInMemoryQueryCache(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	cache := newInMemoryQueryCache(2, func() time.Time { return now })
+
+	key := func(meter, digest string) models.QueryCacheKey {
+		return models.QueryCacheKey{TenantSlug: "acme", MeterSlug: meter, Digest: digest}
+	}
+	result := func(value float64) *models.QueryMeterResult {
+		return &models.QueryMeterResult{Data: []models.QueryMeterRow{{Value: value}}}
+	}
+
+	t.Run("Returns copies of cached results", func(t *testing.T) {
+		cache.Set(ctx, key("requests", "a"), result(1), time.Hour)
+
+		got, ok := cache.Get(ctx, key("requests", "a"))
+		assert.True(t, ok)
+		got.Data[0].Value = 42
+
+		again, _ := cache.Get(ctx, key("requests", "a"))
+		assert.Equal(t, 1.0, again.Data[0].Value)
+	})
+
+	t.Run("Expires entries", func(t *testing.T) {
+		cache.Set(ctx, key("requests", "short"), result(1), time.Minute)
+		now = now.Add(2 * time.Minute)
+		_, ok := cache.Get(ctx, key("requests", "short"))
+		assert.False(t, ok)
+	})
+
+	t.Run("Evicts the least recently used entry", func(t *testing.T) {
+		cache.Set(ctx, key("requests", "a"), result(1), time.Hour)
+		cache.Set(ctx, key("requests", "b"), result(2), time.Hour)
+		cache.Get(ctx, key("requests", "a"))
+		cache.Set(ctx, key("requests", "c"), result(3), time.Hour)
+
+		_, ok := cache.Get(ctx, key("requests", "b"))
+		assert.False(t, ok)
+		_, ok = cache.Get(ctx, key("requests", "a"))
+		assert.True(t, ok)
+	})
+
+	t.Run("Invalidates every entry of a meter", func(t *testing.T) {
+		cache.Set(ctx, key("requests", "a"), result(1), time.Hour)
+		cache.Set(ctx, key("tokens", "a"), result(2), time.Hour)
+		cache.InvalidateMeter(ctx, "acme", "requests")
+
+		_, ok := cache.Get(ctx, key("requests", "a"))
+		assert.False(t, ok)
+		_, ok = cache.Get(ctx, key("tokens", "a"))
+		assert.True(t, ok)
+	})
+	t.Run("Holds merge until they expire", func(t *testing.T) {
+		cache.Set(ctx, key("requests", "a"), result(1), time.Hour)
+		cache.HoldMeter(ctx, "acme", "requests", now.Add(-time.Hour), now.Add(time.Minute))
+		cache.HoldMeter(ctx, "acme", "requests", now.Add(-2*time.Hour), now.Add(30*time.Second))
+
+		_, ok := cache.Get(ctx, key("requests", "a"))
+		assert.False(t, ok, "holding drops the cached results")
+		since, held := cache.MeterHold(ctx, "acme", "requests")
+		assert.True(t, held)
+		assert.Equal(t, now.Add(-2*time.Hour), since)
+		_, held = cache.MeterHold(ctx, "acme", "tokens")
+		assert.False(t, held)
+
+		now = now.Add(time.Minute)
+		_, held = cache.MeterHold(ctx, "acme", "requests")
+		assert.False(t, held)
+	})
+}
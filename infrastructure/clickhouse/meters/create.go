@@ -11,13 +11,29 @@ import (
 
 // CreateMeter creates a materialized view for meter data in ClickHouse
 type CreateMeter struct {
-	Slug          string
-	EventType     string
+	Slug      string
+	EventType string
+	// EventTypes are all the event types aggregated by the view; EventType is used when empty
+	EventTypes    []string
 	ValueProperty string
-	Properties    []string
-	Aggregation   models.AggregationEnum
-	Populate      bool
-	TenantSlug    string
+	// ValueProperties overrides ValueProperty for some of the event types
+	ValueProperties map[string]string
+	Properties      []string
+	Aggregation     models.AggregationEnum
+	Populate        bool
+	TenantSlug      string
+	Granularity     models.Granularity
+	Rollups         []models.Granularity
+	// Filter restricts the events aggregated by the view
+	Filter *models.FilterExpression
+}
+
+// granularityIntervals maps meter granularities to the ClickHouse intervals events are bucketed by
+var granularityIntervals = map[models.Granularity]string{
+	models.GranularitySecond: "toIntervalSecond(1)",
+	models.GranularityMinute: "toIntervalMinute(1)",
+	models.GranularityHour:   "toIntervalHour(1)",
+	models.GranularityDay:    "toIntervalDay(1)",
 }
 
 func (c *CreateMeter) ToCreateSQL() (string, []any, error) {
@@ -26,28 +42,20 @@ func (c *CreateMeter) ToCreateSQL() (string, []any, error) {
 		return "", nil, fmt.Errorf("invalid aggregation type: %s", c.Aggregation)
 	}
 
+	if _, ok := granularityIntervals[c.granularity()]; !ok {
+		return "", nil, fmt.Errorf("invalid granularity: %s", c.Granularity)
+	}
+
 	// Get view name
 	viewName := GetMeterViewName(c.TenantSlug, c.Slug)
 
-	var columnsStr strings.Builder
-	columnsStr.WriteString("organization String, \n\tuser String, \n\twindowstart DateTime, \n\twindowend DateTime, \n\t")
-	columnsStr.WriteString(fmt.Sprintf("value AggregateFunction(%s, %s)", agg.mergeFunc, agg.dataType))
-
-	var orderByString strings.Builder
-	orderByString.WriteString("windowstart, windowend, organization, user")
-
-	propertyNames := make([]string, len(c.Properties))
-	copy(propertyNames, c.Properties)
-	sort.Strings(propertyNames)
-	// Add each property as a column
-	for _, name := range propertyNames {
-		columnName := sqlbuilder.Escape(name)
-		columnsStr.WriteString(fmt.Sprintf(", \n\t%s String", columnName))
-		orderByString.WriteString(fmt.Sprintf(", %s", columnName))
-	}
+	columns, orderBy := c.viewColumns(agg.mergeFunc, agg.dataType)
 
 	// Build the SELECT query using the helper method
-	selectSQL, selectArgs := c.toSeleteSQL(agg.stateFunc, agg.dataType)
+	selectSQL, selectArgs, err := c.toSeleteSQL(agg.stateFunc, agg.dataType)
+	if err != nil {
+		return "", nil, err
+	}
 
 	// Handle POPULATE option
 	populateClause := ""
@@ -63,8 +71,8 @@ func (c *CreateMeter) ToCreateSQL() (string, []any, error) {
 		ORDER BY (%v)
 		%v AS %v
 		`, sqlbuilder.Raw(viewName),
-		sqlbuilder.Raw(columnsStr.String()),
-		sqlbuilder.Raw(orderByString.String()),
+		sqlbuilder.Raw(columns),
+		sqlbuilder.Raw(orderBy),
 		sqlbuilder.Raw(populateClause),
 		sqlbuilder.Raw(selectSQL),
 	)
@@ -74,7 +82,67 @@ func (c *CreateMeter) ToCreateSQL() (string, []any, error) {
 	return createSQL, append(createArgs, selectArgs...), nil
 }
 
-func (c *CreateMeter) toSeleteSQL(aggStateFunc, dataType string) (string, []any) {
+// viewColumns returns the column definitions and sorting key shared by a meter view and its rollups.
+func (c *CreateMeter) viewColumns(mergeFunc, dataType string) (string, string) {
+	definitions := make([]string, 0, len(c.Properties)+5)
+	for _, column := range c.columnDefinitions(mergeFunc, dataType) {
+		definitions = append(definitions, fmt.Sprintf("%s %s", column.Name, column.Type))
+	}
+
+	var orderByString strings.Builder
+	orderByString.WriteString("windowstart, windowend, organization, user")
+
+	propertyNames := make([]string, len(c.Properties))
+	copy(propertyNames, c.Properties)
+	sort.Strings(propertyNames)
+	for _, name := range propertyNames {
+		orderByString.WriteString(fmt.Sprintf(", %s", sqlbuilder.Escape(name)))
+	}
+
+	return strings.Join(definitions, ", \n\t"), orderByString.String()
+}
+
+// columnDefinitions returns the columns of a meter view and its rollups in declaration order.
+func (c *CreateMeter) columnDefinitions(mergeFunc, dataType string) []models.OlapColumn {
+	columns := []models.OlapColumn{
+		{Name: "organization", Type: "String"},
+		{Name: "user", Type: "String"},
+		{Name: "windowstart", Type: "DateTime"},
+		{Name: "windowend", Type: "DateTime"},
+		{Name: "value", Type: fmt.Sprintf("AggregateFunction(%s, %s)", mergeFunc, dataType)},
+	}
+
+	propertyNames := make([]string, len(c.Properties))
+	copy(propertyNames, c.Properties)
+	sort.Strings(propertyNames)
+	// Add each property as a column
+	for _, name := range propertyNames {
+		columns = append(columns, models.OlapColumn{Name: sqlbuilder.Escape(name), Type: "String"})
+	}
+	return columns
+}
+
+// granularity returns the granularity of the view, defaulting to models.DefaultGranularity.
+func (c *CreateMeter) granularity() models.Granularity {
+	if c.Granularity == "" {
+		return models.DefaultGranularity
+	}
+	return c.Granularity
+}
+
+func (c *CreateMeter) toSeleteSQL(aggStateFunc, dataType string) (string, []any, error) {
+	query, err := c.selectBuilder(aggStateFunc, dataType)
+	if err != nil {
+		return "", nil, err
+	}
+	sql, args := query.Build()
+	return sql, args, nil
+}
+
+// selectBuilder builds the SELECT aggregating rc_events into meter rows with the given aggregate function.
+func (c *CreateMeter) selectBuilder(aggStateFunc, dataType string) (*sqlbuilder.SelectBuilder, error) {
+	interval := granularityIntervals[c.granularity()]
+
 	// Create the select builder
 	query := sqlbuilder.ClickHouse.NewSelectBuilder()
 
@@ -82,21 +150,20 @@ func (c *CreateMeter) toSeleteSQL(aggStateFunc, dataType string) (string, []any)
 
 	var valueColumn string
 	// Add value column based on aggregation type
-	if c.ValueProperty == "" && c.Aggregation == models.AggregationCount {
+	if !c.hasValueProperty() && c.Aggregation == models.AggregationCount {
 		valueColumn = fmt.Sprintf("%s(*) AS value", aggStateFunc)
 	} else if c.Aggregation == models.AggregationUniqueCount {
-		valueColumn = fmt.Sprintf("%s(JSONExtractString(properties, '%s')) AS value",
-			aggStateFunc, sqlbuilder.Escape(c.ValueProperty))
+		valueColumn = fmt.Sprintf("%s(%s) AS value", aggStateFunc, c.valueExpr())
 	} else {
-		valueColumn = fmt.Sprintf("%s(cast(JSONExtractString(properties, '%s'), '%s')) AS value",
-			aggStateFunc, sqlbuilder.Escape(c.ValueProperty), dataType)
+		valueColumn = fmt.Sprintf("%s(cast(%s, '%s')) AS value", aggStateFunc, c.valueExpr(), dataType)
 	}
 
+	// Buckets are aligned in UTC like the rollups and the query filters, whatever the server timezone
 	columnNames := []string{
 		"organization",
 		"user",
-		"tumbleStart(timestamp, toIntervalMinute(1)) AS windowstart",
-		"tumbleEnd(timestamp, toIntervalMinute(1)) AS windowend",
+		fmt.Sprintf("tumbleStart(timestamp, %s, 'UTC') AS windowstart", interval),
+		fmt.Sprintf("tumbleEnd(timestamp, %s, 'UTC') AS windowend", interval),
 		valueColumn,
 	}
 
@@ -115,7 +182,9 @@ func (c *CreateMeter) toSeleteSQL(aggStateFunc, dataType string) (string, []any)
 	query.Select(columnNames...)
 	query.From(eventsTable)
 
-	query.Where(query.Equal(fmt.Sprintf("%s.type", eventsTable), c.EventType))
+	if err := c.whereEvents(query); err != nil {
+		return nil, err
+	}
 
 	// Set GROUP BY clause
 	groupByColumns := []string{"windowstart", "windowend", "organization", "user"}
@@ -124,6 +193,71 @@ func (c *CreateMeter) toSeleteSQL(aggStateFunc, dataType string) (string, []any)
 	}
 	query.GroupBy(groupByColumns...)
 
-	sql, args := query.Build()
-	return sql, args
+	return query, nil
+}
+
+// eventTypes returns the event types aggregated by the view.
+func (c *CreateMeter) eventTypes() []string {
+	if len(c.EventTypes) > 0 {
+		return c.EventTypes
+	}
+	return []string{c.EventType}
+}
+
+// whereEvents restricts the builder to the events of the meter's types that match its filter.
+func (c *CreateMeter) whereEvents(builder *sqlbuilder.SelectBuilder) error {
+	column := fmt.Sprintf("%s.type", eventsTable)
+	if eventTypes := c.eventTypes(); len(eventTypes) == 1 {
+		builder.Where(builder.Equal(column, eventTypes[0]))
+	} else {
+		values := make([]any, len(eventTypes))
+		for i, eventType := range eventTypes {
+			values[i] = eventType
+		}
+		builder.Where(builder.In(column, values...))
+	}
+	if c.Filter != nil {
+		filter, err := compileEventFilter(&builder.Cond, c.Filter)
+		if err != nil {
+			return err
+		}
+		builder.Where(filter)
+	}
+	return nil
+}
+
+// hasValueProperty reports whether the value is read from an event property.
+func (c *CreateMeter) hasValueProperty() bool {
+	return c.ValueProperty != "" || len(c.ValueProperties) > 0
+}
+
+// valueExpr returns the String expression the aggregated value is read from.
+func (c *CreateMeter) valueExpr() string {
+	return c.perEventType(func(property string) string {
+		return fmt.Sprintf("JSONExtractString(properties, %s)", quoteString(property))
+	})
+}
+
+// perEventType applies expr to the value property of each event type, selecting the result
+// by the type of the event when some types override the value property.
+func (c *CreateMeter) perEventType(expr func(property string) string) string {
+	if len(c.ValueProperties) == 0 {
+		return expr(c.ValueProperty)
+	}
+
+	eventTypes := make([]string, 0, len(c.ValueProperties))
+	for eventType := range c.ValueProperties {
+		eventTypes = append(eventTypes, eventType)
+	}
+	sort.Strings(eventTypes)
+
+	branches := make([]string, 0, 2*len(eventTypes)+1)
+	for _, eventType := range eventTypes {
+		branches = append(branches,
+			fmt.Sprintf("type = %s", quoteString(eventType)),
+			expr(c.ValueProperties[eventType]),
+		)
+	}
+	branches = append(branches, expr(c.ValueProperty))
+	return fmt.Sprintf("multiIf(%s)", strings.Join(branches, ", "))
 }
@@ -6,6 +6,7 @@ import (
 
 	"github.com/redcardinal-io/metering/domain/models"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestCreateMeter(t *testing.T) {
@@ -33,8 +34,8 @@ func TestCreateMeter(t *testing.T) {
 			 AS SELECT
 				organization,
 				user,
-				tumbleStart(timestamp, toIntervalMinute(1)) AS windowstart,
-				tumbleEnd(timestamp, toIntervalMinute(1)) AS windowend,
+				tumbleStart(timestamp, toIntervalMinute(1), 'UTC') AS windowstart,
+				tumbleEnd(timestamp, toIntervalMinute(1), 'UTC') AS windowend,
 				sumState(cast(JSONExtractString(properties, 'count'), 'Float64')) AS value,
 				JSONExtractString(properties, 'path') as path,
 				JSONExtractString(properties, 'referrer') as referrer
@@ -61,8 +62,8 @@ func TestCreateMeter(t *testing.T) {
 			AS SELECT
 				organization,
 				user,
-				tumbleStart(timestamp, toIntervalMinute(1)) AS windowstart,
-				tumbleEnd(timestamp, toIntervalMinute(1)) AS windowend,
+				tumbleStart(timestamp, toIntervalMinute(1), 'UTC') AS windowstart,
+				tumbleEnd(timestamp, toIntervalMinute(1), 'UTC') AS windowend,
 				uniqState(JSONExtractString(properties, 'user_id')) AS value,
 				JSONExtractString(properties, 'country') as country,
 				JSONExtractString(properties, 'device') as device
@@ -88,8 +89,8 @@ func TestCreateMeter(t *testing.T) {
 			 AS SELECT
 				organization,
 				user,
-				tumbleStart(timestamp, toIntervalMinute(1)) AS windowstart,
-				tumbleEnd(timestamp, toIntervalMinute(1)) AS windowend,
+				tumbleStart(timestamp, toIntervalMinute(1), 'UTC') AS windowstart,
+				tumbleEnd(timestamp, toIntervalMinute(1), 'UTC') AS windowend,
 				countState(*) AS value,
 				JSONExtractString(properties, 'endpoint') as endpoint,
 				JSONExtractString(properties, 'method') as method
@@ -156,8 +157,8 @@ func TestCreateMeterSelectSQL(t *testing.T) {
 			wantSQL: `SELECT
 				organization,
 				user,
-				tumbleStart(timestamp, toIntervalMinute(1)) AS windowstart,
-				tumbleEnd(timestamp, toIntervalMinute(1)) AS windowend,
+				tumbleStart(timestamp, toIntervalMinute(1), 'UTC') AS windowstart,
+				tumbleEnd(timestamp, toIntervalMinute(1), 'UTC') AS windowend,
 				sumState(cast(JSONExtractString(properties, 'count'), 'Float64')) AS value,
 				JSONExtractString(properties, 'path') as path,
 				JSONExtractString(properties, 'referrer') as referrer
@@ -166,6 +167,29 @@ func TestCreateMeterSelectSQL(t *testing.T) {
 			GROUP BY windowstart, windowend, organization, user, path, referrer`,
 			wantArgs: []any{"page_view"},
 		},
+		{
+			name: "Select SQL with hour granularity",
+			meter: CreateMeter{
+				EventType:   "api_request",
+				Properties:  []string{"endpoint"},
+				Aggregation: models.AggregationCount,
+				TenantSlug:  "test_tenant",
+				Granularity: models.GranularityHour,
+			},
+			stateFunc: "countState",
+			dataType:  "Float64",
+			wantSQL: `SELECT
+				organization,
+				user,
+				tumbleStart(timestamp, toIntervalHour(1), 'UTC') AS windowstart,
+				tumbleEnd(timestamp, toIntervalHour(1), 'UTC') AS windowend,
+				countState(*) AS value,
+				JSONExtractString(properties, 'endpoint') as endpoint
+			FROM rc_events
+			WHERE rc_events.type = ?
+			GROUP BY windowstart, windowend, organization, user, endpoint`,
+			wantArgs: []any{"api_request"},
+		},
 		{
 			name: "Select SQL for count aggregation",
 			meter: CreateMeter{
@@ -180,8 +204,8 @@ func TestCreateMeterSelectSQL(t *testing.T) {
 			wantSQL: `SELECT
 				organization,
 				user,
-				tumbleStart(timestamp, toIntervalMinute(1)) AS windowstart,
-				tumbleEnd(timestamp, toIntervalMinute(1)) AS windowend,
+				tumbleStart(timestamp, toIntervalMinute(1), 'UTC') AS windowstart,
+				tumbleEnd(timestamp, toIntervalMinute(1), 'UTC') AS windowend,
 				countState(*) AS value,
 				JSONExtractString(properties, 'endpoint') as endpoint,
 				JSONExtractString(properties, 'method') as method
@@ -194,7 +218,8 @@ func TestCreateMeterSelectSQL(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			gotSQL, gotArgs := tt.meter.toSeleteSQL(tt.stateFunc, tt.dataType)
+			gotSQL, gotArgs, err := tt.meter.toSeleteSQL(tt.stateFunc, tt.dataType)
+			require.NoError(t, err)
 
 			// Normalize and compare SQL
 			assert.Equal(t, normalizeSQL(tt.wantSQL), normalizeSQL(gotSQL))
@@ -204,3 +229,23 @@ func TestCreateMeterSelectSQL(t *testing.T) {
 		})
 	}
 }
+
+func TestCreateMeterEventTypes(t *testing.T) {
+	meter := CreateMeter{
+		Slug:            "compute_seconds",
+		EventType:       "vm.stopped",
+		EventTypes:      []string{"vm.stopped", "job.finished"},
+		ValueProperty:   "seconds",
+		ValueProperties: map[string]string{"job.finished": "duration"},
+		Properties:      []string{"region"},
+		Aggregation:     models.AggregationSum,
+		TenantSlug:      "test_tenant",
+	}
+
+	sql, args, err := meter.toSeleteSQL("sumState", "Float64")
+	require.NoError(t, err)
+	assert.Contains(t, normalizeSQL(sql),
+		"sumState(cast(multiIf(type = 'job.finished', JSONExtractString(properties, 'duration'), JSONExtractString(properties, 'seconds')), 'Float64')) AS value")
+	assert.Contains(t, normalizeSQL(sql), "WHERE rc_events.type IN (?, ?)")
+	assert.Equal(t, []any{"vm.stopped", "job.finished"}, args)
+}
@@ -0,0 +1,146 @@
+package meters
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/huandu/go-sqlbuilder"
+	"github.com/redcardinal-io/metering/domain/models"
+)
+
+// filterTarget maps the properties of a filter expression to the SQL it is compiled against.
+type filterTarget struct {
+	// value returns the String expression a property is compared on
+	value func(property string) string
+	// exists and missing return the conditions of the exists and missing operators
+	exists  func(property string) string
+	missing func(property string) string
+}
+
+// compileFilter converts a filter expression on meter view columns into a parameterized condition.
+// Property columns are stored as String in the meter view, so numeric comparisons
+// cast with toFloat64OrNull and rows with non-numeric values never match.
+func compileFilter(cond *sqlbuilder.Cond, f *models.FilterExpression) (string, error) {
+	return compileFilterNode(cond, f, filterTarget{
+		value: func(property string) string {
+			return sqlbuilder.Escape(property)
+		},
+		exists: func(property string) string {
+			return cond.NotEqual(sqlbuilder.Escape(property), "")
+		},
+		missing: func(property string) string {
+			return cond.Equal(sqlbuilder.Escape(property), "")
+		},
+	}, 1)
+}
+
+// compileEventFilter converts a filter expression on event properties into a parameterized
+// condition on rc_events. Properties are compared as strings the way meter views store them,
+// except that numbers and booleans are compared on their JSON text rather than as empty strings.
+// Property keys are inlined as string literals because condition fields are not parameterized.
+func compileEventFilter(cond *sqlbuilder.Cond, f *models.FilterExpression) (string, error) {
+	return compileFilterNode(cond, f, filterTarget{
+		value: func(property string) string {
+			key := quoteString(property)
+			return fmt.Sprintf("if(JSONType(properties, %[1]s) = 'String', JSONExtractString(properties, %[1]s), JSONExtractRaw(properties, %[1]s))", key)
+		},
+		exists: func(property string) string {
+			return fmt.Sprintf("JSONHas(properties, %s)", quoteString(property))
+		},
+		missing: func(property string) string {
+			return fmt.Sprintf("NOT JSONHas(properties, %s)", quoteString(property))
+		},
+	}, 1)
+}
+
+// quoteString returns value as a ClickHouse string literal safe to embed in a builder format.
+func quoteString(value string) string {
+	value = strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(value)
+	return "'" + sqlbuilder.Escape(value) + "'"
+}
+
+func compileFilterNode(cond *sqlbuilder.Cond, f *models.FilterExpression, target filterTarget, depth int) (string, error) {
+	if depth > models.MaxFilterDepth {
+		return "", fmt.Errorf("filter nesting exceeds maximum depth of %d", models.MaxFilterDepth)
+	}
+
+	if f.IsGroup() {
+		if len(f.And) > 0 && len(f.Or) > 0 {
+			return "", fmt.Errorf("filter group cannot contain both and and or")
+		}
+		children := f.And
+		if len(f.Or) > 0 {
+			children = f.Or
+		}
+		exprs := make([]string, 0, len(children))
+		for i := range children {
+			expr, err := compileFilterNode(cond, &children[i], target, depth+1)
+			if err != nil {
+				return "", err
+			}
+			exprs = append(exprs, expr)
+		}
+		if len(f.Or) > 0 {
+			return cond.Or(exprs...), nil
+		}
+		return cond.And(exprs...), nil
+	}
+
+	if f.Property == "" {
+		return "", fmt.Errorf("filter property is required")
+	}
+
+	switch f.Operator {
+	case models.FilterOpEq, models.FilterOpNeq, models.FilterOpPrefix, models.FilterOpContains:
+		value, err := f.StringValue()
+		if err != nil {
+			return "", err
+		}
+		column := target.value(f.Property)
+		switch f.Operator {
+		case models.FilterOpEq:
+			return cond.Equal(column, value), nil
+		case models.FilterOpNeq:
+			return cond.NotEqual(column, value), nil
+		case models.FilterOpPrefix:
+			return fmt.Sprintf("startsWith(%s, %s)", column, cond.Var(value)), nil
+		default:
+			return fmt.Sprintf("position(%s, %s) > 0", column, cond.Var(value)), nil
+		}
+	case models.FilterOpIn, models.FilterOpNotIn:
+		if len(f.Values) == 0 {
+			return "", fmt.Errorf("filter operator %s on %s requires values", f.Operator, f.Property)
+		}
+		values := make([]any, len(f.Values))
+		for i, v := range f.Values {
+			values[i] = v
+		}
+		column := target.value(f.Property)
+		if f.Operator == models.FilterOpIn {
+			return cond.In(column, values...), nil
+		}
+		return cond.NotIn(column, values...), nil
+	case models.FilterOpGt, models.FilterOpGte, models.FilterOpLt, models.FilterOpLte:
+		value, err := f.NumericValue()
+		if err != nil {
+			return "", err
+		}
+		numeric := fmt.Sprintf("toFloat64OrNull(%s)", target.value(f.Property))
+		switch f.Operator {
+		case models.FilterOpGt:
+			return cond.GreaterThan(numeric, value), nil
+		case models.FilterOpGte:
+			return cond.GreaterEqualThan(numeric, value), nil
+		case models.FilterOpLt:
+			return cond.LessThan(numeric, value), nil
+		default:
+			return cond.LessEqualThan(numeric, value), nil
+		}
+	case models.FilterOpExists:
+		return target.exists(f.Property), nil
+	case models.FilterOpMissing:
+		return target.missing(f.Property), nil
+	default:
+		return "", fmt.Errorf("invalid filter operator: %s", f.Operator)
+	}
+}
@@ -0,0 +1,138 @@
+package meters
+
+import (
+	"testing"
+	"time"
+
+	"github.com/redcardinal-io/metering/domain/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueryMeterFilterToSQL(t *testing.T) {
+	from := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(24 * time.Hour)
+	minute := models.WindowSizeMinute
+
+	tests := []struct {
+		name     string
+		filter   *models.FilterExpression
+		wantErr  bool
+		wantSQL  string
+		wantArgs []any
+	}{
+		{
+			name:     "Not equals",
+			filter:   &models.FilterExpression{Property: "region", Operator: models.FilterOpNeq, Value: "eu"},
+			wantSQL:  "region <> ?",
+			wantArgs: []any{"eu"},
+		},
+		{
+			name:     "Not in",
+			filter:   &models.FilterExpression{Property: "region", Operator: models.FilterOpNotIn, Values: []string{"eu", "us"}},
+			wantSQL:  "region NOT IN (?, ?)",
+			wantArgs: []any{"eu", "us"},
+		},
+		{
+			name:     "Prefix",
+			filter:   &models.FilterExpression{Property: "path", Operator: models.FilterOpPrefix, Value: "/api"},
+			wantSQL:  "startsWith(path, ?)",
+			wantArgs: []any{"/api"},
+		},
+		{
+			name:     "Contains",
+			filter:   &models.FilterExpression{Property: "path", Operator: models.FilterOpContains, Value: "v1"},
+			wantSQL:  "position(path, ?) > 0",
+			wantArgs: []any{"v1"},
+		},
+		{
+			name:     "Numeric comparison",
+			filter:   &models.FilterExpression{Property: "status", Operator: models.FilterOpGte, Value: float64(500)},
+			wantSQL:  "toFloat64OrNull(status) >= ?",
+			wantArgs: []any{float64(500)},
+		},
+		{
+			name:     "Missing",
+			filter:   &models.FilterExpression{Property: "model", Operator: models.FilterOpMissing},
+			wantSQL:  "model = ?",
+			wantArgs: []any{""},
+		},
+		{
+			name: "Nested groups",
+			filter: &models.FilterExpression{
+				Or: []models.FilterExpression{
+					{Property: "model", Operator: models.FilterOpEq, Value: "large"},
+					{And: []models.FilterExpression{
+						{Property: "model", Operator: models.FilterOpExists},
+						{Property: "status", Operator: models.FilterOpLt, Value: "300"},
+					}},
+				},
+			},
+			wantSQL:  "(model = ? OR (model <> ? AND toFloat64OrNull(status) < ?))",
+			wantArgs: []any{"large", "", float64(300)},
+		},
+		{
+			name:    "Non-numeric value for numeric operator",
+			filter:  &models.FilterExpression{Property: "status", Operator: models.FilterOpGt, Value: "abc"},
+			wantErr: true,
+		},
+		{
+			name:    "Invalid operator",
+			filter:  &models.FilterExpression{Property: "status", Operator: "like", Value: "a"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := QueryMeter{
+				TenantSlug:  "test_tenant",
+				MeterSlug:   "api_calls",
+				Aggregation: models.AggregationCount,
+				From:        &from,
+				To:          &to,
+				WindowSize:  &minute,
+				Filter:      tt.filter,
+			}
+			sql, args, err := q.ToSQL()
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Contains(t, normalizeSQL(sql), "WHERE "+tt.wantSQL+" AND windowstart >= ?")
+			assert.Equal(t, tt.wantArgs, args[:len(tt.wantArgs)])
+		})
+	}
+}
+
+func TestCreateMeterEventFilter(t *testing.T) {
+	meter := CreateMeter{
+		Slug:        "api_requests",
+		EventType:   "api_request",
+		Properties:  []string{"method"},
+		Aggregation: models.AggregationCount,
+		TenantSlug:  "test_tenant",
+		Filter: &models.FilterExpression{And: []models.FilterExpression{
+			{Property: "region", Operator: models.FilterOpEq, Value: "eu"},
+			{Property: "status", Operator: models.FilterOpGte, Value: float64(500)},
+			{Property: "it's", Operator: models.FilterOpExists},
+		}},
+	}
+
+	sql, args, err := meter.ToCreateSQL()
+	assert.NoError(t, err)
+	assert.Contains(t, normalizeSQL(sql), "WHERE rc_events.type = ? AND "+
+		"(if(JSONType(properties, 'region') = 'String', JSONExtractString(properties, 'region'), JSONExtractRaw(properties, 'region')) = ? AND "+
+		"toFloat64OrNull(if(JSONType(properties, 'status') = 'String', JSONExtractString(properties, 'status'), JSONExtractRaw(properties, 'status'))) >= ? AND "+
+		"JSONHas(properties, 'it\\'s')) GROUP BY")
+	assert.Equal(t, []any{"api_request", "eu", float64(500)}, args)
+
+	preview := PreviewMeter{Meter: meter, Limit: 10}
+	statsSQL, _, err := preview.ToStatsSQL()
+	assert.NoError(t, err)
+	assert.Contains(t, normalizeSQL(statsSQL), "JSONHas(properties, 'it\\'s')")
+
+	meter.Filter = &models.FilterExpression{Property: "status", Operator: "like", Value: "5%"}
+	_, _, err = meter.ToCreateSQL()
+	assert.Error(t, err)
+}
@@ -0,0 +1,143 @@
+package meters
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/huandu/go-sqlbuilder"
+	"github.com/redcardinal-io/metering/domain/models"
+)
+
+// sampleEventsStructure is the structure of the inline table sample events are previewed from
+const sampleEventsStructure = "type String, organization String, user String, timestamp DateTime, properties String"
+
+// PreviewMeter evaluates a meter definition against sample events or a range of rc_events
+// without creating anything.
+type PreviewMeter struct {
+	Meter  CreateMeter
+	Events []*models.Event
+	From   *time.Time
+	To     *time.Time
+	Limit  int
+}
+
+// ToSQL returns the query producing the rows the meter view would store, with final instead of
+// intermediate aggregate values.
+func (p *PreviewMeter) ToSQL() (string, []any, error) {
+	agg, ok := aggregationMap[p.Meter.Aggregation]
+	if !ok {
+		return "", nil, fmt.Errorf("invalid aggregation type: %s", p.Meter.Aggregation)
+	}
+
+	builder, err := p.Meter.selectBuilder(agg.mergeFunc, agg.dataType)
+	if err != nil {
+		return "", nil, err
+	}
+	if err := p.from(builder); err != nil {
+		return "", nil, err
+	}
+	builder.OrderBy("windowstart", "organization", "user")
+	if p.Limit > 0 {
+		builder.Limit(p.Limit)
+	}
+
+	sql, args := builder.Build()
+	return sql, args, nil
+}
+
+// ToStatsSQL returns the query counting events with missing or non-numeric values and the
+// cardinality of each property, from which preview warnings are derived.
+func (p *PreviewMeter) ToStatsSQL() (string, []any, error) {
+	builder := sqlbuilder.ClickHouse.NewSelectBuilder()
+	columns := []string{"count() AS events"}
+	if p.Meter.hasValueProperty() {
+		hasValue := p.Meter.perEventType(func(property string) string {
+			return fmt.Sprintf("JSONHas(properties, %s)", quoteString(property))
+		})
+		columns = append(columns, fmt.Sprintf("countIf(NOT %s) AS missing_value", hasValue))
+		if p.Meter.Aggregation != models.AggregationUniqueCount {
+			columns = append(columns, fmt.Sprintf(
+				"countIf(%s AND toFloat64OrNull(%s) IS NULL) AS non_numeric",
+				hasValue, p.Meter.valueExpr()))
+		}
+	}
+	for i, name := range p.properties() {
+		property := sqlbuilder.Escape(name)
+		columns = append(columns,
+			fmt.Sprintf("countIf(NOT JSONHas(properties, '%s')) AS missing_%d", property, i),
+			fmt.Sprintf("uniq(JSONExtractString(properties, '%s')) AS distinct_%d", property, i),
+		)
+	}
+	builder.Select(columns...)
+	if err := p.Meter.whereEvents(builder); err != nil {
+		return "", nil, err
+	}
+	if err := p.from(builder); err != nil {
+		return "", nil, err
+	}
+
+	sql, args := builder.Build()
+	return sql, args, nil
+}
+
+// properties returns the meter properties in the order used by ToStatsSQL column aliases.
+func (p *PreviewMeter) properties() []string {
+	names := append([]string(nil), p.Meter.Properties...)
+	sort.Strings(names)
+	return names
+}
+
+// PropertyStatsColumns names the stats columns of a property
+type PropertyStatsColumns struct {
+	Missing  string
+	Distinct string
+}
+
+// PropertyStatsColumns returns the stats column aliases of each property.
+func (p *PreviewMeter) PropertyStatsColumns() map[string]PropertyStatsColumns {
+	columns := make(map[string]PropertyStatsColumns, len(p.Meter.Properties))
+	for i, name := range p.properties() {
+		columns[name] = PropertyStatsColumns{
+			Missing:  fmt.Sprintf("missing_%d", i),
+			Distinct: fmt.Sprintf("distinct_%d", i),
+		}
+	}
+	return columns
+}
+
+// from points the builder at the sample events, or at rc_events restricted to the preview range.
+func (p *PreviewMeter) from(builder *sqlbuilder.SelectBuilder) error {
+	if len(p.Events) == 0 {
+		builder.From(eventsTable)
+		if p.From != nil {
+			builder.Where(builder.GreaterEqualThan("timestamp", p.From.Unix()))
+		}
+		if p.To != nil {
+			builder.Where(builder.LessThan("timestamp", p.To.Unix()))
+		}
+		return nil
+	}
+
+	tuples := make([]string, 0, len(p.Events))
+	for _, event := range p.Events {
+		timestamp, err := time.Parse(time.RFC3339, event.Timestamp)
+		if err != nil {
+			return fmt.Errorf("invalid timestamp %q for event %s: %w", event.Timestamp, event.ID, err)
+		}
+		properties := event.Properties
+		if properties == "" {
+			properties = "{}"
+		}
+		tuples = append(tuples, fmt.Sprintf("(%s, %s, %s, %s, %s)",
+			builder.Var(event.Type),
+			builder.Var(event.Organization),
+			builder.Var(event.User),
+			builder.Var(timestamp.Unix()),
+			builder.Var(properties),
+		))
+	}
+	builder.From(fmt.Sprintf("values('%s', %s) AS %s", sampleEventsStructure, strings.Join(tuples, ", "), eventsTable))
+	return nil
+}
@@ -0,0 +1,74 @@
+package meters
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/redcardinal-io/metering/domain/models"
+)
+
+func TestPreviewMeter(t *testing.T) {
+	meter := CreateMeter{
+		Slug:          "api_tokens",
+		EventType:     "completion",
+		ValueProperty: "tokens",
+		Properties:    []string{"model", "region"},
+		Aggregation:   models.AggregationSum,
+		TenantSlug:    "test_tenant",
+	}
+
+	t.Run("Sample events", func(t *testing.T) {
+		preview := PreviewMeter{
+			Meter: meter,
+			Events: []*models.Event{
+				{Type: "completion", Organization: "org-a", User: "user-1", Timestamp: "2025-06-01T10:00:00Z", Properties: `{"tokens":"12"}`},
+				{Type: "completion", Organization: "org-a", User: "user-2", Timestamp: "2025-06-01T10:00:30Z"},
+			},
+			Limit: 10,
+		}
+
+		sql, args, err := preview.ToSQL()
+		require.NoError(t, err)
+		normalized := normalizeSQL(sql)
+		assert.Contains(t, normalized, "sum(cast(JSONExtractString(properties, 'tokens'), 'Float64')) AS value")
+		assert.Contains(t, normalized, "FROM values('type String, organization String, user String, timestamp DateTime, properties String', (?, ?, ?, ?, ?), (?, ?, ?, ?, ?)) AS rc_events")
+		assert.Contains(t, normalized, "WHERE rc_events.type = ?")
+		assert.Contains(t, normalized, "ORDER BY windowstart, organization, user LIMIT ?")
+		assert.Equal(t, []any{
+			"completion", "org-a", "user-1", int64(1748772000), `{"tokens":"12"}`,
+			"completion", "org-a", "user-2", int64(1748772030), "{}",
+			"completion", 10,
+		}, args)
+	})
+
+	t.Run("Stored events", func(t *testing.T) {
+		from := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+		to := from.Add(24 * time.Hour)
+		preview := PreviewMeter{Meter: meter, From: &from, To: &to}
+
+		sql, args, err := preview.ToStatsSQL()
+		require.NoError(t, err)
+		normalized := normalizeSQL(sql)
+		assert.Contains(t, normalized, "count() AS events")
+		assert.Contains(t, normalized, "countIf(NOT JSONHas(properties, 'tokens')) AS missing_value")
+		assert.Contains(t, normalized, "AS non_numeric")
+		assert.Contains(t, normalized, "uniq(JSONExtractString(properties, 'model')) AS distinct_0")
+		assert.Contains(t, normalized, "countIf(NOT JSONHas(properties, 'region')) AS missing_1")
+		assert.Contains(t, normalized, "FROM rc_events WHERE rc_events.type = ? AND timestamp >= ? AND timestamp < ?")
+		assert.Equal(t, []any{"completion", from.Unix(), to.Unix()}, args)
+
+		assert.Equal(t, map[string]PropertyStatsColumns{
+			"model":  {Missing: "missing_0", Distinct: "distinct_0"},
+			"region": {Missing: "missing_1", Distinct: "distinct_1"},
+		}, preview.PropertyStatsColumns())
+	})
+
+	t.Run("Invalid sample timestamp", func(t *testing.T) {
+		preview := PreviewMeter{Meter: meter, Events: []*models.Event{{Type: "completion", Timestamp: "yesterday"}}}
+		_, _, err := preview.ToSQL()
+		assert.Error(t, err)
+	})
+}
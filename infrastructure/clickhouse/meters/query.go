@@ -2,6 +2,7 @@ package meters
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/huandu/go-sqlbuilder"
@@ -10,23 +11,35 @@ import (
 
 // QueryMeter represents the parameters used for querying meter data.
 type QueryMeter struct {
-	TenantSlug     string                 // Unique identifier for the tenant
-	MeterSlug      string                 // Unique identifier for the meter
-	Aggregation    models.AggregationEnum // Type of aggregation to apply (sum, count, etc.)
-	FilterGroupBy  map[string][]string    // Custom dimensions to filter and group by
-	From           *time.Time             // Start time of the query range
-	To             *time.Time             // End time of the query range
-	GroupBy        []string               // Dimensions to group results by
-	WindowSize     *models.WindowSize     // Time window size for time-based aggregations
-	WindowTimeZone *string                // Timezone to use for time-based windows (default is UTC)
+	TenantSlug     string                    // Unique identifier for the tenant
+	MeterSlug      string                    // Unique identifier for the meter
+	Aggregation    models.AggregationEnum    // Type of aggregation to apply (sum, count, etc.)
+	FilterGroupBy  map[string][]string       // Custom dimensions to filter and group by
+	Filter         *models.FilterExpression  // Property filter expression applied to the view rows
+	From           *time.Time                // Start time of the query range
+	To             *time.Time                // End time of the query range
+	GroupBy        []string                  // Dimensions to group results by
+	WindowSize     *models.WindowSize        // Time window size for time-based aggregations
+	WindowTimeZone *string                   // Timezone to use for time-based windows (default is UTC)
+	OrderBy        *models.QueryMeterOrderBy // Ordering of the result rows
+	Limit          *int                      // Maximum number of groups (or rows when not grouping) to return
+	Offset         *int                      // Number of groups (or rows when not grouping) to skip
+	IncludeOthers  bool                      // Aggregate groups outside the page into an "others" row
+	Granularity    models.Granularity        // Base granularity of the meter view, windows cannot be finer
+	Rollups        []models.Granularity      // Granularities of the rollup views kept for the meter
+}
 
+// windowIntervals maps query window sizes to ClickHouse intervals
+var windowIntervals = map[models.WindowSize]string{
+	models.WindowSizeMinute: "toIntervalMinute(1)",
+	models.WindowSizeHour:   "toIntervalHour(1)",
+	models.WindowSizeDay:    "toIntervalDay(1)",
 }
 
 func (q *QueryMeter) ToSQL() (string, []any, error) {
 	if q.WindowTimeZone != nil && *q.WindowTimeZone != "UTC" {
 		return "", nil, fmt.Errorf("Currently, only UTC is supported for WindowTimeZone")
 	}
-	viewName := GetMeterViewName(q.TenantSlug, q.MeterSlug)
 	var selectColumns []string
 	var groupByColumns []string
 	var adjustedFrom, adjustedTo time.Time
@@ -43,13 +56,13 @@ func (q *QueryMeter) ToSQL() (string, []any, error) {
 		if q.From == nil || q.To == nil {
 			return "", nil, fmt.Errorf("From/To must be provided when WindowSize is set")
 		}
+		if q.Granularity != "" && !q.Granularity.SupportsWindow(*q.WindowSize) {
+			return "", nil, fmt.Errorf("window size %s is finer than the meter granularity %s", *q.WindowSize, q.Granularity)
+		}
 		switch *q.WindowSize {
 		case models.WindowSizeMinute:
 			adjustedFrom = q.From.Truncate(time.Minute)
 			adjustedTo = q.To.Truncate(time.Minute)
-
-			selectColumns = append(selectColumns, "windowstart")
-			selectColumns = append(selectColumns, "windowend")
 		case models.WindowSizeHour:
 			adjustedFrom = q.From.Truncate(time.Hour)
 			truncatedTo := q.To.Truncate(time.Hour)
@@ -58,8 +71,6 @@ func (q *QueryMeter) ToSQL() (string, []any, error) {
 			} else {
 				adjustedTo = *q.To
 			}
-			selectColumns = append(selectColumns, fmt.Sprintf("tumbleStart(windowstart, toIntervalHour(1), '%s') AS windowstart", tz))
-			selectColumns = append(selectColumns, fmt.Sprintf("tumbleEnd(windowend, toIntervalHour(1), '%s') AS windowend", tz))
 		case models.WindowSizeDay:
 			from := *q.From
 			to := *q.To
@@ -69,45 +80,135 @@ func (q *QueryMeter) ToSQL() (string, []any, error) {
 			} else {
 				adjustedTo = to
 			}
-			selectColumns = append(selectColumns, fmt.Sprintf("tumbleStart(windowstart, toIntervalDay(1), '%s') AS windowstart", tz))
-			selectColumns = append(selectColumns, fmt.Sprintf("tumbleEnd(windowend, toIntervalDay(1), '%s') AS windowend", tz))
 		default:
 			return "", nil, fmt.Errorf("unsupported window size")
 		}
+		interval := windowIntervals[*q.WindowSize]
+		selectColumns = append(selectColumns, fmt.Sprintf("tumbleStart(windowstart, %s, '%s') AS windowstart", interval, tz))
+		selectColumns = append(selectColumns, fmt.Sprintf("tumbleEnd(windowend, %s, '%s') AS windowend", interval, tz))
 		groupByColumns = append(groupByColumns, "windowstart", "windowend")
 	} else {
 		selectColumns = append(selectColumns, "min(windowstart) AS windowstart", "max(windowend) AS windowend")
+		if q.From != nil {
+			adjustedFrom = *q.From
+		}
+		if q.To != nil {
+			adjustedTo = *q.To
+		}
 	}
+	windowColumns := append([]string(nil), selectColumns...)
+	windowGroupBy := append([]string(nil), groupByColumns...)
 
 	// Add value aggregation
-	switch q.Aggregation {
-	case models.AggregationSum:
-		selectColumns = append(selectColumns, "sumMerge(value) AS value")
-	case models.AggregationAvg:
-		selectColumns = append(selectColumns, "avgMerge(value) AS value")
-	case models.AggregationMin:
-		selectColumns = append(selectColumns, "minMerge(value) AS value")
-	case models.AggregationMax:
-		selectColumns = append(selectColumns, "maxMerge(value) AS value")
-	case models.AggregationUniqueCount:
-		selectColumns = append(selectColumns, "toFloat64(uniqMerge(value)) AS value")
-	case models.AggregationCount:
-		selectColumns = append(selectColumns, "toFloat64(countMerge(value)) AS value")
-	default:
-		return "", nil, fmt.Errorf("invalid aggregation type: %s", q.Aggregation)
+	mergeExpr, err := mergeValueExpr(q.Aggregation)
+	if err != nil {
+		return "", nil, err
 	}
+	selectColumns = append(selectColumns, mergeExpr+" AS value")
 
 	// Build the query using sqlbuilder
+	viewName := q.sourceView(adjustedFrom, adjustedTo)
 	builder := sqlbuilder.ClickHouse.NewSelectBuilder()
 	builder.From(viewName)
 
 	// Add group by columns
+	dimensions := make([]string, 0, len(q.GroupBy))
 	for _, column := range q.GroupBy {
-		safeCol := sqlbuilder.Escape(column)
-		selectColumns = append(selectColumns, safeCol)
-		groupByColumns = append(groupByColumns, safeCol)
+		dimensions = append(dimensions, sqlbuilder.Escape(column))
+	}
+	selectColumns = append(selectColumns, dimensions...)
+	groupByColumns = append(groupByColumns, dimensions...)
+
+	if err := q.addConditions(builder, adjustedFrom, adjustedTo); err != nil {
+		return "", nil, err
+	}
+
+	orderBy, err := q.orderByColumn(dimensions, mergeExpr)
+	if err != nil {
+		return "", nil, err
+	}
+	if q.Offset != nil && q.Limit == nil {
+		return "", nil, fmt.Errorf("Offset requires Limit to be set")
+	}
+
+	// When grouping, Limit/Offset page through groups ranked over the whole range
+	// rather than through individual window rows
+	paginateGroups := len(dimensions) > 0 && q.Limit != nil
+	var ranked *sqlbuilder.SelectBuilder
+	if paginateGroups {
+		ranked, err = q.rankedGroupsBuilder(viewName, dimensions, orderBy, adjustedFrom, adjustedTo)
+		if err != nil {
+			return "", nil, err
+		}
+		builder.Where(builder.In(dimensionsTuple(dimensions), ranked))
 	}
 
+	// Add GROUP BY clause
+	if len(groupByColumns) > 0 {
+		builder.GroupBy(groupByColumns...)
+	}
+
+	orderColumns := make([]string, 0, 2+len(dimensions))
+	if orderBy != nil {
+		if paginateGroups && groupByWindowSize && orderBy.column != "windowstart" {
+			orderColumns = append(orderColumns, "windowstart ASC")
+		}
+		orderColumns = append(orderColumns, orderBy.column+" "+orderBy.direction)
+		orderColumns = append(orderColumns, tieBreakers(dimensions, orderBy)...)
+	}
+
+	if !q.IncludeOthers {
+		builder.Select(selectColumns...)
+		if len(orderColumns) > 0 {
+			builder.OrderBy(orderColumns...)
+		}
+		if !paginateGroups && q.Limit != nil {
+			builder.Limit(*q.Limit)
+			if q.Offset != nil {
+				builder.Offset(*q.Offset)
+			}
+		}
+		sql, args := builder.Build()
+		return sql, args, nil
+	}
+
+	if !paginateGroups {
+		return "", nil, fmt.Errorf("IncludeOthers requires GroupBy and Limit to be set")
+	}
+	builder.Select(append(selectColumns, "0 AS others")...)
+
+	// Aggregate every group outside the ranked page into a single "others" series
+	othersBucket := sqlbuilder.ClickHouse.NewSelectBuilder()
+	othersBucket.Select(append(windowColumns, mergeExpr+" AS value")...)
+	othersBucket.From(viewName)
+	if err := q.addConditions(othersBucket, adjustedFrom, adjustedTo); err != nil {
+		return "", nil, err
+	}
+	othersBucket.Where(othersBucket.NotIn(dimensionsTuple(dimensions), ranked))
+	if len(windowGroupBy) > 0 {
+		othersBucket.GroupBy(windowGroupBy...)
+	}
+
+	othersColumns := []string{"windowstart", "windowend", "value"}
+	for _, dimension := range dimensions {
+		othersColumns = append(othersColumns, fmt.Sprintf("'' AS %s", dimension))
+	}
+	othersRow := sqlbuilder.ClickHouse.NewSelectBuilder()
+	othersRow.Select(append(othersColumns, "1 AS others")...)
+	othersRow.From(othersRow.BuilderAs(othersBucket, "others_bucket"))
+
+	union := sqlbuilder.ClickHouse.NewUnionBuilder().UnionAll(builder, othersRow)
+	result := sqlbuilder.ClickHouse.NewSelectBuilder()
+	result.Select("*")
+	result.From(result.BuilderAs(union, "results"))
+	result.OrderBy(append([]string{"others ASC"}, orderColumns...)...)
+
+	sql, args := result.Build()
+	return sql, args, nil
+}
+
+// addConditions applies the dimension filters, filter expression and time range to a query on the meter view.
+func (q *QueryMeter) addConditions(builder *sqlbuilder.SelectBuilder, from, to time.Time) error {
 	// Add group by filter conditions
 	for column, values := range q.FilterGroupBy {
 		if len(values) == 0 {
@@ -126,21 +227,120 @@ func (q *QueryMeter) ToSQL() (string, []any, error) {
 		}
 	}
 
+	// Add filter expression conditions
+	if q.Filter != nil {
+		expr, err := compileFilter(&builder.Cond, q.Filter)
+		if err != nil {
+			return err
+		}
+		builder.Where(expr)
+	}
+
 	// Add time range filters
-	if !adjustedFrom.IsZero() {
-		builder.Where(builder.GE("windowstart", adjustedFrom.Unix()))
+	if !from.IsZero() {
+		builder.Where(builder.GE("windowstart", from.Unix()))
 	}
-	if !adjustedTo.IsZero() {
-		builder.Where(builder.LE("windowend", adjustedTo.Unix()))
+	if !to.IsZero() {
+		builder.Where(builder.LE("windowend", to.Unix()))
 	}
+	return nil
+}
 
-	// Add GROUP BY clause
-	if len(groupByColumns) > 0 {
-		builder.GroupBy(groupByColumns...)
+type orderByColumn struct {
+	column    string // column of the result set to order by
+	rankExpr  string // expression used to rank groups
+	direction string
+}
+
+// orderByColumn resolves the requested ordering. Limiting results without an explicit
+// ordering ranks by value in descending order so that Limit yields the top N.
+func (q *QueryMeter) orderByColumn(dimensions []string, mergeExpr string) (*orderByColumn, error) {
+	if q.OrderBy == nil {
+		if q.Limit == nil {
+			return nil, nil
+		}
+		return &orderByColumn{column: "value", rankExpr: mergeExpr, direction: "DESC"}, nil
 	}
-	builder.Select(selectColumns...)
 
-	sql, args := builder.Build()
+	direction := "ASC"
+	switch q.OrderBy.Direction {
+	case models.SortAsc, "":
+	case models.SortDesc:
+		direction = "DESC"
+	default:
+		return nil, fmt.Errorf("invalid order direction: %s", q.OrderBy.Direction)
+	}
 
-	return sql, args, nil
+	switch q.OrderBy.Field {
+	case models.OrderByValue:
+		return &orderByColumn{column: "value", rankExpr: mergeExpr, direction: direction}, nil
+	case "windowstart":
+		// Groups are ranked by their earliest window ascending and their latest window descending
+		rankExpr := "min(windowstart)"
+		if direction == "DESC" {
+			rankExpr = "max(windowstart)"
+		}
+		return &orderByColumn{column: "windowstart", rankExpr: rankExpr, direction: direction}, nil
+	}
+
+	field := sqlbuilder.Escape(q.OrderBy.Field)
+	for _, dimension := range dimensions {
+		if dimension == field {
+			return &orderByColumn{column: field, rankExpr: field, direction: direction}, nil
+		}
+	}
+	return nil, fmt.Errorf("order by field must be value, windowstart or a group by dimension: %s", q.OrderBy.Field)
+}
+
+// rankedGroupsBuilder selects the dimension tuples of the requested page of groups.
+func (q *QueryMeter) rankedGroupsBuilder(viewName string, dimensions []string, orderBy *orderByColumn, from, to time.Time) (*sqlbuilder.SelectBuilder, error) {
+	ranked := sqlbuilder.ClickHouse.NewSelectBuilder()
+	ranked.Select(dimensions...)
+	ranked.From(viewName)
+	if err := q.addConditions(ranked, from, to); err != nil {
+		return nil, err
+	}
+	ranked.GroupBy(dimensions...)
+	ranked.OrderBy(append([]string{orderBy.rankExpr + " " + orderBy.direction}, tieBreakers(dimensions, orderBy)...)...)
+	ranked.Limit(*q.Limit)
+	if q.Offset != nil {
+		ranked.Offset(*q.Offset)
+	}
+	return ranked, nil
+}
+
+// tieBreakers orders groups ranking equally by their dimensions, so that pages of groups and the
+// groups left to the others bucket are the same on every call.
+func tieBreakers(dimensions []string, orderBy *orderByColumn) []string {
+	columns := make([]string, 0, len(dimensions))
+	for _, dimension := range dimensions {
+		if dimension != orderBy.column {
+			columns = append(columns, dimension+" ASC")
+		}
+	}
+	return columns
+}
+
+// mergeValueExpr returns the expression that finalizes the aggregate state stored in the view.
+func mergeValueExpr(aggregation models.AggregationEnum) (string, error) {
+	switch aggregation {
+	case models.AggregationSum:
+		return "sumMerge(value)", nil
+	case models.AggregationAvg:
+		return "avgMerge(value)", nil
+	case models.AggregationMin:
+		return "minMerge(value)", nil
+	case models.AggregationMax:
+		return "maxMerge(value)", nil
+	case models.AggregationUniqueCount:
+		return "toFloat64(uniqMerge(value))", nil
+	case models.AggregationCount:
+		return "toFloat64(countMerge(value))", nil
+	default:
+		return "", fmt.Errorf("invalid aggregation type: %s", aggregation)
+	}
+}
+
+func dimensionsTuple(dimensions []string) string {
+	return "(" + strings.Join(dimensions, ", ") + ")"
 }
@@ -52,6 +52,22 @@ func TestQueryMeterToSQL(t *testing.T) {
 				assert.Equal(t, toTime.Unix(), args[1])
 			},
 		},
+		{
+			name: "Query without window applies an open-ended range",
+			query: QueryMeter{
+				TenantSlug:  "test_tenant",
+				MeterSlug:   "page_views",
+				Aggregation: models.AggregationSum,
+				From:        fromTime,
+			},
+			wantErr: false,
+			checkResult: func(t *testing.T, sql string, args []any) {
+				sql = normalizeSQL(sql)
+				assert.Contains(t, sql, "WHERE windowstart >= ?")
+				assert.NotContains(t, sql, "windowend <=")
+				assert.Equal(t, []any{fromTime.Unix()}, args)
+			},
+		},
 		{
 			name: "Query with minute window size",
 			query: QueryMeter{
@@ -109,6 +125,38 @@ func TestQueryMeterToSQL(t *testing.T) {
 				assert.Contains(t, sql, "GROUP BY windowstart, windowend")
 			},
 		},
+		{
+			name: "Query with window finer than the meter granularity",
+			query: QueryMeter{
+				TenantSlug:     "test_tenant",
+				MeterSlug:      "page_views",
+				Aggregation:    models.AggregationSum,
+				From:           fromTime,
+				To:             toTime,
+				WindowSize:     &minute,
+				WindowTimeZone: &utc,
+				Granularity:    models.GranularityHour,
+			},
+			wantErr: true,
+		},
+		{
+			name: "Query with window coarser than the meter granularity",
+			query: QueryMeter{
+				TenantSlug:     "test_tenant",
+				MeterSlug:      "page_views",
+				Aggregation:    models.AggregationSum,
+				From:           fromTime,
+				To:             toTime,
+				WindowSize:     &minute,
+				WindowTimeZone: &utc,
+				Granularity:    models.GranularitySecond,
+			},
+			wantErr: false,
+			checkResult: func(t *testing.T, sql string, args []any) {
+				sql = normalizeSQL(sql)
+				assert.Contains(t, sql, "tumbleStart(windowstart, toIntervalMinute(1), 'UTC') AS windowstart")
+			},
+		},
 		{
 			name: "Query with avg aggregation",
 			query: QueryMeter{
@@ -405,3 +453,211 @@ func TestQueryMeterToSQL(t *testing.T) {
 		})
 	}
 }
+
+func TestQueryMeterPaginationToSQL(t *testing.T) {
+	from := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(30 * 24 * time.Hour)
+	day := models.WindowSizeDay
+	limit := 20
+	offset := 40
+
+	tests := []struct {
+		name        string
+		query       QueryMeter
+		wantErr     bool
+		checkResult func(t *testing.T, sql string, args []any)
+	}{
+		{
+			name: "Top N groups defaults to value descending",
+			query: QueryMeter{
+				TenantSlug:  "test_tenant",
+				MeterSlug:   "api_calls",
+				Aggregation: models.AggregationCount,
+				From:        &from,
+				To:          &to,
+				GroupBy:     []string{"organization"},
+				Limit:       &limit,
+			},
+			checkResult: func(t *testing.T, sql string, args []any) {
+				sql = normalizeSQL(sql)
+				assert.Contains(t, sql, "(organization) IN (SELECT organization FROM rc_test_tenant_api_calls_mv WHERE windowstart >= ? AND windowend <= ? GROUP BY organization ORDER BY toFloat64(countMerge(value)) DESC, organization ASC LIMIT ?)")
+				assert.True(t, strings.HasSuffix(sql, "GROUP BY organization ORDER BY value DESC, organization ASC"))
+				assert.Equal(t, []any{from.Unix(), to.Unix(), from.Unix(), to.Unix(), limit}, args)
+			},
+		},
+		{
+			name: "Order groups by earliest window",
+			query: QueryMeter{
+				TenantSlug:  "test_tenant",
+				MeterSlug:   "api_calls",
+				Aggregation: models.AggregationSum,
+				From:        &from,
+				To:          &to,
+				GroupBy:     []string{"organization"},
+				OrderBy:     &models.QueryMeterOrderBy{Field: "windowstart", Direction: models.SortAsc},
+				Limit:       &limit,
+			},
+			checkResult: func(t *testing.T, sql string, args []any) {
+				sql = normalizeSQL(sql)
+				assert.Contains(t, sql, "GROUP BY organization ORDER BY min(windowstart) ASC, organization ASC LIMIT ?)")
+				assert.NotContains(t, sql, "ORDER BY sumMerge(value)")
+			},
+		},
+		{
+			name: "Order groups by latest window",
+			query: QueryMeter{
+				TenantSlug:  "test_tenant",
+				MeterSlug:   "api_calls",
+				Aggregation: models.AggregationSum,
+				From:        &from,
+				To:          &to,
+				GroupBy:     []string{"organization"},
+				OrderBy:     &models.QueryMeterOrderBy{Field: "windowstart", Direction: models.SortDesc},
+				Limit:       &limit,
+			},
+			checkResult: func(t *testing.T, sql string, args []any) {
+				assert.Contains(t, normalizeSQL(sql), "GROUP BY organization ORDER BY max(windowstart) DESC, organization ASC LIMIT ?)")
+			},
+		},
+		{
+			name: "Order by dimension with offset",
+			query: QueryMeter{
+				TenantSlug:  "test_tenant",
+				MeterSlug:   "api_calls",
+				Aggregation: models.AggregationSum,
+				From:        &from,
+				To:          &to,
+				GroupBy:     []string{"organization"},
+				OrderBy:     &models.QueryMeterOrderBy{Field: "organization", Direction: models.SortAsc},
+				Limit:       &limit,
+				Offset:      &offset,
+			},
+			checkResult: func(t *testing.T, sql string, args []any) {
+				sql = normalizeSQL(sql)
+				assert.Contains(t, sql, "GROUP BY organization ORDER BY organization ASC LIMIT ? OFFSET ?)")
+				assert.True(t, strings.HasSuffix(sql, "ORDER BY organization ASC"))
+			},
+		},
+		{
+			name: "Groups with tied values are ordered by their dimensions",
+			query: QueryMeter{
+				TenantSlug:  "test_tenant",
+				MeterSlug:   "api_calls",
+				Aggregation: models.AggregationSum,
+				From:        &from,
+				To:          &to,
+				GroupBy:     []string{"organization", "model"},
+				OrderBy:     &models.QueryMeterOrderBy{Field: "model", Direction: models.SortDesc},
+				Limit:       &limit,
+				Offset:      &offset,
+			},
+			checkResult: func(t *testing.T, sql string, args []any) {
+				sql = normalizeSQL(sql)
+				// Groups sharing a model keep the same order on every page
+				assert.Contains(t, sql, "GROUP BY organization, model ORDER BY model DESC, organization ASC LIMIT ? OFFSET ?)")
+				assert.True(t, strings.HasSuffix(sql, "GROUP BY organization, model ORDER BY model DESC, organization ASC"))
+			},
+		},
+		{
+			name: "Windowed groups are ordered by window first",
+			query: QueryMeter{
+				TenantSlug:  "test_tenant",
+				MeterSlug:   "api_calls",
+				Aggregation: models.AggregationSum,
+				From:        &from,
+				To:          &to,
+				WindowSize:  &day,
+				GroupBy:     []string{"organization"},
+				Limit:       &limit,
+			},
+			checkResult: func(t *testing.T, sql string, args []any) {
+				sql = normalizeSQL(sql)
+				assert.True(t, strings.HasSuffix(sql, "ORDER BY windowstart ASC, value DESC, organization ASC"))
+			},
+		},
+		{
+			name: "Row pagination without grouping",
+			query: QueryMeter{
+				TenantSlug:  "test_tenant",
+				MeterSlug:   "api_calls",
+				Aggregation: models.AggregationSum,
+				From:        &from,
+				To:          &to,
+				WindowSize:  &day,
+				OrderBy:     &models.QueryMeterOrderBy{Field: "windowstart", Direction: models.SortDesc},
+				Limit:       &limit,
+				Offset:      &offset,
+			},
+			checkResult: func(t *testing.T, sql string, args []any) {
+				sql = normalizeSQL(sql)
+				assert.NotContains(t, sql, " IN (SELECT")
+				assert.True(t, strings.HasSuffix(sql, "ORDER BY windowstart DESC LIMIT ? OFFSET ?"))
+				assert.Equal(t, []any{limit, offset}, args[len(args)-2:])
+			},
+		},
+		{
+			name: "Others bucket",
+			query: QueryMeter{
+				TenantSlug:    "test_tenant",
+				MeterSlug:     "api_calls",
+				Aggregation:   models.AggregationAvg,
+				From:          &from,
+				To:            &to,
+				GroupBy:       []string{"organization", "model"},
+				Limit:         &limit,
+				IncludeOthers: true,
+			},
+			checkResult: func(t *testing.T, sql string, args []any) {
+				sql = normalizeSQL(sql)
+				assert.Contains(t, sql, "avgMerge(value) AS value, organization, model, 0 AS others")
+				assert.Contains(t, sql, "UNION ALL (SELECT windowstart, windowend, value, '' AS organization, '' AS model, 1 AS others FROM (SELECT min(windowstart) AS windowstart, max(windowend) AS windowend, avgMerge(value) AS value FROM rc_test_tenant_api_calls_mv")
+				assert.Contains(t, sql, "(organization, model) NOT IN (SELECT organization, model FROM")
+				assert.True(t, strings.HasSuffix(sql, "AS results ORDER BY others ASC, value DESC, organization ASC, model ASC"))
+			},
+		},
+		{
+			name: "Others bucket requires grouping",
+			query: QueryMeter{
+				TenantSlug:    "test_tenant",
+				MeterSlug:     "api_calls",
+				Aggregation:   models.AggregationSum,
+				Limit:         &limit,
+				IncludeOthers: true,
+			},
+			wantErr: true,
+		},
+		{
+			name: "Order by unknown field",
+			query: QueryMeter{
+				TenantSlug:  "test_tenant",
+				MeterSlug:   "api_calls",
+				Aggregation: models.AggregationSum,
+				GroupBy:     []string{"organization"},
+				OrderBy:     &models.QueryMeterOrderBy{Field: "model"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Offset without limit",
+			query: QueryMeter{
+				TenantSlug:  "test_tenant",
+				MeterSlug:   "api_calls",
+				Aggregation: models.AggregationSum,
+				Offset:      &offset,
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotSQL, gotArgs, err := tt.query.ToSQL()
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			tt.checkResult(t, gotSQL, gotArgs)
+		})
+	}
+}
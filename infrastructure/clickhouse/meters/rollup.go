@@ -0,0 +1,111 @@
+package meters
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/huandu/go-sqlbuilder"
+	"github.com/redcardinal-io/metering/domain/models"
+)
+
+// rollupGranularities lists every granularity a rollup view may exist for, coarsest first
+var rollupGranularities = []models.Granularity{models.GranularityDay, models.GranularityHour}
+
+// GetMeterRollupViewName returns the name of the rollup view of a meter at the given granularity.
+func GetMeterRollupViewName(organization, meterSlug string, granularity models.Granularity) string {
+	return fmt.Sprintf("%s_%s", GetMeterViewName(organization, meterSlug), granularity)
+}
+
+// ToRollupSQLs returns the statements creating the rollup views of the meter, in creation order.
+// Each rollup is chained from the next finer view and merges its aggregate states, so events are
+// aggregated from rc_events only once.
+func (c *CreateMeter) ToRollupSQLs() ([]string, error) {
+	agg, ok := aggregationMap[c.Aggregation]
+	if !ok {
+		return nil, fmt.Errorf("invalid aggregation type: %s", c.Aggregation)
+	}
+
+	columns, orderBy := c.viewColumns(agg.mergeFunc, agg.dataType)
+	populateClause := ""
+	if c.Populate {
+		populateClause = "POPULATE"
+	}
+
+	propertyNames := make([]string, len(c.Properties))
+	copy(propertyNames, c.Properties)
+	sort.Strings(propertyNames)
+
+	source := GetMeterViewName(c.TenantSlug, c.Slug)
+	statements := make([]string, 0, len(c.Rollups))
+	for _, granularity := range c.Rollups {
+		interval, ok := granularityIntervals[granularity]
+		if !ok || granularity.Duration() <= c.granularity().Duration() {
+			return nil, fmt.Errorf("invalid rollup granularity %s for meter granularity %s", granularity, c.granularity())
+		}
+
+		query := sqlbuilder.ClickHouse.NewSelectBuilder()
+		selectColumns := []string{
+			"organization",
+			"user",
+			fmt.Sprintf("tumbleStart(windowstart, %s, 'UTC') AS windowstart", interval),
+			fmt.Sprintf("tumbleEnd(windowstart, %s, 'UTC') AS windowend", interval),
+			fmt.Sprintf("%sMergeState(value) AS value", agg.mergeFunc),
+		}
+		groupByColumns := []string{"windowstart", "windowend", "organization", "user"}
+		for _, name := range propertyNames {
+			selectColumns = append(selectColumns, sqlbuilder.Escape(name))
+			groupByColumns = append(groupByColumns, sqlbuilder.Escape(name))
+		}
+		query.Select(selectColumns...)
+		query.From(source)
+		query.GroupBy(groupByColumns...)
+		selectSQL, _ := query.Build()
+
+		viewName := GetMeterRollupViewName(c.TenantSlug, c.Slug, granularity)
+		statements = append(statements, fmt.Sprintf(`
+		CREATE MATERIALIZED VIEW IF NOT EXISTS %s (
+		 %s
+		) ENGINE = AggregatingMergeTree()
+		ORDER BY (%s)
+		%s AS %s
+		`, viewName, columns, orderBy, populateClause, selectSQL))
+		source = viewName
+	}
+
+	return statements, nil
+}
+
+// RollupSQLs returns the statements dropping every rollup view of the meter, coarsest first,
+// so that no view is dropped while another view still reads from it.
+func (d *DeleteMeter) RollupSQLs() []string {
+	statements := make([]string, 0, len(rollupGranularities))
+	for _, granularity := range rollupGranularities {
+		viewName := GetMeterRollupViewName(d.TenantSlug, d.MeterSlug, granularity)
+		sql, _ := sqlbuilder.Buildf("drop view if exists %s", sqlbuilder.Raw(viewName)).Build()
+		statements = append(statements, sql)
+	}
+	return statements
+}
+
+// sourceView returns the coarsest view able to answer the query exactly: its buckets must divide
+// the requested windows and the range boundaries must fall on bucket boundaries.
+func (q *QueryMeter) sourceView(from, to time.Time) string {
+	viewName := GetMeterViewName(q.TenantSlug, q.MeterSlug)
+	best := time.Duration(0)
+	for _, granularity := range q.Rollups {
+		size := granularity.Duration()
+		if size <= best {
+			continue
+		}
+		if q.WindowSize != nil && !granularity.SupportsWindow(*q.WindowSize) {
+			continue
+		}
+		if !from.Truncate(size).Equal(from) || !to.Truncate(size).Equal(to) {
+			continue
+		}
+		viewName = GetMeterRollupViewName(q.TenantSlug, q.MeterSlug, granularity)
+		best = size
+	}
+	return viewName
+}
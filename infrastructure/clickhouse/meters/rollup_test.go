@@ -0,0 +1,105 @@
+package meters
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/redcardinal-io/metering/domain/models"
+)
+
+func TestCreateMeterRollups(t *testing.T) {
+	meter := CreateMeter{
+		Slug:        "api_requests",
+		EventType:   "api_request",
+		Properties:  []string{"method"},
+		Aggregation: models.AggregationCount,
+		TenantSlug:  "test_tenant",
+		Granularity: models.GranularityMinute,
+		Rollups:     []models.Granularity{models.GranularityHour, models.GranularityDay},
+	}
+
+	statements, err := meter.ToRollupSQLs()
+	require.NoError(t, err)
+	require.Len(t, statements, 2)
+
+	hourly := normalizeSQL(statements[0])
+	assert.Contains(t, hourly, "CREATE MATERIALIZED VIEW IF NOT EXISTS rc_test_tenant_api_requests_mv_hour")
+	assert.Contains(t, hourly, "value AggregateFunction(count, Float64)")
+	assert.Contains(t, hourly, "tumbleStart(windowstart, toIntervalHour(1), 'UTC') AS windowstart")
+	assert.Contains(t, hourly, "countMergeState(value) AS value")
+	assert.Contains(t, hourly, "FROM rc_test_tenant_api_requests_mv GROUP BY windowstart, windowend, organization, user, method")
+
+	daily := normalizeSQL(statements[1])
+	assert.Contains(t, daily, "CREATE MATERIALIZED VIEW IF NOT EXISTS rc_test_tenant_api_requests_mv_day")
+	assert.Contains(t, daily, "FROM rc_test_tenant_api_requests_mv_hour")
+
+	t.Run("Rejects rollups not coarser than the base granularity", func(t *testing.T) {
+		invalid := meter
+		invalid.Granularity = models.GranularityHour
+		_, err := invalid.ToRollupSQLs()
+		assert.Error(t, err)
+	})
+}
+
+func TestDeleteMeterRollups(t *testing.T) {
+	d := DeleteMeter{TenantSlug: "test_tenant", MeterSlug: "api_requests"}
+	assert.Equal(t, []string{
+		"drop view if exists rc_test_tenant_api_requests_mv_day",
+		"drop view if exists rc_test_tenant_api_requests_mv_hour",
+	}, d.RollupSQLs())
+}
+
+func TestQueryMeterRollupSelection(t *testing.T) {
+	hour := models.WindowSizeHour
+	day := models.WindowSizeDay
+	rollups := []models.Granularity{models.GranularityHour, models.GranularityDay}
+	ptr := func(t time.Time) *time.Time { return &t }
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		query    QueryMeter
+		wantView string
+	}{
+		{
+			name:     "Daily windows use the daily rollup",
+			query:    QueryMeter{From: ptr(from), To: ptr(to), WindowSize: &day, Rollups: rollups},
+			wantView: "FROM rc_test_tenant_api_requests_mv_day ",
+		},
+		{
+			name:     "Hourly windows use the hourly rollup",
+			query:    QueryMeter{From: ptr(from), To: ptr(to), WindowSize: &hour, Rollups: rollups},
+			wantView: "FROM rc_test_tenant_api_requests_mv_hour ",
+		},
+		{
+			name:     "Range aligned to hours uses the hourly rollup",
+			query:    QueryMeter{From: ptr(from.Add(3 * time.Hour)), To: ptr(to), Rollups: rollups},
+			wantView: "FROM rc_test_tenant_api_requests_mv_hour ",
+		},
+		{
+			name:     "Unaligned range uses the base view",
+			query:    QueryMeter{From: ptr(from.Add(90 * time.Second)), To: ptr(to), Rollups: rollups},
+			wantView: "FROM rc_test_tenant_api_requests_mv ",
+		},
+		{
+			name:     "Meters without rollups use the base view",
+			query:    QueryMeter{From: ptr(from), To: ptr(to), WindowSize: &day},
+			wantView: "FROM rc_test_tenant_api_requests_mv ",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.query.TenantSlug = "test_tenant"
+			tt.query.MeterSlug = "api_requests"
+			tt.query.Aggregation = models.AggregationCount
+			sql, _, err := tt.query.ToSQL()
+			require.NoError(t, err)
+			assert.Contains(t, normalizeSQL(sql), tt.wantView)
+		})
+	}
+}
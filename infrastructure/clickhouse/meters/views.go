@@ -0,0 +1,64 @@
+package meters
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/huandu/go-sqlbuilder"
+	"github.com/redcardinal-io/metering/domain/models"
+)
+
+// meterViewPattern matches the names of meter views and their rollups. rc_events_mv, which feeds
+// rc_events from Kafka, has no tenant segment and does not match.
+var meterViewPattern = regexp.MustCompile(`^rc_.+_.+_mv(_hour|_day)?$`)
+
+// IsMeterViewName reports whether name follows the naming of meter views and their rollups.
+func IsMeterViewName(name string) bool {
+	return meterViewPattern.MatchString(name)
+}
+
+// ExpectedViews returns the views the meter should have with their columns, base view first.
+func (c *CreateMeter) ExpectedViews() ([]models.OlapView, error) {
+	agg, ok := aggregationMap[c.Aggregation]
+	if !ok {
+		return nil, fmt.Errorf("invalid aggregation type: %s", c.Aggregation)
+	}
+
+	columns := c.columnDefinitions(agg.mergeFunc, agg.dataType)
+	views := []models.OlapView{{Name: GetMeterViewName(c.TenantSlug, c.Slug), Columns: columns}}
+	for _, granularity := range c.Rollups {
+		views = append(views, models.OlapView{
+			Name:    GetMeterRollupViewName(c.TenantSlug, c.Slug, granularity),
+			Columns: columns,
+		})
+	}
+	return views, nil
+}
+
+// ListViewsSQL returns the query listing the columns of every materialized view in the current
+// database, in declaration order.
+func ListViewsSQL() (string, []any) {
+	tables := sqlbuilder.ClickHouse.NewSelectBuilder()
+	tables.Select("name").From("system.tables")
+	tables.Where(
+		"database = currentDatabase()",
+		tables.Equal("engine", "MaterializedView"),
+	)
+
+	columns := sqlbuilder.ClickHouse.NewSelectBuilder()
+	columns.Select("table", "name", "type").From("system.columns")
+	columns.Where(
+		"database = currentDatabase()",
+		columns.In("table", tables),
+	)
+	columns.OrderBy("table", "position")
+	return columns.Build()
+}
+
+// DropViewSQL returns the statement dropping a meter view. Only meter view names are accepted.
+func DropViewSQL(name string) (string, error) {
+	if !IsMeterViewName(name) {
+		return "", fmt.Errorf("%s is not a meter view", name)
+	}
+	return fmt.Sprintf("drop view if exists %s", name), nil
+}
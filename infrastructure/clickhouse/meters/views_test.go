@@ -0,0 +1,51 @@
+package meters
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/redcardinal-io/metering/domain/models"
+)
+
+func TestExpectedViews(t *testing.T) {
+	meter := CreateMeter{
+		Slug:          "api_tokens",
+		EventType:     "completion",
+		ValueProperty: "tokens",
+		Properties:    []string{"region", "model"},
+		Aggregation:   models.AggregationSum,
+		TenantSlug:    "test_tenant",
+		Rollups:       []models.Granularity{models.GranularityHour, models.GranularityDay},
+	}
+
+	views, err := meter.ExpectedViews()
+	require.NoError(t, err)
+	require.Len(t, views, 3)
+	assert.Equal(t, "rc_test_tenant_api_tokens_mv", views[0].Name)
+	assert.Equal(t, "rc_test_tenant_api_tokens_mv_hour", views[1].Name)
+	assert.Equal(t, "rc_test_tenant_api_tokens_mv_day", views[2].Name)
+	assert.Equal(t, []models.OlapColumn{
+		{Name: "organization", Type: "String"},
+		{Name: "user", Type: "String"},
+		{Name: "windowstart", Type: "DateTime"},
+		{Name: "windowend", Type: "DateTime"},
+		{Name: "value", Type: "AggregateFunction(sum, Float64)"},
+		{Name: "model", Type: "String"},
+		{Name: "region", Type: "String"},
+	}, views[0].Columns)
+}
+
+func TestIsMeterViewName(t *testing.T) {
+	assert.True(t, IsMeterViewName("rc_tenant_requests_mv"))
+	assert.True(t, IsMeterViewName("rc_tenant_requests_mv_day"))
+	assert.False(t, IsMeterViewName("rc_events_mv"))
+	assert.False(t, IsMeterViewName("rc_events"))
+
+	_, err := DropViewSQL("rc_events_mv")
+	assert.Error(t, err)
+	sql, err := DropViewSQL("rc_tenant_requests_mv_hour")
+	require.NoError(t, err)
+	assert.Equal(t, "drop view if exists rc_tenant_requests_mv_hour", sql)
+}
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/ClickHouse/clickhouse-go/v2"
+	"github.com/huandu/go-sqlbuilder"
 	"github.com/jmoiron/sqlx"
 	"github.com/redcardinal-io/metering/application/repositories"
 	domainerrors "github.com/redcardinal-io/metering/domain/errors"
@@ -62,13 +63,18 @@ func (olap *ClickHouseOlap) Connect(cfg *config.OlapConfig) error {
 func (olap *ClickHouseOlap) CreateMeter(ctx context.Context, arg models.CreateMeterInput) error {
 	tenantSlug := ctx.Value(constants.TenantSlugKey).(string)
 	createMeter := meters.CreateMeter{
-		Slug:          arg.MeterSlug,
-		TenantSlug:    tenantSlug,
-		ValueProperty: arg.ValueProperty,
-		Populate:      arg.Populate,
-		Properties:    arg.Properties,
-		Aggregation:   arg.Aggregation,
-		EventType:     arg.EventType,
+		Slug:            arg.MeterSlug,
+		TenantSlug:      tenantSlug,
+		ValueProperty:   arg.ValueProperty,
+		Populate:        arg.Populate,
+		Properties:      arg.Properties,
+		Aggregation:     arg.Aggregation,
+		EventType:       arg.EventType,
+		EventTypes:      arg.EventTypes,
+		ValueProperties: arg.ValueProperties,
+		Granularity:     arg.Granularity,
+		Rollups:         arg.Rollups,
+		Filter:          arg.Filter,
 	}
 
 	sql, args, err := createMeter.ToCreateSQL()
@@ -83,28 +89,61 @@ func (olap *ClickHouseOlap) CreateMeter(ctx context.Context, arg models.CreateMe
 		)
 	}
 
+	rollupSQLs, err := createMeter.ToRollupSQLs()
+	if err != nil {
+		return domainerrors.New(err,
+			domainerrors.EOLAP,
+			"Error generating meter rollup SQL",
+			domainerrors.
+				WithOperation("ClickHouse.CreateMeter"),
+		)
+	}
+
 	_, err = olap.db.ExecContext(ctx, sql, args...)
 	if err != nil {
 		return MapError(err, "ClickHouse.CreateMeter")
 	}
 
+	for _, rollupSQL := range rollupSQLs {
+		olap.logger.Debug("Creating meter rollup SQL", zap.String("sql", rollupSQL))
+		if _, err := olap.db.ExecContext(ctx, rollupSQL); err != nil {
+			// Drop the views created so far so the meter can be created again
+			if dropErr := olap.DeleteMeter(ctx, arg.MeterSlug); dropErr != nil {
+				olap.logger.Error("Failed to drop partially created meter", zap.String("meter", arg.MeterSlug), zap.Error(dropErr))
+			}
+			return MapError(err, "ClickHouse.CreateMeter")
+		}
+	}
+
 	olap.logger.Info("Created meter", zap.String("meter", meters.GetMeterViewName(tenantSlug, arg.MeterSlug)))
 	return nil
 }
 
-func (olap *ClickHouseOlap) QueryMeter(ctx context.Context, input models.QueryMeterParams, agg *models.AggregationEnum) (*models.QueryMeterResult, error) {
-	tenantSlug := ctx.Value(constants.TenantSlugKey).(string)
-	queryMeter := meters.QueryMeter{
-		TenantSlug:     tenantSlug,
+// newQueryMeter builds the ClickHouse query for a meter query of the tenant in ctx.
+func newQueryMeter(ctx context.Context, input models.QueryMeterParams, meter *models.Meter) meters.QueryMeter {
+	return meters.QueryMeter{
+		TenantSlug:     ctx.Value(constants.TenantSlugKey).(string),
 		MeterSlug:      input.MeterSlug,
 		FilterGroupBy:  input.FilterGroupBy,
+		Filter:         input.Filter,
 		From:           input.From,
 		To:             input.To,
 		GroupBy:        input.GroupBy,
 		WindowSize:     input.WindowSize,
 		WindowTimeZone: input.WindowTimeZone,
-		Aggregation:    *agg,
+		OrderBy:        input.OrderBy,
+		Limit:          input.Limit,
+		Offset:         input.Offset,
+		IncludeOthers:  input.IncludeOthers,
+		Aggregation:    meter.Aggregation,
+		Granularity:    meter.Granularity,
+		Rollups:        meter.Rollups,
 	}
+}
+
+func (olap *ClickHouseOlap) QueryMeter(ctx context.Context, input models.QueryMeterParams, meter *models.Meter) (*models.QueryMeterResult, error) {
+	tenantSlug := ctx.Value(constants.TenantSlugKey).(string)
+	queryMeter := newQueryMeter(ctx, input, meter)
 
 	sql, args, err := queryMeter.ToSQL()
 	if err != nil {
@@ -140,6 +179,162 @@ func (olap *ClickHouseOlap) QueryMeter(ctx context.Context, input models.QueryMe
 	}, nil
 }
 
+func (olap *ClickHouseOlap) StreamMeter(ctx context.Context, input models.QueryMeterParams, meter *models.Meter, fn func(row models.QueryMeterRow) error) error {
+	tenantSlug := ctx.Value(constants.TenantSlugKey).(string)
+	queryMeter := newQueryMeter(ctx, input, meter)
+
+	sql, args, err := queryMeter.ToSQL()
+	if err != nil {
+		return domainerrors.New(err,
+			domainerrors.EOLAP,
+			"Error generating meter query SQL",
+			domainerrors.
+				WithOperation("ClickHouse.StreamMeter"),
+		)
+	}
+	olap.logger.Debug("Streaming meter SQL", zap.String("sql", sql), zap.Any("args", args))
+
+	rows, err := olap.db.QueryxContext(ctx, sql, args...)
+	if err != nil {
+		return MapError(err, "ClickHouse.StreamMeter")
+	}
+	defer rows.Close()
+
+	scan, err := olap.newRowScanner(rows)
+	if err != nil {
+		return MapError(err, "ClickHouse.StreamMeter")
+	}
+	count := 0
+	for rows.Next() {
+		row, err := scan()
+		if err != nil {
+			return MapError(err, "ClickHouse.StreamMeter")
+		}
+		if err := fn(row); err != nil {
+			return err
+		}
+		count++
+	}
+	if err := rows.Err(); err != nil {
+		return MapError(err, "ClickHouse.StreamMeter")
+	}
+
+	olap.logger.Debug("Streamed meter", zap.String("meter", meters.GetMeterViewName(tenantSlug, input.MeterSlug)), zap.Int("rows", count))
+	return nil
+}
+
+func (olap *ClickHouseOlap) PreviewMeter(ctx context.Context, input models.PreviewMeterInput) (*models.MeterPreview, error) {
+	tenantSlug := ctx.Value(constants.TenantSlugKey).(string)
+	preview := meters.PreviewMeter{
+		Meter: meters.CreateMeter{
+			Slug:            input.Meter.MeterSlug,
+			TenantSlug:      tenantSlug,
+			ValueProperty:   input.Meter.ValueProperty,
+			Properties:      input.Meter.Properties,
+			Aggregation:     input.Meter.Aggregation,
+			EventType:       input.Meter.EventType,
+			EventTypes:      input.Meter.EventTypes,
+			ValueProperties: input.Meter.ValueProperties,
+			Granularity:     input.Meter.Granularity,
+			Filter:          input.Meter.Filter,
+		},
+		Events: input.Events,
+		From:   input.From,
+		To:     input.To,
+		Limit:  input.Limit,
+	}
+
+	createSQL, createArgs, err := preview.Meter.ToCreateSQL()
+	if err == nil {
+		createSQL, err = sqlbuilder.ClickHouse.Interpolate(createSQL, createArgs)
+	}
+	if err != nil {
+		return nil, domainerrors.New(err,
+			domainerrors.EOLAP,
+			"Error generating meter creation SQL",
+			domainerrors.
+				WithOperation("ClickHouse.PreviewMeter"),
+		)
+	}
+	rowsSQL, rowsArgs, err := preview.ToSQL()
+	if err != nil {
+		return nil, domainerrors.New(err,
+			domainerrors.EINVALID,
+			"Error generating meter preview SQL",
+			domainerrors.
+				WithOperation("ClickHouse.PreviewMeter"),
+		)
+	}
+	statsSQL, statsArgs, err := preview.ToStatsSQL()
+	if err != nil {
+		return nil, domainerrors.New(err,
+			domainerrors.EINVALID,
+			"Error generating meter preview SQL",
+			domainerrors.
+				WithOperation("ClickHouse.PreviewMeter"),
+		)
+	}
+	olap.logger.Debug("Previewing meter SQL", zap.String("sql", rowsSQL), zap.String("stats_sql", statsSQL))
+
+	result := &models.MeterPreview{
+		SQL:  createSQL,
+		Rows: make([]models.MeterPreviewRow, 0),
+	}
+
+	rows, err := olap.db.QueryxContext(ctx, rowsSQL, rowsArgs...)
+	if err != nil {
+		return nil, MapError(err, "ClickHouse.PreviewMeter")
+	}
+	defer rows.Close()
+	for rows.Next() {
+		values, err := rows.SliceScan()
+		if err != nil {
+			return nil, MapError(err, "ClickHouse.PreviewMeter")
+		}
+		columns, _ := rows.Columns()
+		row := models.MeterPreviewRow{Properties: make(map[string]string)}
+		for i, column := range columns {
+			switch column {
+			case "organization":
+				row.Organization = fmt.Sprintf("%v", values[i])
+			case "user":
+				row.User = fmt.Sprintf("%v", values[i])
+			case "windowstart":
+				row.WindowStart, _ = values[i].(time.Time)
+			case "windowend":
+				row.WindowEnd, _ = values[i].(time.Time)
+			case "value":
+				row.Value = toFloat64(values[i])
+			default:
+				row.Properties[column] = fmt.Sprintf("%v", values[i])
+			}
+		}
+		result.Rows = append(result.Rows, row)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, MapError(err, "ClickHouse.PreviewMeter")
+	}
+
+	stats := make(map[string]any)
+	if err := olap.db.QueryRowxContext(ctx, statsSQL, statsArgs...).MapScan(stats); err != nil {
+		return nil, MapError(err, "ClickHouse.PreviewMeter")
+	}
+	result.Stats = models.MeterPreviewStats{
+		Events:               int(toFloat64(stats["events"])),
+		MissingValueProperty: int(toFloat64(stats["missing_value"])),
+		NonNumericValues:     int(toFloat64(stats["non_numeric"])),
+		Properties:           make(map[string]models.MeterPreviewPropertyStats),
+	}
+	for property, columns := range preview.PropertyStatsColumns() {
+		result.Stats.Properties[property] = models.MeterPreviewPropertyStats{
+			Missing:  int(toFloat64(stats[columns.Missing])),
+			Distinct: int(toFloat64(stats[columns.Distinct])),
+		}
+	}
+
+	return result, nil
+}
+
 func (olap *ClickHouseOlap) Close() error {
 	if olap.db != nil {
 		return MapError(olap.db.Close(), "ClickHouse.Close")
@@ -155,6 +350,14 @@ func (olap *ClickHouseOlap) DeleteMeter(ctx context.Context, meterSlug string) e
 		MeterSlug:  meterSlug,
 	}
 
+	// Rollups read from the base view, so they are dropped first
+	for _, rollupSQL := range deleteMeter.RollupSQLs() {
+		olap.logger.Debug("Deleting meter rollup SQL", zap.String("sql", rollupSQL))
+		if _, err := olap.db.ExecContext(ctx, rollupSQL); err != nil {
+			return MapError(err, "ClickHouse.DeleteMeter")
+		}
+	}
+
 	sql, args := deleteMeter.ToSQL()
 	olap.logger.Debug("Deleting meter SQL", zap.String("sql", sql), zap.Any("args", args))
 
@@ -167,10 +370,103 @@ func (olap *ClickHouseOlap) DeleteMeter(ctx context.Context, meterSlug string) e
 	return nil
 }
 
+func (olap *ClickHouseOlap) ListMeterViews(ctx context.Context) ([]models.OlapView, error) {
+	sql, args := meters.ListViewsSQL()
+	rows, err := olap.db.QueryxContext(ctx, sql, args...)
+	if err != nil {
+		return nil, MapError(err, "ClickHouse.ListMeterViews")
+	}
+	defer rows.Close()
+
+	views := make([]models.OlapView, 0)
+	for rows.Next() {
+		var table string
+		var column models.OlapColumn
+		if err := rows.Scan(&table, &column.Name, &column.Type); err != nil {
+			return nil, MapError(err, "ClickHouse.ListMeterViews")
+		}
+		if !meters.IsMeterViewName(table) {
+			continue
+		}
+		if len(views) == 0 || views[len(views)-1].Name != table {
+			views = append(views, models.OlapView{Name: table})
+		}
+		views[len(views)-1].Columns = append(views[len(views)-1].Columns, column)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, MapError(err, "ClickHouse.ListMeterViews")
+	}
+
+	return views, nil
+}
+
+func (olap *ClickHouseOlap) ExpectedMeterViews(meter *models.Meter) ([]models.OlapView, error) {
+	createMeter := meters.CreateMeter{
+		Slug:          meter.Slug,
+		TenantSlug:    meter.TenantSlug,
+		ValueProperty: meter.ValueProperty,
+		Properties:    meter.Properties,
+		Aggregation:   meter.Aggregation,
+		EventType:     meter.EventType,
+		Granularity:   meter.Granularity,
+		Rollups:       meter.Rollups,
+	}
+	views, err := createMeter.ExpectedViews()
+	if err != nil {
+		return nil, domainerrors.New(err,
+			domainerrors.EINVALID,
+			"Error describing meter views",
+			domainerrors.
+				WithOperation("ClickHouse.ExpectedMeterViews"),
+		)
+	}
+	return views, nil
+}
+
+func (olap *ClickHouseOlap) DropMeterView(ctx context.Context, name string) error {
+	sql, err := meters.DropViewSQL(name)
+	if err != nil {
+		return domainerrors.New(err,
+			domainerrors.EINVALID,
+			"Error dropping meter view",
+			domainerrors.
+				WithOperation("ClickHouse.DropMeterView"),
+		)
+	}
+
+	olap.logger.Debug("Dropping meter view SQL", zap.String("sql", sql))
+	if _, err := olap.db.ExecContext(ctx, sql); err != nil {
+		return MapError(err, "ClickHouse.DropMeterView")
+	}
+
+	olap.logger.Info("Dropped meter view", zap.String("view", name))
+	return nil
+}
+
 func (olap *ClickHouseOlap) GetDB() any {
 	return olap.db
 }
 
+// toFloat64 converts a numeric column value, returning zero for anything else.
+func toFloat64(val any) float64 {
+	switch v := val.(type) {
+	case float64:
+		return v
+	case float32:
+		return float64(v)
+	case int64:
+		return float64(v)
+	case uint64:
+		return float64(v)
+	case uint32:
+		return float64(v)
+	case int32:
+		return float64(v)
+	default:
+		return 0
+	}
+}
+
 func determineQueryTimeRange(results []models.QueryMeterRow, From *time.Time, To *time.Time) (*time.Time, *time.Time) {
 	if len(results) == 0 {
 		return From, To
@@ -204,6 +500,24 @@ func determineQueryTimeRange(results []models.QueryMeterRow, From *time.Time, To
 func (olap *ClickHouseOlap) scanRows(rows *sqlx.Rows) ([]models.QueryMeterRow, error) {
 	var results []models.QueryMeterRow
 
+	scan, err := olap.newRowScanner(rows)
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		row, err := scan()
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, row)
+	}
+
+	return results, rows.Err()
+}
+
+// newRowScanner returns a function converting the current row of rows into a QueryMeterRow.
+// The scan buffers are shared between calls, so rows can be consumed one at a time.
+func (olap *ClickHouseOlap) newRowScanner(rows *sqlx.Rows) (func() (models.QueryMeterRow, error), error) {
 	columns, err := rows.Columns()
 	if err != nil {
 		return nil, err
@@ -215,9 +529,9 @@ func (olap *ClickHouseOlap) scanRows(rows *sqlx.Rows) ([]models.QueryMeterRow, e
 		valuePtrs[i] = &values[i]
 	}
 
-	for rows.Next() {
+	return func() (models.QueryMeterRow, error) {
 		if err := rows.Scan(valuePtrs...); err != nil {
-			return nil, err
+			return models.QueryMeterRow{}, err
 		}
 
 		row := models.QueryMeterRow{
@@ -249,6 +563,10 @@ func (olap *ClickHouseOlap) scanRows(rows *sqlx.Rows) ([]models.QueryMeterRow, e
 				} else if i, ok := val.(int64); ok {
 					row.Value = float64(i)
 				}
+			case "others":
+				if u, ok := val.(uint8); ok {
+					row.Others = u == 1
+				}
 			default:
 				if val != nil {
 					row.GroupBy[col] = fmt.Sprintf("%v", val)
@@ -256,8 +574,6 @@ func (olap *ClickHouseOlap) scanRows(rows *sqlx.Rows) ([]models.QueryMeterRow, e
 			}
 		}
 
-		results = append(results, row)
-	}
-
-	return results, nil
+		return row, nil
+	}, nil
 }
@@ -0,0 +1,402 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: alert.sql
+
+package gen
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const countAlertRules = `-- name: CountAlertRules :one
+select count(*) from alert_rule
+where tenant_slug = $1
+`
+
+func (q *Queries) CountAlertRules(ctx context.Context, tenantSlug string) (int64, error) {
+	row := q.db.QueryRow(ctx, countAlertRules, tenantSlug)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const createAlertRule = `-- name: CreateAlertRule :one
+insert into alert_rule (
+  tenant_slug,
+  name,
+  meter_slug,
+  plan_feature_id,
+  subject_type,
+  subject_ids,
+  threshold_type,
+  threshold,
+  window_minutes,
+  enabled,
+  created_by,
+  updated_by
+) values (
+  $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12
+) returning id, tenant_slug, name, meter_slug, plan_feature_id, subject_type, subject_ids, threshold_type, threshold, window_minutes, enabled, created_at, updated_at, created_by, updated_by
+`
+
+type CreateAlertRuleParams struct {
+	TenantSlug    string
+	Name          string
+	MeterSlug     pgtype.Text
+	PlanFeatureID pgtype.UUID
+	SubjectType   SubjectTypeEnum
+	SubjectIds    []string
+	ThresholdType AlertThresholdTypeEnum
+	Threshold     float64
+	WindowMinutes pgtype.Int8
+	Enabled       bool
+	CreatedBy     string
+	UpdatedBy     string
+}
+
+func (q *Queries) CreateAlertRule(ctx context.Context, arg CreateAlertRuleParams) (AlertRule, error) {
+	row := q.db.QueryRow(ctx, createAlertRule,
+		arg.TenantSlug,
+		arg.Name,
+		arg.MeterSlug,
+		arg.PlanFeatureID,
+		arg.SubjectType,
+		arg.SubjectIds,
+		arg.ThresholdType,
+		arg.Threshold,
+		arg.WindowMinutes,
+		arg.Enabled,
+		arg.CreatedBy,
+		arg.UpdatedBy,
+	)
+	var i AlertRule
+	err := row.Scan(
+		&i.ID,
+		&i.TenantSlug,
+		&i.Name,
+		&i.MeterSlug,
+		&i.PlanFeatureID,
+		&i.SubjectType,
+		&i.SubjectIds,
+		&i.ThresholdType,
+		&i.Threshold,
+		&i.WindowMinutes,
+		&i.Enabled,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.CreatedBy,
+		&i.UpdatedBy,
+	)
+	return i, err
+}
+
+const deleteAlertRule = `-- name: DeleteAlertRule :exec
+delete from alert_rule
+where id = $1
+and tenant_slug = $2
+`
+
+type DeleteAlertRuleParams struct {
+	ID         pgtype.UUID
+	TenantSlug string
+}
+
+func (q *Queries) DeleteAlertRule(ctx context.Context, arg DeleteAlertRuleParams) error {
+	_, err := q.db.Exec(ctx, deleteAlertRule, arg.ID, arg.TenantSlug)
+	return err
+}
+
+const getAlertRuleByID = `-- name: GetAlertRuleByID :one
+select id, tenant_slug, name, meter_slug, plan_feature_id, subject_type, subject_ids, threshold_type, threshold, window_minutes, enabled, created_at, updated_at, created_by, updated_by from alert_rule
+where id = $1
+and tenant_slug = $2
+`
+
+type GetAlertRuleByIDParams struct {
+	ID         pgtype.UUID
+	TenantSlug string
+}
+
+func (q *Queries) GetAlertRuleByID(ctx context.Context, arg GetAlertRuleByIDParams) (AlertRule, error) {
+	row := q.db.QueryRow(ctx, getAlertRuleByID, arg.ID, arg.TenantSlug)
+	var i AlertRule
+	err := row.Scan(
+		&i.ID,
+		&i.TenantSlug,
+		&i.Name,
+		&i.MeterSlug,
+		&i.PlanFeatureID,
+		&i.SubjectType,
+		&i.SubjectIds,
+		&i.ThresholdType,
+		&i.Threshold,
+		&i.WindowMinutes,
+		&i.Enabled,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.CreatedBy,
+		&i.UpdatedBy,
+	)
+	return i, err
+}
+
+const listAlertRulesPaginated = `-- name: ListAlertRulesPaginated :many
+select id, tenant_slug, name, meter_slug, plan_feature_id, subject_type, subject_ids, threshold_type, threshold, window_minutes, enabled, created_at, updated_at, created_by, updated_by from alert_rule
+where tenant_slug = $1
+order by created_at desc
+limit $2
+offset $3
+`
+
+type ListAlertRulesPaginatedParams struct {
+	TenantSlug string
+	Limit      int32
+	Offset     int32
+}
+
+func (q *Queries) ListAlertRulesPaginated(ctx context.Context, arg ListAlertRulesPaginatedParams) ([]AlertRule, error) {
+	rows, err := q.db.Query(ctx, listAlertRulesPaginated, arg.TenantSlug, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []AlertRule
+	for rows.Next() {
+		var i AlertRule
+		if err := rows.Scan(
+			&i.ID,
+			&i.TenantSlug,
+			&i.Name,
+			&i.MeterSlug,
+			&i.PlanFeatureID,
+			&i.SubjectType,
+			&i.SubjectIds,
+			&i.ThresholdType,
+			&i.Threshold,
+			&i.WindowMinutes,
+			&i.Enabled,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.CreatedBy,
+			&i.UpdatedBy,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listAlertStates = `-- name: ListAlertStates :many
+select s.id, s.alert_rule_id, s.subject_id, s.status, s.value, s.threshold_value, s.period_start, s.fired_at, s.resolved_at, s.evaluated_at from alert_state s
+join alert_rule r on s.alert_rule_id = r.id
+where s.alert_rule_id = $1
+and r.tenant_slug = $2
+order by s.subject_id
+`
+
+type ListAlertStatesParams struct {
+	AlertRuleID pgtype.UUID
+	TenantSlug  string
+}
+
+func (q *Queries) ListAlertStates(ctx context.Context, arg ListAlertStatesParams) ([]AlertState, error) {
+	rows, err := q.db.Query(ctx, listAlertStates, arg.AlertRuleID, arg.TenantSlug)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []AlertState
+	for rows.Next() {
+		var i AlertState
+		if err := rows.Scan(
+			&i.ID,
+			&i.AlertRuleID,
+			&i.SubjectID,
+			&i.Status,
+			&i.Value,
+			&i.ThresholdValue,
+			&i.PeriodStart,
+			&i.FiredAt,
+			&i.ResolvedAt,
+			&i.EvaluatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listEnabledAlertRules = `-- name: ListEnabledAlertRules :many
+select id, tenant_slug, name, meter_slug, plan_feature_id, subject_type, subject_ids, threshold_type, threshold, window_minutes, enabled, created_at, updated_at, created_by, updated_by from alert_rule
+where enabled
+order by tenant_slug, created_at
+`
+
+func (q *Queries) ListEnabledAlertRules(ctx context.Context) ([]AlertRule, error) {
+	rows, err := q.db.Query(ctx, listEnabledAlertRules)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []AlertRule
+	for rows.Next() {
+		var i AlertRule
+		if err := rows.Scan(
+			&i.ID,
+			&i.TenantSlug,
+			&i.Name,
+			&i.MeterSlug,
+			&i.PlanFeatureID,
+			&i.SubjectType,
+			&i.SubjectIds,
+			&i.ThresholdType,
+			&i.Threshold,
+			&i.WindowMinutes,
+			&i.Enabled,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.CreatedBy,
+			&i.UpdatedBy,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updateAlertRule = `-- name: UpdateAlertRule :one
+update alert_rule
+set name = coalesce($1, name),
+    subject_ids = coalesce($2::text[], subject_ids),
+    threshold = coalesce($3, threshold),
+    window_minutes = coalesce($4, window_minutes),
+    enabled = coalesce($5, enabled),
+    updated_by = $6,
+    updated_at = now()
+where id = $7
+and tenant_slug = $8
+returning id, tenant_slug, name, meter_slug, plan_feature_id, subject_type, subject_ids, threshold_type, threshold, window_minutes, enabled, created_at, updated_at, created_by, updated_by
+`
+
+type UpdateAlertRuleParams struct {
+	Name          pgtype.Text
+	SubjectIds    []string
+	Threshold     pgtype.Float8
+	WindowMinutes pgtype.Int8
+	Enabled       pgtype.Bool
+	UpdatedBy     string
+	ID            pgtype.UUID
+	TenantSlug    string
+}
+
+func (q *Queries) UpdateAlertRule(ctx context.Context, arg UpdateAlertRuleParams) (AlertRule, error) {
+	row := q.db.QueryRow(ctx, updateAlertRule,
+		arg.Name,
+		arg.SubjectIds,
+		arg.Threshold,
+		arg.WindowMinutes,
+		arg.Enabled,
+		arg.UpdatedBy,
+		arg.ID,
+		arg.TenantSlug,
+	)
+	var i AlertRule
+	err := row.Scan(
+		&i.ID,
+		&i.TenantSlug,
+		&i.Name,
+		&i.MeterSlug,
+		&i.PlanFeatureID,
+		&i.SubjectType,
+		&i.SubjectIds,
+		&i.ThresholdType,
+		&i.Threshold,
+		&i.WindowMinutes,
+		&i.Enabled,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.CreatedBy,
+		&i.UpdatedBy,
+	)
+	return i, err
+}
+
+const upsertAlertState = `-- name: UpsertAlertState :one
+insert into alert_state (
+  alert_rule_id,
+  subject_id,
+  status,
+  value,
+  threshold_value,
+  period_start,
+  fired_at,
+  resolved_at,
+  evaluated_at
+) values (
+  $1, $2, $3, $4, $5, $6, $7, $8, $9
+)
+on conflict (alert_rule_id, subject_id) do update
+set status = excluded.status,
+    value = excluded.value,
+    threshold_value = excluded.threshold_value,
+    period_start = excluded.period_start,
+    fired_at = excluded.fired_at,
+    resolved_at = excluded.resolved_at,
+    evaluated_at = excluded.evaluated_at
+returning id, alert_rule_id, subject_id, status, value, threshold_value, period_start, fired_at, resolved_at, evaluated_at
+`
+
+type UpsertAlertStateParams struct {
+	AlertRuleID    pgtype.UUID
+	SubjectID      string
+	Status         AlertStatusEnum
+	Value          float64
+	ThresholdValue float64
+	PeriodStart    pgtype.Timestamptz
+	FiredAt        pgtype.Timestamptz
+	ResolvedAt     pgtype.Timestamptz
+	EvaluatedAt    pgtype.Timestamptz
+}
+
+func (q *Queries) UpsertAlertState(ctx context.Context, arg UpsertAlertStateParams) (AlertState, error) {
+	row := q.db.QueryRow(ctx, upsertAlertState,
+		arg.AlertRuleID,
+		arg.SubjectID,
+		arg.Status,
+		arg.Value,
+		arg.ThresholdValue,
+		arg.PeriodStart,
+		arg.FiredAt,
+		arg.ResolvedAt,
+		arg.EvaluatedAt,
+	)
+	var i AlertState
+	err := row.Scan(
+		&i.ID,
+		&i.AlertRuleID,
+		&i.SubjectID,
+		&i.Status,
+		&i.Value,
+		&i.ThresholdValue,
+		&i.PeriodStart,
+		&i.FiredAt,
+		&i.ResolvedAt,
+		&i.EvaluatedAt,
+	)
+	return i, err
+}
@@ -0,0 +1,411 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: entitlement_override.sql
+
+package gen
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const countEntitlementOverrides = `-- name: CountEntitlementOverrides :one
+select count(*) from entitlement_override eo
+where eo.tenant_slug = $1
+and eo.subject_type = $2
+and eo.subject_id = $3
+and ($4::boolean or eo.expires_at is null or eo.expires_at > $5)
+`
+
+type CountEntitlementOverridesParams struct {
+	TenantSlug     string
+	SubjectType    SubjectTypeEnum
+	SubjectID      string
+	IncludeExpired bool
+	At             pgtype.Timestamptz
+}
+
+func (q *Queries) CountEntitlementOverrides(ctx context.Context, arg CountEntitlementOverridesParams) (int64, error) {
+	row := q.db.QueryRow(ctx, countEntitlementOverrides,
+		arg.TenantSlug,
+		arg.SubjectType,
+		arg.SubjectID,
+		arg.IncludeExpired,
+		arg.At,
+	)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const createEntitlementOverride = `-- name: CreateEntitlementOverride :one
+insert into entitlement_override (
+  tenant_slug,
+  subject_type,
+  subject_id,
+  plan_assignment_id,
+  feature_id,
+  effect,
+  limit_value,
+  reset_period,
+  custom_period_minutes,
+  action_at_limit,
+  reason,
+  expires_at,
+  created_by,
+  updated_by
+) values (
+  $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14
+) returning id, tenant_slug, subject_type, subject_id, plan_assignment_id, feature_id, effect, limit_value, reset_period, custom_period_minutes, action_at_limit, reason, expires_at, created_at, updated_at, created_by, updated_by
+`
+
+type CreateEntitlementOverrideParams struct {
+	TenantSlug          string
+	SubjectType         SubjectTypeEnum
+	SubjectID           string
+	PlanAssignmentID    pgtype.UUID
+	FeatureID           pgtype.UUID
+	Effect              EntitlementOverrideEffectEnum
+	LimitValue          pgtype.Int8
+	ResetPeriod         NullMeteredResetPeriodEnum
+	CustomPeriodMinutes pgtype.Int8
+	ActionAtLimit       NullMeteredActionAtLimitEnum
+	Reason              pgtype.Text
+	ExpiresAt           pgtype.Timestamptz
+	CreatedBy           string
+	UpdatedBy           string
+}
+
+func (q *Queries) CreateEntitlementOverride(ctx context.Context, arg CreateEntitlementOverrideParams) (EntitlementOverride, error) {
+	row := q.db.QueryRow(ctx, createEntitlementOverride,
+		arg.TenantSlug,
+		arg.SubjectType,
+		arg.SubjectID,
+		arg.PlanAssignmentID,
+		arg.FeatureID,
+		arg.Effect,
+		arg.LimitValue,
+		arg.ResetPeriod,
+		arg.CustomPeriodMinutes,
+		arg.ActionAtLimit,
+		arg.Reason,
+		arg.ExpiresAt,
+		arg.CreatedBy,
+		arg.UpdatedBy,
+	)
+	var i EntitlementOverride
+	err := row.Scan(
+		&i.ID,
+		&i.TenantSlug,
+		&i.SubjectType,
+		&i.SubjectID,
+		&i.PlanAssignmentID,
+		&i.FeatureID,
+		&i.Effect,
+		&i.LimitValue,
+		&i.ResetPeriod,
+		&i.CustomPeriodMinutes,
+		&i.ActionAtLimit,
+		&i.Reason,
+		&i.ExpiresAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.CreatedBy,
+		&i.UpdatedBy,
+	)
+	return i, err
+}
+
+const deleteEntitlementOverride = `-- name: DeleteEntitlementOverride :exec
+delete from entitlement_override
+where id = $1
+and tenant_slug = $2
+`
+
+type DeleteEntitlementOverrideParams struct {
+	ID         pgtype.UUID
+	TenantSlug string
+}
+
+func (q *Queries) DeleteEntitlementOverride(ctx context.Context, arg DeleteEntitlementOverrideParams) error {
+	_, err := q.db.Exec(ctx, deleteEntitlementOverride, arg.ID, arg.TenantSlug)
+	return err
+}
+
+const getEntitlementOverride = `-- name: GetEntitlementOverride :one
+select eo.id, eo.tenant_slug, eo.subject_type, eo.subject_id, eo.plan_assignment_id, eo.feature_id, eo.effect, eo.limit_value, eo.reset_period, eo.custom_period_minutes, eo.action_at_limit, eo.reason, eo.expires_at, eo.created_at, eo.updated_at, eo.created_by, eo.updated_by, f.slug as feature_slug, f.name as feature_name, f.type as feature_type, f.meter_slugs as feature_meter_slugs, f.merge_rule as feature_merge_rule
+from entitlement_override eo
+join feature f on eo.feature_id = f.id
+where eo.id = $1
+and eo.tenant_slug = $2
+`
+
+type GetEntitlementOverrideParams struct {
+	ID         pgtype.UUID
+	TenantSlug string
+}
+
+type GetEntitlementOverrideRow struct {
+	EntitlementOverride EntitlementOverride
+	FeatureSlug         string
+	FeatureName         string
+	FeatureType         FeatureEnum
+	FeatureMeterSlugs   []string
+	FeatureMergeRule    QuotaMergeRuleEnum
+}
+
+func (q *Queries) GetEntitlementOverride(ctx context.Context, arg GetEntitlementOverrideParams) (GetEntitlementOverrideRow, error) {
+	row := q.db.QueryRow(ctx, getEntitlementOverride, arg.ID, arg.TenantSlug)
+	var i GetEntitlementOverrideRow
+	err := row.Scan(
+		&i.EntitlementOverride.ID,
+		&i.EntitlementOverride.TenantSlug,
+		&i.EntitlementOverride.SubjectType,
+		&i.EntitlementOverride.SubjectID,
+		&i.EntitlementOverride.PlanAssignmentID,
+		&i.EntitlementOverride.FeatureID,
+		&i.EntitlementOverride.Effect,
+		&i.EntitlementOverride.LimitValue,
+		&i.EntitlementOverride.ResetPeriod,
+		&i.EntitlementOverride.CustomPeriodMinutes,
+		&i.EntitlementOverride.ActionAtLimit,
+		&i.EntitlementOverride.Reason,
+		&i.EntitlementOverride.ExpiresAt,
+		&i.EntitlementOverride.CreatedAt,
+		&i.EntitlementOverride.UpdatedAt,
+		&i.EntitlementOverride.CreatedBy,
+		&i.EntitlementOverride.UpdatedBy,
+		&i.FeatureSlug,
+		&i.FeatureName,
+		&i.FeatureType,
+		&i.FeatureMeterSlugs,
+		&i.FeatureMergeRule,
+	)
+	return i, err
+}
+
+const listActiveEntitlementOverrides = `-- name: ListActiveEntitlementOverrides :many
+select eo.id, eo.tenant_slug, eo.subject_type, eo.subject_id, eo.plan_assignment_id, eo.feature_id, eo.effect, eo.limit_value, eo.reset_period, eo.custom_period_minutes, eo.action_at_limit, eo.reason, eo.expires_at, eo.created_at, eo.updated_at, eo.created_by, eo.updated_by, f.slug as feature_slug, f.name as feature_name, f.type as feature_type, f.meter_slugs as feature_meter_slugs, f.merge_rule as feature_merge_rule
+from entitlement_override eo
+join feature f on eo.feature_id = f.id
+where eo.tenant_slug = $1
+and eo.subject_type = $2
+and eo.subject_id = $3
+and (eo.expires_at is null or eo.expires_at > $4)
+order by eo.created_at, eo.id
+`
+
+type ListActiveEntitlementOverridesParams struct {
+	TenantSlug  string
+	SubjectType SubjectTypeEnum
+	SubjectID   string
+	At          pgtype.Timestamptz
+}
+
+type ListActiveEntitlementOverridesRow struct {
+	EntitlementOverride EntitlementOverride
+	FeatureSlug         string
+	FeatureName         string
+	FeatureType         FeatureEnum
+	FeatureMeterSlugs   []string
+	FeatureMergeRule    QuotaMergeRuleEnum
+}
+
+// returns the overrides of a subject in force at the given time in the order they were made
+func (q *Queries) ListActiveEntitlementOverrides(ctx context.Context, arg ListActiveEntitlementOverridesParams) ([]ListActiveEntitlementOverridesRow, error) {
+	rows, err := q.db.Query(ctx, listActiveEntitlementOverrides,
+		arg.TenantSlug,
+		arg.SubjectType,
+		arg.SubjectID,
+		arg.At,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListActiveEntitlementOverridesRow
+	for rows.Next() {
+		var i ListActiveEntitlementOverridesRow
+		if err := rows.Scan(
+			&i.EntitlementOverride.ID,
+			&i.EntitlementOverride.TenantSlug,
+			&i.EntitlementOverride.SubjectType,
+			&i.EntitlementOverride.SubjectID,
+			&i.EntitlementOverride.PlanAssignmentID,
+			&i.EntitlementOverride.FeatureID,
+			&i.EntitlementOverride.Effect,
+			&i.EntitlementOverride.LimitValue,
+			&i.EntitlementOverride.ResetPeriod,
+			&i.EntitlementOverride.CustomPeriodMinutes,
+			&i.EntitlementOverride.ActionAtLimit,
+			&i.EntitlementOverride.Reason,
+			&i.EntitlementOverride.ExpiresAt,
+			&i.EntitlementOverride.CreatedAt,
+			&i.EntitlementOverride.UpdatedAt,
+			&i.EntitlementOverride.CreatedBy,
+			&i.EntitlementOverride.UpdatedBy,
+			&i.FeatureSlug,
+			&i.FeatureName,
+			&i.FeatureType,
+			&i.FeatureMeterSlugs,
+			&i.FeatureMergeRule,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listEntitlementOverridesPaginated = `-- name: ListEntitlementOverridesPaginated :many
+select eo.id, eo.tenant_slug, eo.subject_type, eo.subject_id, eo.plan_assignment_id, eo.feature_id, eo.effect, eo.limit_value, eo.reset_period, eo.custom_period_minutes, eo.action_at_limit, eo.reason, eo.expires_at, eo.created_at, eo.updated_at, eo.created_by, eo.updated_by, f.slug as feature_slug, f.name as feature_name, f.type as feature_type, f.meter_slugs as feature_meter_slugs, f.merge_rule as feature_merge_rule
+from entitlement_override eo
+join feature f on eo.feature_id = f.id
+where eo.tenant_slug = $1
+and eo.subject_type = $2
+and eo.subject_id = $3
+and ($4::boolean or eo.expires_at is null or eo.expires_at > $5)
+order by eo.created_at desc
+limit $7
+offset $6
+`
+
+type ListEntitlementOverridesPaginatedParams struct {
+	TenantSlug     string
+	SubjectType    SubjectTypeEnum
+	SubjectID      string
+	IncludeExpired bool
+	At             pgtype.Timestamptz
+	Offset         int32
+	Limit          int32
+}
+
+type ListEntitlementOverridesPaginatedRow struct {
+	EntitlementOverride EntitlementOverride
+	FeatureSlug         string
+	FeatureName         string
+	FeatureType         FeatureEnum
+	FeatureMeterSlugs   []string
+	FeatureMergeRule    QuotaMergeRuleEnum
+}
+
+// lists the overrides of a subject, without expired ones unless include_expired is set
+func (q *Queries) ListEntitlementOverridesPaginated(ctx context.Context, arg ListEntitlementOverridesPaginatedParams) ([]ListEntitlementOverridesPaginatedRow, error) {
+	rows, err := q.db.Query(ctx, listEntitlementOverridesPaginated,
+		arg.TenantSlug,
+		arg.SubjectType,
+		arg.SubjectID,
+		arg.IncludeExpired,
+		arg.At,
+		arg.Offset,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListEntitlementOverridesPaginatedRow
+	for rows.Next() {
+		var i ListEntitlementOverridesPaginatedRow
+		if err := rows.Scan(
+			&i.EntitlementOverride.ID,
+			&i.EntitlementOverride.TenantSlug,
+			&i.EntitlementOverride.SubjectType,
+			&i.EntitlementOverride.SubjectID,
+			&i.EntitlementOverride.PlanAssignmentID,
+			&i.EntitlementOverride.FeatureID,
+			&i.EntitlementOverride.Effect,
+			&i.EntitlementOverride.LimitValue,
+			&i.EntitlementOverride.ResetPeriod,
+			&i.EntitlementOverride.CustomPeriodMinutes,
+			&i.EntitlementOverride.ActionAtLimit,
+			&i.EntitlementOverride.Reason,
+			&i.EntitlementOverride.ExpiresAt,
+			&i.EntitlementOverride.CreatedAt,
+			&i.EntitlementOverride.UpdatedAt,
+			&i.EntitlementOverride.CreatedBy,
+			&i.EntitlementOverride.UpdatedBy,
+			&i.FeatureSlug,
+			&i.FeatureName,
+			&i.FeatureType,
+			&i.FeatureMeterSlugs,
+			&i.FeatureMergeRule,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updateEntitlementOverride = `-- name: UpdateEntitlementOverride :one
+update entitlement_override
+set limit_value = coalesce($1, limit_value),
+    reset_period = coalesce($2::metered_reset_period_enum, reset_period),
+    custom_period_minutes = coalesce($3, custom_period_minutes),
+    action_at_limit = coalesce($4::metered_action_at_limit_enum, action_at_limit),
+    reason = coalesce($5, reason),
+    expires_at = case when $6::boolean then null else coalesce($7, expires_at) end,
+    updated_by = $8,
+    updated_at = now()
+where id = $9
+and tenant_slug = $10
+returning id, tenant_slug, subject_type, subject_id, plan_assignment_id, feature_id, effect, limit_value, reset_period, custom_period_minutes, action_at_limit, reason, expires_at, created_at, updated_at, created_by, updated_by
+`
+
+type UpdateEntitlementOverrideParams struct {
+	LimitValue          pgtype.Int8
+	ResetPeriod         NullMeteredResetPeriodEnum
+	CustomPeriodMinutes pgtype.Int8
+	ActionAtLimit       NullMeteredActionAtLimitEnum
+	Reason              pgtype.Text
+	ClearExpiresAt      bool
+	ExpiresAt           pgtype.Timestamptz
+	UpdatedBy           string
+	ID                  pgtype.UUID
+	TenantSlug          string
+}
+
+func (q *Queries) UpdateEntitlementOverride(ctx context.Context, arg UpdateEntitlementOverrideParams) (EntitlementOverride, error) {
+	row := q.db.QueryRow(ctx, updateEntitlementOverride,
+		arg.LimitValue,
+		arg.ResetPeriod,
+		arg.CustomPeriodMinutes,
+		arg.ActionAtLimit,
+		arg.Reason,
+		arg.ClearExpiresAt,
+		arg.ExpiresAt,
+		arg.UpdatedBy,
+		arg.ID,
+		arg.TenantSlug,
+	)
+	var i EntitlementOverride
+	err := row.Scan(
+		&i.ID,
+		&i.TenantSlug,
+		&i.SubjectType,
+		&i.SubjectID,
+		&i.PlanAssignmentID,
+		&i.FeatureID,
+		&i.Effect,
+		&i.LimitValue,
+		&i.ResetPeriod,
+		&i.CustomPeriodMinutes,
+		&i.ActionAtLimit,
+		&i.Reason,
+		&i.ExpiresAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.CreatedBy,
+		&i.UpdatedBy,
+	)
+	return i, err
+}
@@ -38,10 +38,12 @@ insert into feature (
   type,
   config,
   created_by,
-  updated_by
+  updated_by,
+  meter_slugs,
+  merge_rule
 ) values (
-  $1, $2, $3, $4, $5, $6, $7, $8
-) returning id, name, slug, description, tenant_slug, type, config, created_at, updated_at, created_by, updated_by
+  $1, $2, $3, $4, $5, $6, $7, $8, $9, $10
+) returning id, name, slug, description, tenant_slug, type, config, created_at, updated_at, created_by, updated_by, meter_slugs, merge_rule
 `
 
 type CreateFeatureParams struct {
@@ -53,6 +55,8 @@ type CreateFeatureParams struct {
 	Config      []byte
 	CreatedBy   string
 	UpdatedBy   string
+	MeterSlugs  []string
+	MergeRule   QuotaMergeRuleEnum
 }
 
 func (q *Queries) CreateFeature(ctx context.Context, arg CreateFeatureParams) (Feature, error) {
@@ -65,6 +69,8 @@ func (q *Queries) CreateFeature(ctx context.Context, arg CreateFeatureParams) (F
 		arg.Config,
 		arg.CreatedBy,
 		arg.UpdatedBy,
+		arg.MeterSlugs,
+		arg.MergeRule,
 	)
 	var i Feature
 	err := row.Scan(
@@ -79,6 +85,8 @@ func (q *Queries) CreateFeature(ctx context.Context, arg CreateFeatureParams) (F
 		&i.UpdatedAt,
 		&i.CreatedBy,
 		&i.UpdatedBy,
+		&i.MeterSlugs,
+		&i.MergeRule,
 	)
 	return i, err
 }
@@ -116,7 +124,7 @@ func (q *Queries) DeleteFeatureBySlug(ctx context.Context, arg DeleteFeatureBySl
 }
 
 const getFeatureByID = `-- name: GetFeatureByID :one
-select id, name, slug, description, tenant_slug, type, config, created_at, updated_at, created_by, updated_by from feature
+select id, name, slug, description, tenant_slug, type, config, created_at, updated_at, created_by, updated_by, meter_slugs, merge_rule from feature
 where id = $1
 and tenant_slug = $2
 `
@@ -141,12 +149,14 @@ func (q *Queries) GetFeatureByID(ctx context.Context, arg GetFeatureByIDParams)
 		&i.UpdatedAt,
 		&i.CreatedBy,
 		&i.UpdatedBy,
+		&i.MeterSlugs,
+		&i.MergeRule,
 	)
 	return i, err
 }
 
 const getFeatureBySlug = `-- name: GetFeatureBySlug :one
-select id, name, slug, description, tenant_slug, type, config, created_at, updated_at, created_by, updated_by from feature
+select id, name, slug, description, tenant_slug, type, config, created_at, updated_at, created_by, updated_by, meter_slugs, merge_rule from feature
 where slug = $1
 and tenant_slug = $2
 `
@@ -171,12 +181,14 @@ func (q *Queries) GetFeatureBySlug(ctx context.Context, arg GetFeatureBySlugPara
 		&i.UpdatedAt,
 		&i.CreatedBy,
 		&i.UpdatedBy,
+		&i.MeterSlugs,
+		&i.MergeRule,
 	)
 	return i, err
 }
 
 const listFeaturesPaginated = `-- name: ListFeaturesPaginated :many
-select id, name, slug, description, tenant_slug, type, config, created_at, updated_at, created_by, updated_by from feature
+select id, name, slug, description, tenant_slug, type, config, created_at, updated_at, created_by, updated_by, meter_slugs, merge_rule from feature
 where tenant_slug = $1
 and ($4::feature_enum is null or type = $4::feature_enum)
 order by created_at desc
@@ -217,6 +229,8 @@ func (q *Queries) ListFeaturesPaginated(ctx context.Context, arg ListFeaturesPag
 			&i.UpdatedAt,
 			&i.CreatedBy,
 			&i.UpdatedBy,
+			&i.MeterSlugs,
+			&i.MergeRule,
 		); err != nil {
 			return nil, err
 		}
@@ -233,10 +247,12 @@ update feature
 set name = coalesce($6, name),
     description = coalesce($1, description),
     config = coalesce($2, config),
+    meter_slugs = coalesce($7::text[], meter_slugs),
+    merge_rule = coalesce($8::quota_merge_rule_enum, merge_rule),
     updated_by = $3
 where id = $4
 and tenant_slug = $5
-returning id, name, slug, description, tenant_slug, type, config, created_at, updated_at, created_by, updated_by
+returning id, name, slug, description, tenant_slug, type, config, created_at, updated_at, created_by, updated_by, meter_slugs, merge_rule
 `
 
 type UpdateFeatureByIDParams struct {
@@ -246,6 +262,8 @@ type UpdateFeatureByIDParams struct {
 	ID          pgtype.UUID
 	TenantSlug  string
 	Name        pgtype.Text
+	MeterSlugs  []string
+	MergeRule   NullQuotaMergeRuleEnum
 }
 
 func (q *Queries) UpdateFeatureByID(ctx context.Context, arg UpdateFeatureByIDParams) (Feature, error) {
@@ -256,6 +274,8 @@ func (q *Queries) UpdateFeatureByID(ctx context.Context, arg UpdateFeatureByIDPa
 		arg.ID,
 		arg.TenantSlug,
 		arg.Name,
+		arg.MeterSlugs,
+		arg.MergeRule,
 	)
 	var i Feature
 	err := row.Scan(
@@ -270,6 +290,8 @@ func (q *Queries) UpdateFeatureByID(ctx context.Context, arg UpdateFeatureByIDPa
 		&i.UpdatedAt,
 		&i.CreatedBy,
 		&i.UpdatedBy,
+		&i.MeterSlugs,
+		&i.MergeRule,
 	)
 	return i, err
 }
@@ -279,10 +301,12 @@ update feature
 set name = coalesce($6, name),
     description = coalesce($1, description),
     config = coalesce($2, config),
+    meter_slugs = coalesce($7::text[], meter_slugs),
+    merge_rule = coalesce($8::quota_merge_rule_enum, merge_rule),
     updated_by = $3
 where slug = $4
 and tenant_slug = $5
-returning id, name, slug, description, tenant_slug, type, config, created_at, updated_at, created_by, updated_by
+returning id, name, slug, description, tenant_slug, type, config, created_at, updated_at, created_by, updated_by, meter_slugs, merge_rule
 `
 
 type UpdateFeatureBySlugParams struct {
@@ -292,6 +316,8 @@ type UpdateFeatureBySlugParams struct {
 	Slug        string
 	TenantSlug  string
 	Name        pgtype.Text
+	MeterSlugs  []string
+	MergeRule   NullQuotaMergeRuleEnum
 }
 
 func (q *Queries) UpdateFeatureBySlug(ctx context.Context, arg UpdateFeatureBySlugParams) (Feature, error) {
@@ -302,6 +328,8 @@ func (q *Queries) UpdateFeatureBySlug(ctx context.Context, arg UpdateFeatureBySl
 		arg.Slug,
 		arg.TenantSlug,
 		arg.Name,
+		arg.MeterSlugs,
+		arg.MergeRule,
 	)
 	var i Feature
 	err := row.Scan(
@@ -316,6 +344,8 @@ func (q *Queries) UpdateFeatureBySlug(ctx context.Context, arg UpdateFeatureBySl
 		&i.UpdatedAt,
 		&i.CreatedBy,
 		&i.UpdatedBy,
+		&i.MeterSlugs,
+		&i.MergeRule,
 	)
 	return i, err
 }
@@ -30,7 +30,7 @@ AND tenant_slug = $2
 `
 
 type CountMetersByEventTypeParams struct {
-	EventType  string
+	EventType  pgtype.Text
 	TenantSlug string
 }
 
@@ -52,23 +52,41 @@ INSERT INTO meter (
     aggregation,
     tenant_slug,
     created_by,
-    updated_by
+    updated_by,
+    type,
+    formula,
+    source_meters,
+    granularity,
+    rollups,
+    status,
+    filter,
+    event_types,
+    value_properties
 ) VALUES (
-    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10
-) RETURNING id, name, slug, event_type, description, value_property, properties, aggregation, tenant_slug, created_at, updated_at, created_by, updated_by
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19
+) RETURNING id, name, slug, event_type, description, value_property, properties, aggregation, tenant_slug, created_at, updated_at, created_by, updated_by, type, formula, source_meters, granularity, rollups, status, status_error, reconcile_attempts, filter, event_types, value_properties
 `
 
 type CreateMeterParams struct {
-	Name          string
-	Slug          string
-	EventType     string
-	Description   pgtype.Text
-	ValueProperty pgtype.Text
-	Properties    []string
-	Aggregation   AggregationEnum
-	TenantSlug    string
-	CreatedBy     string
-	UpdatedBy     string
+	Name            string
+	Slug            string
+	EventType       pgtype.Text
+	Description     pgtype.Text
+	ValueProperty   pgtype.Text
+	Properties      []string
+	Aggregation     NullAggregationEnum
+	TenantSlug      string
+	CreatedBy       string
+	UpdatedBy       string
+	Type            MeterTypeEnum
+	Formula         pgtype.Text
+	SourceMeters    []string
+	Granularity     MeterGranularityEnum
+	Rollups         []string
+	Status          MeterStatusEnum
+	Filter          []byte
+	EventTypes      []string
+	ValueProperties []byte
 }
 
 func (q *Queries) CreateMeter(ctx context.Context, arg CreateMeterParams) (Meter, error) {
@@ -83,6 +101,15 @@ func (q *Queries) CreateMeter(ctx context.Context, arg CreateMeterParams) (Meter
 		arg.TenantSlug,
 		arg.CreatedBy,
 		arg.UpdatedBy,
+		arg.Type,
+		arg.Formula,
+		arg.SourceMeters,
+		arg.Granularity,
+		arg.Rollups,
+		arg.Status,
+		arg.Filter,
+		arg.EventTypes,
+		arg.ValueProperties,
 	)
 	var i Meter
 	err := row.Scan(
@@ -99,6 +126,17 @@ func (q *Queries) CreateMeter(ctx context.Context, arg CreateMeterParams) (Meter
 		&i.UpdatedAt,
 		&i.CreatedBy,
 		&i.UpdatedBy,
+		&i.Type,
+		&i.Formula,
+		&i.SourceMeters,
+		&i.Granularity,
+		&i.Rollups,
+		&i.Status,
+		&i.StatusError,
+		&i.ReconcileAttempts,
+		&i.Filter,
+		&i.EventTypes,
+		&i.ValueProperties,
 	)
 	return i, err
 }
@@ -136,7 +174,7 @@ func (q *Queries) DeleteMeterBySlug(ctx context.Context, arg DeleteMeterBySlugPa
 }
 
 const getMeterByID = `-- name: GetMeterByID :one
-SELECT id, name, slug, event_type, description, value_property, properties, aggregation, tenant_slug, created_at, updated_at, created_by, updated_by FROM meter
+SELECT id, name, slug, event_type, description, value_property, properties, aggregation, tenant_slug, created_at, updated_at, created_by, updated_by, type, formula, source_meters, granularity, rollups, status, status_error, reconcile_attempts, filter, event_types, value_properties FROM meter
 WHERE id = $1
 AND tenant_slug = $2
 `
@@ -163,12 +201,23 @@ func (q *Queries) GetMeterByID(ctx context.Context, arg GetMeterByIDParams) (Met
 		&i.UpdatedAt,
 		&i.CreatedBy,
 		&i.UpdatedBy,
+		&i.Type,
+		&i.Formula,
+		&i.SourceMeters,
+		&i.Granularity,
+		&i.Rollups,
+		&i.Status,
+		&i.StatusError,
+		&i.ReconcileAttempts,
+		&i.Filter,
+		&i.EventTypes,
+		&i.ValueProperties,
 	)
 	return i, err
 }
 
 const getMeterBySlug = `-- name: GetMeterBySlug :one
-SELECT id, name, slug, event_type, description, value_property, properties, aggregation, tenant_slug, created_at, updated_at, created_by, updated_by FROM meter
+SELECT id, name, slug, event_type, description, value_property, properties, aggregation, tenant_slug, created_at, updated_at, created_by, updated_by, type, formula, source_meters, granularity, rollups, status, status_error, reconcile_attempts, filter, event_types, value_properties FROM meter
 WHERE slug = $1
 AND tenant_slug = $2
 `
@@ -195,6 +244,17 @@ func (q *Queries) GetMeterBySlug(ctx context.Context, arg GetMeterBySlugParams)
 		&i.UpdatedAt,
 		&i.CreatedBy,
 		&i.UpdatedBy,
+		&i.Type,
+		&i.Formula,
+		&i.SourceMeters,
+		&i.Granularity,
+		&i.Rollups,
+		&i.Status,
+		&i.StatusError,
+		&i.ReconcileAttempts,
+		&i.Filter,
+		&i.EventTypes,
+		&i.ValueProperties,
 	)
 	return i, err
 }
@@ -208,7 +268,7 @@ ORDER BY property
 `
 
 type GetPropertiesByEventTypeParams struct {
-	EventType  string
+	EventType  pgtype.Text
 	TenantSlug string
 }
 
@@ -240,7 +300,7 @@ ORDER BY value_property
 `
 
 type GetValuePropertiesByEventTypeParams struct {
-	EventType  string
+	EventType  pgtype.Text
 	TenantSlug string
 }
 
@@ -264,9 +324,90 @@ func (q *Queries) GetValuePropertiesByEventType(ctx context.Context, arg GetValu
 	return items, nil
 }
 
+const listAllMeters = `-- name: ListAllMeters :many
+SELECT id, name, slug, event_type, description, value_property, properties, aggregation, tenant_slug, created_at, updated_at, created_by, updated_by, type, formula, source_meters, granularity, rollups, status, status_error, reconcile_attempts, filter, event_types, value_properties FROM meter
+ORDER BY tenant_slug, slug
+`
+
+func (q *Queries) ListAllMeters(ctx context.Context) ([]Meter, error) {
+	rows, err := q.db.Query(ctx, listAllMeters)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Meter
+	for rows.Next() {
+		var i Meter
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Slug,
+			&i.EventType,
+			&i.Description,
+			&i.ValueProperty,
+			&i.Properties,
+			&i.Aggregation,
+			&i.TenantSlug,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.CreatedBy,
+			&i.UpdatedBy,
+			&i.Type,
+			&i.Formula,
+			&i.SourceMeters,
+			&i.Granularity,
+			&i.Rollups,
+			&i.Status,
+			&i.StatusError,
+			&i.ReconcileAttempts,
+			&i.Filter,
+			&i.EventTypes,
+			&i.ValueProperties,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listMeterSlugsReferencing = `-- name: ListMeterSlugsReferencing :many
+SELECT slug FROM meter
+WHERE $1::text = ANY(source_meters)
+AND tenant_slug = $2
+`
+
+type ListMeterSlugsReferencingParams struct {
+	Column1    string
+	TenantSlug string
+}
+
+func (q *Queries) ListMeterSlugsReferencing(ctx context.Context, arg ListMeterSlugsReferencingParams) ([]string, error) {
+	rows, err := q.db.Query(ctx, listMeterSlugsReferencing, arg.Column1, arg.TenantSlug)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var slug string
+		if err := rows.Scan(&slug); err != nil {
+			return nil, err
+		}
+		items = append(items, slug)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listMetersByEventTypes = `-- name: ListMetersByEventTypes :many
-SELECT id, name, slug, event_type, description, value_property, properties, aggregation, tenant_slug, created_at, updated_at, created_by, updated_by FROM meter
-WHERE event_type = ANY($1::text[])
+SELECT id, name, slug, event_type, description, value_property, properties, aggregation, tenant_slug, created_at, updated_at, created_by, updated_by, type, formula, source_meters, granularity, rollups, status, status_error, reconcile_attempts, filter, event_types, value_properties FROM meter
+WHERE event_types && $1::text[]
 AND tenant_slug = $2
 `
 
@@ -298,6 +439,76 @@ func (q *Queries) ListMetersByEventTypes(ctx context.Context, arg ListMetersByEv
 			&i.UpdatedAt,
 			&i.CreatedBy,
 			&i.UpdatedBy,
+			&i.Type,
+			&i.Formula,
+			&i.SourceMeters,
+			&i.Granularity,
+			&i.Rollups,
+			&i.Status,
+			&i.StatusError,
+			&i.ReconcileAttempts,
+			&i.Filter,
+			&i.EventTypes,
+			&i.ValueProperties,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listMetersByStatus = `-- name: ListMetersByStatus :many
+SELECT id, name, slug, event_type, description, value_property, properties, aggregation, tenant_slug, created_at, updated_at, created_by, updated_by, type, formula, source_meters, granularity, rollups, status, status_error, reconcile_attempts, filter, event_types, value_properties FROM meter
+WHERE status = ANY($1::meter_status_enum[])
+AND updated_at < $2
+ORDER BY updated_at
+LIMIT $3
+`
+
+type ListMetersByStatusParams struct {
+	Column1   []MeterStatusEnum
+	UpdatedAt pgtype.Timestamptz
+	Limit     int32
+}
+
+func (q *Queries) ListMetersByStatus(ctx context.Context, arg ListMetersByStatusParams) ([]Meter, error) {
+	rows, err := q.db.Query(ctx, listMetersByStatus, arg.Column1, arg.UpdatedAt, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Meter
+	for rows.Next() {
+		var i Meter
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Slug,
+			&i.EventType,
+			&i.Description,
+			&i.ValueProperty,
+			&i.Properties,
+			&i.Aggregation,
+			&i.TenantSlug,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.CreatedBy,
+			&i.UpdatedBy,
+			&i.Type,
+			&i.Formula,
+			&i.SourceMeters,
+			&i.Granularity,
+			&i.Rollups,
+			&i.Status,
+			&i.StatusError,
+			&i.ReconcileAttempts,
+			&i.Filter,
+			&i.EventTypes,
+			&i.ValueProperties,
 		); err != nil {
 			return nil, err
 		}
@@ -310,7 +521,7 @@ func (q *Queries) ListMetersByEventTypes(ctx context.Context, arg ListMetersByEv
 }
 
 const listMetersPaginated = `-- name: ListMetersPaginated :many
-SELECT id, name, slug, event_type, description, value_property, properties, aggregation, tenant_slug, created_at, updated_at, created_by, updated_by FROM meter
+SELECT id, name, slug, event_type, description, value_property, properties, aggregation, tenant_slug, created_at, updated_at, created_by, updated_by, type, formula, source_meters, granularity, rollups, status, status_error, reconcile_attempts, filter, event_types, value_properties FROM meter
 WHERE tenant_slug = $1
 ORDER BY created_at DESC
 LIMIT $2
@@ -346,6 +557,17 @@ func (q *Queries) ListMetersPaginated(ctx context.Context, arg ListMetersPaginat
 			&i.UpdatedAt,
 			&i.CreatedBy,
 			&i.UpdatedBy,
+			&i.Type,
+			&i.Formula,
+			&i.SourceMeters,
+			&i.Granularity,
+			&i.Rollups,
+			&i.Status,
+			&i.StatusError,
+			&i.ReconcileAttempts,
+			&i.Filter,
+			&i.EventTypes,
+			&i.ValueProperties,
 		); err != nil {
 			return nil, err
 		}
@@ -364,7 +586,7 @@ SET name = coalesce($5, name),
     updated_by = $4
 WHERE id = $2
 AND tenant_slug = $3
-RETURNING id, name, slug, event_type, description, value_property, properties, aggregation, tenant_slug, created_at, updated_at, created_by, updated_by
+RETURNING id, name, slug, event_type, description, value_property, properties, aggregation, tenant_slug, created_at, updated_at, created_by, updated_by, type, formula, source_meters, granularity, rollups, status, status_error, reconcile_attempts, filter, event_types, value_properties
 `
 
 type UpdateMeterByIDParams struct {
@@ -398,6 +620,17 @@ func (q *Queries) UpdateMeterByID(ctx context.Context, arg UpdateMeterByIDParams
 		&i.UpdatedAt,
 		&i.CreatedBy,
 		&i.UpdatedBy,
+		&i.Type,
+		&i.Formula,
+		&i.SourceMeters,
+		&i.Granularity,
+		&i.Rollups,
+		&i.Status,
+		&i.StatusError,
+		&i.ReconcileAttempts,
+		&i.Filter,
+		&i.EventTypes,
+		&i.ValueProperties,
 	)
 	return i, err
 }
@@ -409,7 +642,7 @@ SET name = coalesce($5, name),
     updated_by = $3
 WHERE slug = $2
 AND tenant_slug = $4
-RETURNING id, name, slug, event_type, description, value_property, properties, aggregation, tenant_slug, created_at, updated_at, created_by, updated_by
+RETURNING id, name, slug, event_type, description, value_property, properties, aggregation, tenant_slug, created_at, updated_at, created_by, updated_by, type, formula, source_meters, granularity, rollups, status, status_error, reconcile_attempts, filter, event_types, value_properties
 `
 
 type UpdateMeterBySlugParams struct {
@@ -443,6 +676,73 @@ func (q *Queries) UpdateMeterBySlug(ctx context.Context, arg UpdateMeterBySlugPa
 		&i.UpdatedAt,
 		&i.CreatedBy,
 		&i.UpdatedBy,
+		&i.Type,
+		&i.Formula,
+		&i.SourceMeters,
+		&i.Granularity,
+		&i.Rollups,
+		&i.Status,
+		&i.StatusError,
+		&i.ReconcileAttempts,
+		&i.Filter,
+		&i.EventTypes,
+		&i.ValueProperties,
+	)
+	return i, err
+}
+
+const updateMeterStatus = `-- name: UpdateMeterStatus :one
+UPDATE meter
+SET status = $1,
+    status_error = $2,
+    reconcile_attempts = $3
+WHERE id = $4
+AND tenant_slug = $5
+RETURNING id, name, slug, event_type, description, value_property, properties, aggregation, tenant_slug, created_at, updated_at, created_by, updated_by, type, formula, source_meters, granularity, rollups, status, status_error, reconcile_attempts, filter, event_types, value_properties
+`
+
+type UpdateMeterStatusParams struct {
+	Status            MeterStatusEnum
+	StatusError       pgtype.Text
+	ReconcileAttempts int32
+	ID                pgtype.UUID
+	TenantSlug        string
+}
+
+func (q *Queries) UpdateMeterStatus(ctx context.Context, arg UpdateMeterStatusParams) (Meter, error) {
+	row := q.db.QueryRow(ctx, updateMeterStatus,
+		arg.Status,
+		arg.StatusError,
+		arg.ReconcileAttempts,
+		arg.ID,
+		arg.TenantSlug,
+	)
+	var i Meter
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Slug,
+		&i.EventType,
+		&i.Description,
+		&i.ValueProperty,
+		&i.Properties,
+		&i.Aggregation,
+		&i.TenantSlug,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.CreatedBy,
+		&i.UpdatedBy,
+		&i.Type,
+		&i.Formula,
+		&i.SourceMeters,
+		&i.Granularity,
+		&i.Rollups,
+		&i.Status,
+		&i.StatusError,
+		&i.ReconcileAttempts,
+		&i.Filter,
+		&i.EventTypes,
+		&i.ValueProperties,
 	)
 	return i, err
 }
@@ -57,6 +57,133 @@ func (ns NullAggregationEnum) Value() (driver.Value, error) {
 	return string(ns.AggregationEnum), nil
 }
 
+type AlertStatusEnum string
+
+const (
+	AlertStatusEnumOk        AlertStatusEnum = "ok"
+	AlertStatusEnumFiring    AlertStatusEnum = "firing"
+	AlertStatusEnumNotifying AlertStatusEnum = "notifying"
+)
+
+func (e *AlertStatusEnum) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = AlertStatusEnum(s)
+	case string:
+		*e = AlertStatusEnum(s)
+	default:
+		return fmt.Errorf("unsupported scan type for AlertStatusEnum: %T", src)
+	}
+	return nil
+}
+
+type NullAlertStatusEnum struct {
+	AlertStatusEnum AlertStatusEnum
+	Valid           bool // Valid is true if AlertStatusEnum is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullAlertStatusEnum) Scan(value interface{}) error {
+	if value == nil {
+		ns.AlertStatusEnum, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.AlertStatusEnum.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullAlertStatusEnum) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.AlertStatusEnum), nil
+}
+
+type AlertThresholdTypeEnum string
+
+const (
+	AlertThresholdTypeEnumAbsolute AlertThresholdTypeEnum = "absolute"
+	AlertThresholdTypeEnumPercent  AlertThresholdTypeEnum = "percent"
+)
+
+func (e *AlertThresholdTypeEnum) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = AlertThresholdTypeEnum(s)
+	case string:
+		*e = AlertThresholdTypeEnum(s)
+	default:
+		return fmt.Errorf("unsupported scan type for AlertThresholdTypeEnum: %T", src)
+	}
+	return nil
+}
+
+type NullAlertThresholdTypeEnum struct {
+	AlertThresholdTypeEnum AlertThresholdTypeEnum
+	Valid                  bool // Valid is true if AlertThresholdTypeEnum is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullAlertThresholdTypeEnum) Scan(value interface{}) error {
+	if value == nil {
+		ns.AlertThresholdTypeEnum, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.AlertThresholdTypeEnum.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullAlertThresholdTypeEnum) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.AlertThresholdTypeEnum), nil
+}
+
+type EntitlementOverrideEffectEnum string
+
+const (
+	EntitlementOverrideEffectEnumGrant  EntitlementOverrideEffectEnum = "grant"
+	EntitlementOverrideEffectEnumRevoke EntitlementOverrideEffectEnum = "revoke"
+)
+
+func (e *EntitlementOverrideEffectEnum) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = EntitlementOverrideEffectEnum(s)
+	case string:
+		*e = EntitlementOverrideEffectEnum(s)
+	default:
+		return fmt.Errorf("unsupported scan type for EntitlementOverrideEffectEnum: %T", src)
+	}
+	return nil
+}
+
+type NullEntitlementOverrideEffectEnum struct {
+	EntitlementOverrideEffectEnum EntitlementOverrideEffectEnum
+	Valid                         bool // Valid is true if EntitlementOverrideEffectEnum is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullEntitlementOverrideEffectEnum) Scan(value interface{}) error {
+	if value == nil {
+		ns.EntitlementOverrideEffectEnum, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.EntitlementOverrideEffectEnum.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullEntitlementOverrideEffectEnum) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.EntitlementOverrideEffectEnum), nil
+}
+
 type FeatureEnum string
 
 const (
@@ -99,6 +226,137 @@ func (ns NullFeatureEnum) Value() (driver.Value, error) {
 	return string(ns.FeatureEnum), nil
 }
 
+type MeterGranularityEnum string
+
+const (
+	MeterGranularityEnumSecond MeterGranularityEnum = "second"
+	MeterGranularityEnumMinute MeterGranularityEnum = "minute"
+	MeterGranularityEnumHour   MeterGranularityEnum = "hour"
+	MeterGranularityEnumDay    MeterGranularityEnum = "day"
+)
+
+func (e *MeterGranularityEnum) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = MeterGranularityEnum(s)
+	case string:
+		*e = MeterGranularityEnum(s)
+	default:
+		return fmt.Errorf("unsupported scan type for MeterGranularityEnum: %T", src)
+	}
+	return nil
+}
+
+type NullMeterGranularityEnum struct {
+	MeterGranularityEnum MeterGranularityEnum
+	Valid                bool // Valid is true if MeterGranularityEnum is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullMeterGranularityEnum) Scan(value interface{}) error {
+	if value == nil {
+		ns.MeterGranularityEnum, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.MeterGranularityEnum.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullMeterGranularityEnum) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.MeterGranularityEnum), nil
+}
+
+type MeterStatusEnum string
+
+const (
+	MeterStatusEnumPending      MeterStatusEnum = "pending"
+	MeterStatusEnumActive       MeterStatusEnum = "active"
+	MeterStatusEnumDeleting     MeterStatusEnum = "deleting"
+	MeterStatusEnumFailed       MeterStatusEnum = "failed"
+	MeterStatusEnumDeleteFailed MeterStatusEnum = "delete_failed"
+)
+
+func (e *MeterStatusEnum) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = MeterStatusEnum(s)
+	case string:
+		*e = MeterStatusEnum(s)
+	default:
+		return fmt.Errorf("unsupported scan type for MeterStatusEnum: %T", src)
+	}
+	return nil
+}
+
+type NullMeterStatusEnum struct {
+	MeterStatusEnum MeterStatusEnum
+	Valid           bool // Valid is true if MeterStatusEnum is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullMeterStatusEnum) Scan(value interface{}) error {
+	if value == nil {
+		ns.MeterStatusEnum, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.MeterStatusEnum.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullMeterStatusEnum) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.MeterStatusEnum), nil
+}
+
+type MeterTypeEnum string
+
+const (
+	MeterTypeEnumStandard MeterTypeEnum = "standard"
+	MeterTypeEnumDerived  MeterTypeEnum = "derived"
+)
+
+func (e *MeterTypeEnum) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = MeterTypeEnum(s)
+	case string:
+		*e = MeterTypeEnum(s)
+	default:
+		return fmt.Errorf("unsupported scan type for MeterTypeEnum: %T", src)
+	}
+	return nil
+}
+
+type NullMeterTypeEnum struct {
+	MeterTypeEnum MeterTypeEnum
+	Valid         bool // Valid is true if MeterTypeEnum is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullMeterTypeEnum) Scan(value interface{}) error {
+	if value == nil {
+		ns.MeterTypeEnum, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.MeterTypeEnum.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullMeterTypeEnum) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.MeterTypeEnum), nil
+}
+
 type MeteredActionAtLimitEnum string
 
 const (
@@ -189,6 +447,133 @@ func (ns NullMeteredResetPeriodEnum) Value() (driver.Value, error) {
 	return string(ns.MeteredResetPeriodEnum), nil
 }
 
+type PlanAssignmentKindEnum string
+
+const (
+	PlanAssignmentKindEnumStandard PlanAssignmentKindEnum = "standard"
+	PlanAssignmentKindEnumTrial    PlanAssignmentKindEnum = "trial"
+)
+
+func (e *PlanAssignmentKindEnum) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = PlanAssignmentKindEnum(s)
+	case string:
+		*e = PlanAssignmentKindEnum(s)
+	default:
+		return fmt.Errorf("unsupported scan type for PlanAssignmentKindEnum: %T", src)
+	}
+	return nil
+}
+
+type NullPlanAssignmentKindEnum struct {
+	PlanAssignmentKindEnum PlanAssignmentKindEnum
+	Valid                  bool // Valid is true if PlanAssignmentKindEnum is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullPlanAssignmentKindEnum) Scan(value interface{}) error {
+	if value == nil {
+		ns.PlanAssignmentKindEnum, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.PlanAssignmentKindEnum.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullPlanAssignmentKindEnum) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.PlanAssignmentKindEnum), nil
+}
+
+type PlanChangeTypeEnum string
+
+const (
+	PlanChangeTypeEnumUpgrade      PlanChangeTypeEnum = "upgrade"
+	PlanChangeTypeEnumDowngrade    PlanChangeTypeEnum = "downgrade"
+	PlanChangeTypeEnumCancellation PlanChangeTypeEnum = "cancellation"
+)
+
+func (e *PlanChangeTypeEnum) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = PlanChangeTypeEnum(s)
+	case string:
+		*e = PlanChangeTypeEnum(s)
+	default:
+		return fmt.Errorf("unsupported scan type for PlanChangeTypeEnum: %T", src)
+	}
+	return nil
+}
+
+type NullPlanChangeTypeEnum struct {
+	PlanChangeTypeEnum PlanChangeTypeEnum
+	Valid              bool // Valid is true if PlanChangeTypeEnum is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullPlanChangeTypeEnum) Scan(value interface{}) error {
+	if value == nil {
+		ns.PlanChangeTypeEnum, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.PlanChangeTypeEnum.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullPlanChangeTypeEnum) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.PlanChangeTypeEnum), nil
+}
+
+type PlanKindEnum string
+
+const (
+	PlanKindEnumBase  PlanKindEnum = "base"
+	PlanKindEnumAddon PlanKindEnum = "addon"
+)
+
+func (e *PlanKindEnum) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = PlanKindEnum(s)
+	case string:
+		*e = PlanKindEnum(s)
+	default:
+		return fmt.Errorf("unsupported scan type for PlanKindEnum: %T", src)
+	}
+	return nil
+}
+
+type NullPlanKindEnum struct {
+	PlanKindEnum PlanKindEnum
+	Valid        bool // Valid is true if PlanKindEnum is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullPlanKindEnum) Scan(value interface{}) error {
+	if value == nil {
+		ns.PlanKindEnum, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.PlanKindEnum.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullPlanKindEnum) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.PlanKindEnum), nil
+}
+
 type PlanTypeEnum string
 
 const (
@@ -231,6 +616,313 @@ func (ns NullPlanTypeEnum) Value() (driver.Value, error) {
 	return string(ns.PlanTypeEnum), nil
 }
 
+type QuotaMergeRuleEnum string
+
+const (
+	QuotaMergeRuleEnumSum QuotaMergeRuleEnum = "sum"
+	QuotaMergeRuleEnumMax QuotaMergeRuleEnum = "max"
+)
+
+func (e *QuotaMergeRuleEnum) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = QuotaMergeRuleEnum(s)
+	case string:
+		*e = QuotaMergeRuleEnum(s)
+	default:
+		return fmt.Errorf("unsupported scan type for QuotaMergeRuleEnum: %T", src)
+	}
+	return nil
+}
+
+type NullQuotaMergeRuleEnum struct {
+	QuotaMergeRuleEnum QuotaMergeRuleEnum
+	Valid              bool // Valid is true if QuotaMergeRuleEnum is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullQuotaMergeRuleEnum) Scan(value interface{}) error {
+	if value == nil {
+		ns.QuotaMergeRuleEnum, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.QuotaMergeRuleEnum.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullQuotaMergeRuleEnum) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.QuotaMergeRuleEnum), nil
+}
+
+type QuotaOverageTypeEnum string
+
+const (
+	QuotaOverageTypeEnumAbsolute QuotaOverageTypeEnum = "absolute"
+	QuotaOverageTypeEnumPercent  QuotaOverageTypeEnum = "percent"
+)
+
+func (e *QuotaOverageTypeEnum) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = QuotaOverageTypeEnum(s)
+	case string:
+		*e = QuotaOverageTypeEnum(s)
+	default:
+		return fmt.Errorf("unsupported scan type for QuotaOverageTypeEnum: %T", src)
+	}
+	return nil
+}
+
+type NullQuotaOverageTypeEnum struct {
+	QuotaOverageTypeEnum QuotaOverageTypeEnum
+	Valid                bool // Valid is true if QuotaOverageTypeEnum is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullQuotaOverageTypeEnum) Scan(value interface{}) error {
+	if value == nil {
+		ns.QuotaOverageTypeEnum, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.QuotaOverageTypeEnum.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullQuotaOverageTypeEnum) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.QuotaOverageTypeEnum), nil
+}
+
+type SubjectTypeEnum string
+
+const (
+	SubjectTypeEnumOrganization SubjectTypeEnum = "organization"
+	SubjectTypeEnumUser         SubjectTypeEnum = "user"
+)
+
+func (e *SubjectTypeEnum) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = SubjectTypeEnum(s)
+	case string:
+		*e = SubjectTypeEnum(s)
+	default:
+		return fmt.Errorf("unsupported scan type for SubjectTypeEnum: %T", src)
+	}
+	return nil
+}
+
+type NullSubjectTypeEnum struct {
+	SubjectTypeEnum SubjectTypeEnum
+	Valid           bool // Valid is true if SubjectTypeEnum is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullSubjectTypeEnum) Scan(value interface{}) error {
+	if value == nil {
+		ns.SubjectTypeEnum, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.SubjectTypeEnum.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullSubjectTypeEnum) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.SubjectTypeEnum), nil
+}
+
+type TrialEndActionEnum string
+
+const (
+	TrialEndActionEnumConvert  TrialEndActionEnum = "convert"
+	TrialEndActionEnumFallback TrialEndActionEnum = "fallback"
+	TrialEndActionEnumExpire   TrialEndActionEnum = "expire"
+)
+
+func (e *TrialEndActionEnum) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = TrialEndActionEnum(s)
+	case string:
+		*e = TrialEndActionEnum(s)
+	default:
+		return fmt.Errorf("unsupported scan type for TrialEndActionEnum: %T", src)
+	}
+	return nil
+}
+
+type NullTrialEndActionEnum struct {
+	TrialEndActionEnum TrialEndActionEnum
+	Valid              bool // Valid is true if TrialEndActionEnum is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullTrialEndActionEnum) Scan(value interface{}) error {
+	if value == nil {
+		ns.TrialEndActionEnum, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.TrialEndActionEnum.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullTrialEndActionEnum) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.TrialEndActionEnum), nil
+}
+
+type TrialStatusEnum string
+
+const (
+	TrialStatusEnumActive    TrialStatusEnum = "active"
+	TrialStatusEnumConverted TrialStatusEnum = "converted"
+	TrialStatusEnumFellBack  TrialStatusEnum = "fell_back"
+	TrialStatusEnumExpired   TrialStatusEnum = "expired"
+)
+
+func (e *TrialStatusEnum) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = TrialStatusEnum(s)
+	case string:
+		*e = TrialStatusEnum(s)
+	default:
+		return fmt.Errorf("unsupported scan type for TrialStatusEnum: %T", src)
+	}
+	return nil
+}
+
+type NullTrialStatusEnum struct {
+	TrialStatusEnum TrialStatusEnum
+	Valid           bool // Valid is true if TrialStatusEnum is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullTrialStatusEnum) Scan(value interface{}) error {
+	if value == nil {
+		ns.TrialStatusEnum, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.TrialStatusEnum.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullTrialStatusEnum) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.TrialStatusEnum), nil
+}
+
+type WebhookDeliveryStatusEnum string
+
+const (
+	WebhookDeliveryStatusEnumPending   WebhookDeliveryStatusEnum = "pending"
+	WebhookDeliveryStatusEnumSucceeded WebhookDeliveryStatusEnum = "succeeded"
+	WebhookDeliveryStatusEnumFailed    WebhookDeliveryStatusEnum = "failed"
+)
+
+func (e *WebhookDeliveryStatusEnum) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = WebhookDeliveryStatusEnum(s)
+	case string:
+		*e = WebhookDeliveryStatusEnum(s)
+	default:
+		return fmt.Errorf("unsupported scan type for WebhookDeliveryStatusEnum: %T", src)
+	}
+	return nil
+}
+
+type NullWebhookDeliveryStatusEnum struct {
+	WebhookDeliveryStatusEnum WebhookDeliveryStatusEnum
+	Valid                     bool // Valid is true if WebhookDeliveryStatusEnum is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullWebhookDeliveryStatusEnum) Scan(value interface{}) error {
+	if value == nil {
+		ns.WebhookDeliveryStatusEnum, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.WebhookDeliveryStatusEnum.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullWebhookDeliveryStatusEnum) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.WebhookDeliveryStatusEnum), nil
+}
+
+type AlertRule struct {
+	ID            pgtype.UUID
+	TenantSlug    string
+	Name          string
+	MeterSlug     pgtype.Text
+	PlanFeatureID pgtype.UUID
+	SubjectType   SubjectTypeEnum
+	SubjectIds    []string
+	ThresholdType AlertThresholdTypeEnum
+	Threshold     float64
+	WindowMinutes pgtype.Int8
+	Enabled       bool
+	CreatedAt     pgtype.Timestamptz
+	UpdatedAt     pgtype.Timestamptz
+	CreatedBy     string
+	UpdatedBy     string
+}
+
+type AlertState struct {
+	ID             pgtype.UUID
+	AlertRuleID    pgtype.UUID
+	SubjectID      string
+	Status         AlertStatusEnum
+	Value          float64
+	ThresholdValue float64
+	PeriodStart    pgtype.Timestamptz
+	FiredAt        pgtype.Timestamptz
+	ResolvedAt     pgtype.Timestamptz
+	EvaluatedAt    pgtype.Timestamptz
+}
+
+type EntitlementOverride struct {
+	ID                  pgtype.UUID
+	TenantSlug          string
+	SubjectType         SubjectTypeEnum
+	SubjectID           string
+	PlanAssignmentID    pgtype.UUID
+	FeatureID           pgtype.UUID
+	Effect              EntitlementOverrideEffectEnum
+	LimitValue          pgtype.Int8
+	ResetPeriod         NullMeteredResetPeriodEnum
+	CustomPeriodMinutes pgtype.Int8
+	ActionAtLimit       NullMeteredActionAtLimitEnum
+	Reason              pgtype.Text
+	ExpiresAt           pgtype.Timestamptz
+	CreatedAt           pgtype.Timestamptz
+	UpdatedAt           pgtype.Timestamptz
+	CreatedBy           string
+	UpdatedBy           string
+}
+
 type Feature struct {
 	ID          pgtype.UUID
 	Name        string
@@ -243,22 +935,35 @@ type Feature struct {
 	UpdatedAt   pgtype.Timestamptz
 	CreatedBy   string
 	UpdatedBy   string
+	MeterSlugs  []string
+	MergeRule   QuotaMergeRuleEnum
 }
 
 type Meter struct {
-	ID            pgtype.UUID
-	Name          string
-	Slug          string
-	EventType     string
-	Description   pgtype.Text
-	ValueProperty pgtype.Text
-	Properties    []string
-	Aggregation   AggregationEnum
-	TenantSlug    string
-	CreatedAt     pgtype.Timestamptz
-	UpdatedAt     pgtype.Timestamptz
-	CreatedBy     string
-	UpdatedBy     string
+	ID                pgtype.UUID
+	Name              string
+	Slug              string
+	EventType         pgtype.Text
+	Description       pgtype.Text
+	ValueProperty     pgtype.Text
+	Properties        []string
+	Aggregation       NullAggregationEnum
+	TenantSlug        string
+	CreatedAt         pgtype.Timestamptz
+	UpdatedAt         pgtype.Timestamptz
+	CreatedBy         string
+	UpdatedBy         string
+	Type              MeterTypeEnum
+	Formula           pgtype.Text
+	SourceMeters      []string
+	Granularity       MeterGranularityEnum
+	Rollups           []string
+	Status            MeterStatusEnum
+	StatusError       pgtype.Text
+	ReconcileAttempts int32
+	Filter            []byte
+	EventTypes        []string
+	ValueProperties   []byte
 }
 
 type Plan struct {
@@ -273,19 +978,23 @@ type Plan struct {
 	ArchivedAt  pgtype.Timestamptz
 	CreatedBy   string
 	UpdatedBy   string
+	Kind        PlanKindEnum
 }
 
 type PlanAssignment struct {
-	ID             pgtype.UUID
-	PlanID         pgtype.UUID
-	OrganizationID pgtype.Text
-	UserID         pgtype.Text
-	ValidFrom      pgtype.Timestamptz
-	ValidUntil     pgtype.Timestamptz
-	CreatedAt      pgtype.Timestamptz
-	UpdatedAt      pgtype.Timestamptz
-	CreatedBy      string
-	UpdatedBy      string
+	ID                   pgtype.UUID
+	PlanID               pgtype.UUID
+	OrganizationID       pgtype.Text
+	UserID               pgtype.Text
+	ValidFrom            pgtype.Timestamptz
+	ValidUntil           pgtype.Timestamptz
+	CreatedAt            pgtype.Timestamptz
+	UpdatedAt            pgtype.Timestamptz
+	CreatedBy            string
+	UpdatedBy            string
+	PlanVersionID        pgtype.UUID
+	PendingPlanVersionID pgtype.UUID
+	Kind                 PlanAssignmentKindEnum
 }
 
 type PlanAssignmentHistory struct {
@@ -302,13 +1011,114 @@ type PlanAssignmentHistory struct {
 	UpdatedBy      string
 }
 
+type PlanChange struct {
+	ID                 pgtype.UUID
+	TenantSlug         string
+	Type               PlanChangeTypeEnum
+	OrganizationID     pgtype.Text
+	UserID             pgtype.Text
+	FromAssignmentID   pgtype.UUID
+	FromPlanID         pgtype.UUID
+	ToAssignmentID     pgtype.UUID
+	ToPlanID           pgtype.UUID
+	EffectiveAt        pgtype.Timestamptz
+	PreviousValidUntil pgtype.Timestamptz
+	Reason             pgtype.Text
+	CanceledAt         pgtype.Timestamptz
+	CanceledBy         pgtype.Text
+	CreatedAt          pgtype.Timestamptz
+	UpdatedAt          pgtype.Timestamptz
+	CreatedBy          string
+	UpdatedBy          string
+}
+
 type PlanFeatureQuotum struct {
-	ID                  pgtype.UUID
-	PlanFeatureID       pgtype.UUID
-	LimitValue          int64
-	ResetPeriod         MeteredResetPeriodEnum
-	CustomPeriodMinutes pgtype.Int8
-	ActionAtLimit       MeteredActionAtLimitEnum
-	CreatedAt           pgtype.Timestamptz
-	UpdatedAt           pgtype.Timestamptz
+	ID                   pgtype.UUID
+	PlanFeatureID        pgtype.UUID
+	LimitValue           int64
+	ResetPeriod          MeteredResetPeriodEnum
+	CustomPeriodMinutes  pgtype.Int8
+	ActionAtLimit        MeteredActionAtLimitEnum
+	CreatedAt            pgtype.Timestamptz
+	UpdatedAt            pgtype.Timestamptz
+	SoftLimitValue       pgtype.Int8
+	OverageAllowance     pgtype.Int8
+	OverageAllowanceType NullQuotaOverageTypeEnum
+	GracePeriodMinutes   pgtype.Int8
+}
+
+type PlanTrial struct {
+	ID                    pgtype.UUID
+	TenantSlug            string
+	AssignmentID          pgtype.UUID
+	TargetPlanID          pgtype.UUID
+	FallbackPlanID        pgtype.UUID
+	EndAction             TrialEndActionEnum
+	Status                TrialStatusEnum
+	ResultingAssignmentID pgtype.UUID
+	EndedAt               pgtype.Timestamptz
+	CreatedAt             pgtype.Timestamptz
+	UpdatedAt             pgtype.Timestamptz
+	CreatedBy             string
+	UpdatedBy             string
+}
+
+type PlanVersion struct {
+	ID          pgtype.UUID
+	PlanID      pgtype.UUID
+	Version     int32
+	Description pgtype.Text
+	CreatedAt   pgtype.Timestamptz
+	CreatedBy   string
+}
+
+type QuotaGraceState struct {
+	ID                pgtype.UUID
+	TenantSlug        string
+	SubjectType       SubjectTypeEnum
+	SubjectID         string
+	FeatureID         pgtype.UUID
+	AllowanceUsedUpAt pgtype.Timestamptz
+	UpdatedAt         pgtype.Timestamptz
+}
+
+type WebhookDelivery struct {
+	ID            pgtype.UUID
+	EndpointID    pgtype.UUID
+	TenantSlug    string
+	EventID       pgtype.UUID
+	EventType     string
+	Payload       []byte
+	Status        WebhookDeliveryStatusEnum
+	Attempts      int32
+	NextAttemptAt pgtype.Timestamptz
+	LastAttemptAt pgtype.Timestamptz
+	LastError     pgtype.Text
+	CreatedAt     pgtype.Timestamptz
+	UpdatedAt     pgtype.Timestamptz
+}
+
+type WebhookDeliveryAttempt struct {
+	ID             pgtype.UUID
+	DeliveryID     pgtype.UUID
+	Attempt        int32
+	ResponseStatus pgtype.Int4
+	ResponseBody   pgtype.Text
+	Error          pgtype.Text
+	DurationMs     int64
+	AttemptedAt    pgtype.Timestamptz
+}
+
+type WebhookEndpoint struct {
+	ID          pgtype.UUID
+	TenantSlug  string
+	Url         string
+	Description pgtype.Text
+	Secret      string
+	EventTypes  []string
+	Enabled     bool
+	CreatedAt   pgtype.Timestamptz
+	UpdatedAt   pgtype.Timestamptz
+	CreatedBy   string
+	UpdatedBy   string
 }
@@ -17,7 +17,7 @@ SET archived_at = CURRENT_TIMESTAMP,
     updated_by = $3
 WHERE id = $1
 AND tenant_slug = $2
-RETURNING id, name, slug, description, type, tenant_slug, created_at, updated_at, archived_at, created_by, updated_by
+RETURNING id, name, slug, description, type, tenant_slug, created_at, updated_at, archived_at, created_by, updated_by, kind
 `
 
 type ArchivePlanByIDParams struct {
@@ -41,6 +41,7 @@ func (q *Queries) ArchivePlanByID(ctx context.Context, arg ArchivePlanByIDParams
 		&i.ArchivedAt,
 		&i.CreatedBy,
 		&i.UpdatedBy,
+		&i.Kind,
 	)
 	return i, err
 }
@@ -51,7 +52,7 @@ SET archived_at = CURRENT_TIMESTAMP,
     updated_by = $3
 WHERE slug = $1
 AND tenant_slug = $2
-RETURNING id, name, slug, description, type, tenant_slug, created_at, updated_at, archived_at, created_by, updated_by
+RETURNING id, name, slug, description, type, tenant_slug, created_at, updated_at, archived_at, created_by, updated_by, kind
 `
 
 type ArchivePlanBySlugParams struct {
@@ -75,23 +76,100 @@ func (q *Queries) ArchivePlanBySlug(ctx context.Context, arg ArchivePlanBySlugPa
 		&i.ArchivedAt,
 		&i.CreatedBy,
 		&i.UpdatedBy,
+		&i.Kind,
 	)
 	return i, err
 }
 
+const clonePlanFeatureQuotas = `-- name: ClonePlanFeatureQuotas :exec
+INSERT INTO plan_feature_quota (
+    plan_feature_id,
+    limit_value,
+    reset_period,
+    custom_period_minutes,
+    action_at_limit,
+    soft_limit_value,
+    overage_allowance,
+    overage_allowance_type,
+    grace_period_minutes
+)
+SELECT
+    c.id,
+    coalesce(($1::jsonb ->> f.slug)::bigint, q.limit_value),
+    q.reset_period,
+    q.custom_period_minutes,
+    q.action_at_limit,
+    q.soft_limit_value,
+    q.overage_allowance,
+    q.overage_allowance_type,
+    q.grace_period_minutes
+FROM plan_feature c
+JOIN plan_feature s ON s.feature_id = c.feature_id AND s.plan_id = $2::uuid AND s.plan_version_id IS NULL
+JOIN plan_feature_quota q ON q.plan_feature_id = s.id
+JOIN feature f ON f.id = c.feature_id
+WHERE c.plan_id = $3::uuid
+AND c.plan_version_id IS NULL
+`
+
+type ClonePlanFeatureQuotasParams struct {
+	LimitOverrides []byte
+	SourcePlanID   pgtype.UUID
+	PlanID         pgtype.UUID
+}
+
+// copies the quotas of the draft features of a plan onto the cloned features, replacing the limits
+// overridden by feature slug
+func (q *Queries) ClonePlanFeatureQuotas(ctx context.Context, arg ClonePlanFeatureQuotasParams) error {
+	_, err := q.db.Exec(ctx, clonePlanFeatureQuotas, arg.LimitOverrides, arg.SourcePlanID, arg.PlanID)
+	return err
+}
+
+const clonePlanFeatures = `-- name: ClonePlanFeatures :exec
+INSERT INTO plan_feature (
+    plan_id,
+    feature_id,
+    config,
+    created_by,
+    updated_by
+)
+SELECT
+    $1::uuid,
+    feature_id,
+    config,
+    $2,
+    $2
+FROM plan_feature
+WHERE plan_id = $3::uuid
+AND plan_version_id IS NULL
+`
+
+type ClonePlanFeaturesParams struct {
+	PlanID       pgtype.UUID
+	CreatedBy    string
+	SourcePlanID pgtype.UUID
+}
+
+// copies the draft features of a plan onto another plan
+func (q *Queries) ClonePlanFeatures(ctx context.Context, arg ClonePlanFeaturesParams) error {
+	_, err := q.db.Exec(ctx, clonePlanFeatures, arg.PlanID, arg.CreatedBy, arg.SourcePlanID)
+	return err
+}
+
 const countPlans = `-- name: CountPlans :one
 SELECT count(*) FROM plan
 WHERE tenant_slug = $1
 AND ($2::plan_type_enum is null or type = $2::plan_type_enum)
+AND ($3::plan_kind_enum is null or kind = $3::plan_kind_enum)
 `
 
 type CountPlansParams struct {
 	TenantSlug string
 	Type       NullPlanTypeEnum
+	Kind       NullPlanKindEnum
 }
 
 func (q *Queries) CountPlans(ctx context.Context, arg CountPlansParams) (int64, error) {
-	row := q.db.QueryRow(ctx, countPlans, arg.TenantSlug, arg.Type)
+	row := q.db.QueryRow(ctx, countPlans, arg.TenantSlug, arg.Type, arg.Kind)
 	var count int64
 	err := row.Scan(&count)
 	return count, err
@@ -105,10 +183,11 @@ INSERT INTO plan (
     type,
     tenant_slug,
     created_by,
-    updated_by
+    updated_by,
+    kind
 ) VALUES (
-    $1, $2, $3, $4, $5, $6, $7
-) RETURNING id, name, slug, description, type, tenant_slug, created_at, updated_at, archived_at, created_by, updated_by
+    $1, $2, $3, $4, $5, $6, $7, $8
+) RETURNING id, name, slug, description, type, tenant_slug, created_at, updated_at, archived_at, created_by, updated_by, kind
 `
 
 type CreatePlanParams struct {
@@ -119,6 +198,7 @@ type CreatePlanParams struct {
 	TenantSlug  string
 	CreatedBy   string
 	UpdatedBy   string
+	Kind        PlanKindEnum
 }
 
 func (q *Queries) CreatePlan(ctx context.Context, arg CreatePlanParams) (Plan, error) {
@@ -130,6 +210,7 @@ func (q *Queries) CreatePlan(ctx context.Context, arg CreatePlanParams) (Plan, e
 		arg.TenantSlug,
 		arg.CreatedBy,
 		arg.UpdatedBy,
+		arg.Kind,
 	)
 	var i Plan
 	err := row.Scan(
@@ -144,6 +225,7 @@ func (q *Queries) CreatePlan(ctx context.Context, arg CreatePlanParams) (Plan, e
 		&i.ArchivedAt,
 		&i.CreatedBy,
 		&i.UpdatedBy,
+		&i.Kind,
 	)
 	return i, err
 }
@@ -181,7 +263,7 @@ func (q *Queries) DeletePlanBySlug(ctx context.Context, arg DeletePlanBySlugPara
 }
 
 const getPlanByID = `-- name: GetPlanByID :one
-SELECT id, name, slug, description, type, tenant_slug, created_at, updated_at, archived_at, created_by, updated_by FROM plan
+SELECT id, name, slug, description, type, tenant_slug, created_at, updated_at, archived_at, created_by, updated_by, kind FROM plan
 WHERE id = $1
 AND tenant_slug = $2
 `
@@ -206,12 +288,13 @@ func (q *Queries) GetPlanByID(ctx context.Context, arg GetPlanByIDParams) (Plan,
 		&i.ArchivedAt,
 		&i.CreatedBy,
 		&i.UpdatedBy,
+		&i.Kind,
 	)
 	return i, err
 }
 
 const getPlanBySlug = `-- name: GetPlanBySlug :one
-SELECT id, name, slug, description, type, tenant_slug, created_at, updated_at, archived_at, created_by, updated_by FROM plan
+SELECT id, name, slug, description, type, tenant_slug, created_at, updated_at, archived_at, created_by, updated_by, kind FROM plan
 WHERE slug = $1
 AND tenant_slug = $2
 `
@@ -236,15 +319,17 @@ func (q *Queries) GetPlanBySlug(ctx context.Context, arg GetPlanBySlugParams) (P
 		&i.ArchivedAt,
 		&i.CreatedBy,
 		&i.UpdatedBy,
+		&i.Kind,
 	)
 	return i, err
 }
 
 const listPlansPaginated = `-- name: ListPlansPaginated :many
-SELECT id, name, slug, description, type, tenant_slug, created_at, updated_at, archived_at, created_by, updated_by FROM plan
+SELECT id, name, slug, description, type, tenant_slug, created_at, updated_at, archived_at, created_by, updated_by, kind FROM plan
 WHERE tenant_slug = $1
 and ($4::plan_type_enum is null or type = $4::plan_type_enum)
-and ($5::boolean is null or archived_at is not null = $5::boolean)
+and ($5::plan_kind_enum is null or kind = $5::plan_kind_enum)
+and ($6::boolean is null or archived_at is not null = $6::boolean)
 ORDER BY created_at DESC
 LIMIT $2
 OFFSET $3
@@ -255,6 +340,7 @@ type ListPlansPaginatedParams struct {
 	Limit      int32
 	Offset     int32
 	Type       NullPlanTypeEnum
+	Kind       NullPlanKindEnum
 	Archived   pgtype.Bool
 }
 
@@ -264,6 +350,7 @@ func (q *Queries) ListPlansPaginated(ctx context.Context, arg ListPlansPaginated
 		arg.Limit,
 		arg.Offset,
 		arg.Type,
+		arg.Kind,
 		arg.Archived,
 	)
 	if err != nil {
@@ -285,6 +372,7 @@ func (q *Queries) ListPlansPaginated(ctx context.Context, arg ListPlansPaginated
 			&i.ArchivedAt,
 			&i.CreatedBy,
 			&i.UpdatedBy,
+			&i.Kind,
 		); err != nil {
 			return nil, err
 		}
@@ -302,7 +390,7 @@ SET archived_at = null,
     updated_by = $3
 WHERE id = $1
 AND tenant_slug = $2
-RETURNING id, name, slug, description, type, tenant_slug, created_at, updated_at, archived_at, created_by, updated_by
+RETURNING id, name, slug, description, type, tenant_slug, created_at, updated_at, archived_at, created_by, updated_by, kind
 `
 
 type UnArchivePlanByIDParams struct {
@@ -326,6 +414,7 @@ func (q *Queries) UnArchivePlanByID(ctx context.Context, arg UnArchivePlanByIDPa
 		&i.ArchivedAt,
 		&i.CreatedBy,
 		&i.UpdatedBy,
+		&i.Kind,
 	)
 	return i, err
 }
@@ -336,7 +425,7 @@ SET archived_at = null,
     updated_by = $3
 WHERE slug = $1
 AND tenant_slug = $2
-RETURNING id, name, slug, description, type, tenant_slug, created_at, updated_at, archived_at, created_by, updated_by
+RETURNING id, name, slug, description, type, tenant_slug, created_at, updated_at, archived_at, created_by, updated_by, kind
 `
 
 type UnArchivePlanBySlugParams struct {
@@ -360,6 +449,7 @@ func (q *Queries) UnArchivePlanBySlug(ctx context.Context, arg UnArchivePlanBySl
 		&i.ArchivedAt,
 		&i.CreatedBy,
 		&i.UpdatedBy,
+		&i.Kind,
 	)
 	return i, err
 }
@@ -371,7 +461,7 @@ SET name = coalesce($5, name),
     updated_by = $4
 WHERE id = $2
 AND tenant_slug = $3
-RETURNING id, name, slug, description, type, tenant_slug, created_at, updated_at, archived_at, created_by, updated_by
+RETURNING id, name, slug, description, type, tenant_slug, created_at, updated_at, archived_at, created_by, updated_by, kind
 `
 
 type UpdatePlanByIDParams struct {
@@ -403,6 +493,7 @@ func (q *Queries) UpdatePlanByID(ctx context.Context, arg UpdatePlanByIDParams)
 		&i.ArchivedAt,
 		&i.CreatedBy,
 		&i.UpdatedBy,
+		&i.Kind,
 	)
 	return i, err
 }
@@ -414,7 +505,7 @@ SET name = coalesce($5, name),
     updated_by = $3
 WHERE slug = $2
 AND tenant_slug = $4
-RETURNING id, name, slug, description, type, tenant_slug, created_at, updated_at, archived_at, created_by, updated_by
+RETURNING id, name, slug, description, type, tenant_slug, created_at, updated_at, archived_at, created_by, updated_by, kind
 `
 
 type UpdatePlanBySlugParams struct {
@@ -446,6 +537,7 @@ func (q *Queries) UpdatePlanBySlug(ctx context.Context, arg UpdatePlanBySlugPara
 		&i.ArchivedAt,
 		&i.CreatedBy,
 		&i.UpdatedBy,
+		&i.Kind,
 	)
 	return i, err
 }
@@ -19,10 +19,11 @@ insert into plan_assignment (
     valid_from,
     valid_until,
     created_by,
-    updated_by
+    updated_by,
+    plan_version_id
 ) values (
-$1, $2, $3, $4, $5, $6, $7
-) returning id, plan_id, organization_id, user_id, valid_from, valid_until, created_at, updated_at, created_by, updated_by
+$1, $2, $3, $4, $5, $6, $7, $8
+) returning id, plan_id, organization_id, user_id, valid_from, valid_until, created_at, updated_at, created_by, updated_by, plan_version_id, pending_plan_version_id, kind
 `
 
 type AssignPlanParams struct {
@@ -33,6 +34,7 @@ type AssignPlanParams struct {
 	ValidUntil     pgtype.Timestamptz
 	CreatedBy      string
 	UpdatedBy      string
+	PlanVersionID  pgtype.UUID
 }
 
 // assigns a plan to either an organization or a user based on which id is provided
@@ -45,6 +47,7 @@ func (q *Queries) AssignPlan(ctx context.Context, arg AssignPlanParams) (PlanAss
 		arg.ValidUntil,
 		arg.CreatedBy,
 		arg.UpdatedBy,
+		arg.PlanVersionID,
 	)
 	var i PlanAssignment
 	err := row.Scan(
@@ -58,6 +61,67 @@ func (q *Queries) AssignPlan(ctx context.Context, arg AssignPlanParams) (PlanAss
 		&i.UpdatedAt,
 		&i.CreatedBy,
 		&i.UpdatedBy,
+		&i.PlanVersionID,
+		&i.PendingPlanVersionID,
+		&i.Kind,
+	)
+	return i, err
+}
+
+const assignTrialPlan = `-- name: AssignTrialPlan :one
+INSERT INTO plan_assignment (
+    plan_id,
+    organization_id,
+    user_id,
+    valid_from,
+    valid_until,
+    created_by,
+    updated_by,
+    plan_version_id,
+    kind
+) VALUES (
+$1, $2, $3, $4, $5, $6, $7, $8, 'trial'
+) RETURNING id, plan_id, organization_id, user_id, valid_from, valid_until, created_at, updated_at, created_by, updated_by, plan_version_id, pending_plan_version_id, kind
+`
+
+type AssignTrialPlanParams struct {
+	PlanID         pgtype.UUID
+	OrganizationID pgtype.Text
+	UserID         pgtype.Text
+	ValidFrom      pgtype.Timestamptz
+	ValidUntil     pgtype.Timestamptz
+	CreatedBy      string
+	UpdatedBy      string
+	PlanVersionID  pgtype.UUID
+}
+
+// assigns a plan as a trial to either an organization or a user
+func (q *Queries) AssignTrialPlan(ctx context.Context, arg AssignTrialPlanParams) (PlanAssignment, error) {
+	row := q.db.QueryRow(ctx, assignTrialPlan,
+		arg.PlanID,
+		arg.OrganizationID,
+		arg.UserID,
+		arg.ValidFrom,
+		arg.ValidUntil,
+		arg.CreatedBy,
+		arg.UpdatedBy,
+		arg.PlanVersionID,
+	)
+	var i PlanAssignment
+	err := row.Scan(
+		&i.ID,
+		&i.PlanID,
+		&i.OrganizationID,
+		&i.UserID,
+		&i.ValidFrom,
+		&i.ValidUntil,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.CreatedBy,
+		&i.UpdatedBy,
+		&i.PlanVersionID,
+		&i.PendingPlanVersionID,
+		&i.Kind,
 	)
 	return i, err
 }
@@ -158,6 +222,243 @@ func (q *Queries) CountAssignmentsHistory(ctx context.Context, arg CountAssignme
 	return count, err
 }
 
+const countOverlappingBaseAssignments = `-- name: CountOverlappingBaseAssignments :one
+SELECT count(*)
+FROM plan_assignment pa
+INNER JOIN plan p ON pa.plan_id = p.id
+WHERE p.tenant_slug = $1
+AND p.kind = 'base'
+AND pa.organization_id IS NOT DISTINCT FROM $2
+AND pa.user_id IS NOT DISTINCT FROM $3
+AND ($4::timestamptz IS NULL OR $4::timestamptz = '0001-01-01 00:00:00+00' OR pa.valid_from < $4::timestamptz)
+AND (pa.valid_until IS NULL OR pa.valid_until = '0001-01-01 00:00:00+00' OR pa.valid_until > $5)
+`
+
+type CountOverlappingBaseAssignmentsParams struct {
+	TenantSlug     string
+	OrganizationID pgtype.Text
+	UserID         pgtype.Text
+	ValidUntil     pgtype.Timestamptz
+	ValidFrom      pgtype.Timestamptz
+}
+
+// counts the base plan assignments of an organization or user overlapping the given period;
+// a null or zero valid_until leaves the period open-ended
+func (q *Queries) CountOverlappingBaseAssignments(ctx context.Context, arg CountOverlappingBaseAssignmentsParams) (int64, error) {
+	row := q.db.QueryRow(ctx, countOverlappingBaseAssignments,
+		arg.TenantSlug,
+		arg.OrganizationID,
+		arg.UserID,
+		arg.ValidUntil,
+		arg.ValidFrom,
+	)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const deleteAssignmentByID = `-- name: DeleteAssignmentByID :exec
+DELETE FROM plan_assignment
+WHERE id = $1
+`
+
+func (q *Queries) DeleteAssignmentByID(ctx context.Context, id pgtype.UUID) error {
+	_, err := q.db.Exec(ctx, deleteAssignmentByID, id)
+	return err
+}
+
+const getAssignmentByID = `-- name: GetAssignmentByID :one
+SELECT pa.id, pa.plan_id, pa.organization_id, pa.user_id, pa.valid_from, pa.valid_until, pa.created_at, pa.updated_at, pa.created_by, pa.updated_by, pa.plan_version_id, pa.pending_plan_version_id, pa.kind
+FROM plan_assignment pa
+INNER JOIN plan p ON pa.plan_id = p.id
+WHERE pa.id = $1
+AND p.tenant_slug = $2
+`
+
+type GetAssignmentByIDParams struct {
+	ID         pgtype.UUID
+	TenantSlug string
+}
+
+func (q *Queries) GetAssignmentByID(ctx context.Context, arg GetAssignmentByIDParams) (PlanAssignment, error) {
+	row := q.db.QueryRow(ctx, getAssignmentByID, arg.ID, arg.TenantSlug)
+	var i PlanAssignment
+	err := row.Scan(
+		&i.ID,
+		&i.PlanID,
+		&i.OrganizationID,
+		&i.UserID,
+		&i.ValidFrom,
+		&i.ValidUntil,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.CreatedBy,
+		&i.UpdatedBy,
+		&i.PlanVersionID,
+		&i.PendingPlanVersionID,
+		&i.Kind,
+	)
+	return i, err
+}
+
+const insertAssignmentHistory = `-- name: InsertAssignmentHistory :exec
+INSERT INTO plan_assignment_history (
+    action,
+    plan_id,
+    organization_id,
+    user_id,
+    valid_from,
+    valid_until,
+    created_by,
+    updated_by
+) VALUES (
+$1, $2, $3, $4, $5, $6, $7, $7
+)
+`
+
+type InsertAssignmentHistoryParams struct {
+	Action         pgtype.Text
+	PlanID         pgtype.UUID
+	OrganizationID pgtype.Text
+	UserID         pgtype.Text
+	ValidFrom      pgtype.Timestamptz
+	ValidUntil     pgtype.Timestamptz
+	CreatedBy      string
+}
+
+// records an event of an assignment that does not show as a change of its row
+func (q *Queries) InsertAssignmentHistory(ctx context.Context, arg InsertAssignmentHistoryParams) error {
+	_, err := q.db.Exec(ctx, insertAssignmentHistory,
+		arg.Action,
+		arg.PlanID,
+		arg.OrganizationID,
+		arg.UserID,
+		arg.ValidFrom,
+		arg.ValidUntil,
+		arg.CreatedBy,
+	)
+	return err
+}
+
+const listActiveAssignmentsByPlan = `-- name: ListActiveAssignmentsByPlan :many
+SELECT pa.id, pa.plan_id, pa.organization_id, pa.user_id, pa.valid_from, pa.valid_until, pa.created_at, pa.updated_at, pa.created_by, pa.updated_by, pa.plan_version_id, pa.pending_plan_version_id, pa.kind
+FROM plan_assignment pa
+INNER JOIN plan p ON pa.plan_id = p.id
+WHERE pa.plan_id = $1
+AND p.tenant_slug = $2
+AND pa.valid_from <= $3
+AND (pa.valid_until IS NULL OR pa.valid_until = '0001-01-01 00:00:00+00' OR pa.valid_until > $3)
+ORDER BY pa.valid_from
+`
+
+type ListActiveAssignmentsByPlanParams struct {
+	PlanID     pgtype.UUID
+	TenantSlug string
+	At         pgtype.Timestamptz
+}
+
+// returns the assignments of a plan valid at the given time
+func (q *Queries) ListActiveAssignmentsByPlan(ctx context.Context, arg ListActiveAssignmentsByPlanParams) ([]PlanAssignment, error) {
+	rows, err := q.db.Query(ctx, listActiveAssignmentsByPlan, arg.PlanID, arg.TenantSlug, arg.At)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []PlanAssignment
+	for rows.Next() {
+		var i PlanAssignment
+		if err := rows.Scan(
+			&i.ID,
+			&i.PlanID,
+			&i.OrganizationID,
+			&i.UserID,
+			&i.ValidFrom,
+			&i.ValidUntil,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.CreatedBy,
+			&i.UpdatedBy,
+			&i.PlanVersionID,
+			&i.PendingPlanVersionID,
+			&i.Kind,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listActiveSubjectAssignments = `-- name: ListActiveSubjectAssignments :many
+SELECT pa.id, pa.plan_id, pa.organization_id, pa.user_id, pa.valid_from, pa.valid_until, pa.created_at, pa.updated_at, pa.created_by, pa.updated_by, pa.plan_version_id, pa.pending_plan_version_id, pa.kind, p.kind AS plan_kind
+FROM plan_assignment pa
+INNER JOIN plan p ON pa.plan_id = p.id
+WHERE p.tenant_slug = $1
+AND pa.organization_id IS NOT DISTINCT FROM $2
+AND pa.user_id IS NOT DISTINCT FROM $3
+AND pa.valid_from <= $4
+AND (pa.valid_until IS NULL OR pa.valid_until = '0001-01-01 00:00:00+00' OR pa.valid_until > $4)
+ORDER BY p.kind = 'base' DESC, CASE WHEN p.kind = 'base' THEN pa.valid_from END DESC, pa.valid_from, pa.created_at
+`
+
+type ListActiveSubjectAssignmentsParams struct {
+	TenantSlug     string
+	OrganizationID pgtype.Text
+	UserID         pgtype.Text
+	At             pgtype.Timestamptz
+}
+
+type ListActiveSubjectAssignmentsRow struct {
+	PlanAssignment PlanAssignment
+	PlanKind       PlanKindEnum
+}
+
+// returns the assignments of an organization or user valid at the given time: base plans first,
+// latest first, then add-ons in the order they were assigned; open-ended assignments store the
+// zero time as valid_until
+func (q *Queries) ListActiveSubjectAssignments(ctx context.Context, arg ListActiveSubjectAssignmentsParams) ([]ListActiveSubjectAssignmentsRow, error) {
+	rows, err := q.db.Query(ctx, listActiveSubjectAssignments,
+		arg.TenantSlug,
+		arg.OrganizationID,
+		arg.UserID,
+		arg.At,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListActiveSubjectAssignmentsRow
+	for rows.Next() {
+		var i ListActiveSubjectAssignmentsRow
+		if err := rows.Scan(
+			&i.PlanAssignment.ID,
+			&i.PlanAssignment.PlanID,
+			&i.PlanAssignment.OrganizationID,
+			&i.PlanAssignment.UserID,
+			&i.PlanAssignment.ValidFrom,
+			&i.PlanAssignment.ValidUntil,
+			&i.PlanAssignment.CreatedAt,
+			&i.PlanAssignment.UpdatedAt,
+			&i.PlanAssignment.CreatedBy,
+			&i.PlanAssignment.UpdatedBy,
+			&i.PlanAssignment.PlanVersionID,
+			&i.PlanAssignment.PendingPlanVersionID,
+			&i.PlanAssignment.Kind,
+			&i.PlanKind,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listAllAssignmentsPaginated = `-- name: ListAllAssignmentsPaginated :many
 SELECT
     pa.id,
@@ -169,7 +470,10 @@ SELECT
     pa.created_at,
     pa.updated_at,
     pa.created_by,
-    pa.updated_by
+    pa.updated_by,
+    pa.plan_version_id,
+    pa.pending_plan_version_id,
+    pa.kind
 FROM plan_assignment pa
 INNER JOIN plan p ON pa.plan_id = p.id
 WHERE p.tenant_slug = $1
@@ -205,6 +509,9 @@ func (q *Queries) ListAllAssignmentsPaginated(ctx context.Context, arg ListAllAs
 			&i.UpdatedAt,
 			&i.CreatedBy,
 			&i.UpdatedBy,
+			&i.PlanVersionID,
+			&i.PendingPlanVersionID,
+			&i.Kind,
 		); err != nil {
 			return nil, err
 		}
@@ -294,7 +601,7 @@ func (q *Queries) ListAssignmentsHistoryPaginated(ctx context.Context, arg ListA
 }
 
 const listAssignmentsPaginated = `-- name: ListAssignmentsPaginated :many
-SELECT id, plan_id, organization_id, user_id, valid_from, valid_until, created_at, updated_at, created_by, updated_by
+SELECT id, plan_id, organization_id, user_id, valid_from, valid_until, created_at, updated_at, created_by, updated_by, plan_version_id, pending_plan_version_id, kind
 FROM plan_assignment
 WHERE (
     (organization_id = $1 or $1 is null) and
@@ -349,6 +656,9 @@ func (q *Queries) ListAssignmentsPaginated(ctx context.Context, arg ListAssignme
 			&i.UpdatedAt,
 			&i.CreatedBy,
 			&i.UpdatedBy,
+			&i.PlanVersionID,
+			&i.PendingPlanVersionID,
+			&i.Kind,
 		); err != nil {
 			return nil, err
 		}
@@ -360,6 +670,95 @@ func (q *Queries) ListAssignmentsPaginated(ctx context.Context, arg ListAssignme
 	return items, nil
 }
 
+const migratePlanAssignments = `-- name: MigratePlanAssignments :execrows
+UPDATE plan_assignment pa
+SET plan_version_id = CASE WHEN $1::boolean THEN pa.plan_version_id ELSE $2::uuid END,
+    pending_plan_version_id = CASE WHEN $1::boolean THEN $2::uuid ELSE NULL END,
+    updated_by = $3,
+    updated_at = now()
+FROM plan p
+WHERE pa.plan_id = p.id
+AND p.id = $4
+AND p.tenant_slug = $5
+AND pa.plan_version_id IS DISTINCT FROM $2::uuid
+AND ($6::uuid IS NULL OR pa.plan_version_id = $6::uuid)
+AND (
+    (cardinality($7::text[]) = 0 AND cardinality($8::text[]) = 0)
+    OR pa.organization_id = ANY($7::text[])
+    OR pa.user_id = ANY($8::text[])
+)
+AND (pa.valid_until IS NULL OR pa.valid_until = '0001-01-01 00:00:00+00' OR pa.valid_until > $9)
+`
+
+type MigratePlanAssignmentsParams struct {
+	OnRenewal       bool
+	ToVersionID     pgtype.UUID
+	UpdatedBy       string
+	PlanID          pgtype.UUID
+	TenantSlug      string
+	FromVersionID   pgtype.UUID
+	OrganizationIds []string
+	UserIds         []string
+	At              pgtype.Timestamptz
+}
+
+// moves the current and future assignments of a plan to another version of it, either right away
+// or, with on_renewal, by marking the version their renewals are assigned
+func (q *Queries) MigratePlanAssignments(ctx context.Context, arg MigratePlanAssignmentsParams) (int64, error) {
+	result, err := q.db.Exec(ctx, migratePlanAssignments,
+		arg.OnRenewal,
+		arg.ToVersionID,
+		arg.UpdatedBy,
+		arg.PlanID,
+		arg.TenantSlug,
+		arg.FromVersionID,
+		arg.OrganizationIds,
+		arg.UserIds,
+		arg.At,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const setAssignmentValidUntil = `-- name: SetAssignmentValidUntil :one
+UPDATE plan_assignment
+SET valid_until = $1,
+    updated_by = $2,
+    updated_at = now()
+WHERE id = $3
+RETURNING id, plan_id, organization_id, user_id, valid_from, valid_until, created_at, updated_at, created_by, updated_by, plan_version_id, pending_plan_version_id, kind
+`
+
+type SetAssignmentValidUntilParams struct {
+	ValidUntil pgtype.Timestamptz
+	UpdatedBy  string
+	ID         pgtype.UUID
+}
+
+// moves the end of an assignment; the zero time makes it open-ended
+func (q *Queries) SetAssignmentValidUntil(ctx context.Context, arg SetAssignmentValidUntilParams) (PlanAssignment, error) {
+	row := q.db.QueryRow(ctx, setAssignmentValidUntil, arg.ValidUntil, arg.UpdatedBy, arg.ID)
+	var i PlanAssignment
+	err := row.Scan(
+		&i.ID,
+		&i.PlanID,
+		&i.OrganizationID,
+		&i.UserID,
+		&i.ValidFrom,
+		&i.ValidUntil,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.CreatedBy,
+		&i.UpdatedBy,
+		&i.PlanVersionID,
+		&i.PendingPlanVersionID,
+		&i.Kind,
+	)
+	return i, err
+}
+
 const terminateAssignedPlan = `-- name: TerminateAssignedPlan :exec
 delete from plan_assignment
 where plan_id = $1
@@ -391,7 +790,7 @@ and (
     (organization_id = $3 or $3 is null) and
     (user_id = $4 or $4 is null)
 )
-returning id, plan_id, organization_id, user_id, valid_from, valid_until, created_at, updated_at, created_by, updated_by
+returning id, plan_id, organization_id, user_id, valid_from, valid_until, created_at, updated_at, created_by, updated_by, plan_version_id, pending_plan_version_id, kind
 `
 
 type UpdateAssignedPlanParams struct {
@@ -425,6 +824,9 @@ func (q *Queries) UpdateAssignedPlan(ctx context.Context, arg UpdateAssignedPlan
 		&i.UpdatedAt,
 		&i.CreatedBy,
 		&i.UpdatedBy,
+		&i.PlanVersionID,
+		&i.PendingPlanVersionID,
+		&i.Kind,
 	)
 	return i, err
 }
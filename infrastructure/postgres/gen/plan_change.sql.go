@@ -0,0 +1,304 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: plan_change.sql
+
+package gen
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const cancelPlanChange = `-- name: CancelPlanChange :one
+update plan_change
+set canceled_at = $1,
+    canceled_by = $2,
+    updated_by = $2,
+    updated_at = now()
+where id = $3
+and tenant_slug = $4
+and canceled_at is null
+and effective_at > $1
+returning id, tenant_slug, type, organization_id, user_id, from_assignment_id, from_plan_id, to_assignment_id, to_plan_id, effective_at, previous_valid_until, reason, canceled_at, canceled_by, created_at, updated_at, created_by, updated_by
+`
+
+type CancelPlanChangeParams struct {
+	At         pgtype.Timestamptz
+	CanceledBy pgtype.Text
+	ID         pgtype.UUID
+	TenantSlug string
+}
+
+// cancels a plan change that has not taken effect yet
+func (q *Queries) CancelPlanChange(ctx context.Context, arg CancelPlanChangeParams) (PlanChange, error) {
+	row := q.db.QueryRow(ctx, cancelPlanChange,
+		arg.At,
+		arg.CanceledBy,
+		arg.ID,
+		arg.TenantSlug,
+	)
+	var i PlanChange
+	err := row.Scan(
+		&i.ID,
+		&i.TenantSlug,
+		&i.Type,
+		&i.OrganizationID,
+		&i.UserID,
+		&i.FromAssignmentID,
+		&i.FromPlanID,
+		&i.ToAssignmentID,
+		&i.ToPlanID,
+		&i.EffectiveAt,
+		&i.PreviousValidUntil,
+		&i.Reason,
+		&i.CanceledAt,
+		&i.CanceledBy,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.CreatedBy,
+		&i.UpdatedBy,
+	)
+	return i, err
+}
+
+const countPlanChanges = `-- name: CountPlanChanges :one
+select count(*)
+from plan_change pc
+where pc.tenant_slug = $1
+and (pc.organization_id = $2 or $2 is null)
+and (pc.user_id = $3 or $3 is null)
+and (
+  $4::text is null
+  or ($4::text = 'canceled' and pc.canceled_at is not null)
+  or ($4::text = 'pending' and pc.canceled_at is null and pc.effective_at > $5)
+  or ($4::text = 'applied' and pc.canceled_at is null and pc.effective_at <= $5)
+)
+`
+
+type CountPlanChangesParams struct {
+	TenantSlug     string
+	OrganizationID pgtype.Text
+	UserID         pgtype.Text
+	Status         pgtype.Text
+	At             pgtype.Timestamptz
+}
+
+func (q *Queries) CountPlanChanges(ctx context.Context, arg CountPlanChangesParams) (int64, error) {
+	row := q.db.QueryRow(ctx, countPlanChanges,
+		arg.TenantSlug,
+		arg.OrganizationID,
+		arg.UserID,
+		arg.Status,
+		arg.At,
+	)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const createPlanChange = `-- name: CreatePlanChange :one
+insert into plan_change (
+  tenant_slug,
+  type,
+  organization_id,
+  user_id,
+  from_assignment_id,
+  from_plan_id,
+  to_assignment_id,
+  to_plan_id,
+  effective_at,
+  previous_valid_until,
+  reason,
+  created_by,
+  updated_by
+) values (
+  $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13
+) returning id, tenant_slug, type, organization_id, user_id, from_assignment_id, from_plan_id, to_assignment_id, to_plan_id, effective_at, previous_valid_until, reason, canceled_at, canceled_by, created_at, updated_at, created_by, updated_by
+`
+
+type CreatePlanChangeParams struct {
+	TenantSlug         string
+	Type               PlanChangeTypeEnum
+	OrganizationID     pgtype.Text
+	UserID             pgtype.Text
+	FromAssignmentID   pgtype.UUID
+	FromPlanID         pgtype.UUID
+	ToAssignmentID     pgtype.UUID
+	ToPlanID           pgtype.UUID
+	EffectiveAt        pgtype.Timestamptz
+	PreviousValidUntil pgtype.Timestamptz
+	Reason             pgtype.Text
+	CreatedBy          string
+	UpdatedBy          string
+}
+
+func (q *Queries) CreatePlanChange(ctx context.Context, arg CreatePlanChangeParams) (PlanChange, error) {
+	row := q.db.QueryRow(ctx, createPlanChange,
+		arg.TenantSlug,
+		arg.Type,
+		arg.OrganizationID,
+		arg.UserID,
+		arg.FromAssignmentID,
+		arg.FromPlanID,
+		arg.ToAssignmentID,
+		arg.ToPlanID,
+		arg.EffectiveAt,
+		arg.PreviousValidUntil,
+		arg.Reason,
+		arg.CreatedBy,
+		arg.UpdatedBy,
+	)
+	var i PlanChange
+	err := row.Scan(
+		&i.ID,
+		&i.TenantSlug,
+		&i.Type,
+		&i.OrganizationID,
+		&i.UserID,
+		&i.FromAssignmentID,
+		&i.FromPlanID,
+		&i.ToAssignmentID,
+		&i.ToPlanID,
+		&i.EffectiveAt,
+		&i.PreviousValidUntil,
+		&i.Reason,
+		&i.CanceledAt,
+		&i.CanceledBy,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.CreatedBy,
+		&i.UpdatedBy,
+	)
+	return i, err
+}
+
+const getPlanChange = `-- name: GetPlanChange :one
+select pc.id, pc.tenant_slug, pc.type, pc.organization_id, pc.user_id, pc.from_assignment_id, pc.from_plan_id, pc.to_assignment_id, pc.to_plan_id, pc.effective_at, pc.previous_valid_until, pc.reason, pc.canceled_at, pc.canceled_by, pc.created_at, pc.updated_at, pc.created_by, pc.updated_by,
+  (case when pc.canceled_at is not null then 'canceled' when pc.effective_at > $1 then 'pending' else 'applied' end)::text as status
+from plan_change pc
+where pc.id = $2
+and pc.tenant_slug = $3
+`
+
+type GetPlanChangeParams struct {
+	At         pgtype.Timestamptz
+	ID         pgtype.UUID
+	TenantSlug string
+}
+
+type GetPlanChangeRow struct {
+	PlanChange PlanChange
+	Status     string
+}
+
+// returns a plan change with its status at the given time: canceled, pending until it takes
+// effect, applied afterwards
+func (q *Queries) GetPlanChange(ctx context.Context, arg GetPlanChangeParams) (GetPlanChangeRow, error) {
+	row := q.db.QueryRow(ctx, getPlanChange, arg.At, arg.ID, arg.TenantSlug)
+	var i GetPlanChangeRow
+	err := row.Scan(
+		&i.PlanChange.ID,
+		&i.PlanChange.TenantSlug,
+		&i.PlanChange.Type,
+		&i.PlanChange.OrganizationID,
+		&i.PlanChange.UserID,
+		&i.PlanChange.FromAssignmentID,
+		&i.PlanChange.FromPlanID,
+		&i.PlanChange.ToAssignmentID,
+		&i.PlanChange.ToPlanID,
+		&i.PlanChange.EffectiveAt,
+		&i.PlanChange.PreviousValidUntil,
+		&i.PlanChange.Reason,
+		&i.PlanChange.CanceledAt,
+		&i.PlanChange.CanceledBy,
+		&i.PlanChange.CreatedAt,
+		&i.PlanChange.UpdatedAt,
+		&i.PlanChange.CreatedBy,
+		&i.PlanChange.UpdatedBy,
+		&i.Status,
+	)
+	return i, err
+}
+
+const listPlanChangesPaginated = `-- name: ListPlanChangesPaginated :many
+select pc.id, pc.tenant_slug, pc.type, pc.organization_id, pc.user_id, pc.from_assignment_id, pc.from_plan_id, pc.to_assignment_id, pc.to_plan_id, pc.effective_at, pc.previous_valid_until, pc.reason, pc.canceled_at, pc.canceled_by, pc.created_at, pc.updated_at, pc.created_by, pc.updated_by,
+  (case when pc.canceled_at is not null then 'canceled' when pc.effective_at > $1 then 'pending' else 'applied' end)::text as status
+from plan_change pc
+where pc.tenant_slug = $2
+and (pc.organization_id = $3 or $3 is null)
+and (pc.user_id = $4 or $4 is null)
+and (
+  $5::text is null
+  or ($5::text = 'canceled' and pc.canceled_at is not null)
+  or ($5::text = 'pending' and pc.canceled_at is null and pc.effective_at > $1)
+  or ($5::text = 'applied' and pc.canceled_at is null and pc.effective_at <= $1)
+)
+order by pc.effective_at desc, pc.created_at desc
+limit $7
+offset $6
+`
+
+type ListPlanChangesPaginatedParams struct {
+	At             pgtype.Timestamptz
+	TenantSlug     string
+	OrganizationID pgtype.Text
+	UserID         pgtype.Text
+	Status         pgtype.Text
+	Offset         int32
+	Limit          int32
+}
+
+type ListPlanChangesPaginatedRow struct {
+	PlanChange PlanChange
+	Status     string
+}
+
+func (q *Queries) ListPlanChangesPaginated(ctx context.Context, arg ListPlanChangesPaginatedParams) ([]ListPlanChangesPaginatedRow, error) {
+	rows, err := q.db.Query(ctx, listPlanChangesPaginated,
+		arg.At,
+		arg.TenantSlug,
+		arg.OrganizationID,
+		arg.UserID,
+		arg.Status,
+		arg.Offset,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListPlanChangesPaginatedRow
+	for rows.Next() {
+		var i ListPlanChangesPaginatedRow
+		if err := rows.Scan(
+			&i.PlanChange.ID,
+			&i.PlanChange.TenantSlug,
+			&i.PlanChange.Type,
+			&i.PlanChange.OrganizationID,
+			&i.PlanChange.UserID,
+			&i.PlanChange.FromAssignmentID,
+			&i.PlanChange.FromPlanID,
+			&i.PlanChange.ToAssignmentID,
+			&i.PlanChange.ToPlanID,
+			&i.PlanChange.EffectiveAt,
+			&i.PlanChange.PreviousValidUntil,
+			&i.PlanChange.Reason,
+			&i.PlanChange.CanceledAt,
+			&i.PlanChange.CanceledBy,
+			&i.PlanChange.CreatedAt,
+			&i.PlanChange.UpdatedAt,
+			&i.PlanChange.CreatedBy,
+			&i.PlanChange.UpdatedBy,
+			&i.Status,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
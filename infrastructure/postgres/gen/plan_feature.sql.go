@@ -107,6 +107,7 @@ const deletePlanFeature = `-- name: DeletePlanFeature :exec
 delete from plan_feature
 where plan_id = $1
 and feature_id = $2
+and plan_version_id is null
 `
 
 type DeletePlanFeatureParams struct {
@@ -119,6 +120,74 @@ func (q *Queries) DeletePlanFeature(ctx context.Context, arg DeletePlanFeaturePa
 	return err
 }
 
+const getPlanFeatureByID = `-- name: GetPlanFeatureByID :one
+select
+    pf.id as plan_feature_id,
+    pf.plan_id,
+    pf.feature_id,
+    pf.config,
+    pf.created_at,
+    pf.updated_at,
+    pf.created_by,
+    pf.updated_by,
+    f.name as feature_name,
+    f.slug as feature_slug,
+    f.type as feature_type,
+    f.meter_slugs as feature_meter_slugs,
+    f.merge_rule as feature_merge_rule
+from
+    plan_feature pf
+join
+    feature f on pf.feature_id = f.id
+join
+    plan p on pf.plan_id = p.id
+where
+    pf.id = $1
+    and p.tenant_slug = $2
+`
+
+type GetPlanFeatureByIDParams struct {
+	ID         pgtype.UUID
+	TenantSlug string
+}
+
+type GetPlanFeatureByIDRow struct {
+	PlanFeatureID     pgtype.UUID
+	PlanID            pgtype.UUID
+	FeatureID         pgtype.UUID
+	Config            []byte
+	CreatedAt         pgtype.Timestamptz
+	UpdatedAt         pgtype.Timestamptz
+	CreatedBy         string
+	UpdatedBy         string
+	FeatureName       string
+	FeatureSlug       string
+	FeatureType       FeatureEnum
+	FeatureMeterSlugs []string
+	FeatureMergeRule  QuotaMergeRuleEnum
+}
+
+func (q *Queries) GetPlanFeatureByID(ctx context.Context, arg GetPlanFeatureByIDParams) (GetPlanFeatureByIDRow, error) {
+	row := q.db.QueryRow(ctx, getPlanFeatureByID, arg.ID, arg.TenantSlug)
+	var i GetPlanFeatureByIDRow
+	err := row.Scan(
+		&i.PlanFeatureID,
+		&i.PlanID,
+		&i.FeatureID,
+		&i.Config,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.CreatedBy,
+		&i.UpdatedBy,
+		&i.FeatureName,
+		&i.FeatureSlug,
+		&i.FeatureType,
+		&i.FeatureMeterSlugs,
+		&i.FeatureMergeRule,
+	)
+	return i, err
+}
+
 const getPlanFeatureIDByPlanAndFeature = `-- name: GetPlanFeatureIDByPlanAndFeature :one
 select
     pf.id as plan_feature_id
@@ -131,6 +200,7 @@ join
 where
     p.id = $2::uuid
     and f.id = $3::uuid
+    and pf.plan_version_id is null
     and p.tenant_slug = $1
 `
 
@@ -161,7 +231,9 @@ select
     f.slug as feature_slug,
     f.description as feature_description,
     f.type as feature_type,
-    f.config as feature_config
+    f.config as feature_config,
+    f.meter_slugs as feature_meter_slugs,
+    f.merge_rule as feature_merge_rule
 from 
     plan_feature pf
 join
@@ -170,6 +242,7 @@ join
     plan p on pf.plan_id = p.id
 where
     pf.plan_id = $1
+    and pf.plan_version_id is null
     and p.tenant_slug = $2
     and ($3::feature_enum is null or f.type = $3::feature_enum)
 order by
@@ -196,6 +269,8 @@ type ListPlanFeaturesByPlanRow struct {
 	FeatureDescription pgtype.Text
 	FeatureType        FeatureEnum
 	FeatureConfig      []byte
+	FeatureMeterSlugs  []string
+	FeatureMergeRule   QuotaMergeRuleEnum
 }
 
 func (q *Queries) ListPlanFeaturesByPlan(ctx context.Context, arg ListPlanFeaturesByPlanParams) ([]ListPlanFeaturesByPlanRow, error) {
@@ -221,6 +296,99 @@ func (q *Queries) ListPlanFeaturesByPlan(ctx context.Context, arg ListPlanFeatur
 			&i.FeatureDescription,
 			&i.FeatureType,
 			&i.FeatureConfig,
+			&i.FeatureMeterSlugs,
+			&i.FeatureMergeRule,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPlanFeaturesByVersion = `-- name: ListPlanFeaturesByVersion :many
+select
+    pf.id as plan_feature_id,
+    pf.plan_id,
+    pf.feature_id,
+    pf.config,
+    pf.created_at,
+    pf.updated_at,
+    pf.created_by,
+    pf.updated_by,
+    f.name as feature_name,
+    f.slug as feature_slug,
+    f.description as feature_description,
+    f.type as feature_type,
+    f.config as feature_config,
+    f.meter_slugs as feature_meter_slugs,
+    f.merge_rule as feature_merge_rule
+from
+    plan_feature pf
+join
+    feature f on pf.feature_id = f.id
+join
+    plan p on pf.plan_id = p.id
+where
+    pf.plan_version_id = $1
+    and p.tenant_slug = $2
+    and ($3::feature_enum is null or f.type = $3::feature_enum)
+order by
+    pf.created_at desc
+`
+
+type ListPlanFeaturesByVersionParams struct {
+	PlanVersionID pgtype.UUID
+	TenantSlug    string
+	FeatureType   NullFeatureEnum
+}
+
+type ListPlanFeaturesByVersionRow struct {
+	PlanFeatureID      pgtype.UUID
+	PlanID             pgtype.UUID
+	FeatureID          pgtype.UUID
+	Config             []byte
+	CreatedAt          pgtype.Timestamptz
+	UpdatedAt          pgtype.Timestamptz
+	CreatedBy          string
+	UpdatedBy          string
+	FeatureName        string
+	FeatureSlug        string
+	FeatureDescription pgtype.Text
+	FeatureType        FeatureEnum
+	FeatureConfig      []byte
+	FeatureMeterSlugs  []string
+	FeatureMergeRule   QuotaMergeRuleEnum
+}
+
+func (q *Queries) ListPlanFeaturesByVersion(ctx context.Context, arg ListPlanFeaturesByVersionParams) ([]ListPlanFeaturesByVersionRow, error) {
+	rows, err := q.db.Query(ctx, listPlanFeaturesByVersion, arg.PlanVersionID, arg.TenantSlug, arg.FeatureType)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListPlanFeaturesByVersionRow
+	for rows.Next() {
+		var i ListPlanFeaturesByVersionRow
+		if err := rows.Scan(
+			&i.PlanFeatureID,
+			&i.PlanID,
+			&i.FeatureID,
+			&i.Config,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.CreatedBy,
+			&i.UpdatedBy,
+			&i.FeatureName,
+			&i.FeatureSlug,
+			&i.FeatureDescription,
+			&i.FeatureType,
+			&i.FeatureConfig,
+			&i.FeatureMeterSlugs,
+			&i.FeatureMergeRule,
 		); err != nil {
 			return nil, err
 		}
@@ -241,6 +409,7 @@ set
 where
     plan_id = $3      
     and feature_id = $4 
+    and plan_version_id is null
 returning
     id AS plan_feature_id,
     plan_id,
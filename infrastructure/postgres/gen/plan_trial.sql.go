@@ -0,0 +1,391 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: plan_trial.sql
+
+package gen
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const countPlanTrials = `-- name: CountPlanTrials :one
+select count(*)
+from plan_trial t
+join plan_assignment pa on t.assignment_id = pa.id
+where t.tenant_slug = $1
+and (pa.organization_id = $2 or $2 is null)
+and (pa.user_id = $3 or $3 is null)
+and (t.status = $4 or $4 is null)
+`
+
+type CountPlanTrialsParams struct {
+	TenantSlug     string
+	OrganizationID pgtype.Text
+	UserID         pgtype.Text
+	Status         NullTrialStatusEnum
+}
+
+func (q *Queries) CountPlanTrials(ctx context.Context, arg CountPlanTrialsParams) (int64, error) {
+	row := q.db.QueryRow(ctx, countPlanTrials,
+		arg.TenantSlug,
+		arg.OrganizationID,
+		arg.UserID,
+		arg.Status,
+	)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const createPlanTrial = `-- name: CreatePlanTrial :one
+insert into plan_trial (
+  tenant_slug,
+  assignment_id,
+  target_plan_id,
+  fallback_plan_id,
+  end_action,
+  created_by,
+  updated_by
+) values (
+  $1, $2, $3, $4, $5, $6, $7
+) returning id, tenant_slug, assignment_id, target_plan_id, fallback_plan_id, end_action, status, resulting_assignment_id, ended_at, created_at, updated_at, created_by, updated_by
+`
+
+type CreatePlanTrialParams struct {
+	TenantSlug     string
+	AssignmentID   pgtype.UUID
+	TargetPlanID   pgtype.UUID
+	FallbackPlanID pgtype.UUID
+	EndAction      TrialEndActionEnum
+	CreatedBy      string
+	UpdatedBy      string
+}
+
+func (q *Queries) CreatePlanTrial(ctx context.Context, arg CreatePlanTrialParams) (PlanTrial, error) {
+	row := q.db.QueryRow(ctx, createPlanTrial,
+		arg.TenantSlug,
+		arg.AssignmentID,
+		arg.TargetPlanID,
+		arg.FallbackPlanID,
+		arg.EndAction,
+		arg.CreatedBy,
+		arg.UpdatedBy,
+	)
+	var i PlanTrial
+	err := row.Scan(
+		&i.ID,
+		&i.TenantSlug,
+		&i.AssignmentID,
+		&i.TargetPlanID,
+		&i.FallbackPlanID,
+		&i.EndAction,
+		&i.Status,
+		&i.ResultingAssignmentID,
+		&i.EndedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.CreatedBy,
+		&i.UpdatedBy,
+	)
+	return i, err
+}
+
+const endPlanTrial = `-- name: EndPlanTrial :one
+update plan_trial
+set status = $1,
+    resulting_assignment_id = $2,
+    ended_at = $3,
+    updated_by = $4,
+    updated_at = now()
+where id = $5
+and status = 'active'
+returning id, tenant_slug, assignment_id, target_plan_id, fallback_plan_id, end_action, status, resulting_assignment_id, ended_at, created_at, updated_at, created_by, updated_by
+`
+
+type EndPlanTrialParams struct {
+	Status                TrialStatusEnum
+	ResultingAssignmentID pgtype.UUID
+	EndedAt               pgtype.Timestamptz
+	UpdatedBy             string
+	ID                    pgtype.UUID
+}
+
+// records the outcome of an active trial
+func (q *Queries) EndPlanTrial(ctx context.Context, arg EndPlanTrialParams) (PlanTrial, error) {
+	row := q.db.QueryRow(ctx, endPlanTrial,
+		arg.Status,
+		arg.ResultingAssignmentID,
+		arg.EndedAt,
+		arg.UpdatedBy,
+		arg.ID,
+	)
+	var i PlanTrial
+	err := row.Scan(
+		&i.ID,
+		&i.TenantSlug,
+		&i.AssignmentID,
+		&i.TargetPlanID,
+		&i.FallbackPlanID,
+		&i.EndAction,
+		&i.Status,
+		&i.ResultingAssignmentID,
+		&i.EndedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.CreatedBy,
+		&i.UpdatedBy,
+	)
+	return i, err
+}
+
+const extendPlanTrial = `-- name: ExtendPlanTrial :one
+update plan_assignment pa
+set valid_until = $1,
+    updated_by = $2,
+    updated_at = now()
+from plan_trial t
+where t.assignment_id = pa.id
+and t.id = $3
+and t.tenant_slug = $4
+and t.status = 'active'
+returning pa.id, pa.plan_id, pa.organization_id, pa.user_id, pa.valid_from, pa.valid_until, pa.created_at, pa.updated_at, pa.created_by, pa.updated_by, pa.plan_version_id, pa.pending_plan_version_id, pa.kind
+`
+
+type ExtendPlanTrialParams struct {
+	ValidUntil pgtype.Timestamptz
+	UpdatedBy  string
+	ID         pgtype.UUID
+	TenantSlug string
+}
+
+// moves the end of the assignment of an active trial
+func (q *Queries) ExtendPlanTrial(ctx context.Context, arg ExtendPlanTrialParams) (PlanAssignment, error) {
+	row := q.db.QueryRow(ctx, extendPlanTrial,
+		arg.ValidUntil,
+		arg.UpdatedBy,
+		arg.ID,
+		arg.TenantSlug,
+	)
+	var i PlanAssignment
+	err := row.Scan(
+		&i.ID,
+		&i.PlanID,
+		&i.OrganizationID,
+		&i.UserID,
+		&i.ValidFrom,
+		&i.ValidUntil,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.CreatedBy,
+		&i.UpdatedBy,
+		&i.PlanVersionID,
+		&i.PendingPlanVersionID,
+		&i.Kind,
+	)
+	return i, err
+}
+
+const getPlanTrial = `-- name: GetPlanTrial :one
+select t.id, t.tenant_slug, t.assignment_id, t.target_plan_id, t.fallback_plan_id, t.end_action, t.status, t.resulting_assignment_id, t.ended_at, t.created_at, t.updated_at, t.created_by, t.updated_by, pa.id, pa.plan_id, pa.organization_id, pa.user_id, pa.valid_from, pa.valid_until, pa.created_at, pa.updated_at, pa.created_by, pa.updated_by, pa.plan_version_id, pa.pending_plan_version_id, pa.kind
+from plan_trial t
+join plan_assignment pa on t.assignment_id = pa.id
+where t.id = $1
+and t.tenant_slug = $2
+`
+
+type GetPlanTrialParams struct {
+	ID         pgtype.UUID
+	TenantSlug string
+}
+
+type GetPlanTrialRow struct {
+	PlanTrial      PlanTrial
+	PlanAssignment PlanAssignment
+}
+
+func (q *Queries) GetPlanTrial(ctx context.Context, arg GetPlanTrialParams) (GetPlanTrialRow, error) {
+	row := q.db.QueryRow(ctx, getPlanTrial, arg.ID, arg.TenantSlug)
+	var i GetPlanTrialRow
+	err := row.Scan(
+		&i.PlanTrial.ID,
+		&i.PlanTrial.TenantSlug,
+		&i.PlanTrial.AssignmentID,
+		&i.PlanTrial.TargetPlanID,
+		&i.PlanTrial.FallbackPlanID,
+		&i.PlanTrial.EndAction,
+		&i.PlanTrial.Status,
+		&i.PlanTrial.ResultingAssignmentID,
+		&i.PlanTrial.EndedAt,
+		&i.PlanTrial.CreatedAt,
+		&i.PlanTrial.UpdatedAt,
+		&i.PlanTrial.CreatedBy,
+		&i.PlanTrial.UpdatedBy,
+		&i.PlanAssignment.ID,
+		&i.PlanAssignment.PlanID,
+		&i.PlanAssignment.OrganizationID,
+		&i.PlanAssignment.UserID,
+		&i.PlanAssignment.ValidFrom,
+		&i.PlanAssignment.ValidUntil,
+		&i.PlanAssignment.CreatedAt,
+		&i.PlanAssignment.UpdatedAt,
+		&i.PlanAssignment.CreatedBy,
+		&i.PlanAssignment.UpdatedBy,
+		&i.PlanAssignment.PlanVersionID,
+		&i.PlanAssignment.PendingPlanVersionID,
+		&i.PlanAssignment.Kind,
+	)
+	return i, err
+}
+
+const listDueTrials = `-- name: ListDueTrials :many
+select t.id, t.tenant_slug, t.assignment_id, t.target_plan_id, t.fallback_plan_id, t.end_action, t.status, t.resulting_assignment_id, t.ended_at, t.created_at, t.updated_at, t.created_by, t.updated_by, pa.id, pa.plan_id, pa.organization_id, pa.user_id, pa.valid_from, pa.valid_until, pa.created_at, pa.updated_at, pa.created_by, pa.updated_by, pa.plan_version_id, pa.pending_plan_version_id, pa.kind
+from plan_trial t
+join plan_assignment pa on t.assignment_id = pa.id
+where t.status = 'active'
+and pa.valid_until <= $1
+order by pa.valid_until
+limit $2
+`
+
+type ListDueTrialsParams struct {
+	At    pgtype.Timestamptz
+	Limit int32
+}
+
+type ListDueTrialsRow struct {
+	PlanTrial      PlanTrial
+	PlanAssignment PlanAssignment
+}
+
+// returns the active trials of every tenant that ended by the given time, oldest first
+func (q *Queries) ListDueTrials(ctx context.Context, arg ListDueTrialsParams) ([]ListDueTrialsRow, error) {
+	rows, err := q.db.Query(ctx, listDueTrials, arg.At, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListDueTrialsRow
+	for rows.Next() {
+		var i ListDueTrialsRow
+		if err := rows.Scan(
+			&i.PlanTrial.ID,
+			&i.PlanTrial.TenantSlug,
+			&i.PlanTrial.AssignmentID,
+			&i.PlanTrial.TargetPlanID,
+			&i.PlanTrial.FallbackPlanID,
+			&i.PlanTrial.EndAction,
+			&i.PlanTrial.Status,
+			&i.PlanTrial.ResultingAssignmentID,
+			&i.PlanTrial.EndedAt,
+			&i.PlanTrial.CreatedAt,
+			&i.PlanTrial.UpdatedAt,
+			&i.PlanTrial.CreatedBy,
+			&i.PlanTrial.UpdatedBy,
+			&i.PlanAssignment.ID,
+			&i.PlanAssignment.PlanID,
+			&i.PlanAssignment.OrganizationID,
+			&i.PlanAssignment.UserID,
+			&i.PlanAssignment.ValidFrom,
+			&i.PlanAssignment.ValidUntil,
+			&i.PlanAssignment.CreatedAt,
+			&i.PlanAssignment.UpdatedAt,
+			&i.PlanAssignment.CreatedBy,
+			&i.PlanAssignment.UpdatedBy,
+			&i.PlanAssignment.PlanVersionID,
+			&i.PlanAssignment.PendingPlanVersionID,
+			&i.PlanAssignment.Kind,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPlanTrialsPaginated = `-- name: ListPlanTrialsPaginated :many
+select t.id, t.tenant_slug, t.assignment_id, t.target_plan_id, t.fallback_plan_id, t.end_action, t.status, t.resulting_assignment_id, t.ended_at, t.created_at, t.updated_at, t.created_by, t.updated_by, pa.id, pa.plan_id, pa.organization_id, pa.user_id, pa.valid_from, pa.valid_until, pa.created_at, pa.updated_at, pa.created_by, pa.updated_by, pa.plan_version_id, pa.pending_plan_version_id, pa.kind
+from plan_trial t
+join plan_assignment pa on t.assignment_id = pa.id
+where t.tenant_slug = $1
+and (pa.organization_id = $2 or $2 is null)
+and (pa.user_id = $3 or $3 is null)
+and (t.status = $4 or $4 is null)
+order by pa.valid_until, t.created_at
+limit $6
+offset $5
+`
+
+type ListPlanTrialsPaginatedParams struct {
+	TenantSlug     string
+	OrganizationID pgtype.Text
+	UserID         pgtype.Text
+	Status         NullTrialStatusEnum
+	Offset         int32
+	Limit          int32
+}
+
+type ListPlanTrialsPaginatedRow struct {
+	PlanTrial      PlanTrial
+	PlanAssignment PlanAssignment
+}
+
+// lists trials ending soonest first
+func (q *Queries) ListPlanTrialsPaginated(ctx context.Context, arg ListPlanTrialsPaginatedParams) ([]ListPlanTrialsPaginatedRow, error) {
+	rows, err := q.db.Query(ctx, listPlanTrialsPaginated,
+		arg.TenantSlug,
+		arg.OrganizationID,
+		arg.UserID,
+		arg.Status,
+		arg.Offset,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListPlanTrialsPaginatedRow
+	for rows.Next() {
+		var i ListPlanTrialsPaginatedRow
+		if err := rows.Scan(
+			&i.PlanTrial.ID,
+			&i.PlanTrial.TenantSlug,
+			&i.PlanTrial.AssignmentID,
+			&i.PlanTrial.TargetPlanID,
+			&i.PlanTrial.FallbackPlanID,
+			&i.PlanTrial.EndAction,
+			&i.PlanTrial.Status,
+			&i.PlanTrial.ResultingAssignmentID,
+			&i.PlanTrial.EndedAt,
+			&i.PlanTrial.CreatedAt,
+			&i.PlanTrial.UpdatedAt,
+			&i.PlanTrial.CreatedBy,
+			&i.PlanTrial.UpdatedBy,
+			&i.PlanAssignment.ID,
+			&i.PlanAssignment.PlanID,
+			&i.PlanAssignment.OrganizationID,
+			&i.PlanAssignment.UserID,
+			&i.PlanAssignment.ValidFrom,
+			&i.PlanAssignment.ValidUntil,
+			&i.PlanAssignment.CreatedAt,
+			&i.PlanAssignment.UpdatedAt,
+			&i.PlanAssignment.CreatedBy,
+			&i.PlanAssignment.UpdatedBy,
+			&i.PlanAssignment.PlanVersionID,
+			&i.PlanAssignment.PendingPlanVersionID,
+			&i.PlanAssignment.Kind,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -0,0 +1,235 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: plan_version.sql
+
+package gen
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const copyDraftPlanFeatureQuotasToVersion = `-- name: CopyDraftPlanFeatureQuotasToVersion :exec
+insert into plan_feature_quota (
+    plan_feature_id,
+    limit_value,
+    reset_period,
+    custom_period_minutes,
+    action_at_limit,
+    soft_limit_value,
+    overage_allowance,
+    overage_allowance_type,
+    grace_period_minutes
+)
+select
+    v.id,
+    q.limit_value,
+    q.reset_period,
+    q.custom_period_minutes,
+    q.action_at_limit,
+    q.soft_limit_value,
+    q.overage_allowance,
+    q.overage_allowance_type,
+    q.grace_period_minutes
+from plan_feature v
+join plan_feature d on d.plan_id = v.plan_id and d.feature_id = v.feature_id and d.plan_version_id is null
+join plan_feature_quota q on q.plan_feature_id = d.id
+where v.plan_version_id = $1
+`
+
+// snapshots the quotas of the draft features of a plan into the given version
+func (q *Queries) CopyDraftPlanFeatureQuotasToVersion(ctx context.Context, planVersionID pgtype.UUID) error {
+	_, err := q.db.Exec(ctx, copyDraftPlanFeatureQuotasToVersion, planVersionID)
+	return err
+}
+
+const copyDraftPlanFeaturesToVersion = `-- name: CopyDraftPlanFeaturesToVersion :exec
+insert into plan_feature (
+    plan_id,
+    feature_id,
+    config,
+    created_by,
+    updated_by,
+    plan_version_id
+)
+select
+    plan_id,
+    feature_id,
+    config,
+    created_by,
+    updated_by,
+    $1::uuid
+from plan_feature
+where plan_id = $2::uuid
+and plan_version_id is null
+`
+
+type CopyDraftPlanFeaturesToVersionParams struct {
+	PlanVersionID pgtype.UUID
+	PlanID        pgtype.UUID
+}
+
+// snapshots the draft features of a plan into the given version
+func (q *Queries) CopyDraftPlanFeaturesToVersion(ctx context.Context, arg CopyDraftPlanFeaturesToVersionParams) error {
+	_, err := q.db.Exec(ctx, copyDraftPlanFeaturesToVersion, arg.PlanVersionID, arg.PlanID)
+	return err
+}
+
+const createPlanVersion = `-- name: CreatePlanVersion :one
+insert into plan_version (
+    plan_id,
+    version,
+    description,
+    created_by
+)
+select
+    $1::uuid,
+    coalesce(max(version), 0) + 1,
+    $2,
+    $3
+from plan_version
+where plan_id = $1::uuid
+returning id, plan_id, version, description, created_at, created_by
+`
+
+type CreatePlanVersionParams struct {
+	PlanID      pgtype.UUID
+	Description pgtype.Text
+	CreatedBy   string
+}
+
+func (q *Queries) CreatePlanVersion(ctx context.Context, arg CreatePlanVersionParams) (PlanVersion, error) {
+	row := q.db.QueryRow(ctx, createPlanVersion, arg.PlanID, arg.Description, arg.CreatedBy)
+	var i PlanVersion
+	err := row.Scan(
+		&i.ID,
+		&i.PlanID,
+		&i.Version,
+		&i.Description,
+		&i.CreatedAt,
+		&i.CreatedBy,
+	)
+	return i, err
+}
+
+const getLatestPlanVersion = `-- name: GetLatestPlanVersion :one
+select pv.id, pv.plan_id, pv.version, pv.description, pv.created_at, pv.created_by
+from plan_version pv
+join plan p on pv.plan_id = p.id
+where pv.plan_id = $1
+and p.tenant_slug = $2
+order by pv.version desc
+limit 1
+`
+
+type GetLatestPlanVersionParams struct {
+	PlanID     pgtype.UUID
+	TenantSlug string
+}
+
+func (q *Queries) GetLatestPlanVersion(ctx context.Context, arg GetLatestPlanVersionParams) (PlanVersion, error) {
+	row := q.db.QueryRow(ctx, getLatestPlanVersion, arg.PlanID, arg.TenantSlug)
+	var i PlanVersion
+	err := row.Scan(
+		&i.ID,
+		&i.PlanID,
+		&i.Version,
+		&i.Description,
+		&i.CreatedAt,
+		&i.CreatedBy,
+	)
+	return i, err
+}
+
+const getPlanVersion = `-- name: GetPlanVersion :one
+select pv.id, pv.plan_id, pv.version, pv.description, pv.created_at, pv.created_by
+from plan_version pv
+join plan p on pv.plan_id = p.id
+where pv.plan_id = $1
+and pv.version = $2
+and p.tenant_slug = $3
+`
+
+type GetPlanVersionParams struct {
+	PlanID     pgtype.UUID
+	Version    int32
+	TenantSlug string
+}
+
+func (q *Queries) GetPlanVersion(ctx context.Context, arg GetPlanVersionParams) (PlanVersion, error) {
+	row := q.db.QueryRow(ctx, getPlanVersion, arg.PlanID, arg.Version, arg.TenantSlug)
+	var i PlanVersion
+	err := row.Scan(
+		&i.ID,
+		&i.PlanID,
+		&i.Version,
+		&i.Description,
+		&i.CreatedAt,
+		&i.CreatedBy,
+	)
+	return i, err
+}
+
+const listPlanVersions = `-- name: ListPlanVersions :many
+select pv.id, pv.plan_id, pv.version, pv.description, pv.created_at, pv.created_by
+from plan_version pv
+join plan p on pv.plan_id = p.id
+where pv.plan_id = $1
+and p.tenant_slug = $2
+order by pv.version desc
+`
+
+type ListPlanVersionsParams struct {
+	PlanID     pgtype.UUID
+	TenantSlug string
+}
+
+func (q *Queries) ListPlanVersions(ctx context.Context, arg ListPlanVersionsParams) ([]PlanVersion, error) {
+	rows, err := q.db.Query(ctx, listPlanVersions, arg.PlanID, arg.TenantSlug)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []PlanVersion
+	for rows.Next() {
+		var i PlanVersion
+		if err := rows.Scan(
+			&i.ID,
+			&i.PlanID,
+			&i.Version,
+			&i.Description,
+			&i.CreatedAt,
+			&i.CreatedBy,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const lockPlanForVersioning = `-- name: LockPlanForVersioning :one
+select id
+from plan
+where id = $1
+and tenant_slug = $2
+for update
+`
+
+type LockPlanForVersioningParams struct {
+	ID         pgtype.UUID
+	TenantSlug string
+}
+
+// locks the plan so concurrent publishes number their versions one after another
+func (q *Queries) LockPlanForVersioning(ctx context.Context, arg LockPlanForVersioningParams) (pgtype.UUID, error) {
+	row := q.db.QueryRow(ctx, lockPlanForVersioning, arg.ID, arg.TenantSlug)
+	var id pgtype.UUID
+	err := row.Scan(&id)
+	return id, err
+}
@@ -15,20 +15,54 @@ type Querier interface {
 	ArchivePlanBySlug(ctx context.Context, arg ArchivePlanBySlugParams) (Plan, error)
 	// assigns a plan to either an organization or a user based on which id is provided
 	AssignPlan(ctx context.Context, arg AssignPlanParams) (PlanAssignment, error)
+	// assigns a plan as a trial to either an organization or a user
+	AssignTrialPlan(ctx context.Context, arg AssignTrialPlanParams) (PlanAssignment, error)
+	// cancels a plan change that has not taken effect yet
+	CancelPlanChange(ctx context.Context, arg CancelPlanChangeParams) (PlanChange, error)
 	CheckMeteredFeature(ctx context.Context, id pgtype.UUID) (bool, error)
 	CheckPlanAndFeatureForTenant(ctx context.Context, arg CheckPlanAndFeatureForTenantParams) (bool, error)
+	ClaimDueWebhookDeliveries(ctx context.Context, arg ClaimDueWebhookDeliveriesParams) ([]WebhookDelivery, error)
+	// copies the quotas of the draft features of a plan onto the cloned features, replacing the limits
+	// overridden by feature slug
+	ClonePlanFeatureQuotas(ctx context.Context, arg ClonePlanFeatureQuotasParams) error
+	// copies the draft features of a plan onto another plan
+	ClonePlanFeatures(ctx context.Context, arg ClonePlanFeaturesParams) error
+	// snapshots the quotas of the draft features of a plan into the given version
+	CopyDraftPlanFeatureQuotasToVersion(ctx context.Context, planVersionID pgtype.UUID) error
+	// snapshots the draft features of a plan into the given version
+	CopyDraftPlanFeaturesToVersion(ctx context.Context, arg CopyDraftPlanFeaturesToVersionParams) error
+	CountAlertRules(ctx context.Context, tenantSlug string) (int64, error)
 	CountAllAssignments(ctx context.Context, tenantSlug string) (int64, error)
 	CountAssignments(ctx context.Context, arg CountAssignmentsParams) (int64, error)
 	CountAssignmentsHistory(ctx context.Context, arg CountAssignmentsHistoryParams) (int64, error)
+	CountEntitlementOverrides(ctx context.Context, arg CountEntitlementOverridesParams) (int64, error)
 	CountFeatures(ctx context.Context, arg CountFeaturesParams) (int64, error)
 	CountMeters(ctx context.Context, tenantSlug string) (int64, error)
 	CountMetersByEventType(ctx context.Context, arg CountMetersByEventTypeParams) (int64, error)
+	// counts the base plan assignments of an organization or user overlapping the given period;
+	// a null or zero valid_until leaves the period open-ended
+	CountOverlappingBaseAssignments(ctx context.Context, arg CountOverlappingBaseAssignmentsParams) (int64, error)
+	CountPlanChanges(ctx context.Context, arg CountPlanChangesParams) (int64, error)
+	CountPlanTrials(ctx context.Context, arg CountPlanTrialsParams) (int64, error)
 	CountPlans(ctx context.Context, arg CountPlansParams) (int64, error)
+	CountWebhookDeliveries(ctx context.Context, arg CountWebhookDeliveriesParams) (int64, error)
+	CountWebhookEndpoints(ctx context.Context, tenantSlug string) (int64, error)
+	CreateAlertRule(ctx context.Context, arg CreateAlertRuleParams) (AlertRule, error)
+	CreateEntitlementOverride(ctx context.Context, arg CreateEntitlementOverrideParams) (EntitlementOverride, error)
 	CreateFeature(ctx context.Context, arg CreateFeatureParams) (Feature, error)
 	CreateMeter(ctx context.Context, arg CreateMeterParams) (Meter, error)
 	CreatePlan(ctx context.Context, arg CreatePlanParams) (Plan, error)
+	CreatePlanChange(ctx context.Context, arg CreatePlanChangeParams) (PlanChange, error)
 	CreatePlanFeature(ctx context.Context, arg CreatePlanFeatureParams) (CreatePlanFeatureRow, error)
 	CreatePlanFeatureQuota(ctx context.Context, arg CreatePlanFeatureQuotaParams) (PlanFeatureQuotum, error)
+	CreatePlanTrial(ctx context.Context, arg CreatePlanTrialParams) (PlanTrial, error)
+	CreatePlanVersion(ctx context.Context, arg CreatePlanVersionParams) (PlanVersion, error)
+	CreateWebhookDelivery(ctx context.Context, arg CreateWebhookDeliveryParams) (WebhookDelivery, error)
+	CreateWebhookDeliveryAttempt(ctx context.Context, arg CreateWebhookDeliveryAttemptParams) (WebhookDeliveryAttempt, error)
+	CreateWebhookEndpoint(ctx context.Context, arg CreateWebhookEndpointParams) (WebhookEndpoint, error)
+	DeleteAlertRule(ctx context.Context, arg DeleteAlertRuleParams) error
+	DeleteAssignmentByID(ctx context.Context, id pgtype.UUID) error
+	DeleteEntitlementOverride(ctx context.Context, arg DeleteEntitlementOverrideParams) error
 	DeleteFeatureByID(ctx context.Context, arg DeleteFeatureByIDParams) error
 	DeleteFeatureBySlug(ctx context.Context, arg DeleteFeatureBySlugParams) error
 	DeleteMeterByID(ctx context.Context, arg DeleteMeterByIDParams) error
@@ -37,38 +71,101 @@ type Querier interface {
 	DeletePlanBySlug(ctx context.Context, arg DeletePlanBySlugParams) error
 	DeletePlanFeature(ctx context.Context, arg DeletePlanFeatureParams) error
 	DeletePlanFeatureQuota(ctx context.Context, planFeatureID pgtype.UUID) error
+	DeleteWebhookEndpoint(ctx context.Context, arg DeleteWebhookEndpointParams) error
+	// records the outcome of an active trial
+	EndPlanTrial(ctx context.Context, arg EndPlanTrialParams) (PlanTrial, error)
+	// moves the end of the assignment of an active trial
+	ExtendPlanTrial(ctx context.Context, arg ExtendPlanTrialParams) (PlanAssignment, error)
+	GetAlertRuleByID(ctx context.Context, arg GetAlertRuleByIDParams) (AlertRule, error)
+	GetAssignmentByID(ctx context.Context, arg GetAssignmentByIDParams) (PlanAssignment, error)
+	GetEntitlementOverride(ctx context.Context, arg GetEntitlementOverrideParams) (GetEntitlementOverrideRow, error)
 	GetFeatureByID(ctx context.Context, arg GetFeatureByIDParams) (Feature, error)
 	GetFeatureBySlug(ctx context.Context, arg GetFeatureBySlugParams) (Feature, error)
+	GetLatestPlanVersion(ctx context.Context, arg GetLatestPlanVersionParams) (PlanVersion, error)
 	GetMeterByID(ctx context.Context, arg GetMeterByIDParams) (Meter, error)
 	GetMeterBySlug(ctx context.Context, arg GetMeterBySlugParams) (Meter, error)
 	GetPlanByID(ctx context.Context, arg GetPlanByIDParams) (Plan, error)
 	GetPlanBySlug(ctx context.Context, arg GetPlanBySlugParams) (Plan, error)
+	// returns a plan change with its status at the given time: canceled, pending until it takes
+	// effect, applied afterwards
+	GetPlanChange(ctx context.Context, arg GetPlanChangeParams) (GetPlanChangeRow, error)
+	GetPlanFeatureByID(ctx context.Context, arg GetPlanFeatureByIDParams) (GetPlanFeatureByIDRow, error)
 	GetPlanFeatureIDByPlanAndFeature(ctx context.Context, arg GetPlanFeatureIDByPlanAndFeatureParams) (pgtype.UUID, error)
 	GetPlanFeatureQuotaByPlanFeatureID(ctx context.Context, planFeatureID pgtype.UUID) (PlanFeatureQuotum, error)
+	GetPlanTrial(ctx context.Context, arg GetPlanTrialParams) (GetPlanTrialRow, error)
+	GetPlanVersion(ctx context.Context, arg GetPlanVersionParams) (PlanVersion, error)
 	GetPropertiesByEventType(ctx context.Context, arg GetPropertiesByEventTypeParams) ([]interface{}, error)
+	GetQuotaGraceState(ctx context.Context, arg GetQuotaGraceStateParams) (QuotaGraceState, error)
 	GetValuePropertiesByEventType(ctx context.Context, arg GetValuePropertiesByEventTypeParams) ([]pgtype.Text, error)
+	GetWebhookDeliveryByEvent(ctx context.Context, arg GetWebhookDeliveryByEventParams) (WebhookDelivery, error)
+	GetWebhookDeliveryByID(ctx context.Context, arg GetWebhookDeliveryByIDParams) (WebhookDelivery, error)
+	GetWebhookEndpointByID(ctx context.Context, arg GetWebhookEndpointByIDParams) (WebhookEndpoint, error)
+	// records an event of an assignment that does not show as a change of its row
+	InsertAssignmentHistory(ctx context.Context, arg InsertAssignmentHistoryParams) error
+	// returns the assignments of a plan valid at the given time
+	ListActiveAssignmentsByPlan(ctx context.Context, arg ListActiveAssignmentsByPlanParams) ([]PlanAssignment, error)
+	// returns the overrides of a subject in force at the given time in the order they were made
+	ListActiveEntitlementOverrides(ctx context.Context, arg ListActiveEntitlementOverridesParams) ([]ListActiveEntitlementOverridesRow, error)
+	// returns the assignments of an organization or user valid at the given time: base plans first,
+	// latest first, then add-ons in the order they were assigned; open-ended assignments store the
+	// zero time as valid_until
+	ListActiveSubjectAssignments(ctx context.Context, arg ListActiveSubjectAssignmentsParams) ([]ListActiveSubjectAssignmentsRow, error)
+	ListAlertRulesPaginated(ctx context.Context, arg ListAlertRulesPaginatedParams) ([]AlertRule, error)
+	ListAlertStates(ctx context.Context, arg ListAlertStatesParams) ([]AlertState, error)
 	ListAllAssignmentsPaginated(ctx context.Context, arg ListAllAssignmentsPaginatedParams) ([]PlanAssignment, error)
+	ListAllMeters(ctx context.Context) ([]Meter, error)
 	ListAssignmentsHistoryPaginated(ctx context.Context, arg ListAssignmentsHistoryPaginatedParams) ([]PlanAssignmentHistory, error)
 	ListAssignmentsPaginated(ctx context.Context, arg ListAssignmentsPaginatedParams) ([]PlanAssignment, error)
+	// returns the active trials of every tenant that ended by the given time, oldest first
+	ListDueTrials(ctx context.Context, arg ListDueTrialsParams) ([]ListDueTrialsRow, error)
+	ListEnabledAlertRules(ctx context.Context) ([]AlertRule, error)
+	// lists the overrides of a subject, without expired ones unless include_expired is set
+	ListEntitlementOverridesPaginated(ctx context.Context, arg ListEntitlementOverridesPaginatedParams) ([]ListEntitlementOverridesPaginatedRow, error)
 	ListFeaturesPaginated(ctx context.Context, arg ListFeaturesPaginatedParams) ([]Feature, error)
+	ListMeterSlugsReferencing(ctx context.Context, arg ListMeterSlugsReferencingParams) ([]string, error)
 	ListMetersByEventTypes(ctx context.Context, arg ListMetersByEventTypesParams) ([]Meter, error)
+	ListMetersByStatus(ctx context.Context, arg ListMetersByStatusParams) ([]Meter, error)
 	ListMetersPaginated(ctx context.Context, arg ListMetersPaginatedParams) ([]Meter, error)
+	ListPlanChangesPaginated(ctx context.Context, arg ListPlanChangesPaginatedParams) ([]ListPlanChangesPaginatedRow, error)
 	ListPlanFeaturesByPlan(ctx context.Context, arg ListPlanFeaturesByPlanParams) ([]ListPlanFeaturesByPlanRow, error)
+	ListPlanFeaturesByVersion(ctx context.Context, arg ListPlanFeaturesByVersionParams) ([]ListPlanFeaturesByVersionRow, error)
+	// lists trials ending soonest first
+	ListPlanTrialsPaginated(ctx context.Context, arg ListPlanTrialsPaginatedParams) ([]ListPlanTrialsPaginatedRow, error)
+	ListPlanVersions(ctx context.Context, arg ListPlanVersionsParams) ([]PlanVersion, error)
 	ListPlansPaginated(ctx context.Context, arg ListPlansPaginatedParams) ([]Plan, error)
+	ListSubscribedWebhookEndpoints(ctx context.Context, arg ListSubscribedWebhookEndpointsParams) ([]WebhookEndpoint, error)
+	ListWebhookDeliveriesPaginated(ctx context.Context, arg ListWebhookDeliveriesPaginatedParams) ([]WebhookDelivery, error)
+	ListWebhookDeliveryAttempts(ctx context.Context, deliveryID pgtype.UUID) ([]WebhookDeliveryAttempt, error)
+	ListWebhookEndpointsPaginated(ctx context.Context, arg ListWebhookEndpointsPaginatedParams) ([]WebhookEndpoint, error)
+	// locks the plan so concurrent publishes number their versions one after another
+	LockPlanForVersioning(ctx context.Context, arg LockPlanForVersioningParams) (pgtype.UUID, error)
+	// moves the current and future assignments of a plan to another version of it, either right away
+	// or, with on_renewal, by marking the version their renewals are assigned
+	MigratePlanAssignments(ctx context.Context, arg MigratePlanAssignmentsParams) (int64, error)
+	RecordWebhookDeliveryAttempt(ctx context.Context, arg RecordWebhookDeliveryAttemptParams) (WebhookDelivery, error)
+	RequeueWebhookDelivery(ctx context.Context, arg RequeueWebhookDeliveryParams) (WebhookDelivery, error)
+	// moves the end of an assignment; the zero time makes it open-ended
+	SetAssignmentValidUntil(ctx context.Context, arg SetAssignmentValidUntilParams) (PlanAssignment, error)
 	// removes a plan assignment for either an organization or user
 	TerminateAssignedPlan(ctx context.Context, arg TerminateAssignedPlanParams) error
 	UnArchivePlanByID(ctx context.Context, arg UnArchivePlanByIDParams) (Plan, error)
 	UnArchivePlanBySlug(ctx context.Context, arg UnArchivePlanBySlugParams) (Plan, error)
+	UpdateAlertRule(ctx context.Context, arg UpdateAlertRuleParams) (AlertRule, error)
 	// updates the validity period of a plan assignment for either organization or user
 	UpdateAssignedPlan(ctx context.Context, arg UpdateAssignedPlanParams) (PlanAssignment, error)
+	UpdateEntitlementOverride(ctx context.Context, arg UpdateEntitlementOverrideParams) (EntitlementOverride, error)
 	UpdateFeatureByID(ctx context.Context, arg UpdateFeatureByIDParams) (Feature, error)
 	UpdateFeatureBySlug(ctx context.Context, arg UpdateFeatureBySlugParams) (Feature, error)
 	UpdateMeterByID(ctx context.Context, arg UpdateMeterByIDParams) (Meter, error)
 	UpdateMeterBySlug(ctx context.Context, arg UpdateMeterBySlugParams) (Meter, error)
+	UpdateMeterStatus(ctx context.Context, arg UpdateMeterStatusParams) (Meter, error)
 	UpdatePlanByID(ctx context.Context, arg UpdatePlanByIDParams) (Plan, error)
 	UpdatePlanBySlug(ctx context.Context, arg UpdatePlanBySlugParams) (Plan, error)
 	UpdatePlanFeatureConfigByPlan(ctx context.Context, arg UpdatePlanFeatureConfigByPlanParams) (UpdatePlanFeatureConfigByPlanRow, error)
 	UpdatePlanFeatureQuota(ctx context.Context, arg UpdatePlanFeatureQuotaParams) (PlanFeatureQuotum, error)
+	UpdateWebhookEndpoint(ctx context.Context, arg UpdateWebhookEndpointParams) (WebhookEndpoint, error)
+	UpsertAlertState(ctx context.Context, arg UpsertAlertStateParams) (AlertState, error)
+	UpsertQuotaGraceState(ctx context.Context, arg UpsertQuotaGraceStateParams) (QuotaGraceState, error)
 }
 
 var _ Querier = (*Queries)(nil)
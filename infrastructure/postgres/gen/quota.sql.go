@@ -34,18 +34,26 @@ insert into plan_feature_quota (
     limit_value,
     reset_period,
     custom_period_minutes,
-    action_at_limit
+    action_at_limit,
+    soft_limit_value,
+    overage_allowance,
+    overage_allowance_type,
+    grace_period_minutes
 ) values (
-    $1, $2, $3, $4, $5
-) returning id, plan_feature_id, limit_value, reset_period, custom_period_minutes, action_at_limit, created_at, updated_at
+    $1, $2, $3, $4, $5, $6, $7, $8, $9
+) returning id, plan_feature_id, limit_value, reset_period, custom_period_minutes, action_at_limit, created_at, updated_at, soft_limit_value, overage_allowance, overage_allowance_type, grace_period_minutes
 `
 
 type CreatePlanFeatureQuotaParams struct {
-	PlanFeatureID       pgtype.UUID
-	LimitValue          int64
-	ResetPeriod         MeteredResetPeriodEnum
-	CustomPeriodMinutes pgtype.Int8
-	ActionAtLimit       MeteredActionAtLimitEnum
+	PlanFeatureID        pgtype.UUID
+	LimitValue           int64
+	ResetPeriod          MeteredResetPeriodEnum
+	CustomPeriodMinutes  pgtype.Int8
+	ActionAtLimit        MeteredActionAtLimitEnum
+	SoftLimitValue       pgtype.Int8
+	OverageAllowance     pgtype.Int8
+	OverageAllowanceType NullQuotaOverageTypeEnum
+	GracePeriodMinutes   pgtype.Int8
 }
 
 func (q *Queries) CreatePlanFeatureQuota(ctx context.Context, arg CreatePlanFeatureQuotaParams) (PlanFeatureQuotum, error) {
@@ -55,6 +63,10 @@ func (q *Queries) CreatePlanFeatureQuota(ctx context.Context, arg CreatePlanFeat
 		arg.ResetPeriod,
 		arg.CustomPeriodMinutes,
 		arg.ActionAtLimit,
+		arg.SoftLimitValue,
+		arg.OverageAllowance,
+		arg.OverageAllowanceType,
+		arg.GracePeriodMinutes,
 	)
 	var i PlanFeatureQuotum
 	err := row.Scan(
@@ -66,6 +78,10 @@ func (q *Queries) CreatePlanFeatureQuota(ctx context.Context, arg CreatePlanFeat
 		&i.ActionAtLimit,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.SoftLimitValue,
+		&i.OverageAllowance,
+		&i.OverageAllowanceType,
+		&i.GracePeriodMinutes,
 	)
 	return i, err
 }
@@ -81,7 +97,7 @@ func (q *Queries) DeletePlanFeatureQuota(ctx context.Context, planFeatureID pgty
 }
 
 const getPlanFeatureQuotaByPlanFeatureID = `-- name: GetPlanFeatureQuotaByPlanFeatureID :one
-select id, plan_feature_id, limit_value, reset_period, custom_period_minutes, action_at_limit, created_at, updated_at from plan_feature_quota
+select id, plan_feature_id, limit_value, reset_period, custom_period_minutes, action_at_limit, created_at, updated_at, soft_limit_value, overage_allowance, overage_allowance_type, grace_period_minutes from plan_feature_quota
 where plan_feature_id = $1
 `
 
@@ -97,6 +113,45 @@ func (q *Queries) GetPlanFeatureQuotaByPlanFeatureID(ctx context.Context, planFe
 		&i.ActionAtLimit,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.SoftLimitValue,
+		&i.OverageAllowance,
+		&i.OverageAllowanceType,
+		&i.GracePeriodMinutes,
+	)
+	return i, err
+}
+
+const getQuotaGraceState = `-- name: GetQuotaGraceState :one
+select id, tenant_slug, subject_type, subject_id, feature_id, allowance_used_up_at, updated_at from quota_grace_state
+where tenant_slug = $1
+and subject_type = $2
+and subject_id = $3
+and feature_id = $4
+`
+
+type GetQuotaGraceStateParams struct {
+	TenantSlug  string
+	SubjectType SubjectTypeEnum
+	SubjectID   string
+	FeatureID   pgtype.UUID
+}
+
+func (q *Queries) GetQuotaGraceState(ctx context.Context, arg GetQuotaGraceStateParams) (QuotaGraceState, error) {
+	row := q.db.QueryRow(ctx, getQuotaGraceState,
+		arg.TenantSlug,
+		arg.SubjectType,
+		arg.SubjectID,
+		arg.FeatureID,
+	)
+	var i QuotaGraceState
+	err := row.Scan(
+		&i.ID,
+		&i.TenantSlug,
+		&i.SubjectType,
+		&i.SubjectID,
+		&i.FeatureID,
+		&i.AllowanceUsedUpAt,
+		&i.UpdatedAt,
 	)
 	return i, err
 }
@@ -107,17 +162,28 @@ set
     limit_value = coalesce($2, limit_value),
     reset_period = coalesce($3, reset_period),
     custom_period_minutes = coalesce($4, custom_period_minutes),
-    action_at_limit = coalesce($5, action_at_limit)
+    action_at_limit = coalesce($5, action_at_limit),
+    soft_limit_value = case when $6::boolean then null else coalesce($7, soft_limit_value) end,
+    overage_allowance = case when $8::boolean then null else coalesce($9, overage_allowance) end,
+    overage_allowance_type = case when $8::boolean then null else coalesce($10, overage_allowance_type) end,
+    grace_period_minutes = case when $11::boolean then null else coalesce($12, grace_period_minutes) end
 where plan_feature_id = $1
-returning id, plan_feature_id, limit_value, reset_period, custom_period_minutes, action_at_limit, created_at, updated_at
+returning id, plan_feature_id, limit_value, reset_period, custom_period_minutes, action_at_limit, created_at, updated_at, soft_limit_value, overage_allowance, overage_allowance_type, grace_period_minutes
 `
 
 type UpdatePlanFeatureQuotaParams struct {
-	PlanFeatureID       pgtype.UUID
-	LimitValue          pgtype.Int8
-	ResetPeriod         NullMeteredResetPeriodEnum
-	CustomPeriodMinutes pgtype.Int8
-	ActionAtLimit       NullMeteredActionAtLimitEnum
+	PlanFeatureID           pgtype.UUID
+	LimitValue              pgtype.Int8
+	ResetPeriod             NullMeteredResetPeriodEnum
+	CustomPeriodMinutes     pgtype.Int8
+	ActionAtLimit           NullMeteredActionAtLimitEnum
+	ClearSoftLimitValue     bool
+	SoftLimitValue          pgtype.Int8
+	ClearOverageAllowance   bool
+	OverageAllowance        pgtype.Int8
+	OverageAllowanceType    NullQuotaOverageTypeEnum
+	ClearGracePeriodMinutes bool
+	GracePeriodMinutes      pgtype.Int8
 }
 
 func (q *Queries) UpdatePlanFeatureQuota(ctx context.Context, arg UpdatePlanFeatureQuotaParams) (PlanFeatureQuotum, error) {
@@ -127,6 +193,13 @@ func (q *Queries) UpdatePlanFeatureQuota(ctx context.Context, arg UpdatePlanFeat
 		arg.ResetPeriod,
 		arg.CustomPeriodMinutes,
 		arg.ActionAtLimit,
+		arg.ClearSoftLimitValue,
+		arg.SoftLimitValue,
+		arg.ClearOverageAllowance,
+		arg.OverageAllowance,
+		arg.OverageAllowanceType,
+		arg.ClearGracePeriodMinutes,
+		arg.GracePeriodMinutes,
 	)
 	var i PlanFeatureQuotum
 	err := row.Scan(
@@ -138,6 +211,55 @@ func (q *Queries) UpdatePlanFeatureQuota(ctx context.Context, arg UpdatePlanFeat
 		&i.ActionAtLimit,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.SoftLimitValue,
+		&i.OverageAllowance,
+		&i.OverageAllowanceType,
+		&i.GracePeriodMinutes,
+	)
+	return i, err
+}
+
+const upsertQuotaGraceState = `-- name: UpsertQuotaGraceState :one
+insert into quota_grace_state (
+  tenant_slug,
+  subject_type,
+  subject_id,
+  feature_id,
+  allowance_used_up_at
+) values (
+  $1, $2, $3, $4, $5
+)
+on conflict (tenant_slug, subject_type, subject_id, feature_id) do update
+set allowance_used_up_at = excluded.allowance_used_up_at,
+    updated_at = now()
+returning id, tenant_slug, subject_type, subject_id, feature_id, allowance_used_up_at, updated_at
+`
+
+type UpsertQuotaGraceStateParams struct {
+	TenantSlug        string
+	SubjectType       SubjectTypeEnum
+	SubjectID         string
+	FeatureID         pgtype.UUID
+	AllowanceUsedUpAt pgtype.Timestamptz
+}
+
+func (q *Queries) UpsertQuotaGraceState(ctx context.Context, arg UpsertQuotaGraceStateParams) (QuotaGraceState, error) {
+	row := q.db.QueryRow(ctx, upsertQuotaGraceState,
+		arg.TenantSlug,
+		arg.SubjectType,
+		arg.SubjectID,
+		arg.FeatureID,
+		arg.AllowanceUsedUpAt,
+	)
+	var i QuotaGraceState
+	err := row.Scan(
+		&i.ID,
+		&i.TenantSlug,
+		&i.SubjectType,
+		&i.SubjectID,
+		&i.FeatureID,
+		&i.AllowanceUsedUpAt,
+		&i.UpdatedAt,
 	)
 	return i, err
 }
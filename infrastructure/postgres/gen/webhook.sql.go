@@ -0,0 +1,678 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: webhook.sql
+
+package gen
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const claimDueWebhookDeliveries = `-- name: ClaimDueWebhookDeliveries :many
+update webhook_delivery
+set next_attempt_at = $1,
+    updated_at = now()
+where id in (
+  select d.id from webhook_delivery d
+  where d.status = 'pending'
+  and d.next_attempt_at <= $2
+  order by d.next_attempt_at
+  limit $3
+  for update skip locked
+)
+returning id, endpoint_id, tenant_slug, event_id, event_type, payload, status, attempts, next_attempt_at, last_attempt_at, last_error, created_at, updated_at
+`
+
+type ClaimDueWebhookDeliveriesParams struct {
+	LeaseUntil pgtype.Timestamptz
+	Now        pgtype.Timestamptz
+	BatchSize  int32
+}
+
+func (q *Queries) ClaimDueWebhookDeliveries(ctx context.Context, arg ClaimDueWebhookDeliveriesParams) ([]WebhookDelivery, error) {
+	rows, err := q.db.Query(ctx, claimDueWebhookDeliveries, arg.LeaseUntil, arg.Now, arg.BatchSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WebhookDelivery
+	for rows.Next() {
+		var i WebhookDelivery
+		if err := rows.Scan(
+			&i.ID,
+			&i.EndpointID,
+			&i.TenantSlug,
+			&i.EventID,
+			&i.EventType,
+			&i.Payload,
+			&i.Status,
+			&i.Attempts,
+			&i.NextAttemptAt,
+			&i.LastAttemptAt,
+			&i.LastError,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const countWebhookDeliveries = `-- name: CountWebhookDeliveries :one
+select count(*) from webhook_delivery
+where endpoint_id = $1
+and tenant_slug = $2
+`
+
+type CountWebhookDeliveriesParams struct {
+	EndpointID pgtype.UUID
+	TenantSlug string
+}
+
+func (q *Queries) CountWebhookDeliveries(ctx context.Context, arg CountWebhookDeliveriesParams) (int64, error) {
+	row := q.db.QueryRow(ctx, countWebhookDeliveries, arg.EndpointID, arg.TenantSlug)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countWebhookEndpoints = `-- name: CountWebhookEndpoints :one
+select count(*) from webhook_endpoint
+where tenant_slug = $1
+`
+
+func (q *Queries) CountWebhookEndpoints(ctx context.Context, tenantSlug string) (int64, error) {
+	row := q.db.QueryRow(ctx, countWebhookEndpoints, tenantSlug)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const createWebhookDelivery = `-- name: CreateWebhookDelivery :one
+insert into webhook_delivery (
+  endpoint_id,
+  tenant_slug,
+  event_id,
+  event_type,
+  payload
+) values (
+  $1, $2, $3, $4, $5
+)
+on conflict (endpoint_id, event_id) do nothing
+returning id, endpoint_id, tenant_slug, event_id, event_type, payload, status, attempts, next_attempt_at, last_attempt_at, last_error, created_at, updated_at
+`
+
+type CreateWebhookDeliveryParams struct {
+	EndpointID pgtype.UUID
+	TenantSlug string
+	EventID    pgtype.UUID
+	EventType  string
+	Payload    []byte
+}
+
+func (q *Queries) CreateWebhookDelivery(ctx context.Context, arg CreateWebhookDeliveryParams) (WebhookDelivery, error) {
+	row := q.db.QueryRow(ctx, createWebhookDelivery,
+		arg.EndpointID,
+		arg.TenantSlug,
+		arg.EventID,
+		arg.EventType,
+		arg.Payload,
+	)
+	var i WebhookDelivery
+	err := row.Scan(
+		&i.ID,
+		&i.EndpointID,
+		&i.TenantSlug,
+		&i.EventID,
+		&i.EventType,
+		&i.Payload,
+		&i.Status,
+		&i.Attempts,
+		&i.NextAttemptAt,
+		&i.LastAttemptAt,
+		&i.LastError,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const createWebhookDeliveryAttempt = `-- name: CreateWebhookDeliveryAttempt :one
+insert into webhook_delivery_attempt (
+  delivery_id,
+  attempt,
+  response_status,
+  response_body,
+  error,
+  duration_ms,
+  attempted_at
+) values (
+  $1, $2, $3, $4, $5, $6, $7
+) returning id, delivery_id, attempt, response_status, response_body, error, duration_ms, attempted_at
+`
+
+type CreateWebhookDeliveryAttemptParams struct {
+	DeliveryID     pgtype.UUID
+	Attempt        int32
+	ResponseStatus pgtype.Int4
+	ResponseBody   pgtype.Text
+	Error          pgtype.Text
+	DurationMs     int64
+	AttemptedAt    pgtype.Timestamptz
+}
+
+func (q *Queries) CreateWebhookDeliveryAttempt(ctx context.Context, arg CreateWebhookDeliveryAttemptParams) (WebhookDeliveryAttempt, error) {
+	row := q.db.QueryRow(ctx, createWebhookDeliveryAttempt,
+		arg.DeliveryID,
+		arg.Attempt,
+		arg.ResponseStatus,
+		arg.ResponseBody,
+		arg.Error,
+		arg.DurationMs,
+		arg.AttemptedAt,
+	)
+	var i WebhookDeliveryAttempt
+	err := row.Scan(
+		&i.ID,
+		&i.DeliveryID,
+		&i.Attempt,
+		&i.ResponseStatus,
+		&i.ResponseBody,
+		&i.Error,
+		&i.DurationMs,
+		&i.AttemptedAt,
+	)
+	return i, err
+}
+
+const createWebhookEndpoint = `-- name: CreateWebhookEndpoint :one
+insert into webhook_endpoint (
+  tenant_slug,
+  url,
+  description,
+  secret,
+  event_types,
+  enabled,
+  created_by,
+  updated_by
+) values (
+  $1, $2, $3, $4, $5, $6, $7, $8
+) returning id, tenant_slug, url, description, secret, event_types, enabled, created_at, updated_at, created_by, updated_by
+`
+
+type CreateWebhookEndpointParams struct {
+	TenantSlug  string
+	Url         string
+	Description pgtype.Text
+	Secret      string
+	EventTypes  []string
+	Enabled     bool
+	CreatedBy   string
+	UpdatedBy   string
+}
+
+func (q *Queries) CreateWebhookEndpoint(ctx context.Context, arg CreateWebhookEndpointParams) (WebhookEndpoint, error) {
+	row := q.db.QueryRow(ctx, createWebhookEndpoint,
+		arg.TenantSlug,
+		arg.Url,
+		arg.Description,
+		arg.Secret,
+		arg.EventTypes,
+		arg.Enabled,
+		arg.CreatedBy,
+		arg.UpdatedBy,
+	)
+	var i WebhookEndpoint
+	err := row.Scan(
+		&i.ID,
+		&i.TenantSlug,
+		&i.Url,
+		&i.Description,
+		&i.Secret,
+		&i.EventTypes,
+		&i.Enabled,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.CreatedBy,
+		&i.UpdatedBy,
+	)
+	return i, err
+}
+
+const deleteWebhookEndpoint = `-- name: DeleteWebhookEndpoint :exec
+delete from webhook_endpoint
+where id = $1
+and tenant_slug = $2
+`
+
+type DeleteWebhookEndpointParams struct {
+	ID         pgtype.UUID
+	TenantSlug string
+}
+
+func (q *Queries) DeleteWebhookEndpoint(ctx context.Context, arg DeleteWebhookEndpointParams) error {
+	_, err := q.db.Exec(ctx, deleteWebhookEndpoint, arg.ID, arg.TenantSlug)
+	return err
+}
+
+const getWebhookDeliveryByEvent = `-- name: GetWebhookDeliveryByEvent :one
+select id, endpoint_id, tenant_slug, event_id, event_type, payload, status, attempts, next_attempt_at, last_attempt_at, last_error, created_at, updated_at from webhook_delivery
+where endpoint_id = $1
+and event_id = $2
+and tenant_slug = $3
+`
+
+type GetWebhookDeliveryByEventParams struct {
+	EndpointID pgtype.UUID
+	EventID    pgtype.UUID
+	TenantSlug string
+}
+
+func (q *Queries) GetWebhookDeliveryByEvent(ctx context.Context, arg GetWebhookDeliveryByEventParams) (WebhookDelivery, error) {
+	row := q.db.QueryRow(ctx, getWebhookDeliveryByEvent, arg.EndpointID, arg.EventID, arg.TenantSlug)
+	var i WebhookDelivery
+	err := row.Scan(
+		&i.ID,
+		&i.EndpointID,
+		&i.TenantSlug,
+		&i.EventID,
+		&i.EventType,
+		&i.Payload,
+		&i.Status,
+		&i.Attempts,
+		&i.NextAttemptAt,
+		&i.LastAttemptAt,
+		&i.LastError,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getWebhookDeliveryByID = `-- name: GetWebhookDeliveryByID :one
+select id, endpoint_id, tenant_slug, event_id, event_type, payload, status, attempts, next_attempt_at, last_attempt_at, last_error, created_at, updated_at from webhook_delivery
+where id = $1
+and tenant_slug = $2
+`
+
+type GetWebhookDeliveryByIDParams struct {
+	ID         pgtype.UUID
+	TenantSlug string
+}
+
+func (q *Queries) GetWebhookDeliveryByID(ctx context.Context, arg GetWebhookDeliveryByIDParams) (WebhookDelivery, error) {
+	row := q.db.QueryRow(ctx, getWebhookDeliveryByID, arg.ID, arg.TenantSlug)
+	var i WebhookDelivery
+	err := row.Scan(
+		&i.ID,
+		&i.EndpointID,
+		&i.TenantSlug,
+		&i.EventID,
+		&i.EventType,
+		&i.Payload,
+		&i.Status,
+		&i.Attempts,
+		&i.NextAttemptAt,
+		&i.LastAttemptAt,
+		&i.LastError,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getWebhookEndpointByID = `-- name: GetWebhookEndpointByID :one
+select id, tenant_slug, url, description, secret, event_types, enabled, created_at, updated_at, created_by, updated_by from webhook_endpoint
+where id = $1
+and tenant_slug = $2
+`
+
+type GetWebhookEndpointByIDParams struct {
+	ID         pgtype.UUID
+	TenantSlug string
+}
+
+func (q *Queries) GetWebhookEndpointByID(ctx context.Context, arg GetWebhookEndpointByIDParams) (WebhookEndpoint, error) {
+	row := q.db.QueryRow(ctx, getWebhookEndpointByID, arg.ID, arg.TenantSlug)
+	var i WebhookEndpoint
+	err := row.Scan(
+		&i.ID,
+		&i.TenantSlug,
+		&i.Url,
+		&i.Description,
+		&i.Secret,
+		&i.EventTypes,
+		&i.Enabled,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.CreatedBy,
+		&i.UpdatedBy,
+	)
+	return i, err
+}
+
+const listSubscribedWebhookEndpoints = `-- name: ListSubscribedWebhookEndpoints :many
+select id, tenant_slug, url, description, secret, event_types, enabled, created_at, updated_at, created_by, updated_by from webhook_endpoint
+where tenant_slug = $1
+and enabled
+and $2::text = any(event_types)
+`
+
+type ListSubscribedWebhookEndpointsParams struct {
+	TenantSlug string
+	EventType  string
+}
+
+func (q *Queries) ListSubscribedWebhookEndpoints(ctx context.Context, arg ListSubscribedWebhookEndpointsParams) ([]WebhookEndpoint, error) {
+	rows, err := q.db.Query(ctx, listSubscribedWebhookEndpoints, arg.TenantSlug, arg.EventType)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WebhookEndpoint
+	for rows.Next() {
+		var i WebhookEndpoint
+		if err := rows.Scan(
+			&i.ID,
+			&i.TenantSlug,
+			&i.Url,
+			&i.Description,
+			&i.Secret,
+			&i.EventTypes,
+			&i.Enabled,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.CreatedBy,
+			&i.UpdatedBy,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listWebhookDeliveriesPaginated = `-- name: ListWebhookDeliveriesPaginated :many
+select id, endpoint_id, tenant_slug, event_id, event_type, payload, status, attempts, next_attempt_at, last_attempt_at, last_error, created_at, updated_at from webhook_delivery
+where endpoint_id = $1
+and tenant_slug = $2
+order by created_at desc
+limit $3
+offset $4
+`
+
+type ListWebhookDeliveriesPaginatedParams struct {
+	EndpointID pgtype.UUID
+	TenantSlug string
+	Limit      int32
+	Offset     int32
+}
+
+func (q *Queries) ListWebhookDeliveriesPaginated(ctx context.Context, arg ListWebhookDeliveriesPaginatedParams) ([]WebhookDelivery, error) {
+	rows, err := q.db.Query(ctx, listWebhookDeliveriesPaginated,
+		arg.EndpointID,
+		arg.TenantSlug,
+		arg.Limit,
+		arg.Offset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WebhookDelivery
+	for rows.Next() {
+		var i WebhookDelivery
+		if err := rows.Scan(
+			&i.ID,
+			&i.EndpointID,
+			&i.TenantSlug,
+			&i.EventID,
+			&i.EventType,
+			&i.Payload,
+			&i.Status,
+			&i.Attempts,
+			&i.NextAttemptAt,
+			&i.LastAttemptAt,
+			&i.LastError,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listWebhookDeliveryAttempts = `-- name: ListWebhookDeliveryAttempts :many
+select id, delivery_id, attempt, response_status, response_body, error, duration_ms, attempted_at from webhook_delivery_attempt
+where delivery_id = $1
+order by attempt
+`
+
+func (q *Queries) ListWebhookDeliveryAttempts(ctx context.Context, deliveryID pgtype.UUID) ([]WebhookDeliveryAttempt, error) {
+	rows, err := q.db.Query(ctx, listWebhookDeliveryAttempts, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WebhookDeliveryAttempt
+	for rows.Next() {
+		var i WebhookDeliveryAttempt
+		if err := rows.Scan(
+			&i.ID,
+			&i.DeliveryID,
+			&i.Attempt,
+			&i.ResponseStatus,
+			&i.ResponseBody,
+			&i.Error,
+			&i.DurationMs,
+			&i.AttemptedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listWebhookEndpointsPaginated = `-- name: ListWebhookEndpointsPaginated :many
+select id, tenant_slug, url, description, secret, event_types, enabled, created_at, updated_at, created_by, updated_by from webhook_endpoint
+where tenant_slug = $1
+order by created_at desc
+limit $2
+offset $3
+`
+
+type ListWebhookEndpointsPaginatedParams struct {
+	TenantSlug string
+	Limit      int32
+	Offset     int32
+}
+
+func (q *Queries) ListWebhookEndpointsPaginated(ctx context.Context, arg ListWebhookEndpointsPaginatedParams) ([]WebhookEndpoint, error) {
+	rows, err := q.db.Query(ctx, listWebhookEndpointsPaginated, arg.TenantSlug, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WebhookEndpoint
+	for rows.Next() {
+		var i WebhookEndpoint
+		if err := rows.Scan(
+			&i.ID,
+			&i.TenantSlug,
+			&i.Url,
+			&i.Description,
+			&i.Secret,
+			&i.EventTypes,
+			&i.Enabled,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.CreatedBy,
+			&i.UpdatedBy,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const recordWebhookDeliveryAttempt = `-- name: RecordWebhookDeliveryAttempt :one
+update webhook_delivery
+set status = $2,
+    attempts = $3,
+    next_attempt_at = $4,
+    last_attempt_at = $5,
+    last_error = $6,
+    updated_at = now()
+where id = $1
+returning id, endpoint_id, tenant_slug, event_id, event_type, payload, status, attempts, next_attempt_at, last_attempt_at, last_error, created_at, updated_at
+`
+
+type RecordWebhookDeliveryAttemptParams struct {
+	ID            pgtype.UUID
+	Status        WebhookDeliveryStatusEnum
+	Attempts      int32
+	NextAttemptAt pgtype.Timestamptz
+	LastAttemptAt pgtype.Timestamptz
+	LastError     pgtype.Text
+}
+
+func (q *Queries) RecordWebhookDeliveryAttempt(ctx context.Context, arg RecordWebhookDeliveryAttemptParams) (WebhookDelivery, error) {
+	row := q.db.QueryRow(ctx, recordWebhookDeliveryAttempt,
+		arg.ID,
+		arg.Status,
+		arg.Attempts,
+		arg.NextAttemptAt,
+		arg.LastAttemptAt,
+		arg.LastError,
+	)
+	var i WebhookDelivery
+	err := row.Scan(
+		&i.ID,
+		&i.EndpointID,
+		&i.TenantSlug,
+		&i.EventID,
+		&i.EventType,
+		&i.Payload,
+		&i.Status,
+		&i.Attempts,
+		&i.NextAttemptAt,
+		&i.LastAttemptAt,
+		&i.LastError,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const requeueWebhookDelivery = `-- name: RequeueWebhookDelivery :one
+update webhook_delivery
+set status = 'pending',
+    next_attempt_at = now(),
+    updated_at = now()
+where id = $1
+and tenant_slug = $2
+returning id, endpoint_id, tenant_slug, event_id, event_type, payload, status, attempts, next_attempt_at, last_attempt_at, last_error, created_at, updated_at
+`
+
+type RequeueWebhookDeliveryParams struct {
+	ID         pgtype.UUID
+	TenantSlug string
+}
+
+func (q *Queries) RequeueWebhookDelivery(ctx context.Context, arg RequeueWebhookDeliveryParams) (WebhookDelivery, error) {
+	row := q.db.QueryRow(ctx, requeueWebhookDelivery, arg.ID, arg.TenantSlug)
+	var i WebhookDelivery
+	err := row.Scan(
+		&i.ID,
+		&i.EndpointID,
+		&i.TenantSlug,
+		&i.EventID,
+		&i.EventType,
+		&i.Payload,
+		&i.Status,
+		&i.Attempts,
+		&i.NextAttemptAt,
+		&i.LastAttemptAt,
+		&i.LastError,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const updateWebhookEndpoint = `-- name: UpdateWebhookEndpoint :one
+update webhook_endpoint
+set url = coalesce($1, url),
+    description = coalesce($2, description),
+    secret = coalesce($3, secret),
+    event_types = coalesce($4::text[], event_types),
+    enabled = coalesce($5, enabled),
+    updated_by = $6,
+    updated_at = now()
+where id = $7
+and tenant_slug = $8
+returning id, tenant_slug, url, description, secret, event_types, enabled, created_at, updated_at, created_by, updated_by
+`
+
+type UpdateWebhookEndpointParams struct {
+	Url         pgtype.Text
+	Description pgtype.Text
+	Secret      pgtype.Text
+	EventTypes  []string
+	Enabled     pgtype.Bool
+	UpdatedBy   string
+	ID          pgtype.UUID
+	TenantSlug  string
+}
+
+func (q *Queries) UpdateWebhookEndpoint(ctx context.Context, arg UpdateWebhookEndpointParams) (WebhookEndpoint, error) {
+	row := q.db.QueryRow(ctx, updateWebhookEndpoint,
+		arg.Url,
+		arg.Description,
+		arg.Secret,
+		arg.EventTypes,
+		arg.Enabled,
+		arg.UpdatedBy,
+		arg.ID,
+		arg.TenantSlug,
+	)
+	var i WebhookEndpoint
+	err := row.Scan(
+		&i.ID,
+		&i.TenantSlug,
+		&i.Url,
+		&i.Description,
+		&i.Secret,
+		&i.EventTypes,
+		&i.Enabled,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.CreatedBy,
+		&i.UpdatedBy,
+	)
+	return i, err
+}
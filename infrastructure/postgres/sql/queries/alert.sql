@@ -0,0 +1,87 @@
+-- name: CreateAlertRule :one
+insert into alert_rule (
+  tenant_slug,
+  name,
+  meter_slug,
+  plan_feature_id,
+  subject_type,
+  subject_ids,
+  threshold_type,
+  threshold,
+  window_minutes,
+  enabled,
+  created_by,
+  updated_by
+) values (
+  $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12
+) returning *;
+
+-- name: GetAlertRuleByID :one
+select * from alert_rule
+where id = $1
+and tenant_slug = $2;
+
+-- name: ListAlertRulesPaginated :many
+select * from alert_rule
+where tenant_slug = $1
+order by created_at desc
+limit $2
+offset $3;
+
+-- name: CountAlertRules :one
+select count(*) from alert_rule
+where tenant_slug = $1;
+
+-- name: ListEnabledAlertRules :many
+select * from alert_rule
+where enabled
+order by tenant_slug, created_at;
+
+-- name: UpdateAlertRule :one
+update alert_rule
+set name = coalesce(sqlc.narg('name'), name),
+    subject_ids = coalesce(sqlc.narg('subject_ids')::text[], subject_ids),
+    threshold = coalesce(sqlc.narg('threshold'), threshold),
+    window_minutes = coalesce(sqlc.narg('window_minutes'), window_minutes),
+    enabled = coalesce(sqlc.narg('enabled'), enabled),
+    updated_by = sqlc.arg('updated_by'),
+    updated_at = now()
+where id = sqlc.arg('id')
+and tenant_slug = sqlc.arg('tenant_slug')
+returning *;
+
+-- name: DeleteAlertRule :exec
+delete from alert_rule
+where id = $1
+and tenant_slug = $2;
+
+-- name: ListAlertStates :many
+select s.* from alert_state s
+join alert_rule r on s.alert_rule_id = r.id
+where s.alert_rule_id = $1
+and r.tenant_slug = $2
+order by s.subject_id;
+
+-- name: UpsertAlertState :one
+insert into alert_state (
+  alert_rule_id,
+  subject_id,
+  status,
+  value,
+  threshold_value,
+  period_start,
+  fired_at,
+  resolved_at,
+  evaluated_at
+) values (
+  $1, $2, $3, $4, $5, $6, $7, $8, $9
+)
+on conflict (alert_rule_id, subject_id) do update
+set status = excluded.status,
+    value = excluded.value,
+    threshold_value = excluded.threshold_value,
+    period_start = excluded.period_start,
+    fired_at = excluded.fired_at,
+    resolved_at = excluded.resolved_at,
+    evaluated_at = excluded.evaluated_at
+returning *;
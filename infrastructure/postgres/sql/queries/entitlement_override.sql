@@ -0,0 +1,76 @@
+-- name: CreateEntitlementOverride :one
+insert into entitlement_override (
+  tenant_slug,
+  subject_type,
+  subject_id,
+  plan_assignment_id,
+  feature_id,
+  effect,
+  limit_value,
+  reset_period,
+  custom_period_minutes,
+  action_at_limit,
+  reason,
+  expires_at,
+  created_by,
+  updated_by
+) values (
+  $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14
+) returning *;
+
+-- name: GetEntitlementOverride :one
+select sqlc.embed(eo), f.slug as feature_slug, f.name as feature_name, f.type as feature_type, f.meter_slugs as feature_meter_slugs, f.merge_rule as feature_merge_rule
+from entitlement_override eo
+join feature f on eo.feature_id = f.id
+where eo.id = $1
+and eo.tenant_slug = $2;
+
+-- name: ListEntitlementOverridesPaginated :many
+-- lists the overrides of a subject, without expired ones unless include_expired is set
+select sqlc.embed(eo), f.slug as feature_slug, f.name as feature_name, f.type as feature_type, f.meter_slugs as feature_meter_slugs, f.merge_rule as feature_merge_rule
+from entitlement_override eo
+join feature f on eo.feature_id = f.id
+where eo.tenant_slug = sqlc.arg('tenant_slug')
+and eo.subject_type = sqlc.arg('subject_type')
+and eo.subject_id = sqlc.arg('subject_id')
+and (sqlc.arg('include_expired')::boolean or eo.expires_at is null or eo.expires_at > sqlc.arg('at'))
+order by eo.created_at desc
+limit sqlc.arg('limit')
+offset sqlc.arg('offset');
+
+-- name: CountEntitlementOverrides :one
+select count(*) from entitlement_override eo
+where eo.tenant_slug = sqlc.arg('tenant_slug')
+and eo.subject_type = sqlc.arg('subject_type')
+and eo.subject_id = sqlc.arg('subject_id')
+and (sqlc.arg('include_expired')::boolean or eo.expires_at is null or eo.expires_at > sqlc.arg('at'));
+
+-- name: ListActiveEntitlementOverrides :many
+-- returns the overrides of a subject in force at the given time in the order they were made
+select sqlc.embed(eo), f.slug as feature_slug, f.name as feature_name, f.type as feature_type, f.meter_slugs as feature_meter_slugs, f.merge_rule as feature_merge_rule
+from entitlement_override eo
+join feature f on eo.feature_id = f.id
+where eo.tenant_slug = sqlc.arg('tenant_slug')
+and eo.subject_type = sqlc.arg('subject_type')
+and eo.subject_id = sqlc.arg('subject_id')
+and (eo.expires_at is null or eo.expires_at > sqlc.arg('at'))
+order by eo.created_at, eo.id;
+
+-- name: UpdateEntitlementOverride :one
+update entitlement_override
+set limit_value = coalesce(sqlc.narg('limit_value'), limit_value),
+    reset_period = coalesce(sqlc.narg('reset_period')::metered_reset_period_enum, reset_period),
+    custom_period_minutes = coalesce(sqlc.narg('custom_period_minutes'), custom_period_minutes),
+    action_at_limit = coalesce(sqlc.narg('action_at_limit')::metered_action_at_limit_enum, action_at_limit),
+    reason = coalesce(sqlc.narg('reason'), reason),
+    expires_at = case when sqlc.arg('clear_expires_at')::boolean then null else coalesce(sqlc.narg('expires_at'), expires_at) end,
+    updated_by = sqlc.arg('updated_by'),
+    updated_at = now()
+where id = sqlc.arg('id')
+and tenant_slug = sqlc.arg('tenant_slug')
+returning *;
+
+-- name: DeleteEntitlementOverride :exec
+delete from entitlement_override
+where id = $1
+and tenant_slug = $2;
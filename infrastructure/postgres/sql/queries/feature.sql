@@ -7,9 +7,11 @@ insert into feature (
   type,
   config,
   created_by,
-  updated_by
+  updated_by,
+  meter_slugs,
+  merge_rule
 ) values (
-  $1, $2, $3, $4, $5, $6, $7, $8
+  $1, $2, $3, $4, $5, $6, $7, $8, $9, $10
 ) returning *;
 
 -- name: GetFeatureByID :one
@@ -50,6 +52,8 @@ update feature
 set name = coalesce(sqlc.narg('name'), name),
     description = coalesce($1, description),
     config = coalesce($2, config),
+    meter_slugs = coalesce(sqlc.narg('meter_slugs')::text[], meter_slugs),
+    merge_rule = coalesce(sqlc.narg('merge_rule')::quota_merge_rule_enum, merge_rule),
     updated_by = $3
 where id = $4
 and tenant_slug = $5
@@ -60,6 +64,8 @@ update feature
 set name = coalesce(sqlc.narg('name'), name),
     description = coalesce($1, description),
     config = coalesce($2, config),
+    meter_slugs = coalesce(sqlc.narg('meter_slugs')::text[], meter_slugs),
+    merge_rule = coalesce(sqlc.narg('merge_rule')::quota_merge_rule_enum, merge_rule),
     updated_by = $3
 where slug = $4
 and tenant_slug = $5
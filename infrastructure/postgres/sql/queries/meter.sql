@@ -9,9 +9,18 @@ INSERT INTO meter (
     aggregation,
     tenant_slug,
     created_by,
-    updated_by
+    updated_by,
+    type,
+    formula,
+    source_meters,
+    granularity,
+    rollups,
+    status,
+    filter,
+    event_types,
+    value_properties
 ) VALUES (
-    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19
 ) RETURNING *;
 
 -- name: GetMeterByID :one
@@ -33,10 +42,15 @@ OFFSET $3;
 
 -- name: ListMetersByEventTypes :many
 SELECT * FROM meter
-WHERE event_type = ANY($1::text[])
+WHERE event_types && $1::text[]
 AND tenant_slug = $2;
 
 
+-- name: ListMeterSlugsReferencing :many
+SELECT slug FROM meter
+WHERE $1::text = ANY(source_meters)
+AND tenant_slug = $2;
+
 -- name: DeleteMeterByID :exec
 DELETE FROM meter
 WHERE id = $1 
@@ -86,3 +100,23 @@ SET name = coalesce(sqlc.narg('name'), name),
 WHERE slug = $2
 AND tenant_slug = $4
 RETURNING *;
+
+-- name: UpdateMeterStatus :one
+UPDATE meter
+SET status = $1,
+    status_error = $2,
+    reconcile_attempts = $3
+WHERE id = $4
+AND tenant_slug = $5
+RETURNING *;
+
+-- name: ListMetersByStatus :many
+SELECT * FROM meter
+WHERE status = ANY($1::meter_status_enum[])
+AND updated_at < $2
+ORDER BY updated_at
+LIMIT $3;
+
+-- name: ListAllMeters :many
+SELECT * FROM meter
+ORDER BY tenant_slug, slug;
@@ -6,9 +6,10 @@ INSERT INTO plan (
     type,
     tenant_slug,
     created_by,
-    updated_by
+    updated_by,
+    kind
 ) VALUES (
-    $1, $2, $3, $4, $5, $6, $7
+    $1, $2, $3, $4, $5, $6, $7, $8
 ) RETURNING *;
 
 -- name: GetPlanByID :one
@@ -25,6 +26,7 @@ AND tenant_slug = $2;
 SELECT * FROM plan
 WHERE tenant_slug = $1
 and (sqlc.narg('type')::plan_type_enum is null or type = sqlc.narg('type')::plan_type_enum)
+and (sqlc.narg('kind')::plan_kind_enum is null or kind = sqlc.narg('kind')::plan_kind_enum)
 and (sqlc.narg('archived')::boolean is null or archived_at is not null = sqlc.narg('archived')::boolean)
 ORDER BY created_at DESC
 LIMIT $2
@@ -75,7 +77,8 @@ RETURNING *;
 -- name: CountPlans :one
 SELECT count(*) FROM plan
 WHERE tenant_slug = $1
-AND (sqlc.narg('type')::plan_type_enum is null or type = sqlc.narg('type')::plan_type_enum);
+AND (sqlc.narg('type')::plan_type_enum is null or type = sqlc.narg('type')::plan_type_enum)
+AND (sqlc.narg('kind')::plan_kind_enum is null or kind = sqlc.narg('kind')::plan_kind_enum);
 
 -- name: UpdatePlanByID :one
 UPDATE plan
@@ -94,3 +97,53 @@ SET name = coalesce(sqlc.narg('name'), name),
 WHERE slug = $2
 AND tenant_slug = $4
 RETURNING *;
+
+-- name: ClonePlanFeatures :exec
+-- copies the draft features of a plan onto another plan
+INSERT INTO plan_feature (
+    plan_id,
+    feature_id,
+    config,
+    created_by,
+    updated_by
+)
+SELECT
+    sqlc.arg('plan_id')::uuid,
+    feature_id,
+    config,
+    sqlc.arg('created_by'),
+    sqlc.arg('created_by')
+FROM plan_feature
+WHERE plan_id = sqlc.arg('source_plan_id')::uuid
+AND plan_version_id IS NULL;
+
+-- name: ClonePlanFeatureQuotas :exec
+-- copies the quotas of the draft features of a plan onto the cloned features, replacing the limits
+-- overridden by feature slug
+INSERT INTO plan_feature_quota (
+    plan_feature_id,
+    limit_value,
+    reset_period,
+    custom_period_minutes,
+    action_at_limit,
+    soft_limit_value,
+    overage_allowance,
+    overage_allowance_type,
+    grace_period_minutes
+)
+SELECT
+    c.id,
+    coalesce((sqlc.arg('limit_overrides')::jsonb ->> f.slug)::bigint, q.limit_value),
+    q.reset_period,
+    q.custom_period_minutes,
+    q.action_at_limit,
+    q.soft_limit_value,
+    q.overage_allowance,
+    q.overage_allowance_type,
+    q.grace_period_minutes
+FROM plan_feature c
+JOIN plan_feature s ON s.feature_id = c.feature_id AND s.plan_id = sqlc.arg('source_plan_id')::uuid AND s.plan_version_id IS NULL
+JOIN plan_feature_quota q ON q.plan_feature_id = s.id
+JOIN feature f ON f.id = c.feature_id
+WHERE c.plan_id = sqlc.arg('plan_id')::uuid
+AND c.plan_version_id IS NULL;
@@ -7,9 +7,10 @@ insert into plan_assignment (
     valid_from,
     valid_until,
     created_by,
-    updated_by
+    updated_by,
+    plan_version_id
 ) values (
-$1, $2, $3, $4, $5, $6, $7
+$1, $2, $3, $4, $5, $6, $7, $8
 ) returning *;
 
 -- name: TerminateAssignedPlan :exec
@@ -72,7 +73,10 @@ SELECT
     pa.created_at,
     pa.updated_at,
     pa.created_by,
-    pa.updated_by
+    pa.updated_by,
+    pa.plan_version_id,
+    pa.pending_plan_version_id,
+    pa.kind
 FROM plan_assignment pa
 INNER JOIN plan p ON pa.plan_id = p.id
 WHERE p.tenant_slug = $1
@@ -121,3 +125,113 @@ AND (valid_until >= $7 or $7 is null)
 AND (action = $8 or $8 is null)
 AND EXISTS (SELECT 1 FROM plan where id = plan_id and tenant_slug = $9)
 ;
+
+-- name: ListActiveSubjectAssignments :many
+-- returns the assignments of an organization or user valid at the given time: base plans first,
+-- latest first, then add-ons in the order they were assigned; open-ended assignments store the
+-- zero time as valid_until
+SELECT sqlc.embed(pa), p.kind AS plan_kind
+FROM plan_assignment pa
+INNER JOIN plan p ON pa.plan_id = p.id
+WHERE p.tenant_slug = sqlc.arg('tenant_slug')
+AND pa.organization_id IS NOT DISTINCT FROM sqlc.narg('organization_id')
+AND pa.user_id IS NOT DISTINCT FROM sqlc.narg('user_id')
+AND pa.valid_from <= sqlc.arg('at')
+AND (pa.valid_until IS NULL OR pa.valid_until = '0001-01-01 00:00:00+00' OR pa.valid_until > sqlc.arg('at'))
+ORDER BY p.kind = 'base' DESC, CASE WHEN p.kind = 'base' THEN pa.valid_from END DESC, pa.valid_from, pa.created_at;
+
+-- name: CountOverlappingBaseAssignments :one
+-- counts the base plan assignments of an organization or user overlapping the given period;
+-- a null or zero valid_until leaves the period open-ended
+SELECT count(*)
+FROM plan_assignment pa
+INNER JOIN plan p ON pa.plan_id = p.id
+WHERE p.tenant_slug = sqlc.arg('tenant_slug')
+AND p.kind = 'base'
+AND pa.organization_id IS NOT DISTINCT FROM sqlc.narg('organization_id')
+AND pa.user_id IS NOT DISTINCT FROM sqlc.narg('user_id')
+AND (sqlc.narg('valid_until')::timestamptz IS NULL OR sqlc.narg('valid_until')::timestamptz = '0001-01-01 00:00:00+00' OR pa.valid_from < sqlc.narg('valid_until')::timestamptz)
+AND (pa.valid_until IS NULL OR pa.valid_until = '0001-01-01 00:00:00+00' OR pa.valid_until > sqlc.arg('valid_from'));
+
+-- name: ListActiveAssignmentsByPlan :many
+-- returns the assignments of a plan valid at the given time
+SELECT pa.*
+FROM plan_assignment pa
+INNER JOIN plan p ON pa.plan_id = p.id
+WHERE pa.plan_id = sqlc.arg('plan_id')
+AND p.tenant_slug = sqlc.arg('tenant_slug')
+AND pa.valid_from <= sqlc.arg('at')
+AND (pa.valid_until IS NULL OR pa.valid_until = '0001-01-01 00:00:00+00' OR pa.valid_until > sqlc.arg('at'))
+ORDER BY pa.valid_from;
+
+-- name: MigratePlanAssignments :execrows
+-- moves the current and future assignments of a plan to another version of it, either right away
+-- or, with on_renewal, by marking the version their renewals are assigned
+UPDATE plan_assignment pa
+SET plan_version_id = CASE WHEN sqlc.arg('on_renewal')::boolean THEN pa.plan_version_id ELSE sqlc.arg('to_version_id')::uuid END,
+    pending_plan_version_id = CASE WHEN sqlc.arg('on_renewal')::boolean THEN sqlc.arg('to_version_id')::uuid ELSE NULL END,
+    updated_by = sqlc.arg('updated_by'),
+    updated_at = now()
+FROM plan p
+WHERE pa.plan_id = p.id
+AND p.id = sqlc.arg('plan_id')
+AND p.tenant_slug = sqlc.arg('tenant_slug')
+AND pa.plan_version_id IS DISTINCT FROM sqlc.arg('to_version_id')::uuid
+AND (sqlc.narg('from_version_id')::uuid IS NULL OR pa.plan_version_id = sqlc.narg('from_version_id')::uuid)
+AND (
+    (cardinality(sqlc.arg('organization_ids')::text[]) = 0 AND cardinality(sqlc.arg('user_ids')::text[]) = 0)
+    OR pa.organization_id = ANY(sqlc.arg('organization_ids')::text[])
+    OR pa.user_id = ANY(sqlc.arg('user_ids')::text[])
+)
+AND (pa.valid_until IS NULL OR pa.valid_until = '0001-01-01 00:00:00+00' OR pa.valid_until > sqlc.arg('at'));
+
+-- name: GetAssignmentByID :one
+SELECT pa.*
+FROM plan_assignment pa
+INNER JOIN plan p ON pa.plan_id = p.id
+WHERE pa.id = sqlc.arg('id')
+AND p.tenant_slug = sqlc.arg('tenant_slug');
+
+-- name: SetAssignmentValidUntil :one
+-- moves the end of an assignment; the zero time makes it open-ended
+UPDATE plan_assignment
+SET valid_until = sqlc.arg('valid_until'),
+    updated_by = sqlc.arg('updated_by'),
+    updated_at = now()
+WHERE id = sqlc.arg('id')
+RETURNING *;
+
+-- name: DeleteAssignmentByID :exec
+DELETE FROM plan_assignment
+WHERE id = sqlc.arg('id');
+
+-- name: AssignTrialPlan :one
+-- assigns a plan as a trial to either an organization or a user
+INSERT INTO plan_assignment (
+    plan_id,
+    organization_id,
+    user_id,
+    valid_from,
+    valid_until,
+    created_by,
+    updated_by,
+    plan_version_id,
+    kind
+) VALUES (
+$1, $2, $3, $4, $5, $6, $7, $8, 'trial'
+) RETURNING *;
+
+-- name: InsertAssignmentHistory :exec
+-- records an event of an assignment that does not show as a change of its row
+INSERT INTO plan_assignment_history (
+    action,
+    plan_id,
+    organization_id,
+    user_id,
+    valid_from,
+    valid_until,
+    created_by,
+    updated_by
+) VALUES (
+$1, $2, $3, $4, $5, $6, $7, $7
+);
@@ -0,0 +1,70 @@
+-- name: CreatePlanChange :one
+insert into plan_change (
+  tenant_slug,
+  type,
+  organization_id,
+  user_id,
+  from_assignment_id,
+  from_plan_id,
+  to_assignment_id,
+  to_plan_id,
+  effective_at,
+  previous_valid_until,
+  reason,
+  created_by,
+  updated_by
+) values (
+  $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13
+) returning *;
+
+-- name: GetPlanChange :one
+-- returns a plan change with its status at the given time: canceled, pending until it takes
+-- effect, applied afterwards
+select sqlc.embed(pc),
+  (case when pc.canceled_at is not null then 'canceled' when pc.effective_at > sqlc.arg('at') then 'pending' else 'applied' end)::text as status
+from plan_change pc
+where pc.id = sqlc.arg('id')
+and pc.tenant_slug = sqlc.arg('tenant_slug');
+
+-- name: ListPlanChangesPaginated :many
+select sqlc.embed(pc),
+  (case when pc.canceled_at is not null then 'canceled' when pc.effective_at > sqlc.arg('at') then 'pending' else 'applied' end)::text as status
+from plan_change pc
+where pc.tenant_slug = sqlc.arg('tenant_slug')
+and (pc.organization_id = sqlc.narg('organization_id') or sqlc.narg('organization_id') is null)
+and (pc.user_id = sqlc.narg('user_id') or sqlc.narg('user_id') is null)
+and (
+  sqlc.narg('status')::text is null
+  or (sqlc.narg('status')::text = 'canceled' and pc.canceled_at is not null)
+  or (sqlc.narg('status')::text = 'pending' and pc.canceled_at is null and pc.effective_at > sqlc.arg('at'))
+  or (sqlc.narg('status')::text = 'applied' and pc.canceled_at is null and pc.effective_at <= sqlc.arg('at'))
+)
+order by pc.effective_at desc, pc.created_at desc
+limit sqlc.arg('limit')
+offset sqlc.arg('offset');
+
+-- name: CountPlanChanges :one
+select count(*)
+from plan_change pc
+where pc.tenant_slug = sqlc.arg('tenant_slug')
+and (pc.organization_id = sqlc.narg('organization_id') or sqlc.narg('organization_id') is null)
+and (pc.user_id = sqlc.narg('user_id') or sqlc.narg('user_id') is null)
+and (
+  sqlc.narg('status')::text is null
+  or (sqlc.narg('status')::text = 'canceled' and pc.canceled_at is not null)
+  or (sqlc.narg('status')::text = 'pending' and pc.canceled_at is null and pc.effective_at > sqlc.arg('at'))
+  or (sqlc.narg('status')::text = 'applied' and pc.canceled_at is null and pc.effective_at <= sqlc.arg('at'))
+);
+
+-- name: CancelPlanChange :one
+-- cancels a plan change that has not taken effect yet
+update plan_change
+set canceled_at = sqlc.arg('at'),
+    canceled_by = sqlc.arg('canceled_by'),
+    updated_by = sqlc.arg('canceled_by'),
+    updated_at = now()
+where id = sqlc.arg('id')
+and tenant_slug = sqlc.arg('tenant_slug')
+and canceled_at is null
+and effective_at > sqlc.arg('at')
+returning *;
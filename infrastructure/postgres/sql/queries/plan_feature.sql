@@ -35,7 +35,9 @@ select
     f.slug as feature_slug,
     f.description as feature_description,
     f.type as feature_type,
-    f.config as feature_config
+    f.config as feature_config,
+    f.meter_slugs as feature_meter_slugs,
+    f.merge_rule as feature_merge_rule
 from 
     plan_feature pf
 join
@@ -44,6 +46,7 @@ join
     plan p on pf.plan_id = p.id
 where
     pf.plan_id = $1
+    and pf.plan_version_id is null
     and p.tenant_slug = $2
     and (sqlc.narg('feature_type')::feature_enum is null or f.type = sqlc.narg('feature_type')::feature_enum)
 order by
@@ -59,6 +62,7 @@ set
 where
     plan_id = $3      
     and feature_id = $4 
+    and plan_version_id is null
 returning
     id AS plan_feature_id,
     plan_id,
@@ -72,7 +76,8 @@ returning
 -- name: DeletePlanFeature :exec
 delete from plan_feature
 where plan_id = $1
-and feature_id = $2;
+and feature_id = $2
+and plan_version_id is null;
 
 -- name: CheckPlanAndFeatureForTenant :one
 select exists (
@@ -99,8 +104,64 @@ join
 where
     p.id = sqlc.arg('plan_id')::uuid
     and f.id = sqlc.arg('feature_id')::uuid
+    and pf.plan_version_id is null
     and p.tenant_slug = $1;
 
 
 
 
+
+-- name: GetPlanFeatureByID :one
+select
+    pf.id as plan_feature_id,
+    pf.plan_id,
+    pf.feature_id,
+    pf.config,
+    pf.created_at,
+    pf.updated_at,
+    pf.created_by,
+    pf.updated_by,
+    f.name as feature_name,
+    f.slug as feature_slug,
+    f.type as feature_type,
+    f.meter_slugs as feature_meter_slugs,
+    f.merge_rule as feature_merge_rule
+from
+    plan_feature pf
+join
+    feature f on pf.feature_id = f.id
+join
+    plan p on pf.plan_id = p.id
+where
+    pf.id = $1
+    and p.tenant_slug = $2;
+
+-- name: ListPlanFeaturesByVersion :many
+select
+    pf.id as plan_feature_id,
+    pf.plan_id,
+    pf.feature_id,
+    pf.config,
+    pf.created_at,
+    pf.updated_at,
+    pf.created_by,
+    pf.updated_by,
+    f.name as feature_name,
+    f.slug as feature_slug,
+    f.description as feature_description,
+    f.type as feature_type,
+    f.config as feature_config,
+    f.meter_slugs as feature_meter_slugs,
+    f.merge_rule as feature_merge_rule
+from
+    plan_feature pf
+join
+    feature f on pf.feature_id = f.id
+join
+    plan p on pf.plan_id = p.id
+where
+    pf.plan_version_id = $1
+    and p.tenant_slug = $2
+    and (sqlc.narg('feature_type')::feature_enum is null or f.type = sqlc.narg('feature_type')::feature_enum)
+order by
+    pf.created_at desc;
@@ -0,0 +1,76 @@
+-- name: CreatePlanTrial :one
+insert into plan_trial (
+  tenant_slug,
+  assignment_id,
+  target_plan_id,
+  fallback_plan_id,
+  end_action,
+  created_by,
+  updated_by
+) values (
+  $1, $2, $3, $4, $5, $6, $7
+) returning *;
+
+-- name: GetPlanTrial :one
+select sqlc.embed(t), sqlc.embed(pa)
+from plan_trial t
+join plan_assignment pa on t.assignment_id = pa.id
+where t.id = sqlc.arg('id')
+and t.tenant_slug = sqlc.arg('tenant_slug');
+
+-- name: ListPlanTrialsPaginated :many
+-- lists trials ending soonest first
+select sqlc.embed(t), sqlc.embed(pa)
+from plan_trial t
+join plan_assignment pa on t.assignment_id = pa.id
+where t.tenant_slug = sqlc.arg('tenant_slug')
+and (pa.organization_id = sqlc.narg('organization_id') or sqlc.narg('organization_id') is null)
+and (pa.user_id = sqlc.narg('user_id') or sqlc.narg('user_id') is null)
+and (t.status = sqlc.narg('status') or sqlc.narg('status') is null)
+order by pa.valid_until, t.created_at
+limit sqlc.arg('limit')
+offset sqlc.arg('offset');
+
+-- name: CountPlanTrials :one
+select count(*)
+from plan_trial t
+join plan_assignment pa on t.assignment_id = pa.id
+where t.tenant_slug = sqlc.arg('tenant_slug')
+and (pa.organization_id = sqlc.narg('organization_id') or sqlc.narg('organization_id') is null)
+and (pa.user_id = sqlc.narg('user_id') or sqlc.narg('user_id') is null)
+and (t.status = sqlc.narg('status') or sqlc.narg('status') is null);
+
+-- name: ListDueTrials :many
+-- returns the active trials of every tenant that ended by the given time, oldest first
+select sqlc.embed(t), sqlc.embed(pa)
+from plan_trial t
+join plan_assignment pa on t.assignment_id = pa.id
+where t.status = 'active'
+and pa.valid_until <= sqlc.arg('at')
+order by pa.valid_until
+limit sqlc.arg('limit');
+
+-- name: ExtendPlanTrial :one
+-- moves the end of the assignment of an active trial
+update plan_assignment pa
+set valid_until = sqlc.arg('valid_until'),
+    updated_by = sqlc.arg('updated_by'),
+    updated_at = now()
+from plan_trial t
+where t.assignment_id = pa.id
+and t.id = sqlc.arg('id')
+and t.tenant_slug = sqlc.arg('tenant_slug')
+and t.status = 'active'
+returning pa.*;
+
+-- name: EndPlanTrial :one
+-- records the outcome of an active trial
+update plan_trial
+set status = sqlc.arg('status'),
+    resulting_assignment_id = sqlc.narg('resulting_assignment_id'),
+    ended_at = sqlc.arg('ended_at'),
+    updated_by = sqlc.arg('updated_by'),
+    updated_at = now()
+where id = sqlc.arg('id')
+and status = 'active'
+returning *;
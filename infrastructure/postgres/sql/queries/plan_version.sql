@@ -0,0 +1,97 @@
+-- name: LockPlanForVersioning :one
+-- locks the plan so concurrent publishes number their versions one after another
+select id
+from plan
+where id = $1
+and tenant_slug = $2
+for update;
+
+-- name: CreatePlanVersion :one
+insert into plan_version (
+    plan_id,
+    version,
+    description,
+    created_by
+)
+select
+    sqlc.arg('plan_id')::uuid,
+    coalesce(max(version), 0) + 1,
+    sqlc.narg('description'),
+    sqlc.arg('created_by')
+from plan_version
+where plan_id = sqlc.arg('plan_id')::uuid
+returning *;
+
+-- name: CopyDraftPlanFeaturesToVersion :exec
+-- snapshots the draft features of a plan into the given version
+insert into plan_feature (
+    plan_id,
+    feature_id,
+    config,
+    created_by,
+    updated_by,
+    plan_version_id
+)
+select
+    plan_id,
+    feature_id,
+    config,
+    created_by,
+    updated_by,
+    sqlc.arg('plan_version_id')::uuid
+from plan_feature
+where plan_id = sqlc.arg('plan_id')::uuid
+and plan_version_id is null;
+
+-- name: CopyDraftPlanFeatureQuotasToVersion :exec
+-- snapshots the quotas of the draft features of a plan into the given version
+insert into plan_feature_quota (
+    plan_feature_id,
+    limit_value,
+    reset_period,
+    custom_period_minutes,
+    action_at_limit,
+    soft_limit_value,
+    overage_allowance,
+    overage_allowance_type,
+    grace_period_minutes
+)
+select
+    v.id,
+    q.limit_value,
+    q.reset_period,
+    q.custom_period_minutes,
+    q.action_at_limit,
+    q.soft_limit_value,
+    q.overage_allowance,
+    q.overage_allowance_type,
+    q.grace_period_minutes
+from plan_feature v
+join plan_feature d on d.plan_id = v.plan_id and d.feature_id = v.feature_id and d.plan_version_id is null
+join plan_feature_quota q on q.plan_feature_id = d.id
+where v.plan_version_id = $1;
+
+-- name: ListPlanVersions :many
+select pv.*
+from plan_version pv
+join plan p on pv.plan_id = p.id
+where pv.plan_id = $1
+and p.tenant_slug = $2
+order by pv.version desc;
+
+-- name: GetPlanVersion :one
+select pv.*
+from plan_version pv
+join plan p on pv.plan_id = p.id
+where pv.plan_id = $1
+and pv.version = $2
+and p.tenant_slug = $3;
+
+-- name: GetLatestPlanVersion :one
+select pv.*
+from plan_version pv
+join plan p on pv.plan_id = p.id
+where pv.plan_id = $1
+and p.tenant_slug = $2
+order by pv.version desc
+limit 1;
@@ -4,9 +4,13 @@ insert into plan_feature_quota (
     limit_value,
     reset_period,
     custom_period_minutes,
-    action_at_limit
+    action_at_limit,
+    soft_limit_value,
+    overage_allowance,
+    overage_allowance_type,
+    grace_period_minutes
 ) values (
-    $1, $2, $3, $4, $5
+    $1, $2, $3, $4, $5, $6, $7, $8, $9
 ) returning *;
 
 -- name: GetPlanFeatureQuotaByPlanFeatureID :one
@@ -19,7 +23,11 @@ set
     limit_value = coalesce(sqlc.narg('limit_value'), limit_value),
     reset_period = coalesce(sqlc.narg('reset_period'), reset_period),
     custom_period_minutes = coalesce(sqlc.narg('custom_period_minutes'), custom_period_minutes),
-    action_at_limit = coalesce(sqlc.narg('action_at_limit'), action_at_limit)
+    action_at_limit = coalesce(sqlc.narg('action_at_limit'), action_at_limit),
+    soft_limit_value = case when sqlc.arg('clear_soft_limit_value')::boolean then null else coalesce(sqlc.narg('soft_limit_value'), soft_limit_value) end,
+    overage_allowance = case when sqlc.arg('clear_overage_allowance')::boolean then null else coalesce(sqlc.narg('overage_allowance'), overage_allowance) end,
+    overage_allowance_type = case when sqlc.arg('clear_overage_allowance')::boolean then null else coalesce(sqlc.narg('overage_allowance_type'), overage_allowance_type) end,
+    grace_period_minutes = case when sqlc.arg('clear_grace_period_minutes')::boolean then null else coalesce(sqlc.narg('grace_period_minutes'), grace_period_minutes) end
 where plan_feature_id = $1
 returning *;
 
@@ -36,3 +44,24 @@ select exists (
     and f.type = 'metered'
 );
 
+-- name: GetQuotaGraceState :one
+select * from quota_grace_state
+where tenant_slug = $1
+and subject_type = $2
+and subject_id = $3
+and feature_id = $4;
+
+-- name: UpsertQuotaGraceState :one
+insert into quota_grace_state (
+  tenant_slug,
+  subject_type,
+  subject_id,
+  feature_id,
+  allowance_used_up_at
+) values (
+  $1, $2, $3, $4, $5
+)
+on conflict (tenant_slug, subject_type, subject_id, feature_id) do update
+set allowance_used_up_at = excluded.allowance_used_up_at,
+    updated_at = now()
+returning *;
@@ -0,0 +1,142 @@
+-- name: CreateWebhookEndpoint :one
+insert into webhook_endpoint (
+  tenant_slug,
+  url,
+  description,
+  secret,
+  event_types,
+  enabled,
+  created_by,
+  updated_by
+) values (
+  $1, $2, $3, $4, $5, $6, $7, $8
+) returning *;
+
+-- name: GetWebhookEndpointByID :one
+select * from webhook_endpoint
+where id = $1
+and tenant_slug = $2;
+
+-- name: ListWebhookEndpointsPaginated :many
+select * from webhook_endpoint
+where tenant_slug = $1
+order by created_at desc
+limit $2
+offset $3;
+
+-- name: CountWebhookEndpoints :one
+select count(*) from webhook_endpoint
+where tenant_slug = $1;
+
+-- name: ListSubscribedWebhookEndpoints :many
+select * from webhook_endpoint
+where tenant_slug = $1
+and enabled
+and sqlc.arg('event_type')::text = any(event_types);
+
+-- name: UpdateWebhookEndpoint :one
+update webhook_endpoint
+set url = coalesce(sqlc.narg('url'), url),
+    description = coalesce(sqlc.narg('description'), description),
+    secret = coalesce(sqlc.narg('secret'), secret),
+    event_types = coalesce(sqlc.narg('event_types')::text[], event_types),
+    enabled = coalesce(sqlc.narg('enabled'), enabled),
+    updated_by = sqlc.arg('updated_by'),
+    updated_at = now()
+where id = sqlc.arg('id')
+and tenant_slug = sqlc.arg('tenant_slug')
+returning *;
+
+-- name: DeleteWebhookEndpoint :exec
+delete from webhook_endpoint
+where id = $1
+and tenant_slug = $2;
+
+-- name: CreateWebhookDelivery :one
+insert into webhook_delivery (
+  endpoint_id,
+  tenant_slug,
+  event_id,
+  event_type,
+  payload
+) values (
+  $1, $2, $3, $4, $5
+)
+on conflict (endpoint_id, event_id) do nothing
+returning *;
+
+-- name: GetWebhookDeliveryByEvent :one
+select * from webhook_delivery
+where endpoint_id = $1
+and event_id = $2
+and tenant_slug = $3;
+
+-- name: GetWebhookDeliveryByID :one
+select * from webhook_delivery
+where id = $1
+and tenant_slug = $2;
+
+-- name: ListWebhookDeliveriesPaginated :many
+select * from webhook_delivery
+where endpoint_id = $1
+and tenant_slug = $2
+order by created_at desc
+limit $3
+offset $4;
+
+-- name: CountWebhookDeliveries :one
+select count(*) from webhook_delivery
+where endpoint_id = $1
+and tenant_slug = $2;
+
+-- name: ClaimDueWebhookDeliveries :many
+update webhook_delivery
+set next_attempt_at = sqlc.arg('lease_until'),
+    updated_at = now()
+where id in (
+  select d.id from webhook_delivery d
+  where d.status = 'pending'
+  and d.next_attempt_at <= sqlc.arg('now')
+  order by d.next_attempt_at
+  limit sqlc.arg('batch_size')
+  for update skip locked
+)
+returning *;
+
+-- name: RecordWebhookDeliveryAttempt :one
+update webhook_delivery
+set status = $2,
+    attempts = $3,
+    next_attempt_at = $4,
+    last_attempt_at = $5,
+    last_error = $6,
+    updated_at = now()
+where id = $1
+returning *;
+
+-- name: RequeueWebhookDelivery :one
+update webhook_delivery
+set status = 'pending',
+    next_attempt_at = now(),
+    updated_at = now()
+where id = $1
+and tenant_slug = $2
+returning *;
+
+-- name: CreateWebhookDeliveryAttempt :one
+insert into webhook_delivery_attempt (
+  delivery_id,
+  attempt,
+  response_status,
+  response_body,
+  error,
+  duration_ms,
+  attempted_at
+) values (
+  $1, $2, $3, $4, $5, $6, $7
+) returning *;
+
+-- name: ListWebhookDeliveryAttempts :many
+select * from webhook_delivery_attempt
+where delivery_id = $1
+order by attempt;
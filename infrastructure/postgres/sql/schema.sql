@@ -7,20 +7,51 @@ create type aggregation_enum as enum (
     'max'
 );
 
+create type meter_type_enum as enum (
+	'standard',
+	'derived'
+);
+
+create type meter_granularity_enum as enum (
+	'second',
+	'minute',
+	'hour',
+	'day'
+);
+
+create type meter_status_enum as enum (
+	'pending',
+	'active',
+	'deleting',
+	'failed',
+	'delete_failed'
+);
+
 create table "meter" (
   id uuid primary key default uuid_generate_v4(),
 	name varchar not null,
 	slug varchar not null,
-	event_type varchar not null,
+	event_type varchar,
 	description text,
 	value_property varchar,
 	properties text[] not null,
-	aggregation aggregation_enum not null,
+	aggregation aggregation_enum,
 	tenant_slug varchar not null,
 	created_at timestamp with time zone not null default current_timestamp,
 	updated_at timestamp with time zone not null default current_timestamp,
 	created_by varchar not null,
 	updated_by varchar not null,
+	type meter_type_enum not null default 'standard',
+	formula text,
+	source_meters text[] not null default '{}',
+	granularity meter_granularity_enum not null default 'minute',
+	rollups text[] not null default '{}',
+	status meter_status_enum not null default 'active',
+	status_error text,
+	reconcile_attempts integer not null default 0,
+	filter jsonb default null,
+	event_types text[] not null default '{}',
+	value_properties jsonb default null,
 
   unique (tenant_slug, slug)
 );
@@ -30,6 +61,11 @@ create type plan_type_enum as enum (
 	'custom'
 );
 
+create type plan_kind_enum as enum (
+	'base',
+	'addon'
+);
+
 create table if not exists plan (
 	id uuid primary key default uuid_generate_v4(),
 	name varchar not null,
@@ -42,6 +78,7 @@ create table if not exists plan (
 	archived_at timestamp with time zone default null,
 	created_by varchar not null,
 	updated_by varchar not null,
+	kind plan_kind_enum not null default 'base',
 
 	unique (tenant_slug, slug)
 );
@@ -51,6 +88,11 @@ create type feature_enum as enum (
 	'metered'
 );
 
+create type quota_merge_rule_enum as enum (
+	'sum',
+	'max'
+);
+
 create table if not exists feature (
 	id uuid primary key default uuid_generate_v4(),
 	name varchar not null,
@@ -63,9 +105,27 @@ create table if not exists feature (
 	updated_at timestamp with time zone default now(),
 	created_by varchar not null,
 	updated_by varchar not null,
+	meter_slugs text[] not null default '{}',
+	merge_rule quota_merge_rule_enum not null default 'sum',
 	unique (tenant_slug, slug)
 );
 
+create table if not exists plan_version (
+	id uuid primary key default uuid_generate_v4(),
+	plan_id uuid not null references plan(id) on delete cascade,
+	version integer not null,
+	description text default null,
+	created_at timestamp with time zone not null default now(),
+	created_by varchar not null,
+
+	unique (plan_id, version)
+);
+
+create type plan_assignment_kind_enum as enum (
+	'standard',
+	'trial'
+);
+
 create table if not exists plan_assignment (
 	id uuid primary key default uuid_generate_v4(),
 	plan_id uuid not null,
@@ -77,6 +137,9 @@ create table if not exists plan_assignment (
 	updated_at timestamp with time zone not null default current_timestamp,
 	created_by varchar not null,
 	updated_by varchar not null,
+	plan_version_id uuid default null references plan_version(id) on delete cascade,
+	pending_plan_version_id uuid default null references plan_version(id) on delete set null,
+	kind plan_assignment_kind_enum not null default 'standard',
 
 	FOREIGN KEY (plan_id) REFERENCES plan(id)
 	ON DELETE CASCADE,
@@ -96,7 +159,8 @@ create table if not exists plan_feature (
 	updated_at timestamp with time zone not null default now(),
   created_by varchar not null,
   updated_by varchar not null,
-  config jsonb default null
+  config jsonb default null,
+  plan_version_id uuid default null references plan_version(id) on delete cascade
 );
 
 
@@ -116,6 +180,11 @@ create type metered_action_at_limit_enum as enum (
   'throttle'
 );
 
+create type quota_overage_type_enum as enum (
+  'absolute',
+  'percent'
+);
+
 create table if not exists plan_feature_quota (
   id uuid primary key default uuid_generate_v4(),
   plan_feature_id uuid not null references plan_feature(id) on delete cascade,
@@ -124,7 +193,11 @@ create table if not exists plan_feature_quota (
   custom_period_minutes bigint default null,
   action_at_limit metered_action_at_limit_enum not null default 'none',
   created_at timestamp with time zone not null default now(),
-  updated_at timestamp with time zone not null default now()
+  updated_at timestamp with time zone not null default now(),
+  soft_limit_value bigint default null,
+  overage_allowance bigint default null,
+  overage_allowance_type quota_overage_type_enum default null,
+  grace_period_minutes bigint default null
 );
 
 create table if not exists plan_assignment_history (
@@ -140,3 +213,192 @@ create table if not exists plan_assignment_history (
     created_by varchar not null,
     updated_by varchar not null
 );
+
+create type subject_type_enum as enum (
+	'organization',
+	'user'
+);
+
+create type alert_threshold_type_enum as enum (
+	'absolute',
+	'percent'
+);
+
+create type alert_status_enum as enum (
+	'ok',
+	'firing',
+	'notifying'
+);
+
+create table if not exists alert_rule (
+	id uuid primary key default uuid_generate_v4(),
+	tenant_slug varchar not null,
+	name varchar not null,
+	meter_slug varchar default null,
+	plan_feature_id uuid default null references plan_feature(id) on delete cascade,
+	subject_type subject_type_enum not null default 'organization',
+	subject_ids text[] not null default '{}',
+	threshold_type alert_threshold_type_enum not null,
+	threshold double precision not null,
+	window_minutes bigint default null,
+	enabled boolean not null default true,
+	created_at timestamp with time zone not null default now(),
+	updated_at timestamp with time zone not null default now(),
+	created_by varchar not null,
+	updated_by varchar not null,
+	constraint alert_rule_target check ((meter_slug is null) <> (plan_feature_id is null))
+);
+
+create table if not exists alert_state (
+	id uuid primary key default uuid_generate_v4(),
+	alert_rule_id uuid not null references alert_rule(id) on delete cascade,
+	subject_id varchar not null,
+	status alert_status_enum not null default 'ok',
+	value double precision not null default 0,
+	threshold_value double precision not null default 0,
+	period_start timestamp with time zone default null,
+	fired_at timestamp with time zone default null,
+	resolved_at timestamp with time zone default null,
+	evaluated_at timestamp with time zone not null default now(),
+	unique (alert_rule_id, subject_id)
+);
+
+create type webhook_delivery_status_enum as enum (
+	'pending',
+	'succeeded',
+	'failed'
+);
+
+create table if not exists webhook_endpoint (
+	id uuid primary key default uuid_generate_v4(),
+	tenant_slug varchar not null,
+	url varchar not null,
+	description varchar default null,
+	secret varchar not null,
+	event_types text[] not null,
+	enabled boolean not null default true,
+	created_at timestamp with time zone not null default now(),
+	updated_at timestamp with time zone not null default now(),
+	created_by varchar not null,
+	updated_by varchar not null
+);
+
+create table if not exists webhook_delivery (
+	id uuid primary key default uuid_generate_v4(),
+	endpoint_id uuid not null references webhook_endpoint(id) on delete cascade,
+	tenant_slug varchar not null,
+	event_id uuid not null,
+	event_type varchar not null,
+	payload jsonb not null,
+	status webhook_delivery_status_enum not null default 'pending',
+	attempts integer not null default 0,
+	next_attempt_at timestamp with time zone not null default now(),
+	last_attempt_at timestamp with time zone default null,
+	last_error varchar default null,
+	created_at timestamp with time zone not null default now(),
+	updated_at timestamp with time zone not null default now(),
+	constraint webhook_delivery_endpoint_event_key unique (endpoint_id, event_id)
+);
+
+create table if not exists webhook_delivery_attempt (
+	id uuid primary key default uuid_generate_v4(),
+	delivery_id uuid not null references webhook_delivery(id) on delete cascade,
+	attempt integer not null,
+	response_status integer default null,
+	response_body varchar default null,
+	error varchar default null,
+	duration_ms bigint not null,
+	attempted_at timestamp with time zone not null default now()
+);
+
+create type entitlement_override_effect_enum as enum (
+	'grant',
+	'revoke'
+);
+
+create table if not exists entitlement_override (
+	id uuid primary key default uuid_generate_v4(),
+	tenant_slug varchar not null,
+	subject_type subject_type_enum not null,
+	subject_id varchar not null,
+	plan_assignment_id uuid default null references plan_assignment(id) on delete cascade,
+	feature_id uuid not null references feature(id) on delete cascade,
+	effect entitlement_override_effect_enum not null,
+	limit_value bigint default null,
+	reset_period metered_reset_period_enum default null,
+	custom_period_minutes bigint default null,
+	action_at_limit metered_action_at_limit_enum default null,
+	reason text default null,
+	expires_at timestamp with time zone default null,
+	created_at timestamp with time zone not null default now(),
+	updated_at timestamp with time zone not null default now(),
+	created_by varchar not null,
+	updated_by varchar not null
+);
+
+create type plan_change_type_enum as enum (
+	'upgrade',
+	'downgrade',
+	'cancellation'
+);
+
+create table if not exists plan_change (
+	id uuid primary key default uuid_generate_v4(),
+	tenant_slug varchar not null,
+	type plan_change_type_enum not null,
+	organization_id varchar default null,
+	user_id varchar default null,
+	from_assignment_id uuid not null references plan_assignment(id) on delete cascade,
+	from_plan_id uuid not null references plan(id) on delete cascade,
+	to_assignment_id uuid default null references plan_assignment(id) on delete set null,
+	to_plan_id uuid default null references plan(id) on delete cascade,
+	effective_at timestamp with time zone not null,
+	previous_valid_until timestamp with time zone not null,
+	reason text default null,
+	canceled_at timestamp with time zone default null,
+	canceled_by varchar default null,
+	created_at timestamp with time zone not null default now(),
+	updated_at timestamp with time zone not null default now(),
+	created_by varchar not null,
+	updated_by varchar not null
+);
+
+create type trial_end_action_enum as enum (
+	'convert',
+	'fallback',
+	'expire'
+);
+
+create type trial_status_enum as enum (
+	'active',
+	'converted',
+	'fell_back',
+	'expired'
+);
+
+create table if not exists plan_trial (
+	id uuid primary key default uuid_generate_v4(),
+	tenant_slug varchar not null,
+	assignment_id uuid not null unique references plan_assignment(id) on delete cascade,
+	target_plan_id uuid not null references plan(id) on delete cascade,
+	fallback_plan_id uuid default null references plan(id) on delete set null,
+	end_action trial_end_action_enum not null default 'convert',
+	status trial_status_enum not null default 'active',
+	resulting_assignment_id uuid default null references plan_assignment(id) on delete set null,
+	ended_at timestamp with time zone default null,
+	created_at timestamp with time zone not null default now(),
+	updated_at timestamp with time zone not null default now(),
+	created_by varchar not null,
+	updated_by varchar not null
+);
+
+create table if not exists quota_grace_state (
+	id uuid primary key default uuid_generate_v4(),
+	tenant_slug varchar not null,
+	subject_type subject_type_enum not null,
+	subject_id varchar not null,
+	feature_id uuid not null references feature(id) on delete cascade,
+	allowance_used_up_at timestamp with time zone not null,
+	updated_at timestamp with time zone not null default now(),
+	unique (tenant_slug, subject_type, subject_id, feature_id)
+);
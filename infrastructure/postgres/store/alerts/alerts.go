@@ -0,0 +1,84 @@
+package alerts
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/redcardinal-io/metering/application/repositories"
+	"github.com/redcardinal-io/metering/domain/models"
+	"github.com/redcardinal-io/metering/domain/pkg/logger"
+	"github.com/redcardinal-io/metering/infrastructure/postgres/gen"
+)
+
+type PgAlertStoreRepository struct {
+	q      *gen.Queries
+	logger *logger.Logger
+}
+
+// NewPostgresAlertStoreRepository creates a new AlertStoreRepository backed by PostgreSQL using the provided database connection and logger.
+func NewPostgresAlertStoreRepository(db any, logger *logger.Logger) repositories.AlertStoreRepository {
+	return &PgAlertStoreRepository{
+		q:      gen.New(db.(*pgxpool.Pool)),
+		logger: logger,
+	}
+}
+
+// toAlertRuleModel converts a gen.AlertRule database record into a domain models.AlertRule object.
+func toAlertRuleModel(m gen.AlertRule) *models.AlertRule {
+	rule := &models.AlertRule{
+		TenantSlug:    m.TenantSlug,
+		Name:          m.Name,
+		MeterSlug:     m.MeterSlug.String,
+		SubjectType:   models.SubjectType(m.SubjectType),
+		SubjectIDs:    m.SubjectIds,
+		ThresholdType: models.AlertThresholdType(m.ThresholdType),
+		Threshold:     m.Threshold,
+		Enabled:       m.Enabled,
+		Base: models.Base{
+			ID:        m.ID.Bytes,
+			CreatedAt: m.CreatedAt.Time,
+			CreatedBy: m.CreatedBy,
+			UpdatedAt: m.UpdatedAt.Time,
+			UpdatedBy: m.UpdatedBy,
+		},
+	}
+	if m.PlanFeatureID.Valid {
+		planFeatureID := uuid.UUID(m.PlanFeatureID.Bytes)
+		rule.PlanFeatureID = &planFeatureID
+	}
+	if m.WindowMinutes.Valid {
+		rule.WindowMinutes = &m.WindowMinutes.Int64
+	}
+	return rule
+}
+
+// toAlertStateModel converts a gen.AlertState database record into a domain models.AlertState object.
+func toAlertStateModel(m gen.AlertState) *models.AlertState {
+	return &models.AlertState{
+		AlertRuleID:    m.AlertRuleID.Bytes,
+		SubjectID:      m.SubjectID,
+		Status:         models.AlertStatus(m.Status),
+		Value:          m.Value,
+		ThresholdValue: m.ThresholdValue,
+		PeriodStart:    fromTimestamptz(m.PeriodStart),
+		FiredAt:        fromTimestamptz(m.FiredAt),
+		ResolvedAt:     fromTimestamptz(m.ResolvedAt),
+		EvaluatedAt:    m.EvaluatedAt.Time,
+	}
+}
+
+func fromTimestamptz(t pgtype.Timestamptz) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &t.Time
+}
+
+func toTimestamptz(t *time.Time) pgtype.Timestamptz {
+	if t == nil {
+		return pgtype.Timestamptz{}
+	}
+	return pgtype.Timestamptz{Time: *t, Valid: true}
+}
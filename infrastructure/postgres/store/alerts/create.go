@@ -0,0 +1,50 @@
+package alerts
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/redcardinal-io/metering/domain/models"
+	"github.com/redcardinal-io/metering/domain/pkg/constants"
+	"github.com/redcardinal-io/metering/infrastructure/postgres"
+	"github.com/redcardinal-io/metering/infrastructure/postgres/gen"
+	"go.uber.org/zap"
+)
+
+func (p *PgAlertStoreRepository) CreateAlertRule(ctx context.Context, arg models.CreateAlertRuleInput) (*models.AlertRule, error) {
+	tenantSlug := ctx.Value(constants.TenantSlugKey).(string)
+
+	subjectIDs := arg.SubjectIDs
+	if subjectIDs == nil {
+		subjectIDs = []string{}
+	}
+	planFeatureID := pgtype.UUID{}
+	if arg.PlanFeatureID != nil {
+		planFeatureID = pgtype.UUID{Bytes: *arg.PlanFeatureID, Valid: true}
+	}
+	windowMinutes := pgtype.Int8{}
+	if arg.WindowMinutes != nil {
+		windowMinutes = pgtype.Int8{Int64: *arg.WindowMinutes, Valid: true}
+	}
+
+	m, err := p.q.CreateAlertRule(ctx, gen.CreateAlertRuleParams{
+		TenantSlug:    tenantSlug,
+		Name:          arg.Name,
+		MeterSlug:     pgtype.Text{String: arg.MeterSlug, Valid: arg.MeterSlug != ""},
+		PlanFeatureID: planFeatureID,
+		SubjectType:   gen.SubjectTypeEnum(arg.SubjectType),
+		SubjectIds:    subjectIDs,
+		ThresholdType: gen.AlertThresholdTypeEnum(arg.ThresholdType),
+		Threshold:     arg.Threshold,
+		WindowMinutes: windowMinutes,
+		Enabled:       arg.Enabled,
+		CreatedBy:     arg.CreatedBy,
+		UpdatedBy:     arg.CreatedBy,
+	})
+	if err != nil {
+		p.logger.Error("failed to create alert rule", zap.Error(err))
+		return nil, postgres.MapError(err, "Postgres.CreateAlertRule")
+	}
+
+	return toAlertRuleModel(m), nil
+}
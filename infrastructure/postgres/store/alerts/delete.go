@@ -0,0 +1,26 @@
+package alerts
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/redcardinal-io/metering/domain/pkg/constants"
+	"github.com/redcardinal-io/metering/infrastructure/postgres"
+	"github.com/redcardinal-io/metering/infrastructure/postgres/gen"
+	"go.uber.org/zap"
+)
+
+func (p *PgAlertStoreRepository) DeleteAlertRule(ctx context.Context, id uuid.UUID) error {
+	tenantSlug := ctx.Value(constants.TenantSlugKey).(string)
+	err := p.q.DeleteAlertRule(ctx, gen.DeleteAlertRuleParams{
+		ID:         pgtype.UUID{Bytes: id, Valid: true},
+		TenantSlug: tenantSlug,
+	})
+	if err != nil {
+		p.logger.Error("failed to delete alert rule", zap.Error(err))
+		return postgres.MapError(err, "Postgres.DeleteAlertRule")
+	}
+
+	return nil
+}
@@ -0,0 +1,25 @@
+package alerts
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/redcardinal-io/metering/domain/models"
+	"github.com/redcardinal-io/metering/domain/pkg/constants"
+	"github.com/redcardinal-io/metering/infrastructure/postgres"
+	"github.com/redcardinal-io/metering/infrastructure/postgres/gen"
+)
+
+func (p *PgAlertStoreRepository) GetAlertRule(ctx context.Context, id uuid.UUID) (*models.AlertRule, error) {
+	tenantSlug := ctx.Value(constants.TenantSlugKey).(string)
+	m, err := p.q.GetAlertRuleByID(ctx, gen.GetAlertRuleByIDParams{
+		ID:         pgtype.UUID{Bytes: id, Valid: true},
+		TenantSlug: tenantSlug,
+	})
+	if err != nil {
+		return nil, postgres.MapError(err, "Postgres.GetAlertRule")
+	}
+
+	return toAlertRuleModel(m), nil
+}
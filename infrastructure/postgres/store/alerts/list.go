@@ -0,0 +1,54 @@
+package alerts
+
+import (
+	"context"
+
+	"github.com/redcardinal-io/metering/domain/models"
+	"github.com/redcardinal-io/metering/domain/pkg/constants"
+	"github.com/redcardinal-io/metering/domain/pkg/pagination"
+	"github.com/redcardinal-io/metering/infrastructure/postgres"
+	"github.com/redcardinal-io/metering/infrastructure/postgres/gen"
+	"go.uber.org/zap"
+)
+
+func (p *PgAlertStoreRepository) ListAlertRules(ctx context.Context, page pagination.Pagination) (*pagination.PaginationView[models.AlertRule], error) {
+	tenantSlug := ctx.Value(constants.TenantSlugKey).(string)
+	m, err := p.q.ListAlertRulesPaginated(ctx, gen.ListAlertRulesPaginatedParams{
+		Limit:      int32(page.Limit),
+		Offset:     int32(page.GetOffset()),
+		TenantSlug: tenantSlug,
+	})
+	if err != nil {
+		p.logger.Error("Error listing alert rules: ", zap.Error(err))
+		return nil, postgres.MapError(err, "Postgres.ListAlertRules")
+	}
+
+	rules := make([]models.AlertRule, 0, len(m))
+	for _, rule := range m {
+		rules = append(rules, *toAlertRuleModel(rule))
+	}
+
+	count, err := p.q.CountAlertRules(ctx, tenantSlug)
+	if err != nil {
+		p.logger.Error("Error counting alert rules: ", zap.Error(err))
+		return nil, postgres.MapError(err, "Postgres.CountAlertRules")
+	}
+
+	result := pagination.FormatWith(page, int(count), rules)
+
+	return &result, nil
+}
+
+func (p *PgAlertStoreRepository) ListEnabledAlertRules(ctx context.Context) ([]*models.AlertRule, error) {
+	m, err := p.q.ListEnabledAlertRules(ctx)
+	if err != nil {
+		p.logger.Error("Error listing enabled alert rules: ", zap.Error(err))
+		return nil, postgres.MapError(err, "Postgres.ListEnabledAlertRules")
+	}
+
+	rules := make([]*models.AlertRule, 0, len(m))
+	for _, rule := range m {
+		rules = append(rules, toAlertRuleModel(rule))
+	}
+	return rules, nil
+}
@@ -0,0 +1,48 @@
+package alerts
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/redcardinal-io/metering/domain/models"
+	"github.com/redcardinal-io/metering/domain/pkg/constants"
+	"github.com/redcardinal-io/metering/infrastructure/postgres"
+	"github.com/redcardinal-io/metering/infrastructure/postgres/gen"
+)
+
+func (p *PgAlertStoreRepository) ListAlertStates(ctx context.Context, ruleID uuid.UUID) ([]models.AlertState, error) {
+	tenantSlug := ctx.Value(constants.TenantSlugKey).(string)
+	rows, err := p.q.ListAlertStates(ctx, gen.ListAlertStatesParams{
+		AlertRuleID: pgtype.UUID{Bytes: ruleID, Valid: true},
+		TenantSlug:  tenantSlug,
+	})
+	if err != nil {
+		return nil, postgres.MapError(err, "Postgres.ListAlertStates")
+	}
+
+	states := make([]models.AlertState, 0, len(rows))
+	for _, row := range rows {
+		states = append(states, *toAlertStateModel(row))
+	}
+	return states, nil
+}
+
+func (p *PgAlertStoreRepository) SaveAlertState(ctx context.Context, state models.AlertState) (*models.AlertState, error) {
+	m, err := p.q.UpsertAlertState(ctx, gen.UpsertAlertStateParams{
+		AlertRuleID:    pgtype.UUID{Bytes: state.AlertRuleID, Valid: true},
+		SubjectID:      state.SubjectID,
+		Status:         gen.AlertStatusEnum(state.Status),
+		Value:          state.Value,
+		ThresholdValue: state.ThresholdValue,
+		PeriodStart:    toTimestamptz(state.PeriodStart),
+		FiredAt:        toTimestamptz(state.FiredAt),
+		ResolvedAt:     toTimestamptz(state.ResolvedAt),
+		EvaluatedAt:    pgtype.Timestamptz{Time: state.EvaluatedAt, Valid: true},
+	})
+	if err != nil {
+		return nil, postgres.MapError(err, "Postgres.SaveAlertState")
+	}
+
+	return toAlertStateModel(m), nil
+}
@@ -0,0 +1,42 @@
+package alerts
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/redcardinal-io/metering/domain/models"
+	"github.com/redcardinal-io/metering/domain/pkg/constants"
+	"github.com/redcardinal-io/metering/infrastructure/postgres"
+	"github.com/redcardinal-io/metering/infrastructure/postgres/gen"
+)
+
+func (p *PgAlertStoreRepository) UpdateAlertRule(ctx context.Context, id uuid.UUID, arg models.UpdateAlertRuleInput) (*models.AlertRule, error) {
+	tenantSlug := ctx.Value(constants.TenantSlugKey).(string)
+
+	params := gen.UpdateAlertRuleParams{
+		ID:         pgtype.UUID{Bytes: id, Valid: true},
+		TenantSlug: tenantSlug,
+		SubjectIds: arg.SubjectIDs,
+		UpdatedBy:  arg.UpdatedBy,
+	}
+	if arg.Name != nil {
+		params.Name = pgtype.Text{String: *arg.Name, Valid: true}
+	}
+	if arg.Threshold != nil {
+		params.Threshold = pgtype.Float8{Float64: *arg.Threshold, Valid: true}
+	}
+	if arg.WindowMinutes != nil {
+		params.WindowMinutes = pgtype.Int8{Int64: *arg.WindowMinutes, Valid: true}
+	}
+	if arg.Enabled != nil {
+		params.Enabled = pgtype.Bool{Bool: *arg.Enabled, Valid: true}
+	}
+
+	m, err := p.q.UpdateAlertRule(ctx, params)
+	if err != nil {
+		return nil, postgres.MapError(err, "Postgres.UpdateAlertRule")
+	}
+
+	return toAlertRuleModel(m), nil
+}
@@ -0,0 +1,43 @@
+package entitlementoverrides
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/redcardinal-io/metering/domain/models"
+	"github.com/redcardinal-io/metering/domain/pkg/constants"
+	"github.com/redcardinal-io/metering/infrastructure/postgres"
+	"github.com/redcardinal-io/metering/infrastructure/postgres/gen"
+	"go.uber.org/zap"
+)
+
+func (p *PgEntitlementOverrideStoreRepository) CreateEntitlementOverride(ctx context.Context, arg models.CreateEntitlementOverrideInput) (*models.EntitlementOverride, error) {
+	tenantSlug := ctx.Value(constants.TenantSlugKey).(string)
+
+	params := gen.CreateEntitlementOverrideParams{
+		TenantSlug:          tenantSlug,
+		SubjectType:         gen.SubjectTypeEnum(arg.SubjectType),
+		SubjectID:           arg.SubjectID,
+		FeatureID:           pgtype.UUID{Bytes: arg.FeatureID, Valid: true},
+		Effect:              gen.EntitlementOverrideEffectEnum(arg.Effect),
+		LimitValue:          toInt8(arg.LimitValue),
+		ResetPeriod:         toResetPeriod(arg.ResetPeriod),
+		CustomPeriodMinutes: toInt8(arg.CustomPeriodMinutes),
+		ActionAtLimit:       toActionAtLimit(arg.ActionAtLimit),
+		Reason:              pgtype.Text{String: arg.Reason, Valid: arg.Reason != ""},
+		ExpiresAt:           toTimestamptz(arg.ExpiresAt),
+		CreatedBy:           arg.CreatedBy,
+		UpdatedBy:           arg.CreatedBy,
+	}
+	if arg.PlanAssignmentID != nil {
+		params.PlanAssignmentID = pgtype.UUID{Bytes: *arg.PlanAssignmentID, Valid: true}
+	}
+
+	m, err := p.q.CreateEntitlementOverride(ctx, params)
+	if err != nil {
+		p.logger.Error("failed to create entitlement override", zap.Error(err))
+		return nil, postgres.MapError(err, "Postgres.CreateEntitlementOverride")
+	}
+
+	return p.GetEntitlementOverride(ctx, m.ID.Bytes)
+}
@@ -0,0 +1,26 @@
+package entitlementoverrides
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/redcardinal-io/metering/domain/pkg/constants"
+	"github.com/redcardinal-io/metering/infrastructure/postgres"
+	"github.com/redcardinal-io/metering/infrastructure/postgres/gen"
+	"go.uber.org/zap"
+)
+
+func (p *PgEntitlementOverrideStoreRepository) DeleteEntitlementOverride(ctx context.Context, id uuid.UUID) error {
+	tenantSlug := ctx.Value(constants.TenantSlugKey).(string)
+	err := p.q.DeleteEntitlementOverride(ctx, gen.DeleteEntitlementOverrideParams{
+		ID:         pgtype.UUID{Bytes: id, Valid: true},
+		TenantSlug: tenantSlug,
+	})
+	if err != nil {
+		p.logger.Error("failed to delete entitlement override", zap.Error(err))
+		return postgres.MapError(err, "Postgres.DeleteEntitlementOverride")
+	}
+
+	return nil
+}
@@ -0,0 +1,25 @@
+package entitlementoverrides
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/redcardinal-io/metering/domain/models"
+	"github.com/redcardinal-io/metering/domain/pkg/constants"
+	"github.com/redcardinal-io/metering/infrastructure/postgres"
+	"github.com/redcardinal-io/metering/infrastructure/postgres/gen"
+)
+
+func (p *PgEntitlementOverrideStoreRepository) GetEntitlementOverride(ctx context.Context, id uuid.UUID) (*models.EntitlementOverride, error) {
+	tenantSlug := ctx.Value(constants.TenantSlugKey).(string)
+	row, err := p.q.GetEntitlementOverride(ctx, gen.GetEntitlementOverrideParams{
+		ID:         pgtype.UUID{Bytes: id, Valid: true},
+		TenantSlug: tenantSlug,
+	})
+	if err != nil {
+		return nil, postgres.MapError(err, "Postgres.GetEntitlementOverride")
+	}
+
+	return toEntitlementOverrideModel(row), nil
+}
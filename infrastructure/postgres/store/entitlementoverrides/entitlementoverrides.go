@@ -0,0 +1,95 @@
+package entitlementoverrides
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/redcardinal-io/metering/application/repositories"
+	"github.com/redcardinal-io/metering/domain/models"
+	"github.com/redcardinal-io/metering/domain/pkg/logger"
+	"github.com/redcardinal-io/metering/infrastructure/postgres/gen"
+)
+
+type PgEntitlementOverrideStoreRepository struct {
+	q      *gen.Queries
+	logger *logger.Logger
+}
+
+// NewPostgresEntitlementOverrideStoreRepository creates a new EntitlementOverrideStoreRepository backed by PostgreSQL using the provided database connection and logger.
+func NewPostgresEntitlementOverrideStoreRepository(db any, logger *logger.Logger) repositories.EntitlementOverrideStoreRepository {
+	return &PgEntitlementOverrideStoreRepository{
+		q:      gen.New(db.(*pgxpool.Pool)),
+		logger: logger,
+	}
+}
+
+// toEntitlementOverrideModel converts an entitlement override read together with its feature into a domain models.EntitlementOverride object.
+func toEntitlementOverrideModel(row gen.GetEntitlementOverrideRow) *models.EntitlementOverride {
+	m := row.EntitlementOverride
+	override := &models.EntitlementOverride{
+		TenantSlug:    m.TenantSlug,
+		SubjectType:   models.SubjectType(m.SubjectType),
+		SubjectID:     m.SubjectID,
+		FeatureID:     m.FeatureID.Bytes,
+		FeatureSlug:   row.FeatureSlug,
+		FeatureName:   row.FeatureName,
+		FeatureType:   models.FeatureTypeEnum(row.FeatureType),
+		MeterSlugs:    row.FeatureMeterSlugs,
+		MergeRule:     models.QuotaMergeRule(row.FeatureMergeRule),
+		Effect:        models.EntitlementOverrideEffect(m.Effect),
+		ResetPeriod:   models.MeteredResetPeriod(m.ResetPeriod.MeteredResetPeriodEnum),
+		ActionAtLimit: models.MeteredActionAtLimit(m.ActionAtLimit.MeteredActionAtLimitEnum),
+		Reason:        m.Reason.String,
+		Base: models.Base{
+			ID:        m.ID.Bytes,
+			CreatedAt: m.CreatedAt.Time,
+			CreatedBy: m.CreatedBy,
+			UpdatedAt: m.UpdatedAt.Time,
+			UpdatedBy: m.UpdatedBy,
+		},
+	}
+	if m.PlanAssignmentID.Valid {
+		planAssignmentID := uuid.UUID(m.PlanAssignmentID.Bytes)
+		override.PlanAssignmentID = &planAssignmentID
+	}
+	if m.LimitValue.Valid {
+		override.LimitValue = &m.LimitValue.Int64
+	}
+	if m.CustomPeriodMinutes.Valid {
+		override.CustomPeriodMinutes = &m.CustomPeriodMinutes.Int64
+	}
+	if m.ExpiresAt.Valid {
+		override.ExpiresAt = &m.ExpiresAt.Time
+	}
+	return override
+}
+
+func toInt8(v *int64) pgtype.Int8 {
+	if v == nil {
+		return pgtype.Int8{}
+	}
+	return pgtype.Int8{Int64: *v, Valid: true}
+}
+
+func toTimestamptz(t *time.Time) pgtype.Timestamptz {
+	if t == nil {
+		return pgtype.Timestamptz{}
+	}
+	return pgtype.Timestamptz{Time: *t, Valid: true}
+}
+
+func toResetPeriod(period models.MeteredResetPeriod) gen.NullMeteredResetPeriodEnum {
+	return gen.NullMeteredResetPeriodEnum{
+		MeteredResetPeriodEnum: gen.MeteredResetPeriodEnum(period),
+		Valid:                  period != "",
+	}
+}
+
+func toActionAtLimit(action models.MeteredActionAtLimit) gen.NullMeteredActionAtLimitEnum {
+	return gen.NullMeteredActionAtLimitEnum{
+		MeteredActionAtLimitEnum: gen.MeteredActionAtLimitEnum(action),
+		Valid:                    action != "",
+	}
+}
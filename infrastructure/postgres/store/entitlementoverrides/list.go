@@ -0,0 +1,74 @@
+package entitlementoverrides
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/redcardinal-io/metering/domain/models"
+	"github.com/redcardinal-io/metering/domain/pkg/constants"
+	"github.com/redcardinal-io/metering/domain/pkg/pagination"
+	"github.com/redcardinal-io/metering/infrastructure/postgres"
+	"github.com/redcardinal-io/metering/infrastructure/postgres/gen"
+	"go.uber.org/zap"
+)
+
+func (p *PgEntitlementOverrideStoreRepository) ListEntitlementOverrides(ctx context.Context, arg models.QueryEntitlementOverrideInput, page pagination.Pagination) (*pagination.PaginationView[models.EntitlementOverride], error) {
+	tenantSlug := ctx.Value(constants.TenantSlugKey).(string)
+	at := pgtype.Timestamptz{Time: arg.At, Valid: true}
+
+	rows, err := p.q.ListEntitlementOverridesPaginated(ctx, gen.ListEntitlementOverridesPaginatedParams{
+		TenantSlug:     tenantSlug,
+		SubjectType:    gen.SubjectTypeEnum(arg.SubjectType),
+		SubjectID:      arg.SubjectID,
+		IncludeExpired: arg.IncludeExpired,
+		At:             at,
+		Limit:          int32(page.Limit),
+		Offset:         int32(page.GetOffset()),
+	})
+	if err != nil {
+		p.logger.Error("Error listing entitlement overrides: ", zap.Error(err))
+		return nil, postgres.MapError(err, "Postgres.ListEntitlementOverrides")
+	}
+
+	overrides := make([]models.EntitlementOverride, 0, len(rows))
+	for _, row := range rows {
+		overrides = append(overrides, *toEntitlementOverrideModel(gen.GetEntitlementOverrideRow(row)))
+	}
+
+	count, err := p.q.CountEntitlementOverrides(ctx, gen.CountEntitlementOverridesParams{
+		TenantSlug:     tenantSlug,
+		SubjectType:    gen.SubjectTypeEnum(arg.SubjectType),
+		SubjectID:      arg.SubjectID,
+		IncludeExpired: arg.IncludeExpired,
+		At:             at,
+	})
+	if err != nil {
+		p.logger.Error("Error counting entitlement overrides: ", zap.Error(err))
+		return nil, postgres.MapError(err, "Postgres.CountEntitlementOverrides")
+	}
+
+	result := pagination.FormatWith(page, int(count), overrides)
+
+	return &result, nil
+}
+
+func (p *PgEntitlementOverrideStoreRepository) ListActiveEntitlementOverrides(ctx context.Context, subjectType models.SubjectType, subjectID string, at time.Time) ([]models.EntitlementOverride, error) {
+	tenantSlug := ctx.Value(constants.TenantSlugKey).(string)
+	rows, err := p.q.ListActiveEntitlementOverrides(ctx, gen.ListActiveEntitlementOverridesParams{
+		TenantSlug:  tenantSlug,
+		SubjectType: gen.SubjectTypeEnum(subjectType),
+		SubjectID:   subjectID,
+		At:          pgtype.Timestamptz{Time: at, Valid: true},
+	})
+	if err != nil {
+		p.logger.Error("Error listing active entitlement overrides: ", zap.Error(err))
+		return nil, postgres.MapError(err, "Postgres.ListActiveEntitlementOverrides")
+	}
+
+	overrides := make([]models.EntitlementOverride, 0, len(rows))
+	for _, row := range rows {
+		overrides = append(overrides, *toEntitlementOverrideModel(gen.GetEntitlementOverrideRow(row)))
+	}
+	return overrides, nil
+}
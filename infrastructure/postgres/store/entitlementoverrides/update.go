@@ -0,0 +1,37 @@
+package entitlementoverrides
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/redcardinal-io/metering/domain/models"
+	"github.com/redcardinal-io/metering/domain/pkg/constants"
+	"github.com/redcardinal-io/metering/infrastructure/postgres"
+	"github.com/redcardinal-io/metering/infrastructure/postgres/gen"
+)
+
+func (p *PgEntitlementOverrideStoreRepository) UpdateEntitlementOverride(ctx context.Context, id uuid.UUID, arg models.UpdateEntitlementOverrideInput) (*models.EntitlementOverride, error) {
+	tenantSlug := ctx.Value(constants.TenantSlugKey).(string)
+
+	params := gen.UpdateEntitlementOverrideParams{
+		ID:                  pgtype.UUID{Bytes: id, Valid: true},
+		TenantSlug:          tenantSlug,
+		LimitValue:          toInt8(arg.LimitValue),
+		ResetPeriod:         toResetPeriod(arg.ResetPeriod),
+		CustomPeriodMinutes: toInt8(arg.CustomPeriodMinutes),
+		ActionAtLimit:       toActionAtLimit(arg.ActionAtLimit),
+		ClearExpiresAt:      arg.ClearExpiresAt,
+		ExpiresAt:           toTimestamptz(arg.ExpiresAt),
+		UpdatedBy:           arg.UpdatedBy,
+	}
+	if arg.Reason != nil {
+		params.Reason = pgtype.Text{String: *arg.Reason, Valid: true}
+	}
+
+	if _, err := p.q.UpdateEntitlementOverride(ctx, params); err != nil {
+		return nil, postgres.MapError(err, "Postgres.UpdateEntitlementOverride")
+	}
+
+	return p.GetEntitlementOverride(ctx, id)
+}
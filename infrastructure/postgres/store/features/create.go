@@ -21,6 +21,16 @@ func (p *PgFeatureRepository) CreateFeature(ctx context.Context, arg models.Crea
 		return nil, postgres.MapError(err, "Postgres.CreateFeature.MarshalConfig")
 	}
 
+	meterSlugs := arg.MeterSlugs
+	if meterSlugs == nil {
+		meterSlugs = []string{}
+	}
+
+	mergeRule := arg.MergeRule
+	if mergeRule == "" {
+		mergeRule = models.QuotaMergeSum
+	}
+
 	m, err := p.q.CreateFeature(ctx, gen.CreateFeatureParams{
 		Name:        arg.Name,
 		Description: pgtype.Text{String: arg.Description, Valid: arg.Description != ""},
@@ -30,6 +40,8 @@ func (p *PgFeatureRepository) CreateFeature(ctx context.Context, arg models.Crea
 		TenantSlug:  tenantSlug,
 		CreatedBy:   arg.CreatedBy,
 		UpdatedBy:   arg.CreatedBy,
+		MeterSlugs:  meterSlugs,
+		MergeRule:   gen.QuotaMergeRuleEnum(mergeRule),
 	})
 	if err != nil {
 		p.logger.Error("failed to create feature", zap.Error(err))
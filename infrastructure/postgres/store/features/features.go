@@ -35,6 +35,8 @@ func toFeatureModel(m gen.Feature) *models.Feature {
 		TenantSlug:  m.TenantSlug,
 		Type:        models.FeatureTypeEnum(m.Type),
 		Config:      config,
+		MeterSlugs:  m.MeterSlugs,
+		MergeRule:   models.QuotaMergeRule(m.MergeRule),
 		Base: models.Base{
 			ID:        uuid.UUID(m.ID.Bytes),
 			CreatedAt: m.CreatedAt.Time,
@@ -24,6 +24,11 @@ func (p *PgFeatureRepository) UpdateFeatureByIDorSlug(ctx context.Context, idOrS
 		return nil, postgres.MapError(parseErr, "Postgres.MarshalConfig")
 	}
 
+	mergeRule := gen.NullQuotaMergeRuleEnum{
+		QuotaMergeRuleEnum: gen.QuotaMergeRuleEnum(input.MergeRule),
+		Valid:              input.MergeRule != "",
+	}
+
 	if parseErr == nil {
 		m, updateErr = p.q.UpdateFeatureByID(ctx, gen.UpdateFeatureByIDParams{
 			Name:        pgtype.Text{String: input.Name, Valid: input.Name != ""},
@@ -31,6 +36,8 @@ func (p *PgFeatureRepository) UpdateFeatureByIDorSlug(ctx context.Context, idOrS
 			TenantSlug:  tenantSlug,
 			ID:          pgtype.UUID{Bytes: parsedId, Valid: true},
 			Config:      configJson,
+			MeterSlugs:  input.MeterSlugs,
+			MergeRule:   mergeRule,
 			UpdatedBy:   input.UpdatedBy,
 		})
 	} else {
@@ -40,6 +47,8 @@ func (p *PgFeatureRepository) UpdateFeatureByIDorSlug(ctx context.Context, idOrS
 			Description: pgtype.Text{String: input.Description, Valid: input.Description != ""},
 			TenantSlug:  tenantSlug,
 			Slug:        idOrSlug,
+			MeterSlugs:  input.MeterSlugs,
+			MergeRule:   mergeRule,
 			UpdatedBy:   input.UpdatedBy,
 		})
 	}
@@ -2,6 +2,7 @@ package meters
 
 import (
 	"context"
+	"encoding/json"
 
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/redcardinal-io/metering/domain/models"
@@ -13,17 +14,67 @@ import (
 
 func (p *PgMeterStoreRepository) CreateMeter(ctx context.Context, arg models.CreateMeterInput) (*models.Meter, error) {
 	tenantSlug := ctx.Value(constants.TenantSlugKey).(string)
+	meterType := arg.Type
+	if meterType == "" {
+		meterType = models.MeterTypeStandard
+	}
+	granularity := arg.Granularity
+	if granularity == "" {
+		granularity = models.DefaultGranularity
+	}
+	status := arg.Status
+	if status == "" {
+		status = models.MeterStatusActive
+	}
+	rollups := make([]string, 0, len(arg.Rollups))
+	for _, rollup := range arg.Rollups {
+		rollups = append(rollups, string(rollup))
+	}
+	sourceMeters := arg.SourceMeters
+	if sourceMeters == nil {
+		sourceMeters = []string{}
+	}
+	var filter []byte
+	if arg.Filter != nil {
+		var err error
+		if filter, err = json.Marshal(arg.Filter); err != nil {
+			return nil, postgres.MapError(err, "Postgres.CreateMeter.MarshalFilter")
+		}
+	}
+	eventTypes := arg.EventTypes
+	if len(eventTypes) == 0 {
+		eventTypes = []string{}
+		if arg.EventType != "" {
+			eventTypes = append(eventTypes, arg.EventType)
+		}
+	}
+	var valueProperties []byte
+	if len(arg.ValueProperties) > 0 {
+		var err error
+		if valueProperties, err = json.Marshal(arg.ValueProperties); err != nil {
+			return nil, postgres.MapError(err, "Postgres.CreateMeter.MarshalValueProperties")
+		}
+	}
 	m, err := p.q.CreateMeter(ctx, gen.CreateMeterParams{
-		Slug:          arg.MeterSlug,
-		Name:          arg.Name,
-		EventType:     arg.EventType,
-		Description:   pgtype.Text{String: arg.Description, Valid: arg.Description != ""},
-		ValueProperty: pgtype.Text{String: arg.ValueProperty, Valid: arg.ValueProperty != ""},
-		Properties:    arg.Properties,
-		Aggregation:   gen.AggregationEnum(arg.Aggregation),
-		TenantSlug:    tenantSlug,
-		CreatedBy:     arg.CreatedBy,
-		UpdatedBy:     arg.CreatedBy,
+		Slug:            arg.MeterSlug,
+		Name:            arg.Name,
+		EventType:       pgtype.Text{String: arg.EventType, Valid: arg.EventType != ""},
+		Description:     pgtype.Text{String: arg.Description, Valid: arg.Description != ""},
+		ValueProperty:   pgtype.Text{String: arg.ValueProperty, Valid: arg.ValueProperty != ""},
+		Properties:      arg.Properties,
+		Aggregation:     gen.NullAggregationEnum{AggregationEnum: gen.AggregationEnum(arg.Aggregation), Valid: arg.Aggregation != ""},
+		TenantSlug:      tenantSlug,
+		CreatedBy:       arg.CreatedBy,
+		UpdatedBy:       arg.CreatedBy,
+		Type:            gen.MeterTypeEnum(meterType),
+		Formula:         pgtype.Text{String: arg.Formula, Valid: arg.Formula != ""},
+		SourceMeters:    sourceMeters,
+		Granularity:     gen.MeterGranularityEnum(granularity),
+		Rollups:         rollups,
+		Status:          gen.MeterStatusEnum(status),
+		Filter:          filter,
+		EventTypes:      eventTypes,
+		ValueProperties: valueProperties,
 	})
 	if err != nil {
 		p.logger.Error("failed to create meter", zap.Error(err))
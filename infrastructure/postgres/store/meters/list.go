@@ -61,3 +61,33 @@ func (p *PgMeterStoreRepository) ListMetersByEventTypes(
 
 	return meters, nil
 }
+
+func (p *PgMeterStoreRepository) ListMeterSlugsReferencing(ctx context.Context, meterSlug string) ([]string, error) {
+	tenantSlug := ctx.Value(constants.TenantSlugKey).(string)
+
+	slugs, err := p.q.ListMeterSlugsReferencing(ctx, gen.ListMeterSlugsReferencingParams{
+		Column1:    meterSlug,
+		TenantSlug: tenantSlug,
+	})
+	if err != nil {
+		p.logger.Error("Error listing meters referencing meter: ", zap.Error(err))
+		return nil, postgres.MapError(err, "Postgres.ListMeterSlugsReferencing")
+	}
+
+	return slugs, nil
+}
+
+func (p *PgMeterStoreRepository) ListAllMeters(ctx context.Context) ([]*models.Meter, error) {
+	m, err := p.q.ListAllMeters(ctx)
+	if err != nil {
+		p.logger.Error("Error listing all meters: ", zap.Error(err))
+		return nil, postgres.MapError(err, "Postgres.ListAllMeters")
+	}
+
+	meters := make([]*models.Meter, 0, len(m))
+	for _, meter := range m {
+		meters = append(meters, toMeterModel(meter))
+	}
+
+	return meters, nil
+}
@@ -1,6 +1,8 @@
 package meters
 
 import (
+	"encoding/json"
+
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/redcardinal-io/metering/application/repositories"
@@ -24,15 +26,41 @@ func NewPostgresMeterStoreRepository(db any, logger *logger.Logger) repositories
 
 // toMeterModel converts a gen.Meter database record into a domain models.Meter object.
 func toMeterModel(m gen.Meter) *models.Meter {
+	rollups := make([]models.Granularity, 0, len(m.Rollups))
+	for _, rollup := range m.Rollups {
+		rollups = append(rollups, models.Granularity(rollup))
+	}
+	var filter *models.FilterExpression
+	if len(m.Filter) > 0 {
+		filter = &models.FilterExpression{}
+		if err := json.Unmarshal(m.Filter, filter); err != nil {
+			filter = nil
+		}
+	}
+	var valueProperties map[string]string
+	if len(m.ValueProperties) > 0 {
+		_ = json.Unmarshal(m.ValueProperties, &valueProperties)
+	}
 	return &models.Meter{
-		Name:          m.Name,
-		Slug:          m.Slug,
-		ValueProperty: m.ValueProperty.String,
-		EventType:     m.EventType,
-		Description:   m.Description.String,
-		Properties:    m.Properties,
-		Aggregation:   models.AggregationEnum(m.Aggregation),
-		TenantSlug:    m.TenantSlug,
+		Name:              m.Name,
+		Slug:              m.Slug,
+		ValueProperty:     m.ValueProperty.String,
+		Type:              models.MeterTypeEnum(m.Type),
+		EventType:         m.EventType.String,
+		EventTypes:        m.EventTypes,
+		ValueProperties:   valueProperties,
+		Description:       m.Description.String,
+		Properties:        m.Properties,
+		Aggregation:       models.AggregationEnum(m.Aggregation.AggregationEnum),
+		Formula:           m.Formula.String,
+		SourceMeters:      m.SourceMeters,
+		Granularity:       models.Granularity(m.Granularity),
+		Rollups:           rollups,
+		Filter:            filter,
+		Status:            models.MeterStatus(m.Status),
+		StatusError:       m.StatusError.String,
+		TenantSlug:        m.TenantSlug,
+		ReconcileAttempts: int(m.ReconcileAttempts),
 		Base: models.Base{
 			ID:        uuid.UUID(m.ID.Bytes),
 			CreatedAt: m.CreatedAt.Time,
@@ -0,0 +1,55 @@
+package meters
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/redcardinal-io/metering/domain/models"
+	"github.com/redcardinal-io/metering/domain/pkg/constants"
+	"github.com/redcardinal-io/metering/infrastructure/postgres"
+	"github.com/redcardinal-io/metering/infrastructure/postgres/gen"
+	"go.uber.org/zap"
+)
+
+func (p *PgMeterStoreRepository) UpdateMeterStatus(ctx context.Context, id uuid.UUID, arg models.UpdateMeterStatusInput) (*models.Meter, error) {
+	tenantSlug := ctx.Value(constants.TenantSlugKey).(string)
+	m, err := p.q.UpdateMeterStatus(ctx, gen.UpdateMeterStatusParams{
+		Status:            gen.MeterStatusEnum(arg.Status),
+		StatusError:       pgtype.Text{String: arg.Error, Valid: arg.Error != ""},
+		ReconcileAttempts: int32(arg.ReconcileAttempts),
+		ID:                pgtype.UUID{Bytes: id, Valid: true},
+		TenantSlug:        tenantSlug,
+	})
+	if err != nil {
+		p.logger.Error("failed to update meter status", zap.Error(err))
+		return nil, postgres.MapError(err, "Postgres.UpdateMeterStatus")
+	}
+
+	return toMeterModel(m), nil
+}
+
+func (p *PgMeterStoreRepository) ListMetersByStatus(ctx context.Context, statuses []models.MeterStatus, updatedBefore time.Time, limit int) ([]*models.Meter, error) {
+	states := make([]gen.MeterStatusEnum, 0, len(statuses))
+	for _, status := range statuses {
+		states = append(states, gen.MeterStatusEnum(status))
+	}
+
+	m, err := p.q.ListMetersByStatus(ctx, gen.ListMetersByStatusParams{
+		Column1:   states,
+		UpdatedAt: pgtype.Timestamptz{Time: updatedBefore, Valid: true},
+		Limit:     int32(limit),
+	})
+	if err != nil {
+		p.logger.Error("Error listing meters by status: ", zap.Error(err))
+		return nil, postgres.MapError(err, "Postgres.ListMetersByStatus")
+	}
+
+	meters := make([]*models.Meter, 0, len(m))
+	for _, meter := range m {
+		meters = append(meters, toMeterModel(meter))
+	}
+
+	return meters, nil
+}
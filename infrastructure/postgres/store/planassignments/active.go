@@ -0,0 +1,70 @@
+package planassignments
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/redcardinal-io/metering/domain/models"
+	"github.com/redcardinal-io/metering/domain/pkg/constants"
+	"github.com/redcardinal-io/metering/infrastructure/postgres"
+	"github.com/redcardinal-io/metering/infrastructure/postgres/gen"
+)
+
+func (p *PgPlanAssignmentsStoreRepository) ListActiveSubjectAssignments(ctx context.Context, arg models.GetActiveAssignmentInput) ([]models.PlanAssignment, error) {
+	tenantSlug := ctx.Value(constants.TenantSlugKey).(string)
+
+	rows, err := p.q.ListActiveSubjectAssignments(ctx, gen.ListActiveSubjectAssignmentsParams{
+		TenantSlug:     tenantSlug,
+		OrganizationID: pgtype.Text{String: arg.OrganizationID, Valid: arg.OrganizationID != ""},
+		UserID:         pgtype.Text{String: arg.UserID, Valid: arg.UserID != ""},
+		At:             pgtype.Timestamptz{Time: arg.At, Valid: true},
+	})
+	if err != nil {
+		return nil, postgres.MapError(err, "Postgres.ListActiveSubjectAssignments")
+	}
+
+	assignments := make([]models.PlanAssignment, 0, len(rows))
+	for _, row := range rows {
+		assignment := toPlanAssignmentModel(row.PlanAssignment)
+		assignment.PlanKind = models.PlanKindEnum(row.PlanKind)
+		assignments = append(assignments, *assignment)
+	}
+	return assignments, nil
+}
+
+func (p *PgPlanAssignmentsStoreRepository) CountOverlappingBaseAssignments(ctx context.Context, arg models.CreateAssignmentInput) (int64, error) {
+	tenantSlug := ctx.Value(constants.TenantSlugKey).(string)
+
+	count, err := p.q.CountOverlappingBaseAssignments(ctx, gen.CountOverlappingBaseAssignmentsParams{
+		TenantSlug:     tenantSlug,
+		OrganizationID: pgtype.Text{String: arg.OrganizationID, Valid: arg.OrganizationID != ""},
+		UserID:         pgtype.Text{String: arg.UserID, Valid: arg.UserID != ""},
+		ValidFrom:      pgtype.Timestamptz{Time: arg.ValidFrom, Valid: true},
+		ValidUntil:     pgtype.Timestamptz{Time: arg.ValidUntil, Valid: !arg.ValidUntil.IsZero()},
+	})
+	if err != nil {
+		return 0, postgres.MapError(err, "Postgres.CountOverlappingBaseAssignments")
+	}
+	return count, nil
+}
+
+func (p *PgPlanAssignmentsStoreRepository) ListActiveAssignmentsByPlan(ctx context.Context, planID uuid.UUID, at time.Time) ([]models.PlanAssignment, error) {
+	tenantSlug := ctx.Value(constants.TenantSlugKey).(string)
+
+	rows, err := p.q.ListActiveAssignmentsByPlan(ctx, gen.ListActiveAssignmentsByPlanParams{
+		PlanID:     pgtype.UUID{Bytes: planID, Valid: true},
+		TenantSlug: tenantSlug,
+		At:         pgtype.Timestamptz{Time: at, Valid: true},
+	})
+	if err != nil {
+		return nil, postgres.MapError(err, "Postgres.ListActiveAssignmentsByPlan")
+	}
+
+	assignments := make([]models.PlanAssignment, 0, len(rows))
+	for _, row := range rows {
+		assignments = append(assignments, *toPlanAssignmentModel(row))
+	}
+	return assignments, nil
+}
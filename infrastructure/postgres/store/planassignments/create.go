@@ -3,6 +3,7 @@ package planassignments
 import (
 	"context"
 
+	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/redcardinal-io/metering/domain/models"
 	"github.com/redcardinal-io/metering/infrastructure/postgres"
@@ -19,6 +20,7 @@ func (p *PgPlanAssignmentsStoreRepository) CreateAssignment(ctx context.Context,
 		UserID:         pgtype.Text{String: arg.UserID, Valid: arg.UserID != ""},
 		CreatedBy:      arg.CreatedBy,
 		UpdatedBy:      arg.CreatedBy,
+		PlanVersionID:  pgtype.UUID{Bytes: arg.PlanVersionID, Valid: arg.PlanVersionID != uuid.Nil},
 	})
 	if err != nil {
 		p.logger.Error("failed to assign plan", zap.Error(err))
@@ -0,0 +1,25 @@
+package planassignments
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/redcardinal-io/metering/domain/models"
+	"github.com/redcardinal-io/metering/domain/pkg/constants"
+	"github.com/redcardinal-io/metering/infrastructure/postgres"
+	"github.com/redcardinal-io/metering/infrastructure/postgres/gen"
+)
+
+func (p *PgPlanAssignmentsStoreRepository) GetAssignment(ctx context.Context, id uuid.UUID) (*models.PlanAssignment, error) {
+	tenantSlug := ctx.Value(constants.TenantSlugKey).(string)
+	m, err := p.q.GetAssignmentByID(ctx, gen.GetAssignmentByIDParams{
+		ID:         pgtype.UUID{Bytes: id, Valid: true},
+		TenantSlug: tenantSlug,
+	})
+	if err != nil {
+		return nil, postgres.MapError(err, "Postgres.GetAssignment")
+	}
+
+	return toPlanAssignmentModel(m), nil
+}
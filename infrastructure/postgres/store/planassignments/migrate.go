@@ -0,0 +1,41 @@
+package planassignments
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/redcardinal-io/metering/domain/models"
+	"github.com/redcardinal-io/metering/domain/pkg/constants"
+	"github.com/redcardinal-io/metering/infrastructure/postgres"
+	"github.com/redcardinal-io/metering/infrastructure/postgres/gen"
+	"go.uber.org/zap"
+)
+
+func (p *PgPlanAssignmentsStoreRepository) MigrateAssignments(ctx context.Context, arg models.MigratePlanVersionInput) (int64, error) {
+	params := gen.MigratePlanAssignmentsParams{
+		OnRenewal:       arg.OnRenewal,
+		ToVersionID:     pgtype.UUID{Bytes: arg.ToVersionID, Valid: true},
+		UpdatedBy:       arg.UpdatedBy,
+		PlanID:          pgtype.UUID{Bytes: arg.PlanID, Valid: true},
+		TenantSlug:      ctx.Value(constants.TenantSlugKey).(string),
+		OrganizationIds: arg.OrganizationIDs,
+		UserIds:         arg.UserIDs,
+		At:              pgtype.Timestamptz{Time: arg.At, Valid: true},
+	}
+	if params.OrganizationIds == nil {
+		params.OrganizationIds = []string{}
+	}
+	if params.UserIds == nil {
+		params.UserIds = []string{}
+	}
+	if arg.FromVersionID != nil {
+		params.FromVersionID = pgtype.UUID{Bytes: *arg.FromVersionID, Valid: true}
+	}
+
+	migrated, err := p.q.MigratePlanAssignments(ctx, params)
+	if err != nil {
+		p.logger.Error("failed to migrate plan assignments", zap.Error(err))
+		return 0, postgres.MapError(err, "Postgres.MigrateAssignments")
+	}
+	return migrated, nil
+}
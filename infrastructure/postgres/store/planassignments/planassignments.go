@@ -24,7 +24,7 @@ func NewPostgresPlanAssignmentsStoreRepository(db any, logger *logger.Logger) re
 
 // toPlanAssignmentModel converts a gen.PlanAssignment database entity to a domain models.PlanAssignment struct.
 func toPlanAssignmentModel(m gen.PlanAssignment) *models.PlanAssignment {
-	return &models.PlanAssignment{
+	assignment := &models.PlanAssignment{
 		Base: models.Base{
 			ID:        uuid.UUID(m.ID.Bytes),
 			CreatedAt: m.CreatedAt.Time,
@@ -33,9 +33,17 @@ func toPlanAssignmentModel(m gen.PlanAssignment) *models.PlanAssignment {
 			UpdatedAt: m.UpdatedAt.Time,
 		},
 		PlanID:         m.PlanID.String(),
+		Kind:           models.PlanAssignmentKind(m.Kind),
 		OrganizationID: m.OrganizationID.String,
 		UserID:         m.UserID.String,
 		ValidFrom:      m.ValidFrom.Time,
 		ValidUntil:     m.ValidUntil.Time,
 	}
+	if m.PlanVersionID.Valid {
+		assignment.PlanVersionID = m.PlanVersionID.String()
+	}
+	if m.PendingPlanVersionID.Valid {
+		assignment.PendingPlanVersionID = m.PendingPlanVersionID.String()
+	}
+	return assignment
 }
@@ -0,0 +1,56 @@
+package planchanges
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/redcardinal-io/metering/domain/models"
+	"github.com/redcardinal-io/metering/domain/pkg/constants"
+	"github.com/redcardinal-io/metering/infrastructure/postgres"
+	"github.com/redcardinal-io/metering/infrastructure/postgres/gen"
+	"go.uber.org/zap"
+)
+
+func (p *PgPlanChangeStoreRepository) CancelPlanChange(ctx context.Context, id uuid.UUID, canceledBy string, at time.Time) (*models.PlanChange, error) {
+	tx, err := p.db.Begin(ctx)
+	if err != nil {
+		p.logger.Error("failed to begin transaction", zap.Error(err))
+		return nil, postgres.MapError(err, "Postgres.CancelPlanChange")
+	}
+	defer tx.Rollback(ctx)
+	q := p.q.WithTx(tx)
+
+	m, err := q.CancelPlanChange(ctx, gen.CancelPlanChangeParams{
+		ID:         pgtype.UUID{Bytes: id, Valid: true},
+		TenantSlug: ctx.Value(constants.TenantSlugKey).(string),
+		CanceledBy: pgtype.Text{String: canceledBy, Valid: true},
+		At:         pgtype.Timestamptz{Time: at, Valid: true},
+	})
+	if err != nil {
+		p.logger.Error("failed to cancel plan change", zap.Error(err))
+		return nil, postgres.MapError(err, "Postgres.CancelPlanChange")
+	}
+
+	if m.ToAssignmentID.Valid {
+		if err := q.DeleteAssignmentByID(ctx, m.ToAssignmentID); err != nil {
+			p.logger.Error("failed to delete the assignment of the new plan", zap.Error(err))
+			return nil, postgres.MapError(err, "Postgres.CancelPlanChange")
+		}
+	}
+	if _, err := q.SetAssignmentValidUntil(ctx, gen.SetAssignmentValidUntilParams{
+		ID:         m.FromAssignmentID,
+		ValidUntil: m.PreviousValidUntil,
+		UpdatedBy:  canceledBy,
+	}); err != nil {
+		p.logger.Error("failed to restore the changed assignment", zap.Error(err))
+		return nil, postgres.MapError(err, "Postgres.CancelPlanChange")
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		p.logger.Error("failed to commit transaction", zap.Error(err))
+		return nil, postgres.MapError(err, "Postgres.CancelPlanChange")
+	}
+	return p.GetPlanChange(ctx, id, at)
+}
@@ -0,0 +1,94 @@
+package planchanges
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	domainerrors "github.com/redcardinal-io/metering/domain/errors"
+	"github.com/redcardinal-io/metering/domain/models"
+	"github.com/redcardinal-io/metering/domain/pkg/constants"
+	"github.com/redcardinal-io/metering/infrastructure/postgres"
+	"github.com/redcardinal-io/metering/infrastructure/postgres/gen"
+	"go.uber.org/zap"
+)
+
+// pendingChangeIndex keeps a single uncanceled plan change per assignment
+const pendingChangeIndex = "plan_change_from_assignment_idx"
+
+func (p *PgPlanChangeStoreRepository) CreatePlanChange(ctx context.Context, arg models.CreatePlanChangeInput) (*models.PlanChange, error) {
+	tx, err := p.db.Begin(ctx)
+	if err != nil {
+		p.logger.Error("failed to begin transaction", zap.Error(err))
+		return nil, postgres.MapError(err, "Postgres.CreatePlanChange")
+	}
+	defer tx.Rollback(ctx)
+	q := p.q.WithTx(tx)
+
+	effectiveAt := pgtype.Timestamptz{Time: arg.EffectiveAt, Valid: true}
+	if _, err := q.SetAssignmentValidUntil(ctx, gen.SetAssignmentValidUntilParams{
+		ID:         pgtype.UUID{Bytes: arg.From.ID, Valid: true},
+		ValidUntil: effectiveAt,
+		UpdatedBy:  arg.CreatedBy,
+	}); err != nil {
+		p.logger.Error("failed to end the changed assignment", zap.Error(err))
+		return nil, postgres.MapError(err, "Postgres.CreatePlanChange")
+	}
+
+	var toAssignmentID, toPlanID pgtype.UUID
+	if arg.To != nil {
+		m, err := q.AssignPlan(ctx, gen.AssignPlanParams{
+			PlanID:         pgtype.UUID{Bytes: *arg.To.PlanID, Valid: true},
+			OrganizationID: pgtype.Text{String: arg.To.OrganizationID, Valid: arg.To.OrganizationID != ""},
+			UserID:         pgtype.Text{String: arg.To.UserID, Valid: arg.To.UserID != ""},
+			ValidFrom:      pgtype.Timestamptz{Time: arg.To.ValidFrom, Valid: true},
+			ValidUntil:     pgtype.Timestamptz{Time: arg.To.ValidUntil, Valid: true},
+			CreatedBy:      arg.CreatedBy,
+			UpdatedBy:      arg.CreatedBy,
+			PlanVersionID:  pgtype.UUID{Bytes: arg.To.PlanVersionID, Valid: arg.To.PlanVersionID != uuid.Nil},
+		})
+		if err != nil {
+			p.logger.Error("failed to assign the new plan", zap.Error(err))
+			return nil, postgres.MapError(err, "Postgres.CreatePlanChange")
+		}
+		toAssignmentID, toPlanID = m.ID, m.PlanID
+	}
+
+	m, err := q.CreatePlanChange(ctx, gen.CreatePlanChangeParams{
+		TenantSlug:         ctx.Value(constants.TenantSlugKey).(string),
+		Type:               gen.PlanChangeTypeEnum(arg.Type),
+		OrganizationID:     pgtype.Text{String: arg.From.OrganizationID, Valid: arg.From.OrganizationID != ""},
+		UserID:             pgtype.Text{String: arg.From.UserID, Valid: arg.From.UserID != ""},
+		FromAssignmentID:   pgtype.UUID{Bytes: arg.From.ID, Valid: true},
+		FromPlanID:         pgtype.UUID{Bytes: uuid.MustParse(arg.From.PlanID), Valid: true},
+		ToAssignmentID:     toAssignmentID,
+		ToPlanID:           toPlanID,
+		EffectiveAt:        effectiveAt,
+		PreviousValidUntil: pgtype.Timestamptz{Time: arg.From.ValidUntil, Valid: true},
+		Reason:             pgtype.Text{String: arg.Reason, Valid: arg.Reason != ""},
+		CreatedBy:          arg.CreatedBy,
+		UpdatedBy:          arg.CreatedBy,
+	})
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.ConstraintName == pendingChangeIndex {
+		return nil, domainerrors.New(
+			err,
+			domainerrors.ECONFLICT,
+			"the plan already has a pending change, cancel it first",
+			domainerrors.WithOperation("Postgres.CreatePlanChange"),
+		)
+	}
+	if err != nil {
+		p.logger.Error("failed to create plan change", zap.Error(err))
+		return nil, postgres.MapError(err, "Postgres.CreatePlanChange")
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		p.logger.Error("failed to commit transaction", zap.Error(err))
+		return nil, postgres.MapError(err, "Postgres.CreatePlanChange")
+	}
+	return p.GetPlanChange(ctx, uuid.UUID(m.ID.Bytes), time.Now())
+}
@@ -0,0 +1,25 @@
+package planchanges
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/redcardinal-io/metering/domain/models"
+	"github.com/redcardinal-io/metering/domain/pkg/constants"
+	"github.com/redcardinal-io/metering/infrastructure/postgres"
+	"github.com/redcardinal-io/metering/infrastructure/postgres/gen"
+)
+
+func (p *PgPlanChangeStoreRepository) GetPlanChange(ctx context.Context, id uuid.UUID, at time.Time) (*models.PlanChange, error) {
+	row, err := p.q.GetPlanChange(ctx, gen.GetPlanChangeParams{
+		ID:         pgtype.UUID{Bytes: id, Valid: true},
+		TenantSlug: ctx.Value(constants.TenantSlugKey).(string),
+		At:         pgtype.Timestamptz{Time: at, Valid: true},
+	})
+	if err != nil {
+		return nil, postgres.MapError(err, "Postgres.GetPlanChange")
+	}
+	return toPlanChangeModel(row.PlanChange, row.Status), nil
+}
@@ -0,0 +1,56 @@
+package planchanges
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/redcardinal-io/metering/domain/models"
+	"github.com/redcardinal-io/metering/domain/pkg/constants"
+	"github.com/redcardinal-io/metering/domain/pkg/pagination"
+	"github.com/redcardinal-io/metering/infrastructure/postgres"
+	"github.com/redcardinal-io/metering/infrastructure/postgres/gen"
+	"go.uber.org/zap"
+)
+
+func (p *PgPlanChangeStoreRepository) ListPlanChanges(ctx context.Context, arg models.QueryPlanChangeInput, page pagination.Pagination) (*pagination.PaginationView[models.PlanChange], error) {
+	tenantSlug := ctx.Value(constants.TenantSlugKey).(string)
+	organizationID := pgtype.Text{String: arg.OrganizationID, Valid: arg.OrganizationID != ""}
+	userID := pgtype.Text{String: arg.UserID, Valid: arg.UserID != ""}
+	status := pgtype.Text{String: string(arg.Status), Valid: arg.Status != ""}
+	at := pgtype.Timestamptz{Time: arg.At, Valid: true}
+
+	rows, err := p.q.ListPlanChangesPaginated(ctx, gen.ListPlanChangesPaginatedParams{
+		TenantSlug:     tenantSlug,
+		OrganizationID: organizationID,
+		UserID:         userID,
+		Status:         status,
+		At:             at,
+		Limit:          int32(page.Limit),
+		Offset:         int32(page.GetOffset()),
+	})
+	if err != nil {
+		p.logger.Error("Error listing plan changes: ", zap.Error(err))
+		return nil, postgres.MapError(err, "Postgres.ListPlanChanges")
+	}
+
+	changes := make([]models.PlanChange, 0, len(rows))
+	for _, row := range rows {
+		changes = append(changes, *toPlanChangeModel(row.PlanChange, row.Status))
+	}
+
+	count, err := p.q.CountPlanChanges(ctx, gen.CountPlanChangesParams{
+		TenantSlug:     tenantSlug,
+		OrganizationID: organizationID,
+		UserID:         userID,
+		Status:         status,
+		At:             at,
+	})
+	if err != nil {
+		p.logger.Error("Error counting plan changes: ", zap.Error(err))
+		return nil, postgres.MapError(err, "Postgres.CountPlanChanges")
+	}
+
+	result := pagination.FormatWith(page, int(count), changes)
+
+	return &result, nil
+}
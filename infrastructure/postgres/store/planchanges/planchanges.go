@@ -0,0 +1,62 @@
+package planchanges
+
+import (
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/redcardinal-io/metering/application/repositories"
+	"github.com/redcardinal-io/metering/domain/models"
+	"github.com/redcardinal-io/metering/domain/pkg/logger"
+	"github.com/redcardinal-io/metering/infrastructure/postgres/gen"
+)
+
+type PgPlanChangeStoreRepository struct {
+	db     *pgxpool.Pool
+	q      *gen.Queries
+	logger *logger.Logger
+}
+
+// NewPostgresPlanChangeStoreRepository returns a new PlanChangeStoreRepository backed by PostgreSQL, initialized with the given database connection and logger.
+func NewPostgresPlanChangeStoreRepository(db any, logger *logger.Logger) repositories.PlanChangeStoreRepository {
+	pool := db.(*pgxpool.Pool)
+	return &PgPlanChangeStoreRepository{
+		db:     pool,
+		q:      gen.New(pool),
+		logger: logger,
+	}
+}
+
+// toPlanChangeModel converts a gen.PlanChange database entity and its status to a domain models.PlanChange struct.
+func toPlanChangeModel(m gen.PlanChange, status string) *models.PlanChange {
+	change := &models.PlanChange{
+		Base: models.Base{
+			ID:        uuid.UUID(m.ID.Bytes),
+			CreatedAt: m.CreatedAt.Time,
+			UpdatedAt: m.UpdatedAt.Time,
+			CreatedBy: m.CreatedBy,
+			UpdatedBy: m.UpdatedBy,
+		},
+		TenantSlug:         m.TenantSlug,
+		Type:               models.PlanChangeType(m.Type),
+		Status:             models.PlanChangeStatus(status),
+		OrganizationID:     m.OrganizationID.String,
+		UserID:             m.UserID.String,
+		FromAssignmentID:   uuid.UUID(m.FromAssignmentID.Bytes),
+		FromPlanID:         uuid.UUID(m.FromPlanID.Bytes),
+		EffectiveAt:        m.EffectiveAt.Time,
+		PreviousValidUntil: m.PreviousValidUntil.Time.UTC(),
+		Reason:             m.Reason.String,
+		CanceledBy:         m.CanceledBy.String,
+	}
+	if m.ToAssignmentID.Valid {
+		id := uuid.UUID(m.ToAssignmentID.Bytes)
+		change.ToAssignmentID = &id
+	}
+	if m.ToPlanID.Valid {
+		id := uuid.UUID(m.ToPlanID.Bytes)
+		change.ToPlanID = &id
+	}
+	if m.CanceledAt.Valid {
+		change.CanceledAt = &m.CanceledAt.Time
+	}
+	return change
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/redcardinal-io/metering/domain/models"
 	"github.com/redcardinal-io/metering/domain/pkg/constants"
 	"github.com/redcardinal-io/metering/infrastructure/postgres"
 	"github.com/redcardinal-io/metering/infrastructure/postgres/gen"
@@ -36,3 +37,32 @@ func (s *PgPlanFeatureStoreRepository) GetPlanFeatureIDByPlanAndFeature(ctx cont
 	}
 	return id.Bytes, nil
 }
+
+func (s *PgPlanFeatureStoreRepository) GetPlanFeatureByID(ctx context.Context, planFeatureID uuid.UUID) (*models.PlanFeature, error) {
+	tenantSlug := ctx.Value(constants.TenantSlugKey).(string)
+	row, err := s.q.GetPlanFeatureByID(ctx, gen.GetPlanFeatureByIDParams{
+		ID:         pgtype.UUID{Bytes: planFeatureID, Valid: true},
+		TenantSlug: tenantSlug,
+	})
+	if err != nil {
+		return nil, postgres.MapError(err, "Postgres.GetPlanFeatureByID")
+	}
+
+	return &models.PlanFeature{
+		PlanID:      row.PlanID.Bytes,
+		FeatureID:   row.FeatureID.Bytes,
+		Config:      UnMarshalPlanFeatureConfig(row.Config),
+		FeatureName: row.FeatureName,
+		FeatureSlug: row.FeatureSlug,
+		Type:        models.FeatureTypeEnum(row.FeatureType),
+		MeterSlugs:  row.FeatureMeterSlugs,
+		MergeRule:   models.QuotaMergeRule(row.FeatureMergeRule),
+		Base: models.Base{
+			ID:        row.PlanFeatureID.Bytes,
+			CreatedAt: row.CreatedAt.Time,
+			CreatedBy: row.CreatedBy,
+			UpdatedAt: row.UpdatedAt.Time,
+			UpdatedBy: row.UpdatedBy,
+		},
+	}, nil
+}
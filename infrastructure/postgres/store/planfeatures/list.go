@@ -6,6 +6,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/redcardinal-io/metering/domain/models"
+	"github.com/redcardinal-io/metering/domain/pkg/constants"
 	"github.com/redcardinal-io/metering/infrastructure/postgres"
 	"github.com/redcardinal-io/metering/infrastructure/postgres/gen"
 	"go.uber.org/zap"
@@ -30,6 +31,36 @@ func (p *PgPlanFeatureStoreRepository) ListPlanFeaturesByPlan(ctx context.Contex
 		return nil, postgres.MapError(err, "Postgres.ListPlanFeatures")
 	}
 
+	return p.toPlanFeatureModels(rows)
+}
+
+func (p *PgPlanFeatureStoreRepository) ListPlanFeaturesByVersion(ctx context.Context, planVersionID uuid.UUID, filter models.PlanFeatureListFilter) ([]models.PlanFeature, error) {
+	params := gen.ListPlanFeaturesByVersionParams{
+		PlanVersionID: pgtype.UUID{Bytes: planVersionID, Valid: true},
+		TenantSlug:    ctx.Value(constants.TenantSlugKey).(string),
+	}
+	if filter.FeatureType != "" {
+		params.FeatureType = gen.NullFeatureEnum{
+			FeatureEnum: gen.FeatureEnum(filter.FeatureType),
+			Valid:       true,
+		}
+	}
+
+	rows, err := p.q.ListPlanFeaturesByVersion(ctx, params)
+	if err != nil {
+		p.logger.Error("Error listing plan version features: ", zap.Error(err))
+		return nil, postgres.MapError(err, "Postgres.ListPlanFeaturesByVersion")
+	}
+
+	planRows := make([]gen.ListPlanFeaturesByPlanRow, 0, len(rows))
+	for _, row := range rows {
+		planRows = append(planRows, gen.ListPlanFeaturesByPlanRow(row))
+	}
+	return p.toPlanFeatureModels(planRows)
+}
+
+// toPlanFeatureModels converts listed plan feature rows into domain models.PlanFeature objects.
+func (p *PgPlanFeatureStoreRepository) toPlanFeatureModels(rows []gen.ListPlanFeaturesByPlanRow) ([]models.PlanFeature, error) {
 	planFeatures := make([]models.PlanFeature, 0, len(rows))
 	for _, row := range rows {
 		id, err := uuid.FromBytes(row.PlanFeatureID.Bytes[:])
@@ -57,6 +88,8 @@ func (p *PgPlanFeatureStoreRepository) ListPlanFeaturesByPlan(ctx context.Contex
 			FeatureName: row.FeatureName,
 			FeatureSlug: row.FeatureSlug,
 			Type:        models.FeatureTypeEnum(row.FeatureType),
+			MeterSlugs:  row.FeatureMeterSlugs,
+			MergeRule:   models.QuotaMergeRule(row.FeatureMergeRule),
 			Base: models.Base{
 				ID:        id,
 				CreatedAt: row.CreatedAt.Time,
@@ -0,0 +1,72 @@
+package plans
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/redcardinal-io/metering/domain/models"
+	"github.com/redcardinal-io/metering/domain/pkg/constants"
+	"github.com/redcardinal-io/metering/infrastructure/postgres"
+	"github.com/redcardinal-io/metering/infrastructure/postgres/gen"
+	"go.uber.org/zap"
+)
+
+func (p *PgPlanStoreRepository) ClonePlan(ctx context.Context, sourceID uuid.UUID, arg models.ClonePlanInput) (*models.Plan, error) {
+	limits := arg.QuotaLimits
+	if limits == nil {
+		limits = map[string]int64{}
+	}
+	limitOverrides, err := json.Marshal(limits)
+	if err != nil {
+		return nil, postgres.MapError(err, "Postgres.ClonePlan")
+	}
+
+	tx, err := p.db.Begin(ctx)
+	if err != nil {
+		p.logger.Error("failed to begin transaction", zap.Error(err))
+		return nil, postgres.MapError(err, "Postgres.ClonePlan")
+	}
+	defer tx.Rollback(ctx)
+	q := p.q.WithTx(tx)
+
+	m, err := q.CreatePlan(ctx, gen.CreatePlanParams{
+		Name:        arg.Name,
+		Slug:        arg.Slug,
+		Type:        gen.PlanTypeEnum(arg.Type),
+		Kind:        gen.PlanKindEnum(arg.Kind),
+		Description: pgtype.Text{String: arg.Description, Valid: arg.Description != ""},
+		TenantSlug:  ctx.Value(constants.TenantSlugKey).(string),
+		CreatedBy:   arg.CreatedBy,
+		UpdatedBy:   arg.CreatedBy,
+	})
+	if err != nil {
+		p.logger.Error("failed to create cloned plan", zap.Error(err))
+		return nil, postgres.MapError(err, "Postgres.ClonePlan")
+	}
+
+	source := pgtype.UUID{Bytes: sourceID, Valid: true}
+	if err := q.ClonePlanFeatures(ctx, gen.ClonePlanFeaturesParams{
+		PlanID:       m.ID,
+		CreatedBy:    arg.CreatedBy,
+		SourcePlanID: source,
+	}); err != nil {
+		p.logger.Error("failed to clone plan features", zap.Error(err))
+		return nil, postgres.MapError(err, "Postgres.ClonePlan")
+	}
+	if err := q.ClonePlanFeatureQuotas(ctx, gen.ClonePlanFeatureQuotasParams{
+		LimitOverrides: limitOverrides,
+		SourcePlanID:   source,
+		PlanID:         m.ID,
+	}); err != nil {
+		p.logger.Error("failed to clone plan feature quotas", zap.Error(err))
+		return nil, postgres.MapError(err, "Postgres.ClonePlan")
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		p.logger.Error("failed to commit transaction", zap.Error(err))
+		return nil, postgres.MapError(err, "Postgres.ClonePlan")
+	}
+	return toPlanModel(m), nil
+}
@@ -13,10 +13,15 @@ import (
 
 func (p *PgPlanStoreRepository) CreatePlan(ctx context.Context, arg models.CreatePlanInput) (*models.Plan, error) {
 	tenantSlug := ctx.Value(constants.TenantSlugKey).(string)
+	kind := arg.Kind
+	if kind == "" {
+		kind = models.PlanKindBase
+	}
 	m, err := p.q.CreatePlan(ctx, gen.CreatePlanParams{
 		Name:        arg.Name,
 		Slug:        arg.Slug,
 		Type:        gen.PlanTypeEnum(arg.Type),
+		Kind:        gen.PlanKindEnum(kind),
 		Description: pgtype.Text{String: arg.Description, Valid: arg.Description != ""},
 		TenantSlug:  tenantSlug,
 		CreatedBy:   arg.CreatedBy,
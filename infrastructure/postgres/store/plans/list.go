@@ -19,6 +19,7 @@ func (p *PgPlanStoreRepository) ListPlans(ctx context.Context, page pagination.P
 		Offset:     int32(page.GetOffset()),
 		TenantSlug: ctx.Value(constants.TenantSlugKey).(string),
 		Type:       createPlanTypeEnum(page.Queries["type"]),
+		Kind:       createPlanKindEnum(page.Queries["kind"]),
 		Archived: pgtype.Bool{
 			Valid: page.Queries["archived"] != "",
 			Bool:  page.Queries["archived"] == "true",
@@ -37,6 +38,7 @@ func (p *PgPlanStoreRepository) ListPlans(ctx context.Context, page pagination.P
 	count, err := p.q.CountPlans(ctx, gen.CountPlansParams{
 		TenantSlug: tenantSlug,
 		Type:       createPlanTypeEnum(page.Queries["type"]),
+		Kind:       createPlanKindEnum(page.Queries["kind"]),
 	})
 	if err != nil {
 		p.logger.Error("Error counting plans: ", zap.Error(err))
@@ -54,3 +56,10 @@ func createPlanTypeEnum(planType string) gen.NullPlanTypeEnum {
 		Valid:        planType != "",
 	}
 }
+
+func createPlanKindEnum(planKind string) gen.NullPlanKindEnum {
+	return gen.NullPlanKindEnum{
+		PlanKindEnum: gen.PlanKindEnum(planKind),
+		Valid:        planKind != "",
+	}
+}
@@ -10,14 +10,17 @@ import (
 )
 
 type PgPlanStoreRepository struct {
+	db     *pgxpool.Pool
 	q      *gen.Queries
 	logger *logger.Logger
 }
 
 // NewPostgresPlanStoreRepository returns a new plan store repository backed by PostgreSQL, initialized with the given database connection and logger.
 func NewPostgresPlanStoreRepository(db any, logger *logger.Logger) repositories.PlanStoreRepository {
+	pool := db.(*pgxpool.Pool)
 	return &PgPlanStoreRepository{
-		q:      gen.New(db.(*pgxpool.Pool)),
+		db:     pool,
+		q:      gen.New(pool),
 		logger: logger,
 	}
 }
@@ -28,6 +31,7 @@ func toPlanModel(m gen.Plan) *models.Plan {
 		Name:        m.Name,
 		Slug:        m.Slug,
 		Type:        models.PlanTypeEnum(m.Type),
+		Kind:        models.PlanKindEnum(m.Kind),
 		Description: m.Description.String,
 		ArchivedAt:  m.ArchivedAt.Time,
 		TenantSlug:  m.TenantSlug,
@@ -0,0 +1,38 @@
+package planversions
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/redcardinal-io/metering/domain/models"
+	"github.com/redcardinal-io/metering/domain/pkg/constants"
+	"github.com/redcardinal-io/metering/infrastructure/postgres"
+	"github.com/redcardinal-io/metering/infrastructure/postgres/gen"
+	"go.uber.org/zap"
+)
+
+func (p *PgPlanVersionStoreRepository) GetPlanVersion(ctx context.Context, planID uuid.UUID, version int) (*models.PlanVersion, error) {
+	m, err := p.q.GetPlanVersion(ctx, gen.GetPlanVersionParams{
+		PlanID:     pgtype.UUID{Bytes: planID, Valid: true},
+		Version:    int32(version),
+		TenantSlug: ctx.Value(constants.TenantSlugKey).(string),
+	})
+	if err != nil {
+		p.logger.Error("failed to get plan version", zap.Error(err))
+		return nil, postgres.MapError(err, "Postgres.GetPlanVersion")
+	}
+	return toPlanVersionModel(m), nil
+}
+
+func (p *PgPlanVersionStoreRepository) GetLatestPlanVersion(ctx context.Context, planID uuid.UUID) (*models.PlanVersion, error) {
+	m, err := p.q.GetLatestPlanVersion(ctx, gen.GetLatestPlanVersionParams{
+		PlanID:     pgtype.UUID{Bytes: planID, Valid: true},
+		TenantSlug: ctx.Value(constants.TenantSlugKey).(string),
+	})
+	if err != nil {
+		p.logger.Error("failed to get latest plan version", zap.Error(err))
+		return nil, postgres.MapError(err, "Postgres.GetLatestPlanVersion")
+	}
+	return toPlanVersionModel(m), nil
+}
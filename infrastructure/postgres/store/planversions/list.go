@@ -0,0 +1,30 @@
+package planversions
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/redcardinal-io/metering/domain/models"
+	"github.com/redcardinal-io/metering/domain/pkg/constants"
+	"github.com/redcardinal-io/metering/infrastructure/postgres"
+	"github.com/redcardinal-io/metering/infrastructure/postgres/gen"
+	"go.uber.org/zap"
+)
+
+func (p *PgPlanVersionStoreRepository) ListPlanVersions(ctx context.Context, planID uuid.UUID) ([]models.PlanVersion, error) {
+	rows, err := p.q.ListPlanVersions(ctx, gen.ListPlanVersionsParams{
+		PlanID:     pgtype.UUID{Bytes: planID, Valid: true},
+		TenantSlug: ctx.Value(constants.TenantSlugKey).(string),
+	})
+	if err != nil {
+		p.logger.Error("failed to list plan versions", zap.Error(err))
+		return nil, postgres.MapError(err, "Postgres.ListPlanVersions")
+	}
+
+	versions := make([]models.PlanVersion, 0, len(rows))
+	for _, row := range rows {
+		versions = append(versions, *toPlanVersionModel(row))
+	}
+	return versions, nil
+}
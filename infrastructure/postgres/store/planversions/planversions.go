@@ -0,0 +1,37 @@
+package planversions
+
+import (
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/redcardinal-io/metering/application/repositories"
+	"github.com/redcardinal-io/metering/domain/models"
+	"github.com/redcardinal-io/metering/domain/pkg/logger"
+	"github.com/redcardinal-io/metering/infrastructure/postgres/gen"
+)
+
+type PgPlanVersionStoreRepository struct {
+	db     *pgxpool.Pool
+	q      *gen.Queries
+	logger *logger.Logger
+}
+
+// NewPostgresPlanVersionStoreRepository creates a new PlanVersionStoreRepository backed by PostgreSQL using the provided database connection and logger.
+func NewPostgresPlanVersionStoreRepository(db any, logger *logger.Logger) repositories.PlanVersionStoreRepository {
+	pool := db.(*pgxpool.Pool)
+	return &PgPlanVersionStoreRepository{
+		db:     pool,
+		q:      gen.New(pool),
+		logger: logger,
+	}
+}
+
+// toPlanVersionModel converts a gen.PlanVersion database record into a domain models.PlanVersion object.
+func toPlanVersionModel(m gen.PlanVersion) *models.PlanVersion {
+	return &models.PlanVersion{
+		ID:          m.ID.Bytes,
+		PlanID:      m.PlanID.Bytes,
+		Version:     int(m.Version),
+		Description: m.Description.String,
+		CreatedAt:   m.CreatedAt.Time,
+		CreatedBy:   m.CreatedBy,
+	}
+}
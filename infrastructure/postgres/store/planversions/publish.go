@@ -0,0 +1,60 @@
+package planversions
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/redcardinal-io/metering/domain/models"
+	"github.com/redcardinal-io/metering/domain/pkg/constants"
+	"github.com/redcardinal-io/metering/infrastructure/postgres"
+	"github.com/redcardinal-io/metering/infrastructure/postgres/gen"
+	"go.uber.org/zap"
+)
+
+func (p *PgPlanVersionStoreRepository) PublishPlanVersion(ctx context.Context, planID uuid.UUID, arg models.PublishPlanVersionInput) (*models.PlanVersion, error) {
+	tx, err := p.db.Begin(ctx)
+	if err != nil {
+		p.logger.Error("failed to begin transaction", zap.Error(err))
+		return nil, postgres.MapError(err, "Postgres.PublishPlanVersion")
+	}
+	defer tx.Rollback(ctx)
+	q := p.q.WithTx(tx)
+
+	id := pgtype.UUID{Bytes: planID, Valid: true}
+	if _, err := q.LockPlanForVersioning(ctx, gen.LockPlanForVersioningParams{
+		ID:         id,
+		TenantSlug: ctx.Value(constants.TenantSlugKey).(string),
+	}); err != nil {
+		p.logger.Error("failed to lock plan", zap.Error(err))
+		return nil, postgres.MapError(err, "Postgres.PublishPlanVersion")
+	}
+
+	m, err := q.CreatePlanVersion(ctx, gen.CreatePlanVersionParams{
+		PlanID:      id,
+		Description: pgtype.Text{String: arg.Description, Valid: arg.Description != ""},
+		CreatedBy:   arg.CreatedBy,
+	})
+	if err != nil {
+		p.logger.Error("failed to create plan version", zap.Error(err))
+		return nil, postgres.MapError(err, "Postgres.PublishPlanVersion")
+	}
+
+	if err := q.CopyDraftPlanFeaturesToVersion(ctx, gen.CopyDraftPlanFeaturesToVersionParams{
+		PlanVersionID: m.ID,
+		PlanID:        id,
+	}); err != nil {
+		p.logger.Error("failed to copy plan features to version", zap.Error(err))
+		return nil, postgres.MapError(err, "Postgres.PublishPlanVersion")
+	}
+	if err := q.CopyDraftPlanFeatureQuotasToVersion(ctx, m.ID); err != nil {
+		p.logger.Error("failed to copy plan feature quotas to version", zap.Error(err))
+		return nil, postgres.MapError(err, "Postgres.PublishPlanVersion")
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		p.logger.Error("failed to commit transaction", zap.Error(err))
+		return nil, postgres.MapError(err, "Postgres.PublishPlanVersion")
+	}
+	return toPlanVersionModel(m), nil
+}
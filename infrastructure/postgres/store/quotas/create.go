@@ -39,13 +39,36 @@ func (r *PlanFeatureQuotaRepository) CreatePlanFeatureQuota(ctx context.Context,
 		customPeriodMinutes = pgtype.Int8{Int64: *arg.CustomPeriodMinutes, Valid: true}
 	}
 
+	// Handle the soft limit, overage allowance and grace period
+	var softLimitValue, overageAllowance, gracePeriodMinutes pgtype.Int8
+	if arg.SoftLimitValue != nil {
+		softLimitValue = pgtype.Int8{Int64: *arg.SoftLimitValue, Valid: true}
+	}
+	if arg.OverageAllowance != nil {
+		overageAllowance = pgtype.Int8{Int64: *arg.OverageAllowance, Valid: true}
+	}
+	var overageAllowanceType gen.NullQuotaOverageTypeEnum
+	if arg.OverageAllowanceType != "" {
+		overageAllowanceType = gen.NullQuotaOverageTypeEnum{
+			Valid:                true,
+			QuotaOverageTypeEnum: gen.QuotaOverageTypeEnum(arg.OverageAllowanceType),
+		}
+	}
+	if arg.GracePeriodMinutes != nil {
+		gracePeriodMinutes = pgtype.Int8{Int64: *arg.GracePeriodMinutes, Valid: true}
+	}
+
 	// Create the quota
 	quota, err := r.q.CreatePlanFeatureQuota(ctx, gen.CreatePlanFeatureQuotaParams{
-		PlanFeatureID:       pgtype.UUID{Bytes: planFeatureID, Valid: true},
-		LimitValue:          arg.LimitValue,
-		ResetPeriod:         resetPeriod,
-		CustomPeriodMinutes: customPeriodMinutes,
-		ActionAtLimit:       actionAtLimit,
+		PlanFeatureID:        pgtype.UUID{Bytes: planFeatureID, Valid: true},
+		LimitValue:           arg.LimitValue,
+		ResetPeriod:          resetPeriod,
+		CustomPeriodMinutes:  customPeriodMinutes,
+		ActionAtLimit:        actionAtLimit,
+		SoftLimitValue:       softLimitValue,
+		OverageAllowance:     overageAllowance,
+		OverageAllowanceType: overageAllowanceType,
+		GracePeriodMinutes:   gracePeriodMinutes,
 	})
 	if err != nil {
 		r.logger.Error("failed to create plan feature quota", zap.Error(err), zap.String("planFeatureID", arg.PlanFeatureID))
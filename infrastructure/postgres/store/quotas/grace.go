@@ -0,0 +1,56 @@
+package quotas
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/redcardinal-io/metering/domain/models"
+	"github.com/redcardinal-io/metering/domain/pkg/constants"
+	"github.com/redcardinal-io/metering/infrastructure/postgres"
+	"github.com/redcardinal-io/metering/infrastructure/postgres/gen"
+	"go.uber.org/zap"
+)
+
+func (r *PlanFeatureQuotaRepository) GetQuotaGraceState(ctx context.Context, subjectType models.SubjectType, subjectID string, featureID uuid.UUID) (*models.QuotaGraceState, error) {
+	tenantSlug := ctx.Value(constants.TenantSlugKey).(string)
+
+	state, err := r.q.GetQuotaGraceState(ctx, gen.GetQuotaGraceStateParams{
+		TenantSlug:  tenantSlug,
+		SubjectType: gen.SubjectTypeEnum(subjectType),
+		SubjectID:   subjectID,
+		FeatureID:   pgtype.UUID{Bytes: featureID, Valid: true},
+	})
+	if err != nil {
+		return nil, postgres.MapError(err, "failed to get quota grace state")
+	}
+
+	return toQuotaGraceStateModel(state), nil
+}
+
+func (r *PlanFeatureQuotaRepository) SaveQuotaGraceState(ctx context.Context, arg models.QuotaGraceState) (*models.QuotaGraceState, error) {
+	tenantSlug := ctx.Value(constants.TenantSlugKey).(string)
+
+	state, err := r.q.UpsertQuotaGraceState(ctx, gen.UpsertQuotaGraceStateParams{
+		TenantSlug:        tenantSlug,
+		SubjectType:       gen.SubjectTypeEnum(arg.SubjectType),
+		SubjectID:         arg.SubjectID,
+		FeatureID:         pgtype.UUID{Bytes: arg.FeatureID, Valid: true},
+		AllowanceUsedUpAt: pgtype.Timestamptz{Time: arg.AllowanceUsedUpAt, Valid: true},
+	})
+	if err != nil {
+		r.logger.Error("failed to save quota grace state", zap.Error(err), zap.String("subjectID", arg.SubjectID), zap.String("featureID", arg.FeatureID.String()))
+		return nil, postgres.MapError(err, "failed to save quota grace state")
+	}
+
+	return toQuotaGraceStateModel(state), nil
+}
+
+func toQuotaGraceStateModel(state gen.QuotaGraceState) *models.QuotaGraceState {
+	return &models.QuotaGraceState{
+		SubjectType:       models.SubjectType(state.SubjectType),
+		SubjectID:         state.SubjectID,
+		FeatureID:         state.FeatureID.Bytes,
+		AllowanceUsedUpAt: state.AllowanceUsedUpAt.Time,
+	}
+}
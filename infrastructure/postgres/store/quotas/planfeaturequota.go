@@ -23,7 +23,7 @@ func NewPlanFeatureQuotaRepository(db any, logger *logger.Logger) repositories.P
 }
 
 // toPlanFeatureQuotaModel converts a PlanFeatureQuotum database record into a PlanFeatureQuota domain model.
-// It maps all relevant fields and sets CustomPeriodMinutes if present in the database record.
+// It maps all relevant fields and sets the optional period, soft limit, overage and grace fields if present in the database record.
 func toPlanFeatureQuotaModel(quota gen.PlanFeatureQuotum) *models.PlanFeatureQuota {
 	// Initialize the result with the database values
 	result := &models.PlanFeatureQuota{
@@ -44,5 +44,22 @@ func toPlanFeatureQuotaModel(quota gen.PlanFeatureQuotum) *models.PlanFeatureQuo
 		result.CustomPeriodMinutes = &customPeriod
 	}
 
+	// Set the soft limit, overage allowance and grace period if valid
+	if quota.SoftLimitValue.Valid {
+		softLimit := quota.SoftLimitValue.Int64
+		result.SoftLimitValue = &softLimit
+	}
+	if quota.OverageAllowance.Valid {
+		overageAllowance := quota.OverageAllowance.Int64
+		result.OverageAllowance = &overageAllowance
+	}
+	if quota.OverageAllowanceType.Valid {
+		result.OverageAllowanceType = models.QuotaOverageType(quota.OverageAllowanceType.QuotaOverageTypeEnum)
+	}
+	if quota.GracePeriodMinutes.Valid {
+		gracePeriod := quota.GracePeriodMinutes.Int64
+		result.GracePeriodMinutes = &gracePeriod
+	}
+
 	return result
 }
@@ -18,7 +18,10 @@ func (r *PlanFeatureQuotaRepository) UpdatePlanFeatureQuota(ctx context.Context,
 
 	// Prepare the update parameters
 	params := gen.UpdatePlanFeatureQuotaParams{
-		PlanFeatureID: pgtype.UUID{Bytes: planFeatureID, Valid: true},
+		PlanFeatureID:           pgtype.UUID{Bytes: planFeatureID, Valid: true},
+		ClearSoftLimitValue:     arg.ClearSoftLimitValue,
+		ClearOverageAllowance:   arg.ClearOverageAllowance,
+		ClearGracePeriodMinutes: arg.ClearGracePeriodMinutes,
 	}
 
 	// Only update the fields that are provided
@@ -41,6 +44,22 @@ func (r *PlanFeatureQuotaRepository) UpdatePlanFeatureQuota(ctx context.Context,
 		}
 	}
 
+	if arg.SoftLimitValue != nil {
+		params.SoftLimitValue = pgtype.Int8{Int64: *arg.SoftLimitValue, Valid: true}
+	}
+	if arg.OverageAllowance != nil {
+		params.OverageAllowance = pgtype.Int8{Int64: *arg.OverageAllowance, Valid: true}
+	}
+	if arg.OverageAllowanceType != "" {
+		params.OverageAllowanceType = gen.NullQuotaOverageTypeEnum{
+			Valid:                true,
+			QuotaOverageTypeEnum: gen.QuotaOverageTypeEnum(arg.OverageAllowanceType),
+		}
+	}
+	if arg.GracePeriodMinutes != nil {
+		params.GracePeriodMinutes = pgtype.Int8{Int64: *arg.GracePeriodMinutes, Valid: true}
+	}
+
 	// Perform the update
 	updatedQuota, err := r.q.UpdatePlanFeatureQuota(ctx, params)
 	if err != nil {
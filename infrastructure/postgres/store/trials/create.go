@@ -0,0 +1,62 @@
+package trials
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/redcardinal-io/metering/domain/models"
+	"github.com/redcardinal-io/metering/domain/pkg/constants"
+	"github.com/redcardinal-io/metering/infrastructure/postgres"
+	"github.com/redcardinal-io/metering/infrastructure/postgres/gen"
+	"go.uber.org/zap"
+)
+
+func (p *PgTrialStoreRepository) CreateTrial(ctx context.Context, arg models.CreateTrialInput) (*models.Trial, error) {
+	tx, err := p.db.Begin(ctx)
+	if err != nil {
+		p.logger.Error("failed to begin transaction", zap.Error(err))
+		return nil, postgres.MapError(err, "Postgres.CreateTrial")
+	}
+	defer tx.Rollback(ctx)
+	q := p.q.WithTx(tx)
+
+	assignment, err := q.AssignTrialPlan(ctx, gen.AssignTrialPlanParams{
+		PlanID:         pgtype.UUID{Bytes: *arg.Assignment.PlanID, Valid: true},
+		OrganizationID: pgtype.Text{String: arg.Assignment.OrganizationID, Valid: arg.Assignment.OrganizationID != ""},
+		UserID:         pgtype.Text{String: arg.Assignment.UserID, Valid: arg.Assignment.UserID != ""},
+		ValidFrom:      pgtype.Timestamptz{Time: arg.Assignment.ValidFrom, Valid: true},
+		ValidUntil:     pgtype.Timestamptz{Time: arg.Assignment.ValidUntil, Valid: true},
+		CreatedBy:      arg.Assignment.CreatedBy,
+		UpdatedBy:      arg.Assignment.CreatedBy,
+		PlanVersionID:  pgtype.UUID{Bytes: arg.Assignment.PlanVersionID, Valid: arg.Assignment.PlanVersionID != uuid.Nil},
+	})
+	if err != nil {
+		p.logger.Error("failed to assign the trial plan", zap.Error(err))
+		return nil, postgres.MapError(err, "Postgres.CreateTrial")
+	}
+
+	var fallbackPlanID pgtype.UUID
+	if arg.FallbackPlanID != nil {
+		fallbackPlanID = pgtype.UUID{Bytes: *arg.FallbackPlanID, Valid: true}
+	}
+	m, err := q.CreatePlanTrial(ctx, gen.CreatePlanTrialParams{
+		TenantSlug:     ctx.Value(constants.TenantSlugKey).(string),
+		AssignmentID:   assignment.ID,
+		TargetPlanID:   pgtype.UUID{Bytes: *arg.TargetPlanID, Valid: true},
+		FallbackPlanID: fallbackPlanID,
+		EndAction:      gen.TrialEndActionEnum(arg.EndAction),
+		CreatedBy:      arg.Assignment.CreatedBy,
+		UpdatedBy:      arg.Assignment.CreatedBy,
+	})
+	if err != nil {
+		p.logger.Error("failed to create trial", zap.Error(err))
+		return nil, postgres.MapError(err, "Postgres.CreateTrial")
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		p.logger.Error("failed to commit transaction", zap.Error(err))
+		return nil, postgres.MapError(err, "Postgres.CreateTrial")
+	}
+	return toTrialModel(m, assignment), nil
+}
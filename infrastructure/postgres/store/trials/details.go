@@ -0,0 +1,23 @@
+package trials
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/redcardinal-io/metering/domain/models"
+	"github.com/redcardinal-io/metering/domain/pkg/constants"
+	"github.com/redcardinal-io/metering/infrastructure/postgres"
+	"github.com/redcardinal-io/metering/infrastructure/postgres/gen"
+)
+
+func (p *PgTrialStoreRepository) GetTrial(ctx context.Context, id uuid.UUID) (*models.Trial, error) {
+	row, err := p.q.GetPlanTrial(ctx, gen.GetPlanTrialParams{
+		ID:         pgtype.UUID{Bytes: id, Valid: true},
+		TenantSlug: ctx.Value(constants.TenantSlugKey).(string),
+	})
+	if err != nil {
+		return nil, postgres.MapError(err, "Postgres.GetTrial")
+	}
+	return toTrialModel(row.PlanTrial, row.PlanAssignment), nil
+}
@@ -0,0 +1,78 @@
+package trials
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/redcardinal-io/metering/domain/models"
+	"github.com/redcardinal-io/metering/infrastructure/postgres"
+	"github.com/redcardinal-io/metering/infrastructure/postgres/gen"
+	"go.uber.org/zap"
+)
+
+// trialHistoryActions are the assignment history actions recording how a trial ended
+var trialHistoryActions = map[models.TrialStatus]models.HistoryActionEnum{
+	models.TrialConverted: models.TrialConvertedAction,
+	models.TrialFellBack:  models.TrialFellBackAction,
+	models.TrialExpired:   models.TrialExpiredAction,
+}
+
+func (p *PgTrialStoreRepository) EndTrial(ctx context.Context, arg models.EndTrialInput) (*models.Trial, error) {
+	tx, err := p.db.Begin(ctx)
+	if err != nil {
+		p.logger.Error("failed to begin transaction", zap.Error(err))
+		return nil, postgres.MapError(err, "Postgres.EndTrial")
+	}
+	defer tx.Rollback(ctx)
+	q := p.q.WithTx(tx)
+
+	var resultingAssignmentID pgtype.UUID
+	if arg.To != nil {
+		m, err := q.AssignPlan(ctx, gen.AssignPlanParams{
+			PlanID:         pgtype.UUID{Bytes: *arg.To.PlanID, Valid: true},
+			OrganizationID: pgtype.Text{String: arg.To.OrganizationID, Valid: arg.To.OrganizationID != ""},
+			UserID:         pgtype.Text{String: arg.To.UserID, Valid: arg.To.UserID != ""},
+			ValidFrom:      pgtype.Timestamptz{Time: arg.To.ValidFrom, Valid: true},
+			ValidUntil:     pgtype.Timestamptz{Time: arg.To.ValidUntil, Valid: true},
+			CreatedBy:      arg.To.CreatedBy,
+			UpdatedBy:      arg.To.CreatedBy,
+			PlanVersionID:  pgtype.UUID{Bytes: arg.To.PlanVersionID, Valid: arg.To.PlanVersionID != uuid.Nil},
+		})
+		if err != nil {
+			p.logger.Error("failed to assign the plan the trial ends on", zap.Error(err))
+			return nil, postgres.MapError(err, "Postgres.EndTrial")
+		}
+		resultingAssignmentID = m.ID
+	}
+
+	if _, err := q.EndPlanTrial(ctx, gen.EndPlanTrialParams{
+		Status:                gen.TrialStatusEnum(arg.Status),
+		ResultingAssignmentID: resultingAssignmentID,
+		EndedAt:               pgtype.Timestamptz{Time: arg.At, Valid: true},
+		UpdatedBy:             arg.EndedBy,
+		ID:                    pgtype.UUID{Bytes: arg.Trial.ID, Valid: true},
+	}); err != nil {
+		p.logger.Error("failed to end trial", zap.Error(err))
+		return nil, postgres.MapError(err, "Postgres.EndTrial")
+	}
+
+	if err := q.InsertAssignmentHistory(ctx, gen.InsertAssignmentHistoryParams{
+		Action:         pgtype.Text{String: string(trialHistoryActions[arg.Status]), Valid: true},
+		PlanID:         pgtype.UUID{Bytes: arg.Trial.PlanID, Valid: true},
+		OrganizationID: pgtype.Text{String: arg.Trial.OrganizationID, Valid: arg.Trial.OrganizationID != ""},
+		UserID:         pgtype.Text{String: arg.Trial.UserID, Valid: arg.Trial.UserID != ""},
+		ValidFrom:      pgtype.Timestamptz{Time: arg.Trial.StartsAt, Valid: true},
+		ValidUntil:     pgtype.Timestamptz{Time: arg.Trial.EndsAt, Valid: true},
+		CreatedBy:      arg.EndedBy,
+	}); err != nil {
+		p.logger.Error("failed to record the end of the trial", zap.Error(err))
+		return nil, postgres.MapError(err, "Postgres.EndTrial")
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		p.logger.Error("failed to commit transaction", zap.Error(err))
+		return nil, postgres.MapError(err, "Postgres.EndTrial")
+	}
+	return p.GetTrial(ctx, arg.Trial.ID)
+}
@@ -0,0 +1,27 @@
+package trials
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/redcardinal-io/metering/domain/models"
+	"github.com/redcardinal-io/metering/domain/pkg/constants"
+	"github.com/redcardinal-io/metering/infrastructure/postgres"
+	"github.com/redcardinal-io/metering/infrastructure/postgres/gen"
+	"go.uber.org/zap"
+)
+
+func (p *PgTrialStoreRepository) ExtendTrial(ctx context.Context, id uuid.UUID, until time.Time, updatedBy string) (*models.Trial, error) {
+	if _, err := p.q.ExtendPlanTrial(ctx, gen.ExtendPlanTrialParams{
+		ValidUntil: pgtype.Timestamptz{Time: until, Valid: true},
+		UpdatedBy:  updatedBy,
+		ID:         pgtype.UUID{Bytes: id, Valid: true},
+		TenantSlug: ctx.Value(constants.TenantSlugKey).(string),
+	}); err != nil {
+		p.logger.Error("failed to extend trial", zap.Error(err))
+		return nil, postgres.MapError(err, "Postgres.ExtendTrial")
+	}
+	return p.GetTrial(ctx, id)
+}
@@ -0,0 +1,71 @@
+package trials
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/redcardinal-io/metering/domain/models"
+	"github.com/redcardinal-io/metering/domain/pkg/constants"
+	"github.com/redcardinal-io/metering/domain/pkg/pagination"
+	"github.com/redcardinal-io/metering/infrastructure/postgres"
+	"github.com/redcardinal-io/metering/infrastructure/postgres/gen"
+	"go.uber.org/zap"
+)
+
+func (p *PgTrialStoreRepository) ListTrials(ctx context.Context, arg models.QueryTrialInput, page pagination.Pagination) (*pagination.PaginationView[models.Trial], error) {
+	tenantSlug := ctx.Value(constants.TenantSlugKey).(string)
+	organizationID := pgtype.Text{String: arg.OrganizationID, Valid: arg.OrganizationID != ""}
+	userID := pgtype.Text{String: arg.UserID, Valid: arg.UserID != ""}
+	status := gen.NullTrialStatusEnum{TrialStatusEnum: gen.TrialStatusEnum(arg.Status), Valid: arg.Status != ""}
+
+	rows, err := p.q.ListPlanTrialsPaginated(ctx, gen.ListPlanTrialsPaginatedParams{
+		TenantSlug:     tenantSlug,
+		OrganizationID: organizationID,
+		UserID:         userID,
+		Status:         status,
+		Limit:          int32(page.Limit),
+		Offset:         int32(page.GetOffset()),
+	})
+	if err != nil {
+		p.logger.Error("Error listing trials: ", zap.Error(err))
+		return nil, postgres.MapError(err, "Postgres.ListTrials")
+	}
+
+	trials := make([]models.Trial, 0, len(rows))
+	for _, row := range rows {
+		trials = append(trials, *toTrialModel(row.PlanTrial, row.PlanAssignment))
+	}
+
+	count, err := p.q.CountPlanTrials(ctx, gen.CountPlanTrialsParams{
+		TenantSlug:     tenantSlug,
+		OrganizationID: organizationID,
+		UserID:         userID,
+		Status:         status,
+	})
+	if err != nil {
+		p.logger.Error("Error counting trials: ", zap.Error(err))
+		return nil, postgres.MapError(err, "Postgres.CountTrials")
+	}
+
+	result := pagination.FormatWith(page, int(count), trials)
+
+	return &result, nil
+}
+
+func (p *PgTrialStoreRepository) ListDueTrials(ctx context.Context, at time.Time, limit int) ([]models.Trial, error) {
+	rows, err := p.q.ListDueTrials(ctx, gen.ListDueTrialsParams{
+		At:    pgtype.Timestamptz{Time: at, Valid: true},
+		Limit: int32(limit),
+	})
+	if err != nil {
+		p.logger.Error("Error listing due trials: ", zap.Error(err))
+		return nil, postgres.MapError(err, "Postgres.ListDueTrials")
+	}
+
+	trials := make([]models.Trial, 0, len(rows))
+	for _, row := range rows {
+		trials = append(trials, *toTrialModel(row.PlanTrial, row.PlanAssignment))
+	}
+	return trials, nil
+}
@@ -0,0 +1,61 @@
+package trials
+
+import (
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/redcardinal-io/metering/application/repositories"
+	"github.com/redcardinal-io/metering/domain/models"
+	"github.com/redcardinal-io/metering/domain/pkg/logger"
+	"github.com/redcardinal-io/metering/infrastructure/postgres/gen"
+)
+
+type PgTrialStoreRepository struct {
+	db     *pgxpool.Pool
+	q      *gen.Queries
+	logger *logger.Logger
+}
+
+// NewPostgresTrialStoreRepository returns a new TrialStoreRepository backed by PostgreSQL, initialized with the given database connection and logger.
+func NewPostgresTrialStoreRepository(db any, logger *logger.Logger) repositories.TrialStoreRepository {
+	pool := db.(*pgxpool.Pool)
+	return &PgTrialStoreRepository{
+		db:     pool,
+		q:      gen.New(pool),
+		logger: logger,
+	}
+}
+
+// toTrialModel converts a gen.PlanTrial database entity and its assignment to a domain models.Trial struct.
+func toTrialModel(m gen.PlanTrial, assignment gen.PlanAssignment) *models.Trial {
+	trial := &models.Trial{
+		Base: models.Base{
+			ID:        uuid.UUID(m.ID.Bytes),
+			CreatedAt: m.CreatedAt.Time,
+			UpdatedAt: m.UpdatedAt.Time,
+			CreatedBy: m.CreatedBy,
+			UpdatedBy: m.UpdatedBy,
+		},
+		TenantSlug:     m.TenantSlug,
+		AssignmentID:   uuid.UUID(m.AssignmentID.Bytes),
+		PlanID:         uuid.UUID(assignment.PlanID.Bytes),
+		OrganizationID: assignment.OrganizationID.String,
+		UserID:         assignment.UserID.String,
+		TargetPlanID:   uuid.UUID(m.TargetPlanID.Bytes),
+		EndAction:      models.TrialEndAction(m.EndAction),
+		Status:         models.TrialStatus(m.Status),
+		StartsAt:       assignment.ValidFrom.Time,
+		EndsAt:         assignment.ValidUntil.Time,
+	}
+	if m.FallbackPlanID.Valid {
+		id := uuid.UUID(m.FallbackPlanID.Bytes)
+		trial.FallbackPlanID = &id
+	}
+	if m.ResultingAssignmentID.Valid {
+		id := uuid.UUID(m.ResultingAssignmentID.Bytes)
+		trial.ResultingAssignmentID = &id
+	}
+	if m.EndedAt.Valid {
+		trial.EndedAt = &m.EndedAt.Time
+	}
+	return trial
+}
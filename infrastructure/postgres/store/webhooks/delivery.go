@@ -0,0 +1,174 @@
+package webhooks
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/redcardinal-io/metering/domain/models"
+	"github.com/redcardinal-io/metering/domain/pkg/constants"
+	"github.com/redcardinal-io/metering/domain/pkg/pagination"
+	"github.com/redcardinal-io/metering/infrastructure/postgres"
+	"github.com/redcardinal-io/metering/infrastructure/postgres/gen"
+	"go.uber.org/zap"
+)
+
+func (p *PgWebhookStoreRepository) CreateWebhookDelivery(ctx context.Context, arg models.CreateWebhookDeliveryInput) (*models.WebhookDelivery, error) {
+	tenantSlug := ctx.Value(constants.TenantSlugKey).(string)
+
+	m, err := p.q.CreateWebhookDelivery(ctx, gen.CreateWebhookDeliveryParams{
+		EndpointID: pgtype.UUID{Bytes: arg.EndpointID, Valid: true},
+		TenantSlug: tenantSlug,
+		EventID:    pgtype.UUID{Bytes: arg.EventID, Valid: true},
+		EventType:  string(arg.EventType),
+		Payload:    arg.Payload,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		// The event is already queued for the endpoint
+		m, err = p.q.GetWebhookDeliveryByEvent(ctx, gen.GetWebhookDeliveryByEventParams{
+			EndpointID: pgtype.UUID{Bytes: arg.EndpointID, Valid: true},
+			EventID:    pgtype.UUID{Bytes: arg.EventID, Valid: true},
+			TenantSlug: tenantSlug,
+		})
+	}
+	if err != nil {
+		p.logger.Error("failed to create webhook delivery", zap.Error(err))
+		return nil, postgres.MapError(err, "Postgres.CreateWebhookDelivery")
+	}
+
+	return toWebhookDeliveryModel(m), nil
+}
+
+func (p *PgWebhookStoreRepository) GetWebhookDelivery(ctx context.Context, id uuid.UUID) (*models.WebhookDelivery, error) {
+	tenantSlug := ctx.Value(constants.TenantSlugKey).(string)
+	m, err := p.q.GetWebhookDeliveryByID(ctx, gen.GetWebhookDeliveryByIDParams{
+		ID:         pgtype.UUID{Bytes: id, Valid: true},
+		TenantSlug: tenantSlug,
+	})
+	if err != nil {
+		return nil, postgres.MapError(err, "Postgres.GetWebhookDelivery")
+	}
+
+	attempts, err := p.q.ListWebhookDeliveryAttempts(ctx, m.ID)
+	if err != nil {
+		return nil, postgres.MapError(err, "Postgres.ListWebhookDeliveryAttempts")
+	}
+
+	delivery := toWebhookDeliveryModel(m)
+	delivery.Log = make([]models.WebhookDeliveryAttempt, 0, len(attempts))
+	for _, attempt := range attempts {
+		delivery.Log = append(delivery.Log, toWebhookDeliveryAttemptModel(attempt))
+	}
+	return delivery, nil
+}
+
+func (p *PgWebhookStoreRepository) ListWebhookDeliveries(ctx context.Context, endpointID uuid.UUID, page pagination.Pagination) (*pagination.PaginationView[models.WebhookDelivery], error) {
+	tenantSlug := ctx.Value(constants.TenantSlugKey).(string)
+	m, err := p.q.ListWebhookDeliveriesPaginated(ctx, gen.ListWebhookDeliveriesPaginatedParams{
+		EndpointID: pgtype.UUID{Bytes: endpointID, Valid: true},
+		TenantSlug: tenantSlug,
+		Limit:      int32(page.Limit),
+		Offset:     int32(page.GetOffset()),
+	})
+	if err != nil {
+		p.logger.Error("Error listing webhook deliveries: ", zap.Error(err))
+		return nil, postgres.MapError(err, "Postgres.ListWebhookDeliveries")
+	}
+
+	deliveries := make([]models.WebhookDelivery, 0, len(m))
+	for _, delivery := range m {
+		deliveries = append(deliveries, *toWebhookDeliveryModel(delivery))
+	}
+
+	count, err := p.q.CountWebhookDeliveries(ctx, gen.CountWebhookDeliveriesParams{
+		EndpointID: pgtype.UUID{Bytes: endpointID, Valid: true},
+		TenantSlug: tenantSlug,
+	})
+	if err != nil {
+		p.logger.Error("Error counting webhook deliveries: ", zap.Error(err))
+		return nil, postgres.MapError(err, "Postgres.CountWebhookDeliveries")
+	}
+
+	result := pagination.FormatWith(page, int(count), deliveries)
+
+	return &result, nil
+}
+
+func (p *PgWebhookStoreRepository) ClaimDueWebhookDeliveries(ctx context.Context, now, leaseUntil time.Time, limit int) ([]models.WebhookDelivery, error) {
+	m, err := p.q.ClaimDueWebhookDeliveries(ctx, gen.ClaimDueWebhookDeliveriesParams{
+		Now:        pgtype.Timestamptz{Time: now, Valid: true},
+		LeaseUntil: pgtype.Timestamptz{Time: leaseUntil, Valid: true},
+		BatchSize:  int32(limit),
+	})
+	if err != nil {
+		p.logger.Error("Error claiming webhook deliveries: ", zap.Error(err))
+		return nil, postgres.MapError(err, "Postgres.ClaimDueWebhookDeliveries")
+	}
+
+	deliveries := make([]models.WebhookDelivery, 0, len(m))
+	for _, delivery := range m {
+		deliveries = append(deliveries, *toWebhookDeliveryModel(delivery))
+	}
+	return deliveries, nil
+}
+
+// RecordWebhookAttempt logs the attempt and updates its delivery in a single transaction so the
+// log never disagrees with the attempt count of the delivery.
+func (p *PgWebhookStoreRepository) RecordWebhookAttempt(ctx context.Context, arg models.RecordWebhookAttemptInput) (*models.WebhookDelivery, error) {
+	tx, err := p.db.Begin(ctx)
+	if err != nil {
+		return nil, postgres.MapError(err, "Postgres.RecordWebhookAttempt")
+	}
+	defer tx.Rollback(ctx)
+	q := p.q.WithTx(tx)
+
+	attempt := gen.CreateWebhookDeliveryAttemptParams{
+		DeliveryID:   pgtype.UUID{Bytes: arg.DeliveryID, Valid: true},
+		Attempt:      int32(arg.Attempt.Attempt),
+		ResponseBody: pgtype.Text{String: arg.Attempt.ResponseBody, Valid: arg.Attempt.ResponseBody != ""},
+		Error:        pgtype.Text{String: arg.Attempt.Error, Valid: arg.Attempt.Error != ""},
+		DurationMs:   arg.Attempt.DurationMs,
+		AttemptedAt:  pgtype.Timestamptz{Time: arg.Attempt.AttemptedAt, Valid: true},
+	}
+	if arg.Attempt.ResponseStatus != nil {
+		attempt.ResponseStatus = pgtype.Int4{Int32: int32(*arg.Attempt.ResponseStatus), Valid: true}
+	}
+	if _, err := q.CreateWebhookDeliveryAttempt(ctx, attempt); err != nil {
+		p.logger.Error("failed to log webhook delivery attempt", zap.Error(err))
+		return nil, postgres.MapError(err, "Postgres.RecordWebhookAttempt")
+	}
+
+	m, err := q.RecordWebhookDeliveryAttempt(ctx, gen.RecordWebhookDeliveryAttemptParams{
+		ID:            pgtype.UUID{Bytes: arg.DeliveryID, Valid: true},
+		Status:        gen.WebhookDeliveryStatusEnum(arg.Status),
+		Attempts:      int32(arg.Attempt.Attempt),
+		NextAttemptAt: pgtype.Timestamptz{Time: arg.NextAttemptAt, Valid: true},
+		LastAttemptAt: pgtype.Timestamptz{Time: arg.Attempt.AttemptedAt, Valid: true},
+		LastError:     pgtype.Text{String: arg.Attempt.Error, Valid: arg.Attempt.Error != ""},
+	})
+	if err != nil {
+		p.logger.Error("failed to update webhook delivery", zap.Error(err))
+		return nil, postgres.MapError(err, "Postgres.RecordWebhookAttempt")
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, postgres.MapError(err, "Postgres.RecordWebhookAttempt")
+	}
+	return toWebhookDeliveryModel(m), nil
+}
+
+func (p *PgWebhookStoreRepository) RequeueWebhookDelivery(ctx context.Context, id uuid.UUID) (*models.WebhookDelivery, error) {
+	tenantSlug := ctx.Value(constants.TenantSlugKey).(string)
+	m, err := p.q.RequeueWebhookDelivery(ctx, gen.RequeueWebhookDeliveryParams{
+		ID:         pgtype.UUID{Bytes: id, Valid: true},
+		TenantSlug: tenantSlug,
+	})
+	if err != nil {
+		return nil, postgres.MapError(err, "Postgres.RequeueWebhookDelivery")
+	}
+
+	return toWebhookDeliveryModel(m), nil
+}
@@ -0,0 +1,131 @@
+package webhooks
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/redcardinal-io/metering/domain/models"
+	"github.com/redcardinal-io/metering/domain/pkg/constants"
+	"github.com/redcardinal-io/metering/domain/pkg/pagination"
+	"github.com/redcardinal-io/metering/infrastructure/postgres"
+	"github.com/redcardinal-io/metering/infrastructure/postgres/gen"
+	"go.uber.org/zap"
+)
+
+func (p *PgWebhookStoreRepository) CreateWebhookEndpoint(ctx context.Context, arg models.CreateWebhookEndpointInput) (*models.WebhookEndpoint, error) {
+	tenantSlug := ctx.Value(constants.TenantSlugKey).(string)
+
+	m, err := p.q.CreateWebhookEndpoint(ctx, gen.CreateWebhookEndpointParams{
+		TenantSlug:  tenantSlug,
+		Url:         arg.URL,
+		Description: pgtype.Text{String: arg.Description, Valid: arg.Description != ""},
+		Secret:      arg.Secret,
+		EventTypes:  toEventTypes(arg.EventTypes),
+		Enabled:     arg.Enabled,
+		CreatedBy:   arg.CreatedBy,
+		UpdatedBy:   arg.CreatedBy,
+	})
+	if err != nil {
+		p.logger.Error("failed to create webhook endpoint", zap.Error(err))
+		return nil, postgres.MapError(err, "Postgres.CreateWebhookEndpoint")
+	}
+
+	return toWebhookEndpointModel(m), nil
+}
+
+func (p *PgWebhookStoreRepository) GetWebhookEndpoint(ctx context.Context, id uuid.UUID) (*models.WebhookEndpoint, error) {
+	tenantSlug := ctx.Value(constants.TenantSlugKey).(string)
+	m, err := p.q.GetWebhookEndpointByID(ctx, gen.GetWebhookEndpointByIDParams{
+		ID:         pgtype.UUID{Bytes: id, Valid: true},
+		TenantSlug: tenantSlug,
+	})
+	if err != nil {
+		return nil, postgres.MapError(err, "Postgres.GetWebhookEndpoint")
+	}
+
+	return toWebhookEndpointModel(m), nil
+}
+
+func (p *PgWebhookStoreRepository) ListWebhookEndpoints(ctx context.Context, page pagination.Pagination) (*pagination.PaginationView[models.WebhookEndpoint], error) {
+	tenantSlug := ctx.Value(constants.TenantSlugKey).(string)
+	m, err := p.q.ListWebhookEndpointsPaginated(ctx, gen.ListWebhookEndpointsPaginatedParams{
+		Limit:      int32(page.Limit),
+		Offset:     int32(page.GetOffset()),
+		TenantSlug: tenantSlug,
+	})
+	if err != nil {
+		p.logger.Error("Error listing webhook endpoints: ", zap.Error(err))
+		return nil, postgres.MapError(err, "Postgres.ListWebhookEndpoints")
+	}
+
+	endpoints := make([]models.WebhookEndpoint, 0, len(m))
+	for _, endpoint := range m {
+		endpoints = append(endpoints, *toWebhookEndpointModel(endpoint))
+	}
+
+	count, err := p.q.CountWebhookEndpoints(ctx, tenantSlug)
+	if err != nil {
+		p.logger.Error("Error counting webhook endpoints: ", zap.Error(err))
+		return nil, postgres.MapError(err, "Postgres.CountWebhookEndpoints")
+	}
+
+	result := pagination.FormatWith(page, int(count), endpoints)
+
+	return &result, nil
+}
+
+func (p *PgWebhookStoreRepository) ListSubscribedWebhookEndpoints(ctx context.Context, notificationType models.NotificationType) ([]models.WebhookEndpoint, error) {
+	tenantSlug := ctx.Value(constants.TenantSlugKey).(string)
+	m, err := p.q.ListSubscribedWebhookEndpoints(ctx, gen.ListSubscribedWebhookEndpointsParams{
+		TenantSlug: tenantSlug,
+		EventType:  string(notificationType),
+	})
+	if err != nil {
+		return nil, postgres.MapError(err, "Postgres.ListSubscribedWebhookEndpoints")
+	}
+
+	endpoints := make([]models.WebhookEndpoint, 0, len(m))
+	for _, endpoint := range m {
+		endpoints = append(endpoints, *toWebhookEndpointModel(endpoint))
+	}
+	return endpoints, nil
+}
+
+func (p *PgWebhookStoreRepository) UpdateWebhookEndpoint(ctx context.Context, id uuid.UUID, arg models.UpdateWebhookEndpointInput) (*models.WebhookEndpoint, error) {
+	tenantSlug := ctx.Value(constants.TenantSlugKey).(string)
+
+	params := gen.UpdateWebhookEndpointParams{
+		ID:          pgtype.UUID{Bytes: id, Valid: true},
+		TenantSlug:  tenantSlug,
+		Url:         toText(arg.URL),
+		Description: toText(arg.Description),
+		Secret:      toText(arg.Secret),
+		EventTypes:  toEventTypes(arg.EventTypes),
+		UpdatedBy:   arg.UpdatedBy,
+	}
+	if arg.Enabled != nil {
+		params.Enabled = pgtype.Bool{Bool: *arg.Enabled, Valid: true}
+	}
+
+	m, err := p.q.UpdateWebhookEndpoint(ctx, params)
+	if err != nil {
+		return nil, postgres.MapError(err, "Postgres.UpdateWebhookEndpoint")
+	}
+
+	return toWebhookEndpointModel(m), nil
+}
+
+func (p *PgWebhookStoreRepository) DeleteWebhookEndpoint(ctx context.Context, id uuid.UUID) error {
+	tenantSlug := ctx.Value(constants.TenantSlugKey).(string)
+	err := p.q.DeleteWebhookEndpoint(ctx, gen.DeleteWebhookEndpointParams{
+		ID:         pgtype.UUID{Bytes: id, Valid: true},
+		TenantSlug: tenantSlug,
+	})
+	if err != nil {
+		p.logger.Error("failed to delete webhook endpoint", zap.Error(err))
+		return postgres.MapError(err, "Postgres.DeleteWebhookEndpoint")
+	}
+
+	return nil
+}
@@ -0,0 +1,105 @@
+package webhooks
+
+import (
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/redcardinal-io/metering/application/repositories"
+	"github.com/redcardinal-io/metering/domain/models"
+	"github.com/redcardinal-io/metering/domain/pkg/logger"
+	"github.com/redcardinal-io/metering/infrastructure/postgres/gen"
+)
+
+type PgWebhookStoreRepository struct {
+	db     *pgxpool.Pool
+	q      *gen.Queries
+	logger *logger.Logger
+}
+
+// NewPostgresWebhookStoreRepository creates a new WebhookStoreRepository backed by PostgreSQL using the provided database connection and logger.
+func NewPostgresWebhookStoreRepository(db any, logger *logger.Logger) repositories.WebhookStoreRepository {
+	pool := db.(*pgxpool.Pool)
+	return &PgWebhookStoreRepository{
+		db:     pool,
+		q:      gen.New(pool),
+		logger: logger,
+	}
+}
+
+// toWebhookEndpointModel converts a gen.WebhookEndpoint database record into a domain models.WebhookEndpoint object.
+func toWebhookEndpointModel(m gen.WebhookEndpoint) *models.WebhookEndpoint {
+	eventTypes := make([]models.NotificationType, 0, len(m.EventTypes))
+	for _, t := range m.EventTypes {
+		eventTypes = append(eventTypes, models.NotificationType(t))
+	}
+	return &models.WebhookEndpoint{
+		TenantSlug:  m.TenantSlug,
+		URL:         m.Url,
+		Description: m.Description.String,
+		Secret:      m.Secret,
+		EventTypes:  eventTypes,
+		Enabled:     m.Enabled,
+		Base: models.Base{
+			ID:        m.ID.Bytes,
+			CreatedAt: m.CreatedAt.Time,
+			CreatedBy: m.CreatedBy,
+			UpdatedAt: m.UpdatedAt.Time,
+			UpdatedBy: m.UpdatedBy,
+		},
+	}
+}
+
+// toWebhookDeliveryModel converts a gen.WebhookDelivery database record into a domain models.WebhookDelivery object.
+func toWebhookDeliveryModel(m gen.WebhookDelivery) *models.WebhookDelivery {
+	delivery := &models.WebhookDelivery{
+		ID:            m.ID.Bytes,
+		EndpointID:    m.EndpointID.Bytes,
+		TenantSlug:    m.TenantSlug,
+		EventID:       m.EventID.Bytes,
+		EventType:     models.NotificationType(m.EventType),
+		Payload:       m.Payload,
+		Status:        models.WebhookDeliveryStatus(m.Status),
+		Attempts:      int(m.Attempts),
+		NextAttemptAt: m.NextAttemptAt.Time,
+		LastError:     m.LastError.String,
+		CreatedAt:     m.CreatedAt.Time,
+		UpdatedAt:     m.UpdatedAt.Time,
+	}
+	if m.LastAttemptAt.Valid {
+		delivery.LastAttemptAt = &m.LastAttemptAt.Time
+	}
+	return delivery
+}
+
+// toWebhookDeliveryAttemptModel converts a gen.WebhookDeliveryAttempt database record into a domain models.WebhookDeliveryAttempt object.
+func toWebhookDeliveryAttemptModel(m gen.WebhookDeliveryAttempt) models.WebhookDeliveryAttempt {
+	attempt := models.WebhookDeliveryAttempt{
+		Attempt:      int(m.Attempt),
+		ResponseBody: m.ResponseBody.String,
+		Error:        m.Error.String,
+		DurationMs:   m.DurationMs,
+		AttemptedAt:  m.AttemptedAt.Time,
+	}
+	if m.ResponseStatus.Valid {
+		status := int(m.ResponseStatus.Int32)
+		attempt.ResponseStatus = &status
+	}
+	return attempt
+}
+
+func toEventTypes(types []models.NotificationType) []string {
+	if types == nil {
+		return nil
+	}
+	eventTypes := make([]string, 0, len(types))
+	for _, t := range types {
+		eventTypes = append(eventTypes, string(t))
+	}
+	return eventTypes
+}
+
+func toText(s *string) pgtype.Text {
+	if s == nil {
+		return pgtype.Text{}
+	}
+	return pgtype.Text{String: *s, Valid: true}
+}
@@ -0,0 +1,93 @@
+// Package webhook delivers signed webhook requests over HTTP.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"syscall"
+
+	"github.com/redcardinal-io/metering/application/repositories"
+	"github.com/redcardinal-io/metering/domain/models"
+)
+
+// maxResponseBody bounds how much of a response body is kept for the delivery log
+const maxResponseBody = 4096
+
+// errNonPublicAddress is returned for endpoints that resolve to an address of the host or of a
+// private network. The address itself is not reported, as the error ends up in the delivery log.
+var errNonPublicAddress = errors.New("webhook endpoint resolves to a non-public address")
+
+// sharedAddressSpace is the carrier-grade NAT range, which netip does not report as private
+var sharedAddressSpace = netip.MustParsePrefix("100.64.0.0/10")
+
+// HTTPWebhookSender posts webhook requests with a shared HTTP client
+type HTTPWebhookSender struct {
+	client *http.Client
+}
+
+// NewHTTPWebhookSender creates a webhook sender. Request timeouts are left to the context of each
+// request. Unless allowPrivateNetworks is set, connections to loopback, private, link-local and
+// other non-public addresses are refused when dialing, so tenants cannot reach internal services
+// whatever their URL resolves to.
+func NewHTTPWebhookSender(allowPrivateNetworks bool) repositories.WebhookSenderRepository {
+	dialer := &net.Dialer{}
+	if !allowPrivateNetworks {
+		dialer.Control = func(network, address string, c syscall.RawConn) error {
+			addrPort, err := netip.ParseAddrPort(address)
+			if err != nil || !isPublicAddr(addrPort.Addr()) {
+				return errNonPublicAddress
+			}
+			return nil
+		}
+	}
+	return &HTTPWebhookSender{client: &http.Client{
+		Transport: &http.Transport{
+			// Requests are not sent through a proxy, which would dial on their behalf
+			Proxy:             nil,
+			DialContext:       dialer.DialContext,
+			ForceAttemptHTTP2: true,
+		},
+		// Redirects are not followed so a delivery is only accepted by the registered URL
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}}
+}
+
+// isPublicAddr reports whether addr is a globally routable unicast address.
+func isPublicAddr(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	return addr.IsGlobalUnicast() &&
+		!addr.IsPrivate() &&
+		!sharedAddressSpace.Contains(addr)
+}
+
+func (s *HTTPWebhookSender) Send(ctx context.Context, req models.WebhookRequest) (*models.WebhookResponse, error) {
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, req.URL, bytes.NewReader(req.Body))
+	if err != nil {
+		return nil, err
+	}
+	for key, value := range req.Headers {
+		httpReq.Header.Set(key, value)
+	}
+
+	resp, err := s.client.Do(httpReq)
+	if err != nil {
+		if errors.Is(err, errNonPublicAddress) {
+			return nil, errNonPublicAddress
+		}
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBody))
+	if err != nil {
+		return nil, err
+	}
+	return &models.WebhookResponse{StatusCode: resp.StatusCode, Body: string(body)}, nil
+}
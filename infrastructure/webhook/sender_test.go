@@ -0,0 +1,87 @@
+package webhook
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/redcardinal-io/metering/domain/models"
+)
+
+func TestHTTPWebhookSender_Send(t *testing.T) {
+	var gotBody, gotSignature string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		gotBody = string(body)
+		gotSignature = r.Header.Get("X-Metering-Signature")
+		w.WriteHeader(http.StatusAccepted)
+		_, _ = w.Write([]byte(strings.Repeat("a", maxResponseBody+10)))
+	}))
+	defer server.Close()
+
+	resp, err := NewHTTPWebhookSender(true).Send(context.Background(), models.WebhookRequest{
+		URL:     server.URL,
+		Headers: map[string]string{"X-Metering-Signature": "v1=abc"},
+		Body:    []byte(`{"type":"meter.created"}`),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusAccepted, resp.StatusCode)
+	assert.Len(t, resp.Body, maxResponseBody)
+	assert.Equal(t, `{"type":"meter.created"}`, gotBody)
+	assert.Equal(t, "v1=abc", gotSignature)
+}
+
+func TestHTTPWebhookSender_DoesNotFollowRedirects(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "https://example.com", http.StatusFound)
+	}))
+	defer server.Close()
+
+	resp, err := NewHTTPWebhookSender(true).Send(context.Background(), models.WebhookRequest{URL: server.URL})
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusFound, resp.StatusCode)
+}
+
+func TestHTTPWebhookSender_RefusesNonPublicAddresses(t *testing.T) {
+	called := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+		_, _ = w.Write([]byte("internal"))
+	}))
+	defer server.Close()
+
+	sender := NewHTTPWebhookSender(false)
+	for _, url := range []string{server.URL, strings.Replace(server.URL, "127.0.0.1", "localhost", 1), "http://169.254.169.254/latest/meta-data", "http://10.0.0.1", "http://[::1]:80"} {
+		resp, err := sender.Send(context.Background(), models.WebhookRequest{URL: url})
+		assert.ErrorIs(t, err, errNonPublicAddress, url)
+		assert.Nil(t, resp)
+	}
+	assert.False(t, called)
+}
+
+func TestIsPublicAddr(t *testing.T) {
+	for addr, public := range map[string]bool{
+		"93.184.216.34":    true,
+		"2606:2800:220::1": true,
+		"127.0.0.1":        false,
+		"10.1.2.3":         false,
+		"172.16.0.1":       false,
+		"192.168.1.1":      false,
+		"169.254.169.254":  false,
+		"100.64.0.1":       false,
+		"0.0.0.0":          false,
+		"::1":              false,
+		"fe80::1":          false,
+		"fd00::1":          false,
+		"::ffff:127.0.0.1": false,
+	} {
+		assert.Equal(t, public, isPublicAddr(netip.MustParseAddr(addr)), addr)
+	}
+}
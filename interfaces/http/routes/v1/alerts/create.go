@@ -0,0 +1,82 @@
+package alerts
+
+import (
+	"context"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	domainerrors "github.com/redcardinal-io/metering/domain/errors"
+	"github.com/redcardinal-io/metering/domain/models"
+	"github.com/redcardinal-io/metering/domain/pkg/constants"
+	"go.uber.org/zap"
+)
+
+type createAlertRuleRequest struct {
+	Name          string     `json:"name" validate:"required,min=3,max=100"`
+	MeterSlug     string     `json:"meter_slug" validate:"omitempty"`
+	PlanFeatureID *uuid.UUID `json:"plan_feature_id" validate:"omitempty"`
+	SubjectType   string     `json:"subject_type" validate:"omitempty,oneof=organization user"`
+	SubjectIDs    []string   `json:"subject_ids" validate:"omitempty,dive,required"`
+	ThresholdType string     `json:"threshold_type" validate:"required,oneof=absolute percent"`
+	Threshold     float64    `json:"threshold" validate:"required,gt=0"`
+	WindowMinutes *int64     `json:"window_minutes" validate:"omitempty,gt=0"`
+	Enabled       *bool      `json:"enabled"`
+	CreatedBy     string     `json:"created_by" validate:"required"`
+}
+
+// @Summary Create an alert rule
+// @Description Create a rule alerting when the usage of subjects reaches a threshold. Meter rules compare the usage of a meter over the trailing window_minutes with an absolute threshold; plan feature rules compare the usage of the subjects assigned the plan over the current quota period with an absolute threshold or a percentage of the quota. Without subject_ids the rule watches every subject of subject_type.
+// @Tags alerts
+// @Accept json
+// @Produce json
+// @Param X-Tenant-Slug header string true "Tenant Slug"
+// @Param rule body createAlertRuleRequest true "Alert rule creation data"
+// @Success 201 {object} models.HttpResponse[models.AlertRule] "Alert rule created successfully"
+// @Failure 400 {object} domainerrors.ErrorResponse "Invalid request"
+// @Failure 404 {object} domainerrors.ErrorResponse "Meter or plan feature not found"
+// @Failure 500 {object} domainerrors.ErrorResponse "Internal server error"
+// @Router /v1/alerts [post]
+func (h *httpHandler) create(ctx *fiber.Ctx) error {
+	tenantSlug := ctx.Get(constants.TenantHeader)
+
+	var req createAlertRuleRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		errResp := domainerrors.NewErrorResponseWithOpts(err, domainerrors.EINVALID, "failed to parse request body")
+		h.logger.Error("failed to parse request body", zap.Reflect("error", errResp))
+		return ctx.Status(errResp.Status).JSON(errResp.ToJson())
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		errResp := domainerrors.NewErrorResponseWithOpts(err, domainerrors.EINVALID, "invalid request body")
+		h.logger.Error("invalid request body", zap.Reflect("error", errResp))
+		return ctx.Status(errResp.Status).JSON(errResp.ToJson())
+	}
+
+	enabled := true
+	if req.Enabled != nil {
+		enabled = *req.Enabled
+	}
+
+	c := context.WithValue(ctx.UserContext(), constants.TenantSlugKey, tenantSlug)
+
+	rule, err := h.alertSvc.CreateAlertRule(c, models.CreateAlertRuleInput{
+		Name:          req.Name,
+		MeterSlug:     req.MeterSlug,
+		PlanFeatureID: req.PlanFeatureID,
+		SubjectType:   models.SubjectType(req.SubjectType),
+		SubjectIDs:    req.SubjectIDs,
+		ThresholdType: models.AlertThresholdType(req.ThresholdType),
+		Threshold:     req.Threshold,
+		WindowMinutes: req.WindowMinutes,
+		Enabled:       enabled,
+		CreatedBy:     req.CreatedBy,
+	})
+	if err != nil {
+		h.logger.Error("failed to create alert rule", zap.Reflect("error", err))
+		errResp := domainerrors.NewErrorResponse(err)
+		return ctx.Status(errResp.Status).JSON(errResp.ToJson())
+	}
+
+	return ctx.
+		Status(fiber.StatusCreated).JSON(models.NewHttpResponse(rule, "alert rule created successfully", fiber.StatusCreated))
+}
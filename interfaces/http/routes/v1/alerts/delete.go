@@ -0,0 +1,45 @@
+package alerts
+
+import (
+	"context"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	domainerrors "github.com/redcardinal-io/metering/domain/errors"
+	"github.com/redcardinal-io/metering/domain/pkg/constants"
+	"go.uber.org/zap"
+)
+
+// @Summary Delete an alert rule
+// @Description Delete an alert rule and its recorded states
+// @Tags alerts
+// @Accept json
+// @Produce json
+// @Param X-Tenant-Slug header string true "Tenant Slug"
+// @Param id path string true "Alert rule ID"
+// @Success 204 "Alert rule deleted successfully"
+// @Failure 400 {object} domainerrors.ErrorResponse "Invalid request"
+// @Failure 404 {object} domainerrors.ErrorResponse "Alert rule not found"
+// @Failure 500 {object} domainerrors.ErrorResponse "Internal server error"
+// @Router /v1/alerts/{id} [delete]
+func (h *httpHandler) deleteByID(ctx *fiber.Ctx) error {
+	tenantSlug := ctx.Get(constants.TenantHeader)
+
+	id, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		errResp := domainerrors.NewErrorResponseWithOpts(err, domainerrors.EINVALID, "invalid alert rule ID format")
+		h.logger.Error("invalid alert rule ID format", zap.Reflect("error", errResp))
+		return ctx.Status(errResp.Status).JSON(errResp.ToJson())
+	}
+
+	c := context.WithValue(ctx.UserContext(), constants.TenantSlugKey, tenantSlug)
+
+	if err := h.alertSvc.DeleteAlertRule(c, id); err != nil {
+		h.logger.Error("failed to delete alert rule", zap.String("id", id.String()), zap.Reflect("error", err))
+		errResp := domainerrors.NewErrorResponse(err)
+		return ctx.Status(errResp.Status).JSON(errResp.ToJson())
+	}
+
+	return ctx.
+		SendStatus(fiber.StatusNoContent)
+}
@@ -0,0 +1,47 @@
+package alerts
+
+import (
+	"context"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	domainerrors "github.com/redcardinal-io/metering/domain/errors"
+	"github.com/redcardinal-io/metering/domain/models"
+	"github.com/redcardinal-io/metering/domain/pkg/constants"
+	"go.uber.org/zap"
+)
+
+// @Summary Get an alert rule
+// @Description Get an alert rule by ID
+// @Tags alerts
+// @Accept json
+// @Produce json
+// @Param X-Tenant-Slug header string true "Tenant Slug"
+// @Param id path string true "Alert rule ID"
+// @Success 200 {object} models.HttpResponse[models.AlertRule] "Alert rule retrieved successfully"
+// @Failure 400 {object} domainerrors.ErrorResponse "Invalid request"
+// @Failure 404 {object} domainerrors.ErrorResponse "Alert rule not found"
+// @Failure 500 {object} domainerrors.ErrorResponse "Internal server error"
+// @Router /v1/alerts/{id} [get]
+func (h *httpHandler) getByID(ctx *fiber.Ctx) error {
+	tenantSlug := ctx.Get(constants.TenantHeader)
+
+	id, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		errResp := domainerrors.NewErrorResponseWithOpts(err, domainerrors.EINVALID, "invalid alert rule ID format")
+		h.logger.Error("invalid alert rule ID format", zap.Reflect("error", errResp))
+		return ctx.Status(errResp.Status).JSON(errResp.ToJson())
+	}
+
+	c := context.WithValue(ctx.UserContext(), constants.TenantSlugKey, tenantSlug)
+
+	rule, err := h.alertSvc.GetAlertRule(c, id)
+	if err != nil {
+		h.logger.Error("failed to get alert rule", zap.String("id", id.String()), zap.Reflect("error", err))
+		errResp := domainerrors.NewErrorResponse(err)
+		return ctx.Status(errResp.Status).JSON(errResp.ToJson())
+	}
+
+	return ctx.
+		Status(fiber.StatusOK).JSON(models.NewHttpResponse(rule, "alert rule retrieved successfully", fiber.StatusOK))
+}
@@ -0,0 +1,47 @@
+package alerts
+
+import (
+	"context"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	domainerrors "github.com/redcardinal-io/metering/domain/errors"
+	"github.com/redcardinal-io/metering/domain/models"
+	"github.com/redcardinal-io/metering/domain/pkg/constants"
+	"go.uber.org/zap"
+)
+
+// @Summary Evaluate an alert rule
+// @Description Evaluate an alert rule immediately, whether or not it is enabled, notifying subjects that start firing. Returns the states recorded by the evaluation.
+// @Tags alerts
+// @Accept json
+// @Produce json
+// @Param X-Tenant-Slug header string true "Tenant Slug"
+// @Param id path string true "Alert rule ID"
+// @Success 200 {object} models.HttpResponse[[]models.AlertState] "Alert rule evaluated successfully"
+// @Failure 400 {object} domainerrors.ErrorResponse "Invalid request"
+// @Failure 404 {object} domainerrors.ErrorResponse "Alert rule not found"
+// @Failure 500 {object} domainerrors.ErrorResponse "Internal server error"
+// @Router /v1/alerts/{id}/evaluate [post]
+func (h *httpHandler) evaluate(ctx *fiber.Ctx) error {
+	tenantSlug := ctx.Get(constants.TenantHeader)
+
+	id, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		errResp := domainerrors.NewErrorResponseWithOpts(err, domainerrors.EINVALID, "invalid alert rule ID format")
+		h.logger.Error("invalid alert rule ID format", zap.Reflect("error", errResp))
+		return ctx.Status(errResp.Status).JSON(errResp.ToJson())
+	}
+
+	c := context.WithValue(ctx.UserContext(), constants.TenantSlugKey, tenantSlug)
+
+	states, err := h.alertSvc.EvaluateAlertRule(c, id)
+	if err != nil {
+		h.logger.Error("failed to evaluate alert rule", zap.String("id", id.String()), zap.Reflect("error", err))
+		errResp := domainerrors.NewErrorResponse(err)
+		return ctx.Status(errResp.Status).JSON(errResp.ToJson())
+	}
+
+	return ctx.
+		Status(fiber.StatusOK).JSON(models.NewHttpResponse(states, "alert rule evaluated successfully", fiber.StatusOK))
+}
@@ -0,0 +1,42 @@
+package alerts
+
+import (
+	"context"
+
+	"github.com/gofiber/fiber/v2"
+	domainerrors "github.com/redcardinal-io/metering/domain/errors"
+	"github.com/redcardinal-io/metering/domain/models"
+	"github.com/redcardinal-io/metering/domain/pkg/constants"
+	"github.com/redcardinal-io/metering/domain/pkg/pagination"
+	"go.uber.org/zap"
+)
+
+// @Summary List alert rules
+// @Description Get a list of alert rules for the tenant
+// @Tags alerts
+// @Accept json
+// @Produce json
+// @Param X-Tenant-Slug header string true "Tenant Slug"
+// @Param page query integer false "Page number"
+// @Param limit query integer false "Items per page"
+// @Success 200 {object} models.HttpResponse[[]models.AlertRule] "Alert rules retrieved successfully"
+// @Failure 400 {object} domainerrors.ErrorResponse "Invalid request"
+// @Failure 500 {object} domainerrors.ErrorResponse "Internal server error"
+// @Router /v1/alerts [get]
+func (h *httpHandler) list(ctx *fiber.Ctx) error {
+	tenantSlug := ctx.Get(constants.TenantHeader)
+
+	paginationInput := pagination.ExtractPaginationFromContext(ctx)
+
+	c := context.WithValue(ctx.UserContext(), constants.TenantSlugKey, tenantSlug)
+
+	rules, err := h.alertSvc.ListAlertRules(c, paginationInput)
+	if err != nil {
+		h.logger.Error("failed to list alert rules", zap.Reflect("error", err))
+		errResp := domainerrors.NewErrorResponse(err)
+		return ctx.Status(errResp.Status).JSON(errResp.ToJson())
+	}
+
+	return ctx.
+		Status(fiber.StatusOK).JSON(models.NewHttpResponse(rules, "alert rules retrieved successfully", fiber.StatusOK))
+}
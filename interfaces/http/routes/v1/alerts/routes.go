@@ -0,0 +1,32 @@
+package alerts
+
+import (
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/redcardinal-io/metering/application/services"
+	"github.com/redcardinal-io/metering/domain/pkg/logger"
+)
+
+type httpHandler struct {
+	logger    *logger.Logger
+	alertSvc  *services.AlertService
+	validator *validator.Validate
+}
+
+// NewHTTPHandler creates a new httpHandler for alert rule HTTP routes with logging, the alert service, and validation capabilities.
+func NewHTTPHandler(logger *logger.Logger, alertSvc *services.AlertService) *httpHandler {
+	validator := validator.New()
+	return &httpHandler{logger, alertSvc, validator}
+}
+
+func (h *httpHandler) RegisterRoutes(r fiber.Router) {
+	alerts := r.Group("/alerts")
+
+	alerts.Post("/", h.create)
+	alerts.Get("/", h.list)
+	alerts.Get("/:id", h.getByID)
+	alerts.Put("/:id", h.updateByID)
+	alerts.Delete("/:id", h.deleteByID)
+	alerts.Post("/:id/evaluate", h.evaluate)
+	alerts.Get("/:id/states", h.states)
+}
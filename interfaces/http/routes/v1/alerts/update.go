@@ -0,0 +1,82 @@
+package alerts
+
+import (
+	"context"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	domainerrors "github.com/redcardinal-io/metering/domain/errors"
+	"github.com/redcardinal-io/metering/domain/models"
+	"github.com/redcardinal-io/metering/domain/pkg/constants"
+	"go.uber.org/zap"
+)
+
+type updateAlertRuleRequest struct {
+	Name          *string  `json:"name" validate:"omitempty,min=3,max=100"`
+	SubjectIDs    []string `json:"subject_ids" validate:"omitempty,dive,required"`
+	Threshold     *float64 `json:"threshold" validate:"omitempty,gt=0"`
+	WindowMinutes *int64   `json:"window_minutes" validate:"omitempty,gt=0"`
+	Enabled       *bool    `json:"enabled"`
+	UpdatedBy     string   `json:"updated_by" validate:"required"`
+}
+
+// @Summary Update an alert rule
+// @Description Update the name, subjects, threshold, window or enabled state of an alert rule. The target and threshold type of a rule cannot change.
+// @Tags alerts
+// @Accept json
+// @Produce json
+// @Param X-Tenant-Slug header string true "Tenant Slug"
+// @Param id path string true "Alert rule ID"
+// @Param rule body updateAlertRuleRequest true "Alert rule update data"
+// @Success 200 {object} models.HttpResponse[models.AlertRule] "Alert rule updated successfully"
+// @Failure 400 {object} domainerrors.ErrorResponse "Invalid request"
+// @Failure 404 {object} domainerrors.ErrorResponse "Alert rule not found"
+// @Failure 500 {object} domainerrors.ErrorResponse "Internal server error"
+// @Router /v1/alerts/{id} [put]
+func (h *httpHandler) updateByID(ctx *fiber.Ctx) error {
+	tenantSlug := ctx.Get(constants.TenantHeader)
+
+	id, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		errResp := domainerrors.NewErrorResponseWithOpts(err, domainerrors.EINVALID, "invalid alert rule ID format")
+		h.logger.Error("invalid alert rule ID format", zap.Reflect("error", errResp))
+		return ctx.Status(errResp.Status).JSON(errResp.ToJson())
+	}
+
+	var req updateAlertRuleRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		errResp := domainerrors.NewErrorResponseWithOpts(err, domainerrors.EINVALID, "failed to parse request body")
+		h.logger.Error("failed to parse request body", zap.Reflect("error", errResp))
+		return ctx.Status(errResp.Status).JSON(errResp.ToJson())
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		errResp := domainerrors.NewErrorResponseWithOpts(err, domainerrors.EINVALID, "invalid request body")
+		h.logger.Error("invalid request body", zap.Reflect("error", errResp))
+		return ctx.Status(errResp.Status).JSON(errResp.ToJson())
+	}
+	if req.Name == nil && req.SubjectIDs == nil && req.Threshold == nil && req.WindowMinutes == nil && req.Enabled == nil {
+		errResp := domainerrors.NewErrorResponseWithOpts(nil, domainerrors.EINVALID, "at least one field (name or subject_ids or threshold or window_minutes or enabled) is required")
+		h.logger.Error("at least one field (name or subject_ids or threshold or window_minutes or enabled) is required", zap.Reflect("error", errResp))
+		return ctx.Status(errResp.Status).JSON(errResp.ToJson())
+	}
+
+	c := context.WithValue(ctx.UserContext(), constants.TenantSlugKey, tenantSlug)
+
+	rule, err := h.alertSvc.UpdateAlertRule(c, id, models.UpdateAlertRuleInput{
+		Name:          req.Name,
+		SubjectIDs:    req.SubjectIDs,
+		Threshold:     req.Threshold,
+		WindowMinutes: req.WindowMinutes,
+		Enabled:       req.Enabled,
+		UpdatedBy:     req.UpdatedBy,
+	})
+	if err != nil {
+		h.logger.Error("failed to update alert rule", zap.String("id", id.String()), zap.Reflect("error", err))
+		errResp := domainerrors.NewErrorResponse(err)
+		return ctx.Status(errResp.Status).JSON(errResp.ToJson())
+	}
+
+	return ctx.
+		Status(fiber.StatusOK).JSON(models.NewHttpResponse(rule, "alert rule updated successfully", fiber.StatusOK))
+}
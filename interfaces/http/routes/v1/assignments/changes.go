@@ -0,0 +1,242 @@
+package assignments
+
+import (
+	"context"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	domainerrors "github.com/redcardinal-io/metering/domain/errors"
+	"github.com/redcardinal-io/metering/domain/models"
+	"github.com/redcardinal-io/metering/domain/pkg/constants"
+	"github.com/redcardinal-io/metering/domain/pkg/pagination"
+	"go.uber.org/zap"
+)
+
+type schedulePlanChangeRequest struct {
+	Type           string    `json:"type" validate:"required,oneof=upgrade downgrade cancellation"`
+	PlanIDOrSlug   string    `json:"plan_id_or_slug" validate:"required"`
+	ToPlanIDOrSlug string    `json:"to_plan_id_or_slug,omitempty"`
+	ToPlanVersion  int       `json:"to_plan_version,omitempty" validate:"omitempty,min=1"`
+	OrganizationID string    `json:"organization_id"`
+	UserID         string    `json:"user_id"`
+	EffectiveAt    time.Time `json:"effective_at,omitempty"`
+	AtPeriodEnd    bool      `json:"at_period_end,omitempty"`
+	Period         string    `json:"period,omitempty" validate:"omitempty,oneof=day week month year"`
+	Reason         string    `json:"reason,omitempty"`
+	CreatedBy      string    `json:"created_by" validate:"required"`
+}
+
+type cancelPlanChangeRequest struct {
+	CanceledBy string `json:"canceled_by" validate:"required"`
+}
+
+// @Summary Schedule a plan change
+// @Description Switch an organization or user from one of its active plans to another (upgrade or downgrade), or cancel the plan, at effective_at, at the end of the current calendar period (a month unless period is set) with at_period_end, or right away when neither is given. The current assignment ends when the change takes effect and the assignment of the new plan starts then, keeping the end the current one had; both show in the assignment history. Plans can only be switched for plans of the same kind, a new base plan cannot overlap another base plan of the subject, and an assignment has at most one pending change.
+// @Tags plan-assignments
+// @Accept json
+// @Produce json
+// @Param X-Tenant-Slug header string true "Tenant Slug"
+// @Param change body schedulePlanChangeRequest true "Plan change information"
+// @Success 201 {object} models.HttpResponse[models.PlanChange] "Plan change scheduled successfully"
+// @Failure 400 {object} domainerrors.ErrorResponse "Invalid request"
+// @Failure 404 {object} domainerrors.ErrorResponse "Plan or active assignment not found"
+// @Failure 409 {object} domainerrors.ErrorResponse "The assignment already has a pending change or the new base plan overlaps another one"
+// @Failure 500 {object} domainerrors.ErrorResponse "Internal server error"
+// @Router /v1/plans/assignments/changes [post]
+func (h *httpHandler) scheduleChange(ctx *fiber.Ctx) error {
+	tenantSlug := ctx.Get(constants.TenantHeader)
+	var req schedulePlanChangeRequest
+
+	if err := ctx.BodyParser(&req); err != nil {
+		errResp := domainerrors.NewErrorResponseWithOpts(err, domainerrors.EINVALID, "failed to parse request body")
+		h.logger.Error("failed to parse request body", zap.Reflect("error", errResp))
+		return ctx.Status(errResp.Status).JSON(errResp.ToJson())
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		errResp := domainerrors.NewErrorResponseWithOpts(err, domainerrors.EINVALID, "invalid request body")
+		h.logger.Error("invalid request body", zap.Reflect("error", errResp))
+		return ctx.Status(errResp.Status).JSON(errResp.ToJson())
+	}
+
+	if req.OrganizationID != "" && req.UserID != "" {
+		errResp := domainerrors.NewErrorResponseWithOpts(nil, domainerrors.EINVALID, "organization_id and user_id are mutually exclusive, provide any one")
+		h.logger.Error("organization_id and user_id are mutually exclusive, provide any one", zap.Reflect("error", errResp))
+		return ctx.Status(errResp.Status).JSON(errResp.ToJson())
+	}
+
+	if req.OrganizationID == "" && req.UserID == "" {
+		errResp := domainerrors.NewErrorResponseWithOpts(nil, domainerrors.EINVALID, "organization_id or user_id is required")
+		h.logger.Error("organization_id or user_id is required", zap.Reflect("error", errResp))
+		return ctx.Status(errResp.Status).JSON(errResp.ToJson())
+	}
+
+	if req.AtPeriodEnd && !req.EffectiveAt.IsZero() {
+		errResp := domainerrors.NewErrorResponseWithOpts(nil, domainerrors.EINVALID, "effective_at and at_period_end are mutually exclusive, provide any one")
+		h.logger.Error("effective_at and at_period_end are mutually exclusive, provide any one", zap.Reflect("error", errResp))
+		return ctx.Status(errResp.Status).JSON(errResp.ToJson())
+	}
+
+	c := context.WithValue(ctx.UserContext(), constants.TenantSlugKey, tenantSlug)
+
+	fromPlanID, err := getPlanIDFromIdentifier(c, req.PlanIDOrSlug, h.planSvc)
+	if err != nil {
+		errResp := domainerrors.NewErrorResponseWithOpts(err, domainerrors.EINVALID, "invalid plan id or slug")
+		h.logger.Error("invalid plan id or slug", zap.Reflect("error", errResp))
+		return ctx.Status(errResp.Status).JSON(errResp.ToJson())
+	}
+
+	arg := models.SchedulePlanChangeInput{
+		Type:           models.PlanChangeType(req.Type),
+		OrganizationID: req.OrganizationID,
+		UserID:         req.UserID,
+		FromPlanID:     *fromPlanID,
+		ToPlanVersion:  req.ToPlanVersion,
+		EffectiveAt:    req.EffectiveAt,
+		AtPeriodEnd:    req.AtPeriodEnd,
+		Period:         models.MeteredResetPeriod(req.Period),
+		Reason:         req.Reason,
+		CreatedBy:      req.CreatedBy,
+	}
+	if req.ToPlanIDOrSlug != "" {
+		arg.ToPlanID, err = getPlanIDFromIdentifier(c, req.ToPlanIDOrSlug, h.planSvc)
+		if err != nil {
+			errResp := domainerrors.NewErrorResponseWithOpts(err, domainerrors.EINVALID, "invalid to_plan id or slug")
+			h.logger.Error("invalid to_plan id or slug", zap.Reflect("error", errResp))
+			return ctx.Status(errResp.Status).JSON(errResp.ToJson())
+		}
+	}
+
+	change, err := h.planSvc.SchedulePlanChange(c, arg)
+	if err != nil {
+		h.logger.Error("failed to schedule plan change", zap.Reflect("error", err))
+		errResp := domainerrors.NewErrorResponse(err)
+		return ctx.Status(errResp.Status).JSON(errResp.ToJson())
+	}
+
+	return ctx.
+		Status(fiber.StatusCreated).JSON(models.NewHttpResponse(change, "plan change scheduled successfully", fiber.StatusCreated))
+}
+
+// @Summary List plan changes
+// @Description Get the scheduled plan changes of the tenant, latest effective first, optionally for one organization or user and by status: pending until they take effect, applied afterwards, or canceled.
+// @Tags plan-assignments
+// @Accept json
+// @Produce json
+// @Param X-Tenant-Slug header string true "Tenant Slug"
+// @Param organization_id query string false "Organization ID"
+// @Param user_id query string false "User ID"
+// @Param status query string false "Status (pending/applied/canceled)"
+// @Param page query integer false "Page number"
+// @Param limit query integer false "Items per page"
+// @Success 200 {object} models.HttpResponse[[]models.PlanChange] "Plan changes retrieved successfully"
+// @Failure 400 {object} domainerrors.ErrorResponse "Invalid request"
+// @Failure 500 {object} domainerrors.ErrorResponse "Internal server error"
+// @Router /v1/plans/assignments/changes [get]
+func (h *httpHandler) listChanges(ctx *fiber.Ctx) error {
+	tenantSlug := ctx.Get(constants.TenantHeader)
+
+	paginationInput := pagination.ExtractPaginationFromContext(ctx)
+
+	c := context.WithValue(ctx.UserContext(), constants.TenantSlugKey, tenantSlug)
+
+	changes, err := h.planSvc.ListPlanChanges(c, models.QueryPlanChangeInput{
+		OrganizationID: ctx.Query("organization_id"),
+		UserID:         ctx.Query("user_id"),
+		Status:         models.PlanChangeStatus(ctx.Query("status")),
+	}, paginationInput)
+	if err != nil {
+		h.logger.Error("failed to list plan changes", zap.Reflect("error", err))
+		errResp := domainerrors.NewErrorResponse(err)
+		return ctx.Status(errResp.Status).JSON(errResp.ToJson())
+	}
+
+	return ctx.
+		Status(fiber.StatusOK).JSON(models.NewHttpResponse(changes, "plan changes retrieved successfully", fiber.StatusOK))
+}
+
+// @Summary Get a plan change
+// @Description Get a scheduled plan change by ID
+// @Tags plan-assignments
+// @Accept json
+// @Produce json
+// @Param X-Tenant-Slug header string true "Tenant Slug"
+// @Param id path string true "Plan change ID"
+// @Success 200 {object} models.HttpResponse[models.PlanChange] "Plan change retrieved successfully"
+// @Failure 400 {object} domainerrors.ErrorResponse "Invalid request"
+// @Failure 404 {object} domainerrors.ErrorResponse "Plan change not found"
+// @Failure 500 {object} domainerrors.ErrorResponse "Internal server error"
+// @Router /v1/plans/assignments/changes/{id} [get]
+func (h *httpHandler) getChange(ctx *fiber.Ctx) error {
+	tenantSlug := ctx.Get(constants.TenantHeader)
+
+	id, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		errResp := domainerrors.NewErrorResponseWithOpts(err, domainerrors.EINVALID, "invalid plan change ID format")
+		h.logger.Error("invalid plan change ID format", zap.Reflect("error", errResp))
+		return ctx.Status(errResp.Status).JSON(errResp.ToJson())
+	}
+
+	c := context.WithValue(ctx.UserContext(), constants.TenantSlugKey, tenantSlug)
+
+	change, err := h.planSvc.GetPlanChange(c, id)
+	if err != nil {
+		h.logger.Error("failed to get plan change", zap.String("id", id.String()), zap.Reflect("error", err))
+		errResp := domainerrors.NewErrorResponse(err)
+		return ctx.Status(errResp.Status).JSON(errResp.ToJson())
+	}
+
+	return ctx.
+		Status(fiber.StatusOK).JSON(models.NewHttpResponse(change, "plan change retrieved successfully", fiber.StatusOK))
+}
+
+// @Summary Cancel a plan change
+// @Description Cancel a pending plan change: the assignment of the new plan is removed and the current assignment gets back the end it had before the change was scheduled. Changes that already took effect cannot be canceled.
+// @Tags plan-assignments
+// @Accept json
+// @Produce json
+// @Param X-Tenant-Slug header string true "Tenant Slug"
+// @Param id path string true "Plan change ID"
+// @Param cancel body cancelPlanChangeRequest true "Cancellation information"
+// @Success 200 {object} models.HttpResponse[models.PlanChange] "Plan change canceled successfully"
+// @Failure 400 {object} domainerrors.ErrorResponse "Invalid request"
+// @Failure 404 {object} domainerrors.ErrorResponse "Plan change not found"
+// @Failure 409 {object} domainerrors.ErrorResponse "Plan change is no longer pending"
+// @Failure 500 {object} domainerrors.ErrorResponse "Internal server error"
+// @Router /v1/plans/assignments/changes/{id}/cancel [post]
+func (h *httpHandler) cancelChange(ctx *fiber.Ctx) error {
+	tenantSlug := ctx.Get(constants.TenantHeader)
+
+	id, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		errResp := domainerrors.NewErrorResponseWithOpts(err, domainerrors.EINVALID, "invalid plan change ID format")
+		h.logger.Error("invalid plan change ID format", zap.Reflect("error", errResp))
+		return ctx.Status(errResp.Status).JSON(errResp.ToJson())
+	}
+
+	var req cancelPlanChangeRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		errResp := domainerrors.NewErrorResponseWithOpts(err, domainerrors.EINVALID, "failed to parse request body")
+		h.logger.Error("failed to parse request body", zap.Reflect("error", errResp))
+		return ctx.Status(errResp.Status).JSON(errResp.ToJson())
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		errResp := domainerrors.NewErrorResponseWithOpts(err, domainerrors.EINVALID, "invalid request body")
+		h.logger.Error("invalid request body", zap.Reflect("error", errResp))
+		return ctx.Status(errResp.Status).JSON(errResp.ToJson())
+	}
+
+	c := context.WithValue(ctx.UserContext(), constants.TenantSlugKey, tenantSlug)
+
+	change, err := h.planSvc.CancelPlanChange(c, id, req.CanceledBy)
+	if err != nil {
+		h.logger.Error("failed to cancel plan change", zap.String("id", id.String()), zap.Reflect("error", err))
+		errResp := domainerrors.NewErrorResponse(err)
+		return ctx.Status(errResp.Status).JSON(errResp.ToJson())
+	}
+
+	return ctx.
+		Status(fiber.StatusOK).JSON(models.NewHttpResponse(change, "plan change canceled successfully", fiber.StatusOK))
+}
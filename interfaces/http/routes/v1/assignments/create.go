@@ -13,6 +13,7 @@ import (
 
 type createAssignmentRequest struct {
 	PlanIDOrSlug   string    `json:"plan_id_or_slug" validate:"required"`
+	PlanVersion    int       `json:"plan_version,omitempty" validate:"omitempty,min=1"`
 	OrganizationID string    `json:"organization_id"`
 	UserID         string    `json:"user_id"`
 	ValidFrom      time.Time `json:"valid_from" validate:"required"`
@@ -21,7 +22,7 @@ type createAssignmentRequest struct {
 }
 
 // @Summary Create a new plan assignment
-// @Description Assign a plan to either an organization or a user
+// @Description Assign a plan to either an organization or a user. The assignment is pinned to plan_version when given; otherwise a renewal keeps the subject's previous version (or the version it was scheduled to migrate to) and new subscribers get the latest published version. A subject holds at most one base plan at a time, while add-on plans stack on top of it. A plan is refused when one of its quotas resets over another period than the quota of the same feature in the plans held with it: the base plan for an add-on, the add-ons for a base plan.
 // @Tags plan-assignments
 // @Accept json
 // @Produce json
@@ -30,6 +31,7 @@ type createAssignmentRequest struct {
 // @Success 201 {object} models.HttpResponse[models.PlanAssignment] "Plan assignment created successfully"
 // @Failure 400 {object} domainerrors.ErrorResponse "Invalid request"
 // @Failure 404 {object} domainerrors.ErrorResponse "Plan not found"
+// @Failure 409 {object} domainerrors.ErrorResponse "Subject already holds a base plan over the period"
 // @Failure 500 {object} domainerrors.ErrorResponse "Internal server error"
 // @Router /v1/plans/assignments [post]
 func (h *httpHandler) create(ctx *fiber.Ctx) error {
@@ -84,6 +86,7 @@ func (h *httpHandler) create(ctx *fiber.Ctx) error {
 
 	planAssignment, err := h.planSvc.CreateAssignment(c, models.CreateAssignmentInput{
 		PlanID:         planID,
+		PlanVersion:    req.PlanVersion,
 		OrganizationID: req.OrganizationID,
 		UserID:         req.UserID,
 		ValidFrom:      req.ValidFrom,
@@ -176,7 +176,7 @@ func (h *httpHandler) list(ctx *fiber.Ctx) error {
 // @Param valid_from_after query string false "Valid from after date (format: YYYY-MM-DDThh:mm:ssZ)"
 // @Param valid_until_before query string false "Valid until before date (format: YYYY-MM-DDThh:mm:ssZ)"
 // @Param valid_until_after query string false "Valid until after date (format: YYYY-MM-DDThh:mm:ssZ)"
-// @Param action query string false "Action type (Create, Update, Delete)"
+// @Param action query string false "Action type (CREATE, UPDATE, DELETE, TRIAL_CONVERTED, TRIAL_FELL_BACK, TRIAL_EXPIRED)"
 // @Param plan_id_or_slug query string false "Plan ID or slug"
 // @Param org_id query string false "Organization ID"
 // @Param user_id query string false "User ID"
@@ -245,8 +245,8 @@ func (h *httpHandler) listhistory(ctx *fiber.Ctx) error {
 	}
 
 	if action != "" && !models.ValidateHistoryAction(action) {
-		errResp := domainerrors.NewErrorResponseWithOpts(nil, domainerrors.EINVALID, "provide valid action : Create, Update and Delete")
-		h.logger.Error("provide valid action : Insert, Update and Delete", zap.Reflect("error", errResp))
+		errResp := domainerrors.NewErrorResponseWithOpts(nil, domainerrors.EINVALID, "provide valid action : CREATE, UPDATE, DELETE, TRIAL_CONVERTED, TRIAL_FELL_BACK or TRIAL_EXPIRED")
+		h.logger.Error("provide valid action : CREATE, UPDATE, DELETE, TRIAL_CONVERTED, TRIAL_FELL_BACK or TRIAL_EXPIRED", zap.Reflect("error", errResp))
 		return ctx.Status(errResp.Status).JSON(errResp.ToJson())
 	}
 
@@ -33,6 +33,14 @@ func (h *httpHandler) RegisterRoutes(r fiber.Router) {
 	assignments.Post("/", h.create)
 	assignments.Put("/", h.update)
 	assignments.Delete("/", h.delete)
+	assignments.Post("/changes", h.scheduleChange)
+	assignments.Get("/changes", h.listChanges)
+	assignments.Get("/changes/:id", h.getChange)
+	assignments.Post("/changes/:id/cancel", h.cancelChange)
+	assignments.Post("/trials", h.createTrial)
+	assignments.Get("/trials", h.listTrials)
+	assignments.Get("/trials/:id", h.getTrial)
+	assignments.Post("/trials/:id/extend", h.extendTrial)
 }
 
 // getPlanIDFromIdentifier retrieves the UUID of a plan given its ID or slug identifier.
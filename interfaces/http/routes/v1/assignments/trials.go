@@ -0,0 +1,243 @@
+package assignments
+
+import (
+	"context"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	domainerrors "github.com/redcardinal-io/metering/domain/errors"
+	"github.com/redcardinal-io/metering/domain/models"
+	"github.com/redcardinal-io/metering/domain/pkg/constants"
+	"github.com/redcardinal-io/metering/domain/pkg/pagination"
+	"go.uber.org/zap"
+)
+
+type createTrialRequest struct {
+	PlanIDOrSlug         string    `json:"plan_id_or_slug" validate:"required"`
+	PlanVersion          int       `json:"plan_version,omitempty" validate:"omitempty,min=1"`
+	OrganizationID       string    `json:"organization_id"`
+	UserID               string    `json:"user_id"`
+	ValidFrom            time.Time `json:"valid_from,omitempty"`
+	TrialDays            int       `json:"trial_days" validate:"required,min=1"`
+	TargetPlanIDOrSlug   string    `json:"target_plan_id_or_slug" validate:"required"`
+	FallbackPlanIDOrSlug string    `json:"fallback_plan_id_or_slug,omitempty"`
+	EndAction            string    `json:"end_action,omitempty" validate:"omitempty,oneof=convert fallback expire"`
+	CreatedBy            string    `json:"created_by" validate:"required"`
+}
+
+type extendTrialRequest struct {
+	Days      int    `json:"days" validate:"required,min=1"`
+	UpdatedBy string `json:"updated_by" validate:"required"`
+}
+
+// @Summary Start a trial
+// @Description Assign a plan to an organization or user as a trial lasting trial_days from valid_from, or from now when it is not given. When the trial ends the subject is converted to the target plan (end_action convert, the default), moved onto the fallback plan (fallback), or left without a plan (expire). Conversions move onto the fallback plan when the target plan can no longer be assigned, and trials expire when neither can. The plan the trial ends on starts when the trial ends and is open-ended; the outcome is recorded in the assignment history and sent as a trial.ended notification.
+// @Tags plan-assignments
+// @Accept json
+// @Produce json
+// @Param X-Tenant-Slug header string true "Tenant Slug"
+// @Param trial body createTrialRequest true "Trial information"
+// @Success 201 {object} models.HttpResponse[models.Trial] "Trial started successfully"
+// @Failure 400 {object} domainerrors.ErrorResponse "Invalid request"
+// @Failure 409 {object} domainerrors.ErrorResponse "Subject already holds a base plan over the trial"
+// @Failure 500 {object} domainerrors.ErrorResponse "Internal server error"
+// @Router /v1/plans/assignments/trials [post]
+func (h *httpHandler) createTrial(ctx *fiber.Ctx) error {
+	tenantSlug := ctx.Get(constants.TenantHeader)
+	var req createTrialRequest
+
+	if err := ctx.BodyParser(&req); err != nil {
+		errResp := domainerrors.NewErrorResponseWithOpts(err, domainerrors.EINVALID, "failed to parse request body")
+		h.logger.Error("failed to parse request body", zap.Reflect("error", errResp))
+		return ctx.Status(errResp.Status).JSON(errResp.ToJson())
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		errResp := domainerrors.NewErrorResponseWithOpts(err, domainerrors.EINVALID, "invalid request body")
+		h.logger.Error("invalid request body", zap.Reflect("error", errResp))
+		return ctx.Status(errResp.Status).JSON(errResp.ToJson())
+	}
+
+	if req.OrganizationID != "" && req.UserID != "" {
+		errResp := domainerrors.NewErrorResponseWithOpts(nil, domainerrors.EINVALID, "organization_id and user_id are mutually exclusive, provide any one")
+		h.logger.Error("organization_id and user_id are mutually exclusive, provide any one", zap.Reflect("error", errResp))
+		return ctx.Status(errResp.Status).JSON(errResp.ToJson())
+	}
+
+	if req.OrganizationID == "" && req.UserID == "" {
+		errResp := domainerrors.NewErrorResponseWithOpts(nil, domainerrors.EINVALID, "organization_id or user_id is required")
+		h.logger.Error("organization_id or user_id is required", zap.Reflect("error", errResp))
+		return ctx.Status(errResp.Status).JSON(errResp.ToJson())
+	}
+
+	c := context.WithValue(ctx.UserContext(), constants.TenantSlugKey, tenantSlug)
+
+	planID, err := getPlanIDFromIdentifier(c, req.PlanIDOrSlug, h.planSvc)
+	if err != nil {
+		errResp := domainerrors.NewErrorResponseWithOpts(err, domainerrors.EINVALID, "invalid plan id or slug")
+		h.logger.Error("invalid plan id or slug", zap.Reflect("error", errResp))
+		return ctx.Status(errResp.Status).JSON(errResp.ToJson())
+	}
+
+	targetPlanID, err := getPlanIDFromIdentifier(c, req.TargetPlanIDOrSlug, h.planSvc)
+	if err != nil {
+		errResp := domainerrors.NewErrorResponseWithOpts(err, domainerrors.EINVALID, "invalid target_plan id or slug")
+		h.logger.Error("invalid target_plan id or slug", zap.Reflect("error", errResp))
+		return ctx.Status(errResp.Status).JSON(errResp.ToJson())
+	}
+
+	arg := models.CreateTrialInput{
+		Assignment: models.CreateAssignmentInput{
+			OrganizationID: req.OrganizationID,
+			UserID:         req.UserID,
+			PlanID:         planID,
+			PlanVersion:    req.PlanVersion,
+			ValidFrom:      req.ValidFrom,
+			CreatedBy:      req.CreatedBy,
+		},
+		TrialDays:    req.TrialDays,
+		TargetPlanID: targetPlanID,
+		EndAction:    models.TrialEndAction(req.EndAction),
+	}
+	if req.FallbackPlanIDOrSlug != "" {
+		arg.FallbackPlanID, err = getPlanIDFromIdentifier(c, req.FallbackPlanIDOrSlug, h.planSvc)
+		if err != nil {
+			errResp := domainerrors.NewErrorResponseWithOpts(err, domainerrors.EINVALID, "invalid fallback_plan id or slug")
+			h.logger.Error("invalid fallback_plan id or slug", zap.Reflect("error", errResp))
+			return ctx.Status(errResp.Status).JSON(errResp.ToJson())
+		}
+	}
+
+	trial, err := h.planSvc.CreateTrial(c, arg)
+	if err != nil {
+		h.logger.Error("failed to start trial", zap.Reflect("error", err))
+		errResp := domainerrors.NewErrorResponse(err)
+		return ctx.Status(errResp.Status).JSON(errResp.ToJson())
+	}
+
+	return ctx.
+		Status(fiber.StatusCreated).JSON(models.NewHttpResponse(trial, "trial started successfully", fiber.StatusCreated))
+}
+
+// @Summary List trials
+// @Description Get the trials of the tenant, ending soonest first, with the days they have left, optionally for one organization or user and by status (active, converted, fell_back or expired).
+// @Tags plan-assignments
+// @Accept json
+// @Produce json
+// @Param X-Tenant-Slug header string true "Tenant Slug"
+// @Param organization_id query string false "Organization ID"
+// @Param user_id query string false "User ID"
+// @Param status query string false "Status (active/converted/fell_back/expired)"
+// @Param page query integer false "Page number"
+// @Param limit query integer false "Items per page"
+// @Success 200 {object} models.HttpResponse[[]models.Trial] "Trials retrieved successfully"
+// @Failure 400 {object} domainerrors.ErrorResponse "Invalid request"
+// @Failure 500 {object} domainerrors.ErrorResponse "Internal server error"
+// @Router /v1/plans/assignments/trials [get]
+func (h *httpHandler) listTrials(ctx *fiber.Ctx) error {
+	tenantSlug := ctx.Get(constants.TenantHeader)
+
+	paginationInput := pagination.ExtractPaginationFromContext(ctx)
+
+	c := context.WithValue(ctx.UserContext(), constants.TenantSlugKey, tenantSlug)
+
+	trials, err := h.planSvc.ListTrials(c, models.QueryTrialInput{
+		OrganizationID: ctx.Query("organization_id"),
+		UserID:         ctx.Query("user_id"),
+		Status:         models.TrialStatus(ctx.Query("status")),
+	}, paginationInput)
+	if err != nil {
+		h.logger.Error("failed to list trials", zap.Reflect("error", err))
+		errResp := domainerrors.NewErrorResponse(err)
+		return ctx.Status(errResp.Status).JSON(errResp.ToJson())
+	}
+
+	return ctx.
+		Status(fiber.StatusOK).JSON(models.NewHttpResponse(trials, "trials retrieved successfully", fiber.StatusOK))
+}
+
+// @Summary Get a trial
+// @Description Get a trial by ID with the days it has left
+// @Tags plan-assignments
+// @Accept json
+// @Produce json
+// @Param X-Tenant-Slug header string true "Tenant Slug"
+// @Param id path string true "Trial ID"
+// @Success 200 {object} models.HttpResponse[models.Trial] "Trial retrieved successfully"
+// @Failure 400 {object} domainerrors.ErrorResponse "Invalid request"
+// @Failure 404 {object} domainerrors.ErrorResponse "Trial not found"
+// @Failure 500 {object} domainerrors.ErrorResponse "Internal server error"
+// @Router /v1/plans/assignments/trials/{id} [get]
+func (h *httpHandler) getTrial(ctx *fiber.Ctx) error {
+	tenantSlug := ctx.Get(constants.TenantHeader)
+
+	id, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		errResp := domainerrors.NewErrorResponseWithOpts(err, domainerrors.EINVALID, "invalid trial ID format")
+		h.logger.Error("invalid trial ID format", zap.Reflect("error", errResp))
+		return ctx.Status(errResp.Status).JSON(errResp.ToJson())
+	}
+
+	c := context.WithValue(ctx.UserContext(), constants.TenantSlugKey, tenantSlug)
+
+	trial, err := h.planSvc.GetTrial(c, id)
+	if err != nil {
+		h.logger.Error("failed to get trial", zap.String("id", id.String()), zap.Reflect("error", err))
+		errResp := domainerrors.NewErrorResponse(err)
+		return ctx.Status(errResp.Status).JSON(errResp.ToJson())
+	}
+
+	return ctx.
+		Status(fiber.StatusOK).JSON(models.NewHttpResponse(trial, "trial retrieved successfully", fiber.StatusOK))
+}
+
+// @Summary Extend a trial
+// @Description Move the end of an active trial the given number of days further. Trials that already ended cannot be extended.
+// @Tags plan-assignments
+// @Accept json
+// @Produce json
+// @Param X-Tenant-Slug header string true "Tenant Slug"
+// @Param id path string true "Trial ID"
+// @Param extension body extendTrialRequest true "Extension information"
+// @Success 200 {object} models.HttpResponse[models.Trial] "Trial extended successfully"
+// @Failure 400 {object} domainerrors.ErrorResponse "Invalid request"
+// @Failure 404 {object} domainerrors.ErrorResponse "Trial not found"
+// @Failure 409 {object} domainerrors.ErrorResponse "Trial is no longer active or the extension overlaps another base plan"
+// @Failure 500 {object} domainerrors.ErrorResponse "Internal server error"
+// @Router /v1/plans/assignments/trials/{id}/extend [post]
+func (h *httpHandler) extendTrial(ctx *fiber.Ctx) error {
+	tenantSlug := ctx.Get(constants.TenantHeader)
+
+	id, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		errResp := domainerrors.NewErrorResponseWithOpts(err, domainerrors.EINVALID, "invalid trial ID format")
+		h.logger.Error("invalid trial ID format", zap.Reflect("error", errResp))
+		return ctx.Status(errResp.Status).JSON(errResp.ToJson())
+	}
+
+	var req extendTrialRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		errResp := domainerrors.NewErrorResponseWithOpts(err, domainerrors.EINVALID, "failed to parse request body")
+		h.logger.Error("failed to parse request body", zap.Reflect("error", errResp))
+		return ctx.Status(errResp.Status).JSON(errResp.ToJson())
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		errResp := domainerrors.NewErrorResponseWithOpts(err, domainerrors.EINVALID, "invalid request body")
+		h.logger.Error("invalid request body", zap.Reflect("error", errResp))
+		return ctx.Status(errResp.Status).JSON(errResp.ToJson())
+	}
+
+	c := context.WithValue(ctx.UserContext(), constants.TenantSlugKey, tenantSlug)
+
+	trial, err := h.planSvc.ExtendTrial(c, id, req.Days, req.UpdatedBy)
+	if err != nil {
+		h.logger.Error("failed to extend trial", zap.String("id", id.String()), zap.Reflect("error", err))
+		errResp := domainerrors.NewErrorResponse(err)
+		return ctx.Status(errResp.Status).JSON(errResp.ToJson())
+	}
+
+	return ctx.
+		Status(fiber.StatusOK).JSON(models.NewHttpResponse(trial, "trial extended successfully", fiber.StatusOK))
+}
@@ -16,11 +16,13 @@ type createFeatureRequest struct {
 	Slug        string         `json:"slug" validate:"required"`
 	Type        string         `json:"type" validate:"required,oneof=static metered"`
 	Config      map[string]any `json:"config" validate:"omitempty"`
+	MeterSlugs  []string       `json:"meter_slugs" validate:"omitempty,dive,required"`
+	MergeRule   string         `json:"merge_rule,omitempty" validate:"omitempty,oneof=sum max"`
 	CreatedBy   string         `json:"created_by" validate:"required"`
 }
 
 // @Summary Create a new feature
-// @Description Create a new feature for the tenant
+// @Description Create a new feature for the tenant. Metered features can link the meters their usage is measured by, and merge_rule (sum by default, or max) sets how the quotas of a base plan and its add-ons granting the feature combine.
 // @Tags features
 // @Accept json
 // @Produce json
@@ -56,6 +58,8 @@ func (h *httpHandler) create(ctx *fiber.Ctx) error {
 		Type:        models.FeatureTypeEnum(req.Type),
 		TenantSlug:  tenant_slug,
 		Config:      req.Config,
+		MeterSlugs:  req.MeterSlugs,
+		MergeRule:   models.QuotaMergeRule(req.MergeRule),
 		CreatedBy:   req.CreatedBy,
 	})
 	if err != nil {
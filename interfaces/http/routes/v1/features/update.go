@@ -14,6 +14,8 @@ type updateFeatureRequest struct {
 	Name        string         `json:"name,omitempty" validate:"omitempty,min=3,max=100"`
 	Description string         `json:"description" validate:"omitempty,min=10,max=255"`
 	Config      map[string]any `json:"config" validate:"omitempty"`
+	MeterSlugs  []string       `json:"meter_slugs" validate:"omitempty,dive,required"`
+	MergeRule   string         `json:"merge_rule,omitempty" validate:"omitempty,oneof=sum max"`
 	UpdatedBy   string         `json:"updated_by" validate:"required,min=3,max=100"`
 }
 
@@ -52,9 +54,9 @@ func (h *httpHandler) updateByIDorSlug(ctx *fiber.Ctx) error {
 		h.logger.Error("invalid request body", zap.Reflect("error", errResp))
 		return ctx.Status(errResp.Status).JSON(errResp.ToJson())
 	}
-	if req.Name == "" && req.Description == "" && req.Config == nil {
-		errResp := domainerrors.NewErrorResponseWithOpts(nil, domainerrors.EINVALID, "at least one field (name or description or config) is required")
-		h.logger.Error("at least one field (name or description or config) is required ", zap.Reflect("error", errResp))
+	if req.Name == "" && req.Description == "" && req.Config == nil && req.MeterSlugs == nil && req.MergeRule == "" {
+		errResp := domainerrors.NewErrorResponseWithOpts(nil, domainerrors.EINVALID, "at least one field (name or description or config or meter_slugs or merge_rule) is required")
+		h.logger.Error("at least one field (name or description or config or meter_slugs or merge_rule) is required ", zap.Reflect("error", errResp))
 		return ctx.Status(errResp.Status).JSON(errResp.ToJson())
 	}
 
@@ -64,6 +66,8 @@ func (h *httpHandler) updateByIDorSlug(ctx *fiber.Ctx) error {
 		Name:        req.Name,
 		UpdatedBy:   req.UpdatedBy,
 		Config:      req.Config,
+		MeterSlugs:  req.MeterSlugs,
+		MergeRule:   models.QuotaMergeRule(req.MergeRule),
 		Description: req.Description,
 	})
 	if err != nil {
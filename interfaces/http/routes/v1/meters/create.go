@@ -12,19 +12,25 @@ import (
 )
 
 type createMeterRequest struct {
-	Name          string   `json:"name" validate:"required"`
-	Slug          string   `json:"slug" validate:"required"`
-	EventType     string   `json:"event_type" validate:"required"`
-	Description   string   `json:"description,omitempty"`
-	ValueProperty string   `json:"value_property,omitempty"`
-	Properties    []string `json:"properties" validate:"required,min=1"`
-	Aggregation   string   `json:"aggregation" validate:"required,oneof=count sum avg unique_count min max"`
-	CreatedBy     string   `json:"created_by" validate:"required"`
-	Populate      bool     `json:"populate" validate:"required"`
+	Name            string                   `json:"name" validate:"required"`
+	Slug            string                   `json:"slug" validate:"required"`
+	Type            string                   `json:"type,omitempty" validate:"omitempty,oneof=standard derived"`
+	EventType       string                   `json:"event_type,omitempty"`
+	EventTypes      []string                 `json:"event_types,omitempty" validate:"omitempty,dive,required"`
+	Description     string                   `json:"description,omitempty"`
+	ValueProperty   string                   `json:"value_property,omitempty"`
+	ValueProperties map[string]string        `json:"value_properties,omitempty"`
+	Properties      []string                 `json:"properties" validate:"required_unless=Type derived,omitempty,min=1"`
+	Aggregation     string                   `json:"aggregation" validate:"required_unless=Type derived,omitempty,oneof=count sum avg unique_count min max"`
+	Formula         string                   `json:"formula,omitempty" validate:"required_if=Type derived"`
+	Granularity     string                   `json:"granularity,omitempty" validate:"omitempty,oneof=second minute hour day"`
+	Filter          *models.FilterExpression `json:"filter,omitempty"`
+	CreatedBy       string                   `json:"created_by" validate:"required"`
+	Populate        bool                     `json:"populate" validate:"required_unless=Type derived"`
 }
 
 // @Summary Create a new meter
-// @Description Create a new meter for the tenant
+// @Description Create a new meter for the tenant. Derived meters set a formula over the slugs of other meters, writing slugs that are not plain identifiers between brackets, e.g. "[api-calls] / requests"
 // @Tags meters
 // @Accept json
 // @Produce json
@@ -51,9 +57,11 @@ func (h *httpHandler) create(ctx *fiber.Ctx) error {
 	}
 
 	valueProperty := req.ValueProperty
-	if req.Aggregation == string(models.AggregationCount) {
+	if req.Type == string(models.MeterTypeDerived) {
 		valueProperty = ""
-	} else if valueProperty == "" {
+	} else if req.Aggregation == string(models.AggregationCount) {
+		valueProperty = ""
+	} else if valueProperty == "" && len(req.ValueProperties) == 0 {
 		errResp := domainerrors.NewErrorResponseWithOpts(errors.New("value_property is required"), domainerrors.EINVALID, "value_property is required")
 		h.logger.Error("value_property is required", zap.Reflect("error", errResp))
 		return ctx.Status(errResp.Status).JSON(errResp.ToJson())
@@ -62,15 +70,21 @@ func (h *httpHandler) create(ctx *fiber.Ctx) error {
 	c := context.WithValue(ctx.UserContext(), constants.TenantSlugKey, tenant_slug)
 
 	meter, err := h.meterSvc.CreateMeter(c, models.CreateMeterInput{
-		Name:          req.Name,
-		MeterSlug:     req.Slug,
-		EventType:     req.EventType,
-		Description:   req.Description,
-		ValueProperty: valueProperty,
-		Properties:    req.Properties,
-		Aggregation:   models.AggregationEnum(req.Aggregation),
-		Populate:      req.Populate,
-		CreatedBy:     req.CreatedBy,
+		Name:            req.Name,
+		MeterSlug:       req.Slug,
+		Type:            models.MeterTypeEnum(req.Type),
+		EventType:       req.EventType,
+		EventTypes:      req.EventTypes,
+		Description:     req.Description,
+		ValueProperty:   valueProperty,
+		ValueProperties: req.ValueProperties,
+		Properties:      req.Properties,
+		Aggregation:     models.AggregationEnum(req.Aggregation),
+		Formula:         req.Formula,
+		Granularity:     models.Granularity(req.Granularity),
+		Filter:          req.Filter,
+		Populate:        req.Populate,
+		CreatedBy:       req.CreatedBy,
 	})
 	if err != nil {
 		h.logger.Error("failed to create meter", zap.Reflect("error", err))
@@ -0,0 +1,99 @@
+package meters
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	domainerrors "github.com/redcardinal-io/metering/domain/errors"
+	"github.com/redcardinal-io/metering/domain/models"
+	"github.com/redcardinal-io/metering/domain/pkg/constants"
+	"github.com/redcardinal-io/metering/domain/pkg/export"
+	"go.uber.org/zap"
+)
+
+type exportMeterRequest struct {
+	queryMeterRequest
+	// Format takes precedence over the Accept header
+	Format models.ExportFormat `json:"format" validate:"omitempty,oneof=csv ndjson parquet"`
+}
+
+// exportFormat resolves the export format from the request body or the Accept header, defaulting to CSV.
+func exportFormat(ctx *fiber.Ctx, format models.ExportFormat) models.ExportFormat {
+	if format != "" {
+		return format
+	}
+	switch ctx.Accepts(
+		export.ContentType(models.ExportFormatCSV),
+		export.ContentType(models.ExportFormatNDJSON),
+		export.ContentType(models.ExportFormatParquet),
+		"application/parquet",
+	) {
+	case export.ContentType(models.ExportFormatNDJSON):
+		return models.ExportFormatNDJSON
+	case export.ContentType(models.ExportFormatParquet), "application/parquet":
+		return models.ExportFormatParquet
+	default:
+		return models.ExportFormatCSV
+	}
+}
+
+// @Summary Export meter data
+// @Description Stream meter query results as a CSV, NDJSON or Parquet file. The format is taken from the body or the Accept header.
+// @Tags meters
+// @Accept json
+// @Produce text/csv,application/x-ndjson,application/vnd.apache.parquet
+// @Param X-Tenant-Slug header string true "Tenant Slug"
+// @Param query body exportMeterRequest true "Export parameters"
+// @Success 200 {file} file "Exported meter data"
+// @Failure 400 {object} domainerrors.ErrorResponse "Invalid request"
+// @Failure 404 {object} domainerrors.ErrorResponse "Meter not found"
+// @Failure 500 {object} domainerrors.ErrorResponse "Internal server error"
+// @Router /v1/meters/export [post]
+func (h *httpHandler) export(ctx *fiber.Ctx) error {
+	tenantSlug := ctx.Get(constants.TenantHeader)
+
+	var req exportMeterRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		errResp := domainerrors.NewErrorResponseWithOpts(err, domainerrors.EINVALID, "failed to parse request body")
+		h.logger.Error("failed to parse request body", zap.Reflect("error", errResp))
+		return ctx.Status(errResp.Status).JSON(errResp.ToJson())
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		errResp := domainerrors.NewErrorResponseWithOpts(err, domainerrors.EINVALID, "invalid request body")
+		h.logger.Error("invalid request body", zap.Reflect("error", errResp))
+		return ctx.Status(errResp.Status).JSON(errResp.ToJson())
+	}
+
+	if req.WindowSize != nil && !models.IsValidWindowSize(req.WindowSize) {
+		errResp := domainerrors.NewErrorResponseWithOpts(nil, domainerrors.EINVALID, "invalid window_size")
+		h.logger.Error("invalid window_size", zap.Reflect("error", errResp))
+		return ctx.Status(errResp.Status).JSON(errResp.ToJson())
+	}
+
+	format := exportFormat(ctx, req.Format)
+	c := context.WithValue(ctx.UserContext(), constants.TenantSlugKey, tenantSlug)
+	stream, err := h.meterSvc.ExportMeter(c, req.toParams(), format)
+	if err != nil {
+		h.logger.Error("failed to export meter", zap.Reflect("error", err))
+		errResp := domainerrors.NewErrorResponse(err)
+		return ctx.Status(errResp.Status).JSON(errResp.ToJson())
+	}
+
+	filename := fmt.Sprintf("%s-%s.%s", req.MeterSlug, time.Now().UTC().Format("20060102T150405Z"), format)
+	ctx.Set(fiber.HeaderContentType, export.ContentType(format))
+	ctx.Set(fiber.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", filename))
+	ctx.Status(fiber.StatusOK).Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		// The status is already sent, so failures can only be logged and end the stream early
+		if err := stream(w); err != nil {
+			h.logger.Error("failed to stream meter export", zap.String("meter", req.MeterSlug), zap.Error(err))
+		}
+		if err := w.Flush(); err != nil {
+			h.logger.Error("failed to flush meter export", zap.String("meter", req.MeterSlug), zap.Error(err))
+		}
+	})
+	return nil
+}
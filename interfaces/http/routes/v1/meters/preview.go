@@ -0,0 +1,124 @@
+package meters
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	domainerrors "github.com/redcardinal-io/metering/domain/errors"
+	"github.com/redcardinal-io/metering/domain/models"
+	"github.com/redcardinal-io/metering/domain/pkg/constants"
+	"github.com/redcardinal-io/metering/domain/pkg/timeutil"
+	"go.uber.org/zap"
+)
+
+type previewEvent struct {
+	Type         string         `json:"type" validate:"required"`
+	Organization string         `json:"organization" validate:"required"`
+	User         string         `json:"user" validate:"required"`
+	Timestamp    *time.Time     `json:"timestamp"`
+	Properties   map[string]any `json:"properties"`
+}
+
+type previewMeterRequest struct {
+	Slug            string                   `json:"slug"`
+	EventType       string                   `json:"event_type,omitempty"`
+	EventTypes      []string                 `json:"event_types,omitempty" validate:"omitempty,dive,required"`
+	ValueProperty   string                   `json:"value_property,omitempty"`
+	ValueProperties map[string]string        `json:"value_properties,omitempty"`
+	Properties      []string                 `json:"properties" validate:"required,min=1"`
+	Aggregation     string                   `json:"aggregation" validate:"required,oneof=count sum avg unique_count min max"`
+	Granularity     string                   `json:"granularity,omitempty" validate:"omitempty,oneof=second minute hour day"`
+	Filter          *models.FilterExpression `json:"filter,omitempty"`
+	// Events are sample events to preview the meter against; mutually exclusive with From and To
+	Events []previewEvent `json:"events" validate:"omitempty,max=1000,dive"`
+	From   *time.Time     `json:"from"`
+	To     *time.Time     `json:"to"`
+	Limit  int            `json:"limit" validate:"omitempty,min=1,max=1000"`
+}
+
+// @Summary Preview a meter definition
+// @Description Dry-run a meter definition against sample events or a range of stored events. Returns the rows the meter would produce, the SQL that would create it and warnings about the definition. Nothing is created.
+// @Tags meters
+// @Accept json
+// @Produce json
+// @Param X-Tenant-Slug header string true "Tenant Slug"
+// @Param preview body previewMeterRequest true "Meter definition and preview source"
+// @Success 200 {object} models.HttpResponse[models.MeterPreview] "Meter previewed successfully"
+// @Failure 400 {object} domainerrors.ErrorResponse "Invalid request"
+// @Failure 500 {object} domainerrors.ErrorResponse "Internal server error"
+// @Router /v1/meters/preview [post]
+func (h *httpHandler) preview(ctx *fiber.Ctx) error {
+	tenantSlug := ctx.Get(constants.TenantHeader)
+
+	var req previewMeterRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		errResp := domainerrors.NewErrorResponseWithOpts(err, domainerrors.EINVALID, "failed to parse request body")
+		h.logger.Error("failed to parse request body", zap.Reflect("error", errResp))
+		return ctx.Status(errResp.Status).JSON(errResp.ToJson())
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		errResp := domainerrors.NewErrorResponseWithOpts(err, domainerrors.EINVALID, "invalid request body")
+		h.logger.Error("invalid request body", zap.Reflect("error", errResp))
+		return ctx.Status(errResp.Status).JSON(errResp.ToJson())
+	}
+
+	valueProperty := req.ValueProperty
+	if req.Aggregation == string(models.AggregationCount) {
+		valueProperty = ""
+	} else if valueProperty == "" && len(req.ValueProperties) == 0 {
+		errResp := domainerrors.NewErrorResponseWithOpts(errors.New("value_property is required"), domainerrors.EINVALID, "value_property is required")
+		h.logger.Error("value_property is required", zap.Reflect("error", errResp))
+		return ctx.Status(errResp.Status).JSON(errResp.ToJson())
+	}
+
+	events := make([]*models.Event, 0, len(req.Events))
+	for _, event := range req.Events {
+		timestamp := time.Now()
+		if event.Timestamp != nil {
+			timestamp = *event.Timestamp
+		}
+		properties, err := json.Marshal(event.Properties)
+		if err != nil || event.Properties == nil {
+			properties = []byte("{}")
+		}
+		events = append(events, &models.Event{
+			Type:         event.Type,
+			Organization: event.Organization,
+			User:         event.User,
+			Timestamp:    timeutil.FormatTimeUTC(&timestamp, ""),
+			Properties:   string(properties),
+		})
+	}
+
+	c := context.WithValue(ctx.UserContext(), constants.TenantSlugKey, tenantSlug)
+
+	preview, err := h.meterSvc.PreviewMeter(c, models.PreviewMeterInput{
+		Meter: models.CreateMeterInput{
+			MeterSlug:       req.Slug,
+			Type:            models.MeterTypeStandard,
+			EventType:       req.EventType,
+			EventTypes:      req.EventTypes,
+			ValueProperty:   valueProperty,
+			ValueProperties: req.ValueProperties,
+			Properties:      req.Properties,
+			Aggregation:     models.AggregationEnum(req.Aggregation),
+			Granularity:     models.Granularity(req.Granularity),
+			Filter:          req.Filter,
+		},
+		Events: events,
+		From:   req.From,
+		To:     req.To,
+		Limit:  req.Limit,
+	})
+	if err != nil {
+		h.logger.Error("failed to preview meter", zap.Reflect("error", err))
+		errResp := domainerrors.NewErrorResponse(err)
+		return ctx.Status(errResp.Status).JSON(errResp.ToJson())
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(models.NewHttpResponse(preview, "meter previewed successfully", fiber.StatusOK))
+}
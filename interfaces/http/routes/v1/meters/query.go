@@ -12,17 +12,48 @@ import (
 )
 
 type queryMeterRequest struct {
-	MeterSlug      string              `json:"meter_slug" validate:"required"`
-	FilterGroupBy  map[string][]string `json:"filter_group_by"`
-	From           *time.Time          `json:"from"`
-	To             *time.Time          `json:"to"`
-	GroupBy        []string            `json:"group_by"`
-	WindowSize     *models.WindowSize  `json:"window_size"`
-	WindowTimeZone *string             `json:"window_time_zone"`
+	MeterSlug      string                    `json:"meter_slug" validate:"required"`
+	FilterGroupBy  map[string][]string       `json:"filter_group_by"`
+	Filter         *models.FilterExpression  `json:"filter"`
+	From           *time.Time                `json:"from"`
+	To             *time.Time                `json:"to"`
+	GroupBy        []string                  `json:"group_by"`
+	WindowSize     *models.WindowSize        `json:"window_size"`
+	WindowTimeZone *string                   `json:"window_time_zone"`
+	OrderBy        *models.QueryMeterOrderBy `json:"order_by"`
+	Limit          *int                      `json:"limit" validate:"omitempty,min=1,max=10000"`
+	Offset         *int                      `json:"offset" validate:"omitempty,min=0"`
+	Cursor         string                    `json:"cursor"`
+	IncludeOthers  bool                      `json:"include_others"`
+	Fill           models.FillMode           `json:"fill"`
+	Cumulative     bool                      `json:"cumulative"`
+	Compare        models.CompareMode        `json:"compare"`
+}
+
+// toParams converts the request into service query parameters.
+func (req queryMeterRequest) toParams() models.QueryMeterParams {
+	return models.QueryMeterParams{
+		MeterSlug:      req.MeterSlug,
+		FilterGroupBy:  req.FilterGroupBy,
+		Filter:         req.Filter,
+		From:           req.From,
+		To:             req.To,
+		GroupBy:        req.GroupBy,
+		WindowSize:     req.WindowSize,
+		WindowTimeZone: req.WindowTimeZone,
+		OrderBy:        req.OrderBy,
+		Limit:          req.Limit,
+		Offset:         req.Offset,
+		Cursor:         req.Cursor,
+		IncludeOthers:  req.IncludeOthers,
+		Fill:           req.Fill,
+		Cumulative:     req.Cumulative,
+		Compare:        req.Compare,
+	}
 }
 
 // @Summary Query meter data
-// @Description Query meter data with filters and grouping options
+// @Description Query meter data with filters and grouping options. Set cumulative to return running totals per group across windows, or compare to previous_period to add the value of the preceding period of the same length and the change against it to each row.
 // @Tags meters
 // @Accept json
 // @Produce json
@@ -55,16 +86,20 @@ func (h *httpHandler) query(ctx *fiber.Ctx) error {
 		return ctx.Status(errResp.Status).JSON(errResp.ToJson())
 	}
 
+	if req.Fill != "" && !models.IsValidFillMode(req.Fill) {
+		errResp := domainerrors.NewErrorResponseWithOpts(nil, domainerrors.EINVALID, "invalid fill")
+		h.logger.Error("invalid fill", zap.Reflect("error", errResp))
+		return ctx.Status(errResp.Status).JSON(errResp.ToJson())
+	}
+
+	if req.Compare != "" && !models.IsValidCompareMode(req.Compare) {
+		errResp := domainerrors.NewErrorResponseWithOpts(nil, domainerrors.EINVALID, "invalid compare")
+		h.logger.Error("invalid compare", zap.Reflect("error", errResp))
+		return ctx.Status(errResp.Status).JSON(errResp.ToJson())
+	}
+
 	c := context.WithValue(ctx.UserContext(), constants.TenantSlugKey, tenantSlug)
-	result, err := h.meterSvc.QueryMeter(c, models.QueryMeterParams{
-		MeterSlug:      req.MeterSlug,
-		FilterGroupBy:  req.FilterGroupBy,
-		From:           req.From,
-		To:             req.To,
-		GroupBy:        req.GroupBy,
-		WindowSize:     req.WindowSize,
-		WindowTimeZone: req.WindowTimeZone,
-	})
+	result, err := h.meterSvc.QueryMeter(c, req.toParams())
 	if err != nil {
 		h.logger.Error("failed to query meter", zap.Reflect("error", err))
 		errResp := domainerrors.NewErrorResponse(err)
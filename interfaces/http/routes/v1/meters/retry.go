@@ -0,0 +1,46 @@
+package meters
+
+import (
+	"context"
+
+	"github.com/gofiber/fiber/v2"
+	domainerrors "github.com/redcardinal-io/metering/domain/errors"
+	"github.com/redcardinal-io/metering/domain/models"
+	"github.com/redcardinal-io/metering/domain/pkg/constants"
+	"go.uber.org/zap"
+)
+
+// @Summary Retry a failed meter
+// @Description Resume the operation that failed on a meter. A failed meter gets its view created again and is marked active; a delete_failed meter is removed and returned as deleting. The failure is reported in the status_error of the meter.
+// @Tags meters
+// @Accept json
+// @Produce json
+// @Param X-Tenant-Slug header string true "Tenant Slug"
+// @Param idOrSlug path string true "Meter ID or slug"
+// @Success 200 {object} models.HttpResponse[models.Meter] "Meter retried successfully"
+// @Failure 400 {object} domainerrors.ErrorResponse "Invalid request"
+// @Failure 404 {object} domainerrors.ErrorResponse "Meter not found"
+// @Failure 409 {object} domainerrors.ErrorResponse "Meter is not failed or delete_failed"
+// @Failure 500 {object} domainerrors.ErrorResponse "Internal server error"
+// @Router /v1/meters/{idOrSlug}/retry [post]
+func (h *httpHandler) retryByIDorSlug(ctx *fiber.Ctx) error {
+	tenantSlug := ctx.Get(constants.TenantHeader)
+	idOrSlug := ctx.Params("idOrSlug")
+
+	if idOrSlug == "" {
+		errResp := domainerrors.NewErrorResponseWithOpts(nil, domainerrors.EINVALID, "meter ID or slug is required")
+		h.logger.Error("meter ID or slug is required", zap.Reflect("error", errResp))
+		return ctx.Status(errResp.Status).JSON(errResp.ToJson())
+	}
+
+	c := context.WithValue(ctx.UserContext(), constants.TenantSlugKey, tenantSlug)
+
+	meter, err := h.meterSvc.RetryMeter(c, idOrSlug)
+	if err != nil {
+		h.logger.Error("failed to retry meter", zap.String("idOrSlug", idOrSlug), zap.Reflect("error", err))
+		errResp := domainerrors.NewErrorResponse(err)
+		return ctx.Status(errResp.Status).JSON(errResp.ToJson())
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(models.NewHttpResponse(meter, "meter retried successfully", fiber.StatusOK))
+}
@@ -10,15 +10,17 @@ import (
 type httpHandler struct {
 	logger    *logger.Logger
 	meterSvc  *services.MeterService
+	streamHub *services.MeterStreamHub
 	validator *validator.Validate
 }
 
 // NewHTTPHandler creates and returns a new httpHandler for meter-related HTTP endpoints.
-func NewHTTPHandler(logger *logger.Logger, meterSvc *services.MeterService) *httpHandler {
+func NewHTTPHandler(logger *logger.Logger, meterSvc *services.MeterService, streamHub *services.MeterStreamHub) *httpHandler {
 	validator := validator.New()
 	return &httpHandler{
 		logger:    logger,
 		meterSvc:  meterSvc,
+		streamHub: streamHub,
 		validator: validator,
 	}
 }
@@ -30,10 +32,14 @@ func (h *httpHandler) RegisterRoutes(r fiber.Router) {
 	// Meter collection routes
 	meters.Post("/", h.create)
 	meters.Post("/query", h.query)
+	meters.Post("/export", h.export)
+	meters.Post("/preview", h.preview)
 	meters.Get("/", h.list)
 
 	// Single meter routes with idOrSlug parameter
 	meters.Get("/:idOrSlug", h.getByIDorSlug)
 	meters.Put("/:idOrSlug", h.updateByIDorSlug)
 	meters.Delete("/:idOrSlug", h.deleteByIDorSlug)
+	meters.Post("/:idOrSlug/retry", h.retryByIDorSlug)
+	meters.Get("/:idOrSlug/stream", h.stream)
 }
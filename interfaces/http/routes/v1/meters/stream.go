@@ -0,0 +1,165 @@
+package meters
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	domainerrors "github.com/redcardinal-io/metering/domain/errors"
+	"github.com/redcardinal-io/metering/domain/models"
+	"github.com/redcardinal-io/metering/domain/pkg/constants"
+	"go.uber.org/zap"
+)
+
+type streamMeterQuery struct {
+	// Organization and User restrict the stream to the given subjects; values may be comma separated
+	Organization []string `query:"organization"`
+	User         []string `query:"user"`
+	GroupBy      []string `query:"group_by"`
+	WindowSize   string   `query:"window_size" validate:"omitempty,oneof=minute hour day"`
+	// From streams usage since the given time instead of the current window
+	From string `query:"from"`
+	// Filter is a JSON encoded filter expression
+	Filter string `query:"filter"`
+	Limit  int    `query:"limit" validate:"omitempty,min=1,max=10000"`
+}
+
+// toParams converts the query into live query parameters of the meter.
+func (q streamMeterQuery) toParams(meterSlug string) (models.QueryMeterParams, error) {
+	params := models.QueryMeterParams{
+		MeterSlug: meterSlug,
+		GroupBy:   splitValues(q.GroupBy),
+	}
+	filterGroupBy := make(map[string][]string)
+	if organizations := splitValues(q.Organization); len(organizations) > 0 {
+		filterGroupBy["organization"] = organizations
+	}
+	if users := splitValues(q.User); len(users) > 0 {
+		filterGroupBy["user"] = users
+	}
+	if len(filterGroupBy) > 0 {
+		params.FilterGroupBy = filterGroupBy
+	}
+	if q.WindowSize != "" {
+		windowSize := models.WindowSize(q.WindowSize)
+		params.WindowSize = &windowSize
+	}
+	if q.From != "" {
+		from, err := time.Parse(time.RFC3339, q.From)
+		if err != nil {
+			return params, fmt.Errorf("from must be an RFC3339 timestamp: %w", err)
+		}
+		params.From = &from
+	}
+	if q.Filter != "" {
+		params.Filter = &models.FilterExpression{}
+		if err := json.Unmarshal([]byte(q.Filter), params.Filter); err != nil {
+			return params, fmt.Errorf("filter must be a JSON filter expression: %w", err)
+		}
+	}
+	if q.Limit > 0 {
+		params.Limit = &q.Limit
+	}
+	return params, nil
+}
+
+// splitValues flattens repeated and comma separated query values.
+func splitValues(values []string) []string {
+	var result []string
+	for _, value := range values {
+		for _, part := range strings.Split(value, ",") {
+			if part = strings.TrimSpace(part); part != "" {
+				result = append(result, part)
+			}
+		}
+	}
+	return result
+}
+
+// @Summary Stream live meter usage
+// @Description Stream the usage of a meter as Server-Sent Events. Each "usage" event carries the query result for the current window of window_size, or since from, and is sent when the usage changes. Clients with identical parameters share a single query.
+// @Tags meters
+// @Produce text/event-stream
+// @Param X-Tenant-Slug header string true "Tenant Slug"
+// @Param idOrSlug path string true "Meter ID or slug"
+// @Param organization query []string false "Organizations to stream usage for"
+// @Param user query []string false "Users to stream usage for"
+// @Param group_by query []string false "Properties to group usage by"
+// @Param window_size query string false "Window to stream the current usage of" Enums(minute, hour, day)
+// @Param from query string false "Stream usage since this RFC3339 time instead of the current window"
+// @Param filter query string false "JSON encoded filter expression"
+// @Param limit query int false "Maximum number of groups"
+// @Success 200 {object} models.QueryMeterResult "Stream of usage events"
+// @Failure 400 {object} domainerrors.ErrorResponse "Invalid request"
+// @Failure 404 {object} domainerrors.ErrorResponse "Meter not found"
+// @Failure 503 {object} domainerrors.ErrorResponse "Too many subscribers"
+// @Router /v1/meters/{idOrSlug}/stream [get]
+func (h *httpHandler) stream(ctx *fiber.Ctx) error {
+	tenantSlug := ctx.Get(constants.TenantHeader)
+	idOrSlug := ctx.Params("idOrSlug")
+
+	var query streamMeterQuery
+	if err := ctx.QueryParser(&query); err != nil {
+		errResp := domainerrors.NewErrorResponseWithOpts(err, domainerrors.EINVALID, "failed to parse query parameters")
+		h.logger.Error("failed to parse query parameters", zap.Reflect("error", errResp))
+		return ctx.Status(errResp.Status).JSON(errResp.ToJson())
+	}
+
+	if err := h.validator.Struct(query); err != nil {
+		errResp := domainerrors.NewErrorResponseWithOpts(err, domainerrors.EINVALID, "invalid query parameters")
+		h.logger.Error("invalid query parameters", zap.Reflect("error", errResp))
+		return ctx.Status(errResp.Status).JSON(errResp.ToJson())
+	}
+
+	params, err := query.toParams(idOrSlug)
+	if err != nil {
+		errResp := domainerrors.NewErrorResponseWithOpts(err, domainerrors.EINVALID, "invalid query parameters")
+		h.logger.Error("invalid query parameters", zap.Reflect("error", errResp))
+		return ctx.Status(errResp.Status).JSON(errResp.ToJson())
+	}
+
+	c := context.WithValue(ctx.UserContext(), constants.TenantSlugKey, tenantSlug)
+	sub, err := h.streamHub.Subscribe(c, params)
+	if err != nil {
+		h.logger.Error("failed to subscribe to meter stream", zap.Reflect("error", err))
+		errResp := domainerrors.NewErrorResponse(err)
+		return ctx.Status(errResp.Status).JSON(errResp.ToJson())
+	}
+
+	ctx.Set(fiber.HeaderContentType, "text/event-stream")
+	ctx.Set(fiber.HeaderCacheControl, "no-cache")
+	ctx.Set(fiber.HeaderConnection, "keep-alive")
+	ctx.Set("X-Accel-Buffering", "no")
+	heartbeat := h.streamHub.Heartbeat()
+	ctx.Status(fiber.StatusOK).Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		defer sub.Close()
+		ticker := time.NewTicker(heartbeat)
+		defer ticker.Stop()
+
+		// Writes fail once the client disconnects, which ends the stream
+		for {
+			select {
+			case result, ok := <-sub.Updates():
+				if !ok {
+					return
+				}
+				data, err := json.Marshal(result)
+				if err != nil {
+					h.logger.Error("failed to encode meter stream event", zap.String("meter", idOrSlug), zap.Error(err))
+					return
+				}
+				fmt.Fprintf(w, "event: usage\ndata: %s\n\n", data)
+			case <-ticker.C:
+				fmt.Fprint(w, ": keep-alive\n\n")
+			}
+			if err := w.Flush(); err != nil {
+				return
+			}
+		}
+	})
+	return nil
+}
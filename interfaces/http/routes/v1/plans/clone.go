@@ -0,0 +1,72 @@
+package plans
+
+import (
+	"context"
+
+	"github.com/gofiber/fiber/v2"
+	domainerrors "github.com/redcardinal-io/metering/domain/errors"
+	"github.com/redcardinal-io/metering/domain/models"
+	"github.com/redcardinal-io/metering/domain/pkg/constants"
+	"go.uber.org/zap"
+)
+
+// clonePlanRequest defines the request body for cloning a plan
+type clonePlanRequest struct {
+	Slug        string           `json:"slug" validate:"required"`
+	Name        string           `json:"name,omitempty"`
+	Type        string           `json:"type,omitempty" validate:"omitempty,oneof=standard custom"`
+	Description string           `json:"description,omitempty" validate:"omitempty,min=10,max=255"`
+	QuotaLimits map[string]int64 `json:"quota_limits,omitempty" validate:"omitempty,dive,keys,required,endkeys,min=0"`
+	CreatedBy   string           `json:"created_by" validate:"required"`
+}
+
+// @Summary Clone a plan
+// @Description Copy a plan with its features, their configs and quotas into a new slug in one transaction. Name, type and description default to those of the source plan, and quota_limits overrides quota limits by feature slug. The clone is not published.
+// @Tags plans
+// @Accept json
+// @Produce json
+// @Param X-Tenant-Slug header string true "Tenant Slug"
+// @Param idOrSlug path string true "Plan ID or slug"
+// @Param plan body clonePlanRequest true "Clone information"
+// @Success 201 {object} models.HttpResponse[models.Plan] "Plan cloned successfully"
+// @Failure 400 {object} domainerrors.ErrorResponse "Invalid request"
+// @Failure 404 {object} domainerrors.ErrorResponse "Plan not found"
+// @Failure 409 {object} domainerrors.ErrorResponse "Plan already exists"
+// @Failure 500 {object} domainerrors.ErrorResponse "Internal server error"
+// @Router /v1/plans/{idOrSlug}/clone [post]
+func (h *httpHandler) clone(ctx *fiber.Ctx) error {
+	tenantSlug := ctx.Get(constants.TenantHeader)
+	idOrSlug := ctx.Params("idOrSlug")
+
+	var req clonePlanRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		errResp := domainerrors.NewErrorResponseWithOpts(err, domainerrors.EINVALID, "failed to parse request body")
+		h.logger.Error("failed to parse request body", zap.Reflect("error", errResp))
+		return ctx.Status(errResp.Status).JSON(errResp.ToJson())
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		errResp := domainerrors.NewErrorResponseWithOpts(err, domainerrors.EINVALID, "invalid request body")
+		h.logger.Error("invalid request body", zap.Reflect("error", errResp))
+		return ctx.Status(errResp.Status).JSON(errResp.ToJson())
+	}
+
+	c := context.WithValue(ctx.UserContext(), constants.TenantSlugKey, tenantSlug)
+
+	plan, err := h.planSvc.ClonePlan(c, idOrSlug, models.ClonePlanInput{
+		Name:        req.Name,
+		Slug:        req.Slug,
+		Type:        models.PlanTypeEnum(req.Type),
+		Description: req.Description,
+		QuotaLimits: req.QuotaLimits,
+		CreatedBy:   req.CreatedBy,
+	})
+	if err != nil {
+		h.logger.Error("failed to clone plan", zap.String("idOrSlug", idOrSlug), zap.Reflect("error", err))
+		errResp := domainerrors.NewErrorResponse(err)
+		return ctx.Status(errResp.Status).JSON(errResp.ToJson())
+	}
+
+	return ctx.
+		Status(fiber.StatusCreated).JSON(models.NewHttpResponse(plan, "plan cloned successfully", fiber.StatusCreated))
+}
@@ -15,12 +15,13 @@ type createPlanRequest struct {
 	Name        string `json:"name" validate:"required"`
 	Slug        string `json:"slug" validate:"required"`
 	Type        string `json:"type" validate:"required,oneof=standard custom"`
+	Kind        string `json:"kind,omitempty" validate:"omitempty,oneof=base addon"`
 	Description string `json:"description,omitempty" validate:"omitempty,min=10,max=255"`
 	CreatedBy   string `json:"created_by" validate:"required"`
 }
 
 // @Summary Create a new plan
-// @Description Create a new plan for the tenant
+// @Description Create a new plan for the tenant. Subjects hold one base plan at a time and any number of add-on plans stacked on top of it; plans are base plans unless kind is addon.
 // @Tags plans
 // @Accept json
 // @Produce json
@@ -53,6 +54,7 @@ func (h *httpHandler) create(ctx *fiber.Ctx) error {
 		Name:        req.Name,
 		Slug:        req.Slug,
 		Type:        models.PlanTypeEnum(req.Type),
+		Kind:        models.PlanKindEnum(req.Kind),
 		Description: req.Description,
 		CreatedBy:   req.CreatedBy,
 	})
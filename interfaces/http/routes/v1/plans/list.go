@@ -15,6 +15,7 @@ type queryPlanParams struct {
 	Page     int    `query:"page" validate:"omitempty,min=1"`
 	Limit    int    `query:"limit" validate:"omitempty,min=1,max=100"`
 	Type     string `query:"type" validate:"omitempty,oneof=standard custom"`
+	Kind     string `query:"kind" validate:"omitempty,oneof=base addon"`
 	Archived bool   `query:"archived" validate:"omitempty"`
 }
 
@@ -26,6 +27,7 @@ type queryPlanParams struct {
 // @Param X-Tenant-Slug header string true "Tenant Slug"
 // @Param page query int false "Page number" default(1)
 // @Param limit query int false "Items per page" default(10)
+// @Param kind query string false "Plan kind (base/addon)"
 // @Success 200 {object} models.HttpResponse[pagination.PaginationView[models.Plan]] "Plans retrieved successfully"
 // @Failure 400 {object} domainerrors.ErrorResponse "Invalid request"
 // @Failure 500 {object} domainerrors.ErrorResponse "Internal server error"
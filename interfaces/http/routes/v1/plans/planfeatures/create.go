@@ -18,7 +18,7 @@ type createPlanFeatureRequest struct {
 }
 
 // @Summary Create plan feature
-// @Description Adds a new feature to a plan
+// @Description Adds a new feature to the draft of a plan. Assignments see it once a version is published and they are migrated to it
 // @Tags Plan Features
 // @Accept json
 // @Produce json
@@ -12,7 +12,7 @@ import (
 )
 
 // @Summary Delete plan feature
-// @Description Removes a feature from a plan
+// @Description Removes a feature from the draft of a plan. Published versions are unaffected
 // @Tags Plan Features
 // @Accept json
 // @Produce json
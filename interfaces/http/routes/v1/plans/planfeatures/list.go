@@ -16,7 +16,7 @@ type listPlanFeaturesQuery struct {
 }
 
 // @Summary List plan features
-// @Description Retrieves all features associated with a plan
+// @Description Retrieves all features in the draft of a plan
 // @Tags Plan Features
 // @Accept json
 // @Produce json
@@ -11,14 +11,18 @@ import (
 )
 
 type createQuotaRequest struct {
-	LimitValue          int64  `json:"limit_value" validate:"required"`
-	ResetPeriod         string `json:"reset_period" validate:"required,oneof=day week month year custom rolling never"`
-	CustomPeriodMinutes *int64 `json:"custom_period_minutes,omitempty" validate:"required_if=ResetPeriod custom,omitempty,gt=0"`
-	ActionAtLimit       string `json:"action_at_limit" validate:"required,oneof=none block throttle"`
+	LimitValue           int64  `json:"limit_value" validate:"required"`
+	ResetPeriod          string `json:"reset_period" validate:"required,oneof=day week month year custom rolling never"`
+	CustomPeriodMinutes  *int64 `json:"custom_period_minutes,omitempty" validate:"required_if=ResetPeriod custom,omitempty,gt=0"`
+	ActionAtLimit        string `json:"action_at_limit" validate:"required,oneof=none block throttle"`
+	SoftLimitValue       *int64 `json:"soft_limit_value,omitempty" validate:"omitempty,gt=0"`
+	OverageAllowance     *int64 `json:"overage_allowance,omitempty" validate:"required_with=OverageAllowanceType,omitempty,gt=0"`
+	OverageAllowanceType string `json:"overage_allowance_type,omitempty" validate:"required_with=OverageAllowance,omitempty,oneof=absolute percent"`
+	GracePeriodMinutes   *int64 `json:"grace_period_minutes,omitempty" validate:"omitempty,gt=0"`
 }
 
 // @Summary Create plan feature quota
-// @Description Adds a quota configuration to a plan feature
+// @Description Adds a quota configuration to a plan feature in the draft of a plan. A soft limit warns subjects before the limit, an overage allowance, absolute or a percentage of the limit, lets usage go past the limit and a grace period delays the action at limit after the allowance is first used up
 // @Tags Plan Feature Quotas
 // @Accept json
 // @Produce json
@@ -75,10 +79,14 @@ func (h *httpHandler) create(ctx *fiber.Ctx) error {
 
 	c := context.WithValue(ctx.UserContext(), constants.TenantSlugKey, tenantSlug)
 	quota, err := h.planSvc.CreatePlanFeatureQuota(c, models.CreatePlanFeatureQuotaInput{
-		LimitValue:          req.LimitValue,
-		ResetPeriod:         models.MeteredResetPeriod(req.ResetPeriod),
-		CustomPeriodMinutes: req.CustomPeriodMinutes,
-		ActionAtLimit:       models.MeteredActionAtLimit(req.ActionAtLimit),
+		LimitValue:           req.LimitValue,
+		ResetPeriod:          models.MeteredResetPeriod(req.ResetPeriod),
+		CustomPeriodMinutes:  req.CustomPeriodMinutes,
+		ActionAtLimit:        models.MeteredActionAtLimit(req.ActionAtLimit),
+		SoftLimitValue:       req.SoftLimitValue,
+		OverageAllowance:     req.OverageAllowance,
+		OverageAllowanceType: models.QuotaOverageType(req.OverageAllowanceType),
+		GracePeriodMinutes:   req.GracePeriodMinutes,
 	}, planID, featureID)
 	if err != nil {
 		h.logger.Error("failed to create plan feature quota",
@@ -10,7 +10,7 @@ import (
 )
 
 // @Summary Delete plan feature quota
-// @Description Removes the quota configuration from a plan feature
+// @Description Removes the quota configuration from a plan feature in the draft of a plan
 // @Tags Plan Feature Quotas
 // @Accept json
 // @Produce json
@@ -11,7 +11,7 @@ import (
 )
 
 // @Summary Get plan feature quota details
-// @Description Retrieves quota configuration for a specific plan feature
+// @Description Retrieves quota configuration for a specific plan feature in the draft of a plan
 // @Tags Plan Feature Quotas
 // @Accept json
 // @Produce json
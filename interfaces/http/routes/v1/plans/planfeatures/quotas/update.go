@@ -11,14 +11,22 @@ import (
 )
 
 type updatePlanFeatureQuotaRequest struct {
-	LimitValue          int64  `json:"limit_value,omitempty" validate:"omitempty,gt=0"`
-	ResetPeriod         string `json:"reset_period,omitempty" validate:"omitempty,oneof=day week month year custom rolling never"`
-	CustomPeriodMinutes int64  `json:"custom_period_minutes,omitempty" validate:"omitempty,gt=0"`
-	ActionAtLimit       string `json:"action_at_limit,omitempty" validate:"omitempty,oneof=none block throttle"`
+	LimitValue           int64  `json:"limit_value,omitempty" validate:"omitempty,gt=0"`
+	ResetPeriod          string `json:"reset_period,omitempty" validate:"omitempty,oneof=day week month year custom rolling never"`
+	CustomPeriodMinutes  int64  `json:"custom_period_minutes,omitempty" validate:"omitempty,gt=0"`
+	ActionAtLimit        string `json:"action_at_limit,omitempty" validate:"omitempty,oneof=none block throttle"`
+	SoftLimitValue       *int64 `json:"soft_limit_value,omitempty" validate:"omitempty,gt=0"`
+	OverageAllowance     *int64 `json:"overage_allowance,omitempty" validate:"omitempty,gt=0"`
+	OverageAllowanceType string `json:"overage_allowance_type,omitempty" validate:"omitempty,oneof=absolute percent"`
+	GracePeriodMinutes   *int64 `json:"grace_period_minutes,omitempty" validate:"omitempty,gt=0"`
+	// The clear flags remove the soft limit, the overage allowance with its type, or the grace period
+	ClearSoftLimitValue     bool `json:"clear_soft_limit_value,omitempty"`
+	ClearOverageAllowance   bool `json:"clear_overage_allowance,omitempty"`
+	ClearGracePeriodMinutes bool `json:"clear_grace_period_minutes,omitempty"`
 }
 
 // @Summary Update plan feature quota
-// @Description Updates the quota configuration for a plan feature
+// @Description Updates the quota configuration for a plan feature in the draft of a plan. Published versions are unaffected. The soft limit must stay below the limit and an overage allowance needs a type. The soft limit, the overage allowance and the grace period are removed with their clear flags
 // @Tags Plan Feature Quotas
 // @Accept json
 // @Produce json
@@ -63,7 +71,9 @@ func (h *httpHandler) update(ctx *fiber.Ctx) error {
 		return ctx.Status(errResp.Status).JSON(errResp.ToJson())
 	}
 
-	if req.LimitValue == 0 && req.ResetPeriod == "" && req.CustomPeriodMinutes == 0 && req.ActionAtLimit == "" {
+	if req.LimitValue == 0 && req.ResetPeriod == "" && req.CustomPeriodMinutes == 0 && req.ActionAtLimit == "" &&
+		req.SoftLimitValue == nil && req.OverageAllowance == nil && req.OverageAllowanceType == "" && req.GracePeriodMinutes == nil &&
+		!req.ClearSoftLimitValue && !req.ClearOverageAllowance && !req.ClearGracePeriodMinutes {
 		errResp := domainerrors.NewErrorResponseWithOpts(
 			nil,
 			domainerrors.EINVALID,
@@ -73,6 +83,18 @@ func (h *httpHandler) update(ctx *fiber.Ctx) error {
 		return ctx.Status(errResp.Status).JSON(errResp.ToJson())
 	}
 
+	if req.ClearSoftLimitValue && req.SoftLimitValue != nil ||
+		req.ClearOverageAllowance && (req.OverageAllowance != nil || req.OverageAllowanceType != "") ||
+		req.ClearGracePeriodMinutes && req.GracePeriodMinutes != nil {
+		errResp := domainerrors.NewErrorResponseWithOpts(
+			nil,
+			domainerrors.EINVALID,
+			"a field cannot be set together with its clear flag",
+		)
+		h.logger.Error("a field cannot be set together with its clear flag", zap.Reflect("error", errResp))
+		return ctx.Status(errResp.Status).JSON(errResp.ToJson())
+	}
+
 	if req.ResetPeriod == "custom" && req.CustomPeriodMinutes == 0 {
 		errResp := domainerrors.NewErrorResponseWithOpts(
 			nil,
@@ -92,10 +114,17 @@ func (h *httpHandler) update(ctx *fiber.Ctx) error {
 	resetPeriod := models.MeteredResetPeriod(req.ResetPeriod)
 	actionAtLimit := models.MeteredActionAtLimit(req.ActionAtLimit)
 	quota, err := h.planSvc.UpdatePlanFeatureQuota(c, models.UpdatePlanFeatureQuotaInput{
-		LimitValue:          req.LimitValue,
-		ResetPeriod:         resetPeriod,
-		CustomPeriodMinutes: req.CustomPeriodMinutes,
-		ActionAtLimit:       actionAtLimit,
+		LimitValue:              req.LimitValue,
+		ResetPeriod:             resetPeriod,
+		CustomPeriodMinutes:     req.CustomPeriodMinutes,
+		ActionAtLimit:           actionAtLimit,
+		SoftLimitValue:          req.SoftLimitValue,
+		OverageAllowance:        req.OverageAllowance,
+		OverageAllowanceType:    models.QuotaOverageType(req.OverageAllowanceType),
+		GracePeriodMinutes:      req.GracePeriodMinutes,
+		ClearSoftLimitValue:     req.ClearSoftLimitValue,
+		ClearOverageAllowance:   req.ClearOverageAllowance,
+		ClearGracePeriodMinutes: req.ClearGracePeriodMinutes,
 	}, planID, featureID)
 	if err != nil {
 		h.logger.Error("failed to update plan feature quota",
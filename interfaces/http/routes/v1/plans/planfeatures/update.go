@@ -16,7 +16,7 @@ type updatePlanFeatureRequest struct {
 	UpdatedBy string                 `json:"updated_by" validate:"required"`
 }
 
-// @Description Updates configuration of a feature associated with a plan
+// @Description Updates configuration of a feature in the draft of a plan. Published versions are unaffected
 // @Tags Plan Features
 // @Accept json
 // @Produce json
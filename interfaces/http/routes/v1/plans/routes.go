@@ -38,6 +38,13 @@ func (h *httpHandler) RegisterRoutes(r fiber.Router) {
 	plan.Put("/", h.update)
 	plan.Delete("/", h.delete)
 	plan.Put("/archive", h.archive)
+	plan.Post("/clone", h.clone)
+
+	// Plan version routes
+	plan.Post("/versions", h.publishVersion)
+	plan.Get("/versions", h.listVersions)
+	plan.Get("/versions/:version", h.versionDetails)
+	plan.Post("/versions/:version/migrate", h.migrateVersion)
 
 	// Plan Features routes
 	planFeatures := plans.Group("/:planID/features")
@@ -0,0 +1,195 @@
+package plans
+
+import (
+	"context"
+
+	"github.com/gofiber/fiber/v2"
+	domainerrors "github.com/redcardinal-io/metering/domain/errors"
+	"github.com/redcardinal-io/metering/domain/models"
+	"github.com/redcardinal-io/metering/domain/pkg/constants"
+	"go.uber.org/zap"
+)
+
+// publishPlanVersionRequest defines the request body for publishing the draft of a plan
+type publishPlanVersionRequest struct {
+	Description string `json:"description,omitempty" validate:"omitempty,max=255"`
+	CreatedBy   string `json:"created_by" validate:"required"`
+}
+
+// migratePlanVersionRequest defines the request body for migrating assignments to a plan version
+type migratePlanVersionRequest struct {
+	FromVersion     int      `json:"from_version,omitempty" validate:"omitempty,min=1"`
+	OrganizationIDs []string `json:"organization_ids,omitempty" validate:"omitempty,dive,required"`
+	UserIDs         []string `json:"user_ids,omitempty" validate:"omitempty,dive,required"`
+	OnRenewal       bool     `json:"on_renewal"`
+	UpdatedBy       string   `json:"updated_by" validate:"required"`
+}
+
+// @Summary Publish a plan version
+// @Description Publish the current features and quotas of a plan as its next immutable version
+// @Tags plans
+// @Accept json
+// @Produce json
+// @Param X-Tenant-Slug header string true "Tenant Slug"
+// @Param idOrSlug path string true "Plan ID or slug"
+// @Param version body publishPlanVersionRequest true "Version information"
+// @Success 201 {object} models.HttpResponse[models.PlanVersion] "Plan version published successfully"
+// @Failure 400 {object} domainerrors.ErrorResponse "Invalid request"
+// @Failure 404 {object} domainerrors.ErrorResponse "Plan not found"
+// @Failure 500 {object} domainerrors.ErrorResponse "Internal server error"
+// @Router /v1/plans/{idOrSlug}/versions [post]
+func (h *httpHandler) publishVersion(ctx *fiber.Ctx) error {
+	tenantSlug := ctx.Get(constants.TenantHeader)
+	idOrSlug := ctx.Params("idOrSlug")
+
+	var req publishPlanVersionRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		errResp := domainerrors.NewErrorResponseWithOpts(err, domainerrors.EINVALID, "failed to parse request body")
+		h.logger.Error("failed to parse request body", zap.Reflect("error", errResp))
+		return ctx.Status(errResp.Status).JSON(errResp.ToJson())
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		errResp := domainerrors.NewErrorResponseWithOpts(err, domainerrors.EINVALID, "invalid request body")
+		h.logger.Error("invalid request body", zap.Reflect("error", errResp))
+		return ctx.Status(errResp.Status).JSON(errResp.ToJson())
+	}
+
+	c := context.WithValue(ctx.UserContext(), constants.TenantSlugKey, tenantSlug)
+
+	version, err := h.planSvc.PublishPlanVersion(c, idOrSlug, models.PublishPlanVersionInput{
+		Description: req.Description,
+		CreatedBy:   req.CreatedBy,
+	})
+	if err != nil {
+		h.logger.Error("failed to publish plan version", zap.String("idOrSlug", idOrSlug), zap.Reflect("error", err))
+		errResp := domainerrors.NewErrorResponse(err)
+		return ctx.Status(errResp.Status).JSON(errResp.ToJson())
+	}
+
+	return ctx.
+		Status(fiber.StatusCreated).JSON(models.NewHttpResponse(version, "plan version published successfully", fiber.StatusCreated))
+}
+
+// @Summary List plan versions
+// @Description List the published versions of a plan, newest first
+// @Tags plans
+// @Accept json
+// @Produce json
+// @Param X-Tenant-Slug header string true "Tenant Slug"
+// @Param idOrSlug path string true "Plan ID or slug"
+// @Success 200 {object} models.HttpResponse[[]models.PlanVersion] "Plan versions retrieved successfully"
+// @Failure 404 {object} domainerrors.ErrorResponse "Plan not found"
+// @Failure 500 {object} domainerrors.ErrorResponse "Internal server error"
+// @Router /v1/plans/{idOrSlug}/versions [get]
+func (h *httpHandler) listVersions(ctx *fiber.Ctx) error {
+	tenantSlug := ctx.Get(constants.TenantHeader)
+	idOrSlug := ctx.Params("idOrSlug")
+
+	c := context.WithValue(ctx.UserContext(), constants.TenantSlugKey, tenantSlug)
+
+	versions, err := h.planSvc.ListPlanVersions(c, idOrSlug)
+	if err != nil {
+		h.logger.Error("failed to list plan versions", zap.String("idOrSlug", idOrSlug), zap.Reflect("error", err))
+		errResp := domainerrors.NewErrorResponse(err)
+		return ctx.Status(errResp.Status).JSON(errResp.ToJson())
+	}
+
+	return ctx.
+		Status(fiber.StatusOK).JSON(models.NewHttpResponse(versions, "plan versions retrieved successfully", fiber.StatusOK))
+}
+
+// @Summary Get plan version details
+// @Description Get a published version of a plan together with its features
+// @Tags plans
+// @Accept json
+// @Produce json
+// @Param X-Tenant-Slug header string true "Tenant Slug"
+// @Param idOrSlug path string true "Plan ID or slug"
+// @Param version path int true "Version number"
+// @Success 200 {object} models.HttpResponse[models.PlanVersion] "Plan version retrieved successfully"
+// @Failure 400 {object} domainerrors.ErrorResponse "Invalid request"
+// @Failure 404 {object} domainerrors.ErrorResponse "Plan version not found"
+// @Failure 500 {object} domainerrors.ErrorResponse "Internal server error"
+// @Router /v1/plans/{idOrSlug}/versions/{version} [get]
+func (h *httpHandler) versionDetails(ctx *fiber.Ctx) error {
+	tenantSlug := ctx.Get(constants.TenantHeader)
+	idOrSlug := ctx.Params("idOrSlug")
+
+	number, err := ctx.ParamsInt("version")
+	if err != nil || number < 1 {
+		errResp := domainerrors.NewErrorResponseWithOpts(err, domainerrors.EINVALID, "version must be a positive number")
+		h.logger.Error("invalid plan version", zap.Reflect("error", errResp))
+		return ctx.Status(errResp.Status).JSON(errResp.ToJson())
+	}
+
+	c := context.WithValue(ctx.UserContext(), constants.TenantSlugKey, tenantSlug)
+
+	version, err := h.planSvc.GetPlanVersion(c, idOrSlug, number)
+	if err != nil {
+		h.logger.Error("failed to get plan version", zap.String("idOrSlug", idOrSlug), zap.Int("version", number), zap.Reflect("error", err))
+		errResp := domainerrors.NewErrorResponse(err)
+		return ctx.Status(errResp.Status).JSON(errResp.ToJson())
+	}
+
+	return ctx.
+		Status(fiber.StatusOK).JSON(models.NewHttpResponse(version, "plan version retrieved successfully", fiber.StatusOK))
+}
+
+// @Summary Migrate assignments to a plan version
+// @Description Move the current and future assignments of a plan to one of its versions, either right away or when each assignment is renewed. Without organization_ids or user_ids every assignment of the plan is migrated. The migration is refused when a quota of the version resets over another period than the quota of the same feature in a plan held with a migrated assignment.
+// @Tags plans
+// @Accept json
+// @Produce json
+// @Param X-Tenant-Slug header string true "Tenant Slug"
+// @Param idOrSlug path string true "Plan ID or slug"
+// @Param version path int true "Target version number"
+// @Param migration body migratePlanVersionRequest true "Assignments to migrate"
+// @Success 200 {object} models.HttpResponse[models.PlanVersionMigration] "Assignments migrated successfully"
+// @Failure 400 {object} domainerrors.ErrorResponse "Invalid request"
+// @Failure 404 {object} domainerrors.ErrorResponse "Plan version not found"
+// @Failure 500 {object} domainerrors.ErrorResponse "Internal server error"
+// @Router /v1/plans/{idOrSlug}/versions/{version}/migrate [post]
+func (h *httpHandler) migrateVersion(ctx *fiber.Ctx) error {
+	tenantSlug := ctx.Get(constants.TenantHeader)
+	idOrSlug := ctx.Params("idOrSlug")
+
+	number, err := ctx.ParamsInt("version")
+	if err != nil || number < 1 {
+		errResp := domainerrors.NewErrorResponseWithOpts(err, domainerrors.EINVALID, "version must be a positive number")
+		h.logger.Error("invalid plan version", zap.Reflect("error", errResp))
+		return ctx.Status(errResp.Status).JSON(errResp.ToJson())
+	}
+
+	var req migratePlanVersionRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		errResp := domainerrors.NewErrorResponseWithOpts(err, domainerrors.EINVALID, "failed to parse request body")
+		h.logger.Error("failed to parse request body", zap.Reflect("error", errResp))
+		return ctx.Status(errResp.Status).JSON(errResp.ToJson())
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		errResp := domainerrors.NewErrorResponseWithOpts(err, domainerrors.EINVALID, "invalid request body")
+		h.logger.Error("invalid request body", zap.Reflect("error", errResp))
+		return ctx.Status(errResp.Status).JSON(errResp.ToJson())
+	}
+
+	c := context.WithValue(ctx.UserContext(), constants.TenantSlugKey, tenantSlug)
+
+	migration, err := h.planSvc.MigratePlanVersion(c, idOrSlug, models.MigratePlanVersionInput{
+		FromVersion:     req.FromVersion,
+		ToVersion:       number,
+		OrganizationIDs: req.OrganizationIDs,
+		UserIDs:         req.UserIDs,
+		OnRenewal:       req.OnRenewal,
+		UpdatedBy:       req.UpdatedBy,
+	})
+	if err != nil {
+		h.logger.Error("failed to migrate plan assignments", zap.String("idOrSlug", idOrSlug), zap.Int("version", number), zap.Reflect("error", err))
+		errResp := domainerrors.NewErrorResponse(err)
+		return ctx.Status(errResp.Status).JSON(errResp.ToJson())
+	}
+
+	return ctx.
+		Status(fiber.StatusOK).JSON(models.NewHttpResponse(migration, "plan assignments migrated successfully", fiber.StatusOK))
+}
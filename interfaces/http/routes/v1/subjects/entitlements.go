@@ -0,0 +1,48 @@
+package subjects
+
+import (
+	"context"
+
+	"github.com/gofiber/fiber/v2"
+	domainerrors "github.com/redcardinal-io/metering/domain/errors"
+	"github.com/redcardinal-io/metering/domain/models"
+	"github.com/redcardinal-io/metering/domain/pkg/constants"
+	"go.uber.org/zap"
+)
+
+// @Summary Get subject entitlements
+// @Description List the features an organization or user is entitled to, merged across its active base plan and the add-ons stacked on top of it. Quota limits are summed or maxed by the merge rule of each feature, a plan granting a feature without a quota makes it unlimited, and each feature lists the plans granting it. Active entitlement overrides of the subject are applied on top and listed with the actor who last changed them.
+// @Tags subjects
+// @Accept json
+// @Produce json
+// @Param X-Tenant-Slug header string true "Tenant Slug"
+// @Param subject path string true "Organization or user ID"
+// @Param type query string false "Subject type (organization/user)" default(organization)
+// @Success 200 {object} models.HttpResponse[models.SubjectEntitlements] "Subject entitlements retrieved successfully"
+// @Failure 400 {object} domainerrors.ErrorResponse "Invalid request"
+// @Failure 404 {object} domainerrors.ErrorResponse "Subject has no active plan"
+// @Failure 500 {object} domainerrors.ErrorResponse "Internal server error"
+// @Router /v1/subjects/{subject}/entitlements [get]
+func (h *httpHandler) entitlements(ctx *fiber.Ctx) error {
+	tenantSlug := ctx.Get(constants.TenantHeader)
+	subject := ctx.Params("subject")
+	subjectType := models.SubjectType(ctx.Query("type", string(models.SubjectTypeOrganization)))
+
+	if !models.IsValidSubjectType(subjectType) {
+		errResp := domainerrors.NewErrorResponseWithOpts(nil, domainerrors.EINVALID, "invalid subject type")
+		h.logger.Error("invalid subject type", zap.String("type", string(subjectType)))
+		return ctx.Status(errResp.Status).JSON(errResp.ToJson())
+	}
+
+	c := context.WithValue(ctx.UserContext(), constants.TenantSlugKey, tenantSlug)
+
+	entitlements, err := h.usageSvc.GetSubjectEntitlements(c, subjectType, subject)
+	if err != nil {
+		h.logger.Error("failed to get subject entitlements", zap.String("subject", subject), zap.Reflect("error", err))
+		errResp := domainerrors.NewErrorResponse(err)
+		return ctx.Status(errResp.Status).JSON(errResp.ToJson())
+	}
+
+	return ctx.
+		Status(fiber.StatusOK).JSON(models.NewHttpResponse(entitlements, "subject entitlements retrieved successfully", fiber.StatusOK))
+}
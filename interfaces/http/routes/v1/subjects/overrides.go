@@ -0,0 +1,296 @@
+package subjects
+
+import (
+	"context"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	domainerrors "github.com/redcardinal-io/metering/domain/errors"
+	"github.com/redcardinal-io/metering/domain/models"
+	"github.com/redcardinal-io/metering/domain/pkg/constants"
+	"github.com/redcardinal-io/metering/domain/pkg/pagination"
+	"go.uber.org/zap"
+)
+
+type createOverrideRequest struct {
+	Feature             string     `json:"feature" validate:"required"`
+	PlanAssignmentID    string     `json:"plan_assignment_id,omitempty" validate:"omitempty,uuid"`
+	Effect              string     `json:"effect" validate:"required,oneof=grant revoke"`
+	LimitValue          *int64     `json:"limit_value,omitempty" validate:"omitempty,gte=0"`
+	ResetPeriod         string     `json:"reset_period,omitempty" validate:"omitempty,oneof=day week month year custom rolling never"`
+	CustomPeriodMinutes *int64     `json:"custom_period_minutes,omitempty" validate:"omitempty,gt=0"`
+	ActionAtLimit       string     `json:"action_at_limit,omitempty" validate:"omitempty,oneof=none block throttle"`
+	Reason              string     `json:"reason,omitempty"`
+	ExpiresAt           *time.Time `json:"expires_at,omitempty"`
+	CreatedBy           string     `json:"created_by" validate:"required"`
+}
+
+type updateOverrideRequest struct {
+	LimitValue          *int64     `json:"limit_value,omitempty" validate:"omitempty,gte=0"`
+	ResetPeriod         string     `json:"reset_period,omitempty" validate:"omitempty,oneof=day week month year custom rolling never"`
+	CustomPeriodMinutes *int64     `json:"custom_period_minutes,omitempty" validate:"omitempty,gt=0"`
+	ActionAtLimit       string     `json:"action_at_limit,omitempty" validate:"omitempty,oneof=none block throttle"`
+	Reason              *string    `json:"reason,omitempty"`
+	ExpiresAt           *time.Time `json:"expires_at,omitempty"`
+	ClearExpiresAt      bool       `json:"clear_expires_at,omitempty"`
+	UpdatedBy           string     `json:"updated_by" validate:"required"`
+}
+
+// @Summary Create an entitlement override
+// @Description Grant or revoke a feature for a single organization or user on top of its plans, optionally attached to one of its assignments and expiring at a given time. A grant replaces the limit, reset period and action at limit of the merged quota when set, dropping a soft limit that is not below its limit; a grant of a feature the plans do not offer is unlimited unless it sets a limit.
+// @Tags subjects
+// @Accept json
+// @Produce json
+// @Param X-Tenant-Slug header string true "Tenant Slug"
+// @Param subject path string true "Organization or user ID"
+// @Param type query string false "Subject type (organization/user)" default(organization)
+// @Param override body createOverrideRequest true "Entitlement override data"
+// @Success 201 {object} models.HttpResponse[models.EntitlementOverride] "Entitlement override created successfully"
+// @Failure 400 {object} domainerrors.ErrorResponse "Invalid request"
+// @Failure 404 {object} domainerrors.ErrorResponse "Feature or assignment not found"
+// @Failure 500 {object} domainerrors.ErrorResponse "Internal server error"
+// @Router /v1/subjects/{subject}/overrides [post]
+func (h *httpHandler) createOverride(ctx *fiber.Ctx) error {
+	tenantSlug := ctx.Get(constants.TenantHeader)
+	subject := ctx.Params("subject")
+	subjectType := models.SubjectType(ctx.Query("type", string(models.SubjectTypeOrganization)))
+
+	if !models.IsValidSubjectType(subjectType) {
+		errResp := domainerrors.NewErrorResponseWithOpts(nil, domainerrors.EINVALID, "invalid subject type")
+		h.logger.Error("invalid subject type", zap.String("type", string(subjectType)))
+		return ctx.Status(errResp.Status).JSON(errResp.ToJson())
+	}
+
+	var req createOverrideRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		errResp := domainerrors.NewErrorResponseWithOpts(err, domainerrors.EINVALID, "failed to parse request body")
+		h.logger.Error("failed to parse request body", zap.Reflect("error", errResp))
+		return ctx.Status(errResp.Status).JSON(errResp.ToJson())
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		errResp := domainerrors.NewErrorResponseWithOpts(err, domainerrors.EINVALID, "invalid request body")
+		h.logger.Error("invalid request body", zap.Reflect("error", errResp))
+		return ctx.Status(errResp.Status).JSON(errResp.ToJson())
+	}
+
+	arg := models.CreateEntitlementOverrideInput{
+		SubjectType:         subjectType,
+		SubjectID:           subject,
+		Feature:             req.Feature,
+		Effect:              models.EntitlementOverrideEffect(req.Effect),
+		LimitValue:          req.LimitValue,
+		ResetPeriod:         models.MeteredResetPeriod(req.ResetPeriod),
+		CustomPeriodMinutes: req.CustomPeriodMinutes,
+		ActionAtLimit:       models.MeteredActionAtLimit(req.ActionAtLimit),
+		Reason:              req.Reason,
+		ExpiresAt:           req.ExpiresAt,
+		CreatedBy:           req.CreatedBy,
+	}
+	if req.PlanAssignmentID != "" {
+		assignmentID := uuid.MustParse(req.PlanAssignmentID)
+		arg.PlanAssignmentID = &assignmentID
+	}
+
+	c := context.WithValue(ctx.UserContext(), constants.TenantSlugKey, tenantSlug)
+
+	override, err := h.overrideSvc.CreateEntitlementOverride(c, arg)
+	if err != nil {
+		h.logger.Error("failed to create entitlement override", zap.String("subject", subject), zap.Reflect("error", err))
+		errResp := domainerrors.NewErrorResponse(err)
+		return ctx.Status(errResp.Status).JSON(errResp.ToJson())
+	}
+
+	return ctx.
+		Status(fiber.StatusCreated).JSON(models.NewHttpResponse(override, "entitlement override created successfully", fiber.StatusCreated))
+}
+
+// @Summary List entitlement overrides
+// @Description Get the entitlement overrides of an organization or user, newest first. Expired overrides are left out unless include_expired is set.
+// @Tags subjects
+// @Accept json
+// @Produce json
+// @Param X-Tenant-Slug header string true "Tenant Slug"
+// @Param subject path string true "Organization or user ID"
+// @Param type query string false "Subject type (organization/user)" default(organization)
+// @Param include_expired query boolean false "Include expired overrides"
+// @Param page query integer false "Page number"
+// @Param limit query integer false "Items per page"
+// @Success 200 {object} models.HttpResponse[[]models.EntitlementOverride] "Entitlement overrides retrieved successfully"
+// @Failure 400 {object} domainerrors.ErrorResponse "Invalid request"
+// @Failure 500 {object} domainerrors.ErrorResponse "Internal server error"
+// @Router /v1/subjects/{subject}/overrides [get]
+func (h *httpHandler) listOverrides(ctx *fiber.Ctx) error {
+	tenantSlug := ctx.Get(constants.TenantHeader)
+	subject := ctx.Params("subject")
+	subjectType := models.SubjectType(ctx.Query("type", string(models.SubjectTypeOrganization)))
+
+	if !models.IsValidSubjectType(subjectType) {
+		errResp := domainerrors.NewErrorResponseWithOpts(nil, domainerrors.EINVALID, "invalid subject type")
+		h.logger.Error("invalid subject type", zap.String("type", string(subjectType)))
+		return ctx.Status(errResp.Status).JSON(errResp.ToJson())
+	}
+
+	paginationInput := pagination.ExtractPaginationFromContext(ctx)
+
+	c := context.WithValue(ctx.UserContext(), constants.TenantSlugKey, tenantSlug)
+
+	overrides, err := h.overrideSvc.ListEntitlementOverrides(c, models.QueryEntitlementOverrideInput{
+		SubjectType:    subjectType,
+		SubjectID:      subject,
+		IncludeExpired: ctx.QueryBool("include_expired"),
+	}, paginationInput)
+	if err != nil {
+		h.logger.Error("failed to list entitlement overrides", zap.String("subject", subject), zap.Reflect("error", err))
+		errResp := domainerrors.NewErrorResponse(err)
+		return ctx.Status(errResp.Status).JSON(errResp.ToJson())
+	}
+
+	return ctx.
+		Status(fiber.StatusOK).JSON(models.NewHttpResponse(overrides, "entitlement overrides retrieved successfully", fiber.StatusOK))
+}
+
+// @Summary Get an entitlement override
+// @Description Get an entitlement override of an organization or user by ID
+// @Tags subjects
+// @Accept json
+// @Produce json
+// @Param X-Tenant-Slug header string true "Tenant Slug"
+// @Param subject path string true "Organization or user ID"
+// @Param id path string true "Entitlement override ID"
+// @Param type query string false "Subject type (organization/user)" default(organization)
+// @Success 200 {object} models.HttpResponse[models.EntitlementOverride] "Entitlement override retrieved successfully"
+// @Failure 400 {object} domainerrors.ErrorResponse "Invalid request"
+// @Failure 404 {object} domainerrors.ErrorResponse "Entitlement override not found"
+// @Failure 500 {object} domainerrors.ErrorResponse "Internal server error"
+// @Router /v1/subjects/{subject}/overrides/{id} [get]
+func (h *httpHandler) getOverride(ctx *fiber.Ctx) error {
+	tenantSlug := ctx.Get(constants.TenantHeader)
+	subject := ctx.Params("subject")
+	subjectType := models.SubjectType(ctx.Query("type", string(models.SubjectTypeOrganization)))
+
+	id, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		errResp := domainerrors.NewErrorResponseWithOpts(err, domainerrors.EINVALID, "invalid entitlement override ID format")
+		h.logger.Error("invalid entitlement override ID format", zap.Reflect("error", errResp))
+		return ctx.Status(errResp.Status).JSON(errResp.ToJson())
+	}
+
+	c := context.WithValue(ctx.UserContext(), constants.TenantSlugKey, tenantSlug)
+
+	override, err := h.overrideSvc.GetEntitlementOverride(c, subjectType, subject, id)
+	if err != nil {
+		h.logger.Error("failed to get entitlement override", zap.String("id", id.String()), zap.Reflect("error", err))
+		errResp := domainerrors.NewErrorResponse(err)
+		return ctx.Status(errResp.Status).JSON(errResp.ToJson())
+	}
+
+	return ctx.
+		Status(fiber.StatusOK).JSON(models.NewHttpResponse(override, "entitlement override retrieved successfully", fiber.StatusOK))
+}
+
+// @Summary Update an entitlement override
+// @Description Update the limit, reset period, action at limit, reason or expiry of an entitlement override. Revoke overrides only accept a reason and an expiry.
+// @Tags subjects
+// @Accept json
+// @Produce json
+// @Param X-Tenant-Slug header string true "Tenant Slug"
+// @Param subject path string true "Organization or user ID"
+// @Param id path string true "Entitlement override ID"
+// @Param type query string false "Subject type (organization/user)" default(organization)
+// @Param override body updateOverrideRequest true "Entitlement override update data"
+// @Success 200 {object} models.HttpResponse[models.EntitlementOverride] "Entitlement override updated successfully"
+// @Failure 400 {object} domainerrors.ErrorResponse "Invalid request"
+// @Failure 404 {object} domainerrors.ErrorResponse "Entitlement override not found"
+// @Failure 500 {object} domainerrors.ErrorResponse "Internal server error"
+// @Router /v1/subjects/{subject}/overrides/{id} [put]
+func (h *httpHandler) updateOverride(ctx *fiber.Ctx) error {
+	tenantSlug := ctx.Get(constants.TenantHeader)
+	subject := ctx.Params("subject")
+	subjectType := models.SubjectType(ctx.Query("type", string(models.SubjectTypeOrganization)))
+
+	id, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		errResp := domainerrors.NewErrorResponseWithOpts(err, domainerrors.EINVALID, "invalid entitlement override ID format")
+		h.logger.Error("invalid entitlement override ID format", zap.Reflect("error", errResp))
+		return ctx.Status(errResp.Status).JSON(errResp.ToJson())
+	}
+
+	var req updateOverrideRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		errResp := domainerrors.NewErrorResponseWithOpts(err, domainerrors.EINVALID, "failed to parse request body")
+		h.logger.Error("failed to parse request body", zap.Reflect("error", errResp))
+		return ctx.Status(errResp.Status).JSON(errResp.ToJson())
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		errResp := domainerrors.NewErrorResponseWithOpts(err, domainerrors.EINVALID, "invalid request body")
+		h.logger.Error("invalid request body", zap.Reflect("error", errResp))
+		return ctx.Status(errResp.Status).JSON(errResp.ToJson())
+	}
+	if req.ClearExpiresAt && req.ExpiresAt != nil {
+		errResp := domainerrors.NewErrorResponseWithOpts(nil, domainerrors.EINVALID, "expires_at cannot be set together with clear_expires_at")
+		h.logger.Error("expires_at cannot be set together with clear_expires_at", zap.Reflect("error", errResp))
+		return ctx.Status(errResp.Status).JSON(errResp.ToJson())
+	}
+
+	c := context.WithValue(ctx.UserContext(), constants.TenantSlugKey, tenantSlug)
+
+	override, err := h.overrideSvc.UpdateEntitlementOverride(c, subjectType, subject, id, models.UpdateEntitlementOverrideInput{
+		LimitValue:          req.LimitValue,
+		ResetPeriod:         models.MeteredResetPeriod(req.ResetPeriod),
+		CustomPeriodMinutes: req.CustomPeriodMinutes,
+		ActionAtLimit:       models.MeteredActionAtLimit(req.ActionAtLimit),
+		Reason:              req.Reason,
+		ExpiresAt:           req.ExpiresAt,
+		ClearExpiresAt:      req.ClearExpiresAt,
+		UpdatedBy:           req.UpdatedBy,
+	})
+	if err != nil {
+		h.logger.Error("failed to update entitlement override", zap.String("id", id.String()), zap.Reflect("error", err))
+		errResp := domainerrors.NewErrorResponse(err)
+		return ctx.Status(errResp.Status).JSON(errResp.ToJson())
+	}
+
+	return ctx.
+		Status(fiber.StatusOK).JSON(models.NewHttpResponse(override, "entitlement override updated successfully", fiber.StatusOK))
+}
+
+// @Summary Delete an entitlement override
+// @Description Delete an entitlement override, returning the subject to the entitlements of its plans
+// @Tags subjects
+// @Accept json
+// @Produce json
+// @Param X-Tenant-Slug header string true "Tenant Slug"
+// @Param subject path string true "Organization or user ID"
+// @Param id path string true "Entitlement override ID"
+// @Param type query string false "Subject type (organization/user)" default(organization)
+// @Success 204 "Entitlement override deleted successfully"
+// @Failure 400 {object} domainerrors.ErrorResponse "Invalid request"
+// @Failure 404 {object} domainerrors.ErrorResponse "Entitlement override not found"
+// @Failure 500 {object} domainerrors.ErrorResponse "Internal server error"
+// @Router /v1/subjects/{subject}/overrides/{id} [delete]
+func (h *httpHandler) deleteOverride(ctx *fiber.Ctx) error {
+	tenantSlug := ctx.Get(constants.TenantHeader)
+	subject := ctx.Params("subject")
+	subjectType := models.SubjectType(ctx.Query("type", string(models.SubjectTypeOrganization)))
+
+	id, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		errResp := domainerrors.NewErrorResponseWithOpts(err, domainerrors.EINVALID, "invalid entitlement override ID format")
+		h.logger.Error("invalid entitlement override ID format", zap.Reflect("error", errResp))
+		return ctx.Status(errResp.Status).JSON(errResp.ToJson())
+	}
+
+	c := context.WithValue(ctx.UserContext(), constants.TenantSlugKey, tenantSlug)
+
+	if err := h.overrideSvc.DeleteEntitlementOverride(c, subjectType, subject, id); err != nil {
+		h.logger.Error("failed to delete entitlement override", zap.String("id", id.String()), zap.Reflect("error", err))
+		errResp := domainerrors.NewErrorResponse(err)
+		return ctx.Status(errResp.Status).JSON(errResp.ToJson())
+	}
+
+	return ctx.
+		SendStatus(fiber.StatusNoContent)
+}
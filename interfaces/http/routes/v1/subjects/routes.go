@@ -0,0 +1,33 @@
+package subjects
+
+import (
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/redcardinal-io/metering/application/services"
+	"github.com/redcardinal-io/metering/domain/pkg/logger"
+)
+
+type httpHandler struct {
+	logger      *logger.Logger
+	usageSvc    *services.SubjectUsageService
+	overrideSvc *services.EntitlementOverrideService
+	validator   *validator.Validate
+}
+
+// NewHTTPHandler creates a new httpHandler for subject-related HTTP routes with logging, the subject usage and entitlement override services, and validation capabilities.
+func NewHTTPHandler(logger *logger.Logger, usageSvc *services.SubjectUsageService, overrideSvc *services.EntitlementOverrideService) *httpHandler {
+	validator := validator.New()
+	return &httpHandler{logger, usageSvc, overrideSvc, validator}
+}
+
+func (h *httpHandler) RegisterRoutes(r fiber.Router) {
+	subjects := r.Group("/subjects")
+
+	subjects.Get("/:subject/usage", h.usage)
+	subjects.Get("/:subject/entitlements", h.entitlements)
+	subjects.Post("/:subject/overrides", h.createOverride)
+	subjects.Get("/:subject/overrides", h.listOverrides)
+	subjects.Get("/:subject/overrides/:id", h.getOverride)
+	subjects.Put("/:subject/overrides/:id", h.updateOverride)
+	subjects.Delete("/:subject/overrides/:id", h.deleteOverride)
+}
@@ -0,0 +1,48 @@
+package subjects
+
+import (
+	"context"
+
+	"github.com/gofiber/fiber/v2"
+	domainerrors "github.com/redcardinal-io/metering/domain/errors"
+	"github.com/redcardinal-io/metering/domain/models"
+	"github.com/redcardinal-io/metering/domain/pkg/constants"
+	"go.uber.org/zap"
+)
+
+// @Summary Get subject usage
+// @Description Summarize the usage of an organization or user for every metered feature on its active base plan and add-ons, with quotas merged by the merge rule of each feature. Each feature sums the meters it is linked to over the current reset period of its quota and reports the limit and the percentage used.
+// @Tags subjects
+// @Accept json
+// @Produce json
+// @Param X-Tenant-Slug header string true "Tenant Slug"
+// @Param subject path string true "Organization or user ID"
+// @Param type query string false "Subject type (organization/user)" default(organization)
+// @Success 200 {object} models.HttpResponse[models.SubjectUsage] "Subject usage retrieved successfully"
+// @Failure 400 {object} domainerrors.ErrorResponse "Invalid request"
+// @Failure 404 {object} domainerrors.ErrorResponse "Subject has no active plan"
+// @Failure 500 {object} domainerrors.ErrorResponse "Internal server error"
+// @Router /v1/subjects/{subject}/usage [get]
+func (h *httpHandler) usage(ctx *fiber.Ctx) error {
+	tenantSlug := ctx.Get(constants.TenantHeader)
+	subject := ctx.Params("subject")
+	subjectType := models.SubjectType(ctx.Query("type", string(models.SubjectTypeOrganization)))
+
+	if !models.IsValidSubjectType(subjectType) {
+		errResp := domainerrors.NewErrorResponseWithOpts(nil, domainerrors.EINVALID, "invalid subject type")
+		h.logger.Error("invalid subject type", zap.String("type", string(subjectType)))
+		return ctx.Status(errResp.Status).JSON(errResp.ToJson())
+	}
+
+	c := context.WithValue(ctx.UserContext(), constants.TenantSlugKey, tenantSlug)
+
+	usage, err := h.usageSvc.GetSubjectUsage(c, subjectType, subject)
+	if err != nil {
+		h.logger.Error("failed to get subject usage", zap.String("subject", subject), zap.Reflect("error", err))
+		errResp := domainerrors.NewErrorResponse(err)
+		return ctx.Status(errResp.Status).JSON(errResp.ToJson())
+	}
+
+	return ctx.
+		Status(fiber.StatusOK).JSON(models.NewHttpResponse(usage, "subject usage retrieved successfully", fiber.StatusOK))
+}
@@ -0,0 +1,83 @@
+package webhooks
+
+import (
+	"context"
+
+	"github.com/gofiber/fiber/v2"
+	domainerrors "github.com/redcardinal-io/metering/domain/errors"
+	"github.com/redcardinal-io/metering/domain/models"
+	"github.com/redcardinal-io/metering/domain/pkg/constants"
+	"go.uber.org/zap"
+)
+
+type createWebhookEndpointRequest struct {
+	URL         string   `json:"url" validate:"required,url"`
+	Description string   `json:"description" validate:"omitempty,max=255"`
+	Secret      string   `json:"secret" validate:"omitempty,min=16"`
+	EventTypes  []string `json:"event_types" validate:"required,min=1,dive,required"`
+	Enabled     *bool    `json:"enabled"`
+	CreatedBy   string   `json:"created_by" validate:"required"`
+}
+
+// @Summary Create a webhook endpoint
+// @Description Register an endpoint receiving the notifications of the subscribed event types: meter.created, plan.archived, assignment.created, quota.threshold_reached, meter.threshold_reached and trial.ended. Deliveries are signed with the secret of the endpoint, which is generated when none is given and is only returned in this response: the X-Metering-Signature header is v1= followed by the hex encoded HMAC-SHA256 of the X-Metering-Timestamp header, a dot and the request body.
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param X-Tenant-Slug header string true "Tenant Slug"
+// @Param endpoint body createWebhookEndpointRequest true "Webhook endpoint creation data"
+// @Success 201 {object} models.HttpResponse[models.WebhookEndpointWithSecret] "Webhook endpoint created successfully"
+// @Failure 400 {object} domainerrors.ErrorResponse "Invalid request"
+// @Failure 500 {object} domainerrors.ErrorResponse "Internal server error"
+// @Router /v1/webhooks [post]
+func (h *httpHandler) create(ctx *fiber.Ctx) error {
+	tenantSlug := ctx.Get(constants.TenantHeader)
+
+	var req createWebhookEndpointRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		errResp := domainerrors.NewErrorResponseWithOpts(err, domainerrors.EINVALID, "failed to parse request body")
+		h.logger.Error("failed to parse request body", zap.Reflect("error", errResp))
+		return ctx.Status(errResp.Status).JSON(errResp.ToJson())
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		errResp := domainerrors.NewErrorResponseWithOpts(err, domainerrors.EINVALID, "invalid request body")
+		h.logger.Error("invalid request body", zap.Reflect("error", errResp))
+		return ctx.Status(errResp.Status).JSON(errResp.ToJson())
+	}
+
+	enabled := true
+	if req.Enabled != nil {
+		enabled = *req.Enabled
+	}
+
+	c := context.WithValue(ctx.UserContext(), constants.TenantSlugKey, tenantSlug)
+
+	endpoint, err := h.webhookSvc.CreateWebhookEndpoint(c, models.CreateWebhookEndpointInput{
+		URL:         req.URL,
+		Description: req.Description,
+		Secret:      req.Secret,
+		EventTypes:  toNotificationTypes(req.EventTypes),
+		Enabled:     enabled,
+		CreatedBy:   req.CreatedBy,
+	})
+	if err != nil {
+		h.logger.Error("failed to create webhook endpoint", zap.Reflect("error", err))
+		errResp := domainerrors.NewErrorResponse(err)
+		return ctx.Status(errResp.Status).JSON(errResp.ToJson())
+	}
+
+	return ctx.
+		Status(fiber.StatusCreated).JSON(models.NewHttpResponse(models.NewWebhookEndpointWithSecret(endpoint), "webhook endpoint created successfully", fiber.StatusCreated))
+}
+
+func toNotificationTypes(eventTypes []string) []models.NotificationType {
+	if eventTypes == nil {
+		return nil
+	}
+	types := make([]models.NotificationType, 0, len(eventTypes))
+	for _, t := range eventTypes {
+		types = append(types, models.NotificationType(t))
+	}
+	return types
+}
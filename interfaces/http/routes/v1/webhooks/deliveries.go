@@ -0,0 +1,136 @@
+package webhooks
+
+import (
+	"context"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	domainerrors "github.com/redcardinal-io/metering/domain/errors"
+	"github.com/redcardinal-io/metering/domain/models"
+	"github.com/redcardinal-io/metering/domain/pkg/constants"
+	"github.com/redcardinal-io/metering/domain/pkg/pagination"
+	"go.uber.org/zap"
+)
+
+// @Summary List webhook deliveries
+// @Description Get the deliveries queued for a webhook endpoint, most recent first
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param X-Tenant-Slug header string true "Tenant Slug"
+// @Param id path string true "Webhook endpoint ID"
+// @Param page query integer false "Page number"
+// @Param limit query integer false "Items per page"
+// @Success 200 {object} models.HttpResponse[[]models.WebhookDelivery] "Webhook deliveries retrieved successfully"
+// @Failure 400 {object} domainerrors.ErrorResponse "Invalid request"
+// @Failure 404 {object} domainerrors.ErrorResponse "Webhook endpoint not found"
+// @Failure 500 {object} domainerrors.ErrorResponse "Internal server error"
+// @Router /v1/webhooks/{id}/deliveries [get]
+func (h *httpHandler) listDeliveries(ctx *fiber.Ctx) error {
+	tenantSlug := ctx.Get(constants.TenantHeader)
+
+	id, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		errResp := domainerrors.NewErrorResponseWithOpts(err, domainerrors.EINVALID, "invalid webhook endpoint ID format")
+		h.logger.Error("invalid webhook endpoint ID format", zap.Reflect("error", errResp))
+		return ctx.Status(errResp.Status).JSON(errResp.ToJson())
+	}
+
+	paginationInput := pagination.ExtractPaginationFromContext(ctx)
+
+	c := context.WithValue(ctx.UserContext(), constants.TenantSlugKey, tenantSlug)
+
+	deliveries, err := h.webhookSvc.ListWebhookDeliveries(c, id, paginationInput)
+	if err != nil {
+		h.logger.Error("failed to list webhook deliveries", zap.String("id", id.String()), zap.Reflect("error", err))
+		errResp := domainerrors.NewErrorResponse(err)
+		return ctx.Status(errResp.Status).JSON(errResp.ToJson())
+	}
+
+	return ctx.
+		Status(fiber.StatusOK).JSON(models.NewHttpResponse(deliveries, "webhook deliveries retrieved successfully", fiber.StatusOK))
+}
+
+// @Summary Get a webhook delivery
+// @Description Get a delivery of a webhook endpoint with the log of its attempts
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param X-Tenant-Slug header string true "Tenant Slug"
+// @Param id path string true "Webhook endpoint ID"
+// @Param deliveryId path string true "Webhook delivery ID"
+// @Success 200 {object} models.HttpResponse[models.WebhookDelivery] "Webhook delivery retrieved successfully"
+// @Failure 400 {object} domainerrors.ErrorResponse "Invalid request"
+// @Failure 404 {object} domainerrors.ErrorResponse "Webhook delivery not found"
+// @Failure 500 {object} domainerrors.ErrorResponse "Internal server error"
+// @Router /v1/webhooks/{id}/deliveries/{deliveryId} [get]
+func (h *httpHandler) getDelivery(ctx *fiber.Ctx) error {
+	tenantSlug := ctx.Get(constants.TenantHeader)
+
+	id, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		errResp := domainerrors.NewErrorResponseWithOpts(err, domainerrors.EINVALID, "invalid webhook endpoint ID format")
+		h.logger.Error("invalid webhook endpoint ID format", zap.Reflect("error", errResp))
+		return ctx.Status(errResp.Status).JSON(errResp.ToJson())
+	}
+	deliveryID, err := uuid.Parse(ctx.Params("deliveryId"))
+	if err != nil {
+		errResp := domainerrors.NewErrorResponseWithOpts(err, domainerrors.EINVALID, "invalid webhook delivery ID format")
+		h.logger.Error("invalid webhook delivery ID format", zap.Reflect("error", errResp))
+		return ctx.Status(errResp.Status).JSON(errResp.ToJson())
+	}
+
+	c := context.WithValue(ctx.UserContext(), constants.TenantSlugKey, tenantSlug)
+
+	delivery, err := h.webhookSvc.GetWebhookDelivery(c, id, deliveryID)
+	if err != nil {
+		h.logger.Error("failed to get webhook delivery", zap.String("id", deliveryID.String()), zap.Reflect("error", err))
+		errResp := domainerrors.NewErrorResponse(err)
+		return ctx.Status(errResp.Status).JSON(errResp.ToJson())
+	}
+
+	return ctx.
+		Status(fiber.StatusOK).JSON(models.NewHttpResponse(delivery, "webhook delivery retrieved successfully", fiber.StatusOK))
+}
+
+// @Summary Redeliver a webhook delivery
+// @Description Queue a delivery to be sent again on the next dispatch, whatever its status. A delivery that already used all its attempts gets a single further attempt.
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param X-Tenant-Slug header string true "Tenant Slug"
+// @Param id path string true "Webhook endpoint ID"
+// @Param deliveryId path string true "Webhook delivery ID"
+// @Success 202 {object} models.HttpResponse[models.WebhookDelivery] "Webhook delivery queued successfully"
+// @Failure 400 {object} domainerrors.ErrorResponse "Invalid request"
+// @Failure 404 {object} domainerrors.ErrorResponse "Webhook delivery not found"
+// @Failure 500 {object} domainerrors.ErrorResponse "Internal server error"
+// @Router /v1/webhooks/{id}/deliveries/{deliveryId}/redeliver [post]
+func (h *httpHandler) redeliver(ctx *fiber.Ctx) error {
+	tenantSlug := ctx.Get(constants.TenantHeader)
+
+	id, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		errResp := domainerrors.NewErrorResponseWithOpts(err, domainerrors.EINVALID, "invalid webhook endpoint ID format")
+		h.logger.Error("invalid webhook endpoint ID format", zap.Reflect("error", errResp))
+		return ctx.Status(errResp.Status).JSON(errResp.ToJson())
+	}
+	deliveryID, err := uuid.Parse(ctx.Params("deliveryId"))
+	if err != nil {
+		errResp := domainerrors.NewErrorResponseWithOpts(err, domainerrors.EINVALID, "invalid webhook delivery ID format")
+		h.logger.Error("invalid webhook delivery ID format", zap.Reflect("error", errResp))
+		return ctx.Status(errResp.Status).JSON(errResp.ToJson())
+	}
+
+	c := context.WithValue(ctx.UserContext(), constants.TenantSlugKey, tenantSlug)
+
+	delivery, err := h.webhookSvc.RedeliverWebhookDelivery(c, id, deliveryID)
+	if err != nil {
+		h.logger.Error("failed to redeliver webhook delivery", zap.String("id", deliveryID.String()), zap.Reflect("error", err))
+		errResp := domainerrors.NewErrorResponse(err)
+		return ctx.Status(errResp.Status).JSON(errResp.ToJson())
+	}
+
+	return ctx.
+		Status(fiber.StatusAccepted).JSON(models.NewHttpResponse(delivery, "webhook delivery queued successfully", fiber.StatusAccepted))
+}
@@ -0,0 +1,33 @@
+package webhooks
+
+import (
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/redcardinal-io/metering/application/services"
+	"github.com/redcardinal-io/metering/domain/pkg/logger"
+)
+
+type httpHandler struct {
+	logger     *logger.Logger
+	webhookSvc *services.WebhookService
+	validator  *validator.Validate
+}
+
+// NewHTTPHandler creates a new httpHandler for webhook HTTP routes with logging, the webhook service, and validation capabilities.
+func NewHTTPHandler(logger *logger.Logger, webhookSvc *services.WebhookService) *httpHandler {
+	validator := validator.New()
+	return &httpHandler{logger, webhookSvc, validator}
+}
+
+func (h *httpHandler) RegisterRoutes(r fiber.Router) {
+	webhooks := r.Group("/webhooks")
+
+	webhooks.Post("/", h.create)
+	webhooks.Get("/", h.list)
+	webhooks.Get("/:id", h.getByID)
+	webhooks.Put("/:id", h.updateByID)
+	webhooks.Delete("/:id", h.deleteByID)
+	webhooks.Get("/:id/deliveries", h.listDeliveries)
+	webhooks.Get("/:id/deliveries/:deliveryId", h.getDelivery)
+	webhooks.Post("/:id/deliveries/:deliveryId/redeliver", h.redeliver)
+}
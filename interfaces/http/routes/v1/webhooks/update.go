@@ -0,0 +1,86 @@
+package webhooks
+
+import (
+	"context"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	domainerrors "github.com/redcardinal-io/metering/domain/errors"
+	"github.com/redcardinal-io/metering/domain/models"
+	"github.com/redcardinal-io/metering/domain/pkg/constants"
+	"go.uber.org/zap"
+)
+
+type updateWebhookEndpointRequest struct {
+	URL         *string  `json:"url" validate:"omitempty,url"`
+	Description *string  `json:"description" validate:"omitempty,max=255"`
+	Secret      *string  `json:"secret" validate:"omitempty,min=16"`
+	EventTypes  []string `json:"event_types" validate:"omitempty,min=1,dive,required"`
+	Enabled     *bool    `json:"enabled"`
+	UpdatedBy   string   `json:"updated_by" validate:"required"`
+}
+
+// @Summary Update a webhook endpoint
+// @Description Update the URL, description, secret, subscribed event types or enabled state of a webhook endpoint. Deliveries to a disabled endpoint fail until they are redelivered. The secret is only returned when it is rotated by this request.
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param X-Tenant-Slug header string true "Tenant Slug"
+// @Param id path string true "Webhook endpoint ID"
+// @Param endpoint body updateWebhookEndpointRequest true "Webhook endpoint update data"
+// @Success 200 {object} models.HttpResponse[models.WebhookEndpoint] "Webhook endpoint updated successfully"
+// @Failure 400 {object} domainerrors.ErrorResponse "Invalid request"
+// @Failure 404 {object} domainerrors.ErrorResponse "Webhook endpoint not found"
+// @Failure 500 {object} domainerrors.ErrorResponse "Internal server error"
+// @Router /v1/webhooks/{id} [put]
+func (h *httpHandler) updateByID(ctx *fiber.Ctx) error {
+	tenantSlug := ctx.Get(constants.TenantHeader)
+
+	id, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		errResp := domainerrors.NewErrorResponseWithOpts(err, domainerrors.EINVALID, "invalid webhook endpoint ID format")
+		h.logger.Error("invalid webhook endpoint ID format", zap.Reflect("error", errResp))
+		return ctx.Status(errResp.Status).JSON(errResp.ToJson())
+	}
+
+	var req updateWebhookEndpointRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		errResp := domainerrors.NewErrorResponseWithOpts(err, domainerrors.EINVALID, "failed to parse request body")
+		h.logger.Error("failed to parse request body", zap.Reflect("error", errResp))
+		return ctx.Status(errResp.Status).JSON(errResp.ToJson())
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		errResp := domainerrors.NewErrorResponseWithOpts(err, domainerrors.EINVALID, "invalid request body")
+		h.logger.Error("invalid request body", zap.Reflect("error", errResp))
+		return ctx.Status(errResp.Status).JSON(errResp.ToJson())
+	}
+	if req.URL == nil && req.Description == nil && req.Secret == nil && req.EventTypes == nil && req.Enabled == nil {
+		errResp := domainerrors.NewErrorResponseWithOpts(nil, domainerrors.EINVALID, "at least one field (url or description or secret or event_types or enabled) is required")
+		h.logger.Error("at least one field (url or description or secret or event_types or enabled) is required", zap.Reflect("error", errResp))
+		return ctx.Status(errResp.Status).JSON(errResp.ToJson())
+	}
+
+	c := context.WithValue(ctx.UserContext(), constants.TenantSlugKey, tenantSlug)
+
+	endpoint, err := h.webhookSvc.UpdateWebhookEndpoint(c, id, models.UpdateWebhookEndpointInput{
+		URL:         req.URL,
+		Description: req.Description,
+		Secret:      req.Secret,
+		EventTypes:  toNotificationTypes(req.EventTypes),
+		Enabled:     req.Enabled,
+		UpdatedBy:   req.UpdatedBy,
+	})
+	if err != nil {
+		h.logger.Error("failed to update webhook endpoint", zap.String("id", id.String()), zap.Reflect("error", err))
+		errResp := domainerrors.NewErrorResponse(err)
+		return ctx.Status(errResp.Status).JSON(errResp.ToJson())
+	}
+
+	if req.Secret != nil {
+		return ctx.
+			Status(fiber.StatusOK).JSON(models.NewHttpResponse(models.NewWebhookEndpointWithSecret(endpoint), "webhook endpoint updated successfully", fiber.StatusOK))
+	}
+	return ctx.
+		Status(fiber.StatusOK).JSON(models.NewHttpResponse(endpoint, "webhook endpoint updated successfully", fiber.StatusOK))
+}
@@ -1,6 +1,7 @@
 package http
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/gofiber/contrib/fiberzap"
@@ -11,22 +12,33 @@ import (
 	_ "github.com/redcardinal-io/metering/docs"
 	"github.com/redcardinal-io/metering/domain/pkg/config"
 	"github.com/redcardinal-io/metering/domain/pkg/logger"
+	"github.com/redcardinal-io/metering/infrastructure/cache"
 	"github.com/redcardinal-io/metering/infrastructure/clickhouse"
 	"github.com/redcardinal-io/metering/infrastructure/kafka"
 	"github.com/redcardinal-io/metering/infrastructure/postgres/store"
+	"github.com/redcardinal-io/metering/infrastructure/postgres/store/alerts"
+	"github.com/redcardinal-io/metering/infrastructure/postgres/store/entitlementoverrides"
 	"github.com/redcardinal-io/metering/infrastructure/postgres/store/features"
 	"github.com/redcardinal-io/metering/infrastructure/postgres/store/meters"
 	"github.com/redcardinal-io/metering/infrastructure/postgres/store/planassignments"
+	"github.com/redcardinal-io/metering/infrastructure/postgres/store/planchanges"
 	"github.com/redcardinal-io/metering/infrastructure/postgres/store/planfeatures"
 	"github.com/redcardinal-io/metering/infrastructure/postgres/store/plans"
+	"github.com/redcardinal-io/metering/infrastructure/postgres/store/planversions"
 	"github.com/redcardinal-io/metering/infrastructure/postgres/store/quotas"
+	"github.com/redcardinal-io/metering/infrastructure/postgres/store/trials"
+	"github.com/redcardinal-io/metering/infrastructure/postgres/store/webhooks"
+	"github.com/redcardinal-io/metering/infrastructure/webhook"
 	"github.com/redcardinal-io/metering/interfaces/http/routes"
 	"github.com/redcardinal-io/metering/interfaces/http/routes/middleware"
+	alertRoutes "github.com/redcardinal-io/metering/interfaces/http/routes/v1/alerts"
 	"github.com/redcardinal-io/metering/interfaces/http/routes/v1/assignments"
 	"github.com/redcardinal-io/metering/interfaces/http/routes/v1/events"
 	featuresRoutes "github.com/redcardinal-io/metering/interfaces/http/routes/v1/features"
 	meterRoutes "github.com/redcardinal-io/metering/interfaces/http/routes/v1/meters"
 	planRoutes "github.com/redcardinal-io/metering/interfaces/http/routes/v1/plans"
+	"github.com/redcardinal-io/metering/interfaces/http/routes/v1/subjects"
+	webhookRoutes "github.com/redcardinal-io/metering/interfaces/http/routes/v1/webhooks"
 	"go.uber.org/zap"
 )
 
@@ -107,17 +119,54 @@ func ServeHttp() error {
 	planAssignmentsStore := planassignments.NewPostgresPlanAssignmentsStoreRepository(store.GetDB(), logger)
 	planFeatureStore := planfeatures.NewPgPlanFeatureStoreRepository(store.GetDB(), logger)
 	plannFeatureQuotaStore := quotas.NewPlanFeatureQuotaRepository(store.GetDB(), logger)
+	alertStore := alerts.NewPostgresAlertStoreRepository(store.GetDB(), logger)
+	webhookStore := webhooks.NewPostgresWebhookStoreRepository(store.GetDB(), logger)
+	planVersionStore := planversions.NewPostgresPlanVersionStoreRepository(store.GetDB(), logger)
+	planChangeStore := planchanges.NewPostgresPlanChangeStoreRepository(store.GetDB(), logger)
+	trialStore := trials.NewPostgresTrialStoreRepository(store.GetDB(), logger)
+	entitlementOverrideStore := entitlementoverrides.NewPostgresEntitlementOverrideStoreRepository(store.GetDB(), logger)
+
+	// initialize query cache
+	queryCache := cache.NewInMemoryQueryCache(config.QueryCache.MaxEntries)
 
 	// initialize services
-	producerService := services.NewProducerService(producer, meterStore)
-	meterService := services.NewMeterService(olap, meterStore)
+	webhookService := services.NewWebhookService(webhookStore, logger)
+	webhookDispatcher := services.NewWebhookDispatcher(webhookStore, webhook.NewHTTPWebhookSender(config.Webhooks.AllowPrivateNetworks), config.Webhooks, logger)
+	producerService := services.NewProducerService(producer, meterStore, queryCache, config.QueryCache)
+	meterService := services.NewMeterService(olap, meterStore, queryCache, config.QueryCache, webhookService)
+	meterReconciler := services.NewMeterReconciler(meterService, meterStore, config.Reconciler, logger)
+	meterStreamHub := services.NewMeterStreamHub(meterService, config.Stream, logger)
 	planMangementService := services.NewPlanService(
 		planStore,
 		featureStore,
 		planFeatureStore,
 		planAssignmentsStore,
 		plannFeatureQuotaStore,
+		meterStore,
+		planVersionStore,
+		planChangeStore,
+		trialStore,
+		webhookService,
+	)
+	subjectUsageService := services.NewSubjectUsageService(
+		planAssignmentsStore,
+		planFeatureStore,
+		plannFeatureQuotaStore,
+		entitlementOverrideStore,
+		meterService,
 	)
+	entitlementOverrideService := services.NewEntitlementOverrideService(entitlementOverrideStore, featureStore, planAssignmentsStore)
+	alertService := services.NewAlertService(alertStore, subjectUsageService, webhookService)
+	alertEvaluator := services.NewAlertEvaluator(alertService, alertStore, config.Alerts, logger)
+	trialProcessor := services.NewTrialProcessor(planMangementService, trialStore, config.Trials, logger)
+
+	// start background workers
+	workerCtx, stopWorkers := context.WithCancel(context.Background())
+	defer stopWorkers()
+	go meterReconciler.Run(workerCtx)
+	go alertEvaluator.Run(workerCtx)
+	go trialProcessor.Run(workerCtx)
+	go webhookDispatcher.Run(workerCtx)
 
 	// Register routes
 	routes := routes.NewHTTPHandler(logger)
@@ -134,7 +183,7 @@ func ServeHttp() error {
 	eventsRoutes.RegisterRoutes(v1)
 
 	// meter routes
-	meterRoutes := meterRoutes.NewHTTPHandler(logger, meterService)
+	meterRoutes := meterRoutes.NewHTTPHandler(logger, meterService, meterStreamHub)
 	meterRoutes.RegisterRoutes(v1)
 
 	// plan assignment routes
@@ -149,6 +198,18 @@ func ServeHttp() error {
 	featuresRoutes := featuresRoutes.NewHTTPHandler(logger, planMangementService)
 	featuresRoutes.RegisterRoutes(v1)
 
+	// subject routes
+	subjectRoutes := subjects.NewHTTPHandler(logger, subjectUsageService, entitlementOverrideService)
+	subjectRoutes.RegisterRoutes(v1)
+
+	// alert routes
+	alertRoutes := alertRoutes.NewHTTPHandler(logger, alertService)
+	alertRoutes.RegisterRoutes(v1)
+
+	// webhook routes
+	webhookRoutes := webhookRoutes.NewHTTPHandler(logger, webhookService)
+	webhookRoutes.RegisterRoutes(v1)
+
 	// Start server
 	return app.Listen(":" + config.Server.Port)
 }
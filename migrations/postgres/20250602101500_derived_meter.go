@@ -0,0 +1,51 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upDerivedMeter, downDerivedMeter)
+}
+
+// upDerivedMeter adds the meter_type_enum type and the columns describing derived meters, which are computed from a formula over other meters and have no event type or aggregation of their own.
+func upDerivedMeter(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+		do $$
+		begin
+			if not exists (select 1 from pg_type where typname = 'meter_type_enum') then
+				create type meter_type_enum as enum (
+					'standard',
+					'derived'
+				);
+			end if;
+
+			alter table meter add column if not exists type meter_type_enum not null default 'standard';
+			alter table meter add column if not exists formula text;
+			alter table meter add column if not exists source_meters text[] not null default '{}';
+			alter table meter alter column event_type drop not null;
+			alter table meter alter column aggregation drop not null;
+
+			create index if not exists idx_meter_source_meters on meter using gin(source_meters);
+		end;
+		$$;
+	`)
+	return err
+}
+
+func downDerivedMeter(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+		delete from meter where type = 'derived';
+		drop index if exists idx_meter_source_meters;
+		alter table meter alter column aggregation set not null;
+		alter table meter alter column event_type set not null;
+		alter table meter drop column if exists source_meters;
+		alter table meter drop column if exists formula;
+		alter table meter drop column if exists type;
+		drop type if exists meter_type_enum;
+	`)
+	return err
+}
@@ -0,0 +1,41 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upMeterGranularity, downMeterGranularity)
+}
+
+// upMeterGranularity adds the base granularity meter views are bucketed by. Existing views were created with one row per minute.
+func upMeterGranularity(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+		do $$
+		begin
+			if not exists (select 1 from pg_type where typname = 'meter_granularity_enum') then
+				create type meter_granularity_enum as enum (
+					'second',
+					'minute',
+					'hour',
+					'day'
+				);
+			end if;
+
+			alter table meter add column if not exists granularity meter_granularity_enum not null default 'minute';
+		end;
+		$$;
+	`)
+	return err
+}
+
+func downMeterGranularity(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+		alter table meter drop column if exists granularity;
+		drop type if exists meter_granularity_enum;
+	`)
+	return err
+}
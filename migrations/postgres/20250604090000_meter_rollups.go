@@ -0,0 +1,27 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upMeterRollups, downMeterRollups)
+}
+
+// upMeterRollups records the granularities of the rollup views kept for each meter. Meters created before have none.
+func upMeterRollups(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+		alter table meter add column if not exists rollups text[] not null default '{}';
+	`)
+	return err
+}
+
+func downMeterRollups(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+		alter table meter drop column if exists rollups;
+	`)
+	return err
+}
@@ -0,0 +1,48 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upMeterStatus, downMeterStatus)
+}
+
+// upMeterStatus tracks where each meter is in its lifecycle across Postgres and ClickHouse. Existing meters are active.
+func upMeterStatus(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+		do $$
+		begin
+			if not exists (select 1 from pg_type where typname = 'meter_status_enum') then
+				create type meter_status_enum as enum (
+					'pending',
+					'active',
+					'deleting',
+					'failed'
+				);
+			end if;
+
+			alter table meter add column if not exists status meter_status_enum not null default 'active';
+			alter table meter add column if not exists status_error text;
+			alter table meter add column if not exists reconcile_attempts integer not null default 0;
+		end;
+		$$;
+
+		create index if not exists meter_status_idx on meter (status, updated_at) where status <> 'active';
+	`)
+	return err
+}
+
+func downMeterStatus(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+		drop index if exists meter_status_idx;
+		alter table meter drop column if exists reconcile_attempts;
+		alter table meter drop column if exists status_error;
+		alter table meter drop column if exists status;
+		drop type if exists meter_status_enum;
+	`)
+	return err
+}
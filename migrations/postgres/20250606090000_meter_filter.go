@@ -0,0 +1,27 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upMeterFilter, downMeterFilter)
+}
+
+// upMeterFilter stores the event property filter a meter view is created with.
+func upMeterFilter(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+		alter table meter add column if not exists filter jsonb default null;
+	`)
+	return err
+}
+
+func downMeterFilter(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+		alter table meter drop column if exists filter;
+	`)
+	return err
+}
@@ -0,0 +1,36 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upMeterEventTypes, downMeterEventTypes)
+}
+
+// upMeterEventTypes lets a meter aggregate several event types, each optionally reading its value
+// from a different property. Existing meters aggregate their single event type.
+func upMeterEventTypes(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+		alter table meter add column if not exists event_types text[] not null default '{}';
+		alter table meter add column if not exists value_properties jsonb default null;
+
+		update meter set event_types = array[event_type]
+		where event_type is not null and event_types = '{}';
+
+		create index if not exists meter_event_types_idx on meter using gin (event_types);
+	`)
+	return err
+}
+
+func downMeterEventTypes(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+		drop index if exists meter_event_types_idx;
+		alter table meter drop column if exists value_properties;
+		alter table meter drop column if exists event_types;
+	`)
+	return err
+}
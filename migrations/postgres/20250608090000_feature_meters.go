@@ -0,0 +1,27 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upFeatureMeters, downFeatureMeters)
+}
+
+// upFeatureMeters links metered features to the meters their usage is measured by.
+func upFeatureMeters(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+		alter table feature add column if not exists meter_slugs text[] not null default '{}';
+	`)
+	return err
+}
+
+func downFeatureMeters(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+		alter table feature drop column if exists meter_slugs;
+	`)
+	return err
+}
@@ -0,0 +1,88 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upAlert, downAlert)
+}
+
+// upAlert stores tenant-defined usage alert rules and, per rule and subject, the state the last
+// evaluation left it in so repeated firings can be deduplicated.
+func upAlert(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+		do $$
+		begin
+			if not exists (select 1 from pg_type where typname = 'subject_type_enum') then
+				create type subject_type_enum as enum (
+					'organization',
+					'user'
+				);
+			end if;
+			if not exists (select 1 from pg_type where typname = 'alert_threshold_type_enum') then
+				create type alert_threshold_type_enum as enum (
+					'absolute',
+					'percent'
+				);
+			end if;
+			if not exists (select 1 from pg_type where typname = 'alert_status_enum') then
+				create type alert_status_enum as enum (
+					'ok',
+					'firing'
+				);
+			end if;
+		end;
+		$$;
+
+		create table if not exists alert_rule (
+			id uuid primary key default uuid_generate_v4(),
+			tenant_slug varchar not null,
+			name varchar not null,
+			meter_slug varchar default null,
+			plan_feature_id uuid default null references plan_feature(id) on delete cascade,
+			subject_type subject_type_enum not null default 'organization',
+			subject_ids text[] not null default '{}',
+			threshold_type alert_threshold_type_enum not null,
+			threshold double precision not null,
+			window_minutes bigint default null,
+			enabled boolean not null default true,
+			created_at timestamp with time zone not null default now(),
+			updated_at timestamp with time zone not null default now(),
+			created_by varchar not null,
+			updated_by varchar not null,
+			constraint alert_rule_target check ((meter_slug is null) <> (plan_feature_id is null))
+		);
+
+		create index if not exists alert_rule_tenant_slug_idx on alert_rule (tenant_slug);
+
+		create table if not exists alert_state (
+			id uuid primary key default uuid_generate_v4(),
+			alert_rule_id uuid not null references alert_rule(id) on delete cascade,
+			subject_id varchar not null,
+			status alert_status_enum not null default 'ok',
+			value double precision not null default 0,
+			threshold_value double precision not null default 0,
+			period_start timestamp with time zone default null,
+			fired_at timestamp with time zone default null,
+			resolved_at timestamp with time zone default null,
+			evaluated_at timestamp with time zone not null default now(),
+			unique (alert_rule_id, subject_id)
+		);
+	`)
+	return err
+}
+
+func downAlert(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+		drop table if exists alert_state;
+		drop table if exists alert_rule;
+		drop type if exists alert_status_enum;
+		drop type if exists alert_threshold_type_enum;
+		drop type if exists subject_type_enum;
+	`)
+	return err
+}
@@ -0,0 +1,89 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upWebhook, downWebhook)
+}
+
+// upWebhook stores tenant webhook endpoints, the queue of deliveries to them and the log of every
+// delivery attempt.
+func upWebhook(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+		do $$
+		begin
+			if not exists (select 1 from pg_type where typname = 'webhook_delivery_status_enum') then
+				create type webhook_delivery_status_enum as enum (
+					'pending',
+					'succeeded',
+					'failed'
+				);
+			end if;
+		end;
+		$$;
+
+		create table if not exists webhook_endpoint (
+			id uuid primary key default uuid_generate_v4(),
+			tenant_slug varchar not null,
+			url varchar not null,
+			description varchar default null,
+			secret varchar not null,
+			event_types text[] not null,
+			enabled boolean not null default true,
+			created_at timestamp with time zone not null default now(),
+			updated_at timestamp with time zone not null default now(),
+			created_by varchar not null,
+			updated_by varchar not null
+		);
+
+		create index if not exists webhook_endpoint_tenant_slug_idx on webhook_endpoint (tenant_slug);
+
+		create table if not exists webhook_delivery (
+			id uuid primary key default uuid_generate_v4(),
+			endpoint_id uuid not null references webhook_endpoint(id) on delete cascade,
+			tenant_slug varchar not null,
+			event_id uuid not null,
+			event_type varchar not null,
+			payload jsonb not null,
+			status webhook_delivery_status_enum not null default 'pending',
+			attempts integer not null default 0,
+			next_attempt_at timestamp with time zone not null default now(),
+			last_attempt_at timestamp with time zone default null,
+			last_error varchar default null,
+			created_at timestamp with time zone not null default now(),
+			updated_at timestamp with time zone not null default now()
+		);
+
+		create index if not exists webhook_delivery_endpoint_idx on webhook_delivery (endpoint_id, created_at desc);
+		create index if not exists webhook_delivery_due_idx on webhook_delivery (next_attempt_at) where status = 'pending';
+
+		create table if not exists webhook_delivery_attempt (
+			id uuid primary key default uuid_generate_v4(),
+			delivery_id uuid not null references webhook_delivery(id) on delete cascade,
+			attempt integer not null,
+			response_status integer default null,
+			response_body varchar default null,
+			error varchar default null,
+			duration_ms bigint not null,
+			attempted_at timestamp with time zone not null default now()
+		);
+
+		create index if not exists webhook_delivery_attempt_delivery_idx on webhook_delivery_attempt (delivery_id, attempt);
+	`)
+	return err
+}
+
+func downWebhook(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+		drop table if exists webhook_delivery_attempt;
+		drop table if exists webhook_delivery;
+		drop table if exists webhook_endpoint;
+		drop type if exists webhook_delivery_status_enum;
+	`)
+	return err
+}
@@ -0,0 +1,80 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upPlanVersion, downPlanVersion)
+}
+
+// upPlanVersion introduces immutable plan versions. Plan features without a version form the
+// editable draft of a plan; publishing copies the draft and its quotas into a new version, and
+// assignments are pinned to the version they were made on. Every plan with features or
+// assignments gets a first version holding its current features, so existing entitlements are
+// unchanged.
+func upPlanVersion(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+		create table if not exists plan_version (
+			id uuid primary key default uuid_generate_v4(),
+			plan_id uuid not null references plan(id) on delete cascade,
+			version integer not null,
+			description text default null,
+			created_at timestamp with time zone not null default now(),
+			created_by varchar not null,
+
+			unique (plan_id, version)
+		);
+
+		alter table plan_feature add column if not exists plan_version_id uuid default null references plan_version(id) on delete cascade;
+		alter table plan_feature drop constraint if exists plan_feature_plan_id_feature_id_key;
+		create unique index if not exists idx_plan_feature_draft on plan_feature (plan_id, feature_id) where plan_version_id is null;
+		create unique index if not exists idx_plan_feature_version on plan_feature (plan_version_id, feature_id) where plan_version_id is not null;
+
+		alter table plan_assignment add column if not exists plan_version_id uuid default null references plan_version(id) on delete cascade;
+		alter table plan_assignment add column if not exists pending_plan_version_id uuid default null references plan_version(id) on delete set null;
+		create index if not exists idx_plan_assignment_plan_version_id on plan_assignment(plan_version_id);
+
+		insert into plan_version (plan_id, version, description, created_by)
+		select p.id, 1, 'initial version', p.updated_by
+		from plan p
+		where exists (select 1 from plan_feature pf where pf.plan_id = p.id)
+		or exists (select 1 from plan_assignment pa where pa.plan_id = p.id);
+
+		insert into plan_feature (plan_id, feature_id, config, created_by, updated_by, plan_version_id)
+		select pf.plan_id, pf.feature_id, pf.config, pf.created_by, pf.updated_by, pv.id
+		from plan_feature pf
+		join plan_version pv on pv.plan_id = pf.plan_id
+		where pf.plan_version_id is null;
+
+		insert into plan_feature_quota (plan_feature_id, limit_value, reset_period, custom_period_minutes, action_at_limit)
+		select v.id, q.limit_value, q.reset_period, q.custom_period_minutes, q.action_at_limit
+		from plan_feature v
+		join plan_feature d on d.plan_id = v.plan_id and d.feature_id = v.feature_id and d.plan_version_id is null
+		join plan_feature_quota q on q.plan_feature_id = d.id
+		where v.plan_version_id is not null;
+
+		update plan_assignment pa
+		set plan_version_id = pv.id
+		from plan_version pv
+		where pv.plan_id = pa.plan_id;
+	`)
+	return err
+}
+
+func downPlanVersion(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+		delete from plan_feature where plan_version_id is not null;
+		drop index if exists idx_plan_feature_version;
+		drop index if exists idx_plan_feature_draft;
+		alter table plan_assignment drop column if exists pending_plan_version_id;
+		alter table plan_assignment drop column if exists plan_version_id;
+		alter table plan_feature drop column if exists plan_version_id;
+		alter table plan_feature add constraint plan_feature_plan_id_feature_id_key unique (plan_id, feature_id);
+		drop table if exists plan_version;
+	`)
+	return err
+}
@@ -0,0 +1,52 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upPlanKind, downPlanKind)
+}
+
+// upPlanKind marks plans as a base plan or an add-on stacked on top of one, and gives features the
+// rule their quotas are merged by when several assigned plans grant them.
+func upPlanKind(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+		do $$
+		begin
+			if not exists (select 1 from pg_type where typname = 'plan_kind_enum') then
+				create type plan_kind_enum as enum (
+					'base',
+					'addon'
+				);
+			end if;
+			if not exists (select 1 from pg_type where typname = 'quota_merge_rule_enum') then
+				create type quota_merge_rule_enum as enum (
+					'sum',
+					'max'
+				);
+			end if;
+		end;
+		$$;
+
+		alter table plan add column if not exists kind plan_kind_enum not null default 'base';
+		create index if not exists idx_plan_tenant_slug_and_kind on plan(tenant_slug, kind);
+
+		alter table feature add column if not exists merge_rule quota_merge_rule_enum not null default 'sum';
+	`)
+	return err
+}
+
+func downPlanKind(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+		alter table feature drop column if exists merge_rule;
+		drop index if exists idx_plan_tenant_slug_and_kind;
+		alter table plan drop column if exists kind;
+		drop type if exists quota_merge_rule_enum;
+		drop type if exists plan_kind_enum;
+	`)
+	return err
+}
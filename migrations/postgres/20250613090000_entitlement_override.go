@@ -0,0 +1,62 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upEntitlementOverride, downEntitlementOverride)
+}
+
+// upEntitlementOverride stores per-subscriber overrides applied on top of the entitlements of the
+// plans a subject holds. An override grants or revokes a feature for the subject, or only while
+// one of its assignments lasts, and a grant may replace the limit, reset period or action at limit
+// of the feature's quota.
+func upEntitlementOverride(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+		do $$
+		begin
+			if not exists (select 1 from pg_type where typname = 'entitlement_override_effect_enum') then
+				create type entitlement_override_effect_enum as enum (
+					'grant',
+					'revoke'
+				);
+			end if;
+		end;
+		$$;
+
+		create table if not exists entitlement_override (
+			id uuid primary key default uuid_generate_v4(),
+			tenant_slug varchar not null,
+			subject_type subject_type_enum not null,
+			subject_id varchar not null,
+			plan_assignment_id uuid default null references plan_assignment(id) on delete cascade,
+			feature_id uuid not null references feature(id) on delete cascade,
+			effect entitlement_override_effect_enum not null,
+			limit_value bigint default null,
+			reset_period metered_reset_period_enum default null,
+			custom_period_minutes bigint default null,
+			action_at_limit metered_action_at_limit_enum default null,
+			reason text default null,
+			expires_at timestamp with time zone default null,
+			created_at timestamp with time zone not null default now(),
+			updated_at timestamp with time zone not null default now(),
+			created_by varchar not null,
+			updated_by varchar not null
+		);
+
+		create index if not exists entitlement_override_subject_idx on entitlement_override (tenant_slug, subject_type, subject_id);
+	`)
+	return err
+}
+
+func downEntitlementOverride(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+		drop table if exists entitlement_override;
+		drop type if exists entitlement_override_effect_enum;
+	`)
+	return err
+}
@@ -0,0 +1,65 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upPlanChange, downPlanChange)
+}
+
+// upPlanChange stores scheduled switches of a subject from one plan to another, or cancellations
+// of its plan. Scheduling ends the current assignment at effective_at and creates the next one
+// starting then; previous_valid_until keeps the end the current assignment had so a pending
+// change can be canceled. Only one change can be left uncanceled per assignment.
+func upPlanChange(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+		do $$
+		begin
+			if not exists (select 1 from pg_type where typname = 'plan_change_type_enum') then
+				create type plan_change_type_enum as enum (
+					'upgrade',
+					'downgrade',
+					'cancellation'
+				);
+			end if;
+		end;
+		$$;
+
+		create table if not exists plan_change (
+			id uuid primary key default uuid_generate_v4(),
+			tenant_slug varchar not null,
+			type plan_change_type_enum not null,
+			organization_id varchar default null,
+			user_id varchar default null,
+			from_assignment_id uuid not null references plan_assignment(id) on delete cascade,
+			from_plan_id uuid not null references plan(id) on delete cascade,
+			to_assignment_id uuid default null references plan_assignment(id) on delete set null,
+			to_plan_id uuid default null references plan(id) on delete cascade,
+			effective_at timestamp with time zone not null,
+			previous_valid_until timestamp with time zone not null,
+			reason text default null,
+			canceled_at timestamp with time zone default null,
+			canceled_by varchar default null,
+			created_at timestamp with time zone not null default now(),
+			updated_at timestamp with time zone not null default now(),
+			created_by varchar not null,
+			updated_by varchar not null
+		);
+
+		create unique index if not exists plan_change_from_assignment_idx on plan_change (from_assignment_id) where canceled_at is null;
+		create index if not exists plan_change_tenant_slug_and_effective_at_idx on plan_change (tenant_slug, effective_at);
+	`)
+	return err
+}
+
+func downPlanChange(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+		drop table if exists plan_change;
+		drop type if exists plan_change_type_enum;
+	`)
+	return err
+}
@@ -0,0 +1,78 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upPlanTrial, downPlanTrial)
+}
+
+// upPlanTrial adds trial assignments. A trial is an assignment of kind trial ending after the
+// trial length, with the plan it converts to when it ends, an optional fallback plan and what to
+// do at the end: convert to the target plan, fall back to the fallback plan or simply expire.
+// Trials stay active until the trial processor ends them and records the outcome.
+func upPlanTrial(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+		do $$
+		begin
+			if not exists (select 1 from pg_type where typname = 'plan_assignment_kind_enum') then
+				create type plan_assignment_kind_enum as enum (
+					'standard',
+					'trial'
+				);
+			end if;
+			if not exists (select 1 from pg_type where typname = 'trial_end_action_enum') then
+				create type trial_end_action_enum as enum (
+					'convert',
+					'fallback',
+					'expire'
+				);
+			end if;
+			if not exists (select 1 from pg_type where typname = 'trial_status_enum') then
+				create type trial_status_enum as enum (
+					'active',
+					'converted',
+					'fell_back',
+					'expired'
+				);
+			end if;
+		end;
+		$$;
+
+		alter table plan_assignment add column if not exists kind plan_assignment_kind_enum not null default 'standard';
+
+		create table if not exists plan_trial (
+			id uuid primary key default uuid_generate_v4(),
+			tenant_slug varchar not null,
+			assignment_id uuid not null unique references plan_assignment(id) on delete cascade,
+			target_plan_id uuid not null references plan(id) on delete cascade,
+			fallback_plan_id uuid default null references plan(id) on delete set null,
+			end_action trial_end_action_enum not null default 'convert',
+			status trial_status_enum not null default 'active',
+			resulting_assignment_id uuid default null references plan_assignment(id) on delete set null,
+			ended_at timestamp with time zone default null,
+			created_at timestamp with time zone not null default now(),
+			updated_at timestamp with time zone not null default now(),
+			created_by varchar not null,
+			updated_by varchar not null
+		);
+
+		create index if not exists plan_trial_tenant_slug_and_status_idx on plan_trial (tenant_slug, status);
+	`)
+	return err
+}
+
+func downPlanTrial(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+		drop table if exists plan_trial;
+		alter table plan_assignment drop column if exists kind;
+		drop type if exists trial_status_enum;
+		drop type if exists trial_end_action_enum;
+		drop type if exists plan_assignment_kind_enum;
+	`)
+	return err
+}
@@ -0,0 +1,47 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upQuotaBands, downQuotaBands)
+}
+
+// upQuotaBands gives quotas a soft limit subjects are warned at, an overage allowance past the
+// limit, absolute or a percentage of the limit, before the action at limit applies, and a grace
+// period deferring the action after usage first goes past the allowance.
+func upQuotaBands(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+		do $$
+		begin
+			if not exists (select 1 from pg_type where typname = 'quota_overage_type_enum') then
+				create type quota_overage_type_enum as enum (
+					'absolute',
+					'percent'
+				);
+			end if;
+		end;
+		$$;
+
+		alter table plan_feature_quota add column if not exists soft_limit_value bigint default null;
+		alter table plan_feature_quota add column if not exists overage_allowance bigint default null;
+		alter table plan_feature_quota add column if not exists overage_allowance_type quota_overage_type_enum default null;
+		alter table plan_feature_quota add column if not exists grace_period_minutes bigint default null;
+	`)
+	return err
+}
+
+func downQuotaBands(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+		alter table plan_feature_quota drop column if exists grace_period_minutes;
+		alter table plan_feature_quota drop column if exists overage_allowance_type;
+		alter table plan_feature_quota drop column if exists overage_allowance;
+		alter table plan_feature_quota drop column if exists soft_limit_value;
+		drop type if exists quota_overage_type_enum;
+	`)
+	return err
+}
@@ -0,0 +1,31 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	// Enum values cannot be added and used within the same transaction
+	goose.AddMigrationNoTxContext(upMeterDeleteFailed, downMeterDeleteFailed)
+}
+
+// upMeterDeleteFailed separates meters whose removal kept failing from meters whose creation did,
+// so that retrying them resumes the removal.
+func upMeterDeleteFailed(ctx context.Context, db *sql.DB) error {
+	_, err := db.ExecContext(ctx, `
+		alter type meter_status_enum add value if not exists 'delete_failed';
+	`)
+	return err
+}
+
+// downMeterDeleteFailed moves meters back to deleting. Postgres cannot drop an enum value, so the
+// value itself is kept.
+func downMeterDeleteFailed(ctx context.Context, db *sql.DB) error {
+	_, err := db.ExecContext(ctx, `
+		update meter set status = 'deleting' where status = 'delete_failed';
+	`)
+	return err
+}
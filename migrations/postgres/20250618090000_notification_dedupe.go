@@ -0,0 +1,45 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	// Enum values cannot be added and used within the same transaction
+	goose.AddMigrationNoTxContext(upNotificationDedupe, downNotificationDedupe)
+}
+
+// upNotificationDedupe records alert firings before they are notified, so that a retried firing
+// keeps its notification ID, and allows a single delivery of an event per webhook endpoint.
+func upNotificationDedupe(ctx context.Context, db *sql.DB) error {
+	_, err := db.ExecContext(ctx, `
+		alter type alert_status_enum add value if not exists 'notifying';
+	`)
+	if err != nil {
+		return err
+	}
+	_, err = db.ExecContext(ctx, `
+		do $$
+		begin
+			if not exists (select 1 from pg_constraint where conname = 'webhook_delivery_endpoint_event_key') then
+				alter table webhook_delivery
+					add constraint webhook_delivery_endpoint_event_key unique (endpoint_id, event_id);
+			end if;
+		end;
+		$$;
+	`)
+	return err
+}
+
+// downNotificationDedupe drops the constraint and treats firings being notified as firing.
+// Postgres cannot drop an enum value, so the value itself is kept.
+func downNotificationDedupe(ctx context.Context, db *sql.DB) error {
+	_, err := db.ExecContext(ctx, `
+		alter table webhook_delivery drop constraint if exists webhook_delivery_endpoint_event_key;
+		update alert_state set status = 'firing' where status = 'notifying';
+	`)
+	return err
+}
@@ -0,0 +1,37 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upQuotaGraceState, downQuotaGraceState)
+}
+
+// upQuotaGraceState records when subjects first used up the overage allowance of a quota, so that
+// its grace period runs from a fixed time instead of being worked out from usage on every read.
+func upQuotaGraceState(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+		create table if not exists quota_grace_state (
+			id uuid primary key default uuid_generate_v4(),
+			tenant_slug varchar not null,
+			subject_type subject_type_enum not null,
+			subject_id varchar not null,
+			feature_id uuid not null references feature(id) on delete cascade,
+			allowance_used_up_at timestamp with time zone not null,
+			updated_at timestamp with time zone not null default now(),
+			unique (tenant_slug, subject_type, subject_id, feature_id)
+		);
+	`)
+	return err
+}
+
+func downQuotaGraceState(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+		drop table if exists quota_grace_state;
+	`)
+	return err
+}